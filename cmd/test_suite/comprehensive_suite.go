@@ -251,16 +251,20 @@ func (s *ComprehensiveTestSuite) testReadFile() TestResult {
 		return TestResult{Name: "read_file", Success: false, Error: err.Error()}
 	}
 
+	// 文本内容以纯文本返回,随后是元数据 / Text content comes back as plain text, followed by the metadata
+	if len(resp.Content) < 2 || resp.Content[0].Type != "text" {
+		return TestResult{Name: "read_file", Success: false, Error: "响应内容格式不正确"}
+	}
 	var readResp types.ReadFileResponse
-	if err = json.UnmarshalFromString(resp.Content[0].Text, &readResp); err != nil {
+	if err = json.UnmarshalFromString(resp.Content[1].Text, &readResp); err != nil {
 		return TestResult{Name: "read_file", Success: false, Error: err.Error()}
 	}
 
-	if readResp.Content != "Hello, MCP Toolkit!" {
+	if resp.Content[0].Text != "Hello, MCP Toolkit!" || readResp.Size != int64(len(resp.Content[0].Text)) {
 		return TestResult{Name: "read_file", Success: false, Error: "内容不匹配"}
 	}
 
-	fmt.Printf("    ✅ 文件读取成功，内容: %s\n", readResp.Content)
+	fmt.Printf("    ✅ 文件读取成功，内容: %s\n", resp.Content[0].Text)
 	return TestResult{Name: "read_file", Success: true}
 }
 
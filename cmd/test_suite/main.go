@@ -173,13 +173,13 @@ func testReadFile(ctx context.Context, mcpClient client.Client) TestResult {
 		return TestResult{Name: "read_file", Success: false, Error: err.Error()}
 	}
 
-	var readResp types.ReadFileResponse
-	if err = json.UnmarshalFromString(resp.Content[0].Text, &readResp); err != nil {
-		return TestResult{Name: "read_file", Success: false, Error: err.Error()}
+	// 文本内容以纯文本返回 / Text content comes back as plain text
+	if len(resp.Content) == 0 || resp.Content[0].Type != "text" {
+		return TestResult{Name: "read_file", Success: false, Error: "unexpected content type"}
 	}
 
-	fmt.Printf("   Content: %s\n", readResp.Content)
-	if readResp.Content != "Hello, MCP!" {
+	fmt.Printf("   Content: %s\n", resp.Content[0].Text)
+	if resp.Content[0].Text != "Hello, MCP!" {
 		return TestResult{Name: "read_file", Success: false, Error: "content mismatch"}
 	}
 
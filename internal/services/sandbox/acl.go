@@ -0,0 +1,272 @@
+// Copyright 2024 MCP Toolkit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sandbox
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"mcp-toolkit/pkg/types"
+	"mcp-toolkit/pkg/utils/json"
+
+	"go.uber.org/zap"
+)
+
+// LoadAccessConfig 从JSON文件加载访问控制配置 / Load the access control configuration from a JSON file
+func LoadAccessConfig(path string) (types.AccessConfig, error) {
+	var config types.AccessConfig
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return config, fmt.Errorf("failed to read access config: %w", err)
+	}
+	if err = json.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("failed to parse access config: %w", err)
+	}
+	return config, nil
+}
+
+// SetAccessConfig 设置访问控制规则,应在开始提供服务前调用 / Set the access control rules, to be called before serving requests
+func (s *Service) SetAccessConfig(config types.AccessConfig) error {
+	rules := make([]types.AccessRule, 0, len(config.Rules))
+	for i, rule := range config.Rules {
+		pattern := rule.Pattern
+		if _, rest, ok := strings.Cut(pattern, ":"); ok {
+			pattern = rest
+		}
+		if err := validateGlobPattern(pattern); err != nil {
+			return fmt.Errorf("access rule %d: %w", i+1, err)
+		}
+		for _, op := range rule.Operations {
+			if !slices.Contains(types.AccessOperations, op) {
+				return fmt.Errorf("access rule %d: invalid operation %q, must be read, write, delete or list", i+1, op)
+			}
+		}
+		rule.Effect = cmp.Or(rule.Effect, types.AccessDeny)
+		if rule.Effect != types.AccessDeny && rule.Effect != types.AccessAllow {
+			return fmt.Errorf("access rule %d: invalid effect %q, must be allow or deny", i+1, rule.Effect)
+		}
+		rules = append(rules, rule)
+	}
+
+	s.aclMu.Lock()
+	s.accessRules = rules
+	s.aclMu.Unlock()
+	s.logger.Info("access rules set", zap.Int("rules", len(rules)))
+	return nil
+}
+
+// AccessConfig 获取访问控制规则 / Get the access control rules
+func (s *Service) AccessConfig() types.AccessConfig {
+	s.aclMu.RLock()
+	defer s.aclMu.RUnlock()
+	return types.AccessConfig{Rules: slices.Clone(s.accessRules)}
+}
+
+// GetAccessRules 列出访问控制规则,指定路径时评估各操作类别 / List the access rules, evaluating every operation class when a path is given
+func (s *Service) GetAccessRules(req *types.GetAccessRulesRequest) (*types.GetAccessRulesResponse, error) {
+	resp := &types.GetAccessRulesResponse{Rules: s.AccessConfig().Rules}
+	if req == nil || req.Path == "" {
+		return resp, nil
+	}
+
+	validPath, err := s.validatePath(req.Path)
+	if err != nil {
+		return nil, err
+	}
+	resp.Path = s.displayPath(validPath)
+	for _, op := range types.AccessOperations {
+		allowed, rule := s.evaluateAccess(validPath, op)
+		resp.Decisions = append(resp.Decisions, types.AccessDecision{Operation: op, Allowed: allowed, Rule: rule})
+	}
+	return resp, nil
+}
+
+// evaluateAccess 按顺序评估规则,返回是否允许及生效规则序号(从1开始,0表示没有匹配或由内部存储拒绝)
+// 路径经过符号链接时同时评估请求的路径和解析后的目标,任一被拒绝即拒绝
+// Evaluate the rules in order, returning whether the operation is allowed and the 1-based number of the deciding rule (0 when none matched
+// or an internal store denied it). When the path traverses a symlink both the requested path and the resolved target are evaluated, and either one denying denies
+func (s *Service) evaluateAccess(absPath string, op types.AccessOperation) (bool, int) {
+	s.aclMu.RLock()
+	noRules := len(s.accessRules) == 0
+	s.aclMu.RUnlock()
+	if noRules {
+		return true, 0
+	}
+
+	m := s.mountFor(absPath)
+	rel, err := filepath.Rel(m.dir, absPath)
+	if err != nil {
+		return true, 0
+	}
+	allowed, rule := s.matchAccessRules(m.name, rel, op)
+	if !allowed {
+		return false, rule
+	}
+
+	resolved, err := resolveSymlinks(s.fsys, absPath)
+	if err != nil {
+		return allowed, rule
+	}
+	target, targetRel, ok := s.realMountRel(resolved)
+	if !ok || (target.name == m.name && targetRel == rel) {
+		return allowed, rule
+	}
+	if targetAllowed, targetRule := s.matchAccessRules(target.name, targetRel, op); !targetAllowed {
+		return false, targetRule
+	}
+	return allowed, rule
+}
+
+// matchAccessRules 对挂载内的相对路径按顺序匹配规则 / Match the rules in order against a path relative to a mount
+func (s *Service) matchAccessRules(mountName, rel string, op types.AccessOperation) (bool, int) {
+	if rel = filepath.ToSlash(rel); rel == "." {
+		rel = ""
+	}
+	if mountName == types.DefaultMountName && (op == types.AccessRead || op == types.AccessList) && isInternalStoreRel(rel) {
+		return false, 0
+	}
+
+	s.aclMu.RLock()
+	defer s.aclMu.RUnlock()
+	for i, rule := range s.accessRules {
+		if len(rule.Operations) > 0 && !slices.Contains(rule.Operations, op) {
+			continue
+		}
+		pattern := rule.Pattern
+		if name, rest, ok := strings.Cut(pattern, ":"); ok {
+			if name != mountName {
+				continue
+			}
+			pattern = rest
+		}
+		if matchAnyGlob([]string{pattern}, rel) {
+			return rule.Effect == types.AccessAllow, i + 1
+		}
+	}
+	return true, 0
+}
+
+// isInternalStoreRel 相对沙箱根目录的路径是否位于回收站或快照存储中;配置了规则时它们不能被读取或列出,
+// 否则其中保存的副本可以绕过原路径上的规则
+// Whether a path relative to the sandbox root lies in the trash or the snapshot store. With rules configured they cannot be
+// read or listed, since the copies kept there would otherwise get around the rules on the original paths
+func isInternalStoreRel(rel string) bool {
+	first, _, _ := strings.Cut(rel, "/")
+	return first == TrashDirName || first == SnapshotDirName
+}
+
+// realMountRel 查找包含已解析路径的挂载并返回相对路径,路径不在任何挂载内时ok为false
+// Find the mount containing a resolved path and return the path relative to it; ok is false when no mount contains it
+func (s *Service) realMountRel(resolved string) (*mount, string, bool) {
+	for _, m := range s.mounts {
+		if rel, err := filepath.Rel(m.realDir, resolved); err == nil && isLocalRel(rel) {
+			return m, rel, true
+		}
+	}
+	m := s.defaultMount()
+	if rel, err := filepath.Rel(m.realDir, resolved); err == nil && isLocalRel(rel) {
+		return m, rel, true
+	}
+	return nil, "", false
+}
+
+// accessAllowed 检查操作是否被规则允许 / Check whether an operation is allowed by the rules
+func (s *Service) accessAllowed(absPath string, op types.AccessOperation) bool {
+	allowed, _ := s.evaluateAccess(absPath, op)
+	return allowed
+}
+
+// checkAccess 规则拒绝时返回ErrAccessDenied / Return ErrAccessDenied when a rule denies the operation
+func (s *Service) checkAccess(absPath string, op types.AccessOperation) error {
+	allowed, rule := s.evaluateAccess(absPath, op)
+	if allowed {
+		return nil
+	}
+	display := s.displayPath(absPath)
+	s.logger.Warn("access denied by rule",
+		zap.String("operation", string(op)),
+		zap.String("path", display),
+		zap.Int("rule", rule))
+	if rule == 0 {
+		return fmt.Errorf("%s: %s %s (internal store)", types.ErrAccessDenied, op, display)
+	}
+	return fmt.Errorf("%s: %s %s (rule %d)", types.ErrAccessDenied, op, display, rule)
+}
+
+// checkTreeAccess 检查路径及其下每个条目,用于递归删除 / Check a path and every entry below it, used by recursive deletes
+func (s *Service) checkTreeAccess(root string, op types.AccessOperation) error {
+	if len(s.AccessConfig().Rules) == 0 {
+		return nil
+	}
+	return walkDir(s.fsys, root, func(path string, _ fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		return s.checkAccess(path, op)
+	})
+}
+
+// checkTransferAccess 检查复制或移动时源树每个条目的srcOps权限以及对应目标位置的写入权限
+// Check srcOps on every entry of the source tree and write access at the matching destination, used by copies and moves
+func (s *Service) checkTransferAccess(src, dst string, srcOps ...types.AccessOperation) error {
+	if len(s.AccessConfig().Rules) == 0 {
+		return nil
+	}
+	return walkDir(s.fsys, src, func(path string, _ fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		for _, op := range srcOps {
+			if err = s.checkAccess(path, op); err != nil {
+				return err
+			}
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		return s.checkAccess(filepath.Join(dst, rel), types.AccessWrite)
+	})
+}
+
+// validateAccessPath 验证路径并按操作类别检查挂载模式和访问规则
+// Validate a path and check the mount mode and the access rules for the operation class
+func (s *Service) validateAccessPath(p string, op types.AccessOperation) (string, error) {
+	validPath, err := s.validatePath(p)
+	if err != nil {
+		return "", err
+	}
+	if op == types.AccessWrite || op == types.AccessDelete {
+		if err = s.checkWritable(validPath); err != nil {
+			return "", err
+		}
+	}
+	if err = s.checkAccess(validPath, op); err != nil {
+		return "", err
+	}
+	return validPath, nil
+}
@@ -0,0 +1,289 @@
+package sandbox
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"mcp-toolkit/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupAccessService 创建带有访问控制规则的服务 / Create a service with access control rules
+func setupAccessService(t *testing.T) (*Service, string) {
+	service, tempDir := setupTestService(t)
+	require.NoError(t, service.SetAccessConfig(types.AccessConfig{Rules: []types.AccessRule{
+		{Pattern: "config/.env", Operations: []types.AccessOperation{types.AccessRead}, Effect: types.AccessAllow},
+		{Pattern: "**/.env", Operations: []types.AccessOperation{types.AccessRead}},
+		{Pattern: "vendor/**", Operations: []types.AccessOperation{types.AccessWrite, types.AccessDelete}},
+		{Pattern: ".git/**", Operations: []types.AccessOperation{types.AccessDelete}},
+		{Pattern: "secret/**", Operations: []types.AccessOperation{types.AccessList, types.AccessRead}},
+	}}))
+	writeTestFiles(t, tempDir, map[string]string{
+		".env":           "TOKEN=abc\n",
+		"config/.env":    "PUBLIC=1\n",
+		"app/.env":       "TOKEN=def\n",
+		"vendor/lib.go":  "package lib\n",
+		".git/HEAD":      "ref: refs/heads/main\n",
+		"secret/key.txt": "TOKEN=ghi\n",
+		"src/main.go":    "package main\n",
+	})
+	return service, tempDir
+}
+
+func TestSetAccessConfigValidation(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	tests := []struct {
+		name string
+		rule types.AccessRule
+	}{
+		{name: "empty pattern", rule: types.AccessRule{Pattern: ""}},
+		{name: "invalid pattern", rule: types.AccessRule{Pattern: "["}},
+		{name: "invalid operation", rule: types.AccessRule{Pattern: "*.txt", Operations: []types.AccessOperation{"execute"}}},
+		{name: "invalid effect", rule: types.AccessRule{Pattern: "*.txt", Effect: "maybe"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Error(t, service.SetAccessConfig(types.AccessConfig{Rules: []types.AccessRule{tt.rule}}))
+		})
+	}
+
+	// 省略效果时默认为拒绝 / The effect defaults to deny when omitted
+	require.NoError(t, service.SetAccessConfig(types.AccessConfig{Rules: []types.AccessRule{{Pattern: "*.log"}}}))
+	assert.Equal(t, types.AccessDeny, service.AccessConfig().Rules[0].Effect)
+}
+
+func TestAccessRulesReadWrite(t *testing.T) {
+	service, tempDir := setupAccessService(t)
+	defer cleanupTestService(t, tempDir)
+
+	// 拒绝读取使用独立的错误而不是沙箱越界错误 / Denied reads use a distinct error instead of the sandbox violation
+	_, err := service.ReadFile(&types.ReadFileRequest{Path: ".env"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), types.ErrAccessDenied)
+	assert.NotContains(t, err.Error(), types.ErrSandboxViolation)
+
+	_, err = service.ReadFile(&types.ReadFileRequest{Path: "app/.env"})
+	assert.ErrorContains(t, err, types.ErrAccessDenied)
+
+	// 先匹配的允许规则优先 / An earlier allow rule wins
+	resp, err := service.ReadFile(&types.ReadFileRequest{Path: "config/.env"})
+	require.NoError(t, err)
+	assert.Equal(t, "PUBLIC=1\n", resp.Content)
+
+	// 只读目录可以读取但不能写入 / Read-only directories can be read but not written
+	_, err = service.ReadFile(&types.ReadFileRequest{Path: "vendor/lib.go"})
+	assert.NoError(t, err)
+	_, err = service.WriteFile(&types.WriteFileRequest{Path: "vendor/lib.go", Content: "changed"})
+	assert.ErrorContains(t, err, types.ErrAccessDenied)
+	_, err = service.WriteFile(&types.WriteFileRequest{Path: "vendor/new.go", Content: "new"})
+	assert.ErrorContains(t, err, types.ErrAccessDenied)
+	assert.Equal(t, "package lib\n", readTestFile(t, filepath.Join(tempDir, "vendor/lib.go")))
+
+	_, err = service.WriteFile(&types.WriteFileRequest{Path: "src/main.go", Content: "package app\n"})
+	assert.NoError(t, err)
+}
+
+func TestAccessRulesDeleteAndTransfer(t *testing.T) {
+	service, tempDir := setupAccessService(t)
+	defer cleanupTestService(t, tempDir)
+
+	_, err := service.Delete(&types.DeleteRequest{Path: ".git/HEAD"})
+	assert.ErrorContains(t, err, types.ErrAccessDenied)
+
+	// 删除包含受保护条目的目录同样被拒绝 / Deleting a directory containing protected entries is denied as well
+	_, err = service.Delete(&types.DeleteRequest{Path: ".git"})
+	assert.ErrorContains(t, err, types.ErrAccessDenied)
+	assert.FileExists(t, filepath.Join(tempDir, ".git/HEAD"))
+
+	// 移动源文件等同于删除 / Moving a source counts as deleting it
+	_, err = service.Move(&types.MoveRequest{Source: "vendor/lib.go", Destination: "lib.go"})
+	assert.ErrorContains(t, err, types.ErrAccessDenied)
+	assert.FileExists(t, filepath.Join(tempDir, "vendor/lib.go"))
+
+	// 复制需要读取整棵源目录树 / Copying requires read access to the whole source tree
+	_, err = service.Copy(&types.CopyRequest{Source: ".env", Destination: "leak.txt"})
+	assert.ErrorContains(t, err, types.ErrAccessDenied)
+	_, err = service.Copy(&types.CopyRequest{Source: "app", Destination: "app-copy"})
+	assert.ErrorContains(t, err, types.ErrAccessDenied)
+	assert.NoFileExists(t, filepath.Join(tempDir, "app-copy/.env"))
+
+	// 复制到只读目录被拒绝 / Copying into a read-only directory is denied
+	_, err = service.Copy(&types.CopyRequest{Source: "src/main.go", Destination: "vendor/main.go"})
+	assert.ErrorContains(t, err, types.ErrAccessDenied)
+
+	_, err = service.Delete(&types.DeleteRequest{Path: "src"})
+	assert.NoError(t, err)
+	_, err = os.Stat(filepath.Join(tempDir, "src"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestAccessRulesHideEntries(t *testing.T) {
+	service, tempDir := setupAccessService(t)
+	defer cleanupTestService(t, tempDir)
+
+	list, err := service.ListDir(&types.ListDirRequest{Path: "."})
+	require.NoError(t, err)
+	var names []string
+	for _, file := range list.Files {
+		names = append(names, file.Name)
+	}
+	assert.Contains(t, names, "src")
+	assert.NotContains(t, names, "secret")
+
+	_, err = service.ListDir(&types.ListDirRequest{Path: "secret"})
+	assert.ErrorContains(t, err, types.ErrAccessDenied)
+
+	search, err := service.Search(&types.SearchRequest{Path: ".", Pattern: "*.txt"})
+	require.NoError(t, err)
+	assert.Empty(t, search.Files)
+
+	// 内容搜索跳过不可读取的文件 / Content search skips files that cannot be read
+	content, err := service.SearchContent(&types.SearchContentRequest{Pattern: "TOKEN"})
+	require.NoError(t, err)
+	assert.Empty(t, content.Matches)
+}
+
+func TestGetAccessRules(t *testing.T) {
+	service, tempDir := setupAccessService(t)
+	defer cleanupTestService(t, tempDir)
+
+	resp, err := service.GetAccessRules(&types.GetAccessRulesRequest{})
+	require.NoError(t, err)
+	assert.Len(t, resp.Rules, 5)
+	assert.Empty(t, resp.Decisions)
+
+	resp, err = service.GetAccessRules(&types.GetAccessRulesRequest{Path: "app/.env"})
+	require.NoError(t, err)
+	require.Len(t, resp.Decisions, len(types.AccessOperations))
+	for _, decision := range resp.Decisions {
+		switch decision.Operation {
+		case types.AccessRead:
+			assert.False(t, decision.Allowed)
+			assert.Equal(t, 2, decision.Rule)
+		default:
+			assert.True(t, decision.Allowed)
+			assert.Equal(t, 0, decision.Rule)
+		}
+	}
+}
+
+func TestAccessRulesSymlinkTarget(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks require elevated privileges on Windows")
+	}
+	service, tempDir := setupAccessService(t)
+	defer cleanupTestService(t, tempDir)
+	require.NoError(t, service.SetSymlinkPolicy(types.SymlinkPolicyFollow))
+
+	require.NoError(t, os.Symlink(".env", filepath.Join(tempDir, "innocent.txt")))
+	require.NoError(t, os.Symlink("secret", filepath.Join(tempDir, "docs")))
+	require.NoError(t, os.Symlink("config/.env", filepath.Join(tempDir, "public.txt")))
+
+	// 规则同时作用于链接的目标 / Rules also apply to the target of a link
+	_, err := service.ReadFile(&types.ReadFileRequest{Path: "innocent.txt"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), types.ErrAccessDenied)
+
+	_, err = service.ReadFile(&types.ReadFileRequest{Path: "docs/key.txt"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), types.ErrAccessDenied)
+
+	_, err = service.CopyFile(&types.CopyFileRequest{Source: "innocent.txt", Destination: "copy.txt"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), types.ErrAccessDenied)
+
+	resp, err := service.GetAccessRules(&types.GetAccessRulesRequest{Path: "innocent.txt"})
+	require.NoError(t, err)
+	for _, decision := range resp.Decisions {
+		if decision.Operation == types.AccessRead {
+			assert.False(t, decision.Allowed)
+			assert.Equal(t, 2, decision.Rule)
+		}
+	}
+
+	// 两者都允许时可以读取 / Reading works when both are allowed
+	read, err := service.ReadFile(&types.ReadFileRequest{Path: "public.txt"})
+	require.NoError(t, err)
+	assert.Equal(t, "PUBLIC=1\n", read.Content)
+}
+
+func TestAccessRulesMoveRequiresRead(t *testing.T) {
+	service, tempDir := setupAccessService(t)
+	defer cleanupTestService(t, tempDir)
+
+	// 移动会让禁止读取的内容在目标处可读 / A move would make read-denied content readable at the destination
+	_, err := service.MoveFile(&types.MoveFileRequest{Source: ".env", Destination: "x.txt"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), types.ErrAccessDenied)
+
+	_, err = service.Move(&types.MoveRequest{Source: ".env", Destination: "y.txt"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), types.ErrAccessDenied)
+
+	_, err = service.MoveDirectory(&types.MoveDirectoryRequest{Source: "app", Destination: "moved"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), types.ErrAccessDenied)
+
+	assert.FileExists(t, filepath.Join(tempDir, ".env"))
+	assert.FileExists(t, filepath.Join(tempDir, "app", ".env"))
+	assert.NoFileExists(t, filepath.Join(tempDir, "x.txt"))
+	assert.NoFileExists(t, filepath.Join(tempDir, "y.txt"))
+	assert.NoDirExists(t, filepath.Join(tempDir, "moved"))
+
+	_, err = service.MoveFile(&types.MoveFileRequest{Source: "src/main.go", Destination: "src/app.go"})
+	require.NoError(t, err)
+}
+
+func TestAccessRulesSnapshotSkipsDeniedFiles(t *testing.T) {
+	service, tempDir := setupAccessService(t)
+	defer cleanupTestService(t, tempDir)
+
+	resp, err := service.CreateSnapshot(&types.CreateSnapshotRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, 4, resp.Snapshot.FileCount)
+
+	// 禁止读取的文件不进入对象库 / Read-denied files do not enter the object store
+	secret := contentSHA256([]byte("TOKEN=abc\n"))
+	assert.NoFileExists(t, service.snapshotObjectPath(secret))
+
+	// 内部存储中的副本不能被读取或列出 / Copies in the internal stores cannot be read or listed
+	public := contentSHA256([]byte("package main\n"))
+	require.FileExists(t, service.snapshotObjectPath(public))
+	_, err = service.ReadFile(&types.ReadFileRequest{Path: filepath.Join(SnapshotDirName, snapshotObjectsDir, public[:2], public)})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), types.ErrAccessDenied)
+
+	for _, dir := range []string{SnapshotDirName, TrashDirName} {
+		_, err = service.ListDir(&types.ListDirRequest{Path: dir})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), types.ErrAccessDenied)
+	}
+
+	list, err := service.ListDir(&types.ListDirRequest{Path: "."})
+	require.NoError(t, err)
+	assert.NotContains(t, listNames(list), SnapshotDirName)
+}
+
+func TestAccessRulesMountPattern(t *testing.T) {
+	service, tempDir, dataDir, _ := setupMountService(t)
+	defer cleanupTestService(t, tempDir)
+
+	require.NoError(t, service.SetAccessConfig(types.AccessConfig{Rules: []types.AccessRule{
+		{Pattern: "data:**", Operations: []types.AccessOperation{types.AccessWrite}},
+	}}))
+
+	_, err := service.WriteFile(&types.WriteFileRequest{Path: "data:file.txt", Content: "x"})
+	assert.ErrorContains(t, err, types.ErrAccessDenied)
+	assert.NoFileExists(t, filepath.Join(dataDir, "file.txt"))
+
+	// 带挂载前缀的规则不影响默认沙箱 / Mount-prefixed rules do not affect the default sandbox
+	_, err = service.WriteFile(&types.WriteFileRequest{Path: "file.txt", Content: "x"})
+	assert.NoError(t, err)
+}
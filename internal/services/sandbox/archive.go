@@ -0,0 +1,790 @@
+// Copyright 2024 MCP Toolkit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sandbox
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"mcp-toolkit/pkg/types"
+
+	"github.com/klauspost/compress/zstd"
+	"go.uber.org/zap"
+)
+
+// archiveSource 待打包的条目 / Entry to be packed
+type archiveSource struct {
+	name string      // 归档中的名称 / Name inside the archive
+	path string      // 磁盘上的绝对路径 / Absolute path on disk
+	info fs.FileInfo // 文件信息(链接为目标的信息) / File info (of the target for links)
+}
+
+// archiveItem 读取归档时的条目 / Entry produced while reading an archive
+type archiveItem struct {
+	entry  types.ArchiveEntry
+	mode   fs.FileMode
+	reader io.Reader
+}
+
+// detectArchiveFormat 确定归档格式,未指定时按扩展名推断 / Determine the archive format, inferring it from the extension when unset
+func detectArchiveFormat(path, format string) (string, error) {
+	format = strings.ToLower(format)
+	switch format {
+	case types.ArchiveFormatZip, types.ArchiveFormatTar, types.ArchiveFormatTarGz, types.ArchiveFormatTarZst:
+		return format, nil
+	case "":
+	default:
+		return "", fmt.Errorf("unsupported archive format: %s", format)
+	}
+
+	name := strings.ToLower(path)
+	switch {
+	case strings.HasSuffix(name, ".zip"):
+		return types.ArchiveFormatZip, nil
+	case strings.HasSuffix(name, ".tar"):
+		return types.ArchiveFormatTar, nil
+	case strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tgz"):
+		return types.ArchiveFormatTarGz, nil
+	case strings.HasSuffix(name, ".tar.zst"), strings.HasSuffix(name, ".tzst"):
+		return types.ArchiveFormatTarZst, nil
+	}
+	return "", fmt.Errorf("cannot infer archive format from %q, set format explicitly", path)
+}
+
+// CreateArchive 将文件和目录打包为归档 / Pack files and directories into an archive
+func (s *Service) CreateArchive(req *types.CreateArchiveRequest) (*types.CreateArchiveResponse, error) {
+	// 参数验证 / Parameter validation
+	if err := validateCreateArchiveRequest(req); err != nil {
+		return nil, err
+	}
+
+	format, err := detectArchiveFormat(req.Path, req.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	archivePath, err := s.validateWritePath(req.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer s.locks.lock(writeLock(archivePath))()
+	if info, statErr := s.fsys.Stat(archivePath); statErr == nil {
+		if info.IsDir() {
+			return nil, errors.New(types.ErrIsDirectory)
+		}
+		if !req.Overwrite {
+			return nil, fmt.Errorf("archive already exists: %s, set overwrite to replace it", req.Path)
+		}
+	}
+
+	sources, totalSize, err := s.collectArchiveSources(req, archivePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.fsys.MkdirAll(filepath.Dir(archivePath), DefaultDirPerm); err != nil {
+		return nil, fmt.Errorf("failed to create parent directory: %w", err)
+	}
+
+	// 先写入临时文件,成功后再重命名 / Write to a temporary file and rename it once complete
+	tmp, err := s.fsys.CreateTemp(filepath.Dir(archivePath), ".archive-*.tmp")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create archive: %w", err)
+	}
+	defer s.fsys.Remove(tmp.Name())
+
+	if err = writeArchive(s.fsys, tmp, format, sources); err != nil {
+		tmp.Close()
+		return nil, err
+	}
+	if err = tmp.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write archive: %w", err)
+	}
+	if err = s.fsys.Rename(tmp.Name(), archivePath); err != nil {
+		return nil, fmt.Errorf("failed to write archive: %w", err)
+	}
+
+	resp := &types.CreateArchiveResponse{
+		Success:   true,
+		Message:   types.MsgArchiveCreated,
+		Path:      req.Path,
+		Format:    format,
+		Entries:   len(sources),
+		TotalSize: totalSize,
+	}
+	if info, statErr := s.fsys.Stat(archivePath); statErr == nil {
+		resp.ArchiveSize = info.Size()
+	}
+
+	s.logger.Info("archive created",
+		zap.String("path", archivePath),
+		zap.String("format", format),
+		zap.Int("entries", resp.Entries))
+	return resp, nil
+}
+
+// collectArchiveSources 收集待打包的条目,名称相对于每个源的父目录
+// Collect entries to pack, named relative to the parent of each source
+func (s *Service) collectArchiveSources(req *types.CreateArchiveRequest, archivePath string) ([]archiveSource, int64, error) {
+	var sources []archiveSource
+	var totalSize int64
+	seen := make(map[string]bool)
+
+	add := func(name, path string, info fs.FileInfo) error {
+		name = filepath.ToSlash(name)
+		if matchAnyGlob(req.Exclude, name) {
+			return nil
+		}
+		if seen[name] {
+			return fmt.Errorf("duplicate archive entry: %s", name)
+		}
+		seen[name] = true
+
+		if len(sources) >= MaxArchiveEntries {
+			return fmt.Errorf("archive entry count exceeds maximum allowed count of %d", MaxArchiveEntries)
+		}
+		if !info.IsDir() {
+			totalSize += info.Size()
+			if totalSize > MaxArchiveSize {
+				return fmt.Errorf("total uncompressed size exceeds maximum allowed size of %d bytes", MaxArchiveSize)
+			}
+		}
+		sources = append(sources, archiveSource{name: name, path: path, info: info})
+		return nil
+	}
+
+	for _, source := range req.Sources {
+		validPath, err := s.validateAccessPath(source, types.AccessRead)
+		if err != nil {
+			return nil, 0, err
+		}
+		info, err := s.fsys.Stat(validPath)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil, 0, fmt.Errorf("%s: %s", types.ErrFileNotFound, source)
+			}
+			return nil, 0, fmt.Errorf("failed to stat source: %w", err)
+		}
+
+		// 沙箱或挂载的根目录按其内容打包 / The sandbox or mount root is packed by its contents
+		base := filepath.Dir(validPath)
+		if validPath == s.mountFor(validPath).dir {
+			base = validPath
+		}
+
+		if !info.IsDir() {
+			if err = add(filepath.Base(validPath), validPath, info); err != nil {
+				return nil, 0, err
+			}
+			continue
+		}
+
+		err = walkDir(s.fsys, validPath, func(path string, d fs.DirEntry, walkErr error) error {
+			if walkErr != nil {
+				return walkErr
+			}
+			if path == archivePath || path == base {
+				return nil
+			}
+			// 规则禁止列出的目录和禁止读取的文件不打包 / Directories the rules hide and files they deny reading are not packed
+			if d.IsDir() && !s.accessAllowed(path, types.AccessList) {
+				return filepath.SkipDir
+			}
+			if !d.IsDir() && !s.accessAllowed(path, types.AccessRead) {
+				return nil
+			}
+
+			var entryInfo fs.FileInfo
+			var err error
+			if d.Type()&fs.ModeSymlink != 0 {
+				// 只打包指向常规文件的链接,不跟随目录链接 / Only pack links to regular files; directory links are not followed
+				if !s.symlinkEntryAllowed(path) {
+					return nil
+				}
+				if entryInfo, err = s.fsys.Stat(path); err != nil || !entryInfo.Mode().IsRegular() {
+					return nil
+				}
+			} else {
+				if !d.IsDir() && !d.Type().IsRegular() {
+					return nil
+				}
+				if entryInfo, err = d.Info(); err != nil {
+					return err
+				}
+			}
+
+			rel, err := filepath.Rel(base, path)
+			if err != nil {
+				return err
+			}
+			if d.IsDir() && matchAnyGlob(req.Exclude, filepath.ToSlash(rel)) {
+				return filepath.SkipDir
+			}
+			return add(rel, path, entryInfo)
+		})
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+	return sources, totalSize, nil
+}
+
+// writeArchive 将条目写入归档 / Write entries into an archive
+func writeArchive(fsys FileSystem, w io.Writer, format string, sources []archiveSource) error {
+	if format == types.ArchiveFormatZip {
+		zw := zip.NewWriter(w)
+		for _, source := range sources {
+			if err := writeZipEntry(fsys, zw, source); err != nil {
+				return err
+			}
+		}
+		if err := zw.Close(); err != nil {
+			return fmt.Errorf("failed to write archive: %w", err)
+		}
+		return nil
+	}
+
+	var compressor io.WriteCloser
+	switch format {
+	case types.ArchiveFormatTarGz:
+		compressor = gzip.NewWriter(w)
+	case types.ArchiveFormatTarZst:
+		encoder, err := zstd.NewWriter(w)
+		if err != nil {
+			return fmt.Errorf("failed to create zstd encoder: %w", err)
+		}
+		compressor = encoder
+	}
+	if compressor != nil {
+		w = compressor
+	}
+
+	tw := tar.NewWriter(w)
+	for _, source := range sources {
+		if err := writeTarEntry(fsys, tw, source); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	if compressor != nil {
+		if err := compressor.Close(); err != nil {
+			return fmt.Errorf("failed to write archive: %w", err)
+		}
+	}
+	return nil
+}
+
+// writeZipEntry 写入单个zip条目 / Write a single zip entry
+func writeZipEntry(fsys FileSystem, zw *zip.Writer, source archiveSource) error {
+	header, err := zip.FileInfoHeader(source.info)
+	if err != nil {
+		return fmt.Errorf("failed to create header for %s: %w", source.name, err)
+	}
+	header.Name = source.name
+	if source.info.IsDir() {
+		header.Name += "/"
+		header.Method = zip.Store
+	} else {
+		header.Method = zip.Deflate
+	}
+
+	w, err := zw.CreateHeader(header)
+	if err != nil {
+		return fmt.Errorf("failed to write archive entry %s: %w", source.name, err)
+	}
+	if source.info.IsDir() {
+		return nil
+	}
+	return copySourceFile(fsys, w, source)
+}
+
+// writeTarEntry 写入单个tar条目 / Write a single tar entry
+func writeTarEntry(fsys FileSystem, tw *tar.Writer, source archiveSource) error {
+	header, err := tar.FileInfoHeader(source.info, "")
+	if err != nil {
+		return fmt.Errorf("failed to create header for %s: %w", source.name, err)
+	}
+	header.Name = source.name
+	if source.info.IsDir() {
+		header.Name += "/"
+	}
+
+	if err = tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to write archive entry %s: %w", source.name, err)
+	}
+	if source.info.IsDir() {
+		return nil
+	}
+	return copySourceFile(fsys, tw, source)
+}
+
+// copySourceFile 将源文件内容复制到归档 / Copy the content of a source file into the archive
+func copySourceFile(fsys FileSystem, w io.Writer, source archiveSource) error {
+	file, err := fsys.Open(source.path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", source.name, err)
+	}
+	defer file.Close()
+
+	if _, err = io.Copy(w, file); err != nil {
+		return fmt.Errorf("failed to write archive entry %s: %w", source.name, err)
+	}
+	return nil
+}
+
+// ExtractArchive 解压归档或列出其内容 / Extract an archive or list its contents
+func (s *Service) ExtractArchive(req *types.ExtractArchiveRequest) (*types.ExtractArchiveResponse, error) {
+	// 参数验证 / Parameter validation
+	if err := validateExtractArchiveRequest(req); err != nil {
+		return nil, err
+	}
+
+	archivePath, err := s.validateAccessPath(req.Path, types.AccessRead)
+	if err != nil {
+		return nil, err
+	}
+	info, err := s.fsys.Stat(archivePath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, errors.New(types.ErrFileNotFound)
+		}
+		return nil, fmt.Errorf("failed to stat archive: %w", err)
+	}
+	if info.IsDir() {
+		return nil, errors.New(types.ErrIsDirectory)
+	}
+
+	format, err := detectArchiveFormat(req.Path, req.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &types.ExtractArchiveResponse{Format: format}
+	if req.ListOnly {
+		err = readArchive(s.fsys, archivePath, format, func(item *archiveItem) error {
+			if resp.EntryCount++; resp.EntryCount > MaxArchiveEntries {
+				return fmt.Errorf("archive entry count exceeds maximum allowed count of %d", MaxArchiveEntries)
+			}
+			resp.TotalSize += item.entry.Size
+			resp.Entries = append(resp.Entries, item.entry)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		resp.Success = true
+		resp.Message = fmt.Sprintf("archive contains %d entries", resp.EntryCount)
+		return resp, nil
+	}
+
+	destination := req.Destination
+	if destination == "" {
+		// 按解析后的路径取父目录以保留挂载前缀 / Take the parent of the resolved path so the mount prefix is kept
+		destination = s.displayPath(filepath.Dir(archivePath))
+	}
+	destPath, err := s.validateWritePath(destination)
+	if err != nil {
+		return nil, err
+	}
+	defer s.locks.lock(readLock(archivePath), writeLock(destPath))()
+	if info, statErr := s.fsys.Stat(destPath); statErr == nil && !info.IsDir() {
+		return nil, errors.New(types.ErrNotDirectory)
+	}
+	resp.Destination = destination
+
+	// 配额按解压后新增的字节数和条目数计算 / The quota is charged with the bytes and entries the extraction adds
+	var addBytes, addFiles int64
+	planned := make(map[string]bool)
+	entryTypes := make(map[string]string)
+	countNew := func(target string) {
+		for p := target; !planned[p]; p = filepath.Dir(p) {
+			if _, statErr := s.fsys.Lstat(p); statErr == nil {
+				break
+			}
+			planned[p] = true
+			addFiles++
+		}
+	}
+
+	// 第一遍检查所有条目,任何问题都不会写入文件 / The first pass checks every entry so nothing is written if any of them is rejected
+	err = readArchive(s.fsys, archivePath, format, func(item *archiveItem) error {
+		if resp.EntryCount++; resp.EntryCount > MaxArchiveEntries {
+			return fmt.Errorf("archive entry count exceeds maximum allowed count of %d", MaxArchiveEntries)
+		}
+		target, err := s.archiveEntryPath(destination, item.entry.Name)
+		if err != nil {
+			return err
+		}
+		// 重复的名称会让后面的条目悄悄覆盖前面的,绕过覆盖检查 / A repeated name would silently replace the earlier entry, bypassing the overwrite check
+		if seen, ok := entryTypes[target]; ok && (seen == types.ArchiveEntryFile || item.entry.Type == types.ArchiveEntryFile) {
+			return fmt.Errorf("archive contains duplicate entry %s", item.entry.Name)
+		}
+		entryTypes[target] = item.entry.Type
+
+		switch item.entry.Type {
+		case types.ArchiveEntryFile:
+			if item.entry.Size > MaxFileSize {
+				return fmt.Errorf("archive entry %s exceeds maximum allowed file size of %d bytes", item.entry.Name, MaxFileSize)
+			}
+			resp.TotalSize += item.entry.Size
+			if resp.TotalSize > MaxArchiveSize {
+				return fmt.Errorf("total uncompressed size exceeds maximum allowed size of %d bytes", MaxArchiveSize)
+			}
+			addBytes += item.entry.Size
+			if info, statErr := s.fsys.Lstat(target); statErr == nil {
+				if info.IsDir() {
+					return fmt.Errorf("archive entry %s: %s", item.entry.Name, types.ErrIsDirectory)
+				}
+				if !req.Overwrite {
+					return fmt.Errorf("file already exists: %s, set overwrite to replace it", item.entry.Name)
+				}
+				if info.Mode().IsRegular() && !planned[target] {
+					addBytes -= info.Size()
+				}
+				planned[target] = true
+			}
+			countNew(target)
+		case types.ArchiveEntryDirectory:
+			countNew(target)
+		default:
+			// 链接和特殊文件可能指向沙箱外,一律跳过 / Links and special files may point outside the sandbox and are always skipped
+			resp.Skipped = append(resp.Skipped, item.entry.Name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if s.inDefaultMount(destPath) {
+		if err = s.checkQuota(addBytes, addFiles); err != nil {
+			return nil, err
+		}
+	}
+
+	// 文件先解压到目标旁的临时文件,全部成功后才重命名到位,失败时删除本次创建的所有内容
+	// Files are first extracted to temporary files next to their targets and only renamed into place once all succeeded; on failure everything created is removed
+	extraction := &archiveExtraction{fsys: s.fsys}
+	committed := false
+	defer func() {
+		if !committed {
+			extraction.rollback()
+		}
+	}()
+
+	var written int64
+	err = readArchive(s.fsys, archivePath, format, func(item *archiveItem) error {
+		if item.entry.Type != types.ArchiveEntryFile && item.entry.Type != types.ArchiveEntryDirectory {
+			return nil
+		}
+		// 重新验证以检查本次解压中新出现的链接 / Validate again to catch links that appeared during this extraction
+		target, err := s.archiveEntryPath(destination, item.entry.Name)
+		if err != nil {
+			return err
+		}
+		if item.entry.Type == types.ArchiveEntryDirectory {
+			if err = extraction.mkdirAll(target); err != nil {
+				return fmt.Errorf("failed to create directory %s: %w", item.entry.Name, err)
+			}
+			return nil
+		}
+
+		n, err := extraction.stageFile(target, item, MaxArchiveSize-written)
+		written += n
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err = extraction.commit(); err != nil {
+		return nil, err
+	}
+	committed = true
+
+	resp.Success = true
+	resp.Message = types.MsgArchiveExtracted
+	s.logger.Info("archive extracted",
+		zap.String("path", archivePath),
+		zap.String("destination", destPath),
+		zap.Int("entries", resp.EntryCount))
+	return resp, nil
+}
+
+// archiveEntryPath 验证归档条目名称并返回目标路径,拒绝绝对路径和跳出目标目录的名称
+// Validate an archive entry name and return its target path, rejecting absolute names and names escaping the destination
+func (s *Service) archiveEntryPath(destination, name string) (string, error) {
+	local := filepath.FromSlash(strings.TrimSuffix(name, "/"))
+	if local == "" || !filepath.IsLocal(local) {
+		s.logger.Warn("unsafe archive entry rejected", zap.String("entry", name))
+		return "", fmt.Errorf("archive entry %q: %s", name, types.ErrSandboxViolation)
+	}
+	target, err := s.validateWritePath(filepath.Join(destination, local))
+	if err != nil {
+		return "", fmt.Errorf("archive entry %q: %w", name, err)
+	}
+	return target, nil
+}
+
+// archiveExtraction 记录解压过程中创建的路径,使失败的解压不留下半成品
+// Track the paths an extraction creates so a failed extraction leaves nothing half-written behind
+type archiveExtraction struct {
+	fsys    FileSystem
+	created []string      // 本次新建的目录和文件,按创建顺序 / Directories and files created by this extraction, in creation order
+	staged  []stagedEntry // 等待重命名到位的临时文件 / Temporary files waiting to be renamed into place
+}
+
+// stagedEntry 已解压但尚未就位的文件 / File extracted but not yet in place
+type stagedEntry struct {
+	temp   string // 临时文件路径 / Temporary file path
+	target string // 最终路径 / Final path
+}
+
+// mkdirAll 创建目录及缺失的父目录并记录新建的部分 / Create a directory and its missing parents, recording the ones created
+func (x *archiveExtraction) mkdirAll(dir string) error {
+	var missing []string
+	for p := dir; p != filepath.Dir(p); p = filepath.Dir(p) {
+		if _, err := x.fsys.Lstat(p); err == nil {
+			break
+		}
+		missing = append(missing, p)
+	}
+	if err := x.fsys.MkdirAll(dir, DefaultDirPerm); err != nil {
+		return err
+	}
+	for i := len(missing) - 1; i >= 0; i-- {
+		x.created = append(x.created, missing[i])
+	}
+	return nil
+}
+
+// stageFile 将文件条目写入目标目录中的临时文件 / Write a file entry to a temporary file in its target directory
+func (x *archiveExtraction) stageFile(target string, item *archiveItem, budget int64) (int64, error) {
+	if err := x.mkdirAll(filepath.Dir(target)); err != nil {
+		return 0, fmt.Errorf("failed to create parent directory: %w", err)
+	}
+	file, err := x.fsys.CreateTemp(filepath.Dir(target), "."+filepath.Base(target)+".tmp-*")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create file %s: %w", item.entry.Name, err)
+	}
+	x.staged = append(x.staged, stagedEntry{temp: file.Name(), target: target})
+
+	perm := item.mode.Perm()
+	if perm == 0 {
+		perm = DefaultFilePerm
+	}
+	n, err := copyArchiveEntry(file, item, budget)
+	if err == nil {
+		err = file.Chmod(perm)
+	}
+	if closeErr := file.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to extract %s: %w", item.entry.Name, closeErr)
+	}
+	if err != nil {
+		return n, err
+	}
+	_ = x.fsys.Chtimes(file.Name(), item.entry.ModTime, item.entry.ModTime)
+	return n, nil
+}
+
+// copyArchiveEntry 复制条目内容,实际内容超过声明大小或剩余预算时失败
+// Copy the content of an entry, failing when it exceeds its declared size or the remaining budget
+func copyArchiveEntry(w io.Writer, item *archiveItem, budget int64) (int64, error) {
+	limit := min(item.entry.Size, budget)
+	n, err := io.Copy(w, io.LimitReader(item.reader, limit+1))
+	if err != nil {
+		return n, fmt.Errorf("failed to extract %s: %w", item.entry.Name, err)
+	}
+	switch {
+	case n > item.entry.Size:
+		return n, fmt.Errorf("archive entry %s is larger than its declared size", item.entry.Name)
+	case n > limit:
+		return n, fmt.Errorf("extracting %s exceeds the maximum total extracted size of %d bytes", item.entry.Name, MaxArchiveSize)
+	}
+	return n, nil
+}
+
+// commit 将暂存的文件重命名到位 / Rename the staged files into place
+func (x *archiveExtraction) commit() error {
+	for i, entry := range x.staged {
+		_, statErr := x.fsys.Lstat(entry.target)
+		if err := x.fsys.Rename(entry.temp, entry.target); err != nil {
+			x.staged = x.staged[i:]
+			return fmt.Errorf("failed to replace file: %w", err)
+		}
+		if statErr != nil {
+			x.created = append(x.created, entry.target)
+		}
+	}
+	x.staged = nil
+	return nil
+}
+
+// rollback 删除临时文件以及本次新建的文件和目录;提交中途失败时,已被替换的原有文件无法恢复
+// Remove the temporary files and the files and directories this extraction created; existing files already replaced when a commit fails midway cannot be restored
+func (x *archiveExtraction) rollback() {
+	for _, entry := range x.staged {
+		_ = x.fsys.Remove(entry.temp)
+	}
+	for i := len(x.created) - 1; i >= 0; i-- {
+		_ = x.fsys.Remove(x.created[i])
+	}
+}
+
+// readArchive 按顺序读取归档中的条目 / Read archive entries in order
+func readArchive(fsys FileSystem, path, format string, fn func(item *archiveItem) error) error {
+	if format == types.ArchiveFormatZip {
+		return readZipArchive(fsys, path, fn)
+	}
+
+	file, err := fsys.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer file.Close()
+
+	var r io.Reader = file
+	switch format {
+	case types.ArchiveFormatTarGz:
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return fmt.Errorf("failed to read gzip stream: %w", err)
+		}
+		defer gz.Close()
+		r = gz
+	case types.ArchiveFormatTarZst:
+		decoder, err := zstd.NewReader(file, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return fmt.Errorf("failed to read zstd stream: %w", err)
+		}
+		defer decoder.Close()
+		r = decoder
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read archive: %w", err)
+		}
+		if header.Typeflag == tar.TypeXGlobalHeader {
+			continue
+		}
+
+		info := header.FileInfo()
+		item := &archiveItem{
+			entry: types.ArchiveEntry{
+				Name:    header.Name,
+				Type:    tarEntryType(header.Typeflag),
+				Size:    header.Size,
+				Mode:    info.Mode().String(),
+				ModTime: header.ModTime,
+			},
+			mode:   info.Mode(),
+			reader: tr,
+		}
+		if err = fn(item); err != nil {
+			return err
+		}
+	}
+}
+
+// readZipArchive 按顺序读取zip归档中的条目 / Read zip archive entries in order
+func readZipArchive(fsys FileSystem, path string, fn func(item *archiveItem) error) error {
+	file, err := fsys.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat archive: %w", err)
+	}
+	zr, err := zip.NewReader(file, info.Size())
+	if err != nil {
+		return fmt.Errorf("failed to open archive: %w", err)
+	}
+
+	for _, f := range zr.File {
+		info := f.FileInfo()
+		item := &archiveItem{
+			entry: types.ArchiveEntry{
+				Name:    f.Name,
+				Type:    archiveEntryType(info.Mode()),
+				Size:    int64(f.UncompressedSize64),
+				Mode:    info.Mode().String(),
+				ModTime: f.Modified,
+			},
+			mode: info.Mode(),
+		}
+		if err = readZipEntry(f, item, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readZipEntry 打开zip条目并交给回调处理 / Open a zip entry and hand it to the callback
+func readZipEntry(f *zip.File, item *archiveItem, fn func(item *archiveItem) error) error {
+	if item.entry.Type == types.ArchiveEntryFile {
+		rc, err := f.Open()
+		if err != nil {
+			return fmt.Errorf("failed to read archive entry %s: %w", f.Name, err)
+		}
+		defer rc.Close()
+		item.reader = rc
+	}
+	return fn(item)
+}
+
+// tarEntryType 根据tar条目类型返回条目类型,硬链接等没有内容的条目归为其他
+// Return the entry type for a tar type flag; hard links and other entries without content count as other
+func tarEntryType(flag byte) string {
+	switch flag {
+	case tar.TypeReg:
+		return types.ArchiveEntryFile
+	case tar.TypeDir:
+		return types.ArchiveEntryDirectory
+	case tar.TypeSymlink:
+		return types.ArchiveEntrySymlink
+	default:
+		return types.ArchiveEntryOther
+	}
+}
+
+// archiveEntryType 根据文件模式返回条目类型 / Return the entry type for a file mode
+func archiveEntryType(mode fs.FileMode) string {
+	switch {
+	case mode.IsRegular():
+		return types.ArchiveEntryFile
+	case mode.IsDir():
+		return types.ArchiveEntryDirectory
+	case mode&fs.ModeSymlink != 0:
+		return types.ArchiveEntrySymlink
+	default:
+		return types.ArchiveEntryOther
+	}
+}
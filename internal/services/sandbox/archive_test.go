@@ -0,0 +1,274 @@
+package sandbox
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"mcp-toolkit/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchiveRoundTrip(t *testing.T) {
+	for _, name := range []string{"out.zip", "out.tar", "out.tar.gz", "out.tar.zst"} {
+		t.Run(name, func(t *testing.T) {
+			service, tempDir := setupTestService(t)
+			defer cleanupTestService(t, tempDir)
+
+			writeTestFiles(t, tempDir, map[string]string{
+				"src/main.go":     "package main\n",
+				"src/lib/util.go": "package lib\n",
+				"src/debug.log":   "noise",
+				"README.md":       "# readme\n",
+			})
+
+			created, err := service.CreateArchive(&types.CreateArchiveRequest{
+				Sources: []string{"src", "README.md"},
+				Path:    "archives/" + name,
+				Exclude: []string{"*.log"},
+			})
+			require.NoError(t, err)
+			assert.True(t, created.Success)
+			assert.Equal(t, 5, created.Entries)
+			assert.Equal(t, int64(34), created.TotalSize)
+			assert.Positive(t, created.ArchiveSize)
+
+			listed, err := service.ExtractArchive(&types.ExtractArchiveRequest{Path: "archives/" + name, ListOnly: true})
+			require.NoError(t, err)
+			assert.Equal(t, created.Format, listed.Format)
+			assert.Equal(t, 5, listed.EntryCount)
+			names := make([]string, 0, len(listed.Entries))
+			for _, entry := range listed.Entries {
+				names = append(names, entry.Name)
+			}
+			assert.ElementsMatch(t, []string{"src/", "src/main.go", "src/lib/", "src/lib/util.go", "README.md"}, names)
+			assert.NoDirExists(t, filepath.Join(tempDir, "archives", "src"))
+
+			extracted, err := service.ExtractArchive(&types.ExtractArchiveRequest{Path: "archives/" + name, Destination: "restored"})
+			require.NoError(t, err)
+			assert.True(t, extracted.Success)
+			assert.Equal(t, int64(34), extracted.TotalSize)
+
+			data, err := os.ReadFile(filepath.Join(tempDir, "restored", "src", "lib", "util.go"))
+			require.NoError(t, err)
+			assert.Equal(t, "package lib\n", string(data))
+			assert.NoFileExists(t, filepath.Join(tempDir, "restored", "src", "debug.log"))
+
+			// 已存在的文件需要overwrite / Existing files require overwrite
+			_, err = service.ExtractArchive(&types.ExtractArchiveRequest{Path: "archives/" + name, Destination: "restored"})
+			assert.Error(t, err)
+			_, err = service.ExtractArchive(&types.ExtractArchiveRequest{Path: "archives/" + name, Destination: "restored", Overwrite: true})
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestCreateArchiveExistingPath(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{"a.txt": "a", "out.zip": "old"})
+
+	_, err := service.CreateArchive(&types.CreateArchiveRequest{Sources: []string{"a.txt"}, Path: "out.zip"})
+	assert.Error(t, err)
+
+	_, err = service.CreateArchive(&types.CreateArchiveRequest{Sources: []string{"a.txt"}, Path: "out.zip", Overwrite: true})
+	require.NoError(t, err)
+
+	_, err = service.CreateArchive(&types.CreateArchiveRequest{Sources: []string{"a.txt"}, Path: "out.rar"})
+	assert.Error(t, err)
+}
+
+func TestExtractArchiveRejectsUnsafeEntries(t *testing.T) {
+	tests := []struct {
+		name  string
+		entry string
+	}{
+		{name: "parent traversal", entry: "../evil.txt"},
+		{name: "nested traversal", entry: "safe/../../evil.txt"},
+		{name: "absolute path", entry: "/etc/evil.txt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, tempDir := setupTestService(t)
+			defer cleanupTestService(t, tempDir)
+
+			var buf bytes.Buffer
+			zw := zip.NewWriter(&buf)
+			w, err := zw.Create("ok.txt")
+			require.NoError(t, err)
+			_, _ = w.Write([]byte("ok"))
+			w, err = zw.Create(tt.entry)
+			require.NoError(t, err)
+			_, _ = w.Write([]byte("evil"))
+			require.NoError(t, zw.Close())
+			require.NoError(t, os.MkdirAll(filepath.Join(tempDir, "dest"), 0755))
+			require.NoError(t, os.WriteFile(filepath.Join(tempDir, "dest", "bad.zip"), buf.Bytes(), 0644))
+
+			_, err = service.ExtractArchive(&types.ExtractArchiveRequest{Path: "dest/bad.zip"})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), types.ErrSandboxViolation)
+
+			// 检查失败时不写入任何文件 / Nothing is written when the check fails
+			assert.NoFileExists(t, filepath.Join(tempDir, "dest", "ok.txt"))
+			assert.NoFileExists(t, filepath.Join(tempDir, "evil.txt"))
+		})
+	}
+}
+
+func TestExtractArchiveSkipsLinks(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "escape", Typeflag: tar.TypeSymlink, Linkname: "/etc", Mode: 0777}))
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "hard", Typeflag: tar.TypeLink, Linkname: "/etc/passwd"}))
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "file.txt", Typeflag: tar.TypeReg, Size: 4, Mode: 0644}))
+	_, err := tw.Write([]byte("data"))
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "links.tar"), buf.Bytes(), 0644))
+
+	resp, err := service.ExtractArchive(&types.ExtractArchiveRequest{Path: "links.tar", Destination: "out"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"escape", "hard"}, resp.Skipped)
+	assert.FileExists(t, filepath.Join(tempDir, "out", "file.txt"))
+
+	_, err = os.Lstat(filepath.Join(tempDir, "out", "escape"))
+	assert.True(t, os.IsNotExist(err))
+	assert.NoFileExists(t, filepath.Join(tempDir, "out", "hard"))
+}
+
+func TestExtractArchiveSizeLimit(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	// 声明的大小超过单文件上限 / The declared size exceeds the per-file limit
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.CreateRaw(&zip.FileHeader{
+		Name:               "bomb.bin",
+		Method:             zip.Store,
+		CompressedSize64:   4,
+		UncompressedSize64: uint64(MaxFileSize) + 1,
+	})
+	require.NoError(t, err)
+	_, _ = w.Write([]byte("data"))
+	require.NoError(t, zw.Close())
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "bomb.zip"), buf.Bytes(), 0644))
+
+	_, err = service.ExtractArchive(&types.ExtractArchiveRequest{Path: "bomb.zip"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "maximum allowed file size")
+	assert.NoFileExists(t, filepath.Join(tempDir, "bomb.bin"))
+}
+
+func TestExtractArchiveRollsBackOnFailure(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{"dest/a.txt": "old"})
+
+	// 最后一个条目的校验和错误,只有在解压内容时才会发现 / The last entry has a bad checksum that only shows up while extracting its content
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, name := range []string{"a.txt", "sub/b.txt"} {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, _ = w.Write([]byte("new"))
+	}
+	w, err := zw.CreateRaw(&zip.FileHeader{Name: "sub/c.txt", Method: zip.Store, CRC32: 1, CompressedSize64: 3, UncompressedSize64: 3})
+	require.NoError(t, err)
+	_, _ = w.Write([]byte("bad"))
+	require.NoError(t, zw.Close())
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "bad.zip"), buf.Bytes(), 0644))
+
+	_, err = service.ExtractArchive(&types.ExtractArchiveRequest{Path: "bad.zip", Destination: "dest", Overwrite: true})
+	require.Error(t, err)
+
+	// 已有文件保持不变,新建的文件、目录和临时文件都被删除 / Existing files are untouched and new files, directories and temporary files are removed
+	assert.Equal(t, "old", readTestFile(t, filepath.Join(tempDir, "dest", "a.txt")))
+	assert.NoDirExists(t, filepath.Join(tempDir, "dest", "sub"))
+	entries, err := os.ReadDir(filepath.Join(tempDir, "dest"))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestExtractArchiveRejectsDuplicateEntries(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, content := range []string{"first", "second"} {
+		w, err := zw.Create("dup.txt")
+		require.NoError(t, err)
+		_, _ = w.Write([]byte(content))
+	}
+	require.NoError(t, zw.Close())
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "dup.zip"), buf.Bytes(), 0644))
+
+	_, err := service.ExtractArchive(&types.ExtractArchiveRequest{Path: "dup.zip", Destination: "out"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "duplicate entry dup.txt")
+	assert.NoDirExists(t, filepath.Join(tempDir, "out"))
+}
+
+func TestCopyArchiveEntryLimits(t *testing.T) {
+	item := func(declared int64, content string) *archiveItem {
+		return &archiveItem{entry: types.ArchiveEntry{Name: "f.txt", Size: declared}, reader: strings.NewReader(content)}
+	}
+
+	var out bytes.Buffer
+	n, err := copyArchiveEntry(&out, item(4, "data"), 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), n)
+
+	_, err = copyArchiveEntry(&out, item(2, "data"), 10)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "larger than its declared size")
+
+	// 预算耗尽与声明大小错误分开报告 / Running out of budget is reported separately from a wrong declared size
+	_, err = copyArchiveEntry(&out, item(4, "data"), 3)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "maximum total extracted size")
+	assert.NotContains(t, err.Error(), "declared size")
+}
+
+func TestDetectArchiveFormat(t *testing.T) {
+	tests := []struct {
+		path    string
+		format  string
+		want    string
+		wantErr bool
+	}{
+		{path: "a.zip", want: types.ArchiveFormatZip},
+		{path: "a.TAR", want: types.ArchiveFormatTar},
+		{path: "a.tgz", want: types.ArchiveFormatTarGz},
+		{path: "a.tar.gz", want: types.ArchiveFormatTarGz},
+		{path: "a.tzst", want: types.ArchiveFormatTarZst},
+		{path: "a.bin", format: "tar.zst", want: types.ArchiveFormatTarZst},
+		{path: "a.bin", wantErr: true},
+		{path: "a.zip", format: "rar", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path+"/"+tt.format, func(t *testing.T) {
+			got, err := detectArchiveFormat(tt.path, tt.format)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright 2024 MCP Toolkit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sandbox
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"mcp-toolkit/pkg/types"
+)
+
+// writeFileAtomic 通过同目录临时文件和重命名写入文件,崩溃时不会留下截断的文件
+// 目标为符号链接时写入其指向的文件,保持链接本身不变
+// Write a file via a temporary file in the same directory and a rename, so a crash never leaves a truncated file.
+// When the target is a symlink its destination is written and the link itself is kept
+func writeFileAtomic(fsys FileSystem, path string, data []byte, perm fs.FileMode) error {
+	if info, err := fsys.Lstat(path); err == nil && info.Mode()&fs.ModeSymlink != 0 {
+		if resolved, err := fsys.EvalSymlinks(path); err == nil {
+			path = resolved
+		}
+	}
+
+	tmp, err := fsys.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	committed := false
+	defer func() {
+		if !committed {
+			_ = tmp.Close()
+			_ = fsys.Remove(tmpPath)
+		}
+	}()
+
+	if _, err = tmp.Write(data); err != nil {
+		return fmt.Errorf("failed to write temporary file: %w", err)
+	}
+	if err = tmp.Sync(); err != nil {
+		return fmt.Errorf("failed to sync temporary file: %w", err)
+	}
+	if err = tmp.Chmod(perm); err != nil {
+		return fmt.Errorf("failed to set file permissions: %w", err)
+	}
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close temporary file: %w", err)
+	}
+	if err = fsys.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to replace file: %w", err)
+	}
+	committed = true
+	return nil
+}
+
+// fileSHA256 从头计算已打开文件的SHA-256 / Compute the SHA-256 of an open file from its start
+func fileSHA256(file File) (string, error) {
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("failed to seek file: %w", err)
+	}
+	h := sha256.New()
+	if _, err := io.CopyBuffer(h, file, make([]byte, readBufferSize)); err != nil {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// contentSHA256 计算内存中内容的SHA-256 / Compute the SHA-256 of in-memory content
+func contentSHA256(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// fileVersion 返回由文件大小和纳秒修改时间组成的版本标识,只需一次stat即可比较
+// Return a version token made of the file size and the nanosecond modification time, comparable with a single stat
+func fileVersion(info fs.FileInfo) string {
+	return fmt.Sprintf("%x-%x", info.Size(), info.ModTime().UnixNano())
+}
+
+// checkWritePrecondition 检查文件自读取后是否被修改,不满足前置条件时返回冲突错误
+// Check whether the file changed since it was read, returning a conflict error when a precondition fails
+func checkWritePrecondition(fsys FileSystem, path string, req *types.WriteFileRequest) error {
+	if req.ExpectedVersion == "" && req.ExpectedSHA256 == "" && req.ExpectedMtime == nil {
+		return nil
+	}
+
+	file, err := fsys.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("%s: file no longer exists", types.ErrWriteConflict)
+		}
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat file: %w", err)
+	}
+	if info.IsDir() {
+		return errors.New(types.ErrIsDirectory)
+	}
+
+	if version := fileVersion(info); req.ExpectedVersion != "" && req.ExpectedVersion != version {
+		return fmt.Errorf("%s: expected version %s, found %s", types.ErrWriteConflict, req.ExpectedVersion, version)
+	}
+	if req.ExpectedMtime != nil && !info.ModTime().Equal(*req.ExpectedMtime) {
+		return fmt.Errorf("%s: expected mtime %s, found %s", types.ErrWriteConflict,
+			req.ExpectedMtime.Format(time.RFC3339Nano), info.ModTime().Format(time.RFC3339Nano))
+	}
+	if req.ExpectedSHA256 != "" {
+		sum, err := fileSHA256(file)
+		if err != nil {
+			return err
+		}
+		if !strings.EqualFold(sum, req.ExpectedSHA256) {
+			return fmt.Errorf("%s: expected sha256 %s, found %s", types.ErrWriteConflict, req.ExpectedSHA256, sum)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package sandbox
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"mcp-toolkit/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteFileConditional(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{"doc.txt": "v1"})
+
+	read, err := service.ReadFile(&types.ReadFileRequest{Path: "doc.txt", IncludeSHA256: true})
+	require.NoError(t, err)
+	require.Len(t, read.SHA256, 64)
+
+	stat, err := service.FileStat(&types.FileStatRequest{Path: "doc.txt", IncludeSHA256: true})
+	require.NoError(t, err)
+	assert.Equal(t, read.SHA256, stat.SHA256)
+	assert.True(t, read.ModTime.Equal(stat.ModTime))
+
+	// 另一个会话先写入 / Another session writes first
+	other, err := service.WriteFile(&types.WriteFileRequest{Path: "doc.txt", Content: "v2 from other", ExpectedSHA256: read.SHA256})
+	require.NoError(t, err)
+	assert.NotEqual(t, read.SHA256, other.SHA256)
+
+	_, err = service.WriteFile(&types.WriteFileRequest{Path: "doc.txt", Content: "v2 from me", ExpectedSHA256: read.SHA256})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), types.ErrWriteConflict)
+
+	data, err := os.ReadFile(filepath.Join(tempDir, "doc.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "v2 from other", string(data))
+
+	// 使用写入响应中的版本标识可以继续写入 / The token from a write response allows the next write
+	_, err = service.WriteFile(&types.WriteFileRequest{Path: "doc.txt", Content: "v3", ExpectedSHA256: strings.ToUpper(other.SHA256)})
+	require.NoError(t, err)
+}
+
+func TestWriteFileExpectedVersion(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{"doc.txt": "v1"})
+
+	// 默认不计算SHA-256 / The SHA-256 is not computed by default
+	read, err := service.ReadFile(&types.ReadFileRequest{Path: "doc.txt"})
+	require.NoError(t, err)
+	assert.Empty(t, read.SHA256)
+	require.NotEmpty(t, read.Version)
+
+	stat, err := service.FileStat(&types.FileStatRequest{Path: "doc.txt"})
+	require.NoError(t, err)
+	assert.Empty(t, stat.SHA256)
+	assert.Equal(t, read.Version, stat.Version)
+
+	written, err := service.WriteFile(&types.WriteFileRequest{Path: "doc.txt", Content: "v2", ExpectedVersion: read.Version})
+	require.NoError(t, err)
+	assert.NotEqual(t, read.Version, written.Version)
+
+	_, err = service.WriteFile(&types.WriteFileRequest{Path: "doc.txt", Content: "v3", ExpectedVersion: read.Version})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), types.ErrWriteConflict)
+
+	// 大小不变但修改时间变化也会冲突 / A changed mtime with the same size also conflicts
+	later := written.ModTime.Add(time.Second)
+	require.NoError(t, os.Chtimes(filepath.Join(tempDir, "doc.txt"), later, later))
+	_, err = service.WriteFile(&types.WriteFileRequest{Path: "doc.txt", Content: "v3", ExpectedVersion: written.Version})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), types.ErrWriteConflict)
+
+	// 增量修改的响应可用于下次写入 / The response of an incremental change allows the next write
+	appended, err := service.AppendFile(&types.AppendFileRequest{Path: "doc.txt", Content: "+"})
+	require.NoError(t, err)
+	_, err = service.WriteFile(&types.WriteFileRequest{Path: "doc.txt", Content: "v3", ExpectedVersion: appended.Version})
+	require.NoError(t, err)
+	assert.Equal(t, "v3", readTestFile(t, filepath.Join(tempDir, "doc.txt")))
+}
+
+func TestWriteFileExpectedMtime(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{"doc.txt": "v1"})
+
+	read, err := service.ReadFile(&types.ReadFileRequest{Path: "doc.txt"})
+	require.NoError(t, err)
+	mtime := read.ModTime
+
+	later := mtime.Add(time.Second)
+	require.NoError(t, os.Chtimes(filepath.Join(tempDir, "doc.txt"), later, later))
+
+	_, err = service.WriteFile(&types.WriteFileRequest{Path: "doc.txt", Content: "v2", ExpectedMtime: &mtime})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), types.ErrWriteConflict)
+
+	resp, err := service.WriteFile(&types.WriteFileRequest{Path: "doc.txt", Content: "v2", ExpectedMtime: &later})
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+
+	// 文件已被删除时前置条件失败 / The precondition fails when the file was removed
+	require.NoError(t, os.Remove(filepath.Join(tempDir, "doc.txt")))
+	_, err = service.WriteFile(&types.WriteFileRequest{Path: "doc.txt", Content: "v3", ExpectedMtime: &later})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), types.ErrWriteConflict)
+}
+
+func TestWriteFileAtomicKeepsModeAndSymlink(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{"script.sh": "#!/bin/sh\n"})
+	require.NoError(t, os.Chmod(filepath.Join(tempDir, "script.sh"), 0755))
+	require.NoError(t, os.Symlink("script.sh", filepath.Join(tempDir, "link.sh")))
+
+	_, err := service.WriteFile(&types.WriteFileRequest{Path: "link.sh", Content: "#!/bin/sh\necho hi\n"})
+	require.NoError(t, err)
+
+	info, err := os.Lstat(filepath.Join(tempDir, "link.sh"))
+	require.NoError(t, err)
+	assert.NotZero(t, info.Mode()&os.ModeSymlink)
+
+	info, err = os.Stat(filepath.Join(tempDir, "script.sh"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+	data, err := os.ReadFile(filepath.Join(tempDir, "script.sh"))
+	require.NoError(t, err)
+	assert.Equal(t, "#!/bin/sh\necho hi\n", string(data))
+
+	// 不留下临时文件 / No temporary files are left behind
+	entries, err := os.ReadDir(tempDir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+}
+
+func TestValidateWriteFileRequestPrecondition(t *testing.T) {
+	tests := []struct {
+		name    string
+		sha     string
+		wantErr bool
+	}{
+		{name: "no precondition", sha: "", wantErr: false},
+		{name: "valid hash", sha: strings.Repeat("ab", 32), wantErr: false},
+		{name: "too short", sha: "abcd", wantErr: true},
+		{name: "not hex", sha: strings.Repeat("zz", 32), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateWriteFileRequest(&types.WriteFileRequest{Path: "a.txt", ExpectedSHA256: tt.sha})
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,424 @@
+// Copyright 2024 MCP Toolkit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sandbox
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+	"unicode/utf8"
+
+	"mcp-toolkit/pkg/types"
+
+	"go.uber.org/zap"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/unicode"
+)
+
+var (
+	// bomUTF8 UTF-8字节顺序标记 / UTF-8 byte order mark
+	bomUTF8 = []byte{0xEF, 0xBB, 0xBF}
+	// bomUTF16LE 小端UTF-16字节顺序标记 / Little-endian UTF-16 byte order mark
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	// bomUTF16BE 大端UTF-16字节顺序标记 / Big-endian UTF-16 byte order mark
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// charsetAliases 支持的字符集名称及别名 / Supported charset names and their aliases
+var charsetAliases = map[string]string{
+	"utf-8":     types.CharsetUTF8,
+	"utf8":      types.CharsetUTF8,
+	"utf-16le":  types.CharsetUTF16LE,
+	"utf16le":   types.CharsetUTF16LE,
+	"utf-16be":  types.CharsetUTF16BE,
+	"utf16be":   types.CharsetUTF16BE,
+	"gbk":       types.CharsetGBK,
+	"gb2312":    types.CharsetGBK,
+	"cp936":     types.CharsetGBK,
+	"gb18030":   types.CharsetGB18030,
+	"shift_jis": types.CharsetShiftJIS,
+	"shift-jis": types.CharsetShiftJIS,
+	"sjis":      types.CharsetShiftJIS,
+}
+
+// normalizeCharset 返回字符集的规范名称 / Return the canonical name of a charset
+func normalizeCharset(name string) (string, error) {
+	if charset, ok := charsetAliases[strings.ToLower(strings.TrimSpace(name))]; ok {
+		return charset, nil
+	}
+	return "", fmt.Errorf("unsupported charset: %s", name)
+}
+
+// charsetEncoding 返回字符集的编解码器,UTF-8返回nil / Return the codec of a charset, nil for UTF-8
+func charsetEncoding(charset string) encoding.Encoding {
+	switch charset {
+	case types.CharsetUTF16LE:
+		return unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM)
+	case types.CharsetUTF16BE:
+		return unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM)
+	case types.CharsetGBK:
+		return simplifiedchinese.GBK
+	case types.CharsetGB18030:
+		return simplifiedchinese.GB18030
+	case types.CharsetShiftJIS:
+		return japanese.ShiftJIS
+	}
+	return nil
+}
+
+// charsetBOM 返回字符集的字节顺序标记,非UTF字符集返回nil / Return the byte order mark of a charset, nil for non-UTF charsets
+func charsetBOM(charset string) []byte {
+	switch charset {
+	case types.CharsetUTF8:
+		return bomUTF8
+	case types.CharsetUTF16LE:
+		return bomUTF16LE
+	case types.CharsetUTF16BE:
+		return bomUTF16BE
+	}
+	return nil
+}
+
+// detectCharset 根据文件头部检测字符集和BOM长度,二进制内容返回空字符集;采样可能在多字节字符中间截断
+// Detect the charset and BOM length from the head of a file, returning an empty charset for binary content; the sample may end mid-character
+func detectCharset(sample []byte) (string, int) {
+	switch {
+	case bytes.HasPrefix(sample, bomUTF8):
+		return types.CharsetUTF8, len(bomUTF8)
+	case bytes.HasPrefix(sample, bomUTF16LE):
+		return types.CharsetUTF16LE, len(bomUTF16LE)
+	case bytes.HasPrefix(sample, bomUTF16BE):
+		return types.CharsetUTF16BE, len(bomUTF16BE)
+	}
+
+	if bytes.IndexByte(sample, 0) >= 0 {
+		return detectUTF16(sample), 0
+	}
+	if text, _ := trimPartialRunes(sample, false, true); utf8.Valid(text) {
+		return types.CharsetUTF8, 0
+	}
+	if hasControlBytes(sample) {
+		return "", 0
+	}
+
+	// GBK和Shift-JIS的字节范围大量重叠,按常用字符区的占比选择
+	// The byte ranges of GBK and Shift-JIS overlap heavily, so pick by the share of characters in their common ranges
+	gbValid, fourByte, gbCommon := scanGB18030(sample, true)
+	sjisValid, sjisCommon := scanShiftJIS(sample, true)
+	switch {
+	case gbValid && (!sjisValid || gbCommon >= sjisCommon):
+		if fourByte {
+			return types.CharsetGB18030, 0
+		}
+		return types.CharsetGBK, 0
+	case sjisValid:
+		return types.CharsetShiftJIS, 0
+	}
+	return "", 0
+}
+
+// detectUTF16 识别没有BOM的UTF-16文本:以ASCII为主的文本每两个字节中有一个为零
+// Recognise UTF-16 text without a BOM: mostly ASCII text has a zero in every other byte
+func detectUTF16(sample []byte) string {
+	pairs := len(sample) / 2
+	if pairs == 0 {
+		return ""
+	}
+	var evenZeros, oddZeros int
+	for i := 0; i+1 < len(sample); i += 2 {
+		if sample[i] == 0 {
+			evenZeros++
+		}
+		if sample[i+1] == 0 {
+			oddZeros++
+		}
+	}
+	switch {
+	case oddZeros*10 >= pairs*7 && evenZeros*10 <= pairs:
+		return types.CharsetUTF16LE
+	case evenZeros*10 >= pairs*7 && oddZeros*10 <= pairs:
+		return types.CharsetUTF16BE
+	}
+	return ""
+}
+
+// hasControlBytes 是否包含文本中不会出现的控制字符 / Whether the content has control bytes that do not occur in text
+func hasControlBytes(data []byte) bool {
+	for _, b := range data {
+		if b < 0x20 && b != '\t' && b != '\n' && b != '\r' && b != '\f' && b != 0x1b {
+			return true
+		}
+	}
+	return false
+}
+
+// scanGB18030 检查内容是否为合法的GB18030,返回是否包含四字节序列以及双字节字符落在GB2312常用区的比例
+// partial为true时允许末尾的字符不完整
+// Check whether the content is valid GB18030, returning whether it has four-byte sequences and the share of double-byte
+// characters in the common GB2312 range. A truncated final character is allowed when partial is true
+func scanGB18030(data []byte, partial bool) (bool, bool, float64) {
+	var pairs, common int
+	fourByte := false
+	for i := 0; i < len(data); {
+		b := data[i]
+		if b < 0x80 {
+			i++
+			continue
+		}
+		if b == 0x80 || b == 0xFF {
+			return false, false, 0
+		}
+		if i+1 >= len(data) {
+			return partial, fourByte, ratio(common, pairs)
+		}
+		t := data[i+1]
+		switch {
+		case t >= 0x30 && t <= 0x39:
+			if i+3 >= len(data) {
+				return partial, true, ratio(common, pairs)
+			}
+			if data[i+2] < 0x81 || data[i+2] == 0xFF || data[i+3] < 0x30 || data[i+3] > 0x39 {
+				return false, false, 0
+			}
+			fourByte = true
+			i += 4
+		case t >= 0x40 && t <= 0xFE && t != 0x7F:
+			pairs++
+			if b >= 0xA1 && t >= 0xA1 {
+				common++
+			}
+			i += 2
+		default:
+			return false, false, 0
+		}
+	}
+	return true, fourByte, ratio(common, pairs)
+}
+
+// scanShiftJIS 检查内容是否为合法的Shift-JIS,返回多字节字符中假名和第一水准汉字区的比例
+// partial为true时允许末尾的字符不完整
+// Check whether the content is valid Shift-JIS, returning the share of multi-byte characters in the kana and level 1
+// kanji range. A truncated final character is allowed when partial is true
+func scanShiftJIS(data []byte, partial bool) (bool, float64) {
+	var units, common int
+	for i := 0; i < len(data); {
+		b := data[i]
+		switch {
+		case b < 0x80:
+			i++
+		case b >= 0xA1 && b <= 0xDF:
+			// 半角片假名 / Half-width katakana
+			units++
+			i++
+		case (b >= 0x81 && b <= 0x9F) || (b >= 0xE0 && b <= 0xFC):
+			if i+1 >= len(data) {
+				return partial, ratio(common, units)
+			}
+			if t := data[i+1]; t < 0x40 || t == 0x7F || t > 0xFC {
+				return false, 0
+			}
+			units++
+			if b <= 0x9F {
+				common++
+			}
+			i += 2
+		default:
+			return false, 0
+		}
+	}
+	return true, ratio(common, units)
+}
+
+// ratio 计算比例,分母为零时返回0 / Compute a ratio, 0 when the denominator is zero
+func ratio(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) / float64(total)
+}
+
+// decodeText 将指定字符集的内容解码为UTF-8并去掉开头的BOM,内容不是该字符集的合法编码时返回错误
+// Decode content in the given charset to UTF-8, dropping a leading BOM; an error is returned when the content is not valid in that charset
+func decodeText(data []byte, charset string) (string, error) {
+	if bom := charsetBOM(charset); bom != nil {
+		data = bytes.TrimPrefix(data, bom)
+	}
+
+	var valid bool
+	switch charset {
+	case types.CharsetUTF8:
+		valid = utf8.Valid(data)
+	case types.CharsetUTF16LE, types.CharsetUTF16BE:
+		valid = len(data)%2 == 0
+	case types.CharsetGBK:
+		var fourByte bool
+		valid, fourByte, _ = scanGB18030(data, false)
+		valid = valid && !fourByte
+	case types.CharsetGB18030:
+		valid, _, _ = scanGB18030(data, false)
+	case types.CharsetShiftJIS:
+		valid, _ = scanShiftJIS(data, false)
+	}
+	if !valid {
+		return "", fmt.Errorf("content is not valid %s", charset)
+	}
+
+	enc := charsetEncoding(charset)
+	if enc == nil {
+		return string(data), nil
+	}
+	decoded, err := enc.NewDecoder().Bytes(data)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode %s content: %w", charset, err)
+	}
+	return string(decoded), nil
+}
+
+// encodeText 按换行风格和字符集编码UTF-8文本,需要时在开头加上BOM;字符集为空表示UTF-8
+// Encode UTF-8 text with the given line endings and charset, prepending a BOM when asked; an empty charset means UTF-8
+func encodeText(text, charset string, bom bool, lineEnding string) ([]byte, error) {
+	if charset == "" {
+		charset = types.CharsetUTF8
+	}
+	charset, err := normalizeCharset(charset)
+	if err != nil {
+		return nil, err
+	}
+	text = convertLineEndings(text, lineEnding)
+
+	data := []byte(text)
+	if enc := charsetEncoding(charset); enc != nil {
+		if data, err = enc.NewEncoder().Bytes(data); err != nil {
+			return nil, fmt.Errorf("content cannot be encoded as %s: %w", charset, err)
+		}
+	}
+	if bom {
+		data = append(append([]byte{}, charsetBOM(charset)...), data...)
+	}
+	return data, nil
+}
+
+// convertLineEndings 将换行符统一为指定风格,风格为空时保持不变 / Normalise line endings to the given style, unchanged when the style is empty
+func convertLineEndings(text, lineEnding string) string {
+	switch lineEnding {
+	case types.LineEndingLF:
+		return strings.ReplaceAll(text, "\r\n", "\n")
+	case types.LineEndingCRLF:
+		return strings.ReplaceAll(strings.ReplaceAll(text, "\r\n", "\n"), "\n", "\r\n")
+	}
+	return text
+}
+
+// decodeFileText 读取整个文件并从指定字符集解码,文件过大或内容不合法时返回false
+// Read a whole file and decode it from the given charset, returning false when the file is too large or the content is invalid
+func decodeFileText(file File, size int64, charset string) (string, bool, error) {
+	if size > MaxFileSize {
+		return "", false, nil
+	}
+	data, err := readByteRange(file, size, 0, size)
+	if err != nil {
+		return "", false, err
+	}
+	text, err := decodeText(data, charset)
+	if err != nil {
+		return "", false, nil
+	}
+	return text, true, nil
+}
+
+// ConvertEncoding 就地转换文件的字符集和换行符 / Convert the charset and line endings of a file in place
+func (s *Service) ConvertEncoding(req *types.ConvertEncodingRequest) (*types.ConvertEncodingResponse, error) {
+	// 参数验证 / Parameter validation
+	if err := validateConvertEncodingRequest(req); err != nil {
+		return nil, err
+	}
+
+	validPath, err := s.validateWritePath(req.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer s.locks.lock(writeLock(validPath))()
+
+	info, err := s.fsys.Stat(validPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, errors.New(types.ErrFileNotFound)
+		}
+		return nil, fmt.Errorf("failed to stat file: %w", err)
+	}
+	if info.IsDir() {
+		return nil, errors.New(types.ErrIsDirectory)
+	}
+	if info.Size() > MaxFileSize {
+		return nil, fmt.Errorf("file size exceeds maximum allowed size of %d bytes", MaxFileSize)
+	}
+	data, err := s.fsys.ReadFile(validPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	from := req.From
+	if from == "" {
+		if from, _ = detectCharset(data[:min(len(data), BinaryDetectSize)]); from == "" {
+			return nil, errors.New("could not detect the charset of the file, specify from explicitly")
+		}
+	} else if from, err = normalizeCharset(from); err != nil {
+		return nil, err
+	}
+	to, err := normalizeCharset(req.To)
+	if err != nil {
+		return nil, err
+	}
+
+	text, err := decodeText(data, from)
+	if err != nil {
+		return nil, err
+	}
+	converted, err := encodeText(text, to, req.BOM, req.LineEnding)
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(converted)) > MaxFileSize {
+		return nil, fmt.Errorf("file size would exceed maximum allowed size of %d bytes", MaxFileSize)
+	}
+	if err = s.checkWriteQuota(validPath, int64(len(converted))); err != nil {
+		return nil, err
+	}
+	if err = writeFileAtomic(s.fsys, validPath, converted, info.Mode().Perm()); err != nil {
+		return nil, err
+	}
+
+	changed, err := fileChangeResponse(s.fsys, validPath, fmt.Sprintf("converted from %s to %s", from, to))
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Info("file encoding converted", zap.String("path", validPath), zap.String("from", from), zap.String("to", to))
+	return &types.ConvertEncodingResponse{
+		Success: true,
+		Message: changed.Message,
+		From:    from,
+		To:      to,
+		BOM:     req.BOM,
+		Size:    changed.Size,
+		Version: changed.Version,
+		SHA256:  contentSHA256(converted),
+		ModTime: changed.ModTime,
+	}, nil
+}
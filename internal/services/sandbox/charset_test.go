@@ -0,0 +1,196 @@
+package sandbox
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"mcp-toolkit/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/unicode"
+)
+
+const (
+	chineseText  = "第一行：这是一个简体中文文件。\n第二行：用于测试编码检测。\n"
+	japaneseText = "一行目：これは日本語のテキストです。\n二行目：文字コードの検出をテストします。\n"
+)
+
+// mustEncode 按字符集编码测试文本 / Encode test text in a charset
+func mustEncode(t *testing.T, charset, text string) []byte {
+	t.Helper()
+	data, err := charsetEncoding(charset).NewEncoder().Bytes([]byte(text))
+	require.NoError(t, err)
+	return data
+}
+
+func TestDetectCharset(t *testing.T) {
+	utf16LE, err := unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewEncoder().Bytes([]byte("hello"))
+	require.NoError(t, err)
+	gb18030, err := simplifiedchinese.GB18030.NewEncoder().Bytes([]byte("表情😀符号"))
+	require.NoError(t, err)
+	sjis, err := japanese.ShiftJIS.NewEncoder().Bytes([]byte(japaneseText))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		data    []byte
+		charset string
+		bomLen  int
+	}{
+		{"ascii", []byte("plain text\n"), types.CharsetUTF8, 0},
+		{"utf-8", []byte(chineseText), types.CharsetUTF8, 0},
+		{"utf-8 bom", append([]byte{0xEF, 0xBB, 0xBF}, "text"...), types.CharsetUTF8, 3},
+		{"utf-16le bom", utf16LE, types.CharsetUTF16LE, 2},
+		{"utf-16be without bom", mustEncode(t, types.CharsetUTF16BE, "hello world"), types.CharsetUTF16BE, 0},
+		{"gbk", mustEncode(t, types.CharsetGBK, chineseText), types.CharsetGBK, 0},
+		{"gb18030", gb18030, types.CharsetGB18030, 0},
+		{"shift_jis", sjis, types.CharsetShiftJIS, 0},
+		{"binary", []byte{0x89, 'P', 'N', 'G', 0x0D, 0x0A, 0x1A, 0x0A, 0x00, 0x00, 0x00, 0x0D, 0x49, 0x48}, "", 0},
+		{"control bytes", []byte{0x01, 0x02, 0xC4, 0xE3, 0x03}, "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			charset, bomLen := detectCharset(tt.data)
+			assert.Equal(t, tt.charset, charset)
+			assert.Equal(t, tt.bomLen, bomLen)
+		})
+	}
+
+	// 采样在多字节字符中间截断时仍能识别 / A sample cut in the middle of a character is still recognised
+	gbk := mustEncode(t, types.CharsetGBK, chineseText)
+	charset, _ := detectCharset(gbk[:len(gbk)-6+1])
+	assert.Equal(t, types.CharsetGBK, charset)
+}
+
+func TestReadFileCharsets(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	utf16, err := encodeText(chineseText, types.CharsetUTF16LE, true, "")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "gbk.txt"), mustEncode(t, types.CharsetGBK, chineseText), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "sjis.txt"), mustEncode(t, types.CharsetShiftJIS, japaneseText), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "utf16.txt"), utf16, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "plain.txt"), []byte("plain\n"), 0644))
+
+	resp, err := service.ReadFile(&types.ReadFileRequest{Path: "gbk.txt"})
+	require.NoError(t, err)
+	assert.Equal(t, chineseText, resp.Content)
+	assert.Equal(t, types.ContentEncodingUTF8, resp.Encoding)
+	assert.Equal(t, types.CharsetGBK, resp.Charset)
+	assert.False(t, resp.BOM)
+	assert.Equal(t, "text/plain; charset=gbk", resp.MimeType)
+	assert.Equal(t, 2, resp.TotalLines)
+
+	// 行号相对于解码后的文本 / Line numbers refer to the decoded text
+	resp, err = service.ReadFile(&types.ReadFileRequest{Path: "sjis.txt", StartLine: 2})
+	require.NoError(t, err)
+	assert.Equal(t, "二行目：文字コードの検出をテストします。\n", resp.Content)
+	assert.Equal(t, types.CharsetShiftJIS, resp.Charset)
+
+	resp, err = service.ReadFile(&types.ReadFileRequest{Path: "utf16.txt", TailLines: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "第二行：用于测试编码检测。\n", resp.Content)
+	assert.Equal(t, types.CharsetUTF16LE, resp.Charset)
+	assert.True(t, resp.BOM)
+	assert.Equal(t, int64(len(utf16)), resp.Size)
+
+	resp, err = service.ReadFile(&types.ReadFileRequest{Path: "plain.txt"})
+	require.NoError(t, err)
+	assert.Equal(t, types.CharsetUTF8, resp.Charset)
+	assert.Equal(t, "plain\n", resp.Content)
+}
+
+func TestWriteFileCharset(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+	path := filepath.Join(tempDir, "out.txt")
+
+	resp, err := service.WriteFile(&types.WriteFileRequest{Path: "out.txt", Content: "中文\n内容\n", Charset: "GBK", LineEnding: types.LineEndingCRLF})
+	require.NoError(t, err)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, mustEncode(t, types.CharsetGBK, "中文\r\n内容\r\n"), data)
+	sum, err := fileSHA256(mustOpen(t, path))
+	require.NoError(t, err)
+	assert.Equal(t, sum, resp.SHA256)
+
+	_, err = service.WriteFile(&types.WriteFileRequest{Path: "out.txt", Content: "a\r\nb\n", BOM: true, LineEnding: types.LineEndingLF})
+	require.NoError(t, err)
+	assert.Equal(t, "\xEF\xBB\xBFa\nb\n", readTestFile(t, path))
+
+	_, err = service.WriteFile(&types.WriteFileRequest{Path: "out.txt", Content: "hi", Charset: types.CharsetUTF16BE, BOM: true})
+	require.NoError(t, err)
+	assert.Equal(t, "\xFE\xFF\x00h\x00i", readTestFile(t, path))
+
+	// 无法表示的字符和不支持的选项 / Unrepresentable characters and unsupported options
+	_, err = service.WriteFile(&types.WriteFileRequest{Path: "out.txt", Content: "😀", Charset: types.CharsetGBK})
+	assert.ErrorContains(t, err, "cannot be encoded as gbk")
+	_, err = service.WriteFile(&types.WriteFileRequest{Path: "out.txt", Content: "x", Charset: types.CharsetGBK, BOM: true})
+	assert.ErrorContains(t, err, "byte order marks")
+	_, err = service.WriteFile(&types.WriteFileRequest{Path: "out.txt", Content: "x", Charset: "latin-9"})
+	assert.ErrorContains(t, err, "unsupported charset")
+	_, err = service.WriteFile(&types.WriteFileRequest{Path: "out.txt", Content: "x", LineEnding: "cr"})
+	assert.ErrorContains(t, err, "invalid line ending")
+	assert.Equal(t, "\xFE\xFF\x00h\x00i", readTestFile(t, path))
+}
+
+// mustOpen 打开宿主文件并在测试结束时关闭 / Open a host file and close it when the test ends
+func mustOpen(t *testing.T, path string) File {
+	t.Helper()
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = file.Close() })
+	return file
+}
+
+func TestConvertEncoding(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+	path := filepath.Join(tempDir, "legacy.txt")
+	require.NoError(t, os.WriteFile(path, mustEncode(t, types.CharsetGBK, chineseText), 0600))
+
+	// 源字符集自动检测 / The source charset is detected
+	resp, err := service.ConvertEncoding(&types.ConvertEncodingRequest{Path: "legacy.txt", To: "utf8"})
+	require.NoError(t, err)
+	assert.Equal(t, types.CharsetGBK, resp.From)
+	assert.Equal(t, types.CharsetUTF8, resp.To)
+	assert.Equal(t, chineseText, readTestFile(t, path))
+	assert.Equal(t, int64(len(chineseText)), resp.Size)
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	resp, err = service.ConvertEncoding(&types.ConvertEncodingRequest{Path: "legacy.txt", To: types.CharsetUTF16LE, BOM: true, LineEnding: types.LineEndingCRLF})
+	require.NoError(t, err)
+	assert.True(t, resp.BOM)
+	read, err := service.ReadFile(&types.ReadFileRequest{Path: "legacy.txt", IncludeSHA256: true})
+	require.NoError(t, err)
+	assert.Equal(t, types.CharsetUTF16LE, read.Charset)
+	assert.True(t, read.BOM)
+	assert.Equal(t, "第一行：这是一个简体中文文件。\r\n第二行：用于测试编码检测。\r\n", read.Content)
+	assert.Equal(t, read.SHA256, resp.SHA256)
+	assert.Equal(t, read.Version, resp.Version)
+
+	// BOM在转换时被去掉 / The BOM is dropped by the conversion
+	_, err = service.ConvertEncoding(&types.ConvertEncodingRequest{Path: "legacy.txt", From: types.CharsetUTF16LE, To: types.CharsetGB18030, LineEnding: types.LineEndingLF})
+	require.NoError(t, err)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, mustEncode(t, types.CharsetGB18030, chineseText), data)
+
+	_, err = service.ConvertEncoding(&types.ConvertEncodingRequest{Path: "legacy.txt", From: types.CharsetUTF8, To: types.CharsetGBK})
+	assert.ErrorContains(t, err, "not valid utf-8")
+	_, err = service.ConvertEncoding(&types.ConvertEncodingRequest{Path: "legacy.txt"})
+	assert.ErrorContains(t, err, "target charset is required")
+	_, err = service.ConvertEncoding(&types.ConvertEncodingRequest{Path: "missing.txt", To: types.CharsetUTF8})
+	assert.ErrorContains(t, err, types.ErrFileNotFound)
+
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "blob.bin"), []byte{0x00, 0x01, 0x02, 0xFF}, 0644))
+	_, err = service.ConvertEncoding(&types.ConvertEncodingRequest{Path: "blob.bin", To: types.CharsetUTF8})
+	assert.ErrorContains(t, err, "could not detect")
+}
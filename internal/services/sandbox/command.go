@@ -226,7 +226,7 @@ func (s *Service) ExecuteCommand(req *types.ExecuteCommandRequest) (*types.Execu
 	currentWorkDir := s.currentWorkDir
 	s.mu.RUnlock()
 
-	return &types.ExecuteCommandResponse{
+	resp := &types.ExecuteCommandResponse{
 		Success:        success,
 		ExitCode:       exitCode,
 		Stdout:         stdout.String(),
@@ -234,7 +234,15 @@ func (s *Service) ExecuteCommand(req *types.ExecuteCommandRequest) (*types.Execu
 		Message:        message,
 		CommandLine:    fullCommandLine,
 		CurrentWorkDir: currentWorkDir,
-	}, nil
+	}
+
+	// 命令的写入无法预先检查,执行后测量用量并标记超额 / Writes by commands cannot be checked in advance, so usage is measured afterwards and flagged
+	if usage := s.measureUsageAfterCommand(fullCommandLine); usage != nil {
+		resp.QuotaUsage = usage
+		resp.QuotaExceeded = usage.Exceeded
+	}
+
+	return resp, nil
 }
 
 // GetCommandBlacklist 获取命令黑名单 / Get command blacklist
@@ -325,9 +333,15 @@ func (s *Service) GetWorkingDirectory(_ *types.GetWorkingDirectoryRequest) (*typ
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	return &types.GetWorkingDirectoryResponse{
+	resp := &types.GetWorkingDirectoryResponse{
 		WorkDir: s.currentWorkDir,
-	}, nil
+		Mount:   types.DefaultMountName,
+	}
+	if validPath, err := s.validatePath(s.currentWorkDir); err == nil {
+		m := s.mountFor(validPath)
+		resp.Mount, resp.ReadOnly = m.name, m.readOnly
+	}
+	return resp, nil
 }
 
 // ChangeDirectory 切换工作目录 / Change directory
@@ -338,13 +352,13 @@ func (s *Service) ChangeDirectory(req *types.ChangeDirectoryRequest) (*types.Cha
 	}
 
 	// 验证路径 / Validate path
-	validPath, err := s.validatePath(req.Path)
+	validPath, err := s.validateAccessPath(req.Path, types.AccessList)
 	if err != nil {
 		return nil, err
 	}
 
 	// 检查目录是否存在 / Check if directory exists
-	info, err := os.Stat(validPath)
+	info, err := s.fsys.Stat(validPath)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
 			return nil, errors.New(types.ErrPathNotFound)
@@ -366,11 +380,8 @@ func (s *Service) ChangeDirectory(req *types.ChangeDirectoryRequest) (*types.Cha
 		return nil, errors.New(types.ErrDirectoryBlacklisted)
 	}
 
-	// 计算相对路径 / Calculate relative path
-	relPath, err := filepath.Rel(s.sandboxDir, validPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get relative path: %w", err)
-	}
+	// 计算相对路径,挂载内带"名称:"前缀 / Calculate relative path, prefixed with "name:" inside a mount
+	relPath := s.displayPath(validPath)
 
 	s.currentWorkDir = relPath
 	s.logger.Info("working directory changed",
@@ -413,6 +424,12 @@ func (s *Service) isDirectoryBlacklisted(dir string) bool {
 	if strings.HasPrefix(dir, sandboxDir) {
 		return false
 	}
+	// 挂载目录同样由配置显式授权 / Mount directories are explicitly granted by the configuration as well
+	for _, m := range s.mounts {
+		if rel, err := filepath.Rel(m.dir, dir); err == nil && isLocalRel(rel) {
+			return false
+		}
+	}
 
 	// 检查自定义黑名单 / Check custom blacklist
 	for _, blacklisted := range s.blacklistDirs {
@@ -435,6 +452,11 @@ func (s *Service) isDirectoryBlacklisted(dir string) bool {
 
 // validateCommandPaths 验证命令参数中的路径是否在沙箱内 / Validate paths in command arguments are within sandbox
 func (s *Service) validateCommandPaths(command string, args []string, workDir string) error {
+	// 只读挂载内只允许只读命令 / Only read-only commands may run inside a read-only mount
+	if m := s.mountFor(workDir); m.readOnly && !isReadOnlyCommand(command) {
+		return fmt.Errorf("%s: only read-only commands may run in mount %s", types.ErrReadOnlyMount, m.name)
+	}
+
 	// 需要验证路径的命令列表 / Commands that need path validation
 	pathSensitiveCommands := map[string]bool{
 		"rm":     true,
@@ -480,11 +502,18 @@ func (s *Service) validateCommandPaths(command string, args []string, workDir st
 		// 清理路径 / Clean path
 		targetPath = filepath.Clean(targetPath)
 
-		// 验证路径是否在沙箱内 / Validate path is within sandbox
+		// 验证路径是否在沙箱或挂载内 / Validate path is within sandbox or a mount
 		sandboxDir := filepath.Clean(s.sandboxDir)
-		if !strings.HasPrefix(targetPath, sandboxDir) {
+		m := s.mountFor(targetPath)
+		if m.name == types.DefaultMountName && !strings.HasPrefix(targetPath, sandboxDir) {
 			return fmt.Errorf("%s: path '%s' is outside sandbox directory", types.ErrSandboxViolation, arg)
 		}
+		if m.readOnly {
+			return fmt.Errorf("%s: path '%s' is in mount %s", types.ErrReadOnlyMount, arg, m.name)
+		}
+		if err := s.checkTreeAccess(targetPath, types.AccessDelete); err != nil {
+			return err
+		}
 
 		// 检查路径是否在黑名单目录中 / Check if path is in blacklisted directory
 		if s.isDirectoryBlacklisted(targetPath) {
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"os/exec"
+	"strings"
 	"time"
 
 	"mcp-toolkit/pkg/types"
@@ -61,8 +62,11 @@ func (s *Service) ExecuteCommandAsync(req *types.ExecuteCommandAsyncRequest) (*t
 // executeTaskAsync 异步执行任务 / Execute task asynchronously
 func (s *Service) executeTaskAsync(task *types.CommandTask, req *types.ExecuteCommandAsyncRequest) {
 	// 更新任务状态为运行中 / Update task status to running
-	s.updateTaskStatus(task.ID, types.TaskStatusRunning)
-	task.StartTime = time.Now()
+	startTime := time.Now()
+	s.updateTask(task.ID, func(task *types.CommandTask) {
+		task.Status = types.TaskStatusRunning
+		task.StartTime = startTime
+	})
 
 	// 获取必要的配置信息 / Get necessary configuration
 	s.mu.RLock()
@@ -135,49 +139,63 @@ func (s *Service) executeTaskAsync(task *types.CommandTask, req *types.ExecuteCo
 	// 执行命令 / Execute command
 	err = cmd.Run()
 
-	task.EndTime = time.Now()
-	task.Stdout = stdout.String()
-	task.Stderr = stderr.String()
+	endTime := time.Now()
+
+	// 与同步执行一样,命令结束后测量用量并标记超额 / As with synchronous execution, usage is measured and flagged once the command finishes
+	usage := s.measureUsageAfterCommand(strings.Join(append([]string{req.Command}, req.Args...), " "))
 
+	exitCode, status, errorMsg := 0, types.TaskStatusCompleted, ""
 	if err != nil {
+		exitCode = -1
 		if exitErr, ok := err.(*exec.ExitError); ok {
-			task.ExitCode = exitErr.ExitCode()
-		} else {
-			task.ExitCode = -1
+			exitCode = exitErr.ExitCode()
 		}
-		task.Error = err.Error()
-		s.updateTaskStatus(task.ID, types.TaskStatusFailed)
-	} else {
-		task.ExitCode = 0
-		s.updateTaskStatus(task.ID, types.TaskStatusCompleted)
+		status, errorMsg = types.TaskStatusFailed, err.Error()
 	}
 
+	// 结果字段在任务锁内一次写入,查询方不会看到一半的结果 / Result fields are written at once under the task lock so readers never see half a result
+	s.updateTask(task.ID, func(task *types.CommandTask) {
+		task.EndTime = endTime
+		task.Stdout = stdout.String()
+		task.Stderr = stderr.String()
+		task.ExitCode = exitCode
+		task.Error = errorMsg
+		if usage != nil {
+			task.QuotaUsage = usage
+			task.QuotaExceeded = usage.Exceeded
+		}
+		task.Status = status
+	})
+
 	// 添加到历史记录 / Add to history
 	entry := createHistoryEntry(
 		req.Command, req.Args, workDir,
-		task.StartTime, task.EndTime,
-		task.ExitCode, task.Status == types.TaskStatusCompleted,
+		startTime, endTime,
+		exitCode, status == types.TaskStatusCompleted,
 		req.User, req.PermissionLevel, req.Environment,
 	)
 	s.addCommandHistory(entry)
 }
 
-// updateTaskStatus 更新任务状态 / Update task status
-func (s *Service) updateTaskStatus(taskID string, status types.CommandTaskStatus) {
+// updateTask 在任务锁内修改任务,任务的字段只能这样写入 / Modify a task under the task lock, the only way its fields may be written
+func (s *Service) updateTask(taskID string, fn func(task *types.CommandTask)) {
 	s.taskMu.Lock()
 	defer s.taskMu.Unlock()
 
 	if task, exists := s.commandTasks[taskID]; exists {
-		task.Status = status
+		fn(task)
 	}
 }
 
 // failTask 标记任务失败 / Mark task as failed
 func (s *Service) failTask(task *types.CommandTask, errorMsg string) {
-	task.EndTime = time.Now()
-	task.Error = errorMsg
-	task.ExitCode = -1
-	s.updateTaskStatus(task.ID, types.TaskStatusFailed)
+	endTime := time.Now()
+	s.updateTask(task.ID, func(task *types.CommandTask) {
+		task.EndTime = endTime
+		task.Error = errorMsg
+		task.ExitCode = -1
+		task.Status = types.TaskStatusFailed
+	})
 
 	s.logger.Error("async command task failed",
 		zap.String("task_id", task.ID),
@@ -199,8 +217,10 @@ func (s *Service) GetCommandTask(req *types.GetCommandTaskRequest) (*types.GetCo
 		return nil, fmt.Errorf("task not found: %s", req.TaskID)
 	}
 
+	// 返回锁内取得的副本,执行中的任务之后的修改不会影响调用方 / Return a copy taken under the lock so later updates of a running task do not reach the caller
+	snapshot := *task
 	return &types.GetCommandTaskResponse{
-		Task: task,
+		Task: &snapshot,
 	}, nil
 }
 
@@ -15,12 +15,78 @@ const (
 	// MaxFileSize 最大文件大小(100MB) / Maximum file size (100MB)
 	MaxFileSize int64 = 100 * 1024 * 1024
 
+	// DefaultReadSize 单次读取默认返回的最大字节数(1MB) / Default maximum bytes returned by a single read (1MB)
+	DefaultReadSize int64 = 1024 * 1024
+
+	// MaxReadSize 单次读取允许返回的最大字节数(10MB) / Maximum bytes a single read may return (10MB)
+	MaxReadSize int64 = 10 * 1024 * 1024
+
+	// BinaryDetectSize 二进制检测采样字节数 / Number of bytes sampled for binary detection
+	BinaryDetectSize = 8000
+
 	// MaxPathLength 最大路径长度 / Maximum path length
 	MaxPathLength = 4096
 
 	// MaxBatchDeleteCount 最大批量删除数量 / Maximum batch delete count
 	MaxBatchDeleteCount = 1000
 
+	// MaxHashFiles 单次哈希请求允许计算的最大文件数 / Maximum number of files hashed by a single request
+	MaxHashFiles = 10000
+
+	// MaxComparePairs 单次比较请求允许的最大文件对数量 / Maximum number of file pairs in a single compare request
+	MaxComparePairs = 1000
+
+	// MaxArchiveSize 归档未压缩内容的最大总大小 / Maximum total uncompressed size of an archive
+	MaxArchiveSize = 10 * MaxFileSize
+
+	// MaxArchiveEntries 归档允许的最大条目数 / Maximum number of entries in an archive
+	MaxArchiveEntries = 100000
+
+	// MaxSnapshotFiles 单个快照允许的最大文件数 / Maximum number of files in a single snapshot
+	MaxSnapshotFiles = 100000
+
+	// MaxEditCount 单次编辑请求允许的最大编辑数量 / Maximum number of edits in a single edit request
+	MaxEditCount = 100
+
+	// MaxDiffContext 差异允许的最大上下文行数 / Maximum context lines of a diff
+	MaxDiffContext = 1000
+
+	// DefaultSearchResults 内容搜索默认返回的最大匹配行数 / Default maximum matching lines returned by a content search
+	DefaultSearchResults = 100
+
+	// MaxSearchResults 内容搜索允许返回的最大匹配行数 / Maximum matching lines a content search may return
+	MaxSearchResults = 1000
+
+	// DefaultListLimit 列出目录默认每页条目数 / Default page size of a directory listing
+	DefaultListLimit = 1000
+
+	// MaxListLimit 列出目录允许的最大每页条目数 / Maximum page size of a directory listing
+	MaxListLimit = 10000
+
+	// DefaultFileSearchResults 文件搜索默认返回的最大条目数 / Default maximum entries returned by a file search
+	DefaultFileSearchResults = 1000
+
+	// MaxFileSearchResults 文件搜索允许返回的最大条目数 / Maximum entries a file search may return
+	MaxFileSearchResults = 10000
+
+	// DefaultTreeDepth 目录树默认展示深度 / Default depth of a directory tree
+	DefaultTreeDepth = 3
+
+	// MaxTreeDepth 目录树允许的最大展示深度 / Maximum depth of a directory tree
+	MaxTreeDepth = 20
+
+	// DefaultTreeEntries 目录树默认返回的最大条目数 / Default maximum entries of a directory tree
+	DefaultTreeEntries = 500
+
+	// MaxTreeEntries 目录树允许返回的最大条目数 / Maximum entries of a directory tree
+	MaxTreeEntries = 5000
+
+	// MaxContextLines 内容搜索允许的最大上下文行数 / Maximum context lines of a content search
+	MaxContextLines = 20
+
+	// MaxMatchLineLength 搜索结果中单行内容的最大字节数 / Maximum bytes of a single line in search results
+	MaxMatchLineLength = 1000
+
 	// DefaultCommandTimeout 默认命令超时时间(秒) / Default command timeout in seconds
 	DefaultCommandTimeout = 300
 
@@ -63,6 +129,9 @@ var (
 		"/System", "/Library", "/Applications",
 	}
 
+	// DefaultTreeExcludes 目录树默认排除的目录和文件 / Directories and files excluded from directory trees by default
+	DefaultTreeExcludes = []string{".git", TrashDirName, SnapshotDirName, ".svn", ".hg", "node_modules", "__pycache__", ".venv", ".DS_Store"}
+
 	// SystemDirectories 系统目录列表(根据操作系统) / System directories list (based on OS)
 	SystemDirectories = getSystemDirectories()
 )
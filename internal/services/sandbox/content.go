@@ -0,0 +1,87 @@
+// Copyright 2024 MCP Toolkit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sandbox
+
+import (
+	"cmp"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"mcp-toolkit/pkg/types"
+	"mcp-toolkit/pkg/utils/json"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// fileURIScheme 沙箱文件资源的URI方案 / URI scheme of sandbox file resources
+const fileURIScheme = "file"
+
+// detectMimeType 根据内容检测MIME类型,无法识别时按扩展名推断
+// Detect the MIME type from the content, falling back to the extension when it is not recognised
+func detectMimeType(name string, sample []byte) string {
+	detected := http.DetectContentType(sample)
+	if detected == "application/octet-stream" {
+		if byExt := mime.TypeByExtension(filepath.Ext(name)); byExt != "" {
+			return byExt
+		}
+	}
+	return detected
+}
+
+// fileResourceURI 返回沙箱显示路径对应的资源URI / Return the resource URI of a sandbox display path
+func fileResourceURI(display string) string {
+	u := url.URL{Scheme: fileURIScheme, Path: "/" + strings.TrimPrefix(filepath.ToSlash(display), "/")}
+	return u.String()
+}
+
+// readFileResultContent 按内容类型构建read_file的MCP内容:文本为纯文本,完整的图片为图片内容,其他二进制为嵌入资源
+// 最后附加不含内容本身的响应元数据(行号、偏移、是否截断等)
+// Build the MCP content of read_file by content type: text as plain text, whole images as image content and other binaries as embedded resources.
+// The response metadata without the content itself (lines, offsets, truncation) is appended last
+func readFileResultContent(display string, resp *types.ReadFileResponse) ([]mcp.Content, error) {
+	var content mcp.Content
+	if resp.Encoding == types.ContentEncodingBase64 {
+		data, err := base64.StdEncoding.DecodeString(resp.Content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode file content: %w", err)
+		}
+		mimeType, _, _ := mime.ParseMediaType(resp.MimeType)
+		if strings.HasPrefix(mimeType, "image/") && resp.Offset == 0 && !resp.Truncated {
+			content = &mcp.ImageContent{Data: data, MIMEType: mimeType}
+		} else {
+			// 部分读取的图片无法解码,与其他二进制一样作为资源返回 / A partially read image cannot be decoded and is returned as a resource like other binaries
+			content = &mcp.EmbeddedResource{Resource: &mcp.ResourceContents{
+				URI:      fileResourceURI(display),
+				MIMEType: cmp.Or(mimeType, "application/octet-stream"),
+				Blob:     data,
+			}}
+		}
+	} else {
+		content = &mcp.TextContent{Text: resp.Content}
+	}
+
+	metadata := *resp
+	metadata.Content = ""
+	metadataJSON, err := json.MarshalToString(&metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal file metadata: %w", err)
+	}
+	return []mcp.Content{content, &mcp.TextContent{Text: metadataJSON}}, nil
+}
@@ -0,0 +1,658 @@
+// Copyright 2024 MCP Toolkit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sandbox
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"mcp-toolkit/pkg/types"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// MaxDataFileSize 可查询和修改的数据文件的最大大小(10MB) / Maximum size of a data file that can be queried or updated (10MB)
+	MaxDataFileSize = 10 * 1024 * 1024
+
+	// DefaultDataQueryLimit 数据查询默认返回的最大匹配数 / Default maximum number of matches a data query returns
+	DefaultDataQueryLimit = 100
+
+	// MaxDataQueryLimit 数据查询允许返回的最大匹配数 / Maximum number of matches a data query may return
+	MaxDataQueryLimit = 10000
+
+	// maxDataDepth 数据的最大嵌套深度 / Maximum nesting depth of data
+	maxDataDepth = 1000
+)
+
+// errDataLayout 修改无法在保留原布局的前提下完成,需要重新生成文件
+// The change cannot be made while keeping the original layout and the file has to be regenerated
+var errDataLayout = errors.New("the change cannot keep the original layout")
+
+// dataObject 保留键顺序的对象 / Object that keeps the order of its keys
+type dataObject struct {
+	keys   []string
+	values map[string]any
+}
+
+// dataOptions 数据文件的解析选项 / Parsing options of a data file
+type dataOptions struct {
+	format    string // 文件格式 / File format
+	delimiter rune   // CSV分隔符,0表示自动检测 / CSV delimiter, 0 to detect it
+	noHeader  bool   // CSV没有表头行 / The CSV has no header row
+}
+
+// dataOp 一次已定位的修改 / A located change
+type dataOp struct {
+	loc    []any // 位置,下标均为非负数 / Location with non-negative indexes
+	value  any   // 要设置的值 / Value to set
+	delete bool  // 删除而不是设置 / Delete instead of set
+}
+
+// dataDocument 解析后的数据文件 / A parsed data file
+type dataDocument interface {
+	// tree 返回规范化的数据树 / Return the normalized data tree
+	tree() any
+	// apply 直接修改原文以保留布局,无法做到时返回errDataLayout / Change the original text in place to keep its layout, returning errDataLayout when that is not possible
+	apply(op dataOp) error
+	// bytes 返回当前内容 / Return the current content
+	bytes() []byte
+	// rebuild 根据修改后的数据树重新生成整个文件 / Regenerate the whole file from the changed tree
+	rebuild(ops []dataOp, expected any) ([]byte, error)
+}
+
+// QueryData 查询结构化数据文件中的值 / Query values in a structured data file
+func (s *Service) QueryData(req *types.QueryDataRequest) (*types.QueryDataResponse, error) {
+	// 参数验证 / Parameter validation
+	if err := validateQueryDataRequest(req); err != nil {
+		return nil, err
+	}
+
+	validPath, err := s.validateAccessPath(req.Path, types.AccessRead)
+	if err != nil {
+		return nil, err
+	}
+	opts, err := resolveDataOptions(req.Path, req.Format, req.Delimiter, req.NoHeader)
+	if err != nil {
+		return nil, err
+	}
+	query, err := parseDataPath(req.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	defer s.locks.lock(readLock(validPath))()
+
+	data, _, err := s.readDataFile(validPath)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := parseDataDocument(bytes.TrimPrefix(data, bomUTF8), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := query.eval(doc.tree())
+	limit := req.Limit
+	if limit <= 0 {
+		limit = DefaultDataQueryLimit
+	}
+	resp := &types.QueryDataResponse{
+		Success: true,
+		Message: types.MsgDataQueried,
+		Format:  opts.format,
+		Count:   len(nodes),
+		Matches: make([]types.DataMatch, 0, min(len(nodes), limit)),
+	}
+	for i, node := range nodes {
+		if i == limit {
+			resp.Truncated = true
+			break
+		}
+		value, err := marshalDataJSON(node.value, jsonCompact)
+		if err != nil {
+			return nil, err
+		}
+		resp.Matches = append(resp.Matches, types.DataMatch{Path: formatDataPath(node.loc), Value: value})
+	}
+	return resp, nil
+}
+
+// UpdateData 设置或删除结构化数据文件中的值,尽量保留原有格式 / Set or delete values in a structured data file, keeping its formatting as far as possible
+func (s *Service) UpdateData(req *types.UpdateDataRequest) (*types.UpdateDataResponse, error) {
+	// 参数验证 / Parameter validation
+	if err := validateUpdateDataRequest(req); err != nil {
+		return nil, err
+	}
+
+	// 差异会暴露原内容,因此需要读取权限;预览不需要写入权限 / The diff exposes the original content, so read access is required; previews need no write access
+	validPath, err := s.validateAccessPath(req.Path, types.AccessRead)
+	if err != nil {
+		return nil, err
+	}
+	if !req.DryRun {
+		if _, err = s.validateWritePath(req.Path); err != nil {
+			return nil, err
+		}
+	}
+	opts, err := resolveDataOptions(req.Path, req.Format, req.Delimiter, req.NoHeader)
+	if err != nil {
+		return nil, err
+	}
+	query, err := parseDataPath(req.Query)
+	if err != nil {
+		return nil, err
+	}
+	var value any
+	if req.Op == types.DataOpSet {
+		if value, err = parseJSONValue([]byte(req.Value)); err != nil {
+			return nil, fmt.Errorf("invalid value: %w", err)
+		}
+	}
+
+	lock := writeLock(validPath)
+	if req.DryRun {
+		lock = readLock(validPath)
+	}
+	defer s.locks.lock(lock)()
+
+	data, info, err := s.readDataFile(validPath)
+	if err != nil {
+		return nil, err
+	}
+	content := bytes.TrimPrefix(data, bomUTF8)
+	doc, err := parseDataDocument(content, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	expected, ops, err := planDataUpdate(doc.tree(), query, req.Op, value)
+	if err != nil {
+		return nil, err
+	}
+	updated, reformatted, err := applyDataUpdate(content, opts, doc, ops, expected)
+	if err != nil {
+		return nil, err
+	}
+	if len(content) < len(data) {
+		updated = append(bytes.Clone(bomUTF8), updated...)
+	}
+	if int64(len(updated)) > MaxDataFileSize {
+		return nil, fmt.Errorf("content size exceeds maximum allowed size of %d bytes", MaxDataFileSize)
+	}
+
+	resp := &types.UpdateDataResponse{
+		Success:     true,
+		Format:      opts.format,
+		Updated:     len(ops),
+		Paths:       make([]string, 0, len(ops)),
+		Reformatted: reformatted,
+		DryRun:      req.DryRun,
+	}
+	for _, op := range ops {
+		resp.Paths = append(resp.Paths, formatDataPath(op.loc))
+	}
+	resp.Diff, _ = unifiedDiff("a/"+req.Path, "b/"+req.Path, string(data), string(updated), DefaultDiffContext)
+
+	changed := !bytes.Equal(data, updated)
+	if !req.DryRun && changed {
+		if err = s.checkWriteQuota(validPath, int64(len(updated))); err != nil {
+			return nil, err
+		}
+		if err = writeFileAtomic(s.fsys, validPath, updated, info.Mode().Perm()); err != nil {
+			return nil, err
+		}
+		s.logger.Info("data file updated", zap.String("path", validPath), zap.String("op", req.Op), zap.Int("values", len(ops)))
+	}
+	if !req.DryRun {
+		result, err := fileChangeResponse(s.fsys, validPath, "")
+		if err != nil {
+			return nil, err
+		}
+		resp.Size, resp.Version, resp.ModTime = result.Size, result.Version, result.ModTime
+		resp.SHA256 = contentSHA256(updated)
+	}
+
+	switch {
+	case req.DryRun:
+		resp.Message = "dry run completed, file not modified"
+	case !changed:
+		resp.Message = "no changes were made"
+	default:
+		resp.Message = types.MsgDataUpdated
+	}
+	return resp, nil
+}
+
+// readDataFile 读取数据文件 / Read a data file
+func (s *Service) readDataFile(path string) ([]byte, fs.FileInfo, error) {
+	info, err := s.fsys.Stat(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil, errors.New(types.ErrFileNotFound)
+		}
+		return nil, nil, fmt.Errorf("failed to stat file: %w", err)
+	}
+	if info.IsDir() {
+		return nil, nil, errors.New(types.ErrIsDirectory)
+	}
+	if info.Size() > MaxDataFileSize {
+		return nil, nil, fmt.Errorf("data file size exceeds maximum allowed size of %d bytes", MaxDataFileSize)
+	}
+	data, err := s.fsys.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	return data, info, nil
+}
+
+// resolveDataOptions 根据请求和扩展名确定格式和CSV分隔符 / Determine the format and the CSV delimiter from the request and the extension
+func resolveDataOptions(path, format, delimiter string, noHeader bool) (dataOptions, error) {
+	opts := dataOptions{format: format, noHeader: noHeader}
+	ext := strings.ToLower(filepath.Ext(path))
+	if opts.format == "" {
+		switch ext {
+		case ".json":
+			opts.format = types.DataFormatJSON
+		case ".yaml", ".yml":
+			opts.format = types.DataFormatYAML
+		case ".toml":
+			opts.format = types.DataFormatTOML
+		case ".csv", ".tsv":
+			opts.format = types.DataFormatCSV
+		default:
+			return opts, fmt.Errorf("%s: cannot tell the format from the file extension, set format", types.ErrUnsupportedDataFormat)
+		}
+	}
+	if delimiter != "" {
+		r, err := parseDataDelimiter(delimiter)
+		if err != nil {
+			return opts, err
+		}
+		opts.delimiter = r
+	} else if ext == ".tsv" {
+		opts.delimiter = '\t'
+	}
+	return opts, nil
+}
+
+// parseDataDelimiter 解析CSV分隔符,接受单个字符以及"tab"和"\t" / Parse a CSV delimiter, accepting a single character as well as "tab" and "\t"
+func parseDataDelimiter(delimiter string) (rune, error) {
+	if delimiter == "tab" || delimiter == `\t` {
+		return '\t', nil
+	}
+	r, size := utf8.DecodeRuneInString(delimiter)
+	if size != len(delimiter) || r == utf8.RuneError || r == '"' || r == '\r' || r == '\n' {
+		return 0, fmt.Errorf("invalid delimiter %q: must be a single character other than a quote or line break", delimiter)
+	}
+	return r, nil
+}
+
+// parseDataDocument 按格式解析数据文件 / Parse a data file in the given format
+func parseDataDocument(data []byte, opts dataOptions) (dataDocument, error) {
+	if !utf8.Valid(data) {
+		return nil, fmt.Errorf("failed to parse %s: file is not valid UTF-8 text", opts.format)
+	}
+	var doc dataDocument
+	var err error
+	switch opts.format {
+	case types.DataFormatJSON:
+		doc, err = parseJSONDocument(data)
+	case types.DataFormatYAML:
+		doc, err = parseYAMLDocument(data)
+	case types.DataFormatTOML:
+		doc, err = parseTOMLDocument(data)
+	default:
+		doc, err = parseCSVDocument(data, opts)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", opts.format, err)
+	}
+	return doc, nil
+}
+
+// planDataUpdate 在数据树的副本上应用修改,返回修改后的树和已定位的修改
+// Apply the change to a copy of the tree, returning the changed tree and the located changes
+func planDataUpdate(root any, query *dataPath, op string, value any) (any, []dataOp, error) {
+	var locs [][]any
+	for _, node := range query.eval(root) {
+		locs = append(locs, node.loc)
+	}
+	if len(locs) == 0 {
+		// 仅由成员名和下标构成的路径可以创建新值 / Paths made of member names and indexes only can create new values
+		loc, ok := query.definite()
+		if op != types.DataOpSet || !ok {
+			return nil, nil, errors.New(types.ErrDataNoMatch)
+		}
+		locs = [][]any{loc}
+	}
+	locs = outermostLocs(locs)
+	if op == types.DataOpDelete {
+		// 从后往前删除,使前面的下标保持有效 / Delete back to front so earlier indexes stay valid
+		sort.SliceStable(locs, func(i, j int) bool { return compareLocs(locs[i], locs[j]) > 0 })
+	}
+
+	expected := cloneData(root)
+	ops := make([]dataOp, 0, len(locs))
+	for _, loc := range locs {
+		var err error
+		if op == types.DataOpDelete {
+			if len(loc) == 0 {
+				return nil, nil, errors.New("cannot delete the document root")
+			}
+			expected, err = deleteData(expected, loc)
+			ops = append(ops, dataOp{loc: loc, delete: true})
+		} else {
+			expected, loc, err = setData(expected, loc, cloneData(value))
+			ops = append(ops, dataOp{loc: loc, value: value})
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return expected, ops, nil
+}
+
+// applyDataUpdate 应用修改并核对结果,原位修改失败或结果不符时重新生成整个文件
+// Apply the changes and check the result, regenerating the whole file when editing in place fails or gives a different result
+func applyDataUpdate(content []byte, opts dataOptions, doc dataDocument, ops []dataOp, expected any) ([]byte, bool, error) {
+	// CSV总是整体重写,除分隔符和换行符外没有需要保留的布局 / CSV is always rewritten as a whole; it has no layout to keep beyond the delimiter and line endings
+	if opts.format == types.DataFormatCSV {
+		out, err := doc.rebuild(ops, expected)
+		return out, false, err
+	}
+
+	var err error
+	for _, op := range ops {
+		if err = doc.apply(op); err != nil {
+			break
+		}
+	}
+	if err == nil {
+		out := doc.bytes()
+		if check, err := parseDataDocument(out, opts); err == nil && dataEqual(check.tree(), expected) {
+			return out, false, nil
+		}
+	} else if !errors.Is(err, errDataLayout) {
+		return nil, false, err
+	}
+
+	fresh, err := parseDataDocument(content, opts)
+	if err != nil {
+		return nil, false, err
+	}
+	out, err := fresh.rebuild(ops, expected)
+	if err != nil {
+		return nil, false, err
+	}
+	if check, err := parseDataDocument(out, opts); err != nil || !dataEqual(check.tree(), expected) {
+		return nil, false, fmt.Errorf("failed to update %s: the result cannot be represented in this format", opts.format)
+	}
+	return out, true, nil
+}
+
+// outermostLocs 去掉重复的位置和位于其他位置之下的位置 / Drop duplicate locations and locations nested under another one
+func outermostLocs(locs [][]any) [][]any {
+	matched := make(map[string]bool, len(locs))
+	for _, loc := range locs {
+		matched[formatDataPath(loc)] = true
+	}
+	seen := make(map[string]bool, len(locs))
+	result := make([][]any, 0, len(locs))
+outer:
+	for _, loc := range locs {
+		key := formatDataPath(loc)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		for i := range loc {
+			if matched[formatDataPath(loc[:i])] {
+				continue outer
+			}
+		}
+		result = append(result, loc)
+	}
+	return result
+}
+
+// compareLocs 按文档顺序比较两个位置 / Compare two locations in document order
+func compareLocs(a, b []any) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		ai, aInt := a[i].(int)
+		bi, bInt := b[i].(int)
+		switch {
+		case aInt && bInt && ai != bi:
+			if ai < bi {
+				return -1
+			}
+			return 1
+		case !aInt && !bInt:
+			if c := strings.Compare(a[i].(string), b[i].(string)); c != 0 {
+				return c
+			}
+		case aInt != bInt:
+			if aInt {
+				return -1
+			}
+			return 1
+		}
+	}
+	return len(a) - len(b)
+}
+
+// getData 返回位置上的值 / Return the value at a location
+func getData(root any, loc []any) (any, bool) {
+	cur := root
+	for _, key := range loc {
+		switch k := key.(type) {
+		case string:
+			obj, ok := cur.(*dataObject)
+			if !ok {
+				return nil, false
+			}
+			if cur, ok = obj.get(k); !ok {
+				return nil, false
+			}
+		case int:
+			arr, ok := cur.([]any)
+			if !ok || k < 0 || k >= len(arr) {
+				return nil, false
+			}
+			cur = arr[k]
+		}
+	}
+	return cur, true
+}
+
+// setData 设置位置上的值,按需创建缺少的对象和数组;下标等于数组长度时追加,返回修改后的根和解析后的位置
+// Set the value at a location, creating missing objects and arrays as needed; an index equal to the array length appends. Returns the new root and the resolved location
+func setData(root any, loc []any, value any) (any, []any, error) {
+	resolved := make([]any, len(loc))
+	copy(resolved, loc)
+
+	var set func(cur any, i int) (any, error)
+	set = func(cur any, i int) (any, error) {
+		if i == len(loc) {
+			return value, nil
+		}
+		switch k := loc[i].(type) {
+		case string:
+			obj, ok := cur.(*dataObject)
+			if !ok {
+				if cur != nil {
+					return nil, fmt.Errorf("cannot set %s: %s is not an object", formatDataPath(loc), formatDataPath(loc[:i]))
+				}
+				obj = newDataObject()
+			}
+			child, _ := obj.get(k)
+			child, err := set(child, i+1)
+			if err != nil {
+				return nil, err
+			}
+			obj.set(k, child)
+			return obj, nil
+		default:
+			index := k.(int)
+			arr, ok := cur.([]any)
+			if !ok && cur != nil {
+				return nil, fmt.Errorf("cannot set %s: %s is not an array", formatDataPath(loc), formatDataPath(loc[:i]))
+			}
+			if index < 0 {
+				index += len(arr)
+			}
+			if index < 0 || index > len(arr) {
+				return nil, fmt.Errorf("cannot set %s: index out of range, %s has %d elements", formatDataPath(loc), formatDataPath(loc[:i]), len(arr))
+			}
+			resolved[i] = index
+			if index == len(arr) {
+				arr = append(arr, nil)
+			}
+			child, err := set(arr[index], i+1)
+			if err != nil {
+				return nil, err
+			}
+			arr[index] = child
+			return arr, nil
+		}
+	}
+
+	root, err := set(root, 0)
+	return root, resolved, err
+}
+
+// deleteData 删除位置上的值 / Delete the value at a location
+func deleteData(root any, loc []any) (any, error) {
+	parentLoc, key := loc[:len(loc)-1], loc[len(loc)-1]
+	parent, ok := getData(root, parentLoc)
+	if !ok {
+		return nil, fmt.Errorf("cannot delete %s: %s", formatDataPath(loc), types.ErrDataNoMatch)
+	}
+	switch p := parent.(type) {
+	case *dataObject:
+		if k, ok := key.(string); ok && p.remove(k) {
+			return root, nil
+		}
+	case []any:
+		if i, ok := key.(int); ok && i >= 0 && i < len(p) {
+			arr := append(p[:i:i], p[i+1:]...)
+			if len(parentLoc) == 0 {
+				return arr, nil
+			}
+			root, _, err := setData(root, parentLoc, arr)
+			return root, err
+		}
+	}
+	return nil, fmt.Errorf("cannot delete %s: %s", formatDataPath(loc), types.ErrDataNoMatch)
+}
+
+// cloneData 深拷贝数据树 / Deep copy a data tree
+func cloneData(v any) any {
+	switch t := v.(type) {
+	case *dataObject:
+		obj := &dataObject{keys: append([]string(nil), t.keys...), values: make(map[string]any, len(t.values))}
+		for key, value := range t.values {
+			obj.values[key] = cloneData(value)
+		}
+		return obj
+	case []any:
+		arr := make([]any, len(t))
+		for i, item := range t {
+			arr[i] = cloneData(item)
+		}
+		return arr
+	}
+	return v
+}
+
+// normalizeData 将解码得到的值转换为规范化的数据树,映射按键排序 / Convert decoded values into a normalized tree, sorting the keys of maps
+func normalizeData(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(t))
+		for key := range t {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		obj := &dataObject{keys: keys, values: make(map[string]any, len(t))}
+		for key, value := range t {
+			obj.values[key] = normalizeData(value)
+		}
+		return obj
+	case []any:
+		arr := make([]any, len(t))
+		for i, item := range t {
+			arr[i] = normalizeData(item)
+		}
+		return arr
+	case int:
+		return int64(t)
+	case int32:
+		return int64(t)
+	case uint64:
+		if t > 1<<63-1 {
+			return float64(t)
+		}
+		return int64(t)
+	case float32:
+		return float64(t)
+	}
+	return v
+}
+
+// newDataObject 创建空对象 / Create an empty object
+func newDataObject() *dataObject {
+	return &dataObject{values: make(map[string]any)}
+}
+
+// get 返回成员的值 / Return the value of a member
+func (o *dataObject) get(key string) (any, bool) {
+	v, ok := o.values[key]
+	return v, ok
+}
+
+// set 设置成员,已有的成员保持原位置 / Set a member; existing members keep their position
+func (o *dataObject) set(key string, value any) {
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = value
+}
+
+// remove 删除成员 / Remove a member
+func (o *dataObject) remove(key string) bool {
+	if _, ok := o.values[key]; !ok {
+		return false
+	}
+	delete(o.values, key)
+	for i, k := range o.keys {
+		if k == key {
+			o.keys = append(o.keys[:i], o.keys[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// MarshalJSON 按键顺序输出对象 / Marshal the object in key order
+func (o *dataObject) MarshalJSON() ([]byte, error) {
+	return marshalDataJSON(o, jsonCompact)
+}
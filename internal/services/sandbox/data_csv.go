@@ -0,0 +1,219 @@
+// Copyright 2024 MCP Toolkit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sandbox
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// csvDelimiters 自动检测时考虑的分隔符 / Delimiters considered by detection
+const csvDelimiters = ",;\t|"
+
+// csvDocument CSV文件,有表头时每行是以列名为键的对象,否则是数组
+// CSV file; with a header every row is an object keyed by column name, otherwise an array
+type csvDocument struct {
+	value     any
+	header    []string // 列名 / Column names
+	delimiter rune     // 分隔符 / Delimiter
+	crlf      bool     // 使用CRLF换行 / Uses CRLF line endings
+	noHeader  bool     // 没有表头行 / Has no header row
+}
+
+// parseCSVDocument 解析CSV文件 / Parse a CSV file
+func parseCSVDocument(data []byte, opts dataOptions) (*csvDocument, error) {
+	d := &csvDocument{delimiter: opts.delimiter, noHeader: opts.noHeader, crlf: bytes.Contains(data, []byte("\r\n"))}
+	if d.delimiter == 0 {
+		d.delimiter = sniffCSVDelimiter(data)
+	}
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = d.delimiter
+	if d.noHeader {
+		reader.FieldsPerRecord = -1
+	}
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]any, 0, len(records))
+	if d.noHeader {
+		for _, record := range records {
+			row := make([]any, len(record))
+			for i, field := range record {
+				row[i] = field
+			}
+			rows = append(rows, row)
+		}
+		d.value = rows
+		return d, nil
+	}
+
+	if len(records) > 0 {
+		d.header = records[0]
+		seen := make(map[string]bool, len(d.header))
+		for _, name := range d.header {
+			if seen[name] {
+				return nil, fmt.Errorf("duplicate column %q in the header, set no_header to read rows as arrays", name)
+			}
+			seen[name] = true
+		}
+		records = records[1:]
+	}
+	for _, record := range records {
+		row := newDataObject()
+		for i, name := range d.header {
+			row.set(name, record[i])
+		}
+		rows = append(rows, row)
+	}
+	d.value = rows
+	return d, nil
+}
+
+// sniffCSVDelimiter 根据第一行引号外出现最多的字符检测分隔符 / Detect the delimiter as the candidate occurring most often outside quotes on the first line
+func sniffCSVDelimiter(data []byte) rune {
+	counts := make(map[rune]int)
+	quoted := false
+	for _, r := range string(data) {
+		if r == '"' {
+			quoted = !quoted
+		} else if !quoted && (r == '\n' || r == '\r') {
+			break
+		} else if !quoted && strings.ContainsRune(csvDelimiters, r) {
+			counts[r]++
+		}
+	}
+	best := ','
+	for _, r := range csvDelimiters {
+		if counts[r] > counts[best] {
+			best = r
+		}
+	}
+	return best
+}
+
+func (d *csvDocument) tree() any {
+	return d.value
+}
+
+// apply CSV总是整体重写 / CSV is always rewritten as a whole
+func (d *csvDocument) apply(dataOp) error {
+	return errDataLayout
+}
+
+func (d *csvDocument) bytes() []byte {
+	return nil
+}
+
+// rebuild 写出所有行;新出现的键追加为列,所有行都删除的列被移除,缺少的字段为空
+// Write out all rows; new keys are appended as columns, columns deleted from every row are removed and missing fields are empty
+func (d *csvDocument) rebuild(_ []dataOp, expected any) ([]byte, error) {
+	rows, ok := expected.([]any)
+	if !ok {
+		return nil, fmt.Errorf("the root of CSV data must be an array of rows")
+	}
+
+	var records [][]string
+	if d.noHeader {
+		for i, row := range rows {
+			fields, ok := row.([]any)
+			if !ok {
+				return nil, fmt.Errorf("row %d must be an array of fields", i)
+			}
+			record, err := csvRecord(fields, i)
+			if err != nil {
+				return nil, err
+			}
+			records = append(records, record)
+		}
+	} else {
+		columns := append([]string(nil), d.header...)
+		known := make(map[string]bool, len(columns))
+		for _, name := range columns {
+			known[name] = true
+		}
+		used := make(map[string]bool, len(columns))
+		for i, row := range rows {
+			obj, ok := row.(*dataObject)
+			if !ok {
+				return nil, fmt.Errorf("row %d must be an object keyed by column name", i)
+			}
+			for _, key := range obj.keys {
+				used[key] = true
+				if !known[key] {
+					known[key] = true
+					columns = append(columns, key)
+				}
+			}
+		}
+		if len(rows) > 0 {
+			kept := columns[:0]
+			for _, name := range columns {
+				if used[name] {
+					kept = append(kept, name)
+				}
+			}
+			columns = kept
+		}
+
+		records = append(records, columns)
+		for i, row := range rows {
+			obj := row.(*dataObject)
+			fields := make([]any, len(columns))
+			for j, name := range columns {
+				fields[j], _ = obj.get(name)
+			}
+			record, err := csvRecord(fields, i)
+			if err != nil {
+				return nil, err
+			}
+			records = append(records, record)
+		}
+	}
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	writer.Comma = d.delimiter
+	writer.UseCRLF = d.crlf
+	if err := writer.WriteAll(records); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// csvRecord 将字段转换为字符串,只允许标量 / Convert fields to strings, allowing scalars only
+func csvRecord(fields []any, row int) ([]string, error) {
+	record := make([]string, len(fields))
+	for i, field := range fields {
+		switch v := field.(type) {
+		case nil:
+		case string:
+			record[i] = v
+		case bool:
+			record[i] = strconv.FormatBool(v)
+		case int64:
+			record[i] = strconv.FormatInt(v, 10)
+		case float64:
+			record[i] = strconv.FormatFloat(v, 'g', -1, 64)
+		default:
+			return nil, fmt.Errorf("row %d: CSV fields can only hold scalar values", row)
+		}
+	}
+	return record, nil
+}
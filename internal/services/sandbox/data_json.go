@@ -0,0 +1,601 @@
+// Copyright 2024 MCP Toolkit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sandbox
+
+import (
+	"bytes"
+	"encoding"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"mcp-toolkit/pkg/utils/json"
+)
+
+// jsonStyle JSON输出风格 / JSON output style
+type jsonStyle struct {
+	indent  string // 缩进单位,空串表示单行输出 / Indent unit, empty for single-line output
+	colon   string // 成员名后的分隔符 / Separator after member names
+	comma   string // 单行输出时元素之间的分隔符 / Separator between elements in single-line output
+	newline string // 换行符 / Line terminator
+}
+
+// jsonCompact 紧凑的单行输出 / Compact single-line output
+var jsonCompact = jsonStyle{colon: ":", comma: ",", newline: "\n"}
+
+// jsonSpan JSON值在原文中的范围 / Range of a JSON value in the original text
+type jsonSpan struct {
+	start, end int         // 值的起止位置 / Start and end offsets of the value
+	object     bool        // 是否为对象 / Whether the value is an object
+	array      bool        // 是否为数组 / Whether the value is an array
+	keys       []string    // 成员名 / Member names
+	keyStarts  []int       // 成员名的起始位置 / Start offsets of the member names
+	items      []*jsonSpan // 成员值或数组元素 / Member values or array elements
+}
+
+// jsonParser 记录位置的JSON解析器 / JSON parser that records positions
+type jsonParser struct {
+	data  []byte
+	pos   int
+	depth int
+	colon string // 原文使用的成员名分隔符 / Member separator used by the original text
+	comma string // 原文单行容器使用的元素分隔符 / Element separator used by single-line containers in the original text
+}
+
+// jsonDocument 可原位修改的JSON文件 / JSON file that can be changed in place
+type jsonDocument struct {
+	data  []byte
+	root  *jsonSpan
+	value any
+	style jsonStyle
+}
+
+// parseJSONValue 解析JSON值,对象保留键顺序 / Parse a JSON value, keeping the key order of objects
+func parseJSONValue(data []byte) (any, error) {
+	doc, err := parseJSONDocument(data)
+	if err != nil {
+		return nil, err
+	}
+	return doc.value, nil
+}
+
+// parseJSONDocument 解析JSON文件并检测其缩进风格 / Parse a JSON file and detect its indentation style
+func parseJSONDocument(data []byte) (*jsonDocument, error) {
+	p := &jsonParser{data: data}
+	root, value, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos < len(data) {
+		return nil, p.errorf("unexpected content after the top-level value")
+	}
+
+	style := jsonStyle{colon: p.colon, comma: p.comma, newline: "\n"}
+	if bytes.Contains(data, []byte("\r\n")) {
+		style.newline = "\r\n"
+	}
+	if i := bytes.IndexByte(data[root.start:root.end], '\n'); i >= 0 {
+		// 第一个换行后的空白即为一级缩进 / The whitespace after the first line break is one level of indentation
+		rest := data[root.start+i+1 : root.end]
+		style.indent = string(rest[:len(rest)-len(bytes.TrimLeft(rest, " \t"))])
+		if style.indent == "" {
+			style.indent = "  "
+		}
+	}
+	if style.colon == "" {
+		style.colon = ":"
+		if style.indent != "" {
+			style.colon = ": "
+		}
+	}
+	if style.comma == "" {
+		style.comma = ","
+		if style.colon == ": " {
+			style.comma = ", "
+		}
+	}
+	return &jsonDocument{data: data, root: root, value: value, style: style}, nil
+}
+
+// errorf 返回带行列号的解析错误 / Return a parse error carrying the line and column
+func (p *jsonParser) errorf(format string, args ...any) error {
+	before := p.data[:min(p.pos, len(p.data))]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := utf8.RuneCount(before[bytes.LastIndexByte(before, '\n')+1:]) + 1
+	return fmt.Errorf("line %d, column %d: %s", line, column, fmt.Sprintf(format, args...))
+}
+
+// skipSpace 跳过空白 / Skip whitespace
+func (p *jsonParser) skipSpace() {
+	for p.pos < len(p.data) {
+		switch p.data[p.pos] {
+		case ' ', '\t', '\r', '\n':
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+// peek 返回当前字节,到达末尾时返回0 / Return the current byte, or 0 at the end
+func (p *jsonParser) peek() byte {
+	if p.pos < len(p.data) {
+		return p.data[p.pos]
+	}
+	return 0
+}
+
+// parseValue 解析一个值 / Parse one value
+func (p *jsonParser) parseValue() (*jsonSpan, any, error) {
+	p.skipSpace()
+	start := p.pos
+	switch c := p.peek(); {
+	case c == 0 && p.pos >= len(p.data):
+		return nil, nil, p.errorf("unexpected end of input")
+	case c == '{' || c == '[':
+		if p.depth++; p.depth > maxDataDepth {
+			return nil, nil, p.errorf("exceeded maximum nesting depth of %d", maxDataDepth)
+		}
+		span, value, err := p.parseContainer()
+		p.depth--
+		return span, value, err
+	case c == '"':
+		s, err := p.parseString()
+		return &jsonSpan{start: start, end: p.pos}, s, err
+	case c == 't' || c == 'f' || c == 'n':
+		for _, lit := range []struct {
+			text  string
+			value any
+		}{{"true", true}, {"false", false}, {"null", nil}} {
+			if bytes.HasPrefix(p.data[p.pos:], []byte(lit.text)) {
+				p.pos += len(lit.text)
+				return &jsonSpan{start: start, end: p.pos}, lit.value, nil
+			}
+		}
+	case c == '-' || (c >= '0' && c <= '9'):
+		end := p.pos
+		for end < len(p.data) && strings.IndexByte("+-0123456789.eE", p.data[end]) >= 0 {
+			end++
+		}
+		text := p.data[p.pos:end]
+		if !json.Valid(text) {
+			return nil, nil, p.errorf("invalid number %q", text)
+		}
+		value, err := parseDataNumber(string(text))
+		if err != nil {
+			return nil, nil, p.errorf("invalid number %q", text)
+		}
+		p.pos = end
+		return &jsonSpan{start: start, end: end}, value, nil
+	}
+	r, _ := utf8.DecodeRune(p.data[p.pos:])
+	return nil, nil, p.errorf("invalid character %q", r)
+}
+
+// parseContainer 解析对象或数组 / Parse an object or an array
+func (p *jsonParser) parseContainer() (*jsonSpan, any, error) {
+	span := &jsonSpan{start: p.pos, object: p.peek() == '{', array: p.peek() == '['}
+	closer := byte(']')
+	var obj *dataObject
+	arr := []any{}
+	if span.object {
+		closer = '}'
+		obj = newDataObject()
+	}
+	result := func() any {
+		if span.object {
+			return obj
+		}
+		return arr
+	}
+
+	p.pos++
+	p.skipSpace()
+	if p.peek() == closer {
+		p.pos++
+		span.end = p.pos
+		return span, result(), nil
+	}
+	for {
+		p.skipSpace()
+		var key string
+		if span.object {
+			if p.peek() != '"' {
+				return nil, nil, p.errorf("expected a member name")
+			}
+			span.keyStarts = append(span.keyStarts, p.pos)
+			var err error
+			if key, err = p.parseString(); err != nil {
+				return nil, nil, err
+			}
+			p.skipSpace()
+			if p.peek() != ':' {
+				return nil, nil, p.errorf("expected ':' after member name")
+			}
+			p.pos++
+			if p.colon == "" {
+				p.colon = ":"
+				if p.peek() == ' ' {
+					p.colon = ": "
+				}
+			}
+		}
+
+		child, value, err := p.parseValue()
+		if err != nil {
+			return nil, nil, err
+		}
+		span.items = append(span.items, child)
+		if span.object {
+			span.keys = append(span.keys, key)
+			obj.set(key, value)
+		} else {
+			arr = append(arr, value)
+		}
+
+		p.skipSpace()
+		switch p.peek() {
+		case ',':
+			p.pos++
+			if p.comma == "" && p.peek() == ' ' {
+				p.comma = ", "
+			} else if p.comma == "" && p.peek() != '\n' && p.peek() != '\r' {
+				p.comma = ","
+			}
+		case closer:
+			p.pos++
+			span.end = p.pos
+			return span, result(), nil
+		default:
+			return nil, nil, p.errorf("expected ',' or '%c'", closer)
+		}
+	}
+}
+
+// parseString 解析字符串 / Parse a string
+func (p *jsonParser) parseString() (string, error) {
+	start := p.pos
+	escaped := false
+	for i := start + 1; i < len(p.data); i++ {
+		switch c := p.data[i]; {
+		case c == '\\':
+			escaped = true
+			i++
+		case c == '"':
+			p.pos = i + 1
+			if !escaped {
+				return string(p.data[start+1 : i]), nil
+			}
+			var s string
+			if err := json.Unmarshal(p.data[start:p.pos], &s); err != nil {
+				p.pos = start
+				return "", p.errorf("invalid string escape")
+			}
+			return s, nil
+		case c < 0x20:
+			p.pos = i
+			return "", p.errorf("control character in string")
+		}
+	}
+	p.pos = len(p.data)
+	return "", p.errorf("unterminated string")
+}
+
+// child 返回成员或元素的范围及其序号,重复的成员名取最后一个 / Return the span of a member or element and its position; duplicate member names resolve to the last one
+func (s *jsonSpan) child(key any) (*jsonSpan, int) {
+	switch k := key.(type) {
+	case string:
+		for i := len(s.keys) - 1; i >= 0; i-- {
+			if s.keys[i] == k {
+				return s.items[i], i
+			}
+		}
+	case int:
+		if s.array && k >= 0 && k < len(s.items) {
+			return s.items[k], k
+		}
+	}
+	return nil, -1
+}
+
+// memberStart 返回成员的起始位置,对象成员从成员名开始 / Return the start offset of a member; object members start at their name
+func (s *jsonSpan) memberStart(i int) int {
+	if s.object {
+		return s.keyStarts[i]
+	}
+	return s.items[i].start
+}
+
+func (d *jsonDocument) tree() any {
+	return d.value
+}
+
+func (d *jsonDocument) bytes() []byte {
+	return d.data
+}
+
+// apply 在原文中替换、插入或删除值 / Replace, insert or delete a value in the original text
+func (d *jsonDocument) apply(op dataOp) error {
+	var parent *jsonSpan
+	span, index, depth := d.root, -1, 0
+	for depth < len(op.loc) {
+		child, i := span.child(op.loc[depth])
+		if child == nil {
+			break
+		}
+		parent, span, index = span, child, i
+		depth++
+	}
+
+	switch {
+	case op.delete:
+		if depth < len(op.loc) || parent == nil {
+			return errDataLayout
+		}
+		return d.remove(parent, index)
+	case depth == len(op.loc):
+		return d.replace(span, parent, op.value)
+	case span.object || span.array:
+		return d.insert(span, op.loc[depth], wrapDataValue(op.loc[depth+1:], op.value))
+	default:
+		// 标量(通常是null)被新的容器取代 / A scalar, usually null, is replaced by a new container
+		return d.replace(span, parent, wrapDataValue(op.loc[depth:], op.value))
+	}
+}
+
+// replace 替换值,单行容器中的值保持单行 / Replace a value; values inside single-line containers stay on one line
+func (d *jsonDocument) replace(span, parent *jsonSpan, value any) error {
+	text, err := d.render(value, parent != nil && !d.multiline(parent), span.start)
+	if err != nil {
+		return err
+	}
+	return d.splice(span.start, span.end, text)
+}
+
+// insert 在对象末尾添加成员或在数组末尾追加元素 / Add a member at the end of an object or append an element to an array
+func (d *jsonDocument) insert(container *jsonSpan, key any, value any) error {
+	if container.array && key != len(container.items) {
+		return errDataLayout
+	}
+	if len(container.items) == 0 {
+		var whole any = []any{value}
+		if container.object {
+			obj := newDataObject()
+			obj.set(key.(string), value)
+			whole = obj
+		}
+		text, err := d.render(whole, false, container.start)
+		if err != nil {
+			return err
+		}
+		return d.splice(container.start, container.end, text)
+	}
+
+	var member bytes.Buffer
+	last := container.items[len(container.items)-1]
+	style, prefix := d.style, ""
+	if d.multiline(container) {
+		prefix = d.lineIndent(container.memberStart(0))
+		member.WriteString("," + style.newline + prefix)
+	} else {
+		style.indent = ""
+		member.WriteString(style.comma)
+	}
+	if container.object {
+		writeJSONString(&member, key.(string))
+		member.WriteString(style.colon)
+	}
+	if err := style.write(&member, value, prefix, 0); err != nil {
+		return err
+	}
+	return d.splice(last.end, last.end, member.String())
+}
+
+// remove 删除成员或元素及其分隔符 / Remove a member or element together with its separator
+func (d *jsonDocument) remove(container *jsonSpan, i int) error {
+	switch {
+	case len(container.items) == 1:
+		return d.splice(container.start+1, container.end-1, "")
+	case i > 0:
+		return d.splice(container.items[i-1].end, container.items[i].end, "")
+	default:
+		return d.splice(container.memberStart(0), container.memberStart(1), "")
+	}
+}
+
+// render 渲染值,prefix取自位置所在行的缩进 / Render a value, taking the indentation of the line containing an offset as prefix
+func (d *jsonDocument) render(value any, singleLine bool, at int) (string, error) {
+	style := d.style
+	if singleLine {
+		style.indent = ""
+	}
+	var buf bytes.Buffer
+	if err := style.write(&buf, value, d.lineIndent(at), 0); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// multiline 容器是否跨多行 / Whether a container spans several lines
+func (d *jsonDocument) multiline(span *jsonSpan) bool {
+	return d.style.indent != "" && bytes.IndexByte(d.data[span.start:span.end], '\n') >= 0
+}
+
+// lineIndent 返回位置所在行的前导空白 / Return the leading whitespace of the line containing an offset
+func (d *jsonDocument) lineIndent(at int) string {
+	start := bytes.LastIndexByte(d.data[:at], '\n') + 1
+	line := d.data[start:at]
+	return string(line[:len(line)-len(bytes.TrimLeft(line, " \t"))])
+}
+
+// splice 替换一段原文并重新解析 / Replace a range of the text and parse it again
+func (d *jsonDocument) splice(start, end int, text string) error {
+	data := make([]byte, 0, len(d.data)-(end-start)+len(text))
+	data = append(append(append(data, d.data[:start]...), text...), d.data[end:]...)
+	updated, err := parseJSONDocument(data)
+	if err != nil {
+		return errDataLayout
+	}
+	d.data, d.root, d.value = updated.data, updated.root, updated.value
+	return nil
+}
+
+// rebuild 按检测到的风格重新生成整个文件,保留首尾空白 / Regenerate the whole file in the detected style, keeping the surrounding whitespace
+func (d *jsonDocument) rebuild(_ []dataOp, expected any) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Write(d.data[:d.root.start])
+	if err := d.style.write(&buf, expected, "", 0); err != nil {
+		return nil, err
+	}
+	buf.Write(d.data[d.root.end:])
+	return buf.Bytes(), nil
+}
+
+// marshalDataJSON 按风格编码数据树,对象保留键顺序 / Encode a data tree in a style, keeping the key order of objects
+func marshalDataJSON(v any, style jsonStyle) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := style.write(&buf, v, "", 0); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// write 编码一个值,prefix为当前行的缩进 / Encode a value; prefix is the indentation of the current line
+func (st jsonStyle) write(buf *bytes.Buffer, v any, prefix string, depth int) error {
+	if depth > maxDataDepth {
+		return fmt.Errorf("exceeded maximum nesting depth of %d", maxDataDepth)
+	}
+	switch t := v.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		buf.WriteString(strconv.FormatBool(t))
+	case int64:
+		buf.WriteString(strconv.FormatInt(t, 10))
+	case float64:
+		// JSON无法表示的非有限数写为字符串 / Non-finite numbers, which JSON cannot represent, are written as strings
+		if math.IsInf(t, 0) || math.IsNaN(t) {
+			writeJSONString(buf, strconv.FormatFloat(t, 'g', -1, 64))
+		} else {
+			buf.WriteString(strconv.FormatFloat(t, 'g', -1, 64))
+		}
+	case string:
+		writeJSONString(buf, t)
+	case *dataObject:
+		if len(t.keys) == 0 {
+			buf.WriteString("{}")
+			return nil
+		}
+		buf.WriteByte('{')
+		inner := prefix + st.indent
+		for i, key := range t.keys {
+			st.separate(buf, i, inner)
+			writeJSONString(buf, key)
+			buf.WriteString(st.colon)
+			if err := st.write(buf, t.values[key], inner, depth+1); err != nil {
+				return err
+			}
+		}
+		st.close(buf, prefix, '}')
+	case []any:
+		if len(t) == 0 {
+			buf.WriteString("[]")
+			return nil
+		}
+		buf.WriteByte('[')
+		inner := prefix + st.indent
+		for i, item := range t {
+			st.separate(buf, i, inner)
+			if err := st.write(buf, item, inner, depth+1); err != nil {
+				return err
+			}
+		}
+		st.close(buf, prefix, ']')
+	case encoding.TextMarshaler:
+		text, err := t.MarshalText()
+		if err != nil {
+			return err
+		}
+		writeJSONString(buf, string(text))
+	default:
+		return errors.New("unsupported value type " + fmt.Sprintf("%T", v))
+	}
+	return nil
+}
+
+// separate 写入第i个元素之前的分隔和缩进 / Write the separator and indentation before the i-th element
+func (st jsonStyle) separate(buf *bytes.Buffer, i int, inner string) {
+	switch {
+	case st.indent != "":
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteString(st.newline + inner)
+	case i > 0:
+		buf.WriteString(st.comma)
+	}
+}
+
+// close 写入容器的结束符 / Write the closing character of a container
+func (st jsonStyle) close(buf *bytes.Buffer, prefix string, closer byte) {
+	if st.indent != "" {
+		buf.WriteString(st.newline + prefix)
+	}
+	buf.WriteByte(closer)
+}
+
+// writeJSONString 写入带引号的JSON字符串,不转义HTML字符 / Write a quoted JSON string without escaping HTML characters
+func writeJSONString(buf *bytes.Buffer, s string) {
+	buf.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(buf, `\u%04x`, r)
+			} else {
+				buf.WriteRune(r)
+			}
+		}
+	}
+	buf.WriteByte('"')
+}
+
+// wrapDataValue 将值包装在由位置描述的新对象和数组中 / Wrap a value in new objects and arrays described by a location
+func wrapDataValue(loc []any, value any) any {
+	for i := len(loc) - 1; i >= 0; i-- {
+		if key, ok := loc[i].(string); ok {
+			obj := newDataObject()
+			obj.set(key, value)
+			value = obj
+		} else {
+			value = []any{value}
+		}
+	}
+	return value
+}
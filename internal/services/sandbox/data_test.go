@@ -0,0 +1,280 @@
+package sandbox
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"mcp-toolkit/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// updateData 执行数据更新并返回更新后的文件内容 / Run a data update and return the file content afterwards
+func updateData(t *testing.T, service *Service, dir string, req *types.UpdateDataRequest) (*types.UpdateDataResponse, string) {
+	t.Helper()
+	resp, err := service.UpdateData(req)
+	require.NoError(t, err)
+	return resp, readTestFile(t, filepath.Join(dir, req.Path))
+}
+
+func TestQueryData(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+	writeTestFiles(t, tempDir, map[string]string{
+		"package.json": `{"name": "app", "dependencies": {"zod": "^3.0.0", "react": "^18.2.0"}}`,
+		"compose.yml":  "services:\n  web:\n    image: nginx:1.25\n    ports: [\"80:80\"]\n  db:\n    image: postgres:16\n",
+		"Cargo.toml":   "[package]\nname = \"tool\"\nversion = \"0.1.0\"\n\n[[bin]]\nname = \"a\"\n\n[[bin]]\nname = \"b\"\n",
+		"users.csv":    "id;name;age\n1;alice;30\n2;bob;25\n",
+		"data.txt":     "{}",
+	})
+
+	resp, err := service.QueryData(&types.QueryDataRequest{Path: "package.json", Query: "$.dependencies"})
+	require.NoError(t, err)
+	assert.Equal(t, types.DataFormatJSON, resp.Format)
+	require.Equal(t, 1, resp.Count)
+	// 对象保留原有的键顺序 / Objects keep their original key order
+	assert.Equal(t, `{"zod":"^3.0.0","react":"^18.2.0"}`, string(resp.Matches[0].Value))
+
+	resp, err = service.QueryData(&types.QueryDataRequest{Path: "compose.yml", Query: "$.services.*.image"})
+	require.NoError(t, err)
+	assert.Equal(t, []types.DataMatch{
+		{Path: "$.services.web.image", Value: json.RawMessage(`"nginx:1.25"`)},
+		{Path: "$.services.db.image", Value: json.RawMessage(`"postgres:16"`)},
+	}, resp.Matches)
+
+	resp, err = service.QueryData(&types.QueryDataRequest{Path: "Cargo.toml", Query: "$.bin[*].name", Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 2, resp.Count)
+	assert.True(t, resp.Truncated)
+	assert.Equal(t, "$.bin[0].name", resp.Matches[0].Path)
+
+	// 分隔符自动检测,数字字符串在过滤中按数值比较 / The delimiter is detected and numeric strings compare as numbers in filters
+	resp, err = service.QueryData(&types.QueryDataRequest{Path: "users.csv", Query: "$[?(@.age > 26)].name"})
+	require.NoError(t, err)
+	require.Len(t, resp.Matches, 1)
+	assert.Equal(t, `"alice"`, string(resp.Matches[0].Value))
+
+	resp, err = service.QueryData(&types.QueryDataRequest{Path: "users.csv", Query: "$[1][1]", NoHeader: true})
+	require.NoError(t, err)
+	assert.Equal(t, `"alice"`, string(resp.Matches[0].Value))
+
+	resp, err = service.QueryData(&types.QueryDataRequest{Path: "data.txt", Query: "$", Format: types.DataFormatJSON})
+	require.NoError(t, err)
+	assert.Equal(t, "{}", string(resp.Matches[0].Value))
+
+	_, err = service.QueryData(&types.QueryDataRequest{Path: "data.txt", Query: "$"})
+	assert.ErrorContains(t, err, types.ErrUnsupportedDataFormat)
+	_, err = service.QueryData(&types.QueryDataRequest{Path: "compose.yml", Query: "$.services["})
+	assert.ErrorContains(t, err, "invalid query")
+	_, err = service.QueryData(&types.QueryDataRequest{Path: "compose.yml", Query: "$", Format: types.DataFormatJSON})
+	assert.ErrorContains(t, err, "failed to parse json")
+	_, err = service.QueryData(&types.QueryDataRequest{Path: "../outside.json", Query: "$"})
+	assert.ErrorContains(t, err, types.ErrSandboxViolation)
+}
+
+func TestUpdateDataJSON(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+	original := "{\n    \"name\": \"app\",\n    \"version\": \"1.0.0\",\n    \"scripts\": {\n        \"test\": \"jest\"\n    },\n    \"files\": [\"dist\"]\n}\n"
+	writeTestFiles(t, tempDir, map[string]string{"package.json": original})
+
+	// 预览不修改文件 / A dry run leaves the file untouched
+	resp, err := service.UpdateData(&types.UpdateDataRequest{Path: "package.json", Query: "$.version", Op: types.DataOpSet, Value: `"1.1.0"`, DryRun: true})
+	require.NoError(t, err)
+	assert.Contains(t, resp.Diff, "-    \"version\": \"1.0.0\",\n+    \"version\": \"1.1.0\",")
+	assert.Equal(t, original, readTestFile(t, filepath.Join(tempDir, "package.json")))
+
+	resp, content := updateData(t, service, tempDir, &types.UpdateDataRequest{Path: "package.json", Query: "version", Op: types.DataOpSet, Value: `"1.1.0"`})
+	assert.Equal(t, []string{"$.version"}, resp.Paths)
+	assert.False(t, resp.Reformatted)
+	assert.NotEmpty(t, resp.SHA256)
+	assert.Equal(t, "{\n    \"name\": \"app\",\n    \"version\": \"1.1.0\",\n    \"scripts\": {\n        \"test\": \"jest\"\n    },\n    \"files\": [\"dist\"]\n}\n", content)
+
+	// 新成员和缺少的中间对象按原缩进插入 / New members and missing intermediate objects are inserted with the original indentation
+	_, content = updateData(t, service, tempDir, &types.UpdateDataRequest{Path: "package.json", Query: "$.scripts.build", Op: types.DataOpSet, Value: `"tsc"`})
+	_, content = updateData(t, service, tempDir, &types.UpdateDataRequest{Path: "package.json", Query: "$.engines.node", Op: types.DataOpSet, Value: `">=18"`})
+	_, content = updateData(t, service, tempDir, &types.UpdateDataRequest{Path: "package.json", Query: "$.files[1]", Op: types.DataOpSet, Value: `"README.md"`})
+	assert.Equal(t, "{\n    \"name\": \"app\",\n    \"version\": \"1.1.0\",\n    \"scripts\": {\n        \"test\": \"jest\",\n        \"build\": \"tsc\"\n    },\n    \"files\": [\"dist\", \"README.md\"],\n    \"engines\": {\n        \"node\": \">=18\"\n    }\n}\n", content)
+
+	resp, content = updateData(t, service, tempDir, &types.UpdateDataRequest{Path: "package.json", Query: "$..test", Op: types.DataOpDelete})
+	assert.Equal(t, 1, resp.Updated)
+	_, content = updateData(t, service, tempDir, &types.UpdateDataRequest{Path: "package.json", Query: "$.name", Op: types.DataOpDelete})
+	assert.Equal(t, "{\n    \"version\": \"1.1.0\",\n    \"scripts\": {\n        \"build\": \"tsc\"\n    },\n    \"files\": [\"dist\", \"README.md\"],\n    \"engines\": {\n        \"node\": \">=18\"\n    }\n}\n", content)
+
+	// 同一值被多次选中时只修改一次 / A value selected several times is changed once
+	resp, _ = updateData(t, service, tempDir, &types.UpdateDataRequest{Path: "package.json", Query: "$['engines', 'engines']", Op: types.DataOpDelete})
+	assert.Equal(t, 1, resp.Updated)
+
+	resp, _ = updateData(t, service, tempDir, &types.UpdateDataRequest{Path: "package.json", Query: "$.version", Op: types.DataOpSet, Value: `"1.1.0"`})
+	assert.Equal(t, "no changes were made", resp.Message)
+}
+
+func TestUpdateDataYAML(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+	writeTestFiles(t, tempDir, map[string]string{"deploy.yaml": `# deployment settings
+apiVersion: apps/v1
+spec:
+  replicas: 2 # scaled by hand
+  template:
+    containers:
+    - name: web
+      image: "nginx:1.25"
+      env:
+      - name: MODE
+        value: prod
+    - name: sidecar
+      image: 'envoy:1.29'
+`})
+
+	// 标量原位替换,引号和注释保持不变 / Scalars are replaced in place, keeping quotes and comments
+	_, content := updateData(t, service, tempDir, &types.UpdateDataRequest{Path: "deploy.yaml", Query: "$.spec.template.containers[*].image", Op: types.DataOpSet, Value: `"registry.local/app:2.0"`})
+	_, content = updateData(t, service, tempDir, &types.UpdateDataRequest{Path: "deploy.yaml", Query: "$.spec.replicas", Op: types.DataOpSet, Value: `3`})
+	assert.Equal(t, `# deployment settings
+apiVersion: apps/v1
+spec:
+  replicas: 3 # scaled by hand
+  template:
+    containers:
+    - name: web
+      image: "registry.local/app:2.0"
+      env:
+      - name: MODE
+        value: prod
+    - name: sidecar
+      image: 'registry.local/app:2.0'
+`, content)
+
+	// 不缩进的序列中追加和删除条目 / Entries are appended to and removed from indentless sequences
+	_, content = updateData(t, service, tempDir, &types.UpdateDataRequest{Path: "deploy.yaml", Query: "$.spec.template.containers[0].env[1]", Op: types.DataOpSet, Value: `{"name": "DEBUG", "value": "1"}`})
+	_, content = updateData(t, service, tempDir, &types.UpdateDataRequest{Path: "deploy.yaml", Query: "$.spec.template.containers[?(@.name == 'sidecar')]", Op: types.DataOpDelete})
+	resp, content := updateData(t, service, tempDir, &types.UpdateDataRequest{Path: "deploy.yaml", Query: "$.metadata.labels.app", Op: types.DataOpSet, Value: `"web"`})
+	assert.False(t, resp.Reformatted)
+	assert.Equal(t, `# deployment settings
+apiVersion: apps/v1
+spec:
+  replicas: 3 # scaled by hand
+  template:
+    containers:
+    - name: web
+      image: "registry.local/app:2.0"
+      env:
+      - name: MODE
+        value: prod
+      - name: DEBUG
+        value: "1"
+metadata:
+  labels:
+    app: web
+`, content)
+
+	// 无法原位完成的修改重新编码,注释仍然保留 / Changes that cannot be made in place are re-encoded and comments survive
+	resp, content = updateData(t, service, tempDir, &types.UpdateDataRequest{Path: "deploy.yaml", Query: "$.spec.replicas", Op: types.DataOpSet, Value: `{"min": 1, "max": 5}`})
+	assert.True(t, resp.Reformatted)
+	assert.Contains(t, content, "# deployment settings")
+	assert.Contains(t, content, "  replicas: # scaled by hand\n    min: 1\n    max: 5\n")
+}
+
+func TestUpdateDataTOML(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+	writeTestFiles(t, tempDir, map[string]string{"Cargo.toml": `# crate manifest
+[package]
+name = "tool"   # binary name
+version = "0.1.0"
+authors = ["a"]
+
+[dependencies]
+serde = { version = "1.0", features = ["derive"] }
+
+[[bin]]
+name = "tool"
+path = "src/main.rs"
+
+[[bin]]
+name = "helper"
+`})
+
+	_, content := updateData(t, service, tempDir, &types.UpdateDataRequest{Path: "Cargo.toml", Query: "$.package.version", Op: types.DataOpSet, Value: `"0.2.0"`})
+	_, content = updateData(t, service, tempDir, &types.UpdateDataRequest{Path: "Cargo.toml", Query: "$.dependencies.serde.version", Op: types.DataOpSet, Value: `"1.0.200"`})
+	_, content = updateData(t, service, tempDir, &types.UpdateDataRequest{Path: "Cargo.toml", Query: "$.dependencies.anyhow", Op: types.DataOpSet, Value: `"1"`})
+	_, content = updateData(t, service, tempDir, &types.UpdateDataRequest{Path: "Cargo.toml", Query: "$.package.metadata.docs", Op: types.DataOpSet, Value: `true`})
+	_, content = updateData(t, service, tempDir, &types.UpdateDataRequest{Path: "Cargo.toml", Query: "$.package.authors", Op: types.DataOpDelete})
+	resp, content := updateData(t, service, tempDir, &types.UpdateDataRequest{Path: "Cargo.toml", Query: "$.bin[0]", Op: types.DataOpDelete})
+	assert.False(t, resp.Reformatted)
+	assert.Equal(t, `# crate manifest
+[package]
+name = "tool"   # binary name
+version = "0.2.0"
+metadata.docs = true
+
+[dependencies]
+serde = { version = "1.0.200", features = ["derive"] }
+anyhow = "1"
+
+[[bin]]
+name = "helper"
+`, content)
+
+	_, err := service.UpdateData(&types.UpdateDataRequest{Path: "Cargo.toml", Query: "$.package.name", Op: types.DataOpSet, Value: `null`})
+	assert.ErrorContains(t, err, "cannot represent null")
+}
+
+func TestUpdateDataCSV(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+	writeTestFiles(t, tempDir, map[string]string{"users.tsv": "id\tname\temail\r\n1\talice\ta@example.com\r\n2\tbob\tb@example.com\r\n"})
+
+	_, content := updateData(t, service, tempDir, &types.UpdateDataRequest{Path: "users.tsv", Query: "$[?(@.name == 'bob')].email", Op: types.DataOpSet, Value: `"bob@example.com"`})
+	_, content = updateData(t, service, tempDir, &types.UpdateDataRequest{Path: "users.tsv", Query: "$[2]", Op: types.DataOpSet, Value: `{"id": 3, "name": "carol", "role": "admin"}`})
+	assert.Equal(t, "id\tname\temail\trole\r\n1\talice\ta@example.com\t\r\n2\tbob\tbob@example.com\t\r\n3\tcarol\t\tadmin\r\n", content)
+
+	// 所有行都删除的列被移除 / A column deleted from every row is removed
+	_, content = updateData(t, service, tempDir, &types.UpdateDataRequest{Path: "users.tsv", Query: "$[*].email", Op: types.DataOpDelete})
+	_, content = updateData(t, service, tempDir, &types.UpdateDataRequest{Path: "users.tsv", Query: "$[0]", Op: types.DataOpDelete})
+	assert.Equal(t, "id\tname\trole\r\n2\tbob\t\r\n3\tcarol\tadmin\r\n", content)
+
+	_, err := service.UpdateData(&types.UpdateDataRequest{Path: "users.tsv", Query: "$[0].name", Op: types.DataOpSet, Value: `["x"]`})
+	assert.ErrorContains(t, err, "scalar values")
+}
+
+func TestUpdateDataErrors(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+	writeTestFiles(t, tempDir, map[string]string{"config.json": `{"list": [1, 2], "name": "x"}`})
+
+	tests := []struct {
+		name string
+		req  *types.UpdateDataRequest
+		err  string
+	}{
+		{"missing op", &types.UpdateDataRequest{Path: "config.json", Query: "$.name"}, "invalid op"},
+		{"missing value", &types.UpdateDataRequest{Path: "config.json", Query: "$.name", Op: types.DataOpSet}, "value is required"},
+		{"invalid value", &types.UpdateDataRequest{Path: "config.json", Query: "$.name", Op: types.DataOpSet, Value: `{bad`}, "invalid value"},
+		{"no match", &types.UpdateDataRequest{Path: "config.json", Query: "$.missing", Op: types.DataOpDelete}, types.ErrDataNoMatch},
+		{"wildcard creates nothing", &types.UpdateDataRequest{Path: "config.json", Query: "$.missing[*]", Op: types.DataOpSet, Value: `1`}, types.ErrDataNoMatch},
+		{"index out of range", &types.UpdateDataRequest{Path: "config.json", Query: "$.list[5]", Op: types.DataOpSet, Value: `1`}, "index out of range"},
+		{"not an object", &types.UpdateDataRequest{Path: "config.json", Query: "$.name.first", Op: types.DataOpSet, Value: `1`}, "$.name is not an object"},
+		{"root delete", &types.UpdateDataRequest{Path: "config.json", Query: "$", Op: types.DataOpDelete}, "document root"},
+		{"bad delimiter", &types.UpdateDataRequest{Path: "config.json", Query: "$", Op: types.DataOpDelete, Delimiter: "ab"}, "invalid delimiter"},
+		{"bad format", &types.UpdateDataRequest{Path: "config.json", Query: "$", Op: types.DataOpDelete, Format: "xml"}, types.ErrUnsupportedDataFormat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.UpdateData(tt.req)
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+
+	// 下标等于长度时追加,负下标从末尾计 / An index equal to the length appends and negative indexes count from the end
+	_, content := updateData(t, service, tempDir, &types.UpdateDataRequest{Path: "config.json", Query: "$.list[2]", Op: types.DataOpSet, Value: `3`})
+	_, content = updateData(t, service, tempDir, &types.UpdateDataRequest{Path: "config.json", Query: "$.list[-1]", Op: types.DataOpSet, Value: `30`})
+	assert.Equal(t, `{"list": [1, 2, 30], "name": "x"}`, content)
+
+	data, err := os.ReadFile(filepath.Join(tempDir, "config.json"))
+	require.NoError(t, err)
+	assert.True(t, json.Valid(data))
+}
@@ -0,0 +1,484 @@
+// Copyright 2024 MCP Toolkit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sandbox
+
+import (
+	"bytes"
+	"encoding"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/pelletier/go-toml/v2/unstable"
+)
+
+// tomlDocument 可原位修改的TOML文件 / TOML file that can be changed in place
+type tomlDocument struct {
+	data    []byte
+	value   any
+	entries []tomlEntry
+	tables  []tomlTable
+}
+
+// tomlEntry 键值对在原文中的位置 / Position of a key/value pair in the text
+type tomlEntry struct {
+	path               []any // 键的完整位置 / Full location of the key
+	start, end         int   // 从键到值结束的范围 / Range from the key to the end of the value
+	valueStart         int   // 值的起始位置 / Start of the value
+	lineStart, lineEnd int   // 所在行的范围,包含换行符 / Range of its lines, including the terminator
+}
+
+// tomlTable 表在原文中的位置,第一个元素是根表 / Position of a table in the text; the first one is the root table
+type tomlTable struct {
+	path       []any // 表的位置,数组表元素带下标 / Location of the table, with an index for array table elements
+	start, end int   // 从表头行到下一个表头的范围 / Range from the header line to the next header
+	insertAt   int   // 最后一个键值对之后的位置 / Offset after the last key/value pair
+}
+
+// parseTOMLDocument 解析TOML文件 / Parse a TOML file
+func parseTOMLDocument(data []byte) (*tomlDocument, error) {
+	var raw map[string]any
+	if err := toml.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	d := &tomlDocument{data: data, value: normalizeData(raw)}
+	if err := d.scan(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// scan 记录键值对和表的位置,并让数据树的键按原文顺序排列 / Record the positions of key/value pairs and tables, and put the keys of the tree in document order
+func (d *tomlDocument) scan() error {
+	var p unstable.Parser
+	p.Reset(d.data)
+	var current []any
+	arrayCounts := make(map[string]int)
+	order := tomlKeyOrder{keys: make(map[string][]string), seen: make(map[[2]string]bool)}
+	d.tables = []tomlTable{{path: []any{}}}
+	for p.NextExpression() {
+		expr := p.Expression()
+		switch expr.Kind {
+		case unstable.Table, unstable.ArrayTable:
+			keys, offset, _ := tomlKeyParts(expr)
+			current = nil
+			for i, key := range keys {
+				current = append(current, key)
+				id := formatDataPath(current)
+				if expr.Kind == unstable.ArrayTable && i == len(keys)-1 {
+					current = append(current, arrayCounts[id])
+					arrayCounts[id]++
+				} else if n, ok := arrayCounts[id]; ok {
+					current = append(current, n-1)
+				}
+			}
+			order.notePath(current)
+			start := bytes.LastIndexByte(d.data[:offset], '\n') + 1
+			d.tables[len(d.tables)-1].end = start
+			d.tables = append(d.tables, tomlTable{path: current, start: start, insertAt: d.lineEnd(offset)})
+		case unstable.KeyValue:
+			keys, _, keyEnd := tomlKeyParts(expr)
+			path := append(append([]any{}, current...), keys...)
+			order.notePath(path)
+			order.noteValue(expr.Value(), path)
+			start := int(expr.Raw.Offset)
+			end := start + int(expr.Raw.Length)
+			valueStart := keyEnd
+			for valueStart < end && strings.IndexByte(" \t=", d.data[valueStart]) >= 0 {
+				valueStart++
+			}
+			entry := tomlEntry{
+				path:       path,
+				start:      start,
+				end:        end,
+				valueStart: valueStart,
+				lineStart:  bytes.LastIndexByte(d.data[:start], '\n') + 1,
+				lineEnd:    d.lineEnd(end),
+			}
+			d.entries = append(d.entries, entry)
+			d.tables[len(d.tables)-1].insertAt = entry.lineEnd
+		}
+	}
+	d.tables[len(d.tables)-1].end = len(d.data)
+	if err := p.Error(); err != nil {
+		return err
+	}
+	order.apply(d.value, nil)
+	return nil
+}
+
+// tomlKeyOrder 各表中键首次出现的顺序,以表的规范路径为键 / Order in which keys first appear in each table, keyed by the normalized path of the table
+type tomlKeyOrder struct {
+	keys map[string][]string
+	seen map[[2]string]bool
+}
+
+// notePath 记录位置上各级的键 / Record the keys along a location
+func (o tomlKeyOrder) notePath(loc []any) {
+	for i, key := range loc {
+		name, ok := key.(string)
+		if !ok {
+			continue
+		}
+		id := formatDataPath(loc[:i])
+		if !o.seen[[2]string{id, name}] {
+			o.seen[[2]string{id, name}] = true
+			o.keys[id] = append(o.keys[id], name)
+		}
+	}
+}
+
+// noteValue 记录内联表和数组中的键 / Record the keys inside inline tables and arrays
+func (o tomlKeyOrder) noteValue(node *unstable.Node, loc []any) {
+	switch node.Kind {
+	case unstable.InlineTable:
+		it := node.Children()
+		for it.Next() {
+			keys, _, _ := tomlKeyParts(it.Node())
+			path := append(append([]any{}, loc...), keys...)
+			o.notePath(path)
+			o.noteValue(it.Node().Value(), path)
+		}
+	case unstable.Array:
+		it := node.Children()
+		for i := 0; it.Next(); i++ {
+			o.noteValue(it.Node(), childLoc(loc, i))
+		}
+	}
+}
+
+// apply 按记录的顺序排列对象的键 / Order the keys of objects as recorded
+func (o tomlKeyOrder) apply(v any, loc []any) {
+	switch t := v.(type) {
+	case *dataObject:
+		keys := make([]string, 0, len(t.keys))
+		for _, key := range o.keys[formatDataPath(loc)] {
+			if _, ok := t.values[key]; ok {
+				keys = append(keys, key)
+			}
+		}
+		if len(keys) == len(t.keys) {
+			t.keys = keys
+		}
+		for _, key := range t.keys {
+			o.apply(t.values[key], childLoc(loc, key))
+		}
+	case []any:
+		for i, item := range t {
+			o.apply(item, childLoc(loc, i))
+		}
+	}
+}
+
+// tomlKeyParts 返回表达式的键、第一个键的起始位置和最后一个键的结束位置 / Return the keys of an expression, the start of the first key and the end of the last one
+func tomlKeyParts(expr *unstable.Node) ([]any, int, int) {
+	var keys []any
+	first, last := -1, 0
+	it := expr.Key()
+	for it.Next() {
+		node := it.Node()
+		keys = append(keys, string(node.Data))
+		if first < 0 {
+			first = int(node.Raw.Offset)
+		}
+		last = int(node.Raw.Offset + node.Raw.Length)
+	}
+	return keys, first, last
+}
+
+// lineEnd 返回位置所在行之后的位置 / Return the offset after the line containing a position
+func (d *tomlDocument) lineEnd(at int) int {
+	if i := bytes.IndexByte(d.data[at:], '\n'); i >= 0 {
+		return at + i + 1
+	}
+	return len(d.data)
+}
+
+func (d *tomlDocument) tree() any {
+	return d.value
+}
+
+func (d *tomlDocument) bytes() []byte {
+	return d.data
+}
+
+// apply 替换或删除键值对、修改内联值、在表中添加键或删除表,其他修改返回errDataLayout
+// Replace or delete key/value pairs, change inline values, add keys to tables or delete tables; other changes return errDataLayout
+func (d *tomlDocument) apply(op dataOp) error {
+	for i := len(d.entries) - 1; i >= 0; i-- {
+		entry := d.entries[i]
+		if !locHasPrefix(op.loc, entry.path) {
+			continue
+		}
+		if len(entry.path) == len(op.loc) {
+			if op.delete {
+				return d.removeEntry(entry)
+			}
+			text, err := tomlValue(op.value)
+			if err != nil {
+				return err
+			}
+			return d.splice(entry.valueStart, entry.end, text)
+		}
+
+		// 修改内联表或数组内部的值时整体重写该值 / Changes inside an inline table or array rewrite that value as a whole
+		current, _ := getData(d.value, entry.path)
+		current = cloneData(current)
+		var err error
+		if op.delete {
+			current, err = deleteData(current, op.loc[len(entry.path):])
+		} else {
+			current, _, err = setData(current, op.loc[len(entry.path):], op.value)
+		}
+		if err != nil {
+			return err
+		}
+		text, err := tomlValue(current)
+		if err != nil {
+			return err
+		}
+		return d.splice(entry.valueStart, entry.end, text)
+	}
+
+	if op.delete {
+		return d.removeTable(op.loc)
+	}
+	return d.insertEntry(op)
+}
+
+// removeEntry 删除独占一行的键值对 / Remove a key/value pair that has a line of its own
+func (d *tomlDocument) removeEntry(entry tomlEntry) error {
+	before := strings.TrimSpace(string(d.data[entry.lineStart:entry.start]))
+	after := strings.TrimSpace(string(d.data[entry.end:entry.lineEnd]))
+	if before != "" || (after != "" && !strings.HasPrefix(after, "#")) {
+		return errDataLayout
+	}
+	return d.splice(entry.lineStart, entry.lineEnd, "")
+}
+
+// removeTable 删除整个表段,要求表的内容都在该段内 / Remove a whole table section, provided all of its content lies in that section
+func (d *tomlDocument) removeTable(loc []any) error {
+	for _, table := range d.tables[1:] {
+		if !locEqual(table.path, loc) {
+			continue
+		}
+		for _, other := range d.tables {
+			if locHasPrefix(other.path, loc) && other.start != table.start {
+				return errDataLayout
+			}
+		}
+		for _, entry := range d.entries {
+			if locHasPrefix(entry.path, loc) && (entry.start < table.start || entry.start >= table.end) {
+				return errDataLayout
+			}
+		}
+		return d.splice(table.start, table.end, "")
+	}
+	return errDataLayout
+}
+
+// insertEntry 在最近的已有表中添加键,缺少的中间表用点分键表示 / Add a key to the closest existing table, spelling missing intermediate tables as a dotted key
+func (d *tomlDocument) insertEntry(op dataOp) error {
+	best := -1
+	for i, table := range d.tables {
+		if len(table.path) < len(op.loc) && locHasPrefix(op.loc, table.path) && (best < 0 || len(table.path) > len(d.tables[best].path)) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return errDataLayout
+	}
+	table := d.tables[best]
+	keys := make([]string, 0, len(op.loc)-len(table.path))
+	for _, key := range op.loc[len(table.path):] {
+		name, ok := key.(string)
+		if !ok {
+			return errDataLayout
+		}
+		keys = append(keys, tomlKey(name))
+	}
+	text, err := tomlValue(op.value)
+	if err != nil {
+		return err
+	}
+
+	newline := "\n"
+	if bytes.Contains(d.data, []byte("\r\n")) {
+		newline = "\r\n"
+	}
+	line := strings.Join(keys, ".") + " = " + text + newline
+	at := table.insertAt
+	if at == len(d.data) && at > 0 && d.data[at-1] != '\n' {
+		line = newline + line
+	}
+	return d.splice(at, at, line)
+}
+
+// splice 替换一段原文并重新解析 / Replace a range of the text and parse it again
+func (d *tomlDocument) splice(start, end int, text string) error {
+	data := make([]byte, 0, len(d.data)-(end-start)+len(text))
+	data = append(append(append(data, d.data[:start]...), text...), d.data[end:]...)
+	updated, err := parseTOMLDocument(data)
+	if err != nil {
+		return errDataLayout
+	}
+	*d = *updated
+	return nil
+}
+
+// rebuild 由数据树重新编码,注释和原有顺序不会保留 / Encode the data tree again; comments and the original order are not kept
+func (d *tomlDocument) rebuild(_ []dataOp, expected any) ([]byte, error) {
+	if _, ok := expected.(*dataObject); !ok {
+		return nil, errors.New("the root of a TOML document must be a table")
+	}
+	return toml.Marshal(plainData(expected))
+}
+
+// plainData 将数据树转换为映射和切片 / Convert a data tree into maps and slices
+func plainData(v any) any {
+	switch t := v.(type) {
+	case *dataObject:
+		m := make(map[string]any, len(t.keys))
+		for key, value := range t.values {
+			m[key] = plainData(value)
+		}
+		return m
+	case []any:
+		arr := make([]any, len(t))
+		for i, item := range t {
+			arr[i] = plainData(item)
+		}
+		return arr
+	}
+	return v
+}
+
+// tomlValue 将值编码为TOML内联值 / Encode a value as an inline TOML value
+func tomlValue(v any) (string, error) {
+	switch t := v.(type) {
+	case nil:
+		return "", errors.New("TOML cannot represent null values")
+	case bool:
+		return strconv.FormatBool(t), nil
+	case int64:
+		return strconv.FormatInt(t, 10), nil
+	case float64:
+		switch {
+		case math.IsInf(t, 1):
+			return "inf", nil
+		case math.IsInf(t, -1):
+			return "-inf", nil
+		case math.IsNaN(t):
+			return "nan", nil
+		}
+		s := strconv.FormatFloat(t, 'g', -1, 64)
+		if !strings.ContainsAny(s, ".e") {
+			s += ".0"
+		}
+		return s, nil
+	case string:
+		return tomlString(t), nil
+	case []any:
+		parts := make([]string, 0, len(t))
+		for _, item := range t {
+			part, err := tomlValue(item)
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, part)
+		}
+		return "[" + strings.Join(parts, ", ") + "]", nil
+	case *dataObject:
+		if len(t.keys) == 0 {
+			return "{}", nil
+		}
+		parts := make([]string, 0, len(t.keys))
+		for _, key := range t.keys {
+			part, err := tomlValue(t.values[key])
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, tomlKey(key)+" = "+part)
+		}
+		return "{ " + strings.Join(parts, ", ") + " }", nil
+	case encoding.TextMarshaler:
+		text, err := t.MarshalText()
+		return string(text), err
+	}
+	return "", fmt.Errorf("unsupported value type %T", v)
+}
+
+// tomlKey 返回键的TOML写法,必要时加引号 / Return the TOML spelling of a key, quoting it when needed
+func tomlKey(key string) string {
+	if key == "" {
+		return `""`
+	}
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		if !(c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-' || c == '_') {
+			return tomlString(key)
+		}
+	}
+	return key
+}
+
+// tomlString 编码TOML基本字符串 / Encode a TOML basic string
+func tomlString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 || r == 0x7F {
+				fmt.Fprintf(&b, `\u%04X`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// locEqual 两个位置是否相同 / Whether two locations are equal
+func locEqual(a, b []any) bool {
+	return len(a) == len(b) && locHasPrefix(a, b)
+}
+
+// locHasPrefix 位置是否以前缀开始 / Whether a location starts with a prefix
+func locHasPrefix(loc, prefix []any) bool {
+	if len(prefix) > len(loc) {
+		return false
+	}
+	for i := range prefix {
+		if loc[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,678 @@
+// Copyright 2024 MCP Toolkit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sandbox
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// yamlDocument 可原位修改的YAML文件,多文档流的每个文档是根数组的一个元素
+// YAML file that can be changed in place; each document of a multi-document stream is an element of the root array
+type yamlDocument struct {
+	data       []byte
+	docs       []*yaml.Node // 文档节点 / Document nodes
+	value      any
+	indent     int   // 缩进宽度 / Indent width
+	lineStarts []int // 各行的起始位置 / Start offsets of the lines
+}
+
+// parseYAMLDocument 解析YAML文件 / Parse a YAML file
+func parseYAMLDocument(data []byte) (*yamlDocument, error) {
+	d := &yamlDocument{data: data, indent: detectYAMLIndent(data), lineStarts: []int{0}}
+	for i, c := range data {
+		if c == '\n' {
+			d.lineStarts = append(d.lineStarts, i+1)
+		}
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	var values []any
+	for {
+		node := &yaml.Node{}
+		if err := dec.Decode(node); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+		value, err := yamlNodeValue(node, 0)
+		if err != nil {
+			return nil, err
+		}
+		d.docs = append(d.docs, node)
+		values = append(values, value)
+	}
+	switch len(values) {
+	case 0:
+	case 1:
+		d.value = values[0]
+	default:
+		d.value = values
+	}
+	return d, nil
+}
+
+// detectYAMLIndent 根据第一个嵌套映射检测缩进宽度,默认为2 / Detect the indent width from the first nested mapping, defaulting to 2
+func detectYAMLIndent(data []byte) int {
+	lines := strings.Split(string(data), "\n")
+	for i, line := range lines {
+		line = strings.TrimRight(line, " \r")
+		trimmed := strings.TrimLeft(line, " ")
+		if !strings.HasSuffix(trimmed, ":") || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		base := len(line) - len(trimmed)
+		if strings.HasPrefix(trimmed, "- ") {
+			base += 2
+		}
+		for _, next := range lines[i+1:] {
+			next = strings.TrimRight(next, " \r")
+			nextTrimmed := strings.TrimLeft(next, " ")
+			if nextTrimmed == "" || nextTrimmed[0] == '#' {
+				continue
+			}
+			if n := len(next) - len(nextTrimmed) - base; n >= 2 && n <= 8 {
+				return n
+			}
+			break
+		}
+	}
+	return 2
+}
+
+// yamlNodeValue 将节点转换为数据树,展开别名和合并键 / Convert a node into a data tree, expanding aliases and merge keys
+func yamlNodeValue(n *yaml.Node, depth int) (any, error) {
+	if depth > maxDataDepth {
+		return nil, fmt.Errorf("exceeded maximum nesting depth of %d", maxDataDepth)
+	}
+	switch n.Kind {
+	case yaml.DocumentNode:
+		if len(n.Content) == 0 {
+			return nil, nil
+		}
+		return yamlNodeValue(n.Content[0], depth+1)
+	case yaml.AliasNode:
+		return yamlNodeValue(n.Alias, depth+1)
+	case yaml.SequenceNode:
+		arr := make([]any, 0, len(n.Content))
+		for _, item := range n.Content {
+			value, err := yamlNodeValue(item, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, value)
+		}
+		return arr, nil
+	case yaml.MappingNode:
+		obj := newDataObject()
+		var merges []*yaml.Node
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key, value := n.Content[i], n.Content[i+1]
+			if key.Kind == yaml.ScalarNode && key.ShortTag() == "!!merge" {
+				merges = append(merges, value)
+				continue
+			}
+			if key.Kind != yaml.ScalarNode {
+				return nil, fmt.Errorf("line %d: only scalar mapping keys are supported", key.Line)
+			}
+			v, err := yamlNodeValue(value, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			obj.set(key.Value, v)
+		}
+		// 合并键提供的成员不覆盖显式成员 / Members from merge keys do not override explicit members
+		for _, merge := range merges {
+			sources := []*yaml.Node{merge}
+			if merge.Kind == yaml.SequenceNode {
+				sources = merge.Content
+			}
+			for _, source := range sources {
+				value, err := yamlNodeValue(source, depth+1)
+				if err != nil {
+					return nil, err
+				}
+				merged, ok := value.(*dataObject)
+				if !ok {
+					return nil, fmt.Errorf("line %d: merge key requires a mapping", merge.Line)
+				}
+				for _, key := range merged.keys {
+					if _, exists := obj.get(key); !exists {
+						obj.set(key, merged.values[key])
+					}
+				}
+			}
+		}
+		return obj, nil
+	default:
+		var value any
+		if err := n.Decode(&value); err != nil {
+			return nil, err
+		}
+		return normalizeData(value), nil
+	}
+}
+
+// yamlValueNode 将数据树转换为节点 / Convert a data tree into a node
+func yamlValueNode(v any) *yaml.Node {
+	switch t := v.(type) {
+	case *dataObject:
+		n := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		for _, key := range t.keys {
+			n.Content = append(n.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, yamlValueNode(t.values[key]))
+		}
+		return n
+	case []any:
+		n := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, item := range t {
+			n.Content = append(n.Content, yamlValueNode(item))
+		}
+		return n
+	case nil:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(t)}
+	case int64:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.FormatInt(t, 10)}
+	case float64:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!float", Value: formatYAMLFloat(t)}
+	case string:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: t}
+	}
+	n := &yaml.Node{}
+	if err := n.Encode(v); err != nil {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: fmt.Sprint(v)}
+	}
+	return n
+}
+
+// formatYAMLFloat 格式化浮点数,保证读回时仍是浮点数 / Format a float so that it reads back as a float
+func formatYAMLFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return ".inf"
+	case math.IsInf(f, -1):
+		return "-.inf"
+	case math.IsNaN(f):
+		return ".nan"
+	}
+	s := strconv.FormatFloat(f, 'g', -1, 64)
+	if !strings.ContainsAny(s, ".e") {
+		s += ".0"
+	}
+	return s
+}
+
+// keepYAMLStyle 将被替换节点的注释和引号风格带到新节点 / Carry the comments and quoting style of a replaced node over to the new node
+func keepYAMLStyle(old, n *yaml.Node) {
+	n.HeadComment, n.LineComment, n.FootComment = old.HeadComment, old.LineComment, old.FootComment
+	switch {
+	case old.Kind == yaml.ScalarNode && n.Kind == yaml.ScalarNode && n.Tag == "!!str":
+		n.Style = old.Style & (yaml.DoubleQuotedStyle | yaml.SingleQuotedStyle)
+	case old.Kind != yaml.ScalarNode && n.Kind != yaml.ScalarNode:
+		n.Style = old.Style & yaml.FlowStyle
+	}
+}
+
+// yamlChild 返回集合中键对应的值节点及其在Content中的下标 / Return the value node for a key of a collection and its index in Content
+func yamlChild(n *yaml.Node, key any) (*yaml.Node, int) {
+	switch n.Kind {
+	case yaml.MappingNode:
+		if k, ok := key.(string); ok {
+			for i := len(n.Content) - 2; i >= 0; i -= 2 {
+				if n.Content[i].Kind == yaml.ScalarNode && n.Content[i].Value == k && n.Content[i].ShortTag() != "!!merge" {
+					return n.Content[i+1], i + 1
+				}
+			}
+		}
+	case yaml.SequenceNode:
+		if i, ok := key.(int); ok && i >= 0 && i < len(n.Content) {
+			return n.Content[i], i
+		}
+	}
+	return nil, -1
+}
+
+// docRoot 返回位置所在文档的节点和文档内的位置 / Return the document node a location falls in and the location within that document
+func (d *yamlDocument) docRoot(loc []any) (*yaml.Node, []any, error) {
+	switch {
+	case len(d.docs) == 1:
+		return d.docs[0], loc, nil
+	case len(d.docs) > 1 && len(loc) > 0:
+		if i, ok := loc[0].(int); ok && i < len(d.docs) {
+			return d.docs[i], loc[1:], nil
+		}
+	}
+	return nil, nil, errDataLayout
+}
+
+func (d *yamlDocument) tree() any {
+	return d.value
+}
+
+func (d *yamlDocument) bytes() []byte {
+	return d.data
+}
+
+// apply 在原文中替换标量、向块集合添加条目或删除条目,其他修改返回errDataLayout
+// Replace scalars, add entries to block collections or remove entries in the original text; other changes return errDataLayout
+func (d *yamlDocument) apply(op dataOp) error {
+	doc, loc, err := d.docRoot(op.loc)
+	if err != nil || len(doc.Content) == 0 {
+		return errDataLayout
+	}
+	var parent *yaml.Node
+	node, index, depth := doc.Content[0], -1, 0
+	for depth < len(loc) {
+		if node.Kind == yaml.AliasNode {
+			return errDataLayout
+		}
+		child, i := yamlChild(node, loc[depth])
+		if child == nil {
+			break
+		}
+		parent, node, index = node, child, i
+		depth++
+	}
+
+	switch {
+	case op.delete:
+		if depth < len(loc) || parent == nil {
+			return errDataLayout
+		}
+		return d.removeEntry(parent, index)
+	case depth == len(loc):
+		return d.replaceScalar(node, op.value)
+	default:
+		return d.insertEntry(node, loc[depth], wrapDataValue(loc[depth+1:], op.value))
+	}
+}
+
+// replaceScalar 替换单行标量,保留其引号风格和注释 / Replace a single-line scalar, keeping its quoting style and comments
+func (d *yamlDocument) replaceScalar(n *yaml.Node, value any) error {
+	switch value.(type) {
+	case *dataObject, []any:
+		return errDataLayout
+	}
+	if n.Kind != yaml.ScalarNode || n.Anchor != "" || n.Style&^(yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle) != 0 || n.Value == "" && n.Style == 0 {
+		return errDataLayout
+	}
+	start := d.offset(n.Line, n.Column)
+	end := d.scalarEnd(n, start)
+	if end < 0 {
+		return errDataLayout
+	}
+
+	replacement := yamlValueNode(value)
+	keepYAMLStyle(n, replacement)
+	replacement.HeadComment, replacement.LineComment, replacement.FootComment = "", "", ""
+	text, err := yaml.Marshal(replacement)
+	if err != nil {
+		return err
+	}
+	text = bytes.TrimSuffix(text, []byte("\n"))
+	if bytes.IndexByte(text, '\n') >= 0 {
+		return errDataLayout
+	}
+	return d.splice(start, end, string(text))
+}
+
+// scalarEnd 返回单行标量在原文中的结束位置,多行标量返回-1 / Return the end offset of a single-line scalar in the text, or -1 for multi-line scalars
+func (d *yamlDocument) scalarEnd(n *yaml.Node, start int) int {
+	lineEnd := bytes.IndexByte(d.data[start:], '\n')
+	if lineEnd < 0 {
+		lineEnd = len(d.data)
+	} else {
+		lineEnd += start
+	}
+	line := d.data[start:lineEnd]
+	switch n.Style {
+	case yaml.DoubleQuotedStyle:
+		for i := 1; i < len(line); i++ {
+			switch line[i] {
+			case '\\':
+				i++
+			case '"':
+				return start + i + 1
+			}
+		}
+	case yaml.SingleQuotedStyle:
+		for i := 1; i < len(line); i++ {
+			if line[i] == '\'' {
+				if i+1 < len(line) && line[i+1] == '\'' {
+					i++
+					continue
+				}
+				return start + i + 1
+			}
+		}
+	default:
+		if bytes.HasPrefix(line, []byte(n.Value)) && strings.IndexByte(n.Value, '\n') < 0 {
+			return start + len(n.Value)
+		}
+	}
+	return -1
+}
+
+// insertEntry 在块映射末尾添加键或在块序列末尾追加元素 / Add a key at the end of a block mapping or append an element to a block sequence
+func (d *yamlDocument) insertEntry(container *yaml.Node, key any, value any) error {
+	if container.Style&yaml.FlowStyle != 0 || len(container.Content) == 0 {
+		return errDataLayout
+	}
+	var entry *yaml.Node
+	var indent int
+	switch container.Kind {
+	case yaml.MappingNode:
+		name, ok := key.(string)
+		if !ok {
+			return errDataLayout
+		}
+		indent = container.Content[0].Column - 1
+		entry = &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{
+			{Kind: yaml.ScalarNode, Tag: "!!str", Value: name}, yamlValueNode(value),
+		}}
+	case yaml.SequenceNode:
+		var ok bool
+		if indent, ok = d.dashIndent(container.Content[0]); !ok || key != len(container.Content) {
+			return errDataLayout
+		}
+		entry = &yaml.Node{Kind: yaml.SequenceNode, Content: []*yaml.Node{yamlValueNode(value)}}
+	default:
+		return errDataLayout
+	}
+
+	text, err := d.render(entry, indent)
+	if err != nil {
+		return err
+	}
+	at := d.entryEnd(container.Content[len(container.Content)-1], indent)
+	if at == len(d.data) && len(d.data) > 0 && d.data[len(d.data)-1] != '\n' {
+		text = d.newline() + text
+	}
+	return d.splice(at, at, text)
+}
+
+// removeEntry 删除块映射的键或块序列的元素,连同映射键上方的注释 / Remove a key of a block mapping or an element of a block sequence, along with the comment above a mapping key
+func (d *yamlDocument) removeEntry(container *yaml.Node, index int) error {
+	if container.Style&yaml.FlowStyle != 0 {
+		return errDataLayout
+	}
+	var first *yaml.Node
+	var indent int
+	switch container.Kind {
+	case yaml.MappingNode:
+		if len(container.Content) <= 2 {
+			return errDataLayout
+		}
+		first = container.Content[index-1]
+		indent = first.Column - 1
+		if !d.startsLine(first.Line, indent) {
+			return errDataLayout
+		}
+	case yaml.SequenceNode:
+		if len(container.Content) <= 1 {
+			return errDataLayout
+		}
+		first = container.Content[index]
+		var ok bool
+		if indent, ok = d.dashIndent(first); !ok {
+			return errDataLayout
+		}
+	default:
+		return errDataLayout
+	}
+
+	startLine := first.Line
+	if first.HeadComment != "" {
+		for startLine > 1 && strings.HasPrefix(strings.TrimLeft(d.line(startLine-1), " "), "#") {
+			startLine--
+		}
+	}
+	return d.splice(d.lineStart(startLine), d.entryEnd(container.Content[index], indent), "")
+}
+
+// entryEnd 返回条目最后一行之后的位置,缩进更深的续行属于该条目 / Return the offset after the last line of an entry; deeper indented continuation lines belong to it
+func (d *yamlDocument) entryEnd(n *yaml.Node, indent int) int {
+	last := yamlLastLine(n)
+	for line := last + 1; line <= d.lineCount(); line++ {
+		text := d.line(line)
+		trimmed := strings.TrimLeft(text, " ")
+		if trimmed == "" || trimmed[0] == '#' {
+			continue
+		}
+		if len(text)-len(trimmed) <= indent {
+			break
+		}
+		last = line
+	}
+	return d.lineStart(last + 1)
+}
+
+// yamlLastLine 返回节点及其后代所在的最大行号 / Return the largest line number of a node and its descendants
+func yamlLastLine(n *yaml.Node) int {
+	last := n.Line
+	for _, child := range n.Content {
+		last = max(last, yamlLastLine(child))
+	}
+	return last
+}
+
+// dashIndent 返回序列元素前"-"的列,要求它是该行第一个非空白字符 / Return the column of the "-" before a sequence item, which must be the first non-blank character of its line
+func (d *yamlDocument) dashIndent(item *yaml.Node) (int, bool) {
+	line := d.line(item.Line)
+	col := len(line) - len(strings.TrimLeft(line, " "))
+	if strings.HasPrefix(line[col:], "-") && d.offset(item.Line, item.Column) > d.lineStart(item.Line)+col {
+		return col, true
+	}
+	return 0, false
+}
+
+// startsLine 行是否以指定缩进开始 / Whether a line starts at the given indentation
+func (d *yamlDocument) startsLine(line, indent int) bool {
+	text := d.line(line)
+	return len(text)-len(strings.TrimLeft(text, " ")) == indent
+}
+
+// render 以块风格编码节点并为每行加上缩进 / Encode a node in block style and indent every line
+func (d *yamlDocument) render(n *yaml.Node, indent int) (string, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(d.indent)
+	if err := enc.Encode(n); err != nil {
+		return "", err
+	}
+	if err := enc.Close(); err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	prefix := strings.Repeat(" ", indent)
+	for _, line := range strings.SplitAfter(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+		b.WriteString(prefix + strings.TrimSuffix(line, "\n") + d.newline())
+	}
+	return b.String(), nil
+}
+
+// newline 返回原文使用的换行符 / Return the line terminator used by the text
+func (d *yamlDocument) newline() string {
+	if bytes.Contains(d.data, []byte("\r\n")) {
+		return "\r\n"
+	}
+	return "\n"
+}
+
+// lineCount 返回行数 / Return the number of lines
+func (d *yamlDocument) lineCount() int {
+	if d.lineStarts[len(d.lineStarts)-1] == len(d.data) {
+		return len(d.lineStarts) - 1
+	}
+	return len(d.lineStarts)
+}
+
+// lineStart 返回行(从1开始)的起始位置,超出末行时返回文本长度 / Return the start offset of a 1-based line, or the text length past the last line
+func (d *yamlDocument) lineStart(line int) int {
+	if line-1 < len(d.lineStarts) {
+		return d.lineStarts[line-1]
+	}
+	return len(d.data)
+}
+
+// line 返回行的内容,不含换行符 / Return the content of a line without its terminator
+func (d *yamlDocument) line(line int) string {
+	text := d.data[d.lineStart(line):d.lineStart(line+1)]
+	return strings.TrimRight(string(text), "\r\n")
+}
+
+// offset 将行号和按字符计的列号转换为字节位置 / Convert a line and a column counted in characters into a byte offset
+func (d *yamlDocument) offset(line, column int) int {
+	start := d.lineStart(line)
+	pos := start
+	for i := 1; i < column && pos < len(d.data); i++ {
+		_, size := utf8.DecodeRune(d.data[pos:])
+		pos += size
+	}
+	return pos
+}
+
+// splice 替换一段原文并重新解析 / Replace a range of the text and parse it again
+func (d *yamlDocument) splice(start, end int, text string) error {
+	data := make([]byte, 0, len(d.data)-(end-start)+len(text))
+	data = append(append(append(data, d.data[:start]...), text...), d.data[end:]...)
+	updated, err := parseYAMLDocument(data)
+	if err != nil {
+		return errDataLayout
+	}
+	updated.indent = d.indent
+	*d = *updated
+	return nil
+}
+
+// rebuild 在节点树上应用修改后重新编码,注释得以保留但布局可能改变
+// Apply the changes to the node tree and encode it again; comments are kept but the layout may change
+func (d *yamlDocument) rebuild(ops []dataOp, expected any) ([]byte, error) {
+	for _, op := range ops {
+		if err := d.applyNode(op); err != nil {
+			if !errors.Is(err, errDataLayout) {
+				return nil, err
+			}
+			// 无法对应到节点时由数据树生成 / Fall back to the data tree when the change cannot be mapped onto the nodes
+			values := []any{expected}
+			if arr, ok := expected.([]any); ok && len(d.docs) > 1 {
+				values = arr
+			}
+			d.docs = d.docs[:0]
+			for _, value := range values {
+				d.docs = append(d.docs, &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{yamlValueNode(value)}})
+			}
+			break
+		}
+	}
+
+	var buf bytes.Buffer
+	if bytes.HasPrefix(d.data, []byte("---")) {
+		buf.WriteString("---\n")
+	}
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(d.indent)
+	for _, doc := range d.docs {
+		if err := enc.Encode(doc); err != nil {
+			return nil, err
+		}
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	out := buf.Bytes()
+	if d.newline() == "\r\n" {
+		out = bytes.ReplaceAll(out, []byte("\n"), []byte("\r\n"))
+	}
+	return out, nil
+}
+
+// applyNode 在节点树上应用一次修改 / Apply one change to the node tree
+func (d *yamlDocument) applyNode(op dataOp) error {
+	// 多文档流中整个文档的删除和追加 / Deleting and appending whole documents of a multi-document stream
+	if len(d.docs) > 1 && len(op.loc) == 1 {
+		switch i := op.loc[0].(int); {
+		case op.delete:
+			d.docs = append(d.docs[:i], d.docs[i+1:]...)
+			return nil
+		case i == len(d.docs):
+			d.docs = append(d.docs, &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{yamlValueNode(op.value)}})
+			return nil
+		}
+	}
+	doc, loc, err := d.docRoot(op.loc)
+	if err != nil || len(doc.Content) == 0 {
+		return errDataLayout
+	}
+	parent, index := doc, 0
+	node := doc.Content[0]
+	for i, key := range loc {
+		if node.Kind == yaml.AliasNode {
+			return fmt.Errorf("cannot change %s: the path goes through a YAML alias", formatDataPath(op.loc))
+		}
+		child, ci := yamlChild(node, key)
+		if child != nil {
+			parent, node, index = node, child, ci
+			continue
+		}
+		if op.delete {
+			return errDataLayout
+		}
+		switch value := wrapDataValue(loc[i+1:], op.value); node.Kind {
+		case yaml.MappingNode:
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key.(string)}, yamlValueNode(value))
+		case yaml.SequenceNode:
+			node.Content = append(node.Content, yamlValueNode(value))
+		default:
+			replaceYAMLNode(parent, index, yamlValueNode(wrapDataValue(loc[i:], op.value)))
+		}
+		return nil
+	}
+
+	if op.delete {
+		if parent.Kind == yaml.MappingNode {
+			parent.Content = append(parent.Content[:index-1], parent.Content[index+1:]...)
+		} else {
+			parent.Content = append(parent.Content[:index], parent.Content[index+1:]...)
+		}
+		return nil
+	}
+	replaceYAMLNode(parent, index, yamlValueNode(op.value))
+	return nil
+}
+
+// replaceYAMLNode 替换集合中的节点,集合取代标量时行尾注释移到映射键上
+// Replace a node of a collection; when a collection replaces a scalar its line comment moves to the mapping key
+func replaceYAMLNode(parent *yaml.Node, index int, replacement *yaml.Node) {
+	old := parent.Content[index]
+	keepYAMLStyle(old, replacement)
+	if replacement.Kind != yaml.ScalarNode && old.Kind == yaml.ScalarNode && parent.Kind == yaml.MappingNode {
+		if key := parent.Content[index-1]; key.LineComment == "" {
+			key.LineComment = replacement.LineComment
+		}
+		replacement.LineComment = ""
+	}
+	parent.Content[index] = replacement
+}
@@ -0,0 +1,826 @@
+// Copyright 2024 MCP Toolkit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sandbox
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// 选择器类型 / Selector kinds
+const (
+	selectName     = iota // 对象成员 / Object member
+	selectIndex           // 数组元素,负数从末尾计 / Array element, negative counts from the end
+	selectWildcard        // 所有子节点 / Every child
+	selectSlice           // 数组切片 / Array slice
+	selectFilter          // 过滤表达式 / Filter expression
+)
+
+// dataPath 解析后的JSONPath风格表达式,如 $.servers[?(@.port > 8000)].name
+// A parsed JSONPath-style expression such as $.servers[?(@.port > 8000)].name
+type dataPath struct {
+	segments []pathSegment
+}
+
+// pathSegment 路径中的一段,多个选择器构成并集 / One segment of a path; several selectors form a union
+type pathSegment struct {
+	recursive bool           // 由..引入,作用于所有后代 / Introduced by .., applies to every descendant
+	selectors []pathSelector // 选择器 / Selectors
+}
+
+// pathSelector 单个选择器 / A single selector
+type pathSelector struct {
+	kind   int
+	name   string
+	index  int
+	slice  [3]*int // 起始、结束和步长 / Start, end and step
+	filter *filterExpr
+}
+
+// dataNode 表达式选中的一个值及其位置 / A value selected by an expression together with its location
+type dataNode struct {
+	loc   []any // 字符串键和整数下标 / String keys and integer indexes
+	value any
+}
+
+// filterExpr 过滤表达式节点 / Filter expression node
+type filterExpr struct {
+	op          string // ||、&&、!、比较运算符,空串表示存在性测试 / ||, &&, !, a comparison operator, or empty for an existence test
+	left, right *filterExpr
+	lhs, rhs    *filterOperand
+}
+
+// filterOperand 比较的操作数 / Comparison operand
+type filterOperand struct {
+	isPath   bool          // 是否为路径,单独的@或$没有段 / Whether this is a path; a bare @ or $ has no segments
+	path     []pathSegment // 路径操作数 / Path operand
+	relative bool          // @开头的路径相对于当前节点 / Paths starting with @ are relative to the current node
+	literal  any           // 字面量 / Literal
+}
+
+// pathParser 表达式解析器 / Expression parser
+type pathParser struct {
+	src string
+	pos int
+}
+
+// parseDataPath 解析表达式,$可以省略 / Parse an expression; the leading $ is optional
+func parseDataPath(expr string) (*dataPath, error) {
+	p := &pathParser{src: strings.TrimSpace(expr)}
+	if p.src == "" {
+		return nil, errors.New("query cannot be empty")
+	}
+
+	var segments []pathSegment
+	if p.src[0] == '$' {
+		p.pos++
+	} else if p.src[0] != '.' && p.src[0] != '[' {
+		// 省略$时以成员名开头 / Without the $ the expression starts with a member name
+		name, err := p.parseName()
+		if err != nil {
+			return nil, err
+		}
+		segments = append(segments, pathSegment{selectors: []pathSelector{{kind: selectName, name: name}}})
+	}
+
+	rest, err := p.parseSegments()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.src) {
+		return nil, p.errorf("unexpected %q", p.src[p.pos:p.pos+1])
+	}
+	return &dataPath{segments: append(segments, rest...)}, nil
+}
+
+// errorf 带位置的解析错误 / Parse error carrying the position
+func (p *pathParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("invalid query at offset %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+// skipSpace 跳过空白 / Skip whitespace
+func (p *pathParser) skipSpace() {
+	for p.pos < len(p.src) && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t' || p.src[p.pos] == '\n' || p.src[p.pos] == '\r') {
+		p.pos++
+	}
+}
+
+// parseSegments 解析点和方括号段,遇到其他字符时停止 / Parse dot and bracket segments, stopping at any other character
+func (p *pathParser) parseSegments() ([]pathSegment, error) {
+	var segments []pathSegment
+	for p.pos < len(p.src) {
+		var seg pathSegment
+		switch {
+		case strings.HasPrefix(p.src[p.pos:], ".."):
+			p.pos += 2
+			seg.recursive = true
+			if p.pos < len(p.src) && p.src[p.pos] == '[' {
+				selectors, err := p.parseBracket()
+				if err != nil {
+					return nil, err
+				}
+				seg.selectors = selectors
+			} else {
+				selector, err := p.parseDotSelector()
+				if err != nil {
+					return nil, err
+				}
+				seg.selectors = []pathSelector{selector}
+			}
+		case p.src[p.pos] == '.':
+			p.pos++
+			selector, err := p.parseDotSelector()
+			if err != nil {
+				return nil, err
+			}
+			seg.selectors = []pathSelector{selector}
+		case p.src[p.pos] == '[':
+			selectors, err := p.parseBracket()
+			if err != nil {
+				return nil, err
+			}
+			seg.selectors = selectors
+		default:
+			return segments, nil
+		}
+		segments = append(segments, seg)
+	}
+	return segments, nil
+}
+
+// parseDotSelector 解析点号后的成员名或通配符 / Parse the member name or wildcard after a dot
+func (p *pathParser) parseDotSelector() (pathSelector, error) {
+	if p.pos < len(p.src) && p.src[p.pos] == '*' {
+		p.pos++
+		return pathSelector{kind: selectWildcard}, nil
+	}
+	name, err := p.parseName()
+	if err != nil {
+		return pathSelector{}, err
+	}
+	return pathSelector{kind: selectName, name: name}, nil
+}
+
+// parseName 解析点号形式的成员名,含特殊字符的名称需使用['...'] / Parse a dotted member name; names with special characters need ['...']
+func (p *pathParser) parseName() (string, error) {
+	start := p.pos
+	for p.pos < len(p.src) && !strings.ContainsRune(".[]()!=<>&|,'\" \t\r\n", rune(p.src[p.pos])) {
+		p.pos++
+	}
+	if p.pos == start {
+		return "", p.errorf("expected a member name")
+	}
+	return p.src[start:p.pos], nil
+}
+
+// parseBracket 解析方括号中逗号分隔的选择器 / Parse the comma separated selectors in brackets
+func (p *pathParser) parseBracket() ([]pathSelector, error) {
+	p.pos++ // [
+	var selectors []pathSelector
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.src) {
+			return nil, p.errorf("unterminated [")
+		}
+		var selector pathSelector
+		switch c := p.src[p.pos]; {
+		case c == '*':
+			p.pos++
+			selector.kind = selectWildcard
+		case c == '?':
+			p.pos++
+			filter, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			selector = pathSelector{kind: selectFilter, filter: filter}
+		case c == '\'' || c == '"':
+			name, err := p.parseString()
+			if err != nil {
+				return nil, err
+			}
+			selector = pathSelector{kind: selectName, name: name}
+		case c == '-' || c == ':' || (c >= '0' && c <= '9'):
+			var err error
+			if selector, err = p.parseIndexOrSlice(); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, p.errorf("unexpected %q in brackets", string(c))
+		}
+		selectors = append(selectors, selector)
+
+		p.skipSpace()
+		if p.pos >= len(p.src) {
+			return nil, p.errorf("unterminated [")
+		}
+		switch p.src[p.pos] {
+		case ',':
+			p.pos++
+		case ']':
+			p.pos++
+			return selectors, nil
+		default:
+			return nil, p.errorf("expected ',' or ']'")
+		}
+	}
+}
+
+// parseIndexOrSlice 解析下标或 start:end:step 切片 / Parse an index or a start:end:step slice
+func (p *pathParser) parseIndexOrSlice() (pathSelector, error) {
+	var parts [3]*int
+	n := 0
+	for {
+		p.skipSpace()
+		start := p.pos
+		if p.pos < len(p.src) && p.src[p.pos] == '-' {
+			p.pos++
+		}
+		for p.pos < len(p.src) && p.src[p.pos] >= '0' && p.src[p.pos] <= '9' {
+			p.pos++
+		}
+		if p.pos > start {
+			v, err := strconv.Atoi(p.src[start:p.pos])
+			if err != nil {
+				return pathSelector{}, p.errorf("invalid index %q", p.src[start:p.pos])
+			}
+			parts[n] = &v
+		}
+		p.skipSpace()
+		if p.pos >= len(p.src) || p.src[p.pos] != ':' {
+			break
+		}
+		if n++; n > 2 {
+			return pathSelector{}, p.errorf("too many ':' in slice")
+		}
+		p.pos++
+	}
+
+	if n == 0 {
+		if parts[0] == nil {
+			return pathSelector{}, p.errorf("expected an index")
+		}
+		return pathSelector{kind: selectIndex, index: *parts[0]}, nil
+	}
+	if parts[2] != nil && *parts[2] == 0 {
+		return pathSelector{}, p.errorf("slice step cannot be zero")
+	}
+	return pathSelector{kind: selectSlice, slice: parts}, nil
+}
+
+// parseString 解析单引号或双引号字符串 / Parse a single or double quoted string
+func (p *pathParser) parseString() (string, error) {
+	quote := p.src[p.pos]
+	p.pos++
+	var b strings.Builder
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch {
+		case c == quote:
+			p.pos++
+			return b.String(), nil
+		case c == '\\':
+			if p.pos+1 >= len(p.src) {
+				return "", p.errorf("unterminated string")
+			}
+			p.pos++
+			switch e := p.src[p.pos]; e {
+			case 'b':
+				b.WriteByte('\b')
+			case 'f':
+				b.WriteByte('\f')
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case 'u':
+				if p.pos+5 > len(p.src) {
+					return "", p.errorf("invalid unicode escape")
+				}
+				r, err := strconv.ParseUint(p.src[p.pos+1:p.pos+5], 16, 32)
+				if err != nil {
+					return "", p.errorf("invalid unicode escape")
+				}
+				b.WriteRune(rune(r))
+				p.pos += 4
+			default:
+				b.WriteByte(e)
+			}
+			p.pos++
+		default:
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+// parseOr 解析 || 连接的表达式 / Parse expressions joined by ||
+func (p *pathParser) parseOr() (*filterExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpace()
+		if !strings.HasPrefix(p.src[p.pos:], "||") {
+			return left, nil
+		}
+		p.pos += 2
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &filterExpr{op: "||", left: left, right: right}
+	}
+}
+
+// parseAnd 解析 && 连接的表达式 / Parse expressions joined by &&
+func (p *pathParser) parseAnd() (*filterExpr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpace()
+		if !strings.HasPrefix(p.src[p.pos:], "&&") {
+			return left, nil
+		}
+		p.pos += 2
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &filterExpr{op: "&&", left: left, right: right}
+	}
+}
+
+// parseUnary 解析取反、括号、比较和存在性测试 / Parse negation, parentheses, comparisons and existence tests
+func (p *pathParser) parseUnary() (*filterExpr, error) {
+	p.skipSpace()
+	if p.pos >= len(p.src) {
+		return nil, p.errorf("unexpected end of filter")
+	}
+	switch p.src[p.pos] {
+	case '!':
+		p.pos++
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &filterExpr{op: "!", left: inner}, nil
+	case '(':
+		p.pos++
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		if p.pos >= len(p.src) || p.src[p.pos] != ')' {
+			return nil, p.errorf("expected ')'")
+		}
+		p.pos++
+		return inner, nil
+	}
+
+	lhs, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if strings.HasPrefix(p.src[p.pos:], op) {
+			p.pos += len(op)
+			rhs, err := p.parseOperand()
+			if err != nil {
+				return nil, err
+			}
+			return &filterExpr{op: op, lhs: lhs, rhs: rhs}, nil
+		}
+	}
+	if lhs.path == nil {
+		return nil, p.errorf("expected a comparison after a literal")
+	}
+	return &filterExpr{lhs: lhs}, nil
+}
+
+// parseOperand 解析路径或字面量操作数 / Parse a path or literal operand
+func (p *pathParser) parseOperand() (*filterOperand, error) {
+	p.skipSpace()
+	if p.pos >= len(p.src) {
+		return nil, p.errorf("expected an operand")
+	}
+	switch c := p.src[p.pos]; {
+	case c == '@' || c == '$':
+		p.pos++
+		segments, err := p.parseSegments()
+		if err != nil {
+			return nil, err
+		}
+		return &filterOperand{isPath: true, path: segments, relative: c == '@'}, nil
+	case c == '\'' || c == '"':
+		s, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		return &filterOperand{literal: s}, nil
+	case c == '-' || (c >= '0' && c <= '9'):
+		start := p.pos
+		p.pos++
+		for p.pos < len(p.src) && strings.IndexByte("0123456789.eE+-", p.src[p.pos]) >= 0 {
+			p.pos++
+		}
+		v, err := parseDataNumber(p.src[start:p.pos])
+		if err != nil {
+			return nil, p.errorf("invalid number %q", p.src[start:p.pos])
+		}
+		return &filterOperand{literal: v}, nil
+	}
+	for _, kw := range []struct {
+		word  string
+		value any
+	}{{"true", true}, {"false", false}, {"null", nil}} {
+		if strings.HasPrefix(p.src[p.pos:], kw.word) {
+			p.pos += len(kw.word)
+			return &filterOperand{literal: kw.value}, nil
+		}
+	}
+	return nil, p.errorf("expected an operand")
+}
+
+// parseDataNumber 解析数字,整数保持为int64 / Parse a number, keeping integers as int64
+func parseDataNumber(s string) (any, error) {
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return i, nil
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+// eval 对文档求值,按文档顺序返回选中的节点 / Evaluate against a document, returning the selected nodes in document order
+func (d *dataPath) eval(root any) []dataNode {
+	return evalSegments(d.segments, dataNode{value: root}, root)
+}
+
+// evalSegments 从起始节点依次应用各段 / Apply the segments in turn starting from a node
+func evalSegments(segments []pathSegment, start dataNode, root any) []dataNode {
+	nodes := []dataNode{start}
+	for _, seg := range segments {
+		var next []dataNode
+		for _, node := range nodes {
+			targets := []dataNode{node}
+			if seg.recursive {
+				targets = descendants(node, nil)
+			}
+			for _, target := range targets {
+				for _, selector := range seg.selectors {
+					next = append(next, selector.apply(target, root)...)
+				}
+			}
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+// definite 表达式仅由成员名和下标构成时返回其位置,用于创建尚不存在的值
+// Return the location when the expression consists of member names and indexes only, used to create values that do not exist yet
+func (d *dataPath) definite() ([]any, bool) {
+	loc := make([]any, 0, len(d.segments))
+	for _, seg := range d.segments {
+		if seg.recursive || len(seg.selectors) != 1 {
+			return nil, false
+		}
+		switch s := seg.selectors[0]; s.kind {
+		case selectName:
+			loc = append(loc, s.name)
+		case selectIndex:
+			loc = append(loc, s.index)
+		default:
+			return nil, false
+		}
+	}
+	return loc, true
+}
+
+// children 返回对象成员或数组元素 / Return the members of an object or the elements of an array
+func children(node dataNode) []dataNode {
+	switch v := node.value.(type) {
+	case *dataObject:
+		nodes := make([]dataNode, 0, len(v.keys))
+		for _, key := range v.keys {
+			nodes = append(nodes, dataNode{loc: childLoc(node.loc, key), value: v.values[key]})
+		}
+		return nodes
+	case []any:
+		nodes := make([]dataNode, 0, len(v))
+		for i, item := range v {
+			nodes = append(nodes, dataNode{loc: childLoc(node.loc, i), value: item})
+		}
+		return nodes
+	}
+	return nil
+}
+
+// descendants 按先序返回节点自身及其所有后代 / Return the node and all its descendants in pre-order
+func descendants(node dataNode, nodes []dataNode) []dataNode {
+	nodes = append(nodes, node)
+	for _, child := range children(node) {
+		nodes = descendants(child, nodes)
+	}
+	return nodes
+}
+
+// childLoc 复制父位置并追加键 / Copy the parent location and append a key
+func childLoc(loc []any, key any) []any {
+	child := make([]any, len(loc), len(loc)+1)
+	copy(child, loc)
+	return append(child, key)
+}
+
+// apply 将选择器应用到一个节点 / Apply the selector to one node
+func (s pathSelector) apply(node dataNode, root any) []dataNode {
+	switch s.kind {
+	case selectName:
+		if obj, ok := node.value.(*dataObject); ok {
+			if v, ok := obj.get(s.name); ok {
+				return []dataNode{{loc: childLoc(node.loc, s.name), value: v}}
+			}
+		}
+	case selectIndex:
+		if arr, ok := node.value.([]any); ok {
+			i := s.index
+			if i < 0 {
+				i += len(arr)
+			}
+			if i >= 0 && i < len(arr) {
+				return []dataNode{{loc: childLoc(node.loc, i), value: arr[i]}}
+			}
+		}
+	case selectWildcard:
+		return children(node)
+	case selectSlice:
+		if arr, ok := node.value.([]any); ok {
+			var nodes []dataNode
+			for _, i := range sliceIndexes(len(arr), s.slice) {
+				nodes = append(nodes, dataNode{loc: childLoc(node.loc, i), value: arr[i]})
+			}
+			return nodes
+		}
+	case selectFilter:
+		var nodes []dataNode
+		for _, child := range children(node) {
+			if s.filter.test(child, root) {
+				nodes = append(nodes, child)
+			}
+		}
+		return nodes
+	}
+	return nil
+}
+
+// sliceIndexes 按RFC 9535的规则计算切片下标 / Compute slice indexes following RFC 9535
+func sliceIndexes(length int, slice [3]*int) []int {
+	step := 1
+	if slice[2] != nil {
+		step = *slice[2]
+	}
+	bound := func(p *int, def int) int {
+		if p == nil {
+			return def
+		}
+		if *p < 0 {
+			return *p + length
+		}
+		return *p
+	}
+
+	var indexes []int
+	if step > 0 {
+		lower := min(max(bound(slice[0], 0), 0), length)
+		upper := min(max(bound(slice[1], length), 0), length)
+		for i := lower; i < upper; i += step {
+			indexes = append(indexes, i)
+		}
+		return indexes
+	}
+	upper := min(max(bound(slice[0], length-1), -1), length-1)
+	lower := min(max(bound(slice[1], -length-1), -1), length-1)
+	for i := upper; i > lower; i += step {
+		indexes = append(indexes, i)
+	}
+	return indexes
+}
+
+// test 对候选节点求值过滤表达式 / Evaluate the filter for a candidate node
+func (f *filterExpr) test(node dataNode, root any) bool {
+	switch f.op {
+	case "||":
+		return f.left.test(node, root) || f.right.test(node, root)
+	case "&&":
+		return f.left.test(node, root) && f.right.test(node, root)
+	case "!":
+		return !f.left.test(node, root)
+	case "":
+		return len(f.lhs.nodes(node, root)) > 0
+	}
+
+	left, leftOK := f.lhs.value(node, root)
+	right, rightOK := f.rhs.value(node, root)
+	if !leftOK || !rightOK {
+		// 不存在的值只与不存在的值相等 / A missing value only equals another missing value
+		switch f.op {
+		case "==":
+			return leftOK == rightOK
+		case "!=":
+			return leftOK != rightOK
+		}
+		return false
+	}
+	switch f.op {
+	case "==":
+		return filterEqual(left, right)
+	case "!=":
+		return !filterEqual(left, right)
+	}
+	cmp, ok := compareData(left, right)
+	if !ok {
+		return false
+	}
+	switch f.op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	default:
+		return cmp >= 0
+	}
+}
+
+// nodes 求值路径操作数 / Evaluate a path operand
+func (o *filterOperand) nodes(node dataNode, root any) []dataNode {
+	start := dataNode{value: root}
+	if o.relative {
+		start = node
+	}
+	return evalSegments(o.path, start, root)
+}
+
+// value 操作数的值,路径未选中恰好一个值时视为不存在 / Value of the operand; a path that does not select exactly one value is missing
+func (o *filterOperand) value(node dataNode, root any) (any, bool) {
+	if !o.isPath {
+		return o.literal, true
+	}
+	nodes := o.nodes(node, root)
+	if len(nodes) != 1 {
+		return nil, false
+	}
+	return nodes[0].value, true
+}
+
+// dataNumber 取数值,CSV等格式中的数字字符串也按数字处理 / Numeric value; numeric strings, as found in CSV, count as numbers too
+func dataNumber(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(n), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// filterEqual 过滤中的相等比较,数字字符串与数字按数值比较 / Equality in filters; numeric strings and numbers compare by value
+func filterEqual(a, b any) bool {
+	_, aString := a.(string)
+	_, bString := b.(string)
+	if aString != bString {
+		an, aOK := dataNumber(a)
+		bn, bOK := dataNumber(b)
+		return aOK && bOK && an == bn
+	}
+	return dataEqual(a, b)
+}
+
+// compareData 比较两个数字或两个字符串 / Compare two numbers or two strings
+func compareData(a, b any) (int, bool) {
+	as, aString := a.(string)
+	bs, bString := b.(string)
+	if aString && bString {
+		return strings.Compare(as, bs), true
+	}
+	an, aOK := dataNumber(a)
+	bn, bOK := dataNumber(b)
+	if !aOK || !bOK {
+		return 0, false
+	}
+	switch {
+	case an < bn:
+		return -1, true
+	case an > bn:
+		return 1, true
+	}
+	return 0, true
+}
+
+// dataEqual 深度比较两个值,对象不考虑键顺序,整数和浮点数按数值比较 / Deep comparison ignoring object key order, comparing integers and floats by value
+func dataEqual(a, b any) bool {
+	switch av := a.(type) {
+	case *dataObject:
+		bv, ok := b.(*dataObject)
+		if !ok || len(av.keys) != len(bv.keys) {
+			return false
+		}
+		for _, key := range av.keys {
+			other, ok := bv.get(key)
+			if !ok || !dataEqual(av.values[key], other) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		bv, ok := b.([]any)
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for i := range av {
+			if !dataEqual(av[i], bv[i]) {
+				return false
+			}
+		}
+		return true
+	case int64, float64:
+		switch b.(type) {
+		case int64, float64:
+			an, _ := dataNumber(a)
+			bn, _ := dataNumber(b)
+			return an == bn
+		}
+		return false
+	}
+	switch b.(type) {
+	case *dataObject, []any:
+		return false
+	}
+	return a == b
+}
+
+// formatDataPath 将位置格式化为规范路径 / Format a location as a normalized path
+func formatDataPath(loc []any) string {
+	var b strings.Builder
+	b.WriteByte('$')
+	for _, key := range loc {
+		switch k := key.(type) {
+		case int:
+			fmt.Fprintf(&b, "[%d]", k)
+		case string:
+			if isPathIdentifier(k) {
+				b.WriteByte('.')
+				b.WriteString(k)
+			} else {
+				b.WriteString("['")
+				b.WriteString(strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(k))
+				b.WriteString("']")
+			}
+		}
+	}
+	return b.String()
+}
+
+// isPathIdentifier 名称可以直接写在点号后 / Whether a name can be written after a dot as is
+func isPathIdentifier(name string) bool {
+	if name == "" {
+		return false
+	}
+	first, _ := utf8.DecodeRuneInString(name)
+	if !unicode.IsLetter(first) && first != '_' {
+		return false
+	}
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '-' {
+			return false
+		}
+	}
+	return true
+}
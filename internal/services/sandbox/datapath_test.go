@@ -0,0 +1,107 @@
+package sandbox
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const dataPathFixture = `{
+  "name": "api",
+  "servers": [
+    {"host": "a.example.com", "port": 8080, "tags": ["blue"]},
+    {"host": "b.example.com", "port": "9090", "enabled": false},
+    {"host": "c.example.com", "port": 7000, "enabled": true}
+  ],
+  "limits": {"cpu": 1.5, "memory": null},
+  "odd key": {"x y": 1}
+}`
+
+// evalDataPath 对测试文档求值,返回规范路径 / Evaluate against the test document, returning normalized paths
+func evalDataPath(t *testing.T, root any, expr string) []string {
+	t.Helper()
+	query, err := parseDataPath(expr)
+	require.NoError(t, err, expr)
+	paths := []string{}
+	for _, node := range query.eval(root) {
+		paths = append(paths, formatDataPath(node.loc))
+	}
+	return paths
+}
+
+func TestDataPathEval(t *testing.T) {
+	root, err := parseJSONValue([]byte(dataPathFixture))
+	require.NoError(t, err)
+
+	tests := []struct {
+		expr string
+		want []string
+	}{
+		{"$", []string{"$"}},
+		{"name", []string{"$.name"}},
+		{"$.servers[0].host", []string{"$.servers[0].host"}},
+		{"servers[-1].port", []string{"$.servers[2].port"}},
+		{"$.servers[*].host", []string{"$.servers[0].host", "$.servers[1].host", "$.servers[2].host"}},
+		{"$.servers[1:].host", []string{"$.servers[1].host", "$.servers[2].host"}},
+		{"$.servers[::-2].host", []string{"$.servers[2].host", "$.servers[0].host"}},
+		{"$.servers[0,2]['host']", []string{"$.servers[0].host", "$.servers[2].host"}},
+		{"$..port", []string{"$.servers[0].port", "$.servers[1].port", "$.servers[2].port"}},
+		{"$.limits.*", []string{"$.limits.cpu", "$.limits.memory"}},
+		{"$['odd key']['x y']", []string{"$['odd key']['x y']"}},
+		{"$.missing.key", []string{}},
+		{"$.servers[5]", []string{}},
+
+		// 过滤表达式 / Filter expressions
+		{"$.servers[?(@.port > 7500)].host", []string{"$.servers[0].host", "$.servers[1].host"}},
+		{"$.servers[?@.port == 9090].host", []string{"$.servers[1].host"}},
+		{"$.servers[?(@.enabled)].host", []string{"$.servers[1].host", "$.servers[2].host"}},
+		{"$.servers[?(!@.enabled)].host", []string{"$.servers[0].host"}},
+		{"$.servers[?(@.enabled == true && @.port < 8000)].host", []string{"$.servers[2].host"}},
+		{"$.servers[?(@.host == 'a.example.com' || @.enabled == false)].port", []string{"$.servers[0].port", "$.servers[1].port"}},
+		{"$.servers[?(@.tags[0] == 'blue')].host", []string{"$.servers[0].host"}},
+		{"$.servers[?(@.port == $.servers[2].port)].host", []string{"$.servers[2].host"}},
+		{"$.limits[?(@ == null)]", []string{"$.limits.memory"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			assert.Equal(t, tt.want, evalDataPath(t, root, tt.expr))
+		})
+	}
+}
+
+func TestDataPathErrors(t *testing.T) {
+	for _, expr := range []string{"", "$.", "$[", "$[1", "$['x]", "$.a[?(@.b >)]", "$.a]", "$[1:2:0]", "$[?(@.a ==)]"} {
+		_, err := parseDataPath(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestDataPathDefinite(t *testing.T) {
+	query, err := parseDataPath("$.a['b c'][-1]")
+	require.NoError(t, err)
+	loc, ok := query.definite()
+	assert.True(t, ok)
+	assert.Equal(t, []any{"a", "b c", -1}, loc)
+
+	for _, expr := range []string{"$.a[*]", "$..a", "$.a[0,1]", "$.a[1:]", "$.a[?(@.b)]"} {
+		query, err = parseDataPath(expr)
+		require.NoError(t, err)
+		_, ok = query.definite()
+		assert.False(t, ok, expr)
+	}
+}
+
+func TestDataEqual(t *testing.T) {
+	a, err := parseJSONValue([]byte(`{"a": [1, 2.5, "x"], "b": {"c": null}}`))
+	require.NoError(t, err)
+	b, err := parseJSONValue([]byte(`{"b": {"c": null}, "a": [1.0, 2.5, "x"]}`))
+	require.NoError(t, err)
+	assert.True(t, dataEqual(a, b))
+
+	c, err := parseJSONValue([]byte(`{"a": [1, 2.5, "x"], "b": {"c": "null"}}`))
+	require.NoError(t, err)
+	assert.False(t, dataEqual(a, c))
+	assert.False(t, dataEqual(int64(1), "1"))
+	assert.True(t, filterEqual(int64(1), "1"))
+}
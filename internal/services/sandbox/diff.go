@@ -0,0 +1,289 @@
+// Copyright 2024 MCP Toolkit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sandbox
+
+import (
+	"fmt"
+	"strings"
+
+	"mcp-toolkit/pkg/types"
+)
+
+const (
+	// DefaultDiffContext 统一差异默认上下文行数 / Default context lines of a unified diff
+	DefaultDiffContext = 3
+
+	// maxDiffEditDistance Myers算法的最大编辑距离,超过后退化为整体替换
+	// Maximum edit distance for the Myers algorithm, beyond which the changed region is replaced as a whole
+	maxDiffEditDistance = 2000
+
+	// noNewlineMarker 统一差异中的"文件末尾无换行"标记 / "No newline at end of file" marker of a unified diff
+	noNewlineMarker = `\ No newline at end of file`
+)
+
+// diffOp 差异操作 / Diff operation
+type diffOp struct {
+	kind   byte   // ' ' 相同, '-' 删除, '+' 插入 / ' ' equal, '-' delete, '+' insert
+	text   string // 行内容(含换行符) / Line content (including line terminator)
+	oldPos int    // 操作前在原文件中的位置 / Position in the old file before the operation
+	newPos int    // 操作前在新文件中的位置 / Position in the new file before the operation
+}
+
+// splitLinesKeepEnds 按行拆分并保留换行符 / Split into lines keeping line terminators
+func splitLinesKeepEnds(content string) []string {
+	if content == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(content, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines 计算两组行之间的差异 / Compute the diff between two line slices
+func diffLines(a, b []string, equal func(x, y string) bool) []diffOp {
+	// 去掉公共前后缀以缩小比较范围 / Strip common prefix and suffix to narrow the comparison
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && equal(a[prefix], b[prefix]) {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && equal(a[len(a)-1-suffix], b[len(b)-1-suffix]) {
+		suffix++
+	}
+
+	ops := make([]diffOp, 0, len(a)+len(b))
+	for i := 0; i < prefix; i++ {
+		ops = append(ops, diffOp{kind: ' ', text: a[i]})
+	}
+	ops = append(ops, myersDiff(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix], equal)...)
+	for i := len(a) - suffix; i < len(a); i++ {
+		ops = append(ops, diffOp{kind: ' ', text: a[i]})
+	}
+
+	// 计算每个操作的位置 / Compute positions of each operation
+	oldPos, newPos := 0, 0
+	for i := range ops {
+		ops[i].oldPos, ops[i].newPos = oldPos, newPos
+		switch ops[i].kind {
+		case ' ':
+			oldPos++
+			newPos++
+		case '-':
+			oldPos++
+		case '+':
+			newPos++
+		}
+	}
+	return ops
+}
+
+// myersDiff 使用Myers算法计算最短编辑脚本 / Compute the shortest edit script with the Myers algorithm
+func myersDiff(a, b []string, equal func(x, y string) bool) []diffOp {
+	n, m := len(a), len(b)
+	if n == 0 || m == 0 {
+		return replaceAllOps(a, b)
+	}
+
+	maxD := min(n+m, maxDiffEditDistance)
+	offset := maxD + 1
+	v := make([]int, 2*maxD+3)
+	var trace [][]int
+
+	found := false
+	for d := 0; d <= maxD && !found; d++ {
+		// 保存本轮开始前的状态,仅保留[-d-1, d+1]范围 / Snapshot state before this round, keeping only [-d-1, d+1]
+		snapshot := make([]int, 2*d+3)
+		copy(snapshot, v[offset-d-1:offset+d+2])
+		trace = append(trace, snapshot)
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && equal(a[x], b[y]) {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				found = true
+				break
+			}
+		}
+	}
+	if !found {
+		return replaceAllOps(a, b)
+	}
+
+	// 回溯得到编辑脚本 / Backtrack to obtain the edit script
+	var reversed []diffOp
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		snapshot := trace[d]
+		get := func(k int) int { return snapshot[k+d+1] }
+
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && get(k-1) < get(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := get(prevK)
+		prevY := prevX - prevK
+		if d == 0 {
+			prevX, prevY = 0, 0
+		}
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			reversed = append(reversed, diffOp{kind: ' ', text: a[x]})
+		}
+		if d > 0 {
+			if x == prevX {
+				reversed = append(reversed, diffOp{kind: '+', text: b[prevY]})
+			} else {
+				reversed = append(reversed, diffOp{kind: '-', text: a[prevX]})
+			}
+		}
+		x, y = prevX, prevY
+	}
+
+	ops := make([]diffOp, len(reversed))
+	for i := range reversed {
+		ops[i] = reversed[len(reversed)-1-i]
+	}
+	return ops
+}
+
+// replaceAllOps 将a整体替换为b / Replace a with b as a whole
+func replaceAllOps(a, b []string) []diffOp {
+	ops := make([]diffOp, 0, len(a)+len(b))
+	for _, line := range a {
+		ops = append(ops, diffOp{kind: '-', text: line})
+	}
+	for _, line := range b {
+		ops = append(ops, diffOp{kind: '+', text: line})
+	}
+	return ops
+}
+
+// buildHunks 根据差异操作构建统一差异块 / Build unified diff hunks from diff operations
+func buildHunks(ops []diffOp, context int) []types.DiffHunk {
+	var hunks []types.DiffHunk
+
+	i := 0
+	for i < len(ops) {
+		// 找到下一个变更 / Find the next change
+		for i < len(ops) && ops[i].kind == ' ' {
+			i++
+		}
+		if i >= len(ops) {
+			break
+		}
+
+		start := max(0, i-context)
+		end := i
+		// 合并间隔不超过2倍上下文的变更 / Merge changes separated by at most twice the context
+		for end < len(ops) {
+			if ops[end].kind != ' ' {
+				end++
+				continue
+			}
+			next := end
+			for next < len(ops) && ops[next].kind == ' ' {
+				next++
+			}
+			if next < len(ops) && next-end <= 2*context {
+				end = next
+				continue
+			}
+			break
+		}
+		stop := min(len(ops), end+context)
+
+		hunk := types.DiffHunk{}
+		for _, op := range ops[start:stop] {
+			switch op.kind {
+			case ' ':
+				hunk.OldLines++
+				hunk.NewLines++
+			case '-':
+				hunk.OldLines++
+			case '+':
+				hunk.NewLines++
+			}
+			hunk.Lines = append(hunk.Lines, string(op.kind)+strings.TrimSuffix(op.text, "\n"))
+			if !strings.HasSuffix(op.text, "\n") {
+				hunk.Lines = append(hunk.Lines, noNewlineMarker)
+			}
+		}
+
+		// 行数为0时起始行指向前一行 / With zero lines the start points at the preceding line
+		hunk.OldStart = ops[start].oldPos
+		if hunk.OldLines > 0 {
+			hunk.OldStart++
+		}
+		hunk.NewStart = ops[start].newPos
+		if hunk.NewLines > 0 {
+			hunk.NewStart++
+		}
+
+		hunks = append(hunks, hunk)
+		i = stop
+	}
+
+	return hunks
+}
+
+// formatUnifiedDiff 格式化为统一差异文本 / Format hunks as unified diff text
+func formatUnifiedDiff(oldName, newName string, hunks []types.DiffHunk) string {
+	if len(hunks) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", oldName, newName)
+	for _, hunk := range hunks {
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", formatHunkRange(hunk.OldStart, hunk.OldLines), formatHunkRange(hunk.NewStart, hunk.NewLines))
+		for _, line := range hunk.Lines {
+			sb.WriteString(line)
+			sb.WriteByte('\n')
+		}
+	}
+	return sb.String()
+}
+
+// formatHunkRange 格式化块范围 / Format a hunk range
+func formatHunkRange(start, count int) string {
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+// unifiedDiff 计算两段文本的统一差异 / Compute the unified diff of two texts
+func unifiedDiff(oldName, newName, oldContent, newContent string, context int) (string, []types.DiffHunk) {
+	ops := diffLines(splitLinesKeepEnds(oldContent), splitLinesKeepEnds(newContent), func(x, y string) bool { return x == y })
+	hunks := buildHunks(ops, context)
+	return formatUnifiedDiff(oldName, newName, hunks), hunks
+}
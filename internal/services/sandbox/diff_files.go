@@ -0,0 +1,117 @@
+// Copyright 2024 MCP Toolkit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sandbox
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"mcp-toolkit/pkg/types"
+)
+
+// DiffFiles 比较两个文件或内联内容并返回统一差异 / Compare two files or inline contents and return a unified diff
+func (s *Service) DiffFiles(req *types.DiffFilesRequest) (*types.DiffFilesResponse, error) {
+	// 参数验证 / Parameter validation
+	if err := validateDiffFilesRequest(req); err != nil {
+		return nil, err
+	}
+
+	oldName, oldContent, err := s.diffSide("a", req.OldPath, req.OldContent)
+	if err != nil {
+		return nil, err
+	}
+	newName, newContent, err := s.diffSide("b", req.NewPath, req.NewContent)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &types.DiffFilesResponse{Hunks: []types.DiffHunk{}}
+
+	// 二进制内容只报告是否相同 / Binary content only reports equality
+	if isBinaryContent([]byte(oldContent[:min(len(oldContent), BinaryDetectSize)])) ||
+		isBinaryContent([]byte(newContent[:min(len(newContent), BinaryDetectSize)])) {
+		resp.Binary = true
+		resp.Identical = oldContent == newContent
+		if !resp.Identical {
+			resp.Diff = fmt.Sprintf("Binary files %s and %s differ\n", oldName, newName)
+		}
+		return resp, nil
+	}
+
+	context := DefaultDiffContext
+	if req.Context != nil {
+		context = *req.Context
+	}
+
+	equal := func(x, y string) bool { return x == y }
+	if req.IgnoreWhitespace {
+		equal = func(x, y string) bool {
+			return strings.Join(strings.Fields(x), "") == strings.Join(strings.Fields(y), "")
+		}
+	}
+
+	ops := diffLines(splitLinesKeepEnds(oldContent), splitLinesKeepEnds(newContent), equal)
+	for _, op := range ops {
+		switch op.kind {
+		case '-':
+			resp.Removed++
+		case '+':
+			resp.Added++
+		}
+	}
+	if hunks := buildHunks(ops, context); len(hunks) > 0 {
+		resp.Hunks = hunks
+		resp.Diff = formatUnifiedDiff(oldName, newName, hunks)
+	}
+	resp.Identical = resp.Added == 0 && resp.Removed == 0
+	return resp, nil
+}
+
+// diffSide 读取差异的一侧,返回差异头中的名称和内容 / Load one side of a diff, returning its header name and content
+func (s *Service) diffSide(prefix, path string, content *string) (string, string, error) {
+	if content != nil {
+		name := prefix + "/" + path
+		if path == "" {
+			name = prefix + "/(inline)"
+		}
+		return name, *content, nil
+	}
+
+	validPath, err := s.validateAccessPath(path, types.AccessRead)
+	if err != nil {
+		return "", "", err
+	}
+	info, err := s.fsys.Stat(validPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", "", fmt.Errorf("%s: %s", types.ErrFileNotFound, path)
+		}
+		return "", "", fmt.Errorf("failed to stat file: %w", err)
+	}
+	if info.IsDir() {
+		return "", "", fmt.Errorf("%s: %s", types.ErrIsDirectory, path)
+	}
+	if info.Size() > MaxFileSize {
+		return "", "", fmt.Errorf("file %s exceeds maximum allowed size of %d bytes", path, MaxFileSize)
+	}
+
+	data, err := s.fsys.ReadFile(validPath)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read file: %w", err)
+	}
+	return prefix + "/" + path, string(data), nil
+}
@@ -0,0 +1,130 @@
+package sandbox
+
+import (
+	"testing"
+
+	"mcp-toolkit/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func strPtr(s string) *string { return &s }
+
+func intPtr(n int) *int { return &n }
+
+func TestDiffFiles(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{
+		"old.txt": "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\n",
+		"new.txt": "a\nb\nC\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\n",
+	})
+
+	resp, err := service.DiffFiles(&types.DiffFilesRequest{OldPath: "old.txt", NewPath: "new.txt"})
+	require.NoError(t, err)
+	assert.False(t, resp.Identical)
+	assert.Equal(t, 2, resp.Added)
+	assert.Equal(t, 1, resp.Removed)
+	require.Len(t, resp.Hunks, 2)
+	assert.Equal(t, types.DiffHunk{OldStart: 1, OldLines: 6, NewStart: 1, NewLines: 6, Lines: []string{" a", " b", "-c", "+C", " d", " e", " f"}}, resp.Hunks[0])
+	assert.Contains(t, resp.Diff, "--- a/old.txt\n+++ b/new.txt\n")
+
+	// 更大的上下文把两个变更合并到一个块 / A larger context merges both changes into one hunk
+	resp, err = service.DiffFiles(&types.DiffFilesRequest{OldPath: "old.txt", NewPath: "new.txt", Context: intPtr(5)})
+	require.NoError(t, err)
+	assert.Len(t, resp.Hunks, 1)
+
+	resp, err = service.DiffFiles(&types.DiffFilesRequest{OldPath: "old.txt", NewPath: "new.txt", Context: intPtr(0)})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"-c", "+C"}, resp.Hunks[0].Lines)
+}
+
+func TestDiffFilesInlineContent(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{"main.go": "package main\n\nfunc main() {}\n"})
+
+	// 预览write_file / Preview a write_file
+	resp, err := service.DiffFiles(&types.DiffFilesRequest{OldPath: "main.go", NewContent: strPtr("package main\n\nfunc main() {\n}\n")})
+	require.NoError(t, err)
+	assert.False(t, resp.Identical)
+	assert.Contains(t, resp.Diff, "--- a/main.go\n+++ b/(inline)\n")
+	assert.Contains(t, resp.Diff, "-func main() {}\n+func main() {\n+}\n")
+
+	resp, err = service.DiffFiles(&types.DiffFilesRequest{OldContent: strPtr(""), NewPath: "main.go"})
+	require.NoError(t, err)
+	assert.Equal(t, 3, resp.Added)
+
+	resp, err = service.DiffFiles(&types.DiffFilesRequest{OldPath: "main.go", NewPath: "main.go"})
+	require.NoError(t, err)
+	assert.True(t, resp.Identical)
+	assert.Empty(t, resp.Diff)
+	assert.Empty(t, resp.Hunks)
+}
+
+func TestDiffFilesIgnoreWhitespace(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	oldText := "if x {\n\treturn 1\n}\n"
+	newText := "if x {\n    return  1\n}   \n"
+
+	resp, err := service.DiffFiles(&types.DiffFilesRequest{OldContent: &oldText, NewContent: &newText})
+	require.NoError(t, err)
+	assert.False(t, resp.Identical)
+
+	resp, err = service.DiffFiles(&types.DiffFilesRequest{OldContent: &oldText, NewContent: &newText, IgnoreWhitespace: true})
+	require.NoError(t, err)
+	assert.True(t, resp.Identical)
+	assert.Empty(t, resp.Diff)
+}
+
+func TestDiffFilesBinaryAndErrors(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{"a.bin": "\x00\x01", "b.bin": "\x00\x02", "dir/x.txt": "x"})
+
+	resp, err := service.DiffFiles(&types.DiffFilesRequest{OldPath: "a.bin", NewPath: "b.bin"})
+	require.NoError(t, err)
+	assert.True(t, resp.Binary)
+	assert.False(t, resp.Identical)
+	assert.Contains(t, resp.Diff, "Binary files")
+
+	_, err = service.DiffFiles(&types.DiffFilesRequest{OldPath: "missing.txt", NewPath: "a.bin"})
+	assert.ErrorContains(t, err, types.ErrFileNotFound)
+	_, err = service.DiffFiles(&types.DiffFilesRequest{OldPath: "dir", NewPath: "a.bin"})
+	assert.ErrorContains(t, err, types.ErrIsDirectory)
+	_, err = service.DiffFiles(&types.DiffFilesRequest{OldPath: "../x", NewPath: "a.bin"})
+	assert.Error(t, err)
+}
+
+func TestValidateDiffFilesRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     *types.DiffFilesRequest
+		wantErr bool
+	}{
+		{name: "nil request", req: nil, wantErr: true},
+		{name: "two paths", req: &types.DiffFilesRequest{OldPath: "a", NewPath: "b"}, wantErr: false},
+		{name: "empty inline content", req: &types.DiffFilesRequest{OldContent: strPtr(""), NewPath: "b"}, wantErr: false},
+		{name: "missing old side", req: &types.DiffFilesRequest{NewPath: "b"}, wantErr: true},
+		{name: "missing new side", req: &types.DiffFilesRequest{OldPath: "a"}, wantErr: true},
+		{name: "negative context", req: &types.DiffFilesRequest{OldPath: "a", NewPath: "b", Context: intPtr(-1)}, wantErr: true},
+		{name: "context too large", req: &types.DiffFilesRequest{OldPath: "a", NewPath: "b", Context: intPtr(MaxDiffContext + 1)}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateDiffFilesRequest(tt.req)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package sandbox
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	oldContent := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\n"
+	newContent := "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nK\nl\n"
+
+	text, hunks := unifiedDiff("a/x.txt", "b/x.txt", oldContent, newContent, 1)
+	require.Len(t, hunks, 2)
+	assert.Equal(t, 1, hunks[0].OldStart)
+	assert.Equal(t, 3, hunks[0].OldLines)
+	assert.Equal(t, []string{" a", "-b", "+B", " c"}, hunks[0].Lines)
+	assert.Equal(t, 10, hunks[1].OldStart)
+	assert.Equal(t, 3, hunks[1].NewLines)
+
+	assert.True(t, strings.HasPrefix(text, "--- a/x.txt\n+++ b/x.txt\n@@ -1,3 +1,3 @@\n"))
+	assert.Contains(t, text, "@@ -10,3 +10,3 @@\n j\n-k\n+K\n l\n")
+
+	// 上下文足够大时两个变更合并为一个块 / With enough context both changes merge into one hunk
+	_, hunks = unifiedDiff("a", "b", oldContent, newContent, 5)
+	assert.Len(t, hunks, 1)
+}
+
+func TestUnifiedDiffEdgeCases(t *testing.T) {
+	text, hunks := unifiedDiff("a", "b", "same\n", "same\n", DefaultDiffContext)
+	assert.Empty(t, text)
+	assert.Empty(t, hunks)
+
+	_, hunks = unifiedDiff("a", "b", "", "new\n", DefaultDiffContext)
+	require.Len(t, hunks, 1)
+	assert.Equal(t, 0, hunks[0].OldStart)
+	assert.Equal(t, 0, hunks[0].OldLines)
+	assert.Equal(t, 1, hunks[0].NewStart)
+
+	text, _ = unifiedDiff("a", "b", "x\ny", "x\nz", DefaultDiffContext)
+	assert.Contains(t, text, "-y\n"+noNewlineMarker+"\n+z\n"+noNewlineMarker+"\n")
+}
+
+func TestDiffLinesRoundTrip(t *testing.T) {
+	a := splitLinesKeepEnds("1\n2\n3\n4\n5\n6\n")
+	b := splitLinesKeepEnds("0\n2\n3\n3.5\n5\n7\n")
+
+	ops := diffLines(a, b, func(x, y string) bool { return x == y })
+
+	var gotOld, gotNew []string
+	for _, op := range ops {
+		if op.kind != '+' {
+			gotOld = append(gotOld, op.text)
+		}
+		if op.kind != '-' {
+			gotNew = append(gotNew, op.text)
+		}
+	}
+	assert.Equal(t, a, gotOld)
+	assert.Equal(t, b, gotNew)
+}
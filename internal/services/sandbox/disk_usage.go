@@ -0,0 +1,241 @@
+// Copyright 2024 MCP Toolkit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sandbox
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"mcp-toolkit/pkg/types"
+)
+
+const (
+	// DefaultDiskUsageTop 磁盘用量分析默认返回的最大条目数 / Default number of largest entries a disk usage analysis returns
+	DefaultDiskUsageTop = 10
+
+	// MaxDiskUsageTop 磁盘用量分析允许返回的最大条目数 / Maximum number of largest entries a disk usage analysis may return
+	MaxDiskUsageTop = 100
+
+	// DefaultDiskUsageTimeout 磁盘用量分析的默认遍历时间 / Default walk time of a disk usage analysis
+	DefaultDiskUsageTimeout = 10 * time.Second
+
+	// MaxDiskUsageTimeout 磁盘用量分析允许的最长遍历时间 / Maximum walk time of a disk usage analysis
+	MaxDiskUsageTimeout = time.Minute
+)
+
+// usageWalker 磁盘用量统计器 / Disk usage accumulator
+type usageWalker struct {
+	fsys       FileSystem
+	excludes   []string
+	maxDepth   int
+	deadline   time.Time
+	visible    func(absPath string) bool // 访问规则是否允许列出 / Whether the access rules allow listing
+	resp       *types.DiskUsageResponse
+	files      usageTop
+	dirs       usageTop
+	extensions map[string]*types.ExtensionUsage
+}
+
+// usageTop 保留最大的若干条目 / Keeps the largest few entries
+type usageTop struct {
+	limit   int
+	entries []types.DiskUsageEntry
+}
+
+// DiskUsage 分析目录的磁盘用量 / Analyse the disk usage of a directory
+func (s *Service) DiskUsage(req *types.DiskUsageRequest) (*types.DiskUsageResponse, error) {
+	// 参数验证 / Parameter validation
+	if err := validateDiskUsageRequest(req); err != nil {
+		return nil, err
+	}
+
+	root := req.Path
+	if root == "" {
+		root = "."
+	}
+	validPath, err := s.validateAccessPath(root, types.AccessList)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := s.fsys.Stat(validPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, errors.New(types.ErrFileNotFound)
+		}
+		return nil, fmt.Errorf("failed to stat path: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, errors.New(types.ErrNotDirectory)
+	}
+
+	timeout := time.Duration(req.Timeout) * time.Second
+	if timeout <= 0 {
+		timeout = DefaultDiskUsageTimeout
+	}
+	return s.diskUsage(validPath, req, time.Now().Add(timeout)), nil
+}
+
+// diskUsage 在截止时间前遍历目录并汇总用量 / Walk a directory until the deadline and summarise its usage
+func (s *Service) diskUsage(validPath string, req *types.DiskUsageRequest, deadline time.Time) *types.DiskUsageResponse {
+	start := time.Now()
+	top := req.Top
+	if top <= 0 {
+		top = DefaultDiskUsageTop
+	}
+
+	relPath := s.sandboxRelPath(validPath)
+	if relPath == "" {
+		relPath = "."
+	}
+	w := &usageWalker{
+		fsys:       s.fsys,
+		excludes:   req.Exclude,
+		maxDepth:   req.MaxDepth,
+		deadline:   deadline,
+		visible:    func(absPath string) bool { return s.accessAllowed(absPath, types.AccessList) },
+		resp:       &types.DiskUsageResponse{Path: relPath},
+		files:      usageTop{limit: top},
+		dirs:       usageTop{limit: top},
+		extensions: make(map[string]*types.ExtensionUsage),
+	}
+	w.walk(validPath, relPath, "", 0)
+
+	resp := w.resp
+	resp.LargestFiles = w.files.result()
+	resp.LargestDirectories = w.dirs.result()
+	resp.ExtensionCount = len(w.extensions)
+	resp.Extensions = make([]types.ExtensionUsage, 0, len(w.extensions))
+	for _, usage := range w.extensions {
+		resp.Extensions = append(resp.Extensions, *usage)
+	}
+	slices.SortFunc(resp.Extensions, func(a, b types.ExtensionUsage) int {
+		return cmp.Or(cmp.Compare(b.Size, a.Size), cmp.Compare(a.Extension, b.Extension))
+	})
+	if len(resp.Extensions) > top {
+		resp.Extensions = resp.Extensions[:top]
+	}
+	resp.Duration = time.Since(start).Milliseconds()
+	return resp
+}
+
+// walk 统计目录中的条目,返回其子树的总大小和文件数;超出深度的目录只计数不进入
+// Count the entries of a directory and return the total size and file count of its subtree; directories beyond the depth limit are counted but not entered
+func (w *usageWalker) walk(absPath, relPath, walkRel string, depth int) (int64, int) {
+	entries, err := w.fsys.ReadDir(absPath)
+	if err != nil {
+		w.resp.SkippedDirs++
+		return 0, 0
+	}
+
+	var size int64
+	var files int
+	for _, entry := range entries {
+		if w.resp.TimedOut {
+			break
+		}
+		if time.Now().After(w.deadline) {
+			w.resp.TimedOut = true
+			break
+		}
+
+		childPath := filepath.Join(absPath, entry.Name())
+		childWalkRel := path.Join(walkRel, entry.Name())
+		if matchAnyGlob(w.excludes, childWalkRel) || !w.visible(childPath) {
+			continue
+		}
+
+		childRel := joinDisplayPath(relPath, entry.Name())
+		switch {
+		case entry.Type()&fs.ModeSymlink != 0:
+			w.resp.SymlinkCount++
+		case entry.IsDir():
+			w.resp.DirCount++
+			if w.maxDepth > 0 && depth+1 >= w.maxDepth {
+				w.resp.SkippedDirs++
+				w.resp.DepthLimited = true
+				continue
+			}
+			dirSize, dirFiles := w.walk(childPath, childRel, childWalkRel, depth+1)
+			size += dirSize
+			files += dirFiles
+			w.dirs.add(types.DiskUsageEntry{Path: childRel, Size: dirSize, FileCount: dirFiles})
+		case entry.Type().IsRegular():
+			info, err := entry.Info()
+			if err != nil {
+				continue
+			}
+			size += info.Size()
+			files++
+			w.resp.FileCount++
+			w.resp.TotalSize += info.Size()
+			w.files.add(types.DiskUsageEntry{Path: childRel, Size: info.Size()})
+
+			ext := fileExtension(entry.Name())
+			usage, ok := w.extensions[ext]
+			if !ok {
+				usage = &types.ExtensionUsage{Extension: ext}
+				w.extensions[ext] = usage
+			}
+			usage.Size += info.Size()
+			usage.FileCount++
+		}
+	}
+	return size, files
+}
+
+// fileExtension 返回小写扩展名,点文件(如.bashrc)视为没有扩展名 / Return the lower-case extension; dotfiles such as .bashrc have none
+func fileExtension(name string) string {
+	ext := filepath.Ext(name)
+	if ext == name || ext == "." {
+		return ""
+	}
+	return strings.ToLower(ext)
+}
+
+// add 添加条目,超出上限较多时才排序裁剪以保持均摊开销
+// Add an entry, sorting and trimming only once well over the limit to keep the cost amortised
+func (t *usageTop) add(entry types.DiskUsageEntry) {
+	t.entries = append(t.entries, entry)
+	if len(t.entries) >= 2*t.limit {
+		t.trim()
+	}
+}
+
+// result 返回按大小降序排列的最大条目 / Return the largest entries in descending order of size
+func (t *usageTop) result() []types.DiskUsageEntry {
+	t.trim()
+	if t.entries == nil {
+		return []types.DiskUsageEntry{}
+	}
+	return t.entries
+}
+
+// trim 按大小降序排序并裁剪到上限 / Sort by descending size and trim to the limit
+func (t *usageTop) trim() {
+	slices.SortFunc(t.entries, func(a, b types.DiskUsageEntry) int {
+		return cmp.Or(cmp.Compare(b.Size, a.Size), cmp.Compare(a.Path, b.Path))
+	})
+	if len(t.entries) > t.limit {
+		t.entries = t.entries[:t.limit]
+	}
+}
@@ -0,0 +1,132 @@
+package sandbox
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"mcp-toolkit/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiskUsage(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+	writeTestFiles(t, tempDir, map[string]string{
+		"README.md":            strings.Repeat("r", 10),
+		".bashrc":              strings.Repeat("b", 5),
+		"Makefile":             strings.Repeat("m", 7),
+		"src/main.go":          strings.Repeat("g", 100),
+		"src/util.GO":          strings.Repeat("g", 50),
+		"src/internal/deep.go": strings.Repeat("g", 30),
+		"assets/logo.png":      strings.Repeat("p", 400),
+		"assets/icons/a.png":   strings.Repeat("p", 20),
+	})
+	require.NoError(t, os.Symlink(filepath.Join(tempDir, "src"), filepath.Join(tempDir, "link")))
+
+	resp, err := service.DiskUsage(&types.DiskUsageRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, ".", resp.Path)
+	assert.Equal(t, int64(622), resp.TotalSize)
+	assert.Equal(t, 8, resp.FileCount)
+	assert.Equal(t, 4, resp.DirCount)
+	assert.Equal(t, 1, resp.SymlinkCount)
+	assert.False(t, resp.TimedOut)
+	assert.False(t, resp.DepthLimited)
+
+	assert.Equal(t, types.DiskUsageEntry{Path: "assets/logo.png", Size: 400}, resp.LargestFiles[0])
+	assert.Equal(t, types.DiskUsageEntry{Path: "src/main.go", Size: 100}, resp.LargestFiles[1])
+	assert.Equal(t, []types.DiskUsageEntry{
+		{Path: "assets", Size: 420, FileCount: 2},
+		{Path: "src", Size: 180, FileCount: 3},
+		{Path: "src/internal", Size: 30, FileCount: 1},
+		{Path: "assets/icons", Size: 20, FileCount: 1},
+	}, resp.LargestDirectories)
+
+	// 扩展名不区分大小写,点文件和无扩展名的文件归为一组 / Extensions ignore case; dotfiles and files without one are grouped together
+	assert.Equal(t, []types.ExtensionUsage{
+		{Extension: ".png", Size: 420, FileCount: 2},
+		{Extension: ".go", Size: 180, FileCount: 3},
+		{Extension: "", Size: 12, FileCount: 2},
+		{Extension: ".md", Size: 10, FileCount: 1},
+	}, resp.Extensions)
+	assert.Equal(t, 4, resp.ExtensionCount)
+
+	// top限制每个列表,扩展名总数不受影响 / top limits every list while the extension total is unaffected
+	resp, err = service.DiskUsage(&types.DiskUsageRequest{Path: "src", Top: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "src", resp.Path)
+	assert.Equal(t, []types.DiskUsageEntry{{Path: "src/main.go", Size: 100}}, resp.LargestFiles)
+	assert.Equal(t, []types.DiskUsageEntry{{Path: "src/internal", Size: 30, FileCount: 1}}, resp.LargestDirectories)
+	assert.Len(t, resp.Extensions, 1)
+	assert.Equal(t, 1, resp.ExtensionCount)
+}
+
+func TestDiskUsageLimits(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+	writeTestFiles(t, tempDir, map[string]string{
+		"top.txt":        "12345",
+		"a/mid.txt":      "123",
+		"a/b/deep.txt":   "1",
+		"logs/app.log":   "xxxxxxxxxx",
+		"secret/key.pem": "k",
+	})
+	require.NoError(t, service.SetAccessConfig(types.AccessConfig{Rules: []types.AccessRule{
+		{Pattern: "secret/**", Operations: []types.AccessOperation{types.AccessList}},
+	}}))
+
+	// 超出深度的目录只计数不进入 / Directories beyond the depth limit are counted but not entered
+	resp, err := service.DiskUsage(&types.DiskUsageRequest{MaxDepth: 2, Exclude: []string{"*.log"}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(8), resp.TotalSize)
+	assert.Equal(t, 2, resp.FileCount)
+	assert.Equal(t, 3, resp.DirCount)
+	assert.True(t, resp.DepthLimited)
+	assert.Equal(t, 1, resp.SkippedDirs)
+	assert.Equal(t, []types.DiskUsageEntry{{Path: "a", Size: 3, FileCount: 1}, {Path: "logs", Size: 0}}, resp.LargestDirectories)
+
+	resp, err = service.DiskUsage(&types.DiskUsageRequest{MaxDepth: 1})
+	require.NoError(t, err)
+	assert.Equal(t, []types.DiskUsageEntry{{Path: "top.txt", Size: 5}}, resp.LargestFiles)
+	assert.Empty(t, resp.LargestDirectories)
+	assert.Equal(t, 2, resp.SkippedDirs)
+
+	// 超时后返回已统计的部分 / Running out of time returns what was counted so far
+	validPath, err := service.validateAccessPath(".", types.AccessList)
+	require.NoError(t, err)
+	resp = service.diskUsage(validPath, &types.DiskUsageRequest{}, time.Now().Add(-time.Second))
+	assert.True(t, resp.TimedOut)
+	assert.Zero(t, resp.FileCount)
+	assert.Empty(t, resp.LargestFiles)
+}
+
+func TestDiskUsageErrors(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+	writeTestFiles(t, tempDir, map[string]string{"file.txt": "x"})
+
+	tests := []struct {
+		name string
+		req  *types.DiskUsageRequest
+		err  string
+	}{
+		{"not a directory", &types.DiskUsageRequest{Path: "file.txt"}, types.ErrNotDirectory},
+		{"missing", &types.DiskUsageRequest{Path: "missing"}, types.ErrFileNotFound},
+		{"outside sandbox", &types.DiskUsageRequest{Path: "../"}, types.ErrSandboxViolation},
+		{"top too large", &types.DiskUsageRequest{Top: MaxDiskUsageTop + 1}, "top must be between"},
+		{"negative depth", &types.DiskUsageRequest{MaxDepth: -1}, "max_depth must not be negative"},
+		{"timeout too long", &types.DiskUsageRequest{Timeout: 3600}, "timeout must be between"},
+		{"invalid exclude", &types.DiskUsageRequest{Exclude: []string{"[abc"}}, "invalid"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.DiskUsage(tt.req)
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
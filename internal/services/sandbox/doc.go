@@ -9,17 +9,37 @@
 //   - 创建文件（create_file）
 //   - 读取文件（read_file）
 //   - 写入文件（write_file）
+//   - 编辑文件（edit_file）
+//   - 追加文件（append_file）
+//   - 插入/删除行（insert_lines、delete_lines）
+//   - 截断文件（truncate_file）
 //   - 删除文件/目录（delete）
+//   - 回收站（list_trash、restore_from_trash、empty_trash）
+//   - 快照（create_snapshot、list_snapshots、diff_snapshot、restore_snapshot）
 //   - 复制文件/目录（copy）
 //   - 移动/重命名（move）
 //   - 获取文件信息（get_file_info）
 //   - 检查文件存在（file_exists）
+//   - 计算文件哈希（file_hash）
+//   - 比较文件（compare_files）
+//   - 文件差异（diff_files）
+//   - 创建归档（create_archive）
+//   - 解压归档（extract_archive）
+//   - 分块上传（begin_upload、upload_chunk、commit_upload）
+//   - 转换文件编码（convert_encoding）
+//   - 咨询性路径锁（lock_path、unlock_path）
+//   - 查询和修改结构化数据（query_data、update_data）
 //
 // 目录操作：
 //   - 创建目录（create_directory）
 //   - 列出目录（list_directory）
+//   - 目录树（directory_tree）
 //   - 搜索文件（search_files）
+//   - 搜索文件内容（search_content）
 //   - 获取工作目录（get_working_directory）
+//   - 列出挂载（list_mounts）
+//   - 获取访问控制规则（get_access_rules）
+//   - 分析磁盘用量（disk_usage）
 //   - 切换工作目录（change_directory）
 //
 // 命令执行：
@@ -32,6 +52,13 @@
 // 系统功能：
 //   - 获取当前时间（get_current_time）
 //   - 权限级别管理（get_permission_level、set_permission_level）
+//   - 磁盘配额用量（get_quota_usage）
+//
+// MCP 资源：
+//   - 以 file:///{+path} 模板暴露沙箱文件，挂载内的文件使用 file:///名称:路径
+//   - 分页列出沙箱根目录下的文件（resources/list），跳过隐藏条目和默认排除目录
+//   - 读取文件（resources/read），UTF-8 文本返回文本，其他内容返回二进制
+//   - 订阅文件变化（resources/subscribe），定期检查并发送 notifications/resources/updated
 //
 // # 核心组件
 //
@@ -47,7 +74,7 @@
 //   - 所有操作限制在沙箱目录内
 //   - 路径遍历攻击防护（..）
 //   - 绝对路径验证
-//   - 符号链接检查
+//   - 符号链接解析（follow/deny/allow 策略，复制和搜索遍历时同样生效）
 //
 // 参数验证：
 //   - Nil 检查
@@ -55,6 +82,7 @@
 //   - 路径长度限制（4096 字符）
 //   - 文件大小限制（100MB）
 //   - 批量操作限制（1000 个文件）
+//   - 沙箱磁盘配额（总字节数和文件数）
 //
 // 命令执行安全：
 //   - 命令黑名单机制
@@ -63,6 +91,11 @@
 //   - 超时控制
 //   - 权限级别控制
 //
+// 并发控制：
+//   - 写入、删除、移动、复制等操作按路径加读写锁，锁定目录会阻塞其下路径的写入
+//   - 不重叠的路径互不影响，等待者按到达顺序获取锁
+//   - lock_path 授予带租约的咨询性锁，只与其他 lock_path 调用互斥
+//
 // # 使用示例
 //
 // 创建服务：
@@ -73,6 +106,10 @@
 //	    log.Fatal(err)
 //	}
 //
+// 使用内存文件系统后端（命令执行仍然使用宿主文件系统）：
+//
+//	service, err := sandbox.NewService("/sandbox", logger, sandbox.WithFileSystem(sandbox.NewMemFileSystem()))
+//
 // 注册工具到 MCP 服务器：
 //
 //	service.RegisterTools(mcpServer)
@@ -81,6 +118,15 @@
 //
 //	service.RegisterToolsToRegistry(toolRegistry)
 //
+// 注册文件资源（MCP 服务器需要使用 HandleSubscribe 和 HandleUnsubscribe 作为订阅处理器）：
+//
+//	mcpServer := mcp.NewServer(impl, &mcp.ServerOptions{
+//	    SubscribeHandler:   service.HandleSubscribe,
+//	    UnsubscribeHandler: service.HandleUnsubscribe,
+//	})
+//	service.RegisterResources(mcpServer)
+//	service.RegisterResourcesToRegistry(resourceRegistry)
+//
 // 创建文件：
 //
 //	req := types.CreateFileRequest{
@@ -112,6 +158,14 @@
 //   - validation.go：参数验证逻辑
 //   - constants.go：常量定义
 //   - mcp_tools.go：MCP 工具注册
+//   - resources.go：MCP 文件资源与订阅
+//   - upload.go：分块上传会话
+//   - charset.go：文本编码检测与转换
+//   - pathlock.go：按路径的读写锁
+//   - lease.go：咨询性路径锁租约
+//   - datapath.go：JSONPath风格的表达式
+//   - data.go、data_json.go、data_yaml.go、data_toml.go、data_csv.go：结构化数据查询与保留格式的修改
+//   - disk_usage.go：磁盘用量分析
 //   - command.go：命令执行功能
 //   - command_async.go：异步命令执行
 //   - command_blacklist.go：命令黑名单管理
@@ -125,6 +179,8 @@
 //
 // 限制值：
 //   - MaxFileSize：最大文件大小（100MB）
+//   - MaxUploadChunkSize：分块上传的单个分块上限（8MB）
+//   - UploadSessionTTL：上传会话无活动后的过期时间（15分钟）
 //   - MaxPathLength：最大路径长度（4096）
 //   - MaxBatchDeleteCount：最大批量删除数量（1000）
 //   - DefaultCommandTimeout：默认命令超时（30秒）
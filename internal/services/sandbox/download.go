@@ -55,14 +55,20 @@ func (s *Service) DownloadFile(req *types.DownloadFileRequest) (*types.DownloadF
 	}
 
 	// 验证并获取保存路径 / Validate and get save path
-	validPath, err := s.validatePath(req.Path)
+	validPath, err := s.validateWritePath(req.Path)
 	if err != nil {
 		return nil, err
 	}
+	defer s.locks.lock(writeLock(validPath))()
+
+	// 内容大小未知,先检查条目数 / The content size is unknown yet, so check the entry count first
+	if err = s.checkWriteQuota(validPath, 0); err != nil {
+		return nil, err
+	}
 
 	// 确保父目录存在 / Ensure parent directory exists
 	dir := filepath.Dir(validPath)
-	if err = os.MkdirAll(dir, DefaultDirPerm); err != nil {
+	if err = s.fsys.MkdirAll(dir, DefaultDirPerm); err != nil {
 		return nil, fmt.Errorf("failed to create parent directory: %w", err)
 	}
 
@@ -147,15 +153,37 @@ func (s *Service) DownloadFile(req *types.DownloadFileRequest) (*types.DownloadF
 	if contentLength > 0 && contentLength > MaxDownloadFileSize {
 		return nil, fmt.Errorf("file size (%d bytes) exceeds maximum allowed size (%d bytes)", contentLength, MaxDownloadFileSize)
 	}
+	if contentLength > 0 {
+		if err = s.checkWriteQuota(validPath, contentLength); err != nil {
+			return nil, err
+		}
+	}
 
 	// 创建目标文件 / Create target file
-	file, err := os.Create(validPath)
+	file, err := s.fsys.OpenFile(validPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0666)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create file: %w", err)
 	}
 
+	// Content-Length可能缺失或不准确,按剩余配额限制实际写入量
+	// Content-Length may be missing or wrong, so the actual amount written is bounded by the remaining quota
+	maxSize := int64(MaxDownloadFileSize)
+	remaining := int64(-1)
+	if s.inDefaultMount(validPath) {
+		remaining, err = s.remainingQuotaBytes()
+	}
+	if err != nil {
+		_ = file.Close()
+		_ = s.fsys.Remove(validPath)
+		return nil, err
+	}
+	quotaLimited := remaining >= 0 && remaining < maxSize
+	if quotaLimited {
+		maxSize = remaining
+	}
+
 	// 使用LimitReader限制读取大小，防止超大文件 / Use LimitReader to limit read size
-	limitedReader := io.LimitReader(resp.Body, MaxDownloadFileSize+1)
+	limitedReader := io.LimitReader(resp.Body, maxSize+1)
 
 	// 下载文件内容 / Download file content
 	size, err := io.Copy(file, limitedReader)
@@ -165,21 +193,22 @@ func (s *Service) DownloadFile(req *types.DownloadFileRequest) (*types.DownloadF
 
 	if err != nil {
 		// 下载失败，删除不完整的文件 / Delete incomplete file on failure
-		_ = os.Remove(validPath)
+		_ = s.fsys.Remove(validPath)
 		return nil, fmt.Errorf("failed to save file: %w", err)
 	}
 
 	// 检查是否超过最大文件大小 / Check if file size exceeds maximum
+	if quotaLimited && size > maxSize {
+		_ = s.fsys.Remove(validPath)
+		return nil, fmt.Errorf("%s: download exceeds the remaining %d bytes", types.ErrQuotaExceeded, maxSize)
+	}
 	if size > MaxDownloadFileSize {
-		_ = os.Remove(validPath)
+		_ = s.fsys.Remove(validPath)
 		return nil, fmt.Errorf("file size (%d bytes) exceeds maximum allowed size (%d bytes)", size, MaxDownloadFileSize)
 	}
 
 	// 获取相对路径 / Get relative path
-	relPath, err := filepath.Rel(s.sandboxDir, validPath)
-	if err != nil {
-		relPath = req.Path
-	}
+	relPath := s.displayPath(validPath)
 
 	s.logger.Info("file downloaded successfully",
 		zap.String("url", req.URL),
@@ -0,0 +1,320 @@
+// Copyright 2024 MCP Toolkit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sandbox
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"mcp-toolkit/pkg/types"
+
+	"go.uber.org/zap"
+)
+
+// hunkHeaderRegex 统一差异块头正则 / Unified diff hunk header regex
+var hunkHeaderRegex = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// patchHunk 解析后的补丁块 / Parsed patch hunk
+type patchHunk struct {
+	oldStart int      // 原文件起始行 / Start line in the old file
+	oldCount int      // 原文件行数 / Line count in the old file
+	oldLines []string // 原内容行(含换行符) / Old lines (with terminators)
+	newLines []string // 新内容行(含换行符) / New lines (with terminators)
+}
+
+// EditFile 通过查找替换或补丁编辑文件 / Edit file via search/replace edits or a patch
+func (s *Service) EditFile(req *types.EditFileRequest) (*types.EditFileResponse, error) {
+	// 参数验证 / Parameter validation
+	if err := validateEditFileRequest(req); err != nil {
+		return nil, err
+	}
+
+	// 差异会暴露原内容,因此需要读取权限;预览不需要写入权限 / The diff exposes the original content, so read access is required; previews need no write access
+	validPath, err := s.validateAccessPath(req.Path, types.AccessRead)
+	if err != nil {
+		return nil, err
+	}
+	if !req.DryRun {
+		if _, err = s.validateWritePath(req.Path); err != nil {
+			return nil, err
+		}
+	}
+
+	// 读取、修改和写回在路径锁内完成,预览只需共享锁 / Reading, editing and writing back happen under the path lock; previews only need a shared lock
+	lock := writeLock(validPath)
+	if req.DryRun {
+		lock = readLock(validPath)
+	}
+	defer s.locks.lock(lock)()
+
+	info, err := s.fsys.Stat(validPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, errors.New(types.ErrFileNotFound)
+		}
+		return nil, fmt.Errorf("failed to stat file: %w", err)
+	}
+	if info.IsDir() {
+		return nil, errors.New(types.ErrIsDirectory)
+	}
+	if info.Size() > MaxFileSize {
+		return nil, fmt.Errorf("file size exceeds maximum allowed size of %d bytes", MaxFileSize)
+	}
+
+	data, err := s.fsys.ReadFile(validPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	original := string(data)
+
+	resp := &types.EditFileResponse{DryRun: req.DryRun}
+
+	// 所有编辑在内存中完成,任何一处失败都不会写入 / All edits happen in memory; nothing is written if any of them fails
+	var updated string
+	if len(req.Edits) > 0 {
+		updated, resp.Replacements, err = applyTextEdits(original, req.Edits)
+	} else {
+		var hunks []patchHunk
+		if hunks, err = parseUnifiedDiff(req.Patch); err == nil {
+			updated, err = applyPatchHunks(original, hunks)
+			resp.HunksApplied = len(hunks)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(updated)) > MaxFileSize {
+		return nil, fmt.Errorf("content size exceeds maximum allowed size of %d bytes", MaxFileSize)
+	}
+
+	resp.Diff, _ = unifiedDiff("a/"+req.Path, "b/"+req.Path, original, updated, DefaultDiffContext)
+
+	if !req.DryRun && updated != original {
+		if err = writeFileAtomic(s.fsys, validPath, []byte(updated), info.Mode().Perm()); err != nil {
+			return nil, err
+		}
+		s.logger.Info("file edited", zap.String("path", validPath))
+	}
+
+	resp.Success = true
+	switch {
+	case req.DryRun:
+		resp.Message = "dry run completed, file not modified"
+	case updated == original:
+		resp.Message = "no changes were made"
+	default:
+		resp.Message = types.MsgFileEdited
+	}
+	return resp, nil
+}
+
+// applyTextEdits 按顺序应用查找替换编辑 / Apply search/replace edits in order
+func applyTextEdits(content string, edits []types.TextEdit) (string, []int, error) {
+	replacements := make([]int, 0, len(edits))
+	for i, edit := range edits {
+		count := strings.Count(content, edit.OldText)
+		if count == 0 {
+			return "", nil, fmt.Errorf("edit %d: old_text not found in file", i+1)
+		}
+
+		replaceAll := edit.ReplaceAll
+		switch {
+		case edit.ExpectedOccurrences > 0:
+			if count != edit.ExpectedOccurrences {
+				return "", nil, fmt.Errorf("edit %d: expected %d occurrences of old_text, found %d", i+1, edit.ExpectedOccurrences, count)
+			}
+			replaceAll = true
+		case !replaceAll && count > 1:
+			return "", nil, fmt.Errorf("edit %d: old_text is not unique (found %d occurrences), include more context or set replace_all", i+1, count)
+		}
+
+		if replaceAll {
+			content = strings.ReplaceAll(content, edit.OldText, edit.NewText)
+		} else {
+			content = strings.Replace(content, edit.OldText, edit.NewText, 1)
+		}
+		replacements = append(replacements, count)
+	}
+	return content, replacements, nil
+}
+
+// parseUnifiedDiff 解析统一差异格式补丁 / Parse a unified diff patch
+func parseUnifiedDiff(patch string) ([]patchHunk, error) {
+	lines := strings.Split(strings.ReplaceAll(patch, "\r\n", "\n"), "\n")
+
+	var hunks []patchHunk
+	var current *patchHunk
+	var lastKind byte
+	oldRemaining, newRemaining := 0, 0
+
+	for i, line := range lines {
+		// 块体内按行数计数,避免把以"---"开头的删除行误认为文件头
+		// Count lines inside a hunk body so that deletions starting with "---" are not taken as file headers
+		if current != nil && (oldRemaining > 0 || newRemaining > 0) {
+			kind := byte(' ')
+			text := line
+			if line != "" {
+				kind, text = line[0], line[1:]
+			}
+			switch kind {
+			case ' ':
+				current.oldLines = append(current.oldLines, text+"\n")
+				current.newLines = append(current.newLines, text+"\n")
+				oldRemaining--
+				newRemaining--
+			case '-':
+				current.oldLines = append(current.oldLines, text+"\n")
+				oldRemaining--
+			case '+':
+				current.newLines = append(current.newLines, text+"\n")
+				newRemaining--
+			case '\\':
+				stripLastNewline(current, lastKind)
+				continue
+			default:
+				return nil, fmt.Errorf("invalid patch line %d: %q", i+1, line)
+			}
+			if oldRemaining < 0 || newRemaining < 0 {
+				return nil, fmt.Errorf("patch line %d: hunk contains more lines than its header declares", i+1)
+			}
+			lastKind = kind
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			match := hunkHeaderRegex.FindStringSubmatch(line)
+			if match == nil {
+				return nil, fmt.Errorf("invalid hunk header at line %d: %q", i+1, line)
+			}
+			hunks = append(hunks, patchHunk{
+				oldStart: atoiDefault(match[1], 0),
+				oldCount: atoiDefault(match[2], 1),
+			})
+			current = &hunks[len(hunks)-1]
+			oldRemaining = current.oldCount
+			newRemaining = atoiDefault(match[4], 1)
+		case strings.HasPrefix(line, `\`) && current != nil:
+			stripLastNewline(current, lastKind)
+		case line == "" || strings.HasPrefix(line, "--- ") || strings.HasPrefix(line, "+++ ") ||
+			strings.HasPrefix(line, "diff ") || strings.HasPrefix(line, "index "):
+			// 文件头和空行 / File headers and blank lines
+		case strings.HasPrefix(line, "+") || strings.HasPrefix(line, "-") || strings.HasPrefix(line, " "):
+			return nil, fmt.Errorf("patch line %d is outside of any hunk, check the hunk header line counts", i+1)
+		}
+	}
+
+	if len(hunks) == 0 {
+		return nil, errors.New("patch does not contain any hunks")
+	}
+	if oldRemaining > 0 || newRemaining > 0 {
+		return nil, errors.New("patch ended before the last hunk was complete")
+	}
+	return hunks, nil
+}
+
+// stripLastNewline 处理"文件末尾无换行"标记 / Handle the "no newline at end of file" marker
+func stripLastNewline(hunk *patchHunk, kind byte) {
+	if kind == ' ' || kind == '-' {
+		if n := len(hunk.oldLines); n > 0 {
+			hunk.oldLines[n-1] = strings.TrimSuffix(hunk.oldLines[n-1], "\n")
+		}
+	}
+	if kind == ' ' || kind == '+' {
+		if n := len(hunk.newLines); n > 0 {
+			hunk.newLines[n-1] = strings.TrimSuffix(hunk.newLines[n-1], "\n")
+		}
+	}
+}
+
+// applyPatchHunks 应用补丁块,任何一块失败则整体失败 / Apply patch hunks, failing as a whole if any hunk does not apply
+func applyPatchHunks(content string, hunks []patchHunk) (string, error) {
+	lines := splitLinesKeepEnds(content)
+	useCRLF := strings.Contains(content, "\r\n")
+
+	result := make([]string, 0, len(lines))
+	cursor := 0
+	for i, hunk := range hunks {
+		hint := hunk.oldStart - 1
+		if hunk.oldCount == 0 {
+			hint = hunk.oldStart
+		}
+		pos := findHunkPosition(lines, hunk.oldLines, hint, cursor)
+		if pos < 0 {
+			return "", fmt.Errorf("hunk %d (@@ -%d,%d) does not apply: context does not match the file", i+1, hunk.oldStart, hunk.oldCount)
+		}
+
+		result = append(result, lines[cursor:pos]...)
+		for _, line := range hunk.newLines {
+			if useCRLF && strings.HasSuffix(line, "\n") && !strings.HasSuffix(line, "\r\n") {
+				line = strings.TrimSuffix(line, "\n") + "\r\n"
+			}
+			result = append(result, line)
+		}
+		cursor = pos + len(hunk.oldLines)
+	}
+	result = append(result, lines[cursor:]...)
+
+	return strings.Join(result, ""), nil
+}
+
+// findHunkPosition 查找最接近提示位置的匹配位置 / Find the matching position closest to the hint
+func findHunkPosition(lines, oldLines []string, hint, cursor int) int {
+	last := len(lines) - len(oldLines)
+	if last < cursor {
+		return -1
+	}
+	if len(oldLines) == 0 {
+		return min(max(hint, cursor), len(lines))
+	}
+
+	hint = min(max(hint, cursor), last)
+	for delta := 0; hint-delta >= cursor || hint+delta <= last; delta++ {
+		if p := hint - delta; p >= cursor && linesMatchAt(lines, oldLines, p) {
+			return p
+		}
+		if p := hint + delta; delta > 0 && p <= last && linesMatchAt(lines, oldLines, p) {
+			return p
+		}
+	}
+	return -1
+}
+
+// linesMatchAt 检查指定位置是否匹配 / Check whether lines match at the given position
+func linesMatchAt(lines, oldLines []string, pos int) bool {
+	for i, line := range oldLines {
+		if strings.TrimRight(lines[pos+i], "\r\n") != strings.TrimRight(line, "\r\n") {
+			return false
+		}
+	}
+	return true
+}
+
+// atoiDefault 解析整数,为空时返回默认值 / Parse integer, returning the default when empty
+func atoiDefault(s string, def int) int {
+	if s == "" {
+		return def
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return def
+	}
+	return n
+}
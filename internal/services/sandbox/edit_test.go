@@ -0,0 +1,202 @@
+package sandbox
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"mcp-toolkit/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEditFileSearchReplace(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	content := "package main\n\nfunc oldName() {}\n\nfunc main() {\n\toldName()\n}\n"
+	filePath := filepath.Join(tempDir, "main.go")
+	err := os.WriteFile(filePath, []byte(content), 0644)
+	require.NoError(t, err)
+
+	resp, err := service.EditFile(&types.EditFileRequest{
+		Path: "main.go",
+		Edits: []types.TextEdit{
+			{OldText: "oldName", NewText: "newName", ExpectedOccurrences: 2},
+			{OldText: "func main() {}", NewText: "unused"},
+		},
+	})
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+
+	// 失败的编辑不会修改文件 / A failed edit leaves the file unchanged
+	data, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	assert.Equal(t, content, string(data))
+
+	resp, err = service.EditFile(&types.EditFileRequest{
+		Path: "main.go",
+		Edits: []types.TextEdit{
+			{OldText: "oldName", NewText: "newName", ExpectedOccurrences: 2},
+			{OldText: "package main\n", NewText: "package app\n"},
+		},
+	})
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	assert.Equal(t, types.MsgFileEdited, resp.Message)
+	assert.Equal(t, []int{2, 1}, resp.Replacements)
+	assert.Contains(t, resp.Diff, "-func oldName() {}\n+func newName() {}\n")
+
+	data, err = os.ReadFile(filePath)
+	require.NoError(t, err)
+	assert.Equal(t, "package app\n\nfunc newName() {}\n\nfunc main() {\n\tnewName()\n}\n", string(data))
+}
+
+func TestEditFileAmbiguousMatch(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	err := os.WriteFile(filepath.Join(tempDir, "a.txt"), []byte("foo bar foo\n"), 0644)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		edit    types.TextEdit
+		want    string
+		wantErr string
+	}{
+		{name: "not unique", edit: types.TextEdit{OldText: "foo", NewText: "baz"}, wantErr: "not unique"},
+		{name: "not found", edit: types.TextEdit{OldText: "qux", NewText: "baz"}, wantErr: "not found"},
+		{name: "wrong expected count", edit: types.TextEdit{OldText: "foo", NewText: "baz", ExpectedOccurrences: 3}, wantErr: "expected 3 occurrences"},
+		{name: "replace all", edit: types.TextEdit{OldText: "foo", NewText: "baz", ReplaceAll: true}, want: "baz bar baz\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := service.EditFile(&types.EditFileRequest{Path: "a.txt", Edits: []types.TextEdit{tt.edit}, DryRun: true})
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, resp.DryRun)
+			assert.Contains(t, resp.Diff, "+"+tt.want)
+		})
+	}
+
+	// 预览不会写入文件 / Dry runs never write the file
+	data, err := os.ReadFile(filepath.Join(tempDir, "a.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "foo bar foo\n", string(data))
+}
+
+func TestEditFilePatch(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	content := "one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten"
+	filePath := filepath.Join(tempDir, "numbers.txt")
+	err := os.WriteFile(filePath, []byte(content), 0644)
+	require.NoError(t, err)
+
+	// 第一个块的行号故意偏移,第二个块修改没有换行结尾的最后一行
+	// The first hunk's line numbers are deliberately off; the second changes the last line which has no newline
+	patch := "--- a/numbers.txt\n+++ b/numbers.txt\n" +
+		"@@ -1,3 +1,3 @@\n three\n-four\n+FOUR\n five\n" +
+		"@@ -9,2 +9,2 @@\n nine\n-ten\n\\ No newline at end of file\n+TEN\n"
+
+	resp, err := service.EditFile(&types.EditFileRequest{Path: "numbers.txt", Patch: patch})
+	require.NoError(t, err)
+	assert.Equal(t, 2, resp.HunksApplied)
+
+	data, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	assert.Equal(t, "one\ntwo\nthree\nFOUR\nfive\nsix\nseven\neight\nnine\nTEN\n", string(data))
+
+	// 上下文不匹配时整体失败 / Mismatched context fails as a whole
+	_, err = service.EditFile(&types.EditFileRequest{Path: "numbers.txt", Patch: "@@ -1,2 +1,2 @@\n one\n-zero\n+ZERO\n"})
+	assert.Error(t, err)
+}
+
+func TestEditFilePatchCRLF(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	filePath := filepath.Join(tempDir, "crlf.txt")
+	err := os.WriteFile(filePath, []byte("a\r\nb\r\nc\r\n"), 0644)
+	require.NoError(t, err)
+
+	_, err = service.EditFile(&types.EditFileRequest{Path: "crlf.txt", Patch: "@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n"})
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	assert.Equal(t, "a\r\nB\r\nc\r\n", string(data))
+}
+
+func TestEditFileErrors(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	err := os.MkdirAll(filepath.Join(tempDir, "dir"), 0755)
+	require.NoError(t, err)
+
+	_, err = service.EditFile(&types.EditFileRequest{Path: "missing.txt", Edits: []types.TextEdit{{OldText: "a"}}})
+	require.Error(t, err)
+	assert.Equal(t, types.ErrFileNotFound, err.Error())
+
+	_, err = service.EditFile(&types.EditFileRequest{Path: "dir", Edits: []types.TextEdit{{OldText: "a"}}})
+	require.Error(t, err)
+	assert.Equal(t, types.ErrIsDirectory, err.Error())
+
+	_, err = service.EditFile(&types.EditFileRequest{Path: "../outside.txt", Edits: []types.TextEdit{{OldText: "a"}}})
+	assert.Error(t, err)
+}
+
+func TestParseUnifiedDiff(t *testing.T) {
+	// 以"---"开头的删除行不应被当作文件头 / A deletion starting with "---" must not be taken as a file header
+	hunks, err := parseUnifiedDiff("--- a/x\n+++ b/x\n@@ -1,2 +1,1 @@\n---- separator\n keep\n")
+	require.NoError(t, err)
+	require.Len(t, hunks, 1)
+	assert.Equal(t, []string{"--- separator\n", "keep\n"}, hunks[0].oldLines)
+	assert.Equal(t, []string{"keep\n"}, hunks[0].newLines)
+
+	_, err = parseUnifiedDiff("no hunks here")
+	assert.Error(t, err)
+
+	_, err = parseUnifiedDiff("@@ -1,3 +1,3 @@\n a\n")
+	assert.Error(t, err)
+
+	_, err = parseUnifiedDiff("@@ -1 +1 @@\n-a\n+b\n+c\n")
+	assert.Error(t, err)
+}
+
+func TestValidateEditFileRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     *types.EditFileRequest
+		wantErr bool
+	}{
+		{name: "nil request", req: nil, wantErr: true},
+		{name: "empty path", req: &types.EditFileRequest{Edits: []types.TextEdit{{OldText: "a"}}}, wantErr: true},
+		{name: "no edits or patch", req: &types.EditFileRequest{Path: "a.txt"}, wantErr: true},
+		{name: "both edits and patch", req: &types.EditFileRequest{Path: "a.txt", Edits: []types.TextEdit{{OldText: "a"}}, Patch: "@@ -1 +1 @@"}, wantErr: true},
+		{name: "empty old text", req: &types.EditFileRequest{Path: "a.txt", Edits: []types.TextEdit{{NewText: "a"}}}, wantErr: true},
+		{name: "negative occurrences", req: &types.EditFileRequest{Path: "a.txt", Edits: []types.TextEdit{{OldText: "a", ExpectedOccurrences: -1}}}, wantErr: true},
+		{name: "valid edits", req: &types.EditFileRequest{Path: "a.txt", Edits: []types.TextEdit{{OldText: "a", NewText: "b"}}}, wantErr: false},
+		{name: "valid patch", req: &types.EditFileRequest{Path: "a.txt", Patch: "@@ -1 +1 @@\n-a\n+b\n"}, wantErr: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateEditFileRequest(tt.req)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,305 @@
+// Copyright 2024 MCP Toolkit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sandbox
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"mcp-toolkit/pkg/types"
+
+	"go.uber.org/zap"
+)
+
+// AppendFile 向文件末尾追加内容,文件不存在时创建 / Append content to the end of a file, creating it when missing
+func (s *Service) AppendFile(req *types.AppendFileRequest) (*types.AppendFileResponse, error) {
+	// 参数验证 / Parameter validation
+	if err := validateAppendFileRequest(req); err != nil {
+		return nil, err
+	}
+
+	validPath, err := s.validateWritePath(req.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer s.locks.lock(writeLock(validPath))()
+
+	var size int64
+	content := req.Content
+	info, err := s.fsys.Stat(validPath)
+	switch {
+	case err == nil:
+		if info.IsDir() {
+			return nil, errors.New(types.ErrIsDirectory)
+		}
+		size = info.Size()
+		if req.EnsureNewline && size > 0 {
+			last, err := lastByte(s.fsys, validPath, size)
+			if err != nil {
+				return nil, err
+			}
+			if last != '\n' {
+				content = "\n" + content
+			}
+		}
+	case errors.Is(err, fs.ErrNotExist):
+	default:
+		return nil, fmt.Errorf("failed to stat file: %w", err)
+	}
+
+	if size+int64(len(content)) > MaxFileSize {
+		return nil, fmt.Errorf("file size would exceed maximum allowed size of %d bytes", MaxFileSize)
+	}
+	if err = s.checkWriteQuota(validPath, size+int64(len(content))); err != nil {
+		return nil, err
+	}
+
+	if err = s.fsys.MkdirAll(filepath.Dir(validPath), DefaultDirPerm); err != nil {
+		return nil, fmt.Errorf("failed to create parent directory: %w", err)
+	}
+	file, err := s.fsys.OpenFile(validPath, os.O_WRONLY|os.O_APPEND|os.O_CREATE, DefaultFilePerm)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	if _, err = file.WriteString(content); err != nil {
+		return nil, fmt.Errorf("failed to append to file: %w", err)
+	}
+	if err = file.Close(); err != nil {
+		return nil, fmt.Errorf("failed to append to file: %w", err)
+	}
+
+	resp, err := fileChangeResponse(s.fsys, validPath, fmt.Sprintf("appended %d bytes", len(content)))
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Info("file appended", zap.String("path", validPath), zap.Int("bytes", len(content)))
+	return resp, nil
+}
+
+// InsertLines 在指定行之前插入内容 / Insert content before the given line
+func (s *Service) InsertLines(req *types.InsertLinesRequest) (*types.InsertLinesResponse, error) {
+	// 参数验证 / Parameter validation
+	if err := validateInsertLinesRequest(req); err != nil {
+		return nil, err
+	}
+
+	validPath, err := s.validateWritePath(req.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer s.locks.lock(writeLock(validPath))()
+
+	content, perm, err := readTextFile(s.fsys, validPath)
+	if err != nil {
+		return nil, err
+	}
+	lines := splitLinesKeepEnds(content)
+	if req.Line > len(lines)+1 {
+		return nil, fmt.Errorf("line %d is out of range, the file has %d lines", req.Line, len(lines))
+	}
+
+	// 插入内容以文件的换行风格结尾 / Inserted content ends with the file's line terminator
+	newline := "\n"
+	if strings.Contains(content, "\r\n") {
+		newline = "\r\n"
+	}
+	inserted := splitLinesKeepEnds(req.Content)
+	if n := len(inserted); n > 0 && !strings.HasSuffix(inserted[n-1], "\n") {
+		inserted[n-1] += newline
+	}
+	if n := len(lines); req.Line == n+1 && n > 0 && !strings.HasSuffix(lines[n-1], "\n") {
+		lines[n-1] += newline
+	}
+
+	result := make([]string, 0, len(lines)+len(inserted))
+	result = append(result, lines[:req.Line-1]...)
+	result = append(result, inserted...)
+	result = append(result, lines[req.Line-1:]...)
+
+	resp, err := s.writeLines(validPath, result, perm, fmt.Sprintf("inserted %d lines before line %d", len(inserted), req.Line))
+	if err != nil {
+		return nil, err
+	}
+	resp.Lines = len(inserted)
+	s.logger.Info("lines inserted", zap.String("path", validPath), zap.Int("line", req.Line), zap.Int("count", len(inserted)))
+	return resp, nil
+}
+
+// DeleteLines 删除指定的行范围 / Delete the given line range
+func (s *Service) DeleteLines(req *types.DeleteLinesRequest) (*types.DeleteLinesResponse, error) {
+	// 参数验证 / Parameter validation
+	if err := validateDeleteLinesRequest(req); err != nil {
+		return nil, err
+	}
+
+	validPath, err := s.validateWritePath(req.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer s.locks.lock(writeLock(validPath))()
+
+	content, perm, err := readTextFile(s.fsys, validPath)
+	if err != nil {
+		return nil, err
+	}
+	lines := splitLinesKeepEnds(content)
+
+	end := req.EndLine
+	if end == 0 {
+		end = req.StartLine
+	}
+	if end > len(lines) {
+		return nil, fmt.Errorf("line range %d-%d is out of range, the file has %d lines", req.StartLine, end, len(lines))
+	}
+
+	result := append(lines[:req.StartLine-1:req.StartLine-1], lines[end:]...)
+	deleted := end - req.StartLine + 1
+
+	resp, err := s.writeLines(validPath, result, perm, fmt.Sprintf("deleted lines %d-%d", req.StartLine, end))
+	if err != nil {
+		return nil, err
+	}
+	resp.Lines = deleted
+	s.logger.Info("lines deleted", zap.String("path", validPath), zap.Int("start_line", req.StartLine), zap.Int("end_line", end))
+	return resp, nil
+}
+
+// TruncateFile 将文件截断或扩展到指定大小 / Truncate or extend a file to the given size
+func (s *Service) TruncateFile(req *types.TruncateFileRequest) (*types.TruncateFileResponse, error) {
+	// 参数验证 / Parameter validation
+	if err := validateTruncateFileRequest(req); err != nil {
+		return nil, err
+	}
+
+	validPath, err := s.validateWritePath(req.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer s.locks.lock(writeLock(validPath))()
+
+	info, err := s.fsys.Stat(validPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, errors.New(types.ErrFileNotFound)
+		}
+		return nil, fmt.Errorf("failed to stat file: %w", err)
+	}
+	if info.IsDir() {
+		return nil, errors.New(types.ErrIsDirectory)
+	}
+	if err = s.checkWriteQuota(validPath, req.Size); err != nil {
+		return nil, err
+	}
+
+	if err = s.fsys.Truncate(validPath, req.Size); err != nil {
+		return nil, fmt.Errorf("failed to truncate file: %w", err)
+	}
+
+	resp, err := fileChangeResponse(s.fsys, validPath, fmt.Sprintf("file truncated from %d to %d bytes", info.Size(), req.Size))
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Info("file truncated", zap.String("path", validPath), zap.Int64("size", req.Size))
+	return resp, nil
+}
+
+// readTextFile 读取用于按行修改的文本文件,返回内容和权限 / Read a text file for line edits, returning its content and permissions
+func readTextFile(fsys FileSystem, path string) (string, fs.FileMode, error) {
+	info, err := fsys.Stat(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", 0, errors.New(types.ErrFileNotFound)
+		}
+		return "", 0, fmt.Errorf("failed to stat file: %w", err)
+	}
+	if info.IsDir() {
+		return "", 0, errors.New(types.ErrIsDirectory)
+	}
+	if info.Size() > MaxFileSize {
+		return "", 0, fmt.Errorf("file size exceeds maximum allowed size of %d bytes", MaxFileSize)
+	}
+
+	data, err := fsys.ReadFile(path)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to read file: %w", err)
+	}
+	if isBinaryContent(data[:min(len(data), BinaryDetectSize)]) {
+		return "", 0, errors.New("line operations are not supported for binary files")
+	}
+	return string(data), info.Mode().Perm(), nil
+}
+
+// writeLines 原子写回行内容并构建响应 / Atomically write lines back and build the response
+func (s *Service) writeLines(path string, lines []string, perm fs.FileMode, message string) (*types.FileChangeResponse, error) {
+	content := strings.Join(lines, "")
+	if int64(len(content)) > MaxFileSize {
+		return nil, fmt.Errorf("file size would exceed maximum allowed size of %d bytes", MaxFileSize)
+	}
+	if err := s.checkWriteQuota(path, int64(len(content))); err != nil {
+		return nil, err
+	}
+	if err := writeFileAtomic(s.fsys, path, []byte(content), perm); err != nil {
+		return nil, err
+	}
+
+	resp, err := fileChangeResponse(s.fsys, path, message)
+	if err != nil {
+		return nil, err
+	}
+	resp.SHA256 = contentSHA256([]byte(content))
+	resp.TotalLines = len(lines)
+	return resp, nil
+}
+
+// fileChangeResponse 读取修改后的大小和版本标识 / Read the size and version token after a change
+func fileChangeResponse(fsys FileSystem, path, message string) (*types.FileChangeResponse, error) {
+	info, err := fsys.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat file: %w", err)
+	}
+	return &types.FileChangeResponse{
+		Success: true,
+		Message: message,
+		Size:    info.Size(),
+		Version: fileVersion(info),
+		ModTime: info.ModTime(),
+	}, nil
+}
+
+// lastByte 读取文件的最后一个字节 / Read the last byte of a file
+func lastByte(fsys FileSystem, path string, size int64) (byte, error) {
+	file, err := fsys.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	buf := make([]byte, 1)
+	if _, err = file.ReadAt(buf, size-1); err != nil && !errors.Is(err, io.EOF) {
+		return 0, fmt.Errorf("failed to read file: %w", err)
+	}
+	return buf[0], nil
+}
@@ -0,0 +1,162 @@
+package sandbox
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"mcp-toolkit/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readTestFile(t *testing.T, path string) string {
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestAppendFile(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	resp, err := service.AppendFile(&types.AppendFileRequest{Path: "logs/app.log", Content: "first"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), resp.Size)
+	assert.NotEmpty(t, resp.Version)
+
+	resp, err = service.AppendFile(&types.AppendFileRequest{Path: "logs/app.log", Content: "second\n", EnsureNewline: true})
+	require.NoError(t, err)
+	assert.Equal(t, int64(13), resp.Size)
+
+	// 已以换行结尾时不再补换行 / No extra newline when the file already ends with one
+	_, err = service.AppendFile(&types.AppendFileRequest{Path: "logs/app.log", Content: "third", EnsureNewline: true})
+	require.NoError(t, err)
+	assert.Equal(t, "first\nsecond\nthird", readTestFile(t, filepath.Join(tempDir, "logs", "app.log")))
+
+	_, err = service.AppendFile(&types.AppendFileRequest{Path: "logs", Content: "x"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), types.ErrIsDirectory)
+
+	_, err = service.AppendFile(&types.AppendFileRequest{Path: "../outside.log", Content: "x"})
+	assert.Error(t, err)
+}
+
+func TestInsertLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		line     int
+		insert   string
+		expected string
+		wantErr  bool
+	}{
+		{name: "at start", content: "a\nb\n", line: 1, insert: "x", expected: "x\na\nb\n"},
+		{name: "in middle", content: "a\nb\n", line: 2, insert: "x\ny\n", expected: "a\nx\ny\nb\n"},
+		{name: "at end", content: "a\nb\n", line: 3, insert: "x", expected: "a\nb\nx\n"},
+		{name: "at end without trailing newline", content: "a\nb", line: 3, insert: "x", expected: "a\nb\nx\n"},
+		{name: "keeps crlf", content: "a\r\nb\r\n", line: 2, insert: "x", expected: "a\r\nx\r\nb\r\n"},
+		{name: "empty file", content: "", line: 1, insert: "x", expected: "x\n"},
+		{name: "beyond end", content: "a\n", line: 3, insert: "x", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, tempDir := setupTestService(t)
+			defer cleanupTestService(t, tempDir)
+			writeTestFiles(t, tempDir, map[string]string{"f.txt": tt.content})
+
+			resp, err := service.InsertLines(&types.InsertLinesRequest{Path: "f.txt", Line: tt.line, Content: tt.insert})
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Equal(t, tt.content, readTestFile(t, filepath.Join(tempDir, "f.txt")))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, readTestFile(t, filepath.Join(tempDir, "f.txt")))
+			assert.Equal(t, len(splitLinesKeepEnds(tt.expected)), resp.TotalLines)
+		})
+	}
+}
+
+func TestDeleteLines(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{"f.txt": "1\n2\n3\n4\n5"})
+	require.NoError(t, os.Chmod(filepath.Join(tempDir, "f.txt"), 0600))
+
+	resp, err := service.DeleteLines(&types.DeleteLinesRequest{Path: "f.txt", StartLine: 2, EndLine: 3})
+	require.NoError(t, err)
+	assert.Equal(t, 2, resp.Lines)
+	assert.Equal(t, 3, resp.TotalLines)
+	assert.Equal(t, "1\n4\n5", readTestFile(t, filepath.Join(tempDir, "f.txt")))
+
+	info, err := os.Stat(filepath.Join(tempDir, "f.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	resp, err = service.DeleteLines(&types.DeleteLinesRequest{Path: "f.txt", StartLine: 3})
+	require.NoError(t, err)
+	assert.Equal(t, 1, resp.Lines)
+	assert.Equal(t, "1\n4\n", readTestFile(t, filepath.Join(tempDir, "f.txt")))
+
+	_, err = service.DeleteLines(&types.DeleteLinesRequest{Path: "f.txt", StartLine: 2, EndLine: 5})
+	assert.Error(t, err)
+
+	writeTestFiles(t, tempDir, map[string]string{"bin.dat": "\x00\x01\x02\n"})
+	_, err = service.DeleteLines(&types.DeleteLinesRequest{Path: "bin.dat", StartLine: 1})
+	assert.Error(t, err)
+}
+
+func TestTruncateFile(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{"f.txt": "0123456789"})
+
+	resp, err := service.TruncateFile(&types.TruncateFileRequest{Path: "f.txt", Size: 4})
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), resp.Size)
+	assert.Equal(t, "0123", readTestFile(t, filepath.Join(tempDir, "f.txt")))
+
+	resp, err = service.TruncateFile(&types.TruncateFileRequest{Path: "f.txt"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), resp.Size)
+
+	_, err = service.TruncateFile(&types.TruncateFileRequest{Path: "missing.txt"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), types.ErrFileNotFound)
+}
+
+func TestValidateFileOpRequests(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		wantErr bool
+	}{
+		{name: "append ok", err: validateAppendFileRequest(&types.AppendFileRequest{Path: "a"}), wantErr: false},
+		{name: "append nil", err: validateAppendFileRequest(nil), wantErr: true},
+		{name: "append no path", err: validateAppendFileRequest(&types.AppendFileRequest{}), wantErr: true},
+		{name: "insert ok", err: validateInsertLinesRequest(&types.InsertLinesRequest{Path: "a", Line: 1, Content: "x"}), wantErr: false},
+		{name: "insert line zero", err: validateInsertLinesRequest(&types.InsertLinesRequest{Path: "a", Content: "x"}), wantErr: true},
+		{name: "insert empty content", err: validateInsertLinesRequest(&types.InsertLinesRequest{Path: "a", Line: 1}), wantErr: true},
+		{name: "delete ok", err: validateDeleteLinesRequest(&types.DeleteLinesRequest{Path: "a", StartLine: 2, EndLine: 4}), wantErr: false},
+		{name: "delete inverted", err: validateDeleteLinesRequest(&types.DeleteLinesRequest{Path: "a", StartLine: 4, EndLine: 2}), wantErr: true},
+		{name: "delete start zero", err: validateDeleteLinesRequest(&types.DeleteLinesRequest{Path: "a"}), wantErr: true},
+		{name: "truncate ok", err: validateTruncateFileRequest(&types.TruncateFileRequest{Path: "a", Size: 10}), wantErr: false},
+		{name: "truncate negative", err: validateTruncateFileRequest(&types.TruncateFileRequest{Path: "a", Size: -1}), wantErr: true},
+		{name: "truncate too large", err: validateTruncateFileRequest(&types.TruncateFileRequest{Path: "a", Size: MaxFileSize + 1}), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr {
+				assert.Error(t, tt.err)
+			} else {
+				assert.NoError(t, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,216 @@
+// Copyright 2024 MCP Toolkit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sandbox
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// File 文件系统后端打开的文件 / File opened by a filesystem backend
+type File interface {
+	io.Reader
+	io.ReaderAt
+	io.Writer
+	io.StringWriter
+	io.Seeker
+	io.Closer
+
+	// Name 返回打开时使用的路径 / Return the path the file was opened with
+	Name() string
+	// Stat 返回文件信息 / Return file information
+	Stat() (fs.FileInfo, error)
+	// Sync 将内容提交到存储 / Commit the content to storage
+	Sync() error
+	// Chmod 修改文件权限 / Change the file mode
+	Chmod(mode fs.FileMode) error
+	// Truncate 修改文件大小 / Change the file size
+	Truncate(size int64) error
+}
+
+// FileSystem 沙箱服务使用的文件系统后端,路径均为宿主形式的绝对路径
+// 所有文件工具、复制、搜索、回收站、快照和配额统计都通过它访问文件;命令执行始终运行在宿主文件系统上
+// Filesystem backend used by the sandbox service; all paths are absolute host-style paths.
+// Every file tool, copy, search, trash, snapshot and quota measurement goes through it; command execution always runs on the host filesystem
+type FileSystem interface {
+	// Open 以只读方式打开文件 / Open a file for reading
+	Open(name string) (File, error)
+	// OpenFile 按标志打开文件,标志与os.OpenFile相同 / Open a file with os.OpenFile flags
+	OpenFile(name string, flag int, perm fs.FileMode) (File, error)
+	// CreateTemp 在目录中创建唯一的临时文件,模式同os.CreateTemp / Create a unique temporary file in a directory, patterns as in os.CreateTemp
+	CreateTemp(dir, pattern string) (File, error)
+	// Stat 返回文件信息,跟随符号链接 / Return file information, following symlinks
+	Stat(name string) (fs.FileInfo, error)
+	// Lstat 返回文件信息,不跟随符号链接 / Return file information without following symlinks
+	Lstat(name string) (fs.FileInfo, error)
+	// ReadDir 返回按名称排序的目录条目 / Return directory entries sorted by name
+	ReadDir(name string) ([]fs.DirEntry, error)
+	// ReadFile 读取整个文件 / Read a whole file
+	ReadFile(name string) ([]byte, error)
+	// WriteFile 写入整个文件 / Write a whole file
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+	// Mkdir 创建单个目录 / Create a single directory
+	Mkdir(name string, perm fs.FileMode) error
+	// MkdirAll 创建目录及其所有父目录 / Create a directory along with any missing parents
+	MkdirAll(path string, perm fs.FileMode) error
+	// Remove 删除文件或空目录 / Remove a file or an empty directory
+	Remove(name string) error
+	// RemoveAll 递归删除路径,路径不存在时不报错 / Remove a path recursively, succeeding when it does not exist
+	RemoveAll(path string) error
+	// Rename 重命名路径,目标为文件时替换它 / Rename a path, replacing the target when it is a file
+	Rename(oldpath, newpath string) error
+	// Chmod 修改权限 / Change the mode
+	Chmod(name string, mode fs.FileMode) error
+	// Chtimes 修改访问和修改时间 / Change the access and modification times
+	Chtimes(name string, atime, mtime time.Time) error
+	// Truncate 修改文件大小 / Change the size of a file
+	Truncate(name string, size int64) error
+	// Readlink 返回符号链接的目标 / Return the destination of a symlink
+	Readlink(name string) (string, error)
+	// EvalSymlinks 解析路径中的所有符号链接 / Resolve every symlink in a path
+	EvalSymlinks(path string) (string, error)
+}
+
+// OSFileSystem 基于宿主操作系统的文件系统后端 / Filesystem backend backed by the host operating system
+type OSFileSystem struct{}
+
+// NewOSFileSystem 创建宿主文件系统后端 / Create a host filesystem backend
+func NewOSFileSystem() *OSFileSystem {
+	return &OSFileSystem{}
+}
+
+// Open 以只读方式打开文件 / Open a file for reading
+func (OSFileSystem) Open(name string) (File, error) {
+	return wrapOSFile(os.Open(name))
+}
+
+// OpenFile 按标志打开文件 / Open a file with flags
+func (OSFileSystem) OpenFile(name string, flag int, perm fs.FileMode) (File, error) {
+	return wrapOSFile(os.OpenFile(name, flag, perm))
+}
+
+// CreateTemp 创建临时文件 / Create a temporary file
+func (OSFileSystem) CreateTemp(dir, pattern string) (File, error) {
+	return wrapOSFile(os.CreateTemp(dir, pattern))
+}
+
+// Stat 返回文件信息 / Return file information
+func (OSFileSystem) Stat(name string) (fs.FileInfo, error) { return os.Stat(name) }
+
+// Lstat 返回不跟随链接的文件信息 / Return file information without following links
+func (OSFileSystem) Lstat(name string) (fs.FileInfo, error) { return os.Lstat(name) }
+
+// ReadDir 读取目录 / Read a directory
+func (OSFileSystem) ReadDir(name string) ([]fs.DirEntry, error) { return os.ReadDir(name) }
+
+// ReadFile 读取文件 / Read a file
+func (OSFileSystem) ReadFile(name string) ([]byte, error) { return os.ReadFile(name) }
+
+// WriteFile 写入文件 / Write a file
+func (OSFileSystem) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	return os.WriteFile(name, data, perm)
+}
+
+// Mkdir 创建目录 / Create a directory
+func (OSFileSystem) Mkdir(name string, perm fs.FileMode) error { return os.Mkdir(name, perm) }
+
+// MkdirAll 递归创建目录 / Create directories recursively
+func (OSFileSystem) MkdirAll(path string, perm fs.FileMode) error { return os.MkdirAll(path, perm) }
+
+// Remove 删除路径 / Remove a path
+func (OSFileSystem) Remove(name string) error { return os.Remove(name) }
+
+// RemoveAll 递归删除路径 / Remove a path recursively
+func (OSFileSystem) RemoveAll(path string) error { return os.RemoveAll(path) }
+
+// Rename 重命名路径 / Rename a path
+func (OSFileSystem) Rename(oldpath, newpath string) error { return os.Rename(oldpath, newpath) }
+
+// Chmod 修改权限 / Change the mode
+func (OSFileSystem) Chmod(name string, mode fs.FileMode) error { return os.Chmod(name, mode) }
+
+// Chtimes 修改时间 / Change the times
+func (OSFileSystem) Chtimes(name string, atime, mtime time.Time) error {
+	return os.Chtimes(name, atime, mtime)
+}
+
+// Truncate 修改文件大小 / Change the size of a file
+func (OSFileSystem) Truncate(name string, size int64) error { return os.Truncate(name, size) }
+
+// Readlink 读取链接目标 / Read a link destination
+func (OSFileSystem) Readlink(name string) (string, error) { return os.Readlink(name) }
+
+// EvalSymlinks 解析符号链接 / Resolve symlinks
+func (OSFileSystem) EvalSymlinks(path string) (string, error) { return filepath.EvalSymlinks(path) }
+
+// wrapOSFile 将*os.File转换为File,避免返回包含nil指针的非nil接口
+// Convert an *os.File into a File without returning a non-nil interface holding a nil pointer
+func wrapOSFile(file *os.File, err error) (File, error) {
+	if err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// walkDir 在文件系统后端上按词法顺序遍历目录树,语义与filepath.WalkDir相同
+// Walk a directory tree on a filesystem backend in lexical order, with the semantics of filepath.WalkDir
+func walkDir(fsys FileSystem, root string, fn fs.WalkDirFunc) error {
+	info, err := fsys.Lstat(root)
+	if err != nil {
+		err = fn(root, nil, err)
+	} else {
+		err = walkDirEntry(fsys, root, fs.FileInfoToDirEntry(info), fn)
+	}
+	if errors.Is(err, filepath.SkipDir) || errors.Is(err, filepath.SkipAll) {
+		return nil
+	}
+	return err
+}
+
+// walkDirEntry 递归遍历单个条目 / Recursively walk a single entry
+func walkDirEntry(fsys FileSystem, path string, d fs.DirEntry, fn fs.WalkDirFunc) error {
+	if err := fn(path, d, nil); err != nil || !d.IsDir() {
+		if errors.Is(err, filepath.SkipDir) && d.IsDir() {
+			// 跳过该目录 / Skip this directory
+			err = nil
+		}
+		return err
+	}
+
+	entries, err := fsys.ReadDir(path)
+	if err != nil {
+		// 第二次调用报告ReadDir错误 / The second call reports the ReadDir error
+		if err = fn(path, d, err); err != nil {
+			if errors.Is(err, filepath.SkipDir) && d.IsDir() {
+				err = nil
+			}
+			return err
+		}
+	}
+
+	for _, entry := range entries {
+		if err := walkDirEntry(fsys, filepath.Join(path, entry.Name()), entry, fn); err != nil {
+			if errors.Is(err, filepath.SkipDir) {
+				break
+			}
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+// Copyright 2024 MCP Toolkit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sandbox
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// validateGlobPattern 验证glob模式语法 / Validate glob pattern syntax
+func validateGlobPattern(pattern string) error {
+	if pattern == "" {
+		return fmt.Errorf("glob pattern cannot be empty")
+	}
+	for _, segment := range strings.Split(pattern, "/") {
+		if _, err := path.Match(segment, ""); err != nil {
+			return fmt.Errorf("invalid glob pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// matchGlob 匹配支持**的glob模式,name为斜杠分隔的相对路径
+// Match a glob pattern supporting ** against a slash-separated relative path
+func matchGlob(pattern, name string) (bool, error) {
+	return matchGlobSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+// matchGlobSegments 逐段匹配,**匹配零个或多个路径段 / Match segment by segment, ** matches zero or more segments
+func matchGlobSegments(patterns, parts []string) (bool, error) {
+	for len(patterns) > 0 {
+		if patterns[0] == "**" {
+			for len(patterns) > 0 && patterns[0] == "**" {
+				patterns = patterns[1:]
+			}
+			if len(patterns) == 0 {
+				return true, nil
+			}
+			for i := range parts {
+				matched, err := matchGlobSegments(patterns, parts[i:])
+				if err != nil || matched {
+					return matched, err
+				}
+			}
+			return false, nil
+		}
+
+		if len(parts) == 0 {
+			return false, nil
+		}
+		matched, err := path.Match(patterns[0], parts[0])
+		if err != nil || !matched {
+			return false, err
+		}
+		patterns, parts = patterns[1:], parts[1:]
+	}
+	return len(parts) == 0, nil
+}
+
+// matchAnyGlob 检查路径是否匹配任一模式,不含"/"的模式只匹配文件名
+// Check whether a path matches any pattern; patterns without "/" match the base name only
+func matchAnyGlob(patterns []string, relPath string) bool {
+	for _, pattern := range patterns {
+		target := relPath
+		if !strings.Contains(pattern, "/") {
+			target = path.Base(relPath)
+		}
+		if matched, _ := matchGlob(pattern, target); matched {
+			return true
+		}
+	}
+	return false
+}
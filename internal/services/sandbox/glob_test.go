@@ -0,0 +1,49 @@
+package sandbox
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{pattern: "*.go", name: "main.go", want: true},
+		{pattern: "*.go", name: "cmd/main.go", want: false},
+		{pattern: "**/*.go", name: "main.go", want: true},
+		{pattern: "**/*.go", name: "cmd/server/main.go", want: true},
+		{pattern: "src/**", name: "src/a/b.txt", want: true},
+		{pattern: "src/**/test/*.js", name: "src/test/a.js", want: true},
+		{pattern: "src/**/test/*.js", name: "src/x/y/test/a.js", want: true},
+		{pattern: "src/**/test/*.js", name: "src/x/test/y/a.js", want: false},
+		{pattern: "a/?.txt", name: "a/b.txt", want: true},
+		{pattern: "a/[bc].txt", name: "a/d.txt", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.name, func(t *testing.T) {
+			got, err := matchGlob(tt.pattern, tt.name)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestValidateGlobPattern(t *testing.T) {
+	assert.NoError(t, validateGlobPattern("**/*.go"))
+	assert.NoError(t, validateGlobPattern("src/[a-z]*.ts"))
+	assert.Error(t, validateGlobPattern("[unclosed"))
+	assert.Error(t, validateGlobPattern("src/[a-"))
+	assert.Error(t, validateGlobPattern(""))
+}
+
+func TestMatchAnyGlob(t *testing.T) {
+	assert.True(t, matchAnyGlob([]string{"*.txt", "*.go"}, "pkg/util.go"))
+	assert.True(t, matchAnyGlob([]string{"pkg/**"}, "pkg/util.go"))
+	assert.False(t, matchAnyGlob([]string{"cmd/*.go"}, "pkg/util.go"))
+	assert.False(t, matchAnyGlob(nil, "pkg/util.go"))
+}
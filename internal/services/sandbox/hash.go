@@ -0,0 +1,326 @@
+// Copyright 2024 MCP Toolkit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sandbox
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"mcp-toolkit/pkg/types"
+
+	"go.uber.org/zap"
+)
+
+// newHasher 根据算法名创建哈希器 / Create a hasher for the given algorithm
+func newHasher(algorithm string) (hash.Hash, error) {
+	switch algorithm {
+	case types.HashMD5:
+		return md5.New(), nil
+	case types.HashSHA1:
+		return sha1.New(), nil
+	case types.HashSHA256:
+		return sha256.New(), nil
+	case types.HashSHA512:
+		return sha512.New(), nil
+	case types.HashCRC32:
+		return crc32.NewIEEE(), nil
+	default:
+		return nil, fmt.Errorf("unsupported hash algorithm: %s", algorithm)
+	}
+}
+
+// hashFile 流式计算文件哈希,返回十六进制哈希值和读取的字节数
+// Stream a file through the hasher, returning the hex digest and the number of bytes read
+func hashFile(fsys FileSystem, path, algorithm string, buf []byte) (string, int64, error) {
+	h, err := newHasher(algorithm)
+	if err != nil {
+		return "", 0, err
+	}
+
+	file, err := fsys.Open(path)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	n, err := io.CopyBuffer(h, file, buf)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to read file: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), n, nil
+}
+
+// FileHash 计算一个或多个文件的哈希值 / Compute hashes of one or more files
+func (s *Service) FileHash(req *types.FileHashRequest) (*types.FileHashResponse, error) {
+	// 参数验证 / Parameter validation
+	if err := validateFileHashRequest(req); err != nil {
+		return nil, err
+	}
+
+	algorithm := strings.ToLower(req.Algorithm)
+	if algorithm == "" {
+		algorithm = types.HashSHA256
+	}
+
+	resp := &types.FileHashResponse{
+		Algorithm: algorithm,
+		Results:   []types.FileHashResult{},
+	}
+	buf := make([]byte, readBufferSize)
+
+	// add 计算单个文件并记录结果,达到上限时返回false / Hash a single file and record it, returning false once the limit is reached
+	add := func(display, path string) bool {
+		if len(resp.Results) >= MaxHashFiles {
+			resp.Truncated = true
+			return false
+		}
+		result := types.FileHashResult{Path: display}
+		if sum, size, err := hashFile(s.fsys, path, algorithm, buf); err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Hash, result.Size = sum, size
+		}
+		resp.Results = append(resp.Results, result)
+		return true
+	}
+
+	for _, path := range req.Paths {
+		validPath, err := s.validateAccessPath(path, types.AccessRead)
+		if err != nil {
+			resp.Results = append(resp.Results, types.FileHashResult{Path: path, Error: err.Error()})
+			continue
+		}
+
+		info, err := s.fsys.Stat(validPath)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				err = errors.New(types.ErrFileNotFound)
+			}
+			resp.Results = append(resp.Results, types.FileHashResult{Path: path, Error: err.Error()})
+			continue
+		}
+
+		if !info.IsDir() {
+			if !add(path, validPath) {
+				break
+			}
+			continue
+		}
+		if !req.Recursive {
+			resp.Results = append(resp.Results, types.FileHashResult{Path: path, Error: types.ErrIsDirectory})
+			continue
+		}
+		if err = s.hashDirectory(path, validPath, add); err != nil {
+			return nil, err
+		}
+		if resp.Truncated {
+			break
+		}
+	}
+
+	s.logger.Info("file hash completed",
+		zap.String("algorithm", algorithm),
+		zap.Int("files", len(resp.Results)),
+		zap.Bool("truncated", resp.Truncated))
+	return resp, nil
+}
+
+// hashDirectory 递归计算目录下所有常规文件的哈希,结果路径以请求路径为前缀
+// Hash every regular file under a directory, reporting paths prefixed with the requested path
+func (s *Service) hashDirectory(display, root string, add func(display, path string) bool) error {
+	err := walkDir(s.fsys, root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// 跳过无法访问的条目 / Skip entries that cannot be accessed
+			if d != nil && d.IsDir() && path != root {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if d.Type()&fs.ModeSymlink != 0 {
+			if !s.symlinkEntryAllowed(path) {
+				return nil
+			}
+			// 只计算指向常规文件的链接,不跟随目录链接 / Only hash links to regular files; directory links are not followed
+			if info, statErr := s.fsys.Stat(path); statErr != nil || !info.Mode().IsRegular() {
+				return nil
+			}
+		} else if !d.Type().IsRegular() {
+			return nil
+		}
+		// 规则禁止读取的文件不计算哈希 / Files the rules deny reading are not hashed
+		if !s.accessAllowed(path, types.AccessRead) {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return nil
+		}
+		if !add(joinDisplayPath(filepath.ToSlash(display), filepath.ToSlash(rel)), path) {
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to walk directory: %w", err)
+	}
+	return nil
+}
+
+// CompareFiles 逐字节比较文件对 / Compare pairs of files byte by byte
+func (s *Service) CompareFiles(req *types.CompareFilesRequest) (*types.CompareFilesResponse, error) {
+	// 参数验证 / Parameter validation
+	if err := validateCompareFilesRequest(req); err != nil {
+		return nil, err
+	}
+
+	resp := &types.CompareFilesResponse{
+		Results:      make([]types.CompareFilesResult, 0, len(req.Pairs)),
+		AllIdentical: true,
+	}
+	bufA := make([]byte, readBufferSize)
+	bufB := make([]byte, readBufferSize)
+
+	for _, pair := range req.Pairs {
+		result := types.CompareFilesResult{Source: pair.Source, Target: pair.Target}
+		if err := s.compareFilePair(&result, bufA, bufB); err != nil {
+			result.Error = err.Error()
+		}
+		if !result.Identical {
+			resp.AllIdentical = false
+		}
+		resp.Results = append(resp.Results, result)
+	}
+
+	s.logger.Info("file comparison completed",
+		zap.Int("pairs", len(req.Pairs)),
+		zap.Bool("all_identical", resp.AllIdentical))
+	return resp, nil
+}
+
+// compareFilePair 比较单个文件对并填充结果 / Compare a single pair and fill in the result
+func (s *Service) compareFilePair(result *types.CompareFilesResult, bufA, bufB []byte) error {
+	sourcePath, err := s.validateRegularFile(result.Source)
+	if err != nil {
+		return err
+	}
+	targetPath, err := s.validateRegularFile(result.Target)
+	if err != nil {
+		return err
+	}
+
+	source, err := s.fsys.Open(sourcePath)
+	if err != nil {
+		return fmt.Errorf("failed to open source file: %w", err)
+	}
+	defer source.Close()
+	target, err := s.fsys.Open(targetPath)
+	if err != nil {
+		return fmt.Errorf("failed to open target file: %w", err)
+	}
+	defer target.Close()
+
+	if result.SourceSize, err = fileSize(source); err != nil {
+		return err
+	}
+	if result.TargetSize, err = fileSize(target); err != nil {
+		return err
+	}
+
+	offset, equal, err := firstDifference(source, target, bufA, bufB)
+	if err != nil {
+		return err
+	}
+	result.Identical = equal
+	if !equal {
+		result.FirstDifference = offset
+	}
+	return nil
+}
+
+// validateRegularFile 验证路径并确认其为常规文件,返回绝对路径
+// Validate a path and make sure it is a regular file, returning the absolute path
+func (s *Service) validateRegularFile(path string) (string, error) {
+	validPath, err := s.validateAccessPath(path, types.AccessRead)
+	if err != nil {
+		return "", err
+	}
+	info, err := s.fsys.Stat(validPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", errors.New(types.ErrFileNotFound)
+		}
+		return "", fmt.Errorf("failed to stat file: %w", err)
+	}
+	if info.IsDir() {
+		return "", errors.New(types.ErrIsDirectory)
+	}
+	return validPath, nil
+}
+
+// fileSize 获取已打开文件的大小 / Get the size of an opened file
+func fileSize(file File) (int64, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return 0, fmt.Errorf("failed to stat file: %w", err)
+	}
+	return info.Size(), nil
+}
+
+// firstDifference 流式比较两个读取器,返回第一个不同字节的偏移量以及是否完全相同
+// Stream-compare two readers, returning the offset of the first differing byte and whether they are identical
+func firstDifference(a, b io.Reader, bufA, bufB []byte) (int64, bool, error) {
+	var offset int64
+	for {
+		na, errA := io.ReadFull(a, bufA)
+		nb, errB := io.ReadFull(b, bufB)
+		if errA != nil && !errors.Is(errA, io.EOF) && !errors.Is(errA, io.ErrUnexpectedEOF) {
+			return 0, false, fmt.Errorf("failed to read source file: %w", errA)
+		}
+		if errB != nil && !errors.Is(errB, io.EOF) && !errors.Is(errB, io.ErrUnexpectedEOF) {
+			return 0, false, fmt.Errorf("failed to read target file: %w", errB)
+		}
+
+		n := min(na, nb)
+		if !bytes.Equal(bufA[:n], bufB[:n]) {
+			for i := 0; i < n; i++ {
+				if bufA[i] != bufB[i] {
+					return offset + int64(i), false, nil
+				}
+			}
+		}
+		if na != nb {
+			// 较短的一方先结束 / The shorter one ends first
+			return offset + int64(n), false, nil
+		}
+		if na < len(bufA) {
+			return 0, true, nil
+		}
+		offset += int64(n)
+	}
+}
@@ -0,0 +1,196 @@
+package sandbox
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"mcp-toolkit/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileHashAlgorithms(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	err := os.WriteFile(filepath.Join(tempDir, "hello.txt"), []byte("hello world"), 0644)
+	require.NoError(t, err)
+
+	tests := []struct {
+		algorithm string
+		want      string
+	}{
+		{algorithm: "", want: "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"},
+		{algorithm: types.HashMD5, want: "5eb63bbbe01eeed093cb22bb8f5acdc3"},
+		{algorithm: types.HashSHA1, want: "2aae6c35c94fcfb415dbe95f408b9ce91ee846ed"},
+		{algorithm: "SHA256", want: "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"},
+		{algorithm: types.HashSHA512, want: "309ecc489c12d6eb4cc40f50c902f2b4d0ed77ee511a7c7a9bcd3ca86d4cd86f989dd35bc5ff499670da34255b45b0cfd830e81f605dcf7dc5542e93ae9cd76f"},
+		{algorithm: types.HashCRC32, want: "0d4a1185"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.algorithm, func(t *testing.T) {
+			resp, err := service.FileHash(&types.FileHashRequest{Paths: []string{"hello.txt"}, Algorithm: tt.algorithm})
+			require.NoError(t, err)
+			require.Len(t, resp.Results, 1)
+			assert.Equal(t, tt.want, resp.Results[0].Hash)
+			assert.Equal(t, int64(11), resp.Results[0].Size)
+			assert.Empty(t, resp.Results[0].Error)
+		})
+	}
+}
+
+func TestFileHashLargeFile(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	// 跨越多个读取缓冲区 / Spans several read buffers
+	data := bytes.Repeat([]byte("0123456789abcdef"), readBufferSize/4)
+	err := os.WriteFile(filepath.Join(tempDir, "large.bin"), data, 0644)
+	require.NoError(t, err)
+
+	resp, err := service.FileHash(&types.FileHashRequest{Paths: []string{"large.bin"}, Algorithm: types.HashCRC32})
+	require.NoError(t, err)
+	require.Len(t, resp.Results, 1)
+	assert.Equal(t, int64(len(data)), resp.Results[0].Size)
+	assert.Len(t, resp.Results[0].Hash, 8)
+}
+
+func TestFileHashBatch(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{
+		"a.txt":         "same",
+		"dist/b.txt":    "same",
+		"dist/sub/c.md": "other",
+	})
+
+	resp, err := service.FileHash(&types.FileHashRequest{Paths: []string{"a.txt", "dist", "missing.txt"}})
+	require.NoError(t, err)
+	require.Len(t, resp.Results, 3)
+	assert.NotEmpty(t, resp.Results[0].Hash)
+	assert.Equal(t, types.ErrIsDirectory, resp.Results[1].Error)
+	assert.Equal(t, "missing.txt", resp.Results[2].Path)
+	assert.Equal(t, types.ErrFileNotFound, resp.Results[2].Error)
+
+	resp, err = service.FileHash(&types.FileHashRequest{Paths: []string{"dist"}, Recursive: true})
+	require.NoError(t, err)
+	require.Len(t, resp.Results, 2)
+	assert.Equal(t, "dist/b.txt", resp.Results[0].Path)
+	assert.Equal(t, "dist/sub/c.md", resp.Results[1].Path)
+	assert.NotEqual(t, resp.Results[0].Hash, resp.Results[1].Hash)
+	assert.False(t, resp.Truncated)
+}
+
+func TestFileHashOutsideSandbox(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	resp, err := service.FileHash(&types.FileHashRequest{Paths: []string{"../etc/passwd"}})
+	require.NoError(t, err)
+	require.Len(t, resp.Results, 1)
+	assert.Empty(t, resp.Results[0].Hash)
+	assert.Contains(t, resp.Results[0].Error, types.ErrSandboxViolation)
+}
+
+func TestCompareFiles(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	large := strings.Repeat("x", readBufferSize+10)
+	writeTestFiles(t, tempDir, map[string]string{
+		"a.txt":       "hello world",
+		"copy.txt":    "hello world",
+		"changed.txt": "hello there",
+		"short.txt":   "hello",
+		"large1.bin":  large,
+		"large2.bin":  large[:readBufferSize+5] + "y" + large[readBufferSize+6:],
+		"dir/x.txt":   "x",
+	})
+
+	resp, err := service.CompareFiles(&types.CompareFilesRequest{Pairs: []types.FilePair{
+		{Source: "a.txt", Target: "copy.txt"},
+		{Source: "a.txt", Target: "changed.txt"},
+		{Source: "a.txt", Target: "short.txt"},
+		{Source: "large1.bin", Target: "large2.bin"},
+		{Source: "a.txt", Target: "dir"},
+	}})
+	require.NoError(t, err)
+	require.Len(t, resp.Results, 5)
+	assert.False(t, resp.AllIdentical)
+
+	assert.True(t, resp.Results[0].Identical)
+	assert.Equal(t, int64(11), resp.Results[0].TargetSize)
+
+	assert.False(t, resp.Results[1].Identical)
+	assert.Equal(t, int64(6), resp.Results[1].FirstDifference)
+
+	assert.False(t, resp.Results[2].Identical)
+	assert.Equal(t, int64(5), resp.Results[2].FirstDifference)
+	assert.Equal(t, int64(5), resp.Results[2].TargetSize)
+
+	assert.False(t, resp.Results[3].Identical)
+	assert.Equal(t, int64(readBufferSize+5), resp.Results[3].FirstDifference)
+
+	assert.False(t, resp.Results[4].Identical)
+	assert.Equal(t, types.ErrIsDirectory, resp.Results[4].Error)
+
+	resp, err = service.CompareFiles(&types.CompareFilesRequest{Pairs: []types.FilePair{{Source: "large1.bin", Target: "large1.bin"}}})
+	require.NoError(t, err)
+	assert.True(t, resp.AllIdentical)
+}
+
+func TestValidateFileHashRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     *types.FileHashRequest
+		wantErr bool
+	}{
+		{name: "nil request", req: nil, wantErr: true},
+		{name: "valid request", req: &types.FileHashRequest{Paths: []string{"a.txt"}}, wantErr: false},
+		{name: "upper case algorithm", req: &types.FileHashRequest{Paths: []string{"a.txt"}, Algorithm: "MD5"}, wantErr: false},
+		{name: "empty paths", req: &types.FileHashRequest{}, wantErr: true},
+		{name: "empty path", req: &types.FileHashRequest{Paths: []string{""}}, wantErr: true},
+		{name: "unsupported algorithm", req: &types.FileHashRequest{Paths: []string{"a.txt"}, Algorithm: "sha3"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateFileHashRequest(tt.req)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateCompareFilesRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     *types.CompareFilesRequest
+		wantErr bool
+	}{
+		{name: "nil request", req: nil, wantErr: true},
+		{name: "valid request", req: &types.CompareFilesRequest{Pairs: []types.FilePair{{Source: "a", Target: "b"}}}, wantErr: false},
+		{name: "empty pairs", req: &types.CompareFilesRequest{}, wantErr: true},
+		{name: "missing target", req: &types.CompareFilesRequest{Pairs: []types.FilePair{{Source: "a"}}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateCompareFilesRequest(tt.req)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
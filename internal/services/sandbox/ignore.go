@@ -0,0 +1,133 @@
+// Copyright 2024 MCP Toolkit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sandbox
+
+import (
+	"bufio"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// gitIgnoreFile 忽略规则文件名 / Ignore rules file name
+	gitIgnoreFile = ".gitignore"
+
+	// gitDirName Git元数据目录名 / Git metadata directory name
+	gitDirName = ".git"
+)
+
+// ignoreRule 单条忽略规则 / Single ignore rule
+type ignoreRule struct {
+	base    string // 规则文件所在目录(相对沙箱,斜杠分隔) / Directory of the rules file (relative to sandbox, slash-separated)
+	pattern string // 相对base的glob模式 / Glob pattern relative to base
+	negate  bool   // 是否为"!"取反规则 / Whether this is a "!" negation rule
+	dirOnly bool   // 是否只匹配目录 / Whether the rule only matches directories
+}
+
+// ignoreMatcher .gitignore规则匹配器 / .gitignore rules matcher
+type ignoreMatcher struct {
+	fsys  FileSystem // 读取规则文件的文件系统 / Filesystem the rules files are read from
+	rules []ignoreRule
+}
+
+// newIgnoreMatcher 创建匹配器并加载沙箱根目录到root之间(不含root)各级目录的规则
+// Create a matcher loading rules of every directory from the sandbox root down to root (exclusive)
+func newIgnoreMatcher(fsys FileSystem, sandboxDir, root string) *ignoreMatcher {
+	m := &ignoreMatcher{fsys: fsys}
+	rel, err := filepath.Rel(sandboxDir, root)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return m
+	}
+
+	dir := sandboxDir
+	relDir := ""
+	for _, part := range strings.Split(filepath.ToSlash(rel), "/") {
+		m.loadFile(dir, relDir)
+		dir = filepath.Join(dir, part)
+		relDir = strings.TrimPrefix(relDir+"/"+part, "/")
+	}
+	return m
+}
+
+// loadFile 加载目录中的.gitignore文件 / Load the .gitignore file of a directory
+func (m *ignoreMatcher) loadFile(absDir, relDir string) {
+	file, err := m.fsys.Open(filepath.Join(absDir, gitIgnoreFile))
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if rule, ok := parseIgnoreLine(scanner.Text(), relDir); ok {
+			m.rules = append(m.rules, rule)
+		}
+	}
+}
+
+// parseIgnoreLine 解析一行忽略规则 / Parse a single ignore rule line
+func parseIgnoreLine(line, base string) (ignoreRule, bool) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return ignoreRule{}, false
+	}
+
+	rule := ignoreRule{base: base}
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\#`) || strings.HasPrefix(line, `\!`) {
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if line == "" {
+		return ignoreRule{}, false
+	}
+
+	// 含"/"的模式相对规则文件目录锚定,否则匹配任意层级
+	// Patterns containing "/" are anchored to the rules file directory, others match at any level
+	if strings.Contains(line, "/") {
+		line = strings.TrimPrefix(line, "/")
+	} else {
+		line = "**/" + line
+	}
+	rule.pattern = line
+	return rule, true
+}
+
+// match 判断相对沙箱的路径是否被忽略,后出现的规则优先
+// Check whether a sandbox-relative path is ignored; later rules take precedence
+func (m *ignoreMatcher) match(relPath string, isDir bool) bool {
+	ignored := false
+	for _, rule := range m.rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		sub := relPath
+		if rule.base != "" {
+			if !strings.HasPrefix(relPath, rule.base+"/") {
+				continue
+			}
+			sub = relPath[len(rule.base)+1:]
+		}
+		if matched, _ := matchGlob(rule.pattern, sub); matched {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
@@ -0,0 +1,57 @@
+package sandbox
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIgnoreMatcher(t *testing.T) {
+	tempDir := t.TempDir()
+	err := os.WriteFile(filepath.Join(tempDir, gitIgnoreFile), []byte("# comment\n*.log\n!keep.log\nbuild/\n/root-only.txt\ndocs/*.tmp\n"), 0644)
+	require.NoError(t, err)
+
+	m := &ignoreMatcher{fsys: NewOSFileSystem()}
+	m.loadFile(tempDir, "")
+
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{path: "app.log", want: true},
+		{path: "sub/dir/app.log", want: true},
+		{path: "keep.log", want: false},
+		{path: "build", isDir: true, want: true},
+		{path: "build", isDir: false, want: false},
+		{path: "root-only.txt", want: true},
+		{path: "sub/root-only.txt", want: false},
+		{path: "docs/a.tmp", want: true},
+		{path: "docs/sub/a.tmp", want: false},
+		{path: "main.go", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, m.match(tt.path, tt.isDir))
+		})
+	}
+}
+
+func TestIgnoreMatcherNested(t *testing.T) {
+	tempDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(tempDir, "sub", "inner"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, gitIgnoreFile), []byte("*.tmp\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "sub", gitIgnoreFile), []byte("/local.txt\n!important.tmp\n"), 0644))
+
+	// 从子目录开始时也会加载上级目录的规则 / Rules of parent directories are loaded when starting in a subdirectory
+	m := newIgnoreMatcher(NewOSFileSystem(), tempDir, filepath.Join(tempDir, "sub", "inner"))
+	assert.True(t, m.match("sub/inner/a.tmp", false))
+	assert.False(t, m.match("sub/inner/important.tmp", false))
+	assert.False(t, m.match("local.txt", false))
+	assert.False(t, m.match("sub/inner/local.txt", false))
+	assert.True(t, m.match("sub/local.txt", false))
+}
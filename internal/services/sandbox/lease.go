@@ -0,0 +1,246 @@
+// Copyright 2024 MCP Toolkit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sandbox
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"mcp-toolkit/pkg/types"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultLockTTL 路径锁的默认租约时长 / Default lease duration of a path lock
+	DefaultLockTTL = 5 * time.Minute
+
+	// MaxLockTTL 路径锁的最大租约时长 / Maximum lease duration of a path lock
+	MaxLockTTL = time.Hour
+
+	// MaxLockWait 等待被占用路径的最长时间 / Maximum time to wait for a held path
+	MaxLockWait = time.Minute
+
+	// MaxPathLeases 同时持有的路径锁上限 / Maximum number of path locks held at once
+	MaxPathLeases = 256
+
+	// MaxLockOwnerLength 持有者标签的最大长度 / Maximum length of a holder label
+	MaxLockOwnerLength = 128
+)
+
+// pathLease 由lock_path授予的咨询性租约。租约只与其他租约互斥,不会阻塞文件操作,
+// 供多个代理在多步编辑期间协调对文件的占用;过期的租约在下次访问租约表时清理
+// Advisory lease granted by lock_path. Leases only conflict with other leases and never block file
+// operations; they let agents coordinate who works on which files during multi-step edits. Expired
+// leases are pruned the next time the lease table is touched
+type pathLease struct {
+	id        string    // 锁ID / Lock ID
+	lock      pathLock  // 锁定的路径和模式 / Locked path and mode
+	display   string    // 显示用路径 / Path for display
+	owner     string    // 持有者标签 / Holder label
+	expiresAt time.Time // 到期时间 / Expiry time
+}
+
+// LockPath 为路径获取咨询性租约,路径被其他租约占用时按wait等待,提供lock_id时为已有租约续约
+// Take an advisory lease on a path, waiting up to wait seconds while another lease holds it; with a lock_id the existing lease is renewed
+func (s *Service) LockPath(req *types.LockPathRequest) (*types.LockPathResponse, error) {
+	if err := validateLockPathRequest(req); err != nil {
+		return nil, err
+	}
+
+	// 独占锁表示将要修改,需要写入权限 / An exclusive lock announces changes, so write access is required
+	var validPath string
+	var err error
+	if req.Shared {
+		validPath, err = s.validateAccessPath(req.Path, types.AccessRead)
+	} else {
+		validPath, err = s.validateWritePath(req.Path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	lock := writeLock(validPath)
+	if req.Shared {
+		lock = readLock(validPath)
+	}
+	ttl := DefaultLockTTL
+	if req.TTL > 0 {
+		ttl = time.Duration(req.TTL) * time.Second
+	}
+
+	if req.LockID != "" {
+		return s.renewLease(req.LockID, lock, ttl)
+	}
+
+	deadline := time.Now().Add(time.Duration(req.Wait) * time.Second)
+	for {
+		s.leaseMu.Lock()
+		now := time.Now()
+		s.pruneLeasesLocked(now)
+		holder := s.conflictingLeaseLocked(lock)
+		if holder == nil {
+			lease, err := s.grantLeaseLocked(lock, validPath, req.Owner, now.Add(ttl))
+			s.leaseMu.Unlock()
+			if err != nil {
+				return nil, err
+			}
+			s.logger.Info("path locked", zap.String("id", lease.id), zap.String("path", validPath), zap.Bool("shared", req.Shared))
+			return leaseResponse(lease, types.MsgPathLocked), nil
+		}
+
+		remaining := deadline.Sub(now)
+		if remaining <= 0 {
+			s.leaseMu.Unlock()
+			return nil, leaseConflictError(holder)
+		}
+		// 在租约释放、持有者到期或等待超时时重试 / Retry when a lease is released, the holder expires or the wait runs out
+		changed := s.leaseChangedLocked()
+		s.leaseMu.Unlock()
+		timer := time.NewTimer(min(remaining, holder.expiresAt.Sub(now)))
+		select {
+		case <-changed:
+		case <-timer.C:
+		}
+		timer.Stop()
+	}
+}
+
+// UnlockPath 释放路径锁 / Release a path lock
+func (s *Service) UnlockPath(req *types.UnlockPathRequest) (*types.UnlockPathResponse, error) {
+	if err := validateUnlockPathRequest(req); err != nil {
+		return nil, err
+	}
+
+	s.leaseMu.Lock()
+	defer s.leaseMu.Unlock()
+	s.pruneLeasesLocked(time.Now())
+	lease, ok := s.leases[req.LockID]
+	if !ok {
+		return nil, errors.New(types.ErrLockNotFound)
+	}
+	delete(s.leases, lease.id)
+	s.notifyLeasesLocked()
+
+	s.logger.Info("path unlocked", zap.String("id", lease.id), zap.String("path", lease.lock.path))
+	return &types.UnlockPathResponse{
+		Success: true,
+		Message: types.MsgPathUnlocked,
+		Path:    lease.display,
+	}, nil
+}
+
+// renewLease 延长已有租约,路径和模式必须与获取时一致 / Extend an existing lease; the path and mode must match those it was taken with
+func (s *Service) renewLease(id string, lock pathLock, ttl time.Duration) (*types.LockPathResponse, error) {
+	s.leaseMu.Lock()
+	defer s.leaseMu.Unlock()
+
+	now := time.Now()
+	s.pruneLeasesLocked(now)
+	lease, ok := s.leases[id]
+	if !ok {
+		return nil, errors.New(types.ErrLockNotFound)
+	}
+	if lease.lock != lock {
+		return nil, fmt.Errorf("lock %s was taken on %s with a different path or mode", id, lease.display)
+	}
+	lease.expiresAt = now.Add(ttl)
+	return leaseResponse(lease, types.MsgLockRenewed), nil
+}
+
+// grantLeaseLocked 记录新租约,调用方需持有leaseMu / Record a new lease; the caller must hold leaseMu
+func (s *Service) grantLeaseLocked(lock pathLock, validPath, owner string, expiresAt time.Time) (*pathLease, error) {
+	if len(s.leases) >= MaxPathLeases {
+		return nil, fmt.Errorf("too many path locks held, the limit is %d", MaxPathLeases)
+	}
+	lease := &pathLease{
+		id:        uuid.NewString(),
+		lock:      lock,
+		display:   s.displayPath(validPath),
+		owner:     owner,
+		expiresAt: expiresAt,
+	}
+	if s.leases == nil {
+		s.leases = make(map[string]*pathLease)
+	}
+	s.leases[lease.id] = lease
+	return lease, nil
+}
+
+// conflictingLeaseLocked 返回与锁冲突的租约,调用方需持有leaseMu / Return a lease conflicting with the lock; the caller must hold leaseMu
+func (s *Service) conflictingLeaseLocked(lock pathLock) *pathLease {
+	for _, lease := range s.leases {
+		if (pathLockGroup{lease.lock}).conflicts(pathLockGroup{lock}) {
+			return lease
+		}
+	}
+	return nil
+}
+
+// pruneLeasesLocked 移除过期的租约,调用方需持有leaseMu / Remove expired leases; the caller must hold leaseMu
+func (s *Service) pruneLeasesLocked(now time.Time) {
+	pruned := false
+	for id, lease := range s.leases {
+		if !now.Before(lease.expiresAt) {
+			delete(s.leases, id)
+			pruned = true
+		}
+	}
+	if pruned {
+		s.notifyLeasesLocked()
+	}
+}
+
+// leaseChangedLocked 返回在下次释放租约时关闭的通道,调用方需持有leaseMu
+// Return a channel closed the next time a lease is released; the caller must hold leaseMu
+func (s *Service) leaseChangedLocked() <-chan struct{} {
+	if s.leaseChanged == nil {
+		s.leaseChanged = make(chan struct{})
+	}
+	return s.leaseChanged
+}
+
+// notifyLeasesLocked 唤醒等待租约的调用,调用方需持有leaseMu / Wake callers waiting for leases; the caller must hold leaseMu
+func (s *Service) notifyLeasesLocked() {
+	if s.leaseChanged != nil {
+		close(s.leaseChanged)
+		s.leaseChanged = nil
+	}
+}
+
+// leaseResponse 构造租约响应 / Build the response for a lease
+func leaseResponse(lease *pathLease, message string) *types.LockPathResponse {
+	return &types.LockPathResponse{
+		Success:   true,
+		Message:   message,
+		LockID:    lease.id,
+		Path:      lease.display,
+		Shared:    !lease.lock.exclusive,
+		ExpiresAt: lease.expiresAt,
+	}
+}
+
+// leaseConflictError 描述占用路径的租约 / Describe the lease holding a path
+func leaseConflictError(holder *pathLease) error {
+	mode := "an exclusive"
+	if !holder.lock.exclusive {
+		mode = "a shared"
+	}
+	if holder.owner != "" {
+		mode += fmt.Sprintf(" %q", holder.owner)
+	}
+	return fmt.Errorf("%s: %s is held by %s lock until %s", types.ErrPathLocked, holder.display, mode, holder.expiresAt.Format(time.RFC3339))
+}
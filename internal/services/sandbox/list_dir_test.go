@@ -0,0 +1,132 @@
+package sandbox
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"mcp-toolkit/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// listNames 提取列表结果中的名称 / Extract names of a listing
+func listNames(resp *types.ListDirResponse) []string {
+	names := make([]string, 0, len(resp.Files))
+	for _, file := range resp.Files {
+		names = append(names, file.Name)
+	}
+	return names
+}
+
+func TestListDirPagination(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{
+		"a.txt": "", "b.txt": "", "c.txt": "", "d.txt": "", "e.txt": "",
+	})
+
+	var names []string
+	cursor := ""
+	pages := 0
+	for {
+		resp, err := service.ListDir(&types.ListDirRequest{Path: ".", Limit: 2, Cursor: cursor})
+		require.NoError(t, err)
+		assert.Equal(t, 5, resp.Total)
+		names = append(names, listNames(resp)...)
+		pages++
+		if resp.NextCursor == "" {
+			break
+		}
+		cursor = resp.NextCursor
+	}
+	assert.Equal(t, 3, pages)
+	assert.Equal(t, []string{"a.txt", "b.txt", "c.txt", "d.txt", "e.txt"}, names)
+
+	_, err := service.ListDir(&types.ListDirRequest{Path: ".", Cursor: "not-a-cursor"})
+	assert.Error(t, err)
+}
+
+func TestListDirSortAndFilter(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{
+		"big.go":       strings.Repeat("a", 300),
+		"medium.go":    strings.Repeat("a", 200),
+		"small.txt":    strings.Repeat("a", 100),
+		".hidden":      "",
+		"sub/file.txt": "",
+	})
+	old := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(filepath.Join(tempDir, "big.go"), old, old))
+
+	resp, err := service.ListDir(&types.ListDirRequest{Path: ".", Type: types.EntryTypeFile, SortBy: types.SortBySize, SortOrder: types.SortOrderDesc})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"big.go", "medium.go", "small.txt", ".hidden"}, listNames(resp))
+
+	resp, err = service.ListDir(&types.ListDirRequest{Path: ".", Pattern: "*.go", SortBy: types.SortByModTime})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"big.go", "medium.go"}, listNames(resp))
+
+	resp, err = service.ListDir(&types.ListDirRequest{Path: ".", Type: types.EntryTypeDirectory})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sub"}, listNames(resp))
+
+	// 隐藏条目默认列出 / Hidden entries are listed by default
+	resp, err = service.ListDir(&types.ListDirRequest{Path: ".", SortOrder: types.SortOrderDesc})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sub", "small.txt", "medium.go", "big.go", ".hidden"}, listNames(resp))
+	assert.Equal(t, 5, resp.Total)
+
+	includeHidden := false
+	resp, err = service.ListDir(&types.ListDirRequest{Path: ".", IncludeHidden: &includeHidden})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"big.go", "medium.go", "small.txt", "sub"}, listNames(resp))
+	assert.Equal(t, 4, resp.Total)
+}
+
+func TestValidateListDirRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     *types.ListDirRequest
+		wantErr bool
+	}{
+		{name: "nil request", req: nil, wantErr: true},
+		{name: "empty path", req: &types.ListDirRequest{}, wantErr: true},
+		{name: "valid", req: &types.ListDirRequest{Path: ".", Limit: 10, SortBy: types.SortBySize}, wantErr: false},
+		{name: "limit too large", req: &types.ListDirRequest{Path: ".", Limit: MaxListLimit + 1}, wantErr: true},
+		{name: "sort by path", req: &types.ListDirRequest{Path: ".", SortBy: types.SortByPath}, wantErr: true},
+		{name: "invalid order", req: &types.ListDirRequest{Path: ".", SortOrder: "up"}, wantErr: true},
+		{name: "invalid type", req: &types.ListDirRequest{Path: ".", Type: "socket"}, wantErr: true},
+		{name: "invalid pattern", req: &types.ListDirRequest{Path: ".", Pattern: "[a"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateListDirRequest(tt.req)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	offset, err := decodeCursor(encodeCursor(42))
+	require.NoError(t, err)
+	assert.Equal(t, 42, offset)
+
+	offset, err = decodeCursor("")
+	require.NoError(t, err)
+	assert.Equal(t, 0, offset)
+
+	_, err = decodeCursor("!!!")
+	assert.Error(t, err)
+}
@@ -31,7 +31,7 @@ func (s *Service) RegisterTools(mcpServer *mcp.Server) {
 	// Read file / 读取文件
 	mcp.AddTool(mcpServer, &mcp.Tool{
 		Name:        "read_file",
-		Description: "READ AND RETRIEVE complete file content. Primary tool for reading files - use whenever you need to see what's inside a file. Use for viewing, inspecting, checking file contents before modification. Keywords: read, view, show, display, get content, inspect file, open file. / 读取并获取完整文件内容。这是读取文件的主要工具。关键词：读取、查看、显示、获取内容、检查文件。",
+		Description: "READ AND RETRIEVE file content. Primary tool for reading files - use whenever you need to see what's inside a file. Supports line ranges, tail lines and byte offset/length for large files; images are returned as image content and other binary files as embedded resources. Keywords: read, view, show, display, get content, inspect file, open file, tail, log. / 读取并获取文件内容。这是读取文件的主要工具。支持按行范围、末尾行数和字节偏移/长度读取大文件；图片以图片内容返回，其他二进制文件以嵌入资源返回。关键词：读取、查看、显示、获取内容、检查文件、日志。",
 		InputSchema: types.GetToolSchema("read_file"),
 	}, s.handleReadFile)
 
@@ -42,6 +42,41 @@ func (s *Service) RegisterTools(mcpServer *mcp.Server) {
 		InputSchema: types.GetToolSchema("write_file"),
 	}, s.handleWriteFile)
 
+	// Edit file / 编辑文件
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name:        "edit_file",
+		Description: "EDIT PART OF A FILE without resending its whole content. Apply exact search/replace edits (old_text must be unique unless replace_all or expected_occurrences is set) or a unified diff patch. All edits are applied atomically - if any edit or hunk fails, the file is left untouched. Returns the resulting diff. Keywords: edit, modify, replace, patch, diff, change lines, refactor. / 编辑文件的一部分，无需重新发送整个文件内容。支持精确的查找替换编辑（old_text 必须唯一，除非设置 replace_all 或 expected_occurrences）或统一差异补丁。所有编辑原子应用，任何一处失败文件都不会被修改。返回变更差异。关键词：编辑、修改、替换、补丁、差异。",
+		InputSchema: types.GetToolSchema("edit_file"),
+	}, s.handleEditFile)
+
+	// Append file / 追加文件
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name:        "append_file",
+		Description: "Append content to the end of a file, creating the file (and parent directories) if it does not exist. Set ensure_newline to start the content on a new line. Ideal for logs and incremental generation without resending the whole file. / 向文件末尾追加内容，文件（及父目录）不存在时创建。设置 ensure_newline 可使内容从新行开始。适用于日志写入和增量生成，无需重新发送整个文件。",
+		InputSchema: types.GetToolSchema("append_file"),
+	}, s.handleAppendFile)
+
+	// Insert lines / 插入行
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name:        "insert_lines",
+		Description: "Insert content before a given line of a text file (1-based; total lines + 1 appends at the end). The content gets a trailing newline matching the file's line endings, and the file is rewritten atomically. / 在文本文件的指定行之前插入内容（从1开始；总行数+1表示追加到末尾）。内容会补上与文件一致的结尾换行，文件以原子方式重写。",
+		InputSchema: types.GetToolSchema("insert_lines"),
+	}, s.handleInsertLines)
+
+	// Delete lines / 删除行
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name:        "delete_lines",
+		Description: "Delete an inclusive line range from a text file (end_line defaults to start_line). The file is rewritten atomically. / 删除文本文件中的一段行（包含两端，end_line 默认等于 start_line）。文件以原子方式重写。",
+		InputSchema: types.GetToolSchema("delete_lines"),
+	}, s.handleDeleteLines)
+
+	// Truncate file / 截断文件
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name:        "truncate_file",
+		Description: "Truncate a file to the given size in bytes (0 empties it). A larger size extends the file with zero bytes. / 将文件截断为指定字节数（0 表示清空）。更大的大小会以零字节扩展文件。",
+		InputSchema: types.GetToolSchema("truncate_file"),
+	}, s.handleTruncateFile)
+
 	// Delete file or directory (auto-detect) / 删除文件或目录（自动判断）
 	mcp.AddTool(mcpServer, &mcp.Tool{
 		Name:        "delete",
@@ -108,17 +143,31 @@ func (s *Service) RegisterTools(mcpServer *mcp.Server) {
 	// List directory / 列出目录
 	mcp.AddTool(mcpServer, &mcp.Tool{
 		Name:        "list_directory",
-		Description: "LIST AND EXPLORE directory contents. Shows all files and subdirectories with detailed information. Primary tool for directory exploration. Use for seeing what files are in a directory, exploring project structure. Keywords: list, ls, dir, show files, browse. / 列出和探索目录内容。显示所有文件和子目录的详细信息。这是目录探索的主要工具。关键词：列出、显示文件、浏览。",
+		Description: "LIST AND EXPLORE directory contents. Shows files and subdirectories with detailed information, paginated with a cursor, sortable by name/size/mtime and filterable by type and name pattern. Hidden entries are listed unless include_hidden is false. Primary tool for directory exploration. Keywords: list, ls, dir, show files, browse. / 列出和探索目录内容。显示文件和子目录的详细信息，支持游标分页、按名称/大小/修改时间排序以及按类型和名称模式过滤。默认列出隐藏条目，将 include_hidden 设为 false 可跳过。这是目录探索的主要工具。关键词：列出、显示文件、浏览。",
 		InputSchema: types.GetToolSchema("list_directory"),
 	}, s.handleListDir)
 
+	// Directory tree / 目录树
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name:        "directory_tree",
+		Description: "SHOW A RECURSIVE DIRECTORY TREE in a single call, as structured JSON and compact indented text, with per-directory aggregate sizes and file counts. Supports max depth and an entry budget, and excludes noise like .git and node_modules by default. Use to orient yourself in an unfamiliar workspace instead of many list_directory calls. Keywords: tree, project structure, layout, overview. / 一次调用显示递归目录树，以结构化JSON和紧凑缩进文本返回，包含每个目录的汇总大小和文件数。支持最大深度和条目预算，默认排除 .git 和 node_modules 等。用于快速了解陌生工作区，替代多次 list_directory 调用。关键词：目录树、项目结构、概览。",
+		InputSchema: types.GetToolSchema("directory_tree"),
+	}, s.handleDirectoryTree)
+
 	// Search files / 搜索文件
 	mcp.AddTool(mcpServer, &mcp.Tool{
 		Name:        "search_files",
-		Description: "SEARCH AND FIND files matching patterns. Recursively searches through all subdirectories using glob patterns. Use for finding files by extension, locating specific files by name pattern. Keywords: search, find, locate, grep files, filter. / 搜索和查找匹配模式的文件。使用glob模式递归搜索所有子目录。用于按扩展名查找文件、按名称模式定位特定文件。关键词：搜索、查找、定位、过滤。",
+		Description: "SEARCH AND FIND files matching glob patterns. Recursively searches all subdirectories; supports ** against relative paths, multiple include/exclude patterns, type/size/mtime filters, max depth, result limits and sorting. Use for finding files by extension, locating files by name or path pattern, finding large or recently modified files. Keywords: search, find, locate, glob, filter. / 搜索和查找匹配glob模式的文件。递归搜索所有子目录；支持对相对路径使用 **、多个包含/排除模式、类型/大小/修改时间过滤、最大深度、结果上限和排序。用于按扩展名查找文件、按名称或路径模式定位文件、查找大文件或最近修改的文件。关键词：搜索、查找、定位、过滤。",
 		InputSchema: types.GetToolSchema("search_files"),
 	}, s.handleSearch)
 
+	// Search content / 搜索文件内容
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name:        "search_content",
+		Description: "SEARCH INSIDE FILES for text or a regex, like grep. Returns file, line, column and optional context lines for every matching line. Skips binary files, .git and .gitignore'd paths; supports include/exclude globs and result caps. Use this instead of running grep through execute_command. Keywords: grep, find in files, search text, regex, find usages. / 在文件内容中搜索文本或正则表达式，类似 grep。返回每个匹配行的文件、行号、列号和可选的上下文行。跳过二进制文件、.git 和 .gitignore 忽略的路径；支持包含/排除 glob 和结果上限。关键词：grep、文件内搜索、正则、查找引用。",
+		InputSchema: types.GetToolSchema("search_content"),
+	}, s.handleSearchContent)
+
 	// Batch delete / 批量删除
 	mcp.AddTool(mcpServer, &mcp.Tool{
 		Name:        "batch_delete",
@@ -133,6 +182,97 @@ func (s *Service) RegisterTools(mcpServer *mcp.Server) {
 		InputSchema: types.GetToolSchema("file_stat"),
 	}, s.handleFileStat)
 
+	// File hash / 文件哈希
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name:        "file_hash",
+		Description: "Compute checksums (md5, sha1, sha256, sha512 or crc32) of one or more files. Files are streamed so large files are fine; set recursive to hash every file under a directory. Failures are reported per path. / 计算一个或多个文件的校验和（md5、sha1、sha256、sha512 或 crc32）。文件以流式读取，适用于大文件；设置 recursive 可计算目录下所有文件。失败按路径单独报告。",
+		InputSchema: types.GetToolSchema("file_hash"),
+	}, s.handleFileHash)
+
+	// Compare files / 比较文件
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name:        "compare_files",
+		Description: "Check whether pairs of files have identical content. Files are compared byte by byte as streams; the result reports both sizes and the offset of the first difference. / 检查文件对的内容是否完全相同。文件以流式逐字节比较，结果包含双方大小和第一个不同字节的偏移量。",
+		InputSchema: types.GetToolSchema("compare_files"),
+	}, s.handleCompareFiles)
+
+	// Diff files / 比较文件差异
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name:        "diff_files",
+		Description: "Show a unified diff between two sandbox files, or between a file and proposed inline content (e.g. preview a write_file: old_path=file, new_content=new text). Returns the diff text plus structured hunks and added/removed counts; context lines and ignore_whitespace are configurable. / 显示两个沙箱文件之间、或文件与内联内容之间的统一差异（例如预览 write_file：old_path=文件，new_content=新内容）。返回差异文本、结构化差异块以及新增/删除行数；可配置上下文行数和 ignore_whitespace。",
+		InputSchema: types.GetToolSchema("diff_files"),
+	}, s.handleDiffFiles)
+
+	// Create archive / 创建归档
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name:        "create_archive",
+		Description: "Pack files and directories into a zip, tar, tar.gz or tar.zst archive. The format is inferred from the archive extension unless given; entries are named relative to each source's parent directory. / 将文件和目录打包为 zip、tar、tar.gz 或 tar.zst 归档。未指定格式时按扩展名推断；条目名称相对于每个源的父目录。",
+		InputSchema: types.GetToolSchema("create_archive"),
+	}, s.handleCreateArchive)
+
+	// Extract archive / 解压归档
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name:        "extract_archive",
+		Description: "Extract a zip, tar, tar.gz or tar.zst archive inside the sandbox, or list its contents with list_only. Every entry is checked before anything is written: entries escaping the destination are rejected, links are skipped and the total uncompressed size is limited. / 在沙箱内解压 zip、tar、tar.gz 或 tar.zst 归档，或通过 list_only 仅列出内容。写入前会检查所有条目：拒绝跳出目标目录的条目，跳过链接，并限制未压缩总大小。",
+		InputSchema: types.GetToolSchema("extract_archive"),
+	}, s.handleExtractArchive)
+
+	// List trash / 列出回收站
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name:        "list_trash",
+		Description: "List entries in the sandbox trash, most recently deleted first, with their original path, deletion time, size and session. Use the id with restore_from_trash. / 列出沙箱回收站中的条目（最近删除的在前），包括原路径、删除时间、大小和会话。可将 id 用于 restore_from_trash。",
+		InputSchema: types.GetToolSchema("list_trash"),
+	}, s.handleListTrash)
+
+	// Restore from trash / 从回收站恢复
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name:        "restore_from_trash",
+		Description: "Restore an entry from the trash to its original path or to a new destination. Existing paths are only replaced with overwrite, and the replaced content goes to the trash when trash mode is on. / 将回收站中的条目恢复到原路径或新位置。仅在设置 overwrite 时替换已存在的路径，启用回收站模式时被替换的内容同样进入回收站。",
+		InputSchema: types.GetToolSchema("restore_from_trash"),
+	}, s.handleRestoreFromTrash)
+
+	// Empty trash / 清空回收站
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name:        "empty_trash",
+		Description: "Permanently delete entries from the trash. Without ids the whole trash is emptied. This cannot be undone. / 永久删除回收站中的条目。不指定 ids 时清空整个回收站。此操作无法撤销。",
+		InputSchema: types.GetToolSchema("empty_trash"),
+	}, s.handleEmptyTrash)
+
+	// Create snapshot / 创建快照
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name:        "create_snapshot",
+		Description: "Capture the current state of the sandbox (or a directory in it) as a snapshot before a risky change. File contents are stored by hash, so unchanged files are shared between snapshots. / 在进行有风险的变更前，将沙箱（或其中的目录）的当前状态保存为快照。文件内容按哈希存储，未改变的文件在快照之间共享。",
+		InputSchema: types.GetToolSchema("create_snapshot"),
+	}, s.handleCreateSnapshot)
+
+	// List snapshots / 列出快照
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name:        "list_snapshots",
+		Description: "List all snapshots, newest first, with their scope, file count and total size. / 列出所有快照（最新的在前），包括范围、文件数和总大小。",
+		InputSchema: types.GetToolSchema("list_snapshots"),
+	}, s.handleListSnapshots)
+
+	// Diff snapshot / 快照差异
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name:        "diff_snapshot",
+		Description: "Show which paths were added, modified or deleted since a snapshot. Set content to include unified diffs of modified text files. / 显示自快照以来新增、修改或删除的路径。设置 content 可附带修改的文本文件的统一差异。",
+		InputSchema: types.GetToolSchema("diff_snapshot"),
+	}, s.handleDiffSnapshot)
+
+	// Restore snapshot / 恢复快照
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name:        "restore_snapshot",
+		Description: "Roll the sandbox (or a path in it) back to a snapshot. Each file is replaced atomically, paths added since the snapshot are removed unless keep_new_files is set, and the response reports every change. Use dry_run to preview. / 将沙箱（或其中的路径）回滚到快照。每个文件都以原子方式替换，除非设置 keep_new_files，否则会删除快照之后新增的路径，响应中报告所有变更。可使用 dry_run 预览。",
+		InputSchema: types.GetToolSchema("restore_snapshot"),
+	}, s.handleRestoreSnapshot)
+
+	// Get quota usage / 获取配额用量
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name:        "get_quota_usage",
+		Description: "Report the sandbox disk usage (total bytes and file/directory count, including trash and snapshots) against the configured quota, with the remaining allowance. Writes that would exceed the quota fail with a 'sandbox quota exceeded' error. / 报告沙箱磁盘用量（总字节数和文件/目录数，包括回收站和快照）与配置的配额对比以及剩余额度。超出配额的写入会以 'sandbox quota exceeded' 错误失败。",
+		InputSchema: types.GetToolSchema("get_quota_usage"),
+	}, s.handleGetQuotaUsage)
+
 	// File exists / 文件是否存在
 	mcp.AddTool(mcpServer, &mcp.Tool{
 		Name:        "file_exists",
@@ -193,10 +333,87 @@ func (s *Service) RegisterTools(mcpServer *mcp.Server) {
 	// Get working directory / 获取当前工作目录
 	mcp.AddTool(mcpServer, &mcp.Tool{
 		Name:        "get_working_directory",
-		Description: "Get the current working directory path. Returns the absolute path of the current working directory. / 获取当前工作目录路径。返回当前工作目录的绝对路径。",
+		Description: "Get the current working directory path, the mount it is on and whether that mount is read-only. / 获取当前工作目录路径、所在挂载以及该挂载是否只读。",
 		InputSchema: types.GetToolSchema("get_working_directory"),
 	}, s.handleGetWorkingDirectory)
 
+	// List mounts / 列出挂载
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name:        "list_mounts",
+		Description: "List the sandbox root and the named mounts with their host paths and read-only/read-write modes. Paths in other tools address a mount as 'name:relative/path'; unprefixed paths resolve to the sandbox root. / 列出沙箱根目录和命名挂载及其宿主路径和只读/读写模式。其他工具通过'名称:相对路径'访问挂载,无前缀的路径相对于沙箱根目录。",
+		InputSchema: types.GetToolSchema("list_mounts"),
+	}, s.handleListMounts)
+
+	// Get access rules / 获取访问控制规则
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name:        "get_access_rules",
+		Description: "List the path access control rules in evaluation order. Rules are glob patterns denying or allowing the read, write, delete and list operation classes; the first matching rule decides. Pass a path to see which operations are allowed on it and which rule decided. / 按评估顺序列出路径访问控制规则。规则为拒绝或允许read、write、delete、list操作类别的glob模式,第一条匹配的规则生效。传入路径可查看该路径允许哪些操作以及由哪条规则决定。",
+		InputSchema: types.GetToolSchema("get_access_rules"),
+	}, s.handleGetAccessRules)
+
+	// Begin upload / 开始分块上传
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name:        "begin_upload",
+		Description: "Begin a chunked upload of a large file. Returns an upload_id for upload_chunk and commit_upload and the maximum chunk size. Declare the total size to check it against the file size limit and quota up front. Sessions expire after 15 minutes without activity. / 开始大文件的分块上传。返回用于upload_chunk和commit_upload的upload_id以及分块大小上限。声明总大小可提前检查文件大小限制和配额。会话在15分钟无活动后过期。",
+		InputSchema: types.GetToolSchema("begin_upload"),
+	}, s.handleBeginUpload)
+
+	// Upload chunk / 上传分块
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name:        "upload_chunk",
+		Description: "Upload one base64 encoded chunk of a chunked upload at a byte offset. The offset must not exceed the bytes received so far; resending an earlier chunk overwrites it. The file size limit and quota are checked as chunks arrive. Returns the bytes received, which is the offset of the next chunk. / 在指定字节偏移上传一个Base64编码的分块。偏移不能超过已接收的字节数,重发较早的分块会覆盖原内容。每个分块到达时检查文件大小限制和配额。返回已接收的字节数,即下一个分块的偏移。",
+		InputSchema: types.GetToolSchema("upload_chunk"),
+	}, s.handleUploadChunk)
+
+	// Commit upload / 提交分块上传
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name:        "commit_upload",
+		Description: "Finish a chunked upload and atomically move the file into place. Pass the SHA-256 of the whole content to verify it; on a mismatch the upload is discarded. Fails if a declared size was not fully received. / 完成分块上传并以原子方式将文件移动到目标位置。传入完整内容的SHA-256进行校验,不匹配时放弃上传。声明的大小未完全接收时失败。",
+		InputSchema: types.GetToolSchema("commit_upload"),
+	}, s.handleCommitUpload)
+
+	// Convert encoding / 转换文件编码
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name:        "convert_encoding",
+		Description: "Re-encode a text file in place between utf-8, utf-16le, utf-16be, gbk, gb18030 and shift_jis, optionally writing a byte order mark and normalising line endings to lf or crlf. The source charset is detected when from is omitted. / 在utf-8、utf-16le、utf-16be、gbk、gb18030和shift_jis之间就地转换文本文件的编码,可写入字节顺序标记并将换行符统一为lf或crlf。未指定from时自动检测源字符集。",
+		InputSchema: types.GetToolSchema("convert_encoding"),
+	}, s.handleConvertEncoding)
+
+	// LockPath 锁定路径 / Lock path
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name:        "lock_path",
+		Description: "Reserve a file or directory with an advisory lease that conflicts with other lock_path calls on overlapping paths (咨询性地锁定文件或目录)",
+		InputSchema: types.GetToolSchema("lock_path"),
+	}, s.handleLockPath)
+
+	// UnlockPath 释放路径锁 / Unlock path
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name:        "unlock_path",
+		Description: "Release an advisory lease taken with lock_path (释放lock_path获取的租约)",
+		InputSchema: types.GetToolSchema("unlock_path"),
+	}, s.handleUnlockPath)
+
+	// QueryData 查询结构化数据 / Query data
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name:        "query_data",
+		Description: "Evaluate a JSONPath-style expression against a JSON, YAML, TOML or CSV file and return only the matching values (用JSONPath风格的表达式查询JSON、YAML、TOML或CSV文件)",
+		InputSchema: types.GetToolSchema("query_data"),
+	}, s.handleQueryData)
+
+	// UpdateData 修改结构化数据 / Update data
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name:        "update_data",
+		Description: "Set or delete values in a JSON, YAML, TOML or CSV file at a JSONPath-style expression, keeping the original formatting where possible (在JSON、YAML、TOML或CSV文件中设置或删除值,尽可能保留原格式)",
+		InputSchema: types.GetToolSchema("update_data"),
+	}, s.handleUpdateData)
+
+	// DiskUsage 分析磁盘用量 / Disk usage
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name:        "disk_usage",
+		Description: "Report the largest files and directories, usage by extension and counts for a directory, with depth and walk time limits (分析目录的磁盘用量:最大的文件和目录、按扩展名的用量和计数)",
+		InputSchema: types.GetToolSchema("disk_usage"),
+	}, s.handleDiskUsage)
+
 	// Change directory / 切换工作目录
 	mcp.AddTool(mcpServer, &mcp.Tool{
 		Name:        "change_directory",
@@ -291,6 +508,24 @@ func (s *Service) handleReadFile(_ context.Context, _ *mcp.CallToolRequest, args
 		return nil, nil, err
 	}
 
+	validPath, err := s.validatePath(args.Path)
+	if err != nil {
+		return nil, nil, err
+	}
+	content, err := readFileResultContent(s.displayPath(validPath), resp)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &mcp.CallToolResult{Content: content}, resp, nil
+}
+
+// handleWriteFile 处理写入文件请求 / Handle write file request
+func (s *Service) handleWriteFile(_ context.Context, _ *mcp.CallToolRequest, args types.WriteFileRequest) (*mcp.CallToolResult, *types.WriteFileResponse, error) {
+	resp, err := s.WriteFile(&args)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	resultJSON, _ := json.MarshalToString(resp)
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{
@@ -299,9 +534,69 @@ func (s *Service) handleReadFile(_ context.Context, _ *mcp.CallToolRequest, args
 	}, resp, nil
 }
 
-// handleWriteFile 处理写入文件请求 / Handle write file request
-func (s *Service) handleWriteFile(_ context.Context, _ *mcp.CallToolRequest, args types.WriteFileRequest) (*mcp.CallToolResult, *types.WriteFileResponse, error) {
-	resp, err := s.WriteFile(&args)
+// handleEditFile 处理编辑文件请求 / Handle edit file request
+func (s *Service) handleEditFile(_ context.Context, _ *mcp.CallToolRequest, args types.EditFileRequest) (*mcp.CallToolResult, *types.EditFileResponse, error) {
+	resp, err := s.EditFile(&args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resultJSON, _ := json.MarshalToString(resp)
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: resultJSON},
+		},
+	}, resp, nil
+}
+
+// handleAppendFile 处理追加文件请求 / Handle append file request
+func (s *Service) handleAppendFile(_ context.Context, _ *mcp.CallToolRequest, args types.AppendFileRequest) (*mcp.CallToolResult, *types.AppendFileResponse, error) {
+	resp, err := s.AppendFile(&args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resultJSON, _ := json.MarshalToString(resp)
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: resultJSON},
+		},
+	}, resp, nil
+}
+
+// handleInsertLines 处理插入行请求 / Handle insert lines request
+func (s *Service) handleInsertLines(_ context.Context, _ *mcp.CallToolRequest, args types.InsertLinesRequest) (*mcp.CallToolResult, *types.InsertLinesResponse, error) {
+	resp, err := s.InsertLines(&args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resultJSON, _ := json.MarshalToString(resp)
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: resultJSON},
+		},
+	}, resp, nil
+}
+
+// handleDeleteLines 处理删除行请求 / Handle delete lines request
+func (s *Service) handleDeleteLines(_ context.Context, _ *mcp.CallToolRequest, args types.DeleteLinesRequest) (*mcp.CallToolResult, *types.DeleteLinesResponse, error) {
+	resp, err := s.DeleteLines(&args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resultJSON, _ := json.MarshalToString(resp)
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: resultJSON},
+		},
+	}, resp, nil
+}
+
+// handleTruncateFile 处理截断文件请求 / Handle truncate file request
+func (s *Service) handleTruncateFile(_ context.Context, _ *mcp.CallToolRequest, args types.TruncateFileRequest) (*mcp.CallToolResult, *types.TruncateFileResponse, error) {
+	resp, err := s.TruncateFile(&args)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -468,6 +763,23 @@ func (s *Service) handleListDir(_ context.Context, _ *mcp.CallToolRequest, args
 	}, resp, nil
 }
 
+// handleDirectoryTree 处理目录树请求 / Handle directory tree request
+// 目录树是递归类型,无法推导输出模式,因此输出类型声明为any
+// The tree is a recursive type whose output schema cannot be inferred, so the output type is declared as any
+func (s *Service) handleDirectoryTree(_ context.Context, _ *mcp.CallToolRequest, args types.DirectoryTreeRequest) (*mcp.CallToolResult, any, error) {
+	resp, err := s.DirectoryTree(&args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resultJSON, _ := json.MarshalToString(resp)
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: resultJSON},
+		},
+	}, resp, nil
+}
+
 // handleSearch 处理搜索请求 / Handle search request
 func (s *Service) handleSearch(_ context.Context, _ *mcp.CallToolRequest, args types.SearchRequest) (*mcp.CallToolResult, *types.SearchResponse, error) {
 	resp, err := s.Search(&args)
@@ -483,6 +795,21 @@ func (s *Service) handleSearch(_ context.Context, _ *mcp.CallToolRequest, args t
 	}, resp, nil
 }
 
+// handleSearchContent 处理内容搜索请求 / Handle content search request
+func (s *Service) handleSearchContent(_ context.Context, _ *mcp.CallToolRequest, args types.SearchContentRequest) (*mcp.CallToolResult, *types.SearchContentResponse, error) {
+	resp, err := s.SearchContent(&args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resultJSON, _ := json.MarshalToString(resp)
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: resultJSON},
+		},
+	}, resp, nil
+}
+
 // handleBatchDelete 处理批量删除请求 / Handle batch delete request
 func (s *Service) handleBatchDelete(_ context.Context, _ *mcp.CallToolRequest, args types.BatchDeleteRequest) (*mcp.CallToolResult, *types.BatchDeleteResponse, error) {
 	resp, err := s.BatchDelete(&args)
@@ -513,9 +840,9 @@ func (s *Service) handleFileStat(_ context.Context, _ *mcp.CallToolRequest, args
 	}, resp, nil
 }
 
-// handleFileExists 处理文件存在检查请求 / Handle file exists request
-func (s *Service) handleFileExists(_ context.Context, _ *mcp.CallToolRequest, args types.FileExistsRequest) (*mcp.CallToolResult, *types.FileExistsResponse, error) {
-	resp, err := s.FileExists(&args)
+// handleFileHash 处理文件哈希请求 / Handle file hash request
+func (s *Service) handleFileHash(_ context.Context, _ *mcp.CallToolRequest, args types.FileHashRequest) (*mcp.CallToolResult, *types.FileHashResponse, error) {
+	resp, err := s.FileHash(&args)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -528,9 +855,9 @@ func (s *Service) handleFileExists(_ context.Context, _ *mcp.CallToolRequest, ar
 	}, resp, nil
 }
 
-// handleGetCurrentTime 处理获取当前时间请求 / Handle get current time request
-func (s *Service) handleGetCurrentTime(_ context.Context, _ *mcp.CallToolRequest, args types.GetCurrentTimeRequest) (*mcp.CallToolResult, *types.GetCurrentTimeResponse, error) {
-	resp, err := s.GetCurrentTime(&args)
+// handleCompareFiles 处理文件比较请求 / Handle compare files request
+func (s *Service) handleCompareFiles(_ context.Context, _ *mcp.CallToolRequest, args types.CompareFilesRequest) (*mcp.CallToolResult, *types.CompareFilesResponse, error) {
+	resp, err := s.CompareFiles(&args)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -543,9 +870,9 @@ func (s *Service) handleGetCurrentTime(_ context.Context, _ *mcp.CallToolRequest
 	}, resp, nil
 }
 
-// handleGetSystemInfo 处理获取系统信息请求 / Handle get system info request
-func (s *Service) handleGetSystemInfo(_ context.Context, _ *mcp.CallToolRequest, args types.GetSystemInfoRequest) (*mcp.CallToolResult, *types.GetSystemInfoResponse, error) {
-	resp, err := s.GetSystemInfo(&args)
+// handleDiffFiles 处理比较文件差异请求 / Handle diff files request
+func (s *Service) handleDiffFiles(_ context.Context, _ *mcp.CallToolRequest, args types.DiffFilesRequest) (*mcp.CallToolResult, *types.DiffFilesResponse, error) {
+	resp, err := s.DiffFiles(&args)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -558,22 +885,11 @@ func (s *Service) handleGetSystemInfo(_ context.Context, _ *mcp.CallToolRequest,
 	}, resp, nil
 }
 
-// handleExecuteCommand 处理执行命令请求 / Handle execute command request
-func (s *Service) handleExecuteCommand(_ context.Context, _ *mcp.CallToolRequest, args types.ExecuteCommandRequest) (*mcp.CallToolResult, *types.ExecuteCommandResponse, error) {
-	resp, err := s.ExecuteCommand(&args)
-	// 即使发生错误,也返回响应对象(如果存在) / Return response object even if error occurs (if exists)
+// handleCreateArchive 处理创建归档请求 / Handle create archive request
+func (s *Service) handleCreateArchive(_ context.Context, _ *mcp.CallToolRequest, args types.CreateArchiveRequest) (*mcp.CallToolResult, *types.CreateArchiveResponse, error) {
+	resp, err := s.CreateArchive(&args)
 	if err != nil {
-		// 如果响应对象为nil,创建一个错误响应 / If response is nil, create an error response
-		if resp == nil {
-			resp = &types.ExecuteCommandResponse{
-				Success:     false,
-				ExitCode:    -1,
-				Stdout:      "",
-				Stderr:      err.Error(),
-				Message:     "命令执行失败 / Command execution failed",
-				CommandLine: args.Command,
-			}
-		}
+		return nil, nil, err
 	}
 
 	resultJSON, _ := json.MarshalToString(resp)
@@ -584,9 +900,9 @@ func (s *Service) handleExecuteCommand(_ context.Context, _ *mcp.CallToolRequest
 	}, resp, nil
 }
 
-// handleGetCommandBlacklist 处理获取命令黑名单请求 / Handle get command blacklist request
-func (s *Service) handleGetCommandBlacklist(_ context.Context, _ *mcp.CallToolRequest, args types.GetCommandBlacklistRequest) (*mcp.CallToolResult, *types.GetCommandBlacklistResponse, error) {
-	resp, err := s.GetCommandBlacklist(&args)
+// handleExtractArchive 处理解压归档请求 / Handle extract archive request
+func (s *Service) handleExtractArchive(_ context.Context, _ *mcp.CallToolRequest, args types.ExtractArchiveRequest) (*mcp.CallToolResult, *types.ExtractArchiveResponse, error) {
+	resp, err := s.ExtractArchive(&args)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -599,9 +915,9 @@ func (s *Service) handleGetCommandBlacklist(_ context.Context, _ *mcp.CallToolRe
 	}, resp, nil
 }
 
-// handleUpdateCommandBlacklist 处理更新命令黑名单请求 / Handle update command blacklist request
-func (s *Service) handleUpdateCommandBlacklist(_ context.Context, _ *mcp.CallToolRequest, args types.UpdateCommandBlacklistRequest) (*mcp.CallToolResult, *types.UpdateCommandBlacklistResponse, error) {
-	resp, err := s.UpdateCommandBlacklist(&args)
+// handleListTrash 处理列出回收站请求 / Handle list trash request
+func (s *Service) handleListTrash(_ context.Context, _ *mcp.CallToolRequest, args types.ListTrashRequest) (*mcp.CallToolResult, *types.ListTrashResponse, error) {
+	resp, err := s.ListTrash(&args)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -614,9 +930,9 @@ func (s *Service) handleUpdateCommandBlacklist(_ context.Context, _ *mcp.CallToo
 	}, resp, nil
 }
 
-// handleGetWorkingDirectory 处理获取工作目录请求 / Handle get working directory request
-func (s *Service) handleGetWorkingDirectory(_ context.Context, _ *mcp.CallToolRequest, args types.GetWorkingDirectoryRequest) (*mcp.CallToolResult, *types.GetWorkingDirectoryResponse, error) {
-	resp, err := s.GetWorkingDirectory(&args)
+// handleRestoreFromTrash 处理从回收站恢复请求 / Handle restore from trash request
+func (s *Service) handleRestoreFromTrash(_ context.Context, _ *mcp.CallToolRequest, args types.RestoreFromTrashRequest) (*mcp.CallToolResult, *types.RestoreFromTrashResponse, error) {
+	resp, err := s.RestoreFromTrash(&args)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -629,9 +945,9 @@ func (s *Service) handleGetWorkingDirectory(_ context.Context, _ *mcp.CallToolRe
 	}, resp, nil
 }
 
-// handleChangeDirectory 处理切换目录请求 / Handle change directory request
-func (s *Service) handleChangeDirectory(_ context.Context, _ *mcp.CallToolRequest, args types.ChangeDirectoryRequest) (*mcp.CallToolResult, *types.ChangeDirectoryResponse, error) {
-	resp, err := s.ChangeDirectory(&args)
+// handleEmptyTrash 处理清空回收站请求 / Handle empty trash request
+func (s *Service) handleEmptyTrash(_ context.Context, _ *mcp.CallToolRequest, args types.EmptyTrashRequest) (*mcp.CallToolResult, *types.EmptyTrashResponse, error) {
+	resp, err := s.EmptyTrash(&args)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -644,50 +960,456 @@ func (s *Service) handleChangeDirectory(_ context.Context, _ *mcp.CallToolReques
 	}, resp, nil
 }
 
-// RegisterToolsToRegistry 注册所有文件系统工具到工具注册表 / Register all filesystem tools to tool registry
-func (s *Service) RegisterToolsToRegistry(registry *transport.ToolRegistry) {
-	// ==================== File Operation Tools / 文件操作工具 ====================
+// handleCreateSnapshot 处理创建快照请求 / Handle create snapshot request
+func (s *Service) handleCreateSnapshot(_ context.Context, _ *mcp.CallToolRequest, args types.CreateSnapshotRequest) (*mcp.CallToolResult, *types.CreateSnapshotResponse, error) {
+	resp, err := s.CreateSnapshot(&args)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	// Create file / 创建文件
-	registry.RegisterTool(&mcp.Tool{
-		Name:        "create_file",
-		Description: "Create a new file with the specified content. If the file already exists, it will be overwritten. Parent directories will be created automatically if they don't exist. / 创建新文件并写入内容。如果文件已存在则覆盖。父目录不存在时会自动创建。",
-		InputSchema: types.GetToolSchema("create_file"),
-	}, s.wrapCreateFile)
+	resultJSON, _ := json.MarshalToString(resp)
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: resultJSON},
+		},
+	}, resp, nil
+}
 
-	// Create directory / 创建目录
-	registry.RegisterTool(&mcp.Tool{
-		Name:        "create_directory",
-		Description: "Create a new directory. Parent directories will be created automatically if they don't exist (similar to 'mkdir -p'). / 创建新目录。父目录不存在时会自动创建（类似 'mkdir -p'）。",
-		InputSchema: types.GetToolSchema("create_directory"),
-	}, s.wrapCreateDir)
+// handleListSnapshots 处理列出快照请求 / Handle list snapshots request
+func (s *Service) handleListSnapshots(_ context.Context, _ *mcp.CallToolRequest, args types.ListSnapshotsRequest) (*mcp.CallToolResult, *types.ListSnapshotsResponse, error) {
+	resp, err := s.ListSnapshots(&args)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	// Read file / 读取文件
-	registry.RegisterTool(&mcp.Tool{
-		Name:        "read_file",
-		Description: "Read and return the content of a file. Use this to view file contents before making modifications or to understand existing code/configuration. / 读取并返回文件内容。用于在修改前查看文件内容或理解现有代码/配置。",
-		InputSchema: types.GetToolSchema("read_file"),
-	}, s.wrapReadFile)
+	resultJSON, _ := json.MarshalToString(resp)
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: resultJSON},
+		},
+	}, resp, nil
+}
 
-	// Write file / 写入文件
-	registry.RegisterTool(&mcp.Tool{
-		Name:        "write_file",
-		Description: "Write content to an existing file, completely replacing its current content. Use 'create_file' for new files. For partial modifications, read the file first, modify the content, then write back. / 写入内容到现有文件，完全替换当前内容。新文件请使用 'create_file'。部分修改请先读取文件，修改后再写回。",
-		InputSchema: types.GetToolSchema("write_file"),
-	}, s.wrapWriteFile)
+// handleDiffSnapshot 处理快照差异请求 / Handle diff snapshot request
+func (s *Service) handleDiffSnapshot(_ context.Context, _ *mcp.CallToolRequest, args types.DiffSnapshotRequest) (*mcp.CallToolResult, *types.DiffSnapshotResponse, error) {
+	resp, err := s.DiffSnapshot(&args)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	// Delete file or directory (auto-detect) / 删除文件或目录（自动判断）
-	registry.RegisterTool(&mcp.Tool{
-		Name:        "delete",
-		Description: "[RECOMMENDED] Delete a file or directory. This tool automatically detects whether the path is a file or directory and handles it appropriately. For directories, it performs recursive deletion. This is the preferred tool for all deletion operations. / [推荐] 删除文件或目录，自动判断类型。对于目录会递归删除。这是所有删除操作的首选工具。",
-		InputSchema: types.GetToolSchema("delete"),
-	}, s.wrapDelete)
+	resultJSON, _ := json.MarshalToString(resp)
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: resultJSON},
+		},
+	}, resp, nil
+}
 
-	// Delete file only / 删除文件（仅限文件）
-	registry.RegisterTool(&mcp.Tool{
-		Name:        "delete_file",
-		Description: "Delete a specific file. Only works on files, not directories. Use 'delete' tool if you're unsure whether the path is a file or directory. / 删除指定的文件。仅用于删除文件，不能删除目录。如果不确定是文件还是目录，请使用 delete 工具。",
-		InputSchema: types.GetToolSchema("delete_file"),
+// handleRestoreSnapshot 处理恢复快照请求 / Handle restore snapshot request
+func (s *Service) handleRestoreSnapshot(_ context.Context, _ *mcp.CallToolRequest, args types.RestoreSnapshotRequest) (*mcp.CallToolResult, *types.RestoreSnapshotResponse, error) {
+	resp, err := s.RestoreSnapshot(&args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resultJSON, _ := json.MarshalToString(resp)
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: resultJSON},
+		},
+	}, resp, nil
+}
+
+// handleGetQuotaUsage 处理获取配额用量请求 / Handle get quota usage request
+func (s *Service) handleGetQuotaUsage(_ context.Context, _ *mcp.CallToolRequest, args types.GetQuotaUsageRequest) (*mcp.CallToolResult, *types.GetQuotaUsageResponse, error) {
+	resp, err := s.GetQuotaUsage(&args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resultJSON, _ := json.MarshalToString(resp)
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: resultJSON},
+		},
+	}, resp, nil
+}
+
+// handleFileExists 处理文件存在检查请求 / Handle file exists request
+func (s *Service) handleFileExists(_ context.Context, _ *mcp.CallToolRequest, args types.FileExistsRequest) (*mcp.CallToolResult, *types.FileExistsResponse, error) {
+	resp, err := s.FileExists(&args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resultJSON, _ := json.MarshalToString(resp)
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: resultJSON},
+		},
+	}, resp, nil
+}
+
+// handleGetCurrentTime 处理获取当前时间请求 / Handle get current time request
+func (s *Service) handleGetCurrentTime(_ context.Context, _ *mcp.CallToolRequest, args types.GetCurrentTimeRequest) (*mcp.CallToolResult, *types.GetCurrentTimeResponse, error) {
+	resp, err := s.GetCurrentTime(&args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resultJSON, _ := json.MarshalToString(resp)
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: resultJSON},
+		},
+	}, resp, nil
+}
+
+// handleGetSystemInfo 处理获取系统信息请求 / Handle get system info request
+func (s *Service) handleGetSystemInfo(_ context.Context, _ *mcp.CallToolRequest, args types.GetSystemInfoRequest) (*mcp.CallToolResult, *types.GetSystemInfoResponse, error) {
+	resp, err := s.GetSystemInfo(&args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resultJSON, _ := json.MarshalToString(resp)
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: resultJSON},
+		},
+	}, resp, nil
+}
+
+// handleExecuteCommand 处理执行命令请求 / Handle execute command request
+func (s *Service) handleExecuteCommand(_ context.Context, _ *mcp.CallToolRequest, args types.ExecuteCommandRequest) (*mcp.CallToolResult, *types.ExecuteCommandResponse, error) {
+	resp, err := s.ExecuteCommand(&args)
+	// 即使发生错误,也返回响应对象(如果存在) / Return response object even if error occurs (if exists)
+	if err != nil {
+		// 如果响应对象为nil,创建一个错误响应 / If response is nil, create an error response
+		if resp == nil {
+			resp = &types.ExecuteCommandResponse{
+				Success:     false,
+				ExitCode:    -1,
+				Stdout:      "",
+				Stderr:      err.Error(),
+				Message:     "命令执行失败 / Command execution failed",
+				CommandLine: args.Command,
+			}
+		}
+	}
+
+	resultJSON, _ := json.MarshalToString(resp)
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: resultJSON},
+		},
+	}, resp, nil
+}
+
+// handleGetCommandBlacklist 处理获取命令黑名单请求 / Handle get command blacklist request
+func (s *Service) handleGetCommandBlacklist(_ context.Context, _ *mcp.CallToolRequest, args types.GetCommandBlacklistRequest) (*mcp.CallToolResult, *types.GetCommandBlacklistResponse, error) {
+	resp, err := s.GetCommandBlacklist(&args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resultJSON, _ := json.MarshalToString(resp)
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: resultJSON},
+		},
+	}, resp, nil
+}
+
+// handleUpdateCommandBlacklist 处理更新命令黑名单请求 / Handle update command blacklist request
+func (s *Service) handleUpdateCommandBlacklist(_ context.Context, _ *mcp.CallToolRequest, args types.UpdateCommandBlacklistRequest) (*mcp.CallToolResult, *types.UpdateCommandBlacklistResponse, error) {
+	resp, err := s.UpdateCommandBlacklist(&args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resultJSON, _ := json.MarshalToString(resp)
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: resultJSON},
+		},
+	}, resp, nil
+}
+
+// handleGetWorkingDirectory 处理获取工作目录请求 / Handle get working directory request
+func (s *Service) handleGetWorkingDirectory(_ context.Context, _ *mcp.CallToolRequest, args types.GetWorkingDirectoryRequest) (*mcp.CallToolResult, *types.GetWorkingDirectoryResponse, error) {
+	resp, err := s.GetWorkingDirectory(&args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resultJSON, _ := json.MarshalToString(resp)
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: resultJSON},
+		},
+	}, resp, nil
+}
+
+// handleListMounts 处理列出挂载请求 / Handle list mounts request
+func (s *Service) handleListMounts(_ context.Context, _ *mcp.CallToolRequest, args types.ListMountsRequest) (*mcp.CallToolResult, *types.ListMountsResponse, error) {
+	resp, err := s.ListMounts(&args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resultJSON, _ := json.MarshalToString(resp)
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: resultJSON},
+		},
+	}, resp, nil
+}
+
+// handleGetAccessRules 处理获取访问控制规则请求 / Handle get access rules request
+func (s *Service) handleGetAccessRules(_ context.Context, _ *mcp.CallToolRequest, args types.GetAccessRulesRequest) (*mcp.CallToolResult, *types.GetAccessRulesResponse, error) {
+	resp, err := s.GetAccessRules(&args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resultJSON, _ := json.MarshalToString(resp)
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: resultJSON},
+		},
+	}, resp, nil
+}
+
+// handleBeginUpload 处理开始分块上传请求 / Handle begin chunked upload request
+func (s *Service) handleBeginUpload(_ context.Context, _ *mcp.CallToolRequest, args types.BeginUploadRequest) (*mcp.CallToolResult, *types.BeginUploadResponse, error) {
+	resp, err := s.BeginUpload(&args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resultJSON, _ := json.MarshalToString(resp)
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: resultJSON},
+		},
+	}, resp, nil
+}
+
+// handleUploadChunk 处理上传分块请求 / Handle upload chunk request
+func (s *Service) handleUploadChunk(_ context.Context, _ *mcp.CallToolRequest, args types.UploadChunkRequest) (*mcp.CallToolResult, *types.UploadChunkResponse, error) {
+	resp, err := s.UploadChunk(&args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resultJSON, _ := json.MarshalToString(resp)
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: resultJSON},
+		},
+	}, resp, nil
+}
+
+// handleCommitUpload 处理提交分块上传请求 / Handle commit chunked upload request
+func (s *Service) handleCommitUpload(_ context.Context, _ *mcp.CallToolRequest, args types.CommitUploadRequest) (*mcp.CallToolResult, *types.CommitUploadResponse, error) {
+	resp, err := s.CommitUpload(&args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resultJSON, _ := json.MarshalToString(resp)
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: resultJSON},
+		},
+	}, resp, nil
+}
+
+// handleConvertEncoding 处理转换文件编码请求 / Handle convert file encoding request
+func (s *Service) handleConvertEncoding(_ context.Context, _ *mcp.CallToolRequest, args types.ConvertEncodingRequest) (*mcp.CallToolResult, *types.ConvertEncodingResponse, error) {
+	resp, err := s.ConvertEncoding(&args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resultJSON, _ := json.MarshalToString(resp)
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: resultJSON},
+		},
+	}, resp, nil
+}
+
+// handleLockPath 锁定路径 / Lock path
+func (s *Service) handleLockPath(_ context.Context, _ *mcp.CallToolRequest, args types.LockPathRequest) (*mcp.CallToolResult, *types.LockPathResponse, error) {
+	resp, err := s.LockPath(&args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resultJSON, _ := json.MarshalToString(resp)
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: resultJSON},
+		},
+	}, resp, nil
+}
+
+// handleUnlockPath 释放路径锁 / Unlock path
+func (s *Service) handleUnlockPath(_ context.Context, _ *mcp.CallToolRequest, args types.UnlockPathRequest) (*mcp.CallToolResult, *types.UnlockPathResponse, error) {
+	resp, err := s.UnlockPath(&args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resultJSON, _ := json.MarshalToString(resp)
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: resultJSON},
+		},
+	}, resp, nil
+}
+
+// handleQueryData 查询结构化数据 / Query structured data
+func (s *Service) handleQueryData(_ context.Context, _ *mcp.CallToolRequest, args types.QueryDataRequest) (*mcp.CallToolResult, *types.QueryDataResponse, error) {
+	resp, err := s.QueryData(&args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resultJSON, _ := json.MarshalToString(resp)
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: resultJSON},
+		},
+	}, resp, nil
+}
+
+// handleUpdateData 修改结构化数据 / Update structured data
+func (s *Service) handleUpdateData(_ context.Context, _ *mcp.CallToolRequest, args types.UpdateDataRequest) (*mcp.CallToolResult, *types.UpdateDataResponse, error) {
+	resp, err := s.UpdateData(&args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resultJSON, _ := json.MarshalToString(resp)
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: resultJSON},
+		},
+	}, resp, nil
+}
+
+// handleDiskUsage 分析磁盘用量 / Analyse disk usage
+func (s *Service) handleDiskUsage(_ context.Context, _ *mcp.CallToolRequest, args types.DiskUsageRequest) (*mcp.CallToolResult, *types.DiskUsageResponse, error) {
+	resp, err := s.DiskUsage(&args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resultJSON, _ := json.MarshalToString(resp)
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: resultJSON},
+		},
+	}, resp, nil
+}
+
+// handleChangeDirectory 处理切换目录请求 / Handle change directory request
+func (s *Service) handleChangeDirectory(_ context.Context, _ *mcp.CallToolRequest, args types.ChangeDirectoryRequest) (*mcp.CallToolResult, *types.ChangeDirectoryResponse, error) {
+	resp, err := s.ChangeDirectory(&args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resultJSON, _ := json.MarshalToString(resp)
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: resultJSON},
+		},
+	}, resp, nil
+}
+
+// RegisterToolsToRegistry 注册所有文件系统工具到工具注册表 / Register all filesystem tools to tool registry
+func (s *Service) RegisterToolsToRegistry(registry *transport.ToolRegistry) {
+	// ==================== File Operation Tools / 文件操作工具 ====================
+
+	// Create file / 创建文件
+	registry.RegisterTool(&mcp.Tool{
+		Name:        "create_file",
+		Description: "Create a new file with the specified content. If the file already exists, it will be overwritten. Parent directories will be created automatically if they don't exist. / 创建新文件并写入内容。如果文件已存在则覆盖。父目录不存在时会自动创建。",
+		InputSchema: types.GetToolSchema("create_file"),
+	}, s.wrapCreateFile)
+
+	// Create directory / 创建目录
+	registry.RegisterTool(&mcp.Tool{
+		Name:        "create_directory",
+		Description: "Create a new directory. Parent directories will be created automatically if they don't exist (similar to 'mkdir -p'). / 创建新目录。父目录不存在时会自动创建（类似 'mkdir -p'）。",
+		InputSchema: types.GetToolSchema("create_directory"),
+	}, s.wrapCreateDir)
+
+	// Read file / 读取文件
+	registry.RegisterTool(&mcp.Tool{
+		Name:        "read_file",
+		Description: "Read and return the content of a file. Use this to view file contents before making modifications or to understand existing code/configuration. Large files can be read by line range (start_line/end_line), last lines (tail_lines) or byte range (offset/length); images are returned as image content and other binary files as embedded resources. / 读取并返回文件内容。用于在修改前查看文件内容或理解现有代码/配置。大文件可按行范围、末尾行数或字节范围读取；图片以图片内容返回，其他二进制文件以嵌入资源返回。",
+		InputSchema: types.GetToolSchema("read_file"),
+	}, s.wrapReadFile)
+
+	// Write file / 写入文件
+	registry.RegisterTool(&mcp.Tool{
+		Name:        "write_file",
+		Description: "Write content to an existing file, completely replacing its current content. Use 'create_file' for new files. For partial modifications, read the file first, modify the content, then write back. / 写入内容到现有文件，完全替换当前内容。新文件请使用 'create_file'。部分修改请先读取文件，修改后再写回。",
+		InputSchema: types.GetToolSchema("write_file"),
+	}, s.wrapWriteFile)
+
+	// Edit file / 编辑文件
+	registry.RegisterTool(&mcp.Tool{
+		Name:        "edit_file",
+		Description: "Edit a file with a list of exact search/replace edits or a unified diff patch, without resending the whole content. Edits are applied atomically: if any edit or hunk does not apply, the file is left untouched. Returns the resulting unified diff. / 通过查找替换编辑列表或统一差异补丁编辑文件，无需重新发送全部内容。编辑原子应用：任何编辑或补丁块无法应用时文件保持不变。返回变更的统一差异。",
+		InputSchema: types.GetToolSchema("edit_file"),
+	}, s.wrapEditFile)
+
+	// Append file / 追加文件
+	registry.RegisterTool(&mcp.Tool{
+		Name:        "append_file",
+		Description: "Append content to the end of a file, creating the file (and parent directories) if it does not exist. Set ensure_newline to start the content on a new line. Ideal for logs and incremental generation without resending the whole file. / 向文件末尾追加内容，文件（及父目录）不存在时创建。设置 ensure_newline 可使内容从新行开始。适用于日志写入和增量生成，无需重新发送整个文件。",
+		InputSchema: types.GetToolSchema("append_file"),
+	}, s.wrapAppendFile)
+
+	// Insert lines / 插入行
+	registry.RegisterTool(&mcp.Tool{
+		Name:        "insert_lines",
+		Description: "Insert content before a given line of a text file (1-based; total lines + 1 appends at the end). The content gets a trailing newline matching the file's line endings, and the file is rewritten atomically. / 在文本文件的指定行之前插入内容（从1开始；总行数+1表示追加到末尾）。内容会补上与文件一致的结尾换行，文件以原子方式重写。",
+		InputSchema: types.GetToolSchema("insert_lines"),
+	}, s.wrapInsertLines)
+
+	// Delete lines / 删除行
+	registry.RegisterTool(&mcp.Tool{
+		Name:        "delete_lines",
+		Description: "Delete an inclusive line range from a text file (end_line defaults to start_line). The file is rewritten atomically. / 删除文本文件中的一段行（包含两端，end_line 默认等于 start_line）。文件以原子方式重写。",
+		InputSchema: types.GetToolSchema("delete_lines"),
+	}, s.wrapDeleteLines)
+
+	// Truncate file / 截断文件
+	registry.RegisterTool(&mcp.Tool{
+		Name:        "truncate_file",
+		Description: "Truncate a file to the given size in bytes (0 empties it). A larger size extends the file with zero bytes. / 将文件截断为指定字节数（0 表示清空）。更大的大小会以零字节扩展文件。",
+		InputSchema: types.GetToolSchema("truncate_file"),
+	}, s.wrapTruncateFile)
+
+	// Delete file or directory (auto-detect) / 删除文件或目录（自动判断）
+	registry.RegisterTool(&mcp.Tool{
+		Name:        "delete",
+		Description: "[RECOMMENDED] Delete a file or directory. This tool automatically detects whether the path is a file or directory and handles it appropriately. For directories, it performs recursive deletion. This is the preferred tool for all deletion operations. / [推荐] 删除文件或目录，自动判断类型。对于目录会递归删除。这是所有删除操作的首选工具。",
+		InputSchema: types.GetToolSchema("delete"),
+	}, s.wrapDelete)
+
+	// Delete file only / 删除文件（仅限文件）
+	registry.RegisterTool(&mcp.Tool{
+		Name:        "delete_file",
+		Description: "Delete a specific file. Only works on files, not directories. Use 'delete' tool if you're unsure whether the path is a file or directory. / 删除指定的文件。仅用于删除文件，不能删除目录。如果不确定是文件还是目录，请使用 delete 工具。",
+		InputSchema: types.GetToolSchema("delete_file"),
 	}, s.wrapDeleteFile)
 
 	// Delete directory only / 删除目录（仅限目录）
@@ -742,17 +1464,31 @@ func (s *Service) RegisterToolsToRegistry(registry *transport.ToolRegistry) {
 	// List directory / 列出目录
 	registry.RegisterTool(&mcp.Tool{
 		Name:        "list_directory",
-		Description: "List all files and subdirectories in a directory. Returns file names, types (file/directory), sizes, and modification times. Useful for exploring directory structure. / 列出目录中的所有文件和子目录。返回文件名、类型（文件/目录）、大小和修改时间。用于探索目录结构。",
+		Description: "List files and subdirectories in a directory. Returns file names, types (file/directory), sizes, and modification times, together with the total count and a cursor for the next page. Supports sorting by name/size/mtime, type and glob filters, and including hidden entries. / 列出目录中的文件和子目录。返回文件名、类型（文件/目录）、大小和修改时间，以及总数和下一页游标。支持按名称/大小/修改时间排序、类型和glob过滤以及包含隐藏条目。",
 		InputSchema: types.GetToolSchema("list_directory"),
 	}, s.wrapListDir)
 
+	// Directory tree / 目录树
+	registry.RegisterTool(&mcp.Tool{
+		Name:        "directory_tree",
+		Description: "Render a recursive directory tree as structured JSON and/or compact indented text, with aggregate sizes and file counts for each directory, a maximum depth and an entry budget. .git, node_modules and similar noise are excluded by default. / 以结构化JSON和/或紧凑缩进文本渲染递归目录树，包含每个目录的汇总大小和文件数，支持最大深度和条目预算。默认排除 .git、node_modules 等。",
+		InputSchema: types.GetToolSchema("directory_tree"),
+	}, s.wrapDirectoryTree)
+
 	// Search files / 搜索文件
 	registry.RegisterTool(&mcp.Tool{
 		Name:        "search_files",
-		Description: "Search for files matching a pattern within a directory. Supports glob patterns with wildcards (* for any characters, ? for single character). Searches recursively through subdirectories. / 在目录中搜索匹配模式的文件。支持通配符（* 匹配任意字符，? 匹配单个字符）。递归搜索子目录。",
+		Description: "Search for files matching glob patterns within a directory. Supports wildcards (* for any characters, ? for single character, ** for any number of directories), include/exclude pattern lists, type/size/mtime filters, max depth, max results with a truncation flag and sorting. Invalid patterns are reported as errors. / 在目录中搜索匹配glob模式的文件。支持通配符（* 匹配任意字符，? 匹配单个字符，** 匹配任意层目录）、包含/排除模式列表、类型/大小/修改时间过滤、最大深度、带截断标记的结果上限和排序。无效模式会返回错误。",
 		InputSchema: types.GetToolSchema("search_files"),
 	}, s.wrapSearch)
 
+	// Search content / 搜索文件内容
+	registry.RegisterTool(&mcp.Tool{
+		Name:        "search_content",
+		Description: "Search file contents for a regular expression or literal text. Returns the path, line, column and context lines of each matching line, skipping binary files, .git and paths ignored by .gitignore. Results are capped and flagged as truncated when more matches exist. / 在文件内容中搜索正则表达式或字面文本。返回每个匹配行的路径、行号、列号和上下文行，跳过二进制文件、.git 和 .gitignore 忽略的路径。结果有上限，超出时标记为截断。",
+		InputSchema: types.GetToolSchema("search_content"),
+	}, s.wrapSearchContent)
+
 	// Batch delete / 批量删除
 	registry.RegisterTool(&mcp.Tool{
 		Name:        "batch_delete",
@@ -767,6 +1503,97 @@ func (s *Service) RegisterToolsToRegistry(registry *transport.ToolRegistry) {
 		InputSchema: types.GetToolSchema("file_stat"),
 	}, s.wrapFileStat)
 
+	// File hash / 文件哈希
+	registry.RegisterTool(&mcp.Tool{
+		Name:        "file_hash",
+		Description: "Compute checksums (md5, sha1, sha256, sha512 or crc32) of one or more files. Files are streamed so large files are fine; set recursive to hash every file under a directory. Failures are reported per path. / 计算一个或多个文件的校验和（md5、sha1、sha256、sha512 或 crc32）。文件以流式读取，适用于大文件；设置 recursive 可计算目录下所有文件。失败按路径单独报告。",
+		InputSchema: types.GetToolSchema("file_hash"),
+	}, s.wrapFileHash)
+
+	// Compare files / 比较文件
+	registry.RegisterTool(&mcp.Tool{
+		Name:        "compare_files",
+		Description: "Check whether pairs of files have identical content. Files are compared byte by byte as streams; the result reports both sizes and the offset of the first difference. / 检查文件对的内容是否完全相同。文件以流式逐字节比较，结果包含双方大小和第一个不同字节的偏移量。",
+		InputSchema: types.GetToolSchema("compare_files"),
+	}, s.wrapCompareFiles)
+
+	// Diff files / 比较文件差异
+	registry.RegisterTool(&mcp.Tool{
+		Name:        "diff_files",
+		Description: "Show a unified diff between two sandbox files, or between a file and proposed inline content (e.g. preview a write_file: old_path=file, new_content=new text). Returns the diff text plus structured hunks and added/removed counts; context lines and ignore_whitespace are configurable. / 显示两个沙箱文件之间、或文件与内联内容之间的统一差异（例如预览 write_file：old_path=文件，new_content=新内容）。返回差异文本、结构化差异块以及新增/删除行数；可配置上下文行数和 ignore_whitespace。",
+		InputSchema: types.GetToolSchema("diff_files"),
+	}, s.wrapDiffFiles)
+
+	// Create archive / 创建归档
+	registry.RegisterTool(&mcp.Tool{
+		Name:        "create_archive",
+		Description: "Pack files and directories into a zip, tar, tar.gz or tar.zst archive. The format is inferred from the archive extension unless given; entries are named relative to each source's parent directory. / 将文件和目录打包为 zip、tar、tar.gz 或 tar.zst 归档。未指定格式时按扩展名推断；条目名称相对于每个源的父目录。",
+		InputSchema: types.GetToolSchema("create_archive"),
+	}, s.wrapCreateArchive)
+
+	// Extract archive / 解压归档
+	registry.RegisterTool(&mcp.Tool{
+		Name:        "extract_archive",
+		Description: "Extract a zip, tar, tar.gz or tar.zst archive inside the sandbox, or list its contents with list_only. Every entry is checked before anything is written: entries escaping the destination are rejected, links are skipped and the total uncompressed size is limited. / 在沙箱内解压 zip、tar、tar.gz 或 tar.zst 归档，或通过 list_only 仅列出内容。写入前会检查所有条目：拒绝跳出目标目录的条目，跳过链接，并限制未压缩总大小。",
+		InputSchema: types.GetToolSchema("extract_archive"),
+	}, s.wrapExtractArchive)
+
+	// List trash / 列出回收站
+	registry.RegisterTool(&mcp.Tool{
+		Name:        "list_trash",
+		Description: "List entries in the sandbox trash, most recently deleted first, with their original path, deletion time, size and session. Use the id with restore_from_trash. / 列出沙箱回收站中的条目（最近删除的在前），包括原路径、删除时间、大小和会话。可将 id 用于 restore_from_trash。",
+		InputSchema: types.GetToolSchema("list_trash"),
+	}, s.wrapListTrash)
+
+	// Restore from trash / 从回收站恢复
+	registry.RegisterTool(&mcp.Tool{
+		Name:        "restore_from_trash",
+		Description: "Restore an entry from the trash to its original path or to a new destination. Existing paths are only replaced with overwrite, and the replaced content goes to the trash when trash mode is on. / 将回收站中的条目恢复到原路径或新位置。仅在设置 overwrite 时替换已存在的路径，启用回收站模式时被替换的内容同样进入回收站。",
+		InputSchema: types.GetToolSchema("restore_from_trash"),
+	}, s.wrapRestoreFromTrash)
+
+	// Empty trash / 清空回收站
+	registry.RegisterTool(&mcp.Tool{
+		Name:        "empty_trash",
+		Description: "Permanently delete entries from the trash. Without ids the whole trash is emptied. This cannot be undone. / 永久删除回收站中的条目。不指定 ids 时清空整个回收站。此操作无法撤销。",
+		InputSchema: types.GetToolSchema("empty_trash"),
+	}, s.wrapEmptyTrash)
+
+	// Create snapshot / 创建快照
+	registry.RegisterTool(&mcp.Tool{
+		Name:        "create_snapshot",
+		Description: "Capture the current state of the sandbox (or a directory in it) as a snapshot before a risky change. File contents are stored by hash, so unchanged files are shared between snapshots. / 在进行有风险的变更前，将沙箱（或其中的目录）的当前状态保存为快照。文件内容按哈希存储，未改变的文件在快照之间共享。",
+		InputSchema: types.GetToolSchema("create_snapshot"),
+	}, s.wrapCreateSnapshot)
+
+	// List snapshots / 列出快照
+	registry.RegisterTool(&mcp.Tool{
+		Name:        "list_snapshots",
+		Description: "List all snapshots, newest first, with their scope, file count and total size. / 列出所有快照（最新的在前），包括范围、文件数和总大小。",
+		InputSchema: types.GetToolSchema("list_snapshots"),
+	}, s.wrapListSnapshots)
+
+	// Diff snapshot / 快照差异
+	registry.RegisterTool(&mcp.Tool{
+		Name:        "diff_snapshot",
+		Description: "Show which paths were added, modified or deleted since a snapshot. Set content to include unified diffs of modified text files. / 显示自快照以来新增、修改或删除的路径。设置 content 可附带修改的文本文件的统一差异。",
+		InputSchema: types.GetToolSchema("diff_snapshot"),
+	}, s.wrapDiffSnapshot)
+
+	// Restore snapshot / 恢复快照
+	registry.RegisterTool(&mcp.Tool{
+		Name:        "restore_snapshot",
+		Description: "Roll the sandbox (or a path in it) back to a snapshot. Each file is replaced atomically, paths added since the snapshot are removed unless keep_new_files is set, and the response reports every change. Use dry_run to preview. / 将沙箱（或其中的路径）回滚到快照。每个文件都以原子方式替换，除非设置 keep_new_files，否则会删除快照之后新增的路径，响应中报告所有变更。可使用 dry_run 预览。",
+		InputSchema: types.GetToolSchema("restore_snapshot"),
+	}, s.wrapRestoreSnapshot)
+
+	// Get quota usage / 获取配额用量
+	registry.RegisterTool(&mcp.Tool{
+		Name:        "get_quota_usage",
+		Description: "Report the sandbox disk usage (total bytes and file/directory count, including trash and snapshots) against the configured quota, with the remaining allowance. Writes that would exceed the quota fail with a 'sandbox quota exceeded' error. / 报告沙箱磁盘用量（总字节数和文件/目录数，包括回收站和快照）与配置的配额对比以及剩余额度。超出配额的写入会以 'sandbox quota exceeded' 错误失败。",
+		InputSchema: types.GetToolSchema("get_quota_usage"),
+	}, s.wrapGetQuotaUsage)
+
 	// File exists / 文件是否存在
 	registry.RegisterTool(&mcp.Tool{
 		Name:        "file_exists",
@@ -827,10 +1654,87 @@ func (s *Service) RegisterToolsToRegistry(registry *transport.ToolRegistry) {
 	// Get working directory / 获取当前工作目录
 	registry.RegisterTool(&mcp.Tool{
 		Name:        "get_working_directory",
-		Description: "Get the current working directory path. Returns the absolute path of the current working directory. / 获取当前工作目录路径。返回当前工作目录的绝对路径。",
+		Description: "Get the current working directory path, the mount it is on and whether that mount is read-only. / 获取当前工作目录路径、所在挂载以及该挂载是否只读。",
 		InputSchema: types.GetToolSchema("get_working_directory"),
 	}, s.wrapGetWorkingDirectory)
 
+	// List mounts / 列出挂载
+	registry.RegisterTool(&mcp.Tool{
+		Name:        "list_mounts",
+		Description: "List the sandbox root and the named mounts with their host paths and read-only/read-write modes. Paths in other tools address a mount as 'name:relative/path'; unprefixed paths resolve to the sandbox root. / 列出沙箱根目录和命名挂载及其宿主路径和只读/读写模式。其他工具通过'名称:相对路径'访问挂载,无前缀的路径相对于沙箱根目录。",
+		InputSchema: types.GetToolSchema("list_mounts"),
+	}, s.wrapListMounts)
+
+	// Get access rules / 获取访问控制规则
+	registry.RegisterTool(&mcp.Tool{
+		Name:        "get_access_rules",
+		Description: "List the path access control rules in evaluation order. Rules are glob patterns denying or allowing the read, write, delete and list operation classes; the first matching rule decides. Pass a path to see which operations are allowed on it and which rule decided. / 按评估顺序列出路径访问控制规则。规则为拒绝或允许read、write、delete、list操作类别的glob模式,第一条匹配的规则生效。传入路径可查看该路径允许哪些操作以及由哪条规则决定。",
+		InputSchema: types.GetToolSchema("get_access_rules"),
+	}, s.wrapGetAccessRules)
+
+	// Begin upload / 开始分块上传
+	registry.RegisterTool(&mcp.Tool{
+		Name:        "begin_upload",
+		Description: "Begin a chunked upload of a large file. Returns an upload_id for upload_chunk and commit_upload and the maximum chunk size. Declare the total size to check it against the file size limit and quota up front. Sessions expire after 15 minutes without activity. / 开始大文件的分块上传。返回用于upload_chunk和commit_upload的upload_id以及分块大小上限。声明总大小可提前检查文件大小限制和配额。会话在15分钟无活动后过期。",
+		InputSchema: types.GetToolSchema("begin_upload"),
+	}, s.wrapBeginUpload)
+
+	// Upload chunk / 上传分块
+	registry.RegisterTool(&mcp.Tool{
+		Name:        "upload_chunk",
+		Description: "Upload one base64 encoded chunk of a chunked upload at a byte offset. The offset must not exceed the bytes received so far; resending an earlier chunk overwrites it. The file size limit and quota are checked as chunks arrive. Returns the bytes received, which is the offset of the next chunk. / 在指定字节偏移上传一个Base64编码的分块。偏移不能超过已接收的字节数,重发较早的分块会覆盖原内容。每个分块到达时检查文件大小限制和配额。返回已接收的字节数,即下一个分块的偏移。",
+		InputSchema: types.GetToolSchema("upload_chunk"),
+	}, s.wrapUploadChunk)
+
+	// Commit upload / 提交分块上传
+	registry.RegisterTool(&mcp.Tool{
+		Name:        "commit_upload",
+		Description: "Finish a chunked upload and atomically move the file into place. Pass the SHA-256 of the whole content to verify it; on a mismatch the upload is discarded. Fails if a declared size was not fully received. / 完成分块上传并以原子方式将文件移动到目标位置。传入完整内容的SHA-256进行校验,不匹配时放弃上传。声明的大小未完全接收时失败。",
+		InputSchema: types.GetToolSchema("commit_upload"),
+	}, s.wrapCommitUpload)
+
+	// Convert encoding / 转换文件编码
+	registry.RegisterTool(&mcp.Tool{
+		Name:        "convert_encoding",
+		Description: "Re-encode a text file in place between utf-8, utf-16le, utf-16be, gbk, gb18030 and shift_jis, optionally writing a byte order mark and normalising line endings to lf or crlf. The source charset is detected when from is omitted. / 在utf-8、utf-16le、utf-16be、gbk、gb18030和shift_jis之间就地转换文本文件的编码,可写入字节顺序标记并将换行符统一为lf或crlf。未指定from时自动检测源字符集。",
+		InputSchema: types.GetToolSchema("convert_encoding"),
+	}, s.wrapConvertEncoding)
+
+	// LockPath 锁定路径 / Lock path
+	registry.RegisterTool(&mcp.Tool{
+		Name:        "lock_path",
+		Description: "Reserve a file or directory with an advisory lease that conflicts with other lock_path calls on overlapping paths (咨询性地锁定文件或目录)",
+		InputSchema: types.GetToolSchema("lock_path"),
+	}, s.wrapLockPath)
+
+	// UnlockPath 释放路径锁 / Unlock path
+	registry.RegisterTool(&mcp.Tool{
+		Name:        "unlock_path",
+		Description: "Release an advisory lease taken with lock_path (释放lock_path获取的租约)",
+		InputSchema: types.GetToolSchema("unlock_path"),
+	}, s.wrapUnlockPath)
+
+	// QueryData 查询结构化数据 / Query data
+	registry.RegisterTool(&mcp.Tool{
+		Name:        "query_data",
+		Description: "Evaluate a JSONPath-style expression against a JSON, YAML, TOML or CSV file and return only the matching values (用JSONPath风格的表达式查询JSON、YAML、TOML或CSV文件)",
+		InputSchema: types.GetToolSchema("query_data"),
+	}, s.wrapQueryData)
+
+	// UpdateData 修改结构化数据 / Update data
+	registry.RegisterTool(&mcp.Tool{
+		Name:        "update_data",
+		Description: "Set or delete values in a JSON, YAML, TOML or CSV file at a JSONPath-style expression, keeping the original formatting where possible (在JSON、YAML、TOML或CSV文件中设置或删除值,尽可能保留原格式)",
+		InputSchema: types.GetToolSchema("update_data"),
+	}, s.wrapUpdateData)
+
+	// DiskUsage 分析磁盘用量 / Disk usage
+	registry.RegisterTool(&mcp.Tool{
+		Name:        "disk_usage",
+		Description: "Report the largest files and directories, usage by extension and counts for a directory, with depth and walk time limits (分析目录的磁盘用量:最大的文件和目录、按扩展名的用量和计数)",
+		InputSchema: types.GetToolSchema("disk_usage"),
+	}, s.wrapDiskUsage)
+
 	// Change directory / 切换工作目录
 	registry.RegisterTool(&mcp.Tool{
 		Name:        "change_directory",
@@ -859,255 +1763,515 @@ func (s *Service) RegisterToolsToRegistry(registry *transport.ToolRegistry) {
 		InputSchema: types.GetToolSchema("cancel_command_task"),
 	}, s.wrapCancelCommandTask)
 
-	// Get command history / 获取命令历史
-	registry.RegisterTool(&mcp.Tool{
-		Name:        "get_command_history",
-		Description: "Get the history of executed commands. Returns a list of previously executed commands with their results. / 获取命令执行历史记录。返回之前执行的命令及其结果列表。",
-		InputSchema: types.GetToolSchema("get_command_history"),
-	}, s.wrapGetCommandHistory)
+	// Get command history / 获取命令历史
+	registry.RegisterTool(&mcp.Tool{
+		Name:        "get_command_history",
+		Description: "Get the history of executed commands. Returns a list of previously executed commands with their results. / 获取命令执行历史记录。返回之前执行的命令及其结果列表。",
+		InputSchema: types.GetToolSchema("get_command_history"),
+	}, s.wrapGetCommandHistory)
+
+	// Clear command history / 清空命令历史
+	registry.RegisterTool(&mcp.Tool{
+		Name:        "clear_command_history",
+		Description: "Clear all command execution history records. This action cannot be undone. / 清空所有命令执行历史记录。此操作不可撤销。",
+		InputSchema: types.GetToolSchema("clear_command_history"),
+	}, s.wrapClearCommandHistory)
+
+	// Set permission level / 设置权限级别
+	registry.RegisterTool(&mcp.Tool{
+		Name:        "set_permission_level",
+		Description: "Set the command execution permission level. Higher levels allow more privileged operations. Level 0 is most restrictive, level 3 is least restrictive. / 设置命令执行权限级别。级别越高允许的操作越多。级别0最严格，级别3最宽松。",
+		InputSchema: types.GetToolSchema("set_permission_level"),
+	}, s.wrapSetPermissionLevel)
+
+	// Get permission level / 获取权限级别
+	registry.RegisterTool(&mcp.Tool{
+		Name:        "get_permission_level",
+		Description: "Get the current command execution permission level. Returns the current level (0-3) and its description. / 获取当前命令执行权限级别。返回当前级别（0-3）及其描述。",
+		InputSchema: types.GetToolSchema("get_permission_level"),
+	}, s.wrapGetPermissionLevel)
+}
+
+// 包装函数，将MCP处理器转换为ToolHandler / Wrapper functions to convert MCP handlers to ToolHandler
+
+func (s *Service) wrapCreateFile(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
+	argsJSON, err := json.Marshal(arguments)
+	if err != nil {
+		return nil, err
+	}
+	var args types.CreateFileRequest
+	if err = json.Unmarshal(argsJSON, &args); err != nil {
+		return nil, err
+	}
+	result, _, err := s.handleCreateFile(ctx, nil, args)
+	return result, err
+}
+
+func (s *Service) wrapCreateDir(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
+	argsJSON, err := json.Marshal(arguments)
+	if err != nil {
+		return nil, err
+	}
+	var args types.CreateDirRequest
+	if err = json.Unmarshal(argsJSON, &args); err != nil {
+		return nil, err
+	}
+	result, _, err := s.handleCreateDir(ctx, nil, args)
+	return result, err
+}
+
+func (s *Service) wrapReadFile(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
+	argsJSON, err := json.Marshal(arguments)
+	if err != nil {
+		return nil, err
+	}
+	var args types.ReadFileRequest
+	if err = json.Unmarshal(argsJSON, &args); err != nil {
+		return nil, err
+	}
+	result, _, err := s.handleReadFile(ctx, nil, args)
+	return result, err
+}
+
+func (s *Service) wrapWriteFile(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
+	argsJSON, err := json.Marshal(arguments)
+	if err != nil {
+		return nil, err
+	}
+	var args types.WriteFileRequest
+	if err = json.Unmarshal(argsJSON, &args); err != nil {
+		return nil, err
+	}
+	result, _, err := s.handleWriteFile(ctx, nil, args)
+	return result, err
+}
+
+func (s *Service) wrapEditFile(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
+	argsJSON, err := json.Marshal(arguments)
+	if err != nil {
+		return nil, err
+	}
+	var args types.EditFileRequest
+	if err = json.Unmarshal(argsJSON, &args); err != nil {
+		return nil, err
+	}
+	result, _, err := s.handleEditFile(ctx, nil, args)
+	return result, err
+}
+
+func (s *Service) wrapAppendFile(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
+	argsJSON, err := json.Marshal(arguments)
+	if err != nil {
+		return nil, err
+	}
+	var args types.AppendFileRequest
+	if err = json.Unmarshal(argsJSON, &args); err != nil {
+		return nil, err
+	}
+	result, _, err := s.handleAppendFile(ctx, nil, args)
+	return result, err
+}
+
+func (s *Service) wrapInsertLines(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
+	argsJSON, err := json.Marshal(arguments)
+	if err != nil {
+		return nil, err
+	}
+	var args types.InsertLinesRequest
+	if err = json.Unmarshal(argsJSON, &args); err != nil {
+		return nil, err
+	}
+	result, _, err := s.handleInsertLines(ctx, nil, args)
+	return result, err
+}
+
+func (s *Service) wrapDeleteLines(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
+	argsJSON, err := json.Marshal(arguments)
+	if err != nil {
+		return nil, err
+	}
+	var args types.DeleteLinesRequest
+	if err = json.Unmarshal(argsJSON, &args); err != nil {
+		return nil, err
+	}
+	result, _, err := s.handleDeleteLines(ctx, nil, args)
+	return result, err
+}
+
+func (s *Service) wrapTruncateFile(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
+	argsJSON, err := json.Marshal(arguments)
+	if err != nil {
+		return nil, err
+	}
+	var args types.TruncateFileRequest
+	if err = json.Unmarshal(argsJSON, &args); err != nil {
+		return nil, err
+	}
+	result, _, err := s.handleTruncateFile(ctx, nil, args)
+	return result, err
+}
+
+func (s *Service) wrapDelete(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
+	argsJSON, err := json.Marshal(arguments)
+	if err != nil {
+		return nil, err
+	}
+	var args types.DeleteRequest
+	if err = json.Unmarshal(argsJSON, &args); err != nil {
+		return nil, err
+	}
+	result, _, err := s.handleDelete(ctx, nil, args)
+	return result, err
+}
+
+func (s *Service) wrapDeleteFile(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
+	argsJSON, err := json.Marshal(arguments)
+	if err != nil {
+		return nil, err
+	}
+	var args types.DeleteFileRequest
+	if err = json.Unmarshal(argsJSON, &args); err != nil {
+		return nil, err
+	}
+	result, _, err := s.handleDeleteFile(ctx, nil, args)
+	return result, err
+}
+
+func (s *Service) wrapDeleteDirectory(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
+	argsJSON, err := json.Marshal(arguments)
+	if err != nil {
+		return nil, err
+	}
+	var args types.DeleteDirectoryRequest
+	if err = json.Unmarshal(argsJSON, &args); err != nil {
+		return nil, err
+	}
+	result, _, err := s.handleDeleteDirectory(ctx, nil, args)
+	return result, err
+}
+
+func (s *Service) wrapCopy(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
+	argsJSON, err := json.Marshal(arguments)
+	if err != nil {
+		return nil, err
+	}
+	var args types.CopyRequest
+	if err = json.Unmarshal(argsJSON, &args); err != nil {
+		return nil, err
+	}
+	result, _, err := s.handleCopy(ctx, nil, args)
+	return result, err
+}
+
+func (s *Service) wrapCopyFile(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
+	argsJSON, err := json.Marshal(arguments)
+	if err != nil {
+		return nil, err
+	}
+	var args types.CopyFileRequest
+	if err = json.Unmarshal(argsJSON, &args); err != nil {
+		return nil, err
+	}
+	result, _, err := s.handleCopyFile(ctx, nil, args)
+	return result, err
+}
+
+func (s *Service) wrapCopyDirectory(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
+	argsJSON, err := json.Marshal(arguments)
+	if err != nil {
+		return nil, err
+	}
+	var args types.CopyDirectoryRequest
+	if err = json.Unmarshal(argsJSON, &args); err != nil {
+		return nil, err
+	}
+	result, _, err := s.handleCopyDirectory(ctx, nil, args)
+	return result, err
+}
+
+func (s *Service) wrapMove(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
+	argsJSON, err := json.Marshal(arguments)
+	if err != nil {
+		return nil, err
+	}
+	var args types.MoveRequest
+	if err = json.Unmarshal(argsJSON, &args); err != nil {
+		return nil, err
+	}
+	result, _, err := s.handleMove(ctx, nil, args)
+	return result, err
+}
 
-	// Clear command history / 清空命令历史
-	registry.RegisterTool(&mcp.Tool{
-		Name:        "clear_command_history",
-		Description: "Clear all command execution history records. This action cannot be undone. / 清空所有命令执行历史记录。此操作不可撤销。",
-		InputSchema: types.GetToolSchema("clear_command_history"),
-	}, s.wrapClearCommandHistory)
+func (s *Service) wrapMoveFile(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
+	argsJSON, err := json.Marshal(arguments)
+	if err != nil {
+		return nil, err
+	}
+	var args types.MoveFileRequest
+	if err = json.Unmarshal(argsJSON, &args); err != nil {
+		return nil, err
+	}
+	result, _, err := s.handleMoveFile(ctx, nil, args)
+	return result, err
+}
 
-	// Set permission level / 设置权限级别
-	registry.RegisterTool(&mcp.Tool{
-		Name:        "set_permission_level",
-		Description: "Set the command execution permission level. Higher levels allow more privileged operations. Level 0 is most restrictive, level 3 is least restrictive. / 设置命令执行权限级别。级别越高允许的操作越多。级别0最严格，级别3最宽松。",
-		InputSchema: types.GetToolSchema("set_permission_level"),
-	}, s.wrapSetPermissionLevel)
+func (s *Service) wrapMoveDirectory(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
+	argsJSON, err := json.Marshal(arguments)
+	if err != nil {
+		return nil, err
+	}
+	var args types.MoveDirectoryRequest
+	if err = json.Unmarshal(argsJSON, &args); err != nil {
+		return nil, err
+	}
+	result, _, err := s.handleMoveDirectory(ctx, nil, args)
+	return result, err
+}
 
-	// Get permission level / 获取权限级别
-	registry.RegisterTool(&mcp.Tool{
-		Name:        "get_permission_level",
-		Description: "Get the current command execution permission level. Returns the current level (0-3) and its description. / 获取当前命令执行权限级别。返回当前级别（0-3）及其描述。",
-		InputSchema: types.GetToolSchema("get_permission_level"),
-	}, s.wrapGetPermissionLevel)
+func (s *Service) wrapListDir(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
+	argsJSON, err := json.Marshal(arguments)
+	if err != nil {
+		return nil, err
+	}
+	var args types.ListDirRequest
+	if err = json.Unmarshal(argsJSON, &args); err != nil {
+		return nil, err
+	}
+	result, _, err := s.handleListDir(ctx, nil, args)
+	return result, err
 }
 
-// 包装函数，将MCP处理器转换为ToolHandler / Wrapper functions to convert MCP handlers to ToolHandler
+func (s *Service) wrapDirectoryTree(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
+	argsJSON, err := json.Marshal(arguments)
+	if err != nil {
+		return nil, err
+	}
+	var args types.DirectoryTreeRequest
+	if err = json.Unmarshal(argsJSON, &args); err != nil {
+		return nil, err
+	}
+	result, _, err := s.handleDirectoryTree(ctx, nil, args)
+	return result, err
+}
 
-func (s *Service) wrapCreateFile(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
+func (s *Service) wrapSearch(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
 	argsJSON, err := json.Marshal(arguments)
 	if err != nil {
 		return nil, err
 	}
-	var args types.CreateFileRequest
+	var args types.SearchRequest
 	if err = json.Unmarshal(argsJSON, &args); err != nil {
 		return nil, err
 	}
-	result, _, err := s.handleCreateFile(ctx, nil, args)
+	result, _, err := s.handleSearch(ctx, nil, args)
 	return result, err
 }
 
-func (s *Service) wrapCreateDir(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
+func (s *Service) wrapSearchContent(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
 	argsJSON, err := json.Marshal(arguments)
 	if err != nil {
 		return nil, err
 	}
-	var args types.CreateDirRequest
+	var args types.SearchContentRequest
 	if err = json.Unmarshal(argsJSON, &args); err != nil {
 		return nil, err
 	}
-	result, _, err := s.handleCreateDir(ctx, nil, args)
+	result, _, err := s.handleSearchContent(ctx, nil, args)
 	return result, err
 }
 
-func (s *Service) wrapReadFile(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
+func (s *Service) wrapBatchDelete(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
 	argsJSON, err := json.Marshal(arguments)
 	if err != nil {
 		return nil, err
 	}
-	var args types.ReadFileRequest
+	var args types.BatchDeleteRequest
 	if err = json.Unmarshal(argsJSON, &args); err != nil {
 		return nil, err
 	}
-	result, _, err := s.handleReadFile(ctx, nil, args)
+	result, _, err := s.handleBatchDelete(ctx, nil, args)
 	return result, err
 }
 
-func (s *Service) wrapWriteFile(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
+func (s *Service) wrapFileStat(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
 	argsJSON, err := json.Marshal(arguments)
 	if err != nil {
 		return nil, err
 	}
-	var args types.WriteFileRequest
+	var args types.FileStatRequest
 	if err = json.Unmarshal(argsJSON, &args); err != nil {
 		return nil, err
 	}
-	result, _, err := s.handleWriteFile(ctx, nil, args)
+	result, _, err := s.handleFileStat(ctx, nil, args)
 	return result, err
 }
 
-func (s *Service) wrapDelete(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
+func (s *Service) wrapFileHash(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
 	argsJSON, err := json.Marshal(arguments)
 	if err != nil {
 		return nil, err
 	}
-	var args types.DeleteRequest
+	var args types.FileHashRequest
 	if err = json.Unmarshal(argsJSON, &args); err != nil {
 		return nil, err
 	}
-	result, _, err := s.handleDelete(ctx, nil, args)
+	result, _, err := s.handleFileHash(ctx, nil, args)
 	return result, err
 }
 
-func (s *Service) wrapDeleteFile(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
+func (s *Service) wrapCompareFiles(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
 	argsJSON, err := json.Marshal(arguments)
 	if err != nil {
 		return nil, err
 	}
-	var args types.DeleteFileRequest
+	var args types.CompareFilesRequest
 	if err = json.Unmarshal(argsJSON, &args); err != nil {
 		return nil, err
 	}
-	result, _, err := s.handleDeleteFile(ctx, nil, args)
+	result, _, err := s.handleCompareFiles(ctx, nil, args)
 	return result, err
 }
 
-func (s *Service) wrapDeleteDirectory(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
+func (s *Service) wrapDiffFiles(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
 	argsJSON, err := json.Marshal(arguments)
 	if err != nil {
 		return nil, err
 	}
-	var args types.DeleteDirectoryRequest
+	var args types.DiffFilesRequest
 	if err = json.Unmarshal(argsJSON, &args); err != nil {
 		return nil, err
 	}
-	result, _, err := s.handleDeleteDirectory(ctx, nil, args)
+	result, _, err := s.handleDiffFiles(ctx, nil, args)
 	return result, err
 }
 
-func (s *Service) wrapCopy(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
+func (s *Service) wrapCreateArchive(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
 	argsJSON, err := json.Marshal(arguments)
 	if err != nil {
 		return nil, err
 	}
-	var args types.CopyRequest
+	var args types.CreateArchiveRequest
 	if err = json.Unmarshal(argsJSON, &args); err != nil {
 		return nil, err
 	}
-	result, _, err := s.handleCopy(ctx, nil, args)
+	result, _, err := s.handleCreateArchive(ctx, nil, args)
 	return result, err
 }
 
-func (s *Service) wrapCopyFile(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
+func (s *Service) wrapExtractArchive(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
 	argsJSON, err := json.Marshal(arguments)
 	if err != nil {
 		return nil, err
 	}
-	var args types.CopyFileRequest
+	var args types.ExtractArchiveRequest
 	if err = json.Unmarshal(argsJSON, &args); err != nil {
 		return nil, err
 	}
-	result, _, err := s.handleCopyFile(ctx, nil, args)
+	result, _, err := s.handleExtractArchive(ctx, nil, args)
 	return result, err
 }
 
-func (s *Service) wrapCopyDirectory(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
+func (s *Service) wrapListTrash(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
 	argsJSON, err := json.Marshal(arguments)
 	if err != nil {
 		return nil, err
 	}
-	var args types.CopyDirectoryRequest
+	var args types.ListTrashRequest
 	if err = json.Unmarshal(argsJSON, &args); err != nil {
 		return nil, err
 	}
-	result, _, err := s.handleCopyDirectory(ctx, nil, args)
+	result, _, err := s.handleListTrash(ctx, nil, args)
 	return result, err
 }
 
-func (s *Service) wrapMove(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
+func (s *Service) wrapRestoreFromTrash(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
 	argsJSON, err := json.Marshal(arguments)
 	if err != nil {
 		return nil, err
 	}
-	var args types.MoveRequest
+	var args types.RestoreFromTrashRequest
 	if err = json.Unmarshal(argsJSON, &args); err != nil {
 		return nil, err
 	}
-	result, _, err := s.handleMove(ctx, nil, args)
+	result, _, err := s.handleRestoreFromTrash(ctx, nil, args)
 	return result, err
 }
 
-func (s *Service) wrapMoveFile(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
+func (s *Service) wrapEmptyTrash(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
 	argsJSON, err := json.Marshal(arguments)
 	if err != nil {
 		return nil, err
 	}
-	var args types.MoveFileRequest
+	var args types.EmptyTrashRequest
 	if err = json.Unmarshal(argsJSON, &args); err != nil {
 		return nil, err
 	}
-	result, _, err := s.handleMoveFile(ctx, nil, args)
+	result, _, err := s.handleEmptyTrash(ctx, nil, args)
 	return result, err
 }
 
-func (s *Service) wrapMoveDirectory(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
+func (s *Service) wrapCreateSnapshot(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
 	argsJSON, err := json.Marshal(arguments)
 	if err != nil {
 		return nil, err
 	}
-	var args types.MoveDirectoryRequest
+	var args types.CreateSnapshotRequest
 	if err = json.Unmarshal(argsJSON, &args); err != nil {
 		return nil, err
 	}
-	result, _, err := s.handleMoveDirectory(ctx, nil, args)
+	result, _, err := s.handleCreateSnapshot(ctx, nil, args)
 	return result, err
 }
 
-func (s *Service) wrapListDir(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
+func (s *Service) wrapListSnapshots(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
 	argsJSON, err := json.Marshal(arguments)
 	if err != nil {
 		return nil, err
 	}
-	var args types.ListDirRequest
+	var args types.ListSnapshotsRequest
 	if err = json.Unmarshal(argsJSON, &args); err != nil {
 		return nil, err
 	}
-	result, _, err := s.handleListDir(ctx, nil, args)
+	result, _, err := s.handleListSnapshots(ctx, nil, args)
 	return result, err
 }
 
-func (s *Service) wrapSearch(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
+func (s *Service) wrapDiffSnapshot(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
 	argsJSON, err := json.Marshal(arguments)
 	if err != nil {
 		return nil, err
 	}
-	var args types.SearchRequest
+	var args types.DiffSnapshotRequest
 	if err = json.Unmarshal(argsJSON, &args); err != nil {
 		return nil, err
 	}
-	result, _, err := s.handleSearch(ctx, nil, args)
+	result, _, err := s.handleDiffSnapshot(ctx, nil, args)
 	return result, err
 }
 
-func (s *Service) wrapBatchDelete(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
+func (s *Service) wrapRestoreSnapshot(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
 	argsJSON, err := json.Marshal(arguments)
 	if err != nil {
 		return nil, err
 	}
-	var args types.BatchDeleteRequest
+	var args types.RestoreSnapshotRequest
 	if err = json.Unmarshal(argsJSON, &args); err != nil {
 		return nil, err
 	}
-	result, _, err := s.handleBatchDelete(ctx, nil, args)
+	result, _, err := s.handleRestoreSnapshot(ctx, nil, args)
 	return result, err
 }
 
-func (s *Service) wrapFileStat(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
+func (s *Service) wrapGetQuotaUsage(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
 	argsJSON, err := json.Marshal(arguments)
 	if err != nil {
 		return nil, err
 	}
-	var args types.FileStatRequest
+	var args types.GetQuotaUsageRequest
 	if err = json.Unmarshal(argsJSON, &args); err != nil {
 		return nil, err
 	}
-	result, _, err := s.handleFileStat(ctx, nil, args)
+	result, _, err := s.handleGetQuotaUsage(ctx, nil, args)
 	return result, err
 }
 
@@ -1216,6 +2380,149 @@ func (s *Service) wrapGetWorkingDirectory(ctx context.Context, arguments interfa
 	return result, err
 }
 
+func (s *Service) wrapListMounts(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
+	argsJSON, err := json.Marshal(arguments)
+	if err != nil {
+		return nil, err
+	}
+	var args types.ListMountsRequest
+	if err = json.Unmarshal(argsJSON, &args); err != nil {
+		return nil, err
+	}
+	result, _, err := s.handleListMounts(ctx, nil, args)
+	return result, err
+}
+
+func (s *Service) wrapGetAccessRules(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
+	argsJSON, err := json.Marshal(arguments)
+	if err != nil {
+		return nil, err
+	}
+	var args types.GetAccessRulesRequest
+	if err = json.Unmarshal(argsJSON, &args); err != nil {
+		return nil, err
+	}
+	result, _, err := s.handleGetAccessRules(ctx, nil, args)
+	return result, err
+}
+
+func (s *Service) wrapBeginUpload(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
+	argsJSON, err := json.Marshal(arguments)
+	if err != nil {
+		return nil, err
+	}
+	var args types.BeginUploadRequest
+	if err = json.Unmarshal(argsJSON, &args); err != nil {
+		return nil, err
+	}
+	result, _, err := s.handleBeginUpload(ctx, nil, args)
+	return result, err
+}
+
+func (s *Service) wrapUploadChunk(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
+	argsJSON, err := json.Marshal(arguments)
+	if err != nil {
+		return nil, err
+	}
+	var args types.UploadChunkRequest
+	if err = json.Unmarshal(argsJSON, &args); err != nil {
+		return nil, err
+	}
+	result, _, err := s.handleUploadChunk(ctx, nil, args)
+	return result, err
+}
+
+func (s *Service) wrapCommitUpload(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
+	argsJSON, err := json.Marshal(arguments)
+	if err != nil {
+		return nil, err
+	}
+	var args types.CommitUploadRequest
+	if err = json.Unmarshal(argsJSON, &args); err != nil {
+		return nil, err
+	}
+	result, _, err := s.handleCommitUpload(ctx, nil, args)
+	return result, err
+}
+
+func (s *Service) wrapConvertEncoding(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
+	argsJSON, err := json.Marshal(arguments)
+	if err != nil {
+		return nil, err
+	}
+	var args types.ConvertEncodingRequest
+	if err = json.Unmarshal(argsJSON, &args); err != nil {
+		return nil, err
+	}
+	result, _, err := s.handleConvertEncoding(ctx, nil, args)
+	return result, err
+}
+
+func (s *Service) wrapLockPath(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
+	argsJSON, err := json.Marshal(arguments)
+	if err != nil {
+		return nil, err
+	}
+	var args types.LockPathRequest
+	if err = json.Unmarshal(argsJSON, &args); err != nil {
+		return nil, err
+	}
+	result, _, err := s.handleLockPath(ctx, nil, args)
+	return result, err
+}
+
+func (s *Service) wrapUnlockPath(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
+	argsJSON, err := json.Marshal(arguments)
+	if err != nil {
+		return nil, err
+	}
+	var args types.UnlockPathRequest
+	if err = json.Unmarshal(argsJSON, &args); err != nil {
+		return nil, err
+	}
+	result, _, err := s.handleUnlockPath(ctx, nil, args)
+	return result, err
+}
+
+func (s *Service) wrapQueryData(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
+	argsJSON, err := json.Marshal(arguments)
+	if err != nil {
+		return nil, err
+	}
+	var args types.QueryDataRequest
+	if err = json.Unmarshal(argsJSON, &args); err != nil {
+		return nil, err
+	}
+	result, _, err := s.handleQueryData(ctx, nil, args)
+	return result, err
+}
+
+func (s *Service) wrapUpdateData(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
+	argsJSON, err := json.Marshal(arguments)
+	if err != nil {
+		return nil, err
+	}
+	var args types.UpdateDataRequest
+	if err = json.Unmarshal(argsJSON, &args); err != nil {
+		return nil, err
+	}
+	result, _, err := s.handleUpdateData(ctx, nil, args)
+	return result, err
+}
+
+func (s *Service) wrapDiskUsage(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
+	argsJSON, err := json.Marshal(arguments)
+	if err != nil {
+		return nil, err
+	}
+	var args types.DiskUsageRequest
+	if err = json.Unmarshal(argsJSON, &args); err != nil {
+		return nil, err
+	}
+	result, _, err := s.handleDiskUsage(ctx, nil, args)
+	return result, err
+}
+
 func (s *Service) wrapChangeDirectory(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
 	argsJSON, err := json.Marshal(arguments)
 	if err != nil {
@@ -102,6 +102,53 @@ func TestHandleReadFile(t *testing.T) {
 	assert.NotNil(t, result)
 	assert.NotNil(t, resp)
 	assert.Equal(t, testContent, resp.Content)
+
+	// 文本以纯文本返回,随后是元数据 / Text is returned as plain text followed by the metadata
+	require.Len(t, result.Content, 2)
+	text, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+	assert.Equal(t, testContent, text.Text)
+	metadata, ok := result.Content[1].(*mcp.TextContent)
+	require.True(t, ok)
+	assert.Contains(t, metadata.Text, `"size":12`)
+	assert.NotContains(t, metadata.Text, testContent)
+}
+
+func TestHandleReadFileBinaryContent(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	png := []byte{0x89, 'P', 'N', 'G', 0x0d, 0x0a, 0x1a, 0x0a, 0x00, 0x00, 0x00, 0x0d, 'I', 'H', 'D', 'R'}
+	blob := []byte{0x00, 0x01, 0x02, 0xff, 0xfe}
+	require.NoError(t, os.MkdirAll(filepath.Join(tempDir, "assets"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "assets", "logo.png"), png, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "data.bin"), blob, 0644))
+
+	// 完整读取的图片作为图片内容返回 / A wholly read image is returned as image content
+	result, _, err := service.handleReadFile(context.Background(), nil, types.ReadFileRequest{Path: "assets/logo.png"})
+	require.NoError(t, err)
+	image, ok := result.Content[0].(*mcp.ImageContent)
+	require.True(t, ok)
+	assert.Equal(t, "image/png", image.MIMEType)
+	assert.Equal(t, png, image.Data)
+
+	// 部分读取的图片作为嵌入资源返回 / A partially read image is returned as an embedded resource
+	result, _, err = service.handleReadFile(context.Background(), nil, types.ReadFileRequest{Path: "assets/logo.png", Length: 4})
+	require.NoError(t, err)
+	resource, ok := result.Content[0].(*mcp.EmbeddedResource)
+	require.True(t, ok)
+	assert.Equal(t, "file:///assets/logo.png", resource.Resource.URI)
+	assert.Equal(t, png[:4], resource.Resource.Blob)
+
+	// 其他二进制作为带blob的嵌入资源返回 / Other binaries are returned as embedded resources with a blob
+	result, _, err = service.handleReadFile(context.Background(), nil, types.ReadFileRequest{Path: "data.bin"})
+	require.NoError(t, err)
+	resource, ok = result.Content[0].(*mcp.EmbeddedResource)
+	require.True(t, ok)
+	assert.Equal(t, "file:///data.bin", resource.Resource.URI)
+	assert.Equal(t, "application/octet-stream", resource.Resource.MIMEType)
+	assert.Equal(t, blob, resource.Resource.Blob)
+	assert.Empty(t, resource.Resource.Text)
 }
 
 func TestHandleWriteFile(t *testing.T) {
@@ -0,0 +1,612 @@
+// Copyright 2024 MCP Toolkit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sandbox
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// maxTempAttempts 创建临时文件时的最大尝试次数 / Maximum attempts when creating a temporary file
+const maxTempAttempts = 10000
+
+// MemFileSystem 内存文件系统后端,不支持符号链接,适用于测试和临时会话
+// In-memory filesystem backend without symlink support, suited to tests and ephemeral sessions
+type MemFileSystem struct {
+	mu   sync.RWMutex // 保护整棵节点树 / Protects the whole node tree
+	root *memNode     // 根目录 / Root directory
+}
+
+// memNode 内存文件系统中的文件或目录 / File or directory in the in-memory filesystem
+type memNode struct {
+	name     string              // 条目名称 / Entry name
+	mode     fs.FileMode         // 类型和权限位 / Type and permission bits
+	modTime  time.Time           // 修改时间 / Modification time
+	data     []byte              // 文件内容 / File content
+	children map[string]*memNode // 目录子条目 / Directory children
+}
+
+// NewMemFileSystem 创建空的内存文件系统 / Create an empty in-memory filesystem
+func NewMemFileSystem() *MemFileSystem {
+	return &MemFileSystem{root: newMemDir(string(filepath.Separator), DefaultDirPerm)}
+}
+
+// newMemDir 创建目录节点 / Create a directory node
+func newMemDir(name string, perm fs.FileMode) *memNode {
+	return &memNode{name: name, mode: fs.ModeDir | perm.Perm(), modTime: time.Now(), children: make(map[string]*memNode)}
+}
+
+// info 返回节点的文件信息快照 / Return a file information snapshot of the node
+func (n *memNode) info() fs.FileInfo {
+	return &memFileInfo{name: n.name, size: int64(len(n.data)), mode: n.mode, modTime: n.modTime}
+}
+
+// memFileInfo 内存文件信息 / In-memory file information
+type memFileInfo struct {
+	name    string
+	size    int64
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+func (i *memFileInfo) Name() string       { return i.name }
+func (i *memFileInfo) Size() int64        { return i.size }
+func (i *memFileInfo) Mode() fs.FileMode  { return i.mode }
+func (i *memFileInfo) ModTime() time.Time { return i.modTime }
+func (i *memFileInfo) IsDir() bool        { return i.mode.IsDir() }
+func (i *memFileInfo) Sys() any           { return nil }
+
+// splitMemPath 将绝对路径拆分为各级名称 / Split an absolute path into its components
+func splitMemPath(name string) []string {
+	cleaned := filepath.ToSlash(filepath.Clean(name))
+	cleaned = strings.TrimPrefix(cleaned, filepath.ToSlash(filepath.VolumeName(name)))
+	cleaned = strings.Trim(cleaned, "/")
+	if cleaned == "" || cleaned == "." {
+		return nil
+	}
+	return strings.Split(cleaned, "/")
+}
+
+// lookup 查找路径对应的节点,调用方需持有锁 / Look up the node of a path; the caller must hold the lock
+func (m *MemFileSystem) lookup(op, name string) (*memNode, error) {
+	node := m.root
+	for _, part := range splitMemPath(name) {
+		if !node.mode.IsDir() {
+			return nil, &fs.PathError{Op: op, Path: name, Err: syscall.ENOTDIR}
+		}
+		child, ok := node.children[part]
+		if !ok {
+			return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+		}
+		node = child
+	}
+	return node, nil
+}
+
+// lookupParent 查找路径的父目录节点和条目名称,调用方需持有锁
+// Look up the parent directory node and entry name of a path; the caller must hold the lock
+func (m *MemFileSystem) lookupParent(op, name string) (*memNode, string, error) {
+	parts := splitMemPath(name)
+	if len(parts) == 0 {
+		return nil, "", &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	parent, err := m.lookup(op, filepath.Dir(filepath.Clean(name)))
+	if err != nil {
+		return nil, "", err
+	}
+	if !parent.mode.IsDir() {
+		return nil, "", &fs.PathError{Op: op, Path: name, Err: syscall.ENOTDIR}
+	}
+	return parent, parts[len(parts)-1], nil
+}
+
+// Open 以只读方式打开文件 / Open a file for reading
+func (m *MemFileSystem) Open(name string) (File, error) {
+	return m.OpenFile(name, os.O_RDONLY, 0)
+}
+
+// OpenFile 按标志打开文件 / Open a file with flags
+func (m *MemFileSystem) OpenFile(name string, flag int, perm fs.FileMode) (File, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	writable := flag&(os.O_WRONLY|os.O_RDWR) != 0
+	node, err := m.lookup("open", name)
+	switch {
+	case err == nil:
+		if flag&os.O_CREATE != 0 && flag&os.O_EXCL != 0 {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrExist}
+		}
+		if node.mode.IsDir() && writable {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: syscall.EISDIR}
+		}
+		if flag&os.O_TRUNC != 0 && writable {
+			node.data = nil
+			node.modTime = time.Now()
+		}
+	case errors.Is(err, fs.ErrNotExist) && flag&os.O_CREATE != 0:
+		parent, base, perr := m.lookupParent("open", name)
+		if perr != nil {
+			return nil, perr
+		}
+		node = &memNode{name: base, mode: perm.Perm(), modTime: time.Now()}
+		parent.children[base] = node
+		parent.modTime = node.modTime
+	default:
+		return nil, err
+	}
+
+	return &memFile{fsys: m, node: node, name: name, flag: flag}, nil
+}
+
+// CreateTemp 在目录中创建唯一的临时文件 / Create a unique temporary file in a directory
+func (m *MemFileSystem) CreateTemp(dir, pattern string) (File, error) {
+	if strings.ContainsRune(pattern, filepath.Separator) {
+		return nil, &fs.PathError{Op: "createtemp", Path: pattern, Err: errors.New("pattern contains path separator")}
+	}
+	prefix, suffix := pattern, ""
+	if i := strings.LastIndex(pattern, "*"); i >= 0 {
+		prefix, suffix = pattern[:i], pattern[i+1:]
+	}
+
+	for range maxTempAttempts {
+		name := filepath.Join(dir, prefix+strconv.FormatUint(uint64(rand.Uint32()), 10)+suffix)
+		file, err := m.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0600)
+		if errors.Is(err, fs.ErrExist) {
+			continue
+		}
+		return file, err
+	}
+	return nil, &fs.PathError{Op: "createtemp", Path: filepath.Join(dir, pattern), Err: fs.ErrExist}
+}
+
+// Stat 返回文件信息 / Return file information
+func (m *MemFileSystem) Stat(name string) (fs.FileInfo, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	node, err := m.lookup("stat", name)
+	if err != nil {
+		return nil, err
+	}
+	return node.info(), nil
+}
+
+// Lstat 返回文件信息,内存文件系统没有符号链接 / Return file information; the in-memory filesystem has no symlinks
+func (m *MemFileSystem) Lstat(name string) (fs.FileInfo, error) {
+	return m.Stat(name)
+}
+
+// ReadDir 返回按名称排序的目录条目 / Return directory entries sorted by name
+func (m *MemFileSystem) ReadDir(name string) ([]fs.DirEntry, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	node, err := m.lookup("open", name)
+	if err != nil {
+		return nil, err
+	}
+	if !node.mode.IsDir() {
+		return nil, &fs.PathError{Op: "readdirent", Path: name, Err: syscall.ENOTDIR}
+	}
+
+	entries := make([]fs.DirEntry, 0, len(node.children))
+	for _, child := range node.children {
+		entries = append(entries, fs.FileInfoToDirEntry(child.info()))
+	}
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int { return strings.Compare(a.Name(), b.Name()) })
+	return entries, nil
+}
+
+// ReadFile 读取整个文件 / Read a whole file
+func (m *MemFileSystem) ReadFile(name string) ([]byte, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	node, err := m.lookup("open", name)
+	if err != nil {
+		return nil, err
+	}
+	if node.mode.IsDir() {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: syscall.EISDIR}
+	}
+	return slices.Clone(node.data), nil
+}
+
+// WriteFile 写入整个文件 / Write a whole file
+func (m *MemFileSystem) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	file, err := m.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err = file.Write(data); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Mkdir 创建单个目录 / Create a single directory
+func (m *MemFileSystem) Mkdir(name string, perm fs.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	parent, base, err := m.lookupParent("mkdir", name)
+	if err != nil {
+		return err
+	}
+	if _, ok := parent.children[base]; ok {
+		return &fs.PathError{Op: "mkdir", Path: name, Err: fs.ErrExist}
+	}
+	parent.children[base] = newMemDir(base, perm)
+	parent.modTime = time.Now()
+	return nil
+}
+
+// MkdirAll 创建目录及其所有父目录 / Create a directory along with any missing parents
+func (m *MemFileSystem) MkdirAll(path string, perm fs.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	node := m.root
+	for _, part := range splitMemPath(path) {
+		child, ok := node.children[part]
+		if !ok {
+			child = newMemDir(part, perm)
+			node.children[part] = child
+			node.modTime = child.modTime
+		}
+		if !child.mode.IsDir() {
+			return &fs.PathError{Op: "mkdir", Path: path, Err: syscall.ENOTDIR}
+		}
+		node = child
+	}
+	return nil
+}
+
+// Remove 删除文件或空目录 / Remove a file or an empty directory
+func (m *MemFileSystem) Remove(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	parent, base, err := m.lookupParent("remove", name)
+	if err != nil {
+		return err
+	}
+	node, ok := parent.children[base]
+	if !ok {
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrNotExist}
+	}
+	if node.mode.IsDir() && len(node.children) > 0 {
+		return &fs.PathError{Op: "remove", Path: name, Err: syscall.ENOTEMPTY}
+	}
+	delete(parent.children, base)
+	parent.modTime = time.Now()
+	return nil
+}
+
+// RemoveAll 递归删除路径 / Remove a path recursively
+func (m *MemFileSystem) RemoveAll(path string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	parent, base, err := m.lookupParent("unlinkat", path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	if _, ok := parent.children[base]; ok {
+		delete(parent.children, base)
+		parent.modTime = time.Now()
+	}
+	return nil
+}
+
+// Rename 重命名路径,语义与POSIX rename相同 / Rename a path with POSIX rename semantics
+func (m *MemFileSystem) Rename(oldpath, newpath string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	linkErr := func(err error) error {
+		return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: err}
+	}
+
+	oldParent, oldBase, err := m.lookupParent("rename", oldpath)
+	if err != nil {
+		return linkErr(errors.Unwrap(err))
+	}
+	node, ok := oldParent.children[oldBase]
+	if !ok {
+		return linkErr(fs.ErrNotExist)
+	}
+	newParent, newBase, err := m.lookupParent("rename", newpath)
+	if err != nil {
+		return linkErr(errors.Unwrap(err))
+	}
+
+	// 目录不能移动到自身内部 / A directory cannot be moved inside itself
+	if node.mode.IsDir() {
+		if rel, err := filepath.Rel(filepath.Clean(oldpath), filepath.Clean(newpath)); err == nil && rel != "." && isLocalRel(rel) {
+			return linkErr(syscall.EINVAL)
+		}
+	}
+
+	if existing, ok := newParent.children[newBase]; ok {
+		if existing == node {
+			return nil
+		}
+		switch {
+		case node.mode.IsDir() && !existing.mode.IsDir():
+			return linkErr(syscall.ENOTDIR)
+		case !node.mode.IsDir() && existing.mode.IsDir():
+			return linkErr(syscall.EISDIR)
+		case existing.mode.IsDir() && len(existing.children) > 0:
+			return linkErr(syscall.ENOTEMPTY)
+		}
+	}
+
+	now := time.Now()
+	delete(oldParent.children, oldBase)
+	node.name = newBase
+	newParent.children[newBase] = node
+	oldParent.modTime, newParent.modTime = now, now
+	return nil
+}
+
+// Chmod 修改权限 / Change the mode
+func (m *MemFileSystem) Chmod(name string, mode fs.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	node, err := m.lookup("chmod", name)
+	if err != nil {
+		return err
+	}
+	node.mode = node.mode.Type() | mode.Perm()
+	return nil
+}
+
+// Chtimes 修改修改时间,内存文件系统不记录访问时间 / Change the modification time; access times are not recorded
+func (m *MemFileSystem) Chtimes(name string, _, mtime time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	node, err := m.lookup("chtimes", name)
+	if err != nil {
+		return err
+	}
+	if !mtime.IsZero() {
+		node.modTime = mtime
+	}
+	return nil
+}
+
+// Truncate 修改文件大小 / Change the size of a file
+func (m *MemFileSystem) Truncate(name string, size int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	node, err := m.lookup("truncate", name)
+	if err != nil {
+		return err
+	}
+	return node.truncate("truncate", name, size)
+}
+
+// truncate 修改节点大小,调用方需持有锁 / Change the size of a node; the caller must hold the lock
+func (n *memNode) truncate(op, name string, size int64) error {
+	if n.mode.IsDir() {
+		return &fs.PathError{Op: op, Path: name, Err: syscall.EISDIR}
+	}
+	if size < 0 {
+		return &fs.PathError{Op: op, Path: name, Err: syscall.EINVAL}
+	}
+	if size <= int64(len(n.data)) {
+		n.data = n.data[:size]
+	} else {
+		n.data = append(n.data, make([]byte, size-int64(len(n.data)))...)
+	}
+	n.modTime = time.Now()
+	return nil
+}
+
+// Readlink 内存文件系统没有符号链接 / The in-memory filesystem has no symlinks
+func (m *MemFileSystem) Readlink(name string) (string, error) {
+	if _, err := m.Lstat(name); err != nil {
+		return "", err
+	}
+	return "", &fs.PathError{Op: "readlink", Path: name, Err: syscall.EINVAL}
+}
+
+// EvalSymlinks 没有符号链接时只检查路径是否存在 / Without symlinks only check that the path exists
+func (m *MemFileSystem) EvalSymlinks(path string) (string, error) {
+	if _, err := m.Lstat(path); err != nil {
+		return "", err
+	}
+	return filepath.Clean(path), nil
+}
+
+// memFile 内存文件句柄 / In-memory file handle
+type memFile struct {
+	fsys   *MemFileSystem // 所属文件系统 / Owning filesystem
+	node   *memNode       // 文件节点,重命名或删除后仍然有效 / File node, still valid after a rename or removal
+	name   string         // 打开时使用的路径 / Path used to open the file
+	flag   int            // 打开标志 / Open flags
+	offset int64          // 读写位置 / Read/write offset
+	closed bool           // 是否已关闭 / Whether the handle is closed
+}
+
+// check 检查句柄状态和访问模式 / Check the handle state and access mode
+func (f *memFile) check(op string, write bool) error {
+	if f.closed {
+		return &fs.PathError{Op: op, Path: f.name, Err: fs.ErrClosed}
+	}
+	if f.node.mode.IsDir() {
+		return &fs.PathError{Op: op, Path: f.name, Err: syscall.EISDIR}
+	}
+	writable := f.flag&(os.O_WRONLY|os.O_RDWR) != 0
+	readable := f.flag&os.O_WRONLY == 0
+	if (write && !writable) || (!write && !readable) {
+		return &fs.PathError{Op: op, Path: f.name, Err: syscall.EBADF}
+	}
+	return nil
+}
+
+// Name 返回打开时使用的路径 / Return the path the file was opened with
+func (f *memFile) Name() string { return f.name }
+
+// Read 从当前位置读取 / Read from the current offset
+func (f *memFile) Read(p []byte) (int, error) {
+	n, err := f.ReadAt(p, f.offset)
+	f.offset += int64(n)
+	if errors.Is(err, io.EOF) && n > 0 {
+		err = nil
+	}
+	return n, err
+}
+
+// ReadAt 从指定位置读取 / Read from the given offset
+func (f *memFile) ReadAt(p []byte, off int64) (int, error) {
+	f.fsys.mu.RLock()
+	defer f.fsys.mu.RUnlock()
+
+	if err := f.check("read", false); err != nil {
+		return 0, err
+	}
+	if off < 0 {
+		return 0, &fs.PathError{Op: "read", Path: f.name, Err: syscall.EINVAL}
+	}
+	if off >= int64(len(f.node.data)) {
+		if len(p) == 0 {
+			return 0, nil
+		}
+		return 0, io.EOF
+	}
+	n := copy(p, f.node.data[off:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// Write 在当前位置写入,追加模式下总是写到末尾 / Write at the current offset, always at the end in append mode
+func (f *memFile) Write(p []byte) (int, error) {
+	f.fsys.mu.Lock()
+	defer f.fsys.mu.Unlock()
+
+	if err := f.check("write", true); err != nil {
+		return 0, err
+	}
+	if f.flag&os.O_APPEND != 0 {
+		f.offset = int64(len(f.node.data))
+	}
+	end := f.offset + int64(len(p))
+	if end > int64(len(f.node.data)) {
+		f.node.data = append(f.node.data, make([]byte, end-int64(len(f.node.data)))...)
+	}
+	copy(f.node.data[f.offset:], p)
+	f.offset = end
+	f.node.modTime = time.Now()
+	return len(p), nil
+}
+
+// WriteString 写入字符串 / Write a string
+func (f *memFile) WriteString(s string) (int, error) {
+	return f.Write([]byte(s))
+}
+
+// Seek 设置读写位置 / Set the read/write offset
+func (f *memFile) Seek(offset int64, whence int) (int64, error) {
+	f.fsys.mu.RLock()
+	defer f.fsys.mu.RUnlock()
+
+	if f.closed {
+		return 0, &fs.PathError{Op: "seek", Path: f.name, Err: fs.ErrClosed}
+	}
+	switch whence {
+	case io.SeekCurrent:
+		offset += f.offset
+	case io.SeekEnd:
+		offset += int64(len(f.node.data))
+	}
+	if offset < 0 {
+		return 0, &fs.PathError{Op: "seek", Path: f.name, Err: syscall.EINVAL}
+	}
+	f.offset = offset
+	return offset, nil
+}
+
+// Close 关闭句柄 / Close the handle
+func (f *memFile) Close() error {
+	if f.closed {
+		return &fs.PathError{Op: "close", Path: f.name, Err: fs.ErrClosed}
+	}
+	f.closed = true
+	return nil
+}
+
+// Stat 返回文件信息 / Return file information
+func (f *memFile) Stat() (fs.FileInfo, error) {
+	f.fsys.mu.RLock()
+	defer f.fsys.mu.RUnlock()
+
+	if f.closed {
+		return nil, &fs.PathError{Op: "stat", Path: f.name, Err: fs.ErrClosed}
+	}
+	return f.node.info(), nil
+}
+
+// Sync 内存文件无需同步 / In-memory files need no syncing
+func (f *memFile) Sync() error {
+	if f.closed {
+		return &fs.PathError{Op: "sync", Path: f.name, Err: fs.ErrClosed}
+	}
+	return nil
+}
+
+// Chmod 修改文件权限 / Change the file mode
+func (f *memFile) Chmod(mode fs.FileMode) error {
+	f.fsys.mu.Lock()
+	defer f.fsys.mu.Unlock()
+
+	if f.closed {
+		return &fs.PathError{Op: "chmod", Path: f.name, Err: fs.ErrClosed}
+	}
+	f.node.mode = f.node.mode.Type() | mode.Perm()
+	return nil
+}
+
+// Truncate 修改文件大小 / Change the file size
+func (f *memFile) Truncate(size int64) error {
+	f.fsys.mu.Lock()
+	defer f.fsys.mu.Unlock()
+
+	if err := f.check("truncate", true); err != nil {
+		return err
+	}
+	return f.node.truncate("truncate", f.name, size)
+}
@@ -0,0 +1,189 @@
+package sandbox
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"mcp-toolkit/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// memSandboxDir 内存文件系统测试使用的沙箱路径,不会在宿主上创建 / Sandbox path used by in-memory tests, never created on the host
+var memSandboxDir = filepath.Join(string(filepath.Separator), "memfs-sandbox")
+
+// setupMemService 创建基于内存文件系统的服务 / Create a service backed by the in-memory filesystem
+func setupMemService(t *testing.T) (*Service, *MemFileSystem) {
+	memFS := NewMemFileSystem()
+	service, err := NewService(memSandboxDir, zap.NewNop(), WithFileSystem(memFS))
+	require.NoError(t, err)
+	return service, memFS
+}
+
+func TestMemFileSystemFiles(t *testing.T) {
+	memFS := NewMemFileSystem()
+	dir := filepath.Join(memSandboxDir, "a", "b")
+	require.NoError(t, memFS.MkdirAll(dir, DefaultDirPerm))
+
+	file := filepath.Join(dir, "file.txt")
+	require.NoError(t, memFS.WriteFile(file, []byte("hello"), 0644))
+	data, err := memFS.ReadFile(file)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+
+	info, err := memFS.Stat(file)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), info.Size())
+	assert.Equal(t, fs.FileMode(0644), info.Mode())
+
+	// 追加写入和排他创建 / Append writes and exclusive creation
+	f, err := memFS.OpenFile(file, os.O_WRONLY|os.O_APPEND, 0)
+	require.NoError(t, err)
+	_, err = f.WriteString(" world")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	_, err = memFS.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	assert.ErrorIs(t, err, fs.ErrExist)
+
+	// 读取和定位 / Reads and seeks
+	f, err = memFS.Open(file)
+	require.NoError(t, err)
+	_, err = f.Seek(6, io.SeekStart)
+	require.NoError(t, err)
+	rest, err := io.ReadAll(f)
+	require.NoError(t, err)
+	assert.Equal(t, "world", string(rest))
+	_, err = f.Write([]byte("x"))
+	assert.Error(t, err)
+	require.NoError(t, f.Close())
+
+	require.NoError(t, memFS.Truncate(file, 8))
+	data, err = memFS.ReadFile(file)
+	require.NoError(t, err)
+	assert.Equal(t, "hello wo", string(data))
+
+	_, err = memFS.ReadFile(filepath.Join(dir, "missing.txt"))
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	assert.True(t, os.IsNotExist(err))
+	_, err = memFS.Stat(filepath.Join(file, "child"))
+	assert.ErrorIs(t, err, syscall.ENOTDIR)
+
+	tmp1, err := memFS.CreateTemp(dir, ".tmp-*")
+	require.NoError(t, err)
+	tmp2, err := memFS.CreateTemp(dir, ".tmp-*")
+	require.NoError(t, err)
+	assert.NotEqual(t, tmp1.Name(), tmp2.Name())
+}
+
+func TestMemFileSystemDirectories(t *testing.T) {
+	memFS := NewMemFileSystem()
+	root := filepath.Join(memSandboxDir, "root")
+	for _, name := range []string{"c.txt", "a.txt", "sub/b.txt"} {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, memFS.MkdirAll(filepath.Dir(path), DefaultDirPerm))
+		require.NoError(t, memFS.WriteFile(path, []byte(name), 0644))
+	}
+
+	entries, err := memFS.ReadDir(root)
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.Equal(t, []string{"a.txt", "c.txt", "sub"}, names)
+
+	// 遍历顺序与filepath.WalkDir相同 / Walk order matches filepath.WalkDir
+	var walked []string
+	require.NoError(t, walkDir(memFS, root, func(path string, d fs.DirEntry, err error) error {
+		require.NoError(t, err)
+		rel, _ := filepath.Rel(root, path)
+		walked = append(walked, filepath.ToSlash(rel))
+		return nil
+	}))
+	assert.Equal(t, []string{".", "a.txt", "c.txt", "sub", "sub/b.txt"}, walked)
+
+	assert.ErrorIs(t, memFS.Remove(filepath.Join(root, "sub")), syscall.ENOTEMPTY)
+	assert.ErrorIs(t, memFS.Mkdir(filepath.Join(root, "sub"), DefaultDirPerm), fs.ErrExist)
+
+	// 重命名替换文件,但不能把目录移入自身 / Renames replace files but cannot move a directory into itself
+	require.NoError(t, memFS.Rename(filepath.Join(root, "a.txt"), filepath.Join(root, "c.txt")))
+	data, err := memFS.ReadFile(filepath.Join(root, "c.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "a.txt", string(data))
+	err = memFS.Rename(root, filepath.Join(root, "sub", "inner"))
+	assert.True(t, errors.Is(err, syscall.EINVAL))
+	require.NoError(t, memFS.Rename(filepath.Join(root, "sub"), filepath.Join(memSandboxDir, "moved")))
+	_, err = memFS.Stat(filepath.Join(memSandboxDir, "moved", "b.txt"))
+	assert.NoError(t, err)
+
+	require.NoError(t, memFS.RemoveAll(root))
+	require.NoError(t, memFS.RemoveAll(root))
+	_, err = memFS.Stat(root)
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+func TestServiceMemFileSystem(t *testing.T) {
+	service, memFS := setupMemService(t)
+
+	// 沙箱只存在于内存中 / The sandbox only exists in memory
+	_, err := os.Stat(memSandboxDir)
+	assert.True(t, os.IsNotExist(err))
+
+	_, err = service.WriteFile(&types.WriteFileRequest{Path: "src/main.go", Content: "package main\n\nfunc main() {}\n"})
+	require.NoError(t, err)
+	_, err = service.WriteFile(&types.WriteFileRequest{Path: "src/util.go", Content: "package main\n"})
+	require.NoError(t, err)
+
+	data, err := memFS.ReadFile(filepath.Join(memSandboxDir, "src", "main.go"))
+	require.NoError(t, err)
+	assert.Equal(t, "package main\n\nfunc main() {}\n", string(data))
+
+	read, err := service.ReadFile(&types.ReadFileRequest{Path: "src/main.go", StartLine: 3})
+	require.NoError(t, err)
+	assert.Equal(t, "func main() {}\n", read.Content)
+
+	_, err = service.EditFile(&types.EditFileRequest{Path: "src/main.go", Edits: []types.TextEdit{{OldText: "func main() {}", NewText: "func main() { run() }"}}})
+	require.NoError(t, err)
+
+	_, err = service.CopyDirectory(&types.CopyDirectoryRequest{Source: "src", Destination: "backup"})
+	require.NoError(t, err)
+	_, err = service.Move(&types.MoveRequest{Source: "backup/util.go", Destination: "util.go"})
+	require.NoError(t, err)
+
+	search, err := service.Search(&types.SearchRequest{Path: ".", Pattern: "*.go"})
+	require.NoError(t, err)
+	var found []string
+	for _, file := range search.Files {
+		found = append(found, file.Path)
+	}
+	assert.ElementsMatch(t, []string{"src/main.go", "src/util.go", "backup/main.go", "util.go"}, found)
+
+	content, err := service.SearchContent(&types.SearchContentRequest{Pattern: "run\\(\\)"})
+	require.NoError(t, err)
+	require.Len(t, content.Matches, 2)
+
+	_, err = service.CreateArchive(&types.CreateArchiveRequest{Sources: []string{"src"}, Path: "src.zip"})
+	require.NoError(t, err)
+	_, err = service.ExtractArchive(&types.ExtractArchiveRequest{Path: "src.zip", Destination: "extracted"})
+	require.NoError(t, err)
+	data, err = memFS.ReadFile(filepath.Join(memSandboxDir, "extracted", "src", "util.go"))
+	require.NoError(t, err)
+	assert.Equal(t, "package main\n", string(data))
+
+	_, err = service.Delete(&types.DeleteRequest{Path: "backup"})
+	require.NoError(t, err)
+	list, err := service.ListDir(&types.ListDirRequest{Path: "."})
+	require.NoError(t, err)
+	var names []string
+	for _, file := range list.Files {
+		names = append(names, file.Name)
+	}
+	assert.Equal(t, []string{"extracted", "src", "src.zip", "util.go"}, names)
+}
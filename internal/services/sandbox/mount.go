@@ -0,0 +1,259 @@
+// Copyright 2024 MCP Toolkit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sandbox
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"syscall"
+
+	"mcp-toolkit/pkg/types"
+
+	"go.uber.org/zap"
+)
+
+// mountNameRegex 挂载名称正则,至少两个字符以免与Windows盘符混淆
+// Mount name regex; at least two characters so names never clash with Windows drive letters
+var mountNameRegex = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_-]+$`)
+
+// mount 命名挂载 / Named mount
+type mount struct {
+	name     string // 挂载名称 / Mount name
+	dir      string // 宿主机绝对路径 / Absolute host path
+	realDir  string // 解析符号链接后的路径 / Path with symlinks resolved
+	readOnly bool   // 是否只读 / Whether read-only
+}
+
+// AddMount 添加命名挂载,应在开始提供服务前调用 / Add a named mount, to be called before serving requests
+func (s *Service) AddMount(config types.MountConfig) error {
+	if !mountNameRegex.MatchString(config.Name) {
+		return fmt.Errorf("invalid mount name %q, must start with a letter and contain at least two letters, digits, '_' or '-'", config.Name)
+	}
+	if config.Name == types.DefaultMountName || s.mounts[config.Name] != nil {
+		return fmt.Errorf("mount %q already exists", config.Name)
+	}
+
+	mode := cmp.Or(config.Mode, types.MountModeReadWrite)
+	if mode != types.MountModeReadWrite && mode != types.MountModeReadOnly {
+		return fmt.Errorf("invalid mount mode %q, must be ro or rw", config.Mode)
+	}
+
+	if config.Path == "" {
+		return fmt.Errorf("mount %q: host path cannot be empty", config.Name)
+	}
+	absPath, err := filepath.Abs(config.Path)
+	if err != nil {
+		return fmt.Errorf("failed to get absolute path: %w", err)
+	}
+	info, err := s.fsys.Stat(absPath)
+	if err != nil {
+		return fmt.Errorf("mount %q: %w", config.Name, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("mount %q: %s", config.Name, types.ErrNotDirectory)
+	}
+	realDir, err := s.fsys.EvalSymlinks(absPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve mount directory: %w", err)
+	}
+
+	// 挂载之间不能嵌套,否则同一文件会有两种模式 / Mounts cannot nest, otherwise one file would have two modes
+	for _, other := range s.mountList() {
+		if pathsOverlap(realDir, other.realDir) {
+			return fmt.Errorf("mount %q overlaps mount %q", config.Name, other.name)
+		}
+	}
+
+	if s.mounts == nil {
+		s.mounts = make(map[string]*mount)
+	}
+	s.mounts[config.Name] = &mount{
+		name:     config.Name,
+		dir:      absPath,
+		realDir:  realDir,
+		readOnly: mode == types.MountModeReadOnly,
+	}
+	s.logger.Info("mount added",
+		zap.String("name", config.Name),
+		zap.String("path", absPath),
+		zap.String("mode", string(mode)))
+	return nil
+}
+
+// ListMounts 列出所有挂载 / List all mounts
+func (s *Service) ListMounts(_ *types.ListMountsRequest) (*types.ListMountsResponse, error) {
+	resp := &types.ListMountsResponse{}
+	for _, m := range s.mountList() {
+		mode := types.MountModeReadWrite
+		if m.readOnly {
+			mode = types.MountModeReadOnly
+		}
+		resp.Mounts = append(resp.Mounts, types.MountInfo{
+			Name:     m.name,
+			Path:     m.dir,
+			Mode:     mode,
+			ReadOnly: m.readOnly,
+			Default:  m.name == types.DefaultMountName,
+		})
+	}
+	return resp, nil
+}
+
+// defaultMount 沙箱根目录对应的默认挂载 / Default mount of the sandbox root
+func (s *Service) defaultMount() *mount {
+	return &mount{name: types.DefaultMountName, dir: s.sandboxDir, realDir: s.realSandboxDir}
+}
+
+// mountList 返回默认挂载及按名称排序的命名挂载 / Return the default mount followed by the named mounts sorted by name
+func (s *Service) mountList() []*mount {
+	list := []*mount{s.defaultMount()}
+	for _, name := range slices.Sorted(maps.Keys(s.mounts)) {
+		list = append(list, s.mounts[name])
+	}
+	return list
+}
+
+// splitMountPath 拆分"名称:相对路径",只识别已注册的挂载名称
+// Split "name:relative/path", recognizing registered mount names only
+func (s *Service) splitMountPath(p string) (*mount, string) {
+	if name, rest, ok := strings.Cut(p, ":"); ok {
+		m := s.mounts[name]
+		if m == nil && name == types.DefaultMountName {
+			m = s.defaultMount()
+		}
+		if m != nil {
+			return m, cmp.Or(rest, ".")
+		}
+	}
+	return s.defaultMount(), p
+}
+
+// mountFor 返回包含绝对路径的挂载 / Return the mount containing an absolute path
+func (s *Service) mountFor(absPath string) *mount {
+	for _, m := range s.mounts {
+		if rel, err := filepath.Rel(m.dir, absPath); err == nil && isLocalRel(rel) {
+			return m
+		}
+	}
+	return s.defaultMount()
+}
+
+// inDefaultMount 检查路径是否位于沙箱根目录下,配额只统计这部分 / Check whether a path lies under the sandbox root, the only part the quota covers
+func (s *Service) inDefaultMount(absPath string) bool {
+	return s.mountFor(absPath).name == types.DefaultMountName
+}
+
+// validateWritePath 验证路径并确认可以写入 / Validate a path and make sure it may be written
+func (s *Service) validateWritePath(p string) (string, error) {
+	return s.validateAccessPath(p, types.AccessWrite)
+}
+
+// checkWritable 只读挂载内的路径返回错误 / Return an error for paths inside a read-only mount
+func (s *Service) checkWritable(absPath string) error {
+	if m := s.mountFor(absPath); m.readOnly {
+		s.logger.Warn("write to read-only mount rejected",
+			zap.String("mount", m.name),
+			zap.String("path", absPath))
+		return fmt.Errorf("%s: %s", types.ErrReadOnlyMount, m.name)
+	}
+	return nil
+}
+
+// sandboxRelPath 计算相对所在挂载的斜杠路径,非默认挂载带"名称:"前缀,默认挂载根目录为空字符串
+// Compute the slash-separated path relative to its mount, prefixed with "name:" outside the default mount; the default root is the empty string
+func (s *Service) sandboxRelPath(absPath string) string {
+	m := s.mountFor(absPath)
+	rel, err := filepath.Rel(m.dir, absPath)
+	if err != nil || rel == "." {
+		rel = ""
+	}
+	rel = filepath.ToSlash(rel)
+	if m.name == types.DefaultMountName {
+		return rel
+	}
+	return m.name + ":" + rel
+}
+
+// displayPath 与sandboxRelPath相同,但默认挂载根目录为"." / Same as sandboxRelPath, but the default root is "."
+func (s *Service) displayPath(absPath string) string {
+	return cmp.Or(s.sandboxRelPath(absPath), ".")
+}
+
+// joinDisplayPath 拼接显示路径,保留挂载前缀 / Join display paths, keeping the mount prefix intact
+func joinDisplayPath(parent, name string) string {
+	if parent == "" || parent == "." {
+		return name
+	}
+	if strings.HasSuffix(parent, ":") {
+		return parent + name
+	}
+	return path.Join(parent, name)
+}
+
+// pathsOverlap 检查两个目录是否相同或互相包含 / Check whether two directories are equal or contain one another
+func pathsOverlap(a, b string) bool {
+	if rel, err := filepath.Rel(a, b); err == nil && isLocalRel(rel) {
+		return true
+	}
+	rel, err := filepath.Rel(b, a)
+	return err == nil && isLocalRel(rel)
+}
+
+// movePath 移动路径,跨设备(如不同挂载)时复制后删除源 / Move a path, copying and removing the source when crossing devices such as different mounts
+func (s *Service) movePath(src, dst string) error {
+	err := s.fsys.Rename(src, dst)
+	if !errors.Is(err, syscall.EXDEV) {
+		return err
+	}
+
+	info, err := s.fsys.Lstat(src)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		err = s.copyDirTree(src, dst)
+	} else {
+		err = s.copyFile(src, dst)
+	}
+	if err != nil {
+		_ = s.fsys.RemoveAll(dst)
+		return err
+	}
+	return s.fsys.RemoveAll(src)
+}
+
+// checkMoveQuota 从挂载移入沙箱根目录时按源的大小检查配额 / Check the quota with the source size when moving from a mount into the sandbox root
+func (s *Service) checkMoveQuota(src, dst string) error {
+	if !s.quotaEnabled() || s.inDefaultMount(src) || !s.inDefaultMount(dst) {
+		return nil
+	}
+	info, err := s.fsys.Lstat(src)
+	if err != nil {
+		return nil
+	}
+	addBytes, addFiles := info.Size(), int64(1)
+	if info.IsDir() {
+		bytes, files := treeUsage(s.fsys, src)
+		addBytes, addFiles = bytes, files+1
+	}
+	return s.checkQuota(addBytes, addFiles)
+}
@@ -0,0 +1,257 @@
+package sandbox
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"mcp-toolkit/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupMountService 创建带有一个读写挂载和一个只读挂载的服务 / Create a service with one read-write and one read-only mount
+func setupMountService(t *testing.T) (*Service, string, string, string) {
+	service, tempDir := setupTestService(t)
+	dataDir, docsDir := t.TempDir(), t.TempDir()
+	require.NoError(t, service.AddMount(types.MountConfig{Name: "data", Path: dataDir, Mode: types.MountModeReadWrite}))
+	require.NoError(t, service.AddMount(types.MountConfig{Name: "docs", Path: docsDir, Mode: types.MountModeReadOnly}))
+	writeTestFiles(t, docsDir, map[string]string{"guide.md": "# guide\n", "sub/notes.txt": "notes\n"})
+	return service, tempDir, dataDir, docsDir
+}
+
+func TestParseMountConfig(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    types.MountConfig
+		wantErr bool
+	}{
+		{spec: "data=/srv/data", want: types.MountConfig{Name: "data", Path: "/srv/data", Mode: types.MountModeReadWrite}},
+		{spec: "docs=/srv/docs:ro", want: types.MountConfig{Name: "docs", Path: "/srv/docs", Mode: types.MountModeReadOnly}},
+		{spec: "work=/srv/work:rw", want: types.MountConfig{Name: "work", Path: "/srv/work", Mode: types.MountModeReadWrite}},
+		{spec: `win=C:\data:ro`, want: types.MountConfig{Name: "win", Path: `C:\data`, Mode: types.MountModeReadOnly}},
+		{spec: `win=C:\data`, want: types.MountConfig{Name: "win", Path: `C:\data`, Mode: types.MountModeReadWrite}},
+		{spec: "/srv/data", wantErr: true},
+		{spec: "data=", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := types.ParseMountConfig(tt.spec)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAddMountValidation(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	hostDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(hostDir, "file.txt"), []byte("x"), 0644))
+	require.NoError(t, service.AddMount(types.MountConfig{Name: "data", Path: hostDir}))
+
+	tests := []struct {
+		name   string
+		config types.MountConfig
+	}{
+		{name: "single letter name", config: types.MountConfig{Name: "c", Path: t.TempDir()}},
+		{name: "invalid characters", config: types.MountConfig{Name: "my data", Path: t.TempDir()}},
+		{name: "reserved name", config: types.MountConfig{Name: types.DefaultMountName, Path: t.TempDir()}},
+		{name: "duplicate name", config: types.MountConfig{Name: "data", Path: t.TempDir()}},
+		{name: "invalid mode", config: types.MountConfig{Name: "other", Path: t.TempDir(), Mode: "wo"}},
+		{name: "missing directory", config: types.MountConfig{Name: "other", Path: filepath.Join(hostDir, "missing")}},
+		{name: "not a directory", config: types.MountConfig{Name: "other", Path: filepath.Join(hostDir, "file.txt")}},
+		{name: "inside sandbox", config: types.MountConfig{Name: "other", Path: tempDir}},
+		{name: "inside another mount", config: types.MountConfig{Name: "other", Path: hostDir}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Error(t, service.AddMount(tt.config))
+		})
+	}
+
+	resp, err := service.ListMounts(&types.ListMountsRequest{})
+	require.NoError(t, err)
+	require.Len(t, resp.Mounts, 2)
+	assert.True(t, resp.Mounts[0].Default)
+	assert.Equal(t, "data", resp.Mounts[1].Name)
+	assert.Equal(t, types.MountModeReadWrite, resp.Mounts[1].Mode)
+}
+
+func TestMountReadWrite(t *testing.T) {
+	service, tempDir, dataDir, _ := setupMountService(t)
+	defer cleanupTestService(t, tempDir)
+
+	_, err := service.WriteFile(&types.WriteFileRequest{Path: "data:out/result.txt", Content: "done"})
+	require.NoError(t, err)
+	assert.Equal(t, "done", readTestFile(t, filepath.Join(dataDir, "out", "result.txt")))
+	assert.NoFileExists(t, filepath.Join(tempDir, "out", "result.txt"))
+
+	read, err := service.ReadFile(&types.ReadFileRequest{Path: "data:out/result.txt"})
+	require.NoError(t, err)
+	assert.Equal(t, "done", read.Content)
+
+	// 路径遍历不能离开挂载 / Path traversal cannot leave the mount
+	_, err = service.ReadFile(&types.ReadFileRequest{Path: "data:../escape.txt"})
+	assert.Error(t, err)
+
+	// 挂载之间的移动 / Moves across mounts
+	_, err = service.Move(&types.MoveRequest{Source: "data:out/result.txt", Destination: "moved.txt"})
+	require.NoError(t, err)
+	assert.Equal(t, "done", readTestFile(t, filepath.Join(tempDir, "moved.txt")))
+	assert.NoFileExists(t, filepath.Join(dataDir, "out", "result.txt"))
+
+	stat, err := service.FileStat(&types.FileStatRequest{Path: "data:out"})
+	require.NoError(t, err)
+	assert.Equal(t, "data:out", stat.Path)
+
+	// 挂载根目录不能删除 / Mount roots cannot be deleted
+	_, err = service.Delete(&types.DeleteRequest{Path: "data:"})
+	assert.Error(t, err)
+	assert.DirExists(t, dataDir)
+}
+
+func TestMountReadOnly(t *testing.T) {
+	service, tempDir, _, docsDir := setupMountService(t)
+	defer cleanupTestService(t, tempDir)
+
+	read, err := service.ReadFile(&types.ReadFileRequest{Path: "docs:guide.md"})
+	require.NoError(t, err)
+	assert.Equal(t, "# guide\n", read.Content)
+
+	list, err := service.ListDir(&types.ListDirRequest{Path: "docs:"})
+	require.NoError(t, err)
+	assert.Len(t, list.Files, 2)
+
+	writes := map[string]func() error{
+		"write_file": func() error {
+			_, err := service.WriteFile(&types.WriteFileRequest{Path: "docs:guide.md", Content: "changed"})
+			return err
+		},
+		"create_file": func() error {
+			_, err := service.CreateFile(&types.CreateFileRequest{Path: "docs:new.md", Content: "new"})
+			return err
+		},
+		"create_directory": func() error {
+			_, err := service.CreateDir(&types.CreateDirRequest{Path: "docs:newdir"})
+			return err
+		},
+		"delete": func() error {
+			_, err := service.Delete(&types.DeleteRequest{Path: "docs:sub"})
+			return err
+		},
+		"append_file": func() error {
+			_, err := service.AppendFile(&types.AppendFileRequest{Path: "docs:guide.md", Content: "more"})
+			return err
+		},
+		"move out": func() error {
+			_, err := service.Move(&types.MoveRequest{Source: "docs:guide.md", Destination: "guide.md"})
+			return err
+		},
+		"copy in": func() error {
+			_, err := service.Copy(&types.CopyRequest{Source: "docs:guide.md", Destination: "docs:copy.md"})
+			return err
+		},
+		"edit_file": func() error {
+			_, err := service.EditFile(&types.EditFileRequest{Path: "docs:guide.md", Edits: []types.TextEdit{{OldText: "guide", NewText: "manual"}}})
+			return err
+		},
+	}
+	for name, write := range writes {
+		t.Run(name, func(t *testing.T) {
+			err := write()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), types.ErrReadOnlyMount)
+		})
+	}
+	assert.Equal(t, "# guide\n", readTestFile(t, filepath.Join(docsDir, "guide.md")))
+	assert.FileExists(t, filepath.Join(docsDir, "sub", "notes.txt"))
+
+	// 只读挂载可以复制到可写位置,编辑可以预览 / Read-only content can be copied elsewhere and edits can be previewed
+	_, err = service.Copy(&types.CopyRequest{Source: "docs:guide.md", Destination: "guide.md"})
+	require.NoError(t, err)
+	preview, err := service.EditFile(&types.EditFileRequest{Path: "docs:guide.md", Edits: []types.TextEdit{{OldText: "guide", NewText: "manual"}}, DryRun: true})
+	require.NoError(t, err)
+	assert.Contains(t, preview.Diff, "+# manual")
+}
+
+func TestMountSymlinkEscape(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks require elevated privileges on Windows")
+	}
+	service, tempDir, dataDir, _ := setupMountService(t)
+	defer cleanupTestService(t, tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{"secret.txt": "secret"})
+	require.NoError(t, os.Symlink(filepath.Join(tempDir, "secret.txt"), filepath.Join(dataDir, "link.txt")))
+
+	// 链接目标必须留在同一挂载内 / Link targets must stay inside the same mount
+	_, err := service.ReadFile(&types.ReadFileRequest{Path: "data:link.txt"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), types.ErrSandboxViolation)
+}
+
+func TestMountWorkingDirectory(t *testing.T) {
+	service, tempDir, _, _ := setupMountService(t)
+	defer cleanupTestService(t, tempDir)
+
+	wd, err := service.GetWorkingDirectory(&types.GetWorkingDirectoryRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, types.DefaultMountName, wd.Mount)
+	assert.False(t, wd.ReadOnly)
+
+	_, err = service.ChangeDirectory(&types.ChangeDirectoryRequest{Path: "docs:sub"})
+	require.NoError(t, err)
+
+	wd, err = service.GetWorkingDirectory(&types.GetWorkingDirectoryRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, "docs:sub", wd.WorkDir)
+	assert.Equal(t, "docs", wd.Mount)
+	assert.True(t, wd.ReadOnly)
+
+	if runtime.GOOS == "windows" {
+		return
+	}
+
+	resp, err := service.ExecuteCommand(&types.ExecuteCommandRequest{Command: "cat", Args: []string{"notes.txt"}})
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	assert.Equal(t, "notes\n", resp.Stdout)
+
+	// 只读挂载内不允许执行可能写入的命令 / Commands that may write are rejected inside a read-only mount
+	resp, err = service.ExecuteCommand(&types.ExecuteCommandRequest{Command: "touch", Args: []string{"new.txt"}})
+	require.NoError(t, err)
+	assert.False(t, resp.Success)
+	assert.Contains(t, resp.Stderr, types.ErrReadOnlyMount)
+
+	_, err = service.ChangeDirectory(&types.ChangeDirectoryRequest{Path: "data:"})
+	require.NoError(t, err)
+	resp, err = service.ExecuteCommand(&types.ExecuteCommandRequest{Command: "touch", Args: []string{"new.txt"}})
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+}
+
+func TestMountExcludedFromQuota(t *testing.T) {
+	service, tempDir, dataDir, _ := setupMountService(t)
+	defer cleanupTestService(t, tempDir)
+	require.NoError(t, service.SetQuotaConfig(types.QuotaConfig{MaxBytes: 10}))
+
+	// 挂载中的写入不计入沙箱配额 / Writes in a mount are not charged to the sandbox quota
+	_, err := service.WriteFile(&types.WriteFileRequest{Path: "data:big.txt", Content: "0123456789abcdef"})
+	require.NoError(t, err)
+
+	// 移入沙箱时按源大小检查配额 / Moving into the sandbox checks the quota with the source size
+	_, err = service.Move(&types.MoveRequest{Source: "data:big.txt", Destination: "big.txt"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), types.ErrQuotaExceeded)
+	assert.FileExists(t, filepath.Join(dataDir, "big.txt"))
+}
@@ -0,0 +1,130 @@
+// Copyright 2024 MCP Toolkit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sandbox
+
+import (
+	"path/filepath"
+	"sync"
+)
+
+// pathLock 对单个路径的加锁请求,锁覆盖路径本身及其下的整个子树 / A lock request on one path, covering the path and its whole subtree
+type pathLock struct {
+	path      string // 清理后的绝对路径 / Cleaned absolute path
+	exclusive bool   // 是否独占 / Whether the lock is exclusive
+}
+
+// readLock 共享锁,与同一子树上的其他共享锁兼容 / Shared lock, compatible with other shared locks on the same subtree
+func readLock(path string) pathLock {
+	return pathLock{path: filepath.Clean(path)}
+}
+
+// writeLock 独占锁,与重叠路径上的任何锁互斥 / Exclusive lock, conflicting with any lock on an overlapping path
+func writeLock(path string) pathLock {
+	return pathLock{path: filepath.Clean(path), exclusive: true}
+}
+
+// pathLockGroup 一次调用原子获取的一组锁 / A group of locks acquired atomically by one call
+type pathLockGroup []pathLock
+
+// conflicts 两组锁中存在路径重叠(相同或互为祖先)且至少一方独占的锁
+// Whether the groups hold locks on overlapping paths (equal or ancestor) where at least one side is exclusive
+func (g pathLockGroup) conflicts(other pathLockGroup) bool {
+	for _, a := range g {
+		for _, b := range other {
+			if (a.exclusive || b.exclusive) && pathsOverlap(a.path, b.path) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// pathLocker 按路径加读写锁的锁管理器。锁具有层级语义:锁定目录会阻塞其下路径的写入,
+// 锁定文件也会阻塞对其祖先目录的独占操作。等待者按到达顺序排队,后来者不能越过与其冲突的
+// 先来者,因此写锁不会被源源不断的读锁饿死;不重叠的路径互不影响
+// Lock manager handing out read/write locks keyed by path. Locks are hierarchical: locking a directory
+// blocks writes below it, and locking a file blocks exclusive operations on its ancestors. Waiters queue
+// in arrival order and may not overtake an earlier conflicting waiter, so writers are not starved by a
+// stream of readers; paths that do not overlap never wait for each other
+type pathLocker struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	held    []*pathLockGroup // 已持有的锁 / Locks currently held
+	waiting []*pathLockGroup // 按到达顺序排队的等待者 / Waiters in arrival order
+}
+
+// newPathLocker 创建锁管理器 / Create a lock manager
+func newPathLocker() *pathLocker {
+	l := &pathLocker{}
+	l.cond = sync.NewCond(&l.mu)
+	return l
+}
+
+// lock 原子地获取一组锁,返回释放函数。同一组内的锁彼此不冲突,因此移动和复制可以同时锁定源和目标
+// 而不会死锁。锁不可重入,持有者不能再次为重叠路径加锁
+// Atomically acquire a group of locks and return the release function. Locks within one group never
+// conflict with each other, so moves and copies can lock source and destination together without
+// deadlocking. Locks are not reentrant; a holder must not lock an overlapping path again
+func (l *pathLocker) lock(locks ...pathLock) func() {
+	group := pathLockGroup(locks)
+	entry := &group
+
+	l.mu.Lock()
+	l.waiting = append(l.waiting, entry)
+	for !l.grantable(entry) {
+		l.cond.Wait()
+	}
+	l.waiting = removeLockGroup(l.waiting, entry)
+	l.held = append(l.held, entry)
+	l.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			l.held = removeLockGroup(l.held, entry)
+			l.mu.Unlock()
+			l.cond.Broadcast()
+		})
+	}
+}
+
+// grantable 没有冲突的已持有锁,也没有排在前面的冲突等待者 / No conflicting lock is held and no conflicting waiter is ahead
+func (l *pathLocker) grantable(entry *pathLockGroup) bool {
+	for _, held := range l.held {
+		if held.conflicts(*entry) {
+			return false
+		}
+	}
+	for _, waiter := range l.waiting {
+		if waiter == entry {
+			break
+		}
+		if waiter.conflicts(*entry) {
+			return false
+		}
+	}
+	return true
+}
+
+// removeLockGroup 从列表中移除一组锁 / Remove a lock group from a list
+func removeLockGroup(list []*pathLockGroup, entry *pathLockGroup) []*pathLockGroup {
+	for i, g := range list {
+		if g == entry {
+			return append(list[:i], list[i+1:]...)
+		}
+	}
+	return list
+}
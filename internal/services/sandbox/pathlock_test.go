@@ -0,0 +1,237 @@
+package sandbox
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"mcp-toolkit/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lockAcquired 在后台加锁,返回获取锁时关闭的通道和释放函数 / Lock in the background, returning a channel closed once the lock is held and the release function
+func lockAcquired(l *pathLocker, locks ...pathLock) (<-chan struct{}, func()) {
+	acquired := make(chan struct{})
+	var unlock func()
+	var mu sync.Mutex
+	go func() {
+		release := l.lock(locks...)
+		mu.Lock()
+		unlock = release
+		mu.Unlock()
+		close(acquired)
+	}()
+	return acquired, func() {
+		<-acquired
+		mu.Lock()
+		defer mu.Unlock()
+		unlock()
+	}
+}
+
+// assertBlocked 断言锁在短时间内未被获取 / Assert the lock is not acquired within a short time
+func assertBlocked(t *testing.T, acquired <-chan struct{}) {
+	t.Helper()
+	select {
+	case <-acquired:
+		t.Fatal("lock acquired while a conflicting lock is held")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// assertAcquired 断言锁被获取 / Assert the lock is acquired
+func assertAcquired(t *testing.T, acquired <-chan struct{}) {
+	t.Helper()
+	select {
+	case <-acquired:
+	case <-time.After(5 * time.Second):
+		t.Fatal("lock not acquired")
+	}
+}
+
+func TestPathLocker(t *testing.T) {
+	l := newPathLocker()
+
+	// 共享锁之间兼容,不重叠的路径互不影响 / Shared locks are compatible and disjoint paths never wait
+	unlockA := l.lock(readLock("/sb/dir"))
+	unlockB := l.lock(readLock("/sb/dir/file.txt"))
+	unlockC := l.lock(writeLock("/sb/other"))
+
+	// 子路径的写锁等待目录上的读锁 / A write lock below a directory waits for the read lock on it
+	acquired, unlockW := lockAcquired(l, writeLock("/sb/dir/sub/new.txt"))
+	assertBlocked(t, acquired)
+	unlockA()
+	unlockA() // 重复释放无副作用 / Releasing twice is harmless
+	assertAcquired(t, acquired)
+	unlockB()
+	unlockC()
+
+	// 目录的写锁等待其下文件的锁 / A write lock on a directory waits for locks on files below it
+	acquired, unlockDir := lockAcquired(l, writeLock("/sb/dir"))
+	assertBlocked(t, acquired)
+	unlockW()
+	assertAcquired(t, acquired)
+
+	// 兄弟路径和前缀相同的路径不冲突 / Siblings and paths sharing a name prefix do not conflict
+	l.lock(writeLock("/sb/dir2"), writeLock("/sb/sibling"))()
+	unlockDir()
+
+	// 同一组内重叠的锁不会自锁 / Overlapping locks in one group do not block each other
+	l.lock(readLock("/sb/a"), writeLock("/sb/a/b"))()
+}
+
+func TestPathLockerFairness(t *testing.T) {
+	l := newPathLocker()
+	unlockRead := l.lock(readLock("/sb/file"))
+
+	// 排队的写锁阻止后来的读锁插队 / A queued writer keeps later readers from overtaking it
+	writer, unlockWriter := lockAcquired(l, writeLock("/sb/file"))
+	assertBlocked(t, writer)
+	reader, unlockReader := lockAcquired(l, readLock("/sb"))
+	assertBlocked(t, reader)
+
+	unlockRead()
+	assertAcquired(t, writer)
+	assertBlocked(t, reader)
+	unlockWriter()
+	assertAcquired(t, reader)
+	unlockReader()
+}
+
+func TestConcurrentAppendsAreSerialized(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+	writeTestFiles(t, tempDir, map[string]string{"log.txt": ""})
+
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := service.AppendFile(&types.AppendFileRequest{Path: "log.txt", Content: fmt.Sprintf("line %02d\n", i)})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	data, err := os.ReadFile(filepath.Join(tempDir, "log.txt"))
+	require.NoError(t, err)
+	assert.Len(t, data, 20*len("line 00\n"))
+}
+
+func TestMoveWaitsForPathLock(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+	writeTestFiles(t, tempDir, map[string]string{"src/a.txt": "a"})
+
+	// 持有源目录下文件的锁时移动目录需要等待 / Moving a directory waits while a file below it is locked
+	unlock := service.locks.lock(readLock(filepath.Join(tempDir, "src", "a.txt")))
+	done := make(chan error, 1)
+	go func() {
+		_, err := service.MoveDirectory(&types.MoveDirectoryRequest{Source: "src", Destination: "dst"})
+		done <- err
+	}()
+	select {
+	case <-done:
+		t.Fatal("move finished while the source was locked")
+	case <-time.After(50 * time.Millisecond):
+	}
+	assert.FileExists(t, filepath.Join(tempDir, "src", "a.txt"))
+
+	unlock()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("move did not finish")
+	}
+	assert.FileExists(t, filepath.Join(tempDir, "dst", "a.txt"))
+}
+
+func TestLockPath(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+	writeTestFiles(t, tempDir, map[string]string{"src/main.go": "package main\n"})
+
+	lock, err := service.LockPath(&types.LockPathRequest{Path: "src", Owner: "refactor-agent"})
+	require.NoError(t, err)
+	assert.Equal(t, "src", lock.Path)
+	assert.False(t, lock.Shared)
+	assert.WithinDuration(t, time.Now().Add(DefaultLockTTL), lock.ExpiresAt, time.Minute)
+
+	// 目录下的路径被占用,冲突信息指明持有者 / Paths below the directory are taken and the conflict names the holder
+	_, err = service.LockPath(&types.LockPathRequest{Path: "src/main.go", Shared: true})
+	assert.ErrorContains(t, err, types.ErrPathLocked)
+	assert.ErrorContains(t, err, `src is held by an exclusive "refactor-agent" lock`)
+	_, err = service.LockPath(&types.LockPathRequest{Path: "."})
+	assert.ErrorContains(t, err, types.ErrPathLocked)
+
+	// 租约是咨询性的,不阻塞文件操作 / Leases are advisory and do not block file operations
+	_, err = service.WriteFile(&types.WriteFileRequest{Path: "src/main.go", Content: "package main\n\nfunc main() {}\n"})
+	require.NoError(t, err)
+
+	// 续约需要相同的路径和模式 / Renewing requires the same path and mode
+	renewed, err := service.LockPath(&types.LockPathRequest{Path: "src", LockID: lock.LockID, TTL: 600})
+	require.NoError(t, err)
+	assert.Equal(t, types.MsgLockRenewed, renewed.Message)
+	assert.True(t, renewed.ExpiresAt.After(lock.ExpiresAt))
+	_, err = service.LockPath(&types.LockPathRequest{Path: "src", Shared: true, LockID: lock.LockID})
+	assert.ErrorContains(t, err, "different path or mode")
+
+	// 等待中的调用在租约释放后获得锁 / A waiting call gets the lock once the lease is released
+	done := make(chan *types.LockPathResponse, 1)
+	go func() {
+		resp, err := service.LockPath(&types.LockPathRequest{Path: "src/main.go", Wait: 5})
+		assert.NoError(t, err)
+		done <- resp
+	}()
+	time.Sleep(50 * time.Millisecond)
+	unlocked, err := service.UnlockPath(&types.UnlockPathRequest{LockID: lock.LockID})
+	require.NoError(t, err)
+	assert.Equal(t, "src", unlocked.Path)
+	select {
+	case resp := <-done:
+		assert.Equal(t, "src/main.go", resp.Path)
+	case <-time.After(5 * time.Second):
+		t.Fatal("waiting lock_path call did not get the lock")
+	}
+
+	_, err = service.UnlockPath(&types.UnlockPathRequest{LockID: lock.LockID})
+	assert.ErrorContains(t, err, types.ErrLockNotFound)
+}
+
+func TestLockPathSharedAndExpiry(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+	writeTestFiles(t, tempDir, map[string]string{"a.txt": "a"})
+
+	first, err := service.LockPath(&types.LockPathRequest{Path: "a.txt", Shared: true})
+	require.NoError(t, err)
+	_, err = service.LockPath(&types.LockPathRequest{Path: "a.txt", Shared: true})
+	require.NoError(t, err)
+	_, err = service.LockPath(&types.LockPathRequest{Path: "a.txt"})
+	assert.ErrorContains(t, err, "held by a shared lock")
+
+	// 过期的租约被清理 / Expired leases are pruned
+	service.leaseMu.Lock()
+	for _, lease := range service.leases {
+		lease.expiresAt = time.Now().Add(-time.Second)
+	}
+	service.leaseMu.Unlock()
+	_, err = service.LockPath(&types.LockPathRequest{Path: "a.txt"})
+	require.NoError(t, err)
+	_, err = service.UnlockPath(&types.UnlockPathRequest{LockID: first.LockID})
+	assert.ErrorContains(t, err, types.ErrLockNotFound)
+
+	_, err = service.LockPath(&types.LockPathRequest{Path: "../outside"})
+	assert.ErrorContains(t, err, types.ErrSandboxViolation)
+	_, err = service.LockPath(&types.LockPathRequest{Path: "a.txt", TTL: 7200})
+	assert.ErrorContains(t, err, "ttl must be between")
+	_, err = service.UnlockPath(&types.UnlockPathRequest{})
+	assert.ErrorContains(t, err, "lock_id is required")
+}
@@ -0,0 +1,287 @@
+// Copyright 2024 MCP Toolkit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sandbox
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"time"
+
+	"mcp-toolkit/pkg/types"
+
+	"go.uber.org/zap"
+)
+
+// QuotaRefreshInterval 缓存的配额用量的最长有效期,超过后下一次检查重新遍历沙箱
+// 期间经过配额检查的写入按大小变化更新缓存,命令执行后总是重新统计
+// Maximum age of the cached quota usage before the next check walks the sandbox again. In between, quota-checked
+// writes update the cache by their size delta, and usage is always recounted after a command
+const QuotaRefreshInterval = time.Minute
+
+// SetQuotaConfig 设置磁盘配额,应在开始提供服务前调用 / Set the disk quota, to be called before serving requests
+func (s *Service) SetQuotaConfig(config types.QuotaConfig) error {
+	if config.MaxBytes < 0 {
+		return fmt.Errorf("invalid quota max bytes %d, must not be negative", config.MaxBytes)
+	}
+	if config.MaxFiles < 0 {
+		return fmt.Errorf("invalid quota max files %d, must not be negative", config.MaxFiles)
+	}
+
+	s.quotaMu.Lock()
+	s.quotaConfig = config
+	s.quotaMeasured = time.Time{}
+	s.quotaMu.Unlock()
+
+	s.logger.Info("quota configured",
+		zap.Int64("max_bytes", config.MaxBytes),
+		zap.Int64("max_files", config.MaxFiles))
+	return nil
+}
+
+// QuotaConfig 获取磁盘配额配置 / Get the disk quota configuration
+func (s *Service) QuotaConfig() types.QuotaConfig {
+	s.quotaMu.Lock()
+	defer s.quotaMu.Unlock()
+	return s.quotaConfig
+}
+
+// GetQuotaUsage 获取沙箱配额用量 / Get sandbox quota usage
+func (s *Service) GetQuotaUsage(_ *types.GetQuotaUsageRequest) (*types.GetQuotaUsageResponse, error) {
+	usage, err := s.refreshUsage()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &types.GetQuotaUsageResponse{
+		QuotaUsage:     usage,
+		Enabled:        usage.MaxBytes > 0 || usage.MaxFiles > 0,
+		RemainingBytes: -1,
+		RemainingFiles: -1,
+	}
+	if usage.MaxBytes > 0 {
+		resp.RemainingBytes = max(usage.MaxBytes-usage.UsedBytes, 0)
+	}
+	if usage.MaxFiles > 0 {
+		resp.RemainingFiles = max(usage.MaxFiles-usage.UsedFiles, 0)
+	}
+	return resp, nil
+}
+
+// measureUsage 统计沙箱内的文件总大小和条目数,回收站和快照同样占用配额
+// Measure the total file size and entry count of the sandbox; the trash and snapshots count against the quota as well
+func (s *Service) measureUsage() (types.QuotaUsage, error) {
+	config := s.QuotaConfig()
+	usage := types.QuotaUsage{MaxBytes: config.MaxBytes, MaxFiles: config.MaxFiles}
+
+	err := walkDir(s.fsys, s.realSandboxDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// 遍历期间被删除的条目不计入 / Entries removed during the walk are not counted
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if path == s.realSandboxDir {
+			return nil
+		}
+		usage.UsedFiles++
+		if d.Type().IsRegular() {
+			if info, infoErr := d.Info(); infoErr == nil {
+				usage.UsedBytes += info.Size()
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return usage, fmt.Errorf("failed to measure sandbox usage: %w", err)
+	}
+
+	usage.Exceeded = usageExceeded(usage)
+	return usage, nil
+}
+
+// usageExceeded 用量是否超出任一配额上限 / Whether usage is over either quota limit
+func usageExceeded(usage types.QuotaUsage) bool {
+	return (usage.MaxBytes > 0 && usage.UsedBytes > usage.MaxBytes) ||
+		(usage.MaxFiles > 0 && usage.UsedFiles > usage.MaxFiles)
+}
+
+// refreshUsage 完整统计用量并替换缓存 / Measure usage in full and replace the cache
+func (s *Service) refreshUsage() (types.QuotaUsage, error) {
+	usage, err := s.measureUsage()
+	if err != nil {
+		return usage, err
+	}
+	s.quotaMu.Lock()
+	s.quotaUsage, s.quotaMeasured = usage, time.Now()
+	s.quotaMu.Unlock()
+	return usage, nil
+}
+
+// cachedUsage 返回缓存的用量,缓存为空或过期时重新统计;fresh表示结果是刚统计的
+// Return the cached usage, measuring again when the cache is empty or stale; fresh reports whether the result was just measured
+func (s *Service) cachedUsage() (usage types.QuotaUsage, fresh bool, err error) {
+	s.quotaMu.Lock()
+	if !s.quotaMeasured.IsZero() && time.Since(s.quotaMeasured) < QuotaRefreshInterval {
+		usage = s.quotaUsage
+		s.quotaMu.Unlock()
+		return usage, false, nil
+	}
+	s.quotaMu.Unlock()
+
+	usage, err = s.refreshUsage()
+	return usage, true, err
+}
+
+// chargeUsage 按写入的大小变化更新缓存的用量 / Update the cached usage by the size delta of a write
+func (s *Service) chargeUsage(addBytes, addFiles int64) {
+	s.quotaMu.Lock()
+	defer s.quotaMu.Unlock()
+	if s.quotaMeasured.IsZero() {
+		return
+	}
+	s.quotaUsage.UsedBytes = max(s.quotaUsage.UsedBytes+addBytes, 0)
+	s.quotaUsage.UsedFiles = max(s.quotaUsage.UsedFiles+addFiles, 0)
+	s.quotaUsage.Exceeded = usageExceeded(s.quotaUsage)
+}
+
+// measureUsageAfterCommand 命令的写入无法预先检查,执行后重新统计用量并在超额时记录日志,统计失败时返回nil
+// Writes by commands cannot be checked in advance, so usage is recounted afterwards and overage is logged; returns nil when measuring fails
+func (s *Service) measureUsageAfterCommand(commandLine string) *types.QuotaUsage {
+	if !s.quotaEnabled() {
+		return nil
+	}
+	usage, err := s.refreshUsage()
+	if err != nil {
+		s.logger.Warn("failed to measure quota usage after command", zap.Error(err))
+		return nil
+	}
+	if usage.Exceeded {
+		s.logger.Warn("sandbox quota exceeded after command",
+			zap.String("command_line", commandLine),
+			zap.Int64("used_bytes", usage.UsedBytes),
+			zap.Int64("max_bytes", usage.MaxBytes),
+			zap.Int64("used_files", usage.UsedFiles),
+			zap.Int64("max_files", usage.MaxFiles))
+	}
+	return &usage
+}
+
+// quotaEnabled 是否配置了任一配额上限 / Whether either quota limit is configured
+func (s *Service) quotaEnabled() bool {
+	config := s.QuotaConfig()
+	return config.MaxBytes > 0 || config.MaxFiles > 0
+}
+
+// checkQuota 检查新增的字节数和条目数是否超出配额,只有增长会被拒绝,因此超额后仍可缩小或删除文件
+// 检查基于缓存的用量,通过后立即把变化计入缓存;删除等未计入的操作会使缓存偏高,因此拒绝前总会重新统计
+// Check whether adding bytes and entries would exceed the quota. Only growth is rejected, so files can still be shrunk or
+// removed once the quota is exceeded. The check uses the cached usage and charges the delta to it once it passes;
+// removals and other uncharged operations leave the cache high, so usage is always recounted before rejecting
+func (s *Service) checkQuota(addBytes, addFiles int64) error {
+	if !s.quotaEnabled() || (addBytes == 0 && addFiles == 0) {
+		return nil
+	}
+
+	usage, fresh, err := s.cachedUsage()
+	if err != nil {
+		return err
+	}
+	if err = quotaError(usage, addBytes, addFiles); err != nil && !fresh {
+		if usage, err = s.refreshUsage(); err != nil {
+			return err
+		}
+		err = quotaError(usage, addBytes, addFiles)
+	}
+	if err != nil {
+		return err
+	}
+	s.chargeUsage(addBytes, addFiles)
+	return nil
+}
+
+// quotaError 检查用量加上增长后是否超出配额 / Check whether usage plus the growth exceeds the quota
+func quotaError(usage types.QuotaUsage, addBytes, addFiles int64) error {
+	if addBytes > 0 && usage.MaxBytes > 0 && usage.UsedBytes+addBytes > usage.MaxBytes {
+		return fmt.Errorf("%s: writing %d bytes would use %d of %d bytes", types.ErrQuotaExceeded,
+			addBytes, usage.UsedBytes+addBytes, usage.MaxBytes)
+	}
+	if addFiles > 0 && usage.MaxFiles > 0 && usage.UsedFiles+addFiles > usage.MaxFiles {
+		return fmt.Errorf("%s: creating %d entries would use %d of %d files", types.ErrQuotaExceeded,
+			addFiles, usage.UsedFiles+addFiles, usage.MaxFiles)
+	}
+	return nil
+}
+
+// remainingQuotaBytes 剩余可写字节数,不限制时返回-1 / Remaining writable bytes, -1 when unlimited
+func (s *Service) remainingQuotaBytes() (int64, error) {
+	if s.QuotaConfig().MaxBytes <= 0 {
+		return -1, nil
+	}
+	usage, _, err := s.cachedUsage()
+	if err != nil {
+		return 0, err
+	}
+	return max(usage.MaxBytes-usage.UsedBytes, 0), nil
+}
+
+// checkWriteQuota 检查将路径写为指定大小的文件是否超出配额,已有文件的大小会被扣除,挂载中的路径不计入配额
+// Check whether writing a file of the given size at path exceeds the quota, crediting the size of an existing file; paths in mounts are not charged
+func (s *Service) checkWriteQuota(path string, size int64) error {
+	if !s.quotaEnabled() || !s.inDefaultMount(path) {
+		return nil
+	}
+	addBytes, addFiles := quotaDelta(s.fsys, path, size)
+	return s.checkQuota(addBytes, addFiles)
+}
+
+// quotaDelta 计算在路径写入指定大小文件带来的字节数和条目数变化,包括需要创建的父目录
+// Compute the change in bytes and entries caused by writing a file of the given size at path, including parent directories to create
+func quotaDelta(fsys FileSystem, path string, size int64) (int64, int64) {
+	if info, err := fsys.Lstat(path); err == nil {
+		if info.Mode().IsRegular() {
+			return size - info.Size(), 0
+		}
+		return size, 0
+	}
+
+	files := int64(1)
+	for dir := filepath.Dir(path); dir != filepath.Dir(dir); dir = filepath.Dir(dir) {
+		if _, err := fsys.Lstat(dir); err == nil {
+			break
+		}
+		files++
+	}
+	return size, files
+}
+
+// treeUsage 统计目录树中的文件总大小和条目数(不含根目录本身)
+// Measure the total file size and entry count of a directory tree (excluding the root itself)
+func treeUsage(fsys FileSystem, root string) (int64, int64) {
+	var bytes, files int64
+	_ = walkDir(fsys, root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || path == root {
+			return nil
+		}
+		files++
+		if info, infoErr := d.Info(); infoErr == nil && !info.IsDir() {
+			bytes += info.Size()
+		}
+		return nil
+	})
+	return bytes, files
+}
@@ -0,0 +1,227 @@
+package sandbox
+
+import (
+	"archive/zip"
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"mcp-toolkit/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupQuotaService(t *testing.T, config types.QuotaConfig) (*Service, string) {
+	service, tempDir := setupTestService(t)
+	require.NoError(t, service.SetQuotaConfig(config))
+	return service, tempDir
+}
+
+func TestGetQuotaUsage(t *testing.T) {
+	service, tempDir := setupQuotaService(t, types.QuotaConfig{MaxBytes: 100, MaxFiles: 10})
+	defer cleanupTestService(t, tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{"a.txt": "12345", "dir/b.txt": "1234567890"})
+
+	resp, err := service.GetQuotaUsage(&types.GetQuotaUsageRequest{})
+	require.NoError(t, err)
+	assert.True(t, resp.Enabled)
+	assert.Equal(t, int64(15), resp.UsedBytes)
+	assert.Equal(t, int64(3), resp.UsedFiles)
+	assert.Equal(t, int64(85), resp.RemainingBytes)
+	assert.Equal(t, int64(7), resp.RemainingFiles)
+	assert.False(t, resp.Exceeded)
+
+	require.NoError(t, service.SetQuotaConfig(types.QuotaConfig{}))
+	resp, err = service.GetQuotaUsage(&types.GetQuotaUsageRequest{})
+	require.NoError(t, err)
+	assert.False(t, resp.Enabled)
+	assert.Equal(t, int64(-1), resp.RemainingBytes)
+	assert.Equal(t, int64(-1), resp.RemainingFiles)
+
+	assert.Error(t, service.SetQuotaConfig(types.QuotaConfig{MaxBytes: -1}))
+	assert.Error(t, service.SetQuotaConfig(types.QuotaConfig{MaxFiles: -1}))
+}
+
+func TestQuotaWriteFile(t *testing.T) {
+	service, tempDir := setupQuotaService(t, types.QuotaConfig{MaxBytes: 20, MaxFiles: 3})
+	defer cleanupTestService(t, tempDir)
+
+	_, err := service.WriteFile(&types.WriteFileRequest{Path: "a.txt", Content: strings.Repeat("a", 15)})
+	require.NoError(t, err)
+
+	_, err = service.CreateFile(&types.CreateFileRequest{Path: "b.txt", Content: strings.Repeat("b", 6)})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), types.ErrQuotaExceeded)
+	assert.NoFileExists(t, filepath.Join(tempDir, "b.txt"))
+
+	// 覆盖已有文件只计算增量 / Overwriting an existing file only counts the difference
+	_, err = service.WriteFile(&types.WriteFileRequest{Path: "a.txt", Content: strings.Repeat("a", 20)})
+	require.NoError(t, err)
+
+	// 缩小文件总是允许 / Shrinking a file is always allowed
+	_, err = service.WriteFile(&types.WriteFileRequest{Path: "a.txt", Content: "a"})
+	require.NoError(t, err)
+
+	// 新建的父目录同样计入文件数 / New parent directories count against the file limit
+	_, err = service.WriteFile(&types.WriteFileRequest{Path: "x/y/z.txt", Content: "z"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), types.ErrQuotaExceeded)
+	assert.NoDirExists(t, filepath.Join(tempDir, "x"))
+
+	_, err = service.WriteFile(&types.WriteFileRequest{Path: "x/z.txt", Content: "z"})
+	require.NoError(t, err)
+}
+
+func TestQuotaCopy(t *testing.T) {
+	service, tempDir := setupQuotaService(t, types.QuotaConfig{MaxBytes: 30})
+	defer cleanupTestService(t, tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{"src/a.txt": "0123456789", "src/b.txt": "0123456789"})
+
+	_, err := service.CopyDirectory(&types.CopyDirectoryRequest{Source: "src", Destination: "dst"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), types.ErrQuotaExceeded)
+	assert.NoDirExists(t, filepath.Join(tempDir, "dst"))
+
+	_, err = service.CopyFile(&types.CopyFileRequest{Source: "src/a.txt", Destination: "copy.txt"})
+	require.NoError(t, err)
+
+	_, err = service.Copy(&types.CopyRequest{Source: "src/b.txt", Destination: "copy2.txt"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), types.ErrQuotaExceeded)
+}
+
+func TestQuotaDownloadFile(t *testing.T) {
+	service, tempDir := setupQuotaService(t, types.QuotaConfig{MaxBytes: 50})
+	defer cleanupTestService(t, tempDir)
+
+	payload := strings.Repeat("x", 100)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 分块传输,不提供Content-Length / Chunked transfer without a Content-Length
+		if r.URL.Path == "/chunked" {
+			w.(http.Flusher).Flush()
+		}
+		_, _ = w.Write([]byte(payload))
+	}))
+	defer server.Close()
+
+	_, err := service.DownloadFile(&types.DownloadFileRequest{URL: server.URL + "/sized", Path: "sized.bin"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), types.ErrQuotaExceeded)
+	assert.NoFileExists(t, filepath.Join(tempDir, "sized.bin"))
+
+	_, err = service.DownloadFile(&types.DownloadFileRequest{URL: server.URL + "/chunked", Path: "chunked.bin"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), types.ErrQuotaExceeded)
+	assert.NoFileExists(t, filepath.Join(tempDir, "chunked.bin"))
+}
+
+func TestQuotaExtractArchive(t *testing.T) {
+	service, tempDir := setupQuotaService(t, types.QuotaConfig{MaxFiles: 4})
+	defer cleanupTestService(t, tempDir)
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, name := range []string{"a/1.txt", "a/2.txt", "a/3.txt"} {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, _ = w.Write([]byte("data"))
+	}
+	require.NoError(t, zw.Close())
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "files.zip"), buf.Bytes(), 0644))
+
+	// 归档本身占用1个,解压需要目标目录、a和3个文件 / The archive uses one entry; extraction needs out, a and 3 files
+	_, err := service.ExtractArchive(&types.ExtractArchiveRequest{Path: "files.zip", Destination: "out"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), types.ErrQuotaExceeded)
+	assert.NoDirExists(t, filepath.Join(tempDir, "out"))
+
+	require.NoError(t, service.SetQuotaConfig(types.QuotaConfig{MaxFiles: 6}))
+	_, err = service.ExtractArchive(&types.ExtractArchiveRequest{Path: "files.zip", Destination: "out"})
+	require.NoError(t, err)
+}
+
+func TestQuotaExecuteCommandFlagsExcess(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell")
+	}
+	service, tempDir := setupQuotaService(t, types.QuotaConfig{MaxBytes: 10})
+	defer cleanupTestService(t, tempDir)
+
+	resp, err := service.ExecuteCommand(&types.ExecuteCommandRequest{Command: "sh", Args: []string{"-c", "printf 0123456789abcdef > big.txt"}})
+	require.NoError(t, err)
+	require.True(t, resp.Success, resp.Stderr)
+	assert.True(t, resp.QuotaExceeded)
+	require.NotNil(t, resp.QuotaUsage)
+	assert.Equal(t, int64(16), resp.QuotaUsage.UsedBytes)
+}
+
+func TestQuotaExecuteCommandAsyncFlagsExcess(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell")
+	}
+	service, tempDir := setupQuotaService(t, types.QuotaConfig{MaxBytes: 10})
+	defer cleanupTestService(t, tempDir)
+
+	resp, err := service.ExecuteCommandAsync(&types.ExecuteCommandAsyncRequest{Command: "sh", Args: []string{"-c", "printf 0123456789abcdef > big.txt"}, Timeout: 10})
+	require.NoError(t, err)
+
+	var task *types.CommandTask
+	require.Eventually(t, func() bool {
+		taskResp, err := service.GetCommandTask(&types.GetCommandTaskRequest{TaskID: resp.TaskID})
+		require.NoError(t, err)
+		task = taskResp.Task
+		return task.Status == types.TaskStatusCompleted || task.Status == types.TaskStatusFailed
+	}, 5*time.Second, 20*time.Millisecond)
+
+	require.Equal(t, types.TaskStatusCompleted, task.Status, task.Stderr)
+	assert.True(t, task.QuotaExceeded)
+	require.NotNil(t, task.QuotaUsage)
+	assert.Equal(t, int64(16), task.QuotaUsage.UsedBytes)
+
+	// 命令之后的检查使用重新统计的用量 / Checks after the command use the recounted usage
+	_, err = service.AppendFile(&types.AppendFileRequest{Path: "big.txt", Content: "x"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), types.ErrQuotaExceeded)
+}
+
+func TestQuotaUsageCache(t *testing.T) {
+	service, tempDir := setupQuotaService(t, types.QuotaConfig{MaxBytes: 100, MaxFiles: 10})
+	defer cleanupTestService(t, tempDir)
+
+	_, err := service.WriteFile(&types.WriteFileRequest{Path: "a.txt", Content: strings.Repeat("a", 40)})
+	require.NoError(t, err)
+	measured := service.quotaMeasured
+	require.False(t, measured.IsZero())
+
+	// 写入按大小变化更新缓存而不重新遍历 / Writes update the cache by their size delta without walking again
+	_, err = service.AppendFile(&types.AppendFileRequest{Path: "dir/b.txt", Content: strings.Repeat("b", 30)})
+	require.NoError(t, err)
+	_, err = service.TruncateFile(&types.TruncateFileRequest{Path: "a.txt", Size: 10})
+	require.NoError(t, err)
+	_, err = service.WriteFile(&types.WriteFileRequest{Path: "a.txt", Content: strings.Repeat("a", 25)})
+	require.NoError(t, err)
+	assert.Equal(t, measured, service.quotaMeasured)
+
+	usage, err := service.measureUsage()
+	require.NoError(t, err)
+	assert.Equal(t, usage, service.quotaUsage)
+
+	// 未计入的删除使缓存偏高,拒绝前重新统计 / An uncharged removal leaves the cache high, so usage is recounted before rejecting
+	require.NoError(t, os.RemoveAll(filepath.Join(tempDir, "dir")))
+	_, err = service.WriteFile(&types.WriteFileRequest{Path: "c.txt", Content: strings.Repeat("c", 70)})
+	require.NoError(t, err)
+	assert.True(t, service.quotaMeasured.After(measured))
+
+	_, err = service.WriteFile(&types.WriteFileRequest{Path: "d.txt", Content: strings.Repeat("d", 6)})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), types.ErrQuotaExceeded)
+}
@@ -0,0 +1,459 @@
+// Copyright 2024 MCP Toolkit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sandbox
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+	"unicode/utf8"
+
+	"mcp-toolkit/pkg/types"
+)
+
+// readBufferSize 流式读取缓冲区大小 / Buffer size for streaming reads
+const readBufferSize = 64 * 1024
+
+// contentReader 读取文本内容所需的接口,由文件和解码后的内存文本实现
+// What reading text content needs, implemented by files and by decoded in-memory text
+type contentReader interface {
+	io.Reader
+	io.ReaderAt
+	io.Seeker
+}
+
+// readFileContent 按请求的模式读取文件内容 / Read file content according to the requested mode
+func readFileContent(file File, size int64, req *types.ReadFileRequest) (*types.ReadFileResponse, error) {
+	limit := req.Length
+	if limit <= 0 {
+		limit = DefaultReadSize
+	}
+
+	// 采样文件头部用于二进制和MIME检测 / Sample file head for binary and MIME detection
+	sample := make([]byte, BinaryDetectSize)
+	n, err := io.ReadFull(file, sample)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	sample = sample[:n]
+
+	resp := &types.ReadFileResponse{
+		Size:     size,
+		MimeType: detectMimeType(file.Name(), sample),
+	}
+
+	lineMode := req.StartLine > 0 || req.EndLine > 0 || req.TailLines > 0
+
+	charset, bomLen := detectCharset(sample)
+	if charset != "" && (charset != types.CharsetUTF8 || bomLen > 0) {
+		// 其他字符集的文件整体解码为UTF-8,偏移、长度和行号均相对于解码后的文本
+		// Files in other charsets are decoded to UTF-8 as a whole; offsets, lengths and line numbers refer to the decoded text
+		text, ok, err := decodeFileText(file, size, charset)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			resp.Charset = charset
+			resp.BOM = bomLen > 0
+			mediaType, _, _ := mime.ParseMediaType(resp.MimeType)
+			if !strings.HasPrefix(mediaType, "text/") {
+				mediaType = "text/plain"
+			}
+			resp.MimeType = mime.FormatMediaType(mediaType, map[string]string{"charset": charset})
+			return readTextContent(strings.NewReader(text), int64(len(text)), resp, req, limit)
+		}
+		// 过大或采样之后含非法字节的文件按二进制返回 / Files too large to decode or invalid past the sample are returned as binary
+		charset = ""
+	}
+
+	if charset == "" {
+		if lineMode {
+			return nil, errors.New("line ranges are not supported for binary files, use offset and length instead")
+		}
+		data, err := readByteRange(file, size, req.Offset, limit)
+		if err != nil {
+			return nil, err
+		}
+		resp.Content = base64.StdEncoding.EncodeToString(data)
+		resp.Encoding = types.ContentEncodingBase64
+		resp.Offset = req.Offset
+		resp.Length = int64(len(data))
+		resp.Truncated = req.Offset+resp.Length < size
+		return resp, nil
+	}
+
+	resp.Charset = charset
+	return readTextContent(file, size, resp, req, limit)
+}
+
+// readTextContent 按字节范围或行范围读取文本内容。总行数只在读取本身已经到达文件末尾或请求了count_lines时给出,
+// 避免对大文件的小范围读取扫描整个文件
+// Read text content by byte range or line range. The total line count is only given when the read reached the end of the
+// file anyway or count_lines was requested, so small reads of large files do not scan the whole file
+func readTextContent(file contentReader, size int64, resp *types.ReadFileResponse, req *types.ReadFileRequest, limit int64) (*types.ReadFileResponse, error) {
+	var (
+		data       []byte
+		encoded    bool // 字节范围已自行设置内容 / The byte range already set the content
+		totalLines = -1 // 未知 / Unknown
+		err        error
+	)
+
+	switch {
+	case req.TailLines > 0:
+		var tail tailResult
+		if tail, err = readTailLines(file, size, req.TailLines, limit); err != nil {
+			return nil, err
+		}
+		data, totalLines = tail.data, tail.totalLines
+		if totalLines < 0 && req.CountLines {
+			if totalLines, err = countLines(file); err != nil {
+				return nil, err
+			}
+		}
+		if totalLines >= 0 && tail.lines > 0 {
+			resp.StartLine = totalLines - tail.lines + 1
+			resp.EndLine = totalLines
+		}
+		resp.Offset = tail.offset
+		resp.Truncated = tail.truncated
+
+	case req.StartLine > 0 || req.EndLine > 0:
+		start := max(req.StartLine, 1)
+		var offset int64
+		var lastLine int
+		if data, offset, lastLine, totalLines, resp.Truncated, err = readLineRange(file, start, req.EndLine, limit); err != nil {
+			return nil, err
+		}
+		if lastLine > 0 {
+			resp.StartLine = start
+			resp.EndLine = lastLine
+		}
+		if offset < 0 {
+			offset = size
+		}
+		resp.Offset = offset
+
+	default:
+		if data, err = readByteRange(file, size, req.Offset, limit); err != nil {
+			return nil, err
+		}
+		end := req.Offset + int64(len(data))
+		if req.Offset == 0 && end == size {
+			totalLines = lineCount(data)
+		}
+
+		// 字节范围可能切断多字节字符 / A byte range may split multi-byte characters
+		text, skipped := trimPartialRunes(data, req.Offset > 0, end < size)
+		if utf8.Valid(text) {
+			resp.Content = string(text)
+			resp.Encoding = types.ContentEncodingUTF8
+			resp.Offset = req.Offset + int64(skipped)
+			resp.Length = int64(len(text))
+		} else {
+			resp.Content = base64.StdEncoding.EncodeToString(data)
+			resp.Encoding = types.ContentEncodingBase64
+			resp.Offset = req.Offset
+			resp.Length = int64(len(data))
+		}
+		resp.Truncated = resp.Offset+resp.Length < size
+		encoded = true
+	}
+
+	if !encoded {
+		if utf8.Valid(data) {
+			resp.Content = string(data)
+			resp.Encoding = types.ContentEncodingUTF8
+		} else {
+			resp.Content = base64.StdEncoding.EncodeToString(data)
+			resp.Encoding = types.ContentEncodingBase64
+		}
+		resp.Length = int64(len(data))
+	}
+
+	if totalLines < 0 && req.CountLines {
+		if totalLines, err = countLines(file); err != nil {
+			return nil, err
+		}
+	}
+	resp.TotalLines = max(totalLines, 0)
+	return resp, nil
+}
+
+// readByteRange 读取指定字节范围 / Read the given byte range
+func readByteRange(file contentReader, size, offset, limit int64) ([]byte, error) {
+	if offset >= size {
+		return []byte{}, nil
+	}
+	length := size - offset
+	if length > limit {
+		length = limit
+	}
+
+	data := make([]byte, length)
+	n, err := file.ReadAt(data, offset)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	return data[:n], nil
+}
+
+// readLineRange 流式读取指定行范围,返回内容、起始字节偏移、最后返回的行号、读到文件末尾时的总行数(否则为-1)以及是否截断
+// Stream the given line range, returning content, start byte offset, last returned line, the total line count when the
+// end of the file was reached (-1 otherwise) and whether it was truncated
+func readLineRange(file contentReader, start, end int, limit int64) ([]byte, int64, int, int, bool, error) {
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, 0, 0, -1, false, fmt.Errorf("failed to seek file: %w", err)
+	}
+
+	reader := bufio.NewReaderSize(file, readBufferSize)
+	var (
+		buf        bytes.Buffer
+		lineBuf    []byte
+		lineNo     = 1
+		pos        int64
+		startPos   int64 = -1
+		lastLine   int
+		totalLines = -1
+		truncated  bool
+	)
+
+	for {
+		chunk, err := reader.ReadSlice('\n')
+		if len(chunk) > 0 && lineNo >= start && (end == 0 || lineNo <= end) {
+			if startPos < 0 {
+				startPos = pos
+			}
+			lineBuf = append(lineBuf, chunk...)
+			if int64(buf.Len()+len(lineBuf)) > limit {
+				// 单行超过预算时返回该行头部 / Return the head of a single line that exceeds the budget
+				if buf.Len() == 0 {
+					head, _ := trimPartialRunes(lineBuf[:limit], false, true)
+					buf.Write(head)
+					lastLine = lineNo
+				}
+				truncated = true
+				break
+			}
+		}
+		pos += int64(len(chunk))
+
+		if errors.Is(err, bufio.ErrBufferFull) {
+			continue
+		}
+		if len(lineBuf) > 0 {
+			buf.Write(lineBuf)
+			lastLine = lineNo
+			lineBuf = lineBuf[:0]
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				// 以换行符结尾的文件在末尾读到空块 / A file ending in a newline yields an empty final chunk
+				totalLines = lineNo
+				if len(chunk) == 0 {
+					totalLines--
+				}
+				break
+			}
+			return nil, 0, 0, -1, false, fmt.Errorf("failed to read file: %w", err)
+		}
+
+		lineNo++
+		if end > 0 && lineNo > end {
+			break
+		}
+	}
+
+	return buf.Bytes(), startPos, lastLine, totalLines, truncated, nil
+}
+
+// tailResult 读取文件末尾若干行的结果 / Result of reading the last lines of a file
+type tailResult struct {
+	data       []byte
+	offset     int64 // 内容的起始字节偏移 / Byte offset of the content
+	lines      int   // 返回的行数 / Number of lines returned
+	totalLines int   // 扫描到文件开头时的总行数,否则为-1 / Total line count when the scan reached the start of the file, -1 otherwise
+	truncated  bool
+}
+
+// readTailLines 从文件末尾按块向前扫描,读取最后n行。超出字节预算时丢弃较早的行,保留最新的行;
+// 最后一行本身超出预算时返回该行的尾部
+// Read the last n lines by scanning backwards from the end of the file in blocks. When they exceed the byte budget the
+// earlier lines are dropped and the newest kept; when the last line alone exceeds it, the end of that line is returned
+func readTailLines(file contentReader, size int64, n int, limit int64) (tailResult, error) {
+	result := tailResult{totalLines: -1}
+	if size == 0 {
+		result.totalLines = 0
+		return result, nil
+	}
+
+	minStart := size - limit // 满足预算的最早起点 / Earliest start within the budget
+	start := int64(-1)       // 选定的起点 / Chosen start
+	fits := int64(-1)        // 预算内最早的行首 / Earliest line start within the budget
+	found, newlines := 0, 0
+	endsWithNewline := false
+	buf := make([]byte, readBufferSize)
+	pos := size
+
+	done := false
+	for pos > 0 && !done {
+		blockStart := max(pos-int64(len(buf)), 0)
+		block := buf[:pos-blockStart]
+		if _, err := file.ReadAt(block, blockStart); err != nil && !errors.Is(err, io.EOF) {
+			return result, fmt.Errorf("failed to read file: %w", err)
+		}
+		if pos == size {
+			endsWithNewline = block[len(block)-1] == '\n'
+		}
+		i := len(block) - 1
+		for ; i >= 0 && !done; i-- {
+			if block[i] != '\n' {
+				continue
+			}
+			newlines++
+			idx := blockStart + int64(i)
+			if idx == size-1 {
+				continue // 最后一行的换行符 / Terminator of the last line
+			}
+			if lineStart := idx + 1; lineStart < minStart {
+				// 更早的行超出预算 / Earlier lines exceed the budget
+				start, result.truncated, done = fits, true, true
+			} else if found++; found == n {
+				start, done = lineStart, true
+			} else {
+				fits = lineStart
+			}
+		}
+		pos = blockStart
+		if done && pos == 0 {
+			// 开头已在内存中,顺便数完剩余的行 / The start is already in memory, so count the remaining lines as well
+			newlines += bytes.Count(block[:i+1], []byte{'\n'})
+		}
+	}
+
+	if pos == 0 {
+		// 已扫描整个文件 / The whole file was scanned
+		result.totalLines = newlines
+		if !endsWithNewline {
+			result.totalLines++ // 最后一行没有换行符 / Last line without trailing newline
+		}
+		if !done {
+			if size <= limit {
+				start = 0
+			} else {
+				start, result.truncated = fits, true
+			}
+		}
+	}
+
+	if start < 0 {
+		// 最后一行本身超出预算 / The last line alone exceeds the budget
+		data, err := readByteRange(file, size, minStart, limit)
+		if err != nil {
+			return result, err
+		}
+		data, skipped := trimPartialRunes(data, true, false)
+		result.data = data
+		result.offset = minStart + int64(skipped)
+		result.lines = 1
+		result.truncated = true
+		return result, nil
+	}
+
+	data, err := readByteRange(file, size, start, limit)
+	if err != nil {
+		return result, err
+	}
+	result.data = data
+	result.offset = start
+	result.lines = lineCount(data)
+	return result, nil
+}
+
+// lineCount 统计内容中的行数 / Count the lines in content
+func lineCount(data []byte) int {
+	lines := bytes.Count(data, []byte{'\n'})
+	if len(data) > 0 && data[len(data)-1] != '\n' {
+		lines++
+	}
+	return lines
+}
+
+// countLines 统计文件行数 / Count lines in file
+func countLines(file contentReader) (int, error) {
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return 0, fmt.Errorf("failed to seek file: %w", err)
+	}
+
+	buf := make([]byte, readBufferSize)
+	lines := 0
+	endsWithNewline := true
+	for {
+		n, err := file.Read(buf)
+		if n > 0 {
+			lines += bytes.Count(buf[:n], []byte{'\n'})
+			endsWithNewline = buf[n-1] == '\n'
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return 0, fmt.Errorf("failed to read file: %w", err)
+		}
+	}
+
+	// 最后一行没有换行符 / Last line without trailing newline
+	if !endsWithNewline {
+		lines++
+	}
+	return lines, nil
+}
+
+// isBinaryContent 判断采样内容是否为二进制 / Check whether sampled content is binary
+func isBinaryContent(sample []byte) bool {
+	if bytes.IndexByte(sample, 0) >= 0 {
+		return true
+	}
+	text, _ := trimPartialRunes(sample, false, true)
+	return !utf8.Valid(text)
+}
+
+// trimPartialRunes 去掉首尾被截断的UTF-8字符字节,返回剩余内容和头部跳过的字节数
+// Drop bytes of UTF-8 characters split at either end, returning the remainder and the number of skipped head bytes
+func trimPartialRunes(data []byte, trimHead, trimTail bool) ([]byte, int) {
+	head := 0
+	if trimHead {
+		for head < len(data) && head < utf8.UTFMax-1 && !utf8.RuneStart(data[head]) {
+			head++
+		}
+	}
+	data = data[head:]
+
+	if trimTail {
+		for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+			if utf8.RuneStart(data[i]) {
+				if !utf8.FullRune(data[i:]) {
+					data = data[:i]
+				}
+				break
+			}
+		}
+	}
+	return data, head
+}
@@ -0,0 +1,277 @@
+package sandbox
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"mcp-toolkit/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadFileLineRange(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	content := "line1\nline2\nline3\nline4\nline5\n"
+	err := os.WriteFile(filepath.Join(tempDir, "lines.txt"), []byte(content), 0644)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		req       types.ReadFileRequest
+		content   string
+		startLine int
+		endLine   int
+		offset    int64
+	}{
+		{
+			name:      "middle range",
+			req:       types.ReadFileRequest{Path: "lines.txt", StartLine: 2, EndLine: 3, CountLines: true},
+			content:   "line2\nline3\n",
+			startLine: 2,
+			endLine:   3,
+			offset:    6,
+		},
+		{
+			name:      "open ended range",
+			req:       types.ReadFileRequest{Path: "lines.txt", StartLine: 4},
+			content:   "line4\nline5\n",
+			startLine: 4,
+			endLine:   5,
+			offset:    18,
+		},
+		{
+			name:      "tail lines",
+			req:       types.ReadFileRequest{Path: "lines.txt", TailLines: 2},
+			content:   "line4\nline5\n",
+			startLine: 4,
+			endLine:   5,
+			offset:    18,
+		},
+		{
+			name:      "tail larger than file",
+			req:       types.ReadFileRequest{Path: "lines.txt", TailLines: 100},
+			content:   content,
+			startLine: 1,
+			endLine:   5,
+			offset:    0,
+		},
+		{
+			name:    "start beyond end of file",
+			req:     types.ReadFileRequest{Path: "lines.txt", StartLine: 10},
+			content: "",
+			offset:  int64(len(content)),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := service.ReadFile(&tt.req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.content, resp.Content)
+			assert.Equal(t, types.ContentEncodingUTF8, resp.Encoding)
+			assert.Equal(t, tt.startLine, resp.StartLine)
+			assert.Equal(t, tt.endLine, resp.EndLine)
+			assert.Equal(t, tt.offset, resp.Offset)
+			assert.Equal(t, 5, resp.TotalLines)
+			assert.Equal(t, int64(len(content)), resp.Size)
+			assert.False(t, resp.Truncated)
+		})
+	}
+}
+
+func TestReadFileLineRangeTruncated(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	content := "aaaa\nbbbb\ncccc\n"
+	err := os.WriteFile(filepath.Join(tempDir, "lines.txt"), []byte(content), 0644)
+	require.NoError(t, err)
+
+	resp, err := service.ReadFile(&types.ReadFileRequest{Path: "lines.txt", StartLine: 1, Length: 12})
+	require.NoError(t, err)
+	assert.Equal(t, "aaaa\nbbbb\n", resp.Content)
+	assert.Equal(t, 2, resp.EndLine)
+	assert.True(t, resp.Truncated)
+}
+
+func TestReadFileTotalLines(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+	writeTestFiles(t, tempDir, map[string]string{"lines.txt": "a\nb\nc"})
+
+	// 未读到文件末尾时只在请求时统计总行数 / Without reaching the end of the file the total is only counted on request
+	resp, err := service.ReadFile(&types.ReadFileRequest{Path: "lines.txt", StartLine: 1, EndLine: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 0, resp.TotalLines)
+	resp, err = service.ReadFile(&types.ReadFileRequest{Path: "lines.txt", Offset: 1, Length: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 0, resp.TotalLines)
+	resp, err = service.ReadFile(&types.ReadFileRequest{Path: "lines.txt", Offset: 1, Length: 1, CountLines: true})
+	require.NoError(t, err)
+	assert.Equal(t, 3, resp.TotalLines)
+
+	// 读到文件末尾时顺便得到总行数 / Reading to the end of the file gives the total for free
+	resp, err = service.ReadFile(&types.ReadFileRequest{Path: "lines.txt"})
+	require.NoError(t, err)
+	assert.Equal(t, 3, resp.TotalLines)
+	resp, err = service.ReadFile(&types.ReadFileRequest{Path: "lines.txt", StartLine: 2})
+	require.NoError(t, err)
+	assert.Equal(t, 3, resp.TotalLines)
+	assert.Equal(t, 3, resp.EndLine)
+}
+
+func TestReadFileTailTruncated(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	var sb strings.Builder
+	for i := 1; i <= 100; i++ {
+		fmt.Fprintf(&sb, "log line %03d: %s\n", i, strings.Repeat("x", 50))
+	}
+	writeTestFiles(t, tempDir, map[string]string{"app.log": sb.String()})
+	lineLen := len(sb.String()) / 100
+
+	// 超出字节预算时保留最新的行 / The newest lines are kept when the byte budget runs out
+	resp, err := service.ReadFile(&types.ReadFileRequest{Path: "app.log", TailLines: 10, Length: 200})
+	require.NoError(t, err)
+	assert.True(t, resp.Truncated)
+	assert.Equal(t, 3, strings.Count(resp.Content, "\n"))
+	assert.True(t, strings.HasPrefix(resp.Content, "log line 098:"))
+	assert.True(t, strings.HasSuffix(resp.Content, "x\n"))
+	assert.Equal(t, 98, resp.StartLine)
+	assert.Equal(t, 100, resp.EndLine)
+	assert.Equal(t, 100, resp.TotalLines)
+	assert.Equal(t, int64(97*lineLen), resp.Offset)
+
+	// 最后一行本身超出预算时返回该行的尾部 / The end of the last line is returned when it alone exceeds the budget
+	resp, err = service.ReadFile(&types.ReadFileRequest{Path: "app.log", TailLines: 1, Length: 10})
+	require.NoError(t, err)
+	assert.Equal(t, "xxxxxxxxx\n", resp.Content)
+	assert.True(t, resp.Truncated)
+	assert.Equal(t, 100, resp.EndLine)
+
+	// 超过一个扫描块的文件只在请求时统计行号 / Files larger than one scan block only get line numbers on request
+	writeTestFiles(t, tempDir, map[string]string{"big.log": strings.Repeat("0123456789abcde\n", readBufferSize/8) + "last"})
+	resp, err = service.ReadFile(&types.ReadFileRequest{Path: "big.log", TailLines: 2})
+	require.NoError(t, err)
+	assert.Equal(t, "0123456789abcde\nlast", resp.Content)
+	assert.Zero(t, resp.StartLine)
+	assert.Zero(t, resp.TotalLines)
+	resp, err = service.ReadFile(&types.ReadFileRequest{Path: "big.log", TailLines: 2, CountLines: true})
+	require.NoError(t, err)
+	assert.Equal(t, readBufferSize/8, resp.StartLine)
+	assert.Equal(t, readBufferSize/8+1, resp.EndLine)
+	assert.Equal(t, readBufferSize/8+1, resp.TotalLines)
+	assert.False(t, resp.Truncated)
+}
+
+func TestReadFileByteRange(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	content := strings.Repeat("0123456789", 10)
+	err := os.WriteFile(filepath.Join(tempDir, "data.txt"), []byte(content), 0644)
+	require.NoError(t, err)
+
+	resp, err := service.ReadFile(&types.ReadFileRequest{Path: "data.txt", Offset: 10, Length: 5})
+	require.NoError(t, err)
+	assert.Equal(t, "01234", resp.Content)
+	assert.Equal(t, int64(10), resp.Offset)
+	assert.Equal(t, int64(5), resp.Length)
+	assert.Equal(t, int64(100), resp.Size)
+	assert.True(t, resp.Truncated)
+
+	resp, err = service.ReadFile(&types.ReadFileRequest{Path: "data.txt", Offset: 95})
+	require.NoError(t, err)
+	assert.Equal(t, "56789", resp.Content)
+	assert.False(t, resp.Truncated)
+}
+
+func TestReadFileByteRangeSplitsRune(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	// "中文" 每个字符3字节 / Each character is 3 bytes
+	content := "中文内容"
+	err := os.WriteFile(filepath.Join(tempDir, "utf8.txt"), []byte(content), 0644)
+	require.NoError(t, err)
+
+	resp, err := service.ReadFile(&types.ReadFileRequest{Path: "utf8.txt", Offset: 1, Length: 7})
+	require.NoError(t, err)
+	assert.Equal(t, types.ContentEncodingUTF8, resp.Encoding)
+	assert.Equal(t, "文", resp.Content)
+	assert.Equal(t, int64(3), resp.Offset)
+	assert.Equal(t, int64(3), resp.Length)
+	assert.True(t, resp.Truncated)
+}
+
+func TestReadFileBinary(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	data := []byte{0x89, 'P', 'N', 'G', 0x0d, 0x0a, 0x1a, 0x0a, 0x00, 0x00, 0x00, 0x0d}
+	err := os.WriteFile(filepath.Join(tempDir, "image.png"), data, 0644)
+	require.NoError(t, err)
+
+	resp, err := service.ReadFile(&types.ReadFileRequest{Path: "image.png"})
+	require.NoError(t, err)
+	assert.Equal(t, types.ContentEncodingBase64, resp.Encoding)
+	assert.Equal(t, "image/png", resp.MimeType)
+	assert.Equal(t, 0, resp.TotalLines)
+
+	decoded, err := base64.StdEncoding.DecodeString(resp.Content)
+	require.NoError(t, err)
+	assert.Equal(t, data, decoded)
+
+	// 二进制文件不支持按行读取 / Binary files do not support line ranges
+	_, err = service.ReadFile(&types.ReadFileRequest{Path: "image.png", StartLine: 1})
+	assert.Error(t, err)
+}
+
+func TestReadFileDirectory(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	err := os.MkdirAll(filepath.Join(tempDir, "dir"), 0755)
+	require.NoError(t, err)
+
+	_, err = service.ReadFile(&types.ReadFileRequest{Path: "dir"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), types.ErrIsDirectory)
+}
+
+func TestValidateReadFileRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     *types.ReadFileRequest
+		wantErr bool
+	}{
+		{name: "nil request", req: nil, wantErr: true},
+		{name: "plain path", req: &types.ReadFileRequest{Path: "a.txt"}, wantErr: false},
+		{name: "line range", req: &types.ReadFileRequest{Path: "a.txt", StartLine: 1, EndLine: 10}, wantErr: false},
+		{name: "line range with length", req: &types.ReadFileRequest{Path: "a.txt", StartLine: 1, Length: 100}, wantErr: false},
+		{name: "inverted line range", req: &types.ReadFileRequest{Path: "a.txt", StartLine: 10, EndLine: 2}, wantErr: true},
+		{name: "negative offset", req: &types.ReadFileRequest{Path: "a.txt", Offset: -1}, wantErr: true},
+		{name: "length too large", req: &types.ReadFileRequest{Path: "a.txt", Length: MaxReadSize + 1}, wantErr: true},
+		{name: "lines and tail", req: &types.ReadFileRequest{Path: "a.txt", StartLine: 1, TailLines: 5}, wantErr: true},
+		{name: "tail and offset", req: &types.ReadFileRequest{Path: "a.txt", TailLines: 5, Offset: 10}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateReadFileRequest(tt.req)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,339 @@
+// Copyright 2024 MCP Toolkit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sandbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"mime"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"mcp-toolkit/pkg/transport"
+	"mcp-toolkit/pkg/types"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+)
+
+const (
+	// FileResourceTemplate 沙箱文件资源的URI模板,挂载内的文件使用"file:///名称:路径"
+	// URI template of sandbox file resources; files inside mounts use "file:///name:path"
+	FileResourceTemplate = "file:///{+path}"
+
+	// DefaultResourcePageSize resources/list每页返回的资源数 / Number of resources returned per resources/list page
+	DefaultResourcePageSize = 100
+
+	// ResourceWatchInterval 检查已订阅资源是否变化的间隔 / Interval between checks of subscribed resources for changes
+	ResourceWatchInterval = 2 * time.Second
+)
+
+// resourceSubscription 已订阅的资源及其最近一次观察到的状态 / A subscribed resource and its last observed state
+type resourceSubscription struct {
+	path    string    // 资源对应的绝对路径 / Absolute path of the resource
+	refs    int       // 订阅数 / Number of subscriptions
+	exists  bool      // 文件是否存在 / Whether the file exists
+	size    int64     // 文件大小 / File size
+	modTime time.Time // 修改时间 / Modification time
+}
+
+// observe 读取资源当前状态,返回状态是否与上次不同 / Read the current state of the resource and report whether it differs from the last one
+func (r *resourceSubscription) observe(fsys FileSystem) bool {
+	info, err := fsys.Stat(r.path)
+	exists := err == nil
+	var size int64
+	var modTime time.Time
+	if exists {
+		size, modTime = info.Size(), info.ModTime()
+	}
+	changed := exists != r.exists || size != r.size || !modTime.Equal(r.modTime)
+	r.exists, r.size, r.modTime = exists, size, modTime
+	return changed
+}
+
+// resourcePath 将file:// URI解析为沙箱显示路径 / Resolve a file:// URI into a sandbox display path
+func resourcePath(uri string) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != fileURIScheme || u.Host != "" {
+		return "", errors.New(types.ErrInvalidResourceURI)
+	}
+	p := strings.TrimPrefix(u.Path, "/")
+	if p == "" {
+		return "", errors.New(types.ErrInvalidResourceURI)
+	}
+	return p, nil
+}
+
+// ListResources 分页列出沙箱根目录下的文件资源,跳过隐藏条目、默认排除目录和访问规则隐藏的条目
+// List the file resources under the sandbox root page by page, skipping hidden entries, default excludes and entries hidden by access rules
+func (s *Service) ListResources(params *mcp.ListResourcesParams) (*mcp.ListResourcesResult, error) {
+	var cursor string
+	if params != nil {
+		cursor = params.Cursor
+	}
+	offset, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &mcp.ListResourcesResult{Resources: []*mcp.Resource{}}
+	index := 0
+	err = walkDir(s.fsys, s.sandboxDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// 跳过无法读取的条目 / Skip entries that cannot be read
+			return nil
+		}
+		if path == s.sandboxDir {
+			return nil
+		}
+		name := d.Name()
+		if strings.HasPrefix(name, ".") || (d.IsDir() && slices.Contains(DefaultTreeExcludes, name)) || !s.accessAllowed(path, types.AccessList) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		if index == offset+DefaultResourcePageSize {
+			result.NextCursor = encodeCursor(index)
+			return filepath.SkipAll
+		}
+		index++
+		if index <= offset {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		display := s.displayPath(path)
+		result.Resources = append(result.Resources, &mcp.Resource{
+			URI:      fileResourceURI(display),
+			Name:     display,
+			MIMEType: mime.TypeByExtension(filepath.Ext(name)),
+			Size:     info.Size(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list resources: %w", err)
+	}
+	return result, nil
+}
+
+// ListResourceTemplates 返回沙箱文件的URI模板 / Return the URI template of sandbox files
+func (s *Service) ListResourceTemplates(params *mcp.ListResourceTemplatesParams) (*mcp.ListResourceTemplatesResult, error) {
+	return &mcp.ListResourceTemplatesResult{ResourceTemplates: []*mcp.ResourceTemplate{fileResourceTemplate()}}, nil
+}
+
+// fileResourceTemplate 沙箱文件资源模板 / Resource template of sandbox files
+func fileResourceTemplate() *mcp.ResourceTemplate {
+	return &mcp.ResourceTemplate{
+		URITemplate: FileResourceTemplate,
+		Name:        "sandbox-file",
+		Title:       "Sandbox file",
+		Description: "A file in the sandbox, addressed by its path relative to the sandbox root or as name:path inside a mount / 沙箱中的文件,使用相对沙箱根目录的路径或挂载内的名称:路径",
+	}
+}
+
+// ReadResource 读取文件资源,UTF-8文本作为文本返回,其他内容作为二进制返回
+// Read a file resource; UTF-8 text is returned as text and anything else as a blob
+func (s *Service) ReadResource(params *mcp.ReadResourceParams) (*mcp.ReadResourceResult, error) {
+	if params == nil {
+		return nil, errors.New(types.ErrInvalidResourceURI)
+	}
+	display, err := resourcePath(params.URI)
+	if err != nil {
+		return nil, err
+	}
+	validPath, err := s.validateAccessPath(display, types.AccessRead)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := s.fsys.Stat(validPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, mcp.ResourceNotFoundError(params.URI)
+		}
+		return nil, fmt.Errorf("failed to stat file: %w", err)
+	}
+	if info.IsDir() {
+		return nil, errors.New(types.ErrIsDirectory)
+	}
+	if info.Size() > MaxReadSize {
+		return nil, fmt.Errorf("file too large to read as a resource: %d bytes (max %d)", info.Size(), MaxReadSize)
+	}
+
+	data, err := s.fsys.ReadFile(validPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	contents := &mcp.ResourceContents{
+		URI:      params.URI,
+		MIMEType: detectMimeType(validPath, data[:min(len(data), BinaryDetectSize)]),
+	}
+	if utf8.Valid(data) && !isBinaryContent(data[:min(len(data), BinaryDetectSize)]) {
+		contents.Text = string(data)
+	} else {
+		contents.Blob = data
+	}
+	return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{contents}}, nil
+}
+
+// SubscribeResource 订阅文件资源的变化,文件可以尚不存在 / Subscribe to changes of a file resource; the file need not exist yet
+func (s *Service) SubscribeResource(uri string) error {
+	display, err := resourcePath(uri)
+	if err != nil {
+		return err
+	}
+	validPath, err := s.validateAccessPath(display, types.AccessRead)
+	if err != nil {
+		return err
+	}
+
+	s.resourceMu.Lock()
+	defer s.resourceMu.Unlock()
+	if sub, ok := s.resourceSubs[uri]; ok {
+		sub.refs++
+		return nil
+	}
+	if s.resourceSubs == nil {
+		s.resourceSubs = make(map[string]*resourceSubscription)
+	}
+	sub := &resourceSubscription{path: validPath, refs: 1}
+	sub.observe(s.fsys)
+	s.resourceSubs[uri] = sub
+
+	// 第一个订阅启动监视 / The first subscription starts the watcher
+	if s.resourceStop == nil {
+		s.resourceStop = make(chan struct{})
+		go s.watchResources(s.resourceStop)
+	}
+	return nil
+}
+
+// UnsubscribeResource 取消一次订阅,最后一个订阅结束时停止监视 / Cancel one subscription, stopping the watcher after the last one
+func (s *Service) UnsubscribeResource(uri string) error {
+	s.resourceMu.Lock()
+	defer s.resourceMu.Unlock()
+	sub, ok := s.resourceSubs[uri]
+	if !ok {
+		return nil
+	}
+	if sub.refs--; sub.refs > 0 {
+		return nil
+	}
+	delete(s.resourceSubs, uri)
+	if len(s.resourceSubs) == 0 && s.resourceStop != nil {
+		close(s.resourceStop)
+		s.resourceStop = nil
+	}
+	return nil
+}
+
+// OnResourceUpdated 注册已订阅资源变化时的回调 / Register a callback invoked when a subscribed resource changes
+func (s *Service) OnResourceUpdated(fn func(uri string)) {
+	s.resourceMu.Lock()
+	defer s.resourceMu.Unlock()
+	s.resourceListeners = append(s.resourceListeners, fn)
+}
+
+// watchResources 定期检查已订阅资源直到停止 / Periodically check subscribed resources until stopped
+func (s *Service) watchResources(stop chan struct{}) {
+	ticker := time.NewTicker(ResourceWatchInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			s.checkResources()
+		}
+	}
+}
+
+// checkResources 检查已订阅资源并通知发生变化的资源,回调在锁外调用
+// Check subscribed resources and report the changed ones; callbacks run outside the lock
+func (s *Service) checkResources() {
+	s.resourceMu.Lock()
+	var changed []string
+	for uri, sub := range s.resourceSubs {
+		if sub.observe(s.fsys) {
+			changed = append(changed, uri)
+		}
+	}
+	listeners := slices.Clone(s.resourceListeners)
+	s.resourceMu.Unlock()
+
+	slices.Sort(changed)
+	for _, uri := range changed {
+		s.logger.Debug("subscribed resource changed", zap.String("uri", uri))
+		for _, fn := range listeners {
+			fn(uri)
+		}
+	}
+}
+
+// RegisterResources 注册文件资源模板、动态资源列表和变更通知到MCP服务器
+// 服务器选项需要使用HandleSubscribe和HandleUnsubscribe作为订阅处理器
+// Register the file resource template, the dynamic resource listing and change notifications to the MCP server.
+// The server options must use HandleSubscribe and HandleUnsubscribe as subscription handlers
+func (s *Service) RegisterResources(mcpServer *mcp.Server) {
+	mcpServer.AddResourceTemplate(fileResourceTemplate(), func(_ context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		return s.ReadResource(req.Params)
+	})
+
+	// SDK只列出静态资源,沙箱文件由服务动态列出 / The SDK only lists static resources, so sandbox files are listed by the service
+	mcpServer.AddReceivingMiddleware(func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			if listReq, ok := req.(*mcp.ListResourcesRequest); ok && method == "resources/list" {
+				return s.ListResources(listReq.Params)
+			}
+			return next(ctx, method, req)
+		}
+	})
+
+	s.OnResourceUpdated(func(uri string) {
+		_ = mcpServer.ResourceUpdated(context.Background(), &mcp.ResourceUpdatedNotificationParams{URI: uri})
+	})
+}
+
+// HandleSubscribe 处理resources/subscribe请求 / Handle resources/subscribe requests
+func (s *Service) HandleSubscribe(_ context.Context, req *mcp.SubscribeRequest) error {
+	return s.SubscribeResource(req.Params.URI)
+}
+
+// HandleUnsubscribe 处理resources/unsubscribe请求 / Handle resources/unsubscribe requests
+func (s *Service) HandleUnsubscribe(_ context.Context, req *mcp.UnsubscribeRequest) error {
+	return s.UnsubscribeResource(req.Params.URI)
+}
+
+// RegisterResourcesToRegistry 注册文件资源到资源注册表 / Register file resources to the resource registry
+func (s *Service) RegisterResourcesToRegistry(registry *transport.ResourceRegistry) {
+	registry.SetProvider(s)
+	s.OnResourceUpdated(registry.NotifyUpdated)
+}
@@ -0,0 +1,214 @@
+package sandbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"mcp-toolkit/pkg/types"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListResources(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	files := map[string]string{
+		".env":                 "TOKEN=abc\n",
+		".git/HEAD":            "ref: refs/heads/main\n",
+		"node_modules/x/x.js":  "x\n",
+		"secret/key.txt":       "key\n",
+		"docs/readme.md":       "# readme\n",
+		"src/main.go":          "package main\n",
+		"src/internal/util.go": "package internal\n",
+	}
+	writeTestFiles(t, tempDir, files)
+	require.NoError(t, service.SetAccessConfig(types.AccessConfig{Rules: []types.AccessRule{
+		{Pattern: "secret/**", Operations: []types.AccessOperation{types.AccessList}},
+	}}))
+
+	result, err := service.ListResources(nil)
+	require.NoError(t, err)
+	assert.Empty(t, result.NextCursor)
+	var uris []string
+	for _, resource := range result.Resources {
+		uris = append(uris, resource.URI)
+	}
+	assert.Equal(t, []string{"file:///docs/readme.md", "file:///src/internal/util.go", "file:///src/main.go"}, uris)
+	assert.Equal(t, "src/main.go", result.Resources[2].Name)
+	assert.Equal(t, int64(len("package main\n")), result.Resources[2].Size)
+
+	_, err = service.ListResources(&mcp.ListResourcesParams{Cursor: "bogus"})
+	assert.Error(t, err)
+}
+
+func TestListResourcesPaging(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	files := make(map[string]string)
+	for i := range DefaultResourcePageSize + 5 {
+		files[fmt.Sprintf("file%03d.txt", i)] = "x"
+	}
+	writeTestFiles(t, tempDir, files)
+
+	first, err := service.ListResources(&mcp.ListResourcesParams{})
+	require.NoError(t, err)
+	require.Len(t, first.Resources, DefaultResourcePageSize)
+	require.NotEmpty(t, first.NextCursor)
+
+	second, err := service.ListResources(&mcp.ListResourcesParams{Cursor: first.NextCursor})
+	require.NoError(t, err)
+	require.Len(t, second.Resources, 5)
+	assert.Empty(t, second.NextCursor)
+	assert.Equal(t, "file:///file100.txt", second.Resources[0].URI)
+}
+
+func TestReadResource(t *testing.T) {
+	service, tempDir, dataDir, _ := setupMountService(t)
+	defer cleanupTestService(t, tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{
+		"notes.txt": "hello\n",
+		"image.png": "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR",
+		"dir/a.txt": "a",
+	})
+	writeTestFiles(t, dataDir, map[string]string{"data.json": "{}"})
+
+	result, err := service.ReadResource(&mcp.ReadResourceParams{URI: "file:///notes.txt"})
+	require.NoError(t, err)
+	require.Len(t, result.Contents, 1)
+	assert.Equal(t, "hello\n", result.Contents[0].Text)
+	assert.Equal(t, "text/plain; charset=utf-8", result.Contents[0].MIMEType)
+
+	result, err = service.ReadResource(&mcp.ReadResourceParams{URI: "file:///image.png"})
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", result.Contents[0].MIMEType)
+	assert.Equal(t, []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), result.Contents[0].Blob)
+	assert.Empty(t, result.Contents[0].Text)
+
+	// 挂载内的文件使用"名称:路径" / Files inside mounts use "name:path"
+	result, err = service.ReadResource(&mcp.ReadResourceParams{URI: fileResourceURI("data:data.json")})
+	require.NoError(t, err)
+	assert.Equal(t, "{}", result.Contents[0].Text)
+
+	_, err = service.ReadResource(&mcp.ReadResourceParams{URI: "file:///missing.txt"})
+	var wireErr *jsonrpc.Error
+	require.True(t, errors.As(err, &wireErr))
+	assert.Equal(t, int64(mcp.CodeResourceNotFound), wireErr.Code)
+
+	_, err = service.ReadResource(&mcp.ReadResourceParams{URI: "file:///dir"})
+	assert.ErrorContains(t, err, types.ErrIsDirectory)
+
+	for _, uri := range []string{"http://example.com/notes.txt", "file://host/notes.txt", "file:///", "::"} {
+		_, err = service.ReadResource(&mcp.ReadResourceParams{URI: uri})
+		assert.ErrorContains(t, err, types.ErrInvalidResourceURI, uri)
+	}
+
+	_, err = service.ReadResource(&mcp.ReadResourceParams{URI: "file:///../outside.txt"})
+	assert.ErrorContains(t, err, types.ErrSandboxViolation)
+}
+
+func TestResourceSubscriptions(t *testing.T) {
+	service, memFS := setupMemService(t)
+	var updated []string
+	service.OnResourceUpdated(func(uri string) { updated = append(updated, uri) })
+
+	path := filepath.Join(memSandboxDir, "watched.txt")
+	require.NoError(t, memFS.WriteFile(path, []byte("v1"), 0644))
+
+	require.NoError(t, service.SubscribeResource("file:///watched.txt"))
+	require.NoError(t, service.SubscribeResource("file:///watched.txt"))
+	// 尚不存在的文件也可以订阅 / Files that do not exist yet can be subscribed to
+	require.NoError(t, service.SubscribeResource("file:///later.txt"))
+	assert.Error(t, service.SubscribeResource("file:///../escape.txt"))
+
+	service.checkResources()
+	assert.Empty(t, updated)
+
+	_, err := service.WriteFile(&types.WriteFileRequest{Path: "watched.txt", Content: "version two"})
+	require.NoError(t, err)
+	_, err = service.WriteFile(&types.WriteFileRequest{Path: "later.txt", Content: "new"})
+	require.NoError(t, err)
+	service.checkResources()
+	assert.Equal(t, []string{"file:///later.txt", "file:///watched.txt"}, updated)
+
+	// 删除同样是变化 / Deletion is a change as well
+	updated = nil
+	require.NoError(t, memFS.Remove(path))
+	service.checkResources()
+	assert.Equal(t, []string{"file:///watched.txt"}, updated)
+
+	// 最后一个订阅结束后停止监视 / The watcher stops after the last subscription ends
+	require.NoError(t, service.UnsubscribeResource("file:///watched.txt"))
+	assert.NotNil(t, service.resourceStop)
+	require.NoError(t, service.UnsubscribeResource("file:///watched.txt"))
+	require.NoError(t, service.UnsubscribeResource("file:///later.txt"))
+	assert.Nil(t, service.resourceStop)
+	assert.Empty(t, service.resourceSubs)
+}
+
+func TestRegisterResources(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+	writeTestFiles(t, tempDir, map[string]string{"a.txt": "alpha", "sub/b.txt": "beta"})
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "1.0.0"}, &mcp.ServerOptions{
+		SubscribeHandler:   service.HandleSubscribe,
+		UnsubscribeHandler: service.HandleUnsubscribe,
+	})
+	service.RegisterResources(server)
+
+	updated := make(chan string, 1)
+	client := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "1.0.0"}, &mcp.ClientOptions{
+		ResourceUpdatedHandler: func(_ context.Context, req *mcp.ResourceUpdatedNotificationRequest) {
+			updated <- req.Params.URI
+		},
+	})
+
+	ctx := context.Background()
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	defer func() { _ = serverSession.Close() }()
+	session, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	defer func() { _ = session.Close() }()
+
+	caps := session.InitializeResult().Capabilities
+	require.NotNil(t, caps.Resources)
+	assert.True(t, caps.Resources.Subscribe)
+
+	list, err := session.ListResources(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, list.Resources, 2)
+	assert.Equal(t, "file:///sub/b.txt", list.Resources[1].URI)
+
+	templates, err := session.ListResourceTemplates(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, templates.ResourceTemplates, 1)
+	assert.Equal(t, FileResourceTemplate, templates.ResourceTemplates[0].URITemplate)
+
+	read, err := session.ReadResource(ctx, &mcp.ReadResourceParams{URI: "file:///sub/b.txt"})
+	require.NoError(t, err)
+	assert.Equal(t, "beta", read.Contents[0].Text)
+
+	require.NoError(t, session.Subscribe(ctx, &mcp.SubscribeParams{URI: "file:///a.txt"}))
+	_, err = service.WriteFile(&types.WriteFileRequest{Path: "a.txt", Content: "changed"})
+	require.NoError(t, err)
+	service.checkResources()
+	select {
+	case uri := <-updated:
+		assert.Equal(t, "file:///a.txt", uri)
+	case <-time.After(5 * time.Second):
+		t.Fatal("resource update notification not received")
+	}
+	require.NoError(t, session.Unsubscribe(ctx, &mcp.UnsubscribeParams{URI: "file:///a.txt"}))
+}
@@ -0,0 +1,109 @@
+// Copyright 2024 MCP Toolkit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sandbox
+
+import (
+	"cmp"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+	"time"
+
+	"mcp-toolkit/pkg/types"
+)
+
+// fileFilter 文件条目的类型、大小和时间过滤条件 / Type, size and time filters of file entries
+type fileFilter struct {
+	entryType      string
+	minSize        int64
+	maxSize        int64
+	modifiedAfter  time.Time
+	modifiedBefore time.Time
+}
+
+// newFileFilter 根据搜索请求创建过滤条件 / Create filters from a search request
+func newFileFilter(req *types.SearchRequest) (*fileFilter, error) {
+	f := &fileFilter{
+		entryType: req.Type,
+		minSize:   req.MinSize,
+		maxSize:   req.MaxSize,
+	}
+
+	var err error
+	if req.ModifiedAfter != "" {
+		if f.modifiedAfter, err = time.Parse(time.RFC3339, req.ModifiedAfter); err != nil {
+			return nil, fmt.Errorf("invalid modified_after, expected RFC3339 time: %w", err)
+		}
+	}
+	if req.ModifiedBefore != "" {
+		if f.modifiedBefore, err = time.Parse(time.RFC3339, req.ModifiedBefore); err != nil {
+			return nil, fmt.Errorf("invalid modified_before, expected RFC3339 time: %w", err)
+		}
+	}
+	return f, nil
+}
+
+// match 检查条目是否满足过滤条件,大小条件只匹配文件 / Check whether an entry passes the filters; size filters only match files
+func (f *fileFilter) match(info fs.FileInfo) bool {
+	switch f.entryType {
+	case types.EntryTypeFile:
+		if info.IsDir() {
+			return false
+		}
+	case types.EntryTypeDirectory:
+		if !info.IsDir() {
+			return false
+		}
+	}
+
+	if f.minSize > 0 || f.maxSize > 0 {
+		if info.IsDir() || info.Size() < f.minSize || (f.maxSize > 0 && info.Size() > f.maxSize) {
+			return false
+		}
+	}
+
+	if !f.modifiedAfter.IsZero() && info.ModTime().Before(f.modifiedAfter) {
+		return false
+	}
+	if !f.modifiedBefore.IsZero() && !info.ModTime().Before(f.modifiedBefore) {
+		return false
+	}
+	return true
+}
+
+// sortFileInfos 按指定字段和方向排序,相同时按路径排序 / Sort by the given field and order, falling back to the path
+func sortFileInfos(files []types.FileInfo, sortBy, order string) {
+	desc := order == types.SortOrderDesc
+	sort.SliceStable(files, func(i, j int) bool {
+		a, b := files[i], files[j]
+		var result int
+		switch sortBy {
+		case types.SortByName:
+			result = strings.Compare(a.Name, b.Name)
+		case types.SortBySize:
+			result = cmp.Compare(a.Size, b.Size)
+		case types.SortByModTime:
+			result = a.ModTime.Compare(b.ModTime)
+		}
+		if result == 0 {
+			result = strings.Compare(a.Path, b.Path)
+		}
+		if desc {
+			return result > 0
+		}
+		return result < 0
+	})
+}
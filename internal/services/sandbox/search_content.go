@@ -0,0 +1,236 @@
+// Copyright 2024 MCP Toolkit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sandbox
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"mcp-toolkit/pkg/types"
+)
+
+// SearchContent 在文件内容中搜索匹配的行 / Search file contents for matching lines
+func (s *Service) SearchContent(req *types.SearchContentRequest) (*types.SearchContentResponse, error) {
+	// 参数验证 / Parameter validation
+	if err := validateSearchContentRequest(req); err != nil {
+		return nil, err
+	}
+
+	root := req.Path
+	if root == "" {
+		root = "."
+	}
+	validPath, err := s.validateAccessPath(root, types.AccessList)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = s.fsys.Stat(validPath); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, errors.New(types.ErrFileNotFound)
+		}
+		return nil, fmt.Errorf("failed to stat path: %w", err)
+	}
+
+	re, err := compileContentPattern(req)
+	if err != nil {
+		return nil, err
+	}
+
+	maxResults := req.MaxResults
+	if maxResults <= 0 {
+		maxResults = DefaultSearchResults
+	}
+
+	var ignore *ignoreMatcher
+	if !req.NoIgnore {
+		ignore = newIgnoreMatcher(s.fsys, s.mountFor(validPath).dir, validPath)
+	}
+
+	resp := &types.SearchContentResponse{Matches: []types.ContentMatch{}}
+
+	err = walkDir(s.fsys, validPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // 跳过错误 / Skip errors
+		}
+
+		relPath := s.sandboxRelPath(path)
+		searchRel := filepath.ToSlash(strings.TrimPrefix(strings.TrimPrefix(path, validPath), string(filepath.Separator)))
+
+		if d.IsDir() {
+			if path != validPath {
+				if d.Name() == gitDirName || matchAnyGlob(req.Exclude, searchRel) ||
+					(ignore != nil && ignore.match(relPath, true)) || !s.accessAllowed(path, types.AccessList) {
+					return filepath.SkipDir
+				}
+			}
+			if ignore != nil {
+				ignore.loadFile(path, relPath)
+			}
+			return nil
+		}
+
+		if !d.Type().IsRegular() || !s.accessAllowed(path, types.AccessRead) {
+			return nil
+		}
+		if path != validPath {
+			if (ignore != nil && ignore.match(relPath, false)) || matchAnyGlob(req.Exclude, searchRel) ||
+				(len(req.Include) > 0 && !matchAnyGlob(req.Include, searchRel)) {
+				return nil
+			}
+		}
+
+		if info, err := d.Info(); err != nil || info.Size() > MaxFileSize {
+			resp.SkippedFiles++
+			return nil
+		}
+
+		limit := maxResults - len(resp.Matches)
+		if req.MaxMatchesPerFile > 0 {
+			limit = min(limit, req.MaxMatchesPerFile)
+		}
+
+		matches, limited, binary, err := searchFileContent(s.fsys, path, relPath, re, req.ContextLines, limit)
+		if err != nil || binary {
+			resp.SkippedFiles++
+			return nil
+		}
+
+		resp.FilesSearched++
+		if len(matches) > 0 {
+			resp.FilesMatched++
+			resp.Matches = append(resp.Matches, matches...)
+		}
+		if limited {
+			resp.Truncated = true
+			if len(resp.Matches) >= maxResults {
+				return fs.SkipAll
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search content: %w", err)
+	}
+
+	resp.TotalMatches = len(resp.Matches)
+	return resp, nil
+}
+
+// compileContentPattern 编译搜索模式 / Compile the search pattern
+func compileContentPattern(req *types.SearchContentRequest) (*regexp.Regexp, error) {
+	pattern := req.Pattern
+	if req.Literal {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	if req.IgnoreCase {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regular expression: %w", err)
+	}
+	return re, nil
+}
+
+// searchFileContent 搜索单个文件,返回匹配、是否因上限省略了更多匹配以及是否为二进制文件
+// Search a single file, returning matches, whether further matches were omitted by the limit, and whether the file is binary
+func searchFileContent(fsys FileSystem, absPath, relPath string, re *regexp.Regexp, contextLines, limit int) ([]types.ContentMatch, bool, bool, error) {
+	file, err := fsys.Open(absPath)
+	if err != nil {
+		return nil, false, false, err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReaderSize(file, readBufferSize)
+	sample, _ := reader.Peek(BinaryDetectSize)
+	if isBinaryContent(sample) {
+		return nil, false, true, nil
+	}
+
+	var (
+		matches []types.ContentMatch
+		before  []string
+		pending []int // 等待后续上下文的匹配 / Matches still collecting trailing context
+		lineNo  int
+		limited bool
+	)
+
+	for !limited || len(pending) > 0 {
+		line, err := reader.ReadString('\n')
+		if len(line) > 0 {
+			lineNo++
+			// 去掉换行符后再匹配,使$能匹配行尾 / Match without the line break so that $ matches at the end of the line
+			content := strings.TrimRight(line, "\r\n")
+			text := clipLine(content)
+
+			kept := pending[:0]
+			for _, idx := range pending {
+				matches[idx].After = append(matches[idx].After, text)
+				if len(matches[idx].After) < contextLines {
+					kept = append(kept, idx)
+				}
+			}
+			pending = kept
+
+			if loc := re.FindStringIndex(content); loc != nil && !limited {
+				if len(matches) >= limit {
+					limited = true
+				} else {
+					matches = append(matches, types.ContentMatch{
+						Path:   relPath,
+						Line:   lineNo,
+						Column: utf8.RuneCountInString(content[:loc[0]]) + 1,
+						Text:   text,
+						Before: append([]string(nil), before...),
+					})
+					if contextLines > 0 {
+						pending = append(pending, len(matches)-1)
+					}
+				}
+			}
+
+			if contextLines > 0 {
+				before = append(before, text)
+				if len(before) > contextLines {
+					before = before[1:]
+				}
+			}
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, false, false, err
+		}
+	}
+
+	return matches, limited, false, nil
+}
+
+// clipLine 截断过长的行 / Clip overly long lines
+func clipLine(line string) string {
+	if len(line) <= MaxMatchLineLength {
+		return line
+	}
+	clipped, _ := trimPartialRunes([]byte(line[:MaxMatchLineLength]), false, true)
+	return string(clipped) + "..."
+}
@@ -0,0 +1,191 @@
+package sandbox
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"mcp-toolkit/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestFiles 在沙箱中创建测试文件 / Create test files in the sandbox
+func writeTestFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+}
+
+func TestSearchContent(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{
+		"main.go":          "package main\n\nfunc main() {\n\t// TODO: start\n}\n",
+		"pkg/util.go":      "package pkg\n\n// todo lower case\nfunc Util() {}\n",
+		"pkg/util_test.go": "package pkg\n// TODO: test\n",
+		"notes.md":         "TODO in markdown\n",
+		"image.bin":        "TODO\x00\x01\x02",
+		".gitignore":       "ignored/\n*.md\n",
+		"ignored/a.go":     "// TODO ignored\n",
+		".git/config":      "TODO in git\n",
+	})
+
+	resp, err := service.SearchContent(&types.SearchContentRequest{Pattern: "TODO"})
+	require.NoError(t, err)
+	paths := make([]string, 0, len(resp.Matches))
+	for _, m := range resp.Matches {
+		paths = append(paths, m.Path)
+	}
+	assert.ElementsMatch(t, []string{"main.go", "pkg/util_test.go"}, paths)
+	assert.Equal(t, 2, resp.TotalMatches)
+	assert.Equal(t, 2, resp.FilesMatched)
+	assert.Equal(t, 1, resp.SkippedFiles)
+	assert.False(t, resp.Truncated)
+
+	for _, m := range resp.Matches {
+		if m.Path == "main.go" {
+			assert.Equal(t, 4, m.Line)
+			assert.Equal(t, 5, m.Column)
+			assert.Equal(t, "\t// TODO: start", m.Text)
+		}
+	}
+
+	// 忽略大小写、包含过滤和no_ignore / Ignore case, include filter and no_ignore
+	resp, err = service.SearchContent(&types.SearchContentRequest{Pattern: "todo", IgnoreCase: true, Include: []string{"*.go"}, Exclude: []string{"*_test.go"}, NoIgnore: true})
+	require.NoError(t, err)
+	paths = paths[:0]
+	for _, m := range resp.Matches {
+		paths = append(paths, m.Path)
+	}
+	assert.ElementsMatch(t, []string{"main.go", "pkg/util.go", "ignored/a.go"}, paths)
+
+	// 子目录搜索 / Search a subdirectory
+	resp, err = service.SearchContent(&types.SearchContentRequest{Path: "pkg", Pattern: "func Util()", Literal: true})
+	require.NoError(t, err)
+	require.Len(t, resp.Matches, 1)
+	assert.Equal(t, "pkg/util.go", resp.Matches[0].Path)
+}
+
+func TestSearchContentContextAndLimits(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	var sb strings.Builder
+	for i := 1; i <= 10; i++ {
+		if i%3 == 0 {
+			sb.WriteString("match\n")
+		} else {
+			sb.WriteString("line\n")
+		}
+	}
+	writeTestFiles(t, tempDir, map[string]string{"a.txt": sb.String(), "b.txt": "match\n"})
+
+	resp, err := service.SearchContent(&types.SearchContentRequest{Path: "a.txt", Pattern: "match", ContextLines: 1})
+	require.NoError(t, err)
+	require.Len(t, resp.Matches, 3)
+	assert.Equal(t, 3, resp.Matches[0].Line)
+	assert.Equal(t, []string{"line"}, resp.Matches[0].Before)
+	assert.Equal(t, []string{"line"}, resp.Matches[0].After)
+
+	resp, err = service.SearchContent(&types.SearchContentRequest{Pattern: "match", MaxResults: 2})
+	require.NoError(t, err)
+	assert.Len(t, resp.Matches, 2)
+	assert.True(t, resp.Truncated)
+
+	resp, err = service.SearchContent(&types.SearchContentRequest{Pattern: "match", MaxMatchesPerFile: 1})
+	require.NoError(t, err)
+	assert.Len(t, resp.Matches, 2)
+	assert.True(t, resp.Truncated)
+
+	resp, err = service.SearchContent(&types.SearchContentRequest{Pattern: "match", MaxResults: 4})
+	require.NoError(t, err)
+	assert.Len(t, resp.Matches, 4)
+	assert.False(t, resp.Truncated)
+}
+
+func TestSearchContentLineAnchors(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+	writeTestFiles(t, tempDir, map[string]string{
+		"lf.go":   "func a() {\n\treturn\n}\n",
+		"crlf.go": "func b() {\r\n\treturn \r\n}",
+	})
+
+	// $匹配行尾,不受LF或CRLF换行符影响 / $ matches at the end of the line regardless of LF or CRLF line breaks
+	tests := []struct {
+		pattern string
+		matches []string
+	}{
+		{`\{$`, []string{"crlf.go:1", "lf.go:1"}},
+		{`^}$`, []string{"crlf.go:3", "lf.go:3"}},
+		{`return$`, []string{"lf.go:2"}},
+		{`\s+$`, []string{"crlf.go:2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			resp, err := service.SearchContent(&types.SearchContentRequest{Pattern: tt.pattern})
+			require.NoError(t, err)
+			found := make([]string, 0, len(resp.Matches))
+			for _, m := range resp.Matches {
+				found = append(found, fmt.Sprintf("%s:%d", m.Path, m.Line))
+			}
+			assert.ElementsMatch(t, tt.matches, found)
+		})
+	}
+
+	resp, err := service.SearchContent(&types.SearchContentRequest{Path: "crlf.go", Pattern: `\s+$`})
+	require.NoError(t, err)
+	require.Len(t, resp.Matches, 1)
+	assert.Equal(t, 8, resp.Matches[0].Column)
+	assert.Equal(t, "\treturn ", resp.Matches[0].Text)
+}
+
+func TestSearchContentErrors(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	_, err := service.SearchContent(&types.SearchContentRequest{Pattern: "("})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid regular expression")
+
+	_, err = service.SearchContent(&types.SearchContentRequest{Path: "missing", Pattern: "a"})
+	require.Error(t, err)
+	assert.Equal(t, types.ErrFileNotFound, err.Error())
+
+	_, err = service.SearchContent(&types.SearchContentRequest{Path: "../", Pattern: "a"})
+	assert.Error(t, err)
+}
+
+func TestValidateSearchContentRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     *types.SearchContentRequest
+		wantErr bool
+	}{
+		{name: "nil request", req: nil, wantErr: true},
+		{name: "empty pattern", req: &types.SearchContentRequest{}, wantErr: true},
+		{name: "valid", req: &types.SearchContentRequest{Pattern: "a", ContextLines: 2}, wantErr: false},
+		{name: "too much context", req: &types.SearchContentRequest{Pattern: "a", ContextLines: MaxContextLines + 1}, wantErr: true},
+		{name: "too many results", req: &types.SearchContentRequest{Pattern: "a", MaxResults: MaxSearchResults + 1}, wantErr: true},
+		{name: "invalid include", req: &types.SearchContentRequest{Pattern: "a", Include: []string{"[a"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSearchContentRequest(tt.req)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
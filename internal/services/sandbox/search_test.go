@@ -0,0 +1,139 @@
+package sandbox
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"mcp-toolkit/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// searchPaths 提取搜索结果的斜杠路径 / Extract slash-separated paths of search results
+func searchPaths(resp *types.SearchResponse) []string {
+	paths := make([]string, 0, len(resp.Files))
+	for _, file := range resp.Files {
+		paths = append(paths, filepath.ToSlash(file.Path))
+	}
+	return paths
+}
+
+func TestSearchDoublestar(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{
+		"main.go":                    "",
+		"src/app.ts":                 "",
+		"src/app.test.ts":            "",
+		"src/lib/util.test.ts":       "",
+		"node_modules/pkg/a.ts":      "",
+		"node_modules/pkg/a.test.ts": "",
+	})
+
+	resp, err := service.Search(&types.SearchRequest{Path: ".", Pattern: "src/**/*.test.ts"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"src/app.test.ts", "src/lib/util.test.ts"}, searchPaths(resp))
+
+	resp, err = service.Search(&types.SearchRequest{Path: ".", Pattern: "*.ts", Exclude: []string{"node_modules"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"src/app.test.ts", "src/app.ts", "src/lib/util.test.ts"}, searchPaths(resp))
+
+	resp, err = service.Search(&types.SearchRequest{Path: "src", Include: []string{"*.go", "lib/*"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"src/lib/util.test.ts"}, searchPaths(resp))
+}
+
+func TestSearchFilters(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{
+		"small.txt":         "a",
+		"medium.txt":        strings.Repeat("a", 100),
+		"large.txt":         strings.Repeat("a", 1000),
+		"dir/deep/file.txt": "abc",
+	})
+	old := time.Now().Add(-48 * time.Hour)
+	require.NoError(t, os.Chtimes(filepath.Join(tempDir, "small.txt"), old, old))
+
+	resp, err := service.Search(&types.SearchRequest{Path: ".", Pattern: "*", Type: types.EntryTypeDirectory})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dir", "dir/deep"}, searchPaths(resp))
+
+	resp, err = service.Search(&types.SearchRequest{Path: ".", Pattern: "*.txt", MinSize: 50, MaxSize: 500})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"medium.txt"}, searchPaths(resp))
+
+	resp, err = service.Search(&types.SearchRequest{Path: ".", Pattern: "*.txt", ModifiedBefore: time.Now().Add(-24 * time.Hour).Format(time.RFC3339)})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"small.txt"}, searchPaths(resp))
+
+	resp, err = service.Search(&types.SearchRequest{Path: ".", Pattern: "*", MaxDepth: 2})
+	require.NoError(t, err)
+	assert.NotContains(t, searchPaths(resp), "dir/deep/file.txt")
+	assert.Contains(t, searchPaths(resp), "dir/deep")
+}
+
+func TestSearchSortAndLimit(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{
+		"a.txt": strings.Repeat("a", 30),
+		"b.txt": strings.Repeat("a", 10),
+		"c.txt": strings.Repeat("a", 20),
+	})
+
+	resp, err := service.Search(&types.SearchRequest{Path: ".", Pattern: "*.txt", SortBy: types.SortBySize, SortOrder: types.SortOrderDesc, MaxResults: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a.txt", "c.txt"}, searchPaths(resp))
+	assert.Equal(t, 3, resp.TotalMatches)
+	assert.True(t, resp.Truncated)
+
+	resp, err = service.Search(&types.SearchRequest{Path: ".", Pattern: "*.txt", SortBy: types.SortByName, SortOrder: types.SortOrderDesc})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c.txt", "b.txt", "a.txt"}, searchPaths(resp))
+	assert.False(t, resp.Truncated)
+}
+
+func TestValidateSearchRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     *types.SearchRequest
+		wantErr bool
+	}{
+		{name: "nil request", req: nil, wantErr: true},
+		{name: "no pattern", req: &types.SearchRequest{Path: "."}, wantErr: true},
+		{name: "include only", req: &types.SearchRequest{Path: ".", Include: []string{"*.go"}}, wantErr: false},
+		{name: "invalid pattern", req: &types.SearchRequest{Path: ".", Pattern: "[a"}, wantErr: true},
+		{name: "invalid exclude", req: &types.SearchRequest{Path: ".", Pattern: "*", Exclude: []string{"[a"}}, wantErr: true},
+		{name: "invalid type", req: &types.SearchRequest{Path: ".", Pattern: "*", Type: "link"}, wantErr: true},
+		{name: "inverted size range", req: &types.SearchRequest{Path: ".", Pattern: "*", MinSize: 10, MaxSize: 5}, wantErr: true},
+		{name: "invalid sort", req: &types.SearchRequest{Path: ".", Pattern: "*", SortBy: "color"}, wantErr: true},
+		{name: "too many results", req: &types.SearchRequest{Path: ".", Pattern: "*", MaxResults: MaxFileSearchResults + 1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSearchRequest(tt.req)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSearchInvalidTime(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	_, err := service.Search(&types.SearchRequest{Path: ".", Pattern: "*", ModifiedAfter: "yesterday"})
+	assert.Error(t, err)
+}
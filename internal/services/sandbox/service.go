@@ -21,33 +21,76 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"time"
 
 	"mcp-toolkit/pkg/types"
 
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
 // Service 文件系统服务 / Filesystem service
 type Service struct {
-	sandboxDir         string                        // 沙箱目录 / Sandbox directory
-	logger             *zap.Logger                   // 日志记录器 / Logger
-	currentWorkDir     string                        // 当前工作目录(相对于沙箱根目录) / Current working directory (relative to sandbox root)
-	blacklistCommands  []string                      // 黑名单命令列表 / Blacklist commands
-	blacklistDirs      []string                      // 黑名单目录列表 / Blacklist directories
-	mu                 sync.RWMutex                  // 读写锁,保护黑名单和工作目录 / RWMutex to protect blacklist and working directory
-	commandHistory     []*types.CommandHistoryEntry  // 命令执行历史 / Command execution history
-	commandTasks       map[string]*types.CommandTask // 异步命令任务 / Async command tasks
-	taskMu             sync.RWMutex                  // 任务锁 / Task mutex
-	permissionLevel    types.CommandPermissionLevel  // 当前权限级别 / Current permission level
-	defaultEnvironment map[string]string             // 默认环境变量 / Default environment variables
-	auditLogger        *zap.Logger                   // 审计日志记录器 / Audit logger
+	sandboxDir         string                           // 沙箱目录 / Sandbox directory
+	logger             *zap.Logger                      // 日志记录器 / Logger
+	currentWorkDir     string                           // 当前工作目录(相对于沙箱根目录) / Current working directory (relative to sandbox root)
+	blacklistCommands  []string                         // 黑名单命令列表 / Blacklist commands
+	blacklistDirs      []string                         // 黑名单目录列表 / Blacklist directories
+	mu                 sync.RWMutex                     // 读写锁,保护黑名单和工作目录 / RWMutex to protect blacklist and working directory
+	commandHistory     []*types.CommandHistoryEntry     // 命令执行历史 / Command execution history
+	commandTasks       map[string]*types.CommandTask    // 异步命令任务 / Async command tasks
+	taskMu             sync.RWMutex                     // 任务锁 / Task mutex
+	permissionLevel    types.CommandPermissionLevel     // 当前权限级别 / Current permission level
+	defaultEnvironment map[string]string                // 默认环境变量 / Default environment variables
+	auditLogger        *zap.Logger                      // 审计日志记录器 / Audit logger
+	realSandboxDir     string                           // 解析符号链接后的沙箱目录 / Sandbox directory with symlinks resolved
+	symlinkPolicy      types.SymlinkPolicy              // 符号链接策略 / Symlink policy
+	trashConfig        types.TrashConfig                // 回收站配置 / Trash configuration
+	trashMu            sync.Mutex                       // 回收站锁 / Trash mutex
+	sessionID          string                           // 服务会话ID,记录在回收站元数据中 / Server session ID recorded in trash metadata
+	snapshotMu         sync.Mutex                       // 快照锁 / Snapshot mutex
+	quotaConfig        types.QuotaConfig                // 磁盘配额配置 / Disk quota configuration
+	quotaUsage         types.QuotaUsage                 // 缓存的配额用量 / Cached quota usage
+	quotaMeasured      time.Time                        // 缓存的用量最近一次完整统计的时间,零值表示无缓存 / When the cached usage was last measured in full, zero when there is no cache
+	quotaMu            sync.Mutex                       // 配额配置和用量缓存锁 / Quota configuration and usage cache mutex
+	locks              *pathLocker                      // 按路径的读写锁,使前置条件检查与替换不可分割 / Per-path read/write locks making precondition checks and replacements indivisible
+	mounts             map[string]*mount                // 命名挂载 / Named mounts
+	accessRules        []types.AccessRule               // 路径访问控制规则 / Path access control rules
+	aclMu              sync.RWMutex                     // 访问控制规则锁 / Access rules mutex
+	fsys               FileSystem                       // 文件系统后端 / Filesystem backend
+	resourceSubs       map[string]*resourceSubscription // 已订阅的资源 / Subscribed resources
+	resourceListeners  []func(uri string)               // 资源变化回调 / Resource change callbacks
+	resourceStop       chan struct{}                    // 停止资源监视 / Stops the resource watcher
+	resourceMu         sync.Mutex                       // 资源订阅锁 / Resource subscription mutex
+	uploads            map[string]*uploadSession        // 进行中的分块上传 / Chunked uploads in progress
+	uploadMu           sync.Mutex                       // 上传会话表锁 / Upload session table mutex
+	leases             map[string]*pathLease            // lock_path授予的咨询性租约 / Advisory leases granted by lock_path
+	leaseChanged       chan struct{}                    // 释放租约时关闭,唤醒等待者 / Closed when a lease is released to wake waiters
+	leaseMu            sync.Mutex                       // 租约表锁 / Lease table mutex
+}
+
+// serviceOptions 服务创建选项集合 / Collected service creation options
+type serviceOptions struct {
+	fsys FileSystem // 文件系统后端 / Filesystem backend
+}
+
+// Option 服务创建选项 / Service creation option
+type Option func(*serviceOptions)
+
+// WithFileSystem 指定文件系统后端,默认为宿主文件系统 / Select the filesystem backend, the host filesystem by default
+func WithFileSystem(fsys FileSystem) Option {
+	return func(o *serviceOptions) {
+		if fsys != nil {
+			o.fsys = fsys
+		}
+	}
 }
 
 // NewService 创建文件系统服务实例 / Create filesystem service instance
-func NewService(sandboxDir string, logger *zap.Logger) (*Service, error) {
+func NewService(sandboxDir string, logger *zap.Logger, opts ...Option) (*Service, error) {
 	// 参数验证 / Parameter validation
 	if sandboxDir == "" {
 		return nil, errors.New("sandbox directory cannot be empty")
@@ -56,6 +99,11 @@ func NewService(sandboxDir string, logger *zap.Logger) (*Service, error) {
 		return nil, errors.New("logger cannot be nil")
 	}
 
+	options := serviceOptions{fsys: NewOSFileSystem()}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	// 确保沙箱目录存在 / Ensure sandbox directory exists
 	absPath, err := filepath.Abs(sandboxDir)
 	if err != nil {
@@ -63,10 +111,16 @@ func NewService(sandboxDir string, logger *zap.Logger) (*Service, error) {
 	}
 
 	// 创建沙箱目录(如果不存在) / Create sandbox directory if not exists
-	if err = os.MkdirAll(absPath, DefaultDirPerm); err != nil {
+	if err = options.fsys.MkdirAll(absPath, DefaultDirPerm); err != nil {
 		return nil, fmt.Errorf("failed to create sandbox directory: %w", err)
 	}
 
+	// 沙箱目录自身可能位于符号链接下(如macOS的/tmp) / The sandbox itself may live under a symlink (e.g. /tmp on macOS)
+	realSandboxDir, err := options.fsys.EvalSymlinks(absPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve sandbox directory: %w", err)
+	}
+
 	// 初始化黑名单 / Initialize blacklist
 	blacklistCommands := make([]string, len(DefaultBlacklistCommands))
 	copy(blacklistCommands, DefaultBlacklistCommands)
@@ -88,10 +142,16 @@ func NewService(sandboxDir string, logger *zap.Logger) (*Service, error) {
 		permissionLevel:    types.PermissionLevelStandard, // 默认标准权限 / Default standard permission
 		defaultEnvironment: make(map[string]string),
 		auditLogger:        auditLogger,
+		realSandboxDir:     realSandboxDir,
+		sessionID:          uuid.NewString(),
+		symlinkPolicy:      types.SymlinkPolicyFollow,
+		fsys:               options.fsys,
+		locks:              newPathLocker(),
 	}, nil
 }
 
-// validatePath 验证路径是否在沙箱目录内 / Validate if path is within sandbox directory
+// validatePath 验证路径是否在沙箱目录或其挂载内 / Validate if path is within sandbox directory or its mount
+// 路径可以使用"名称:相对路径"指定挂载,无前缀时相对沙箱根目录 / A path may select a mount as "name:relative/path"; unprefixed paths are relative to the sandbox root
 func (s *Service) validatePath(path string) (string, error) {
 	path = strings.TrimSpace(path)
 
@@ -100,8 +160,10 @@ func (s *Service) validatePath(path string) (string, error) {
 		return "", errors.New(types.ErrInvalidPath)
 	}
 
+	root, rootRel := s.splitMountPath(path)
+
 	// 清理路径 / Clean path
-	cleanedPath := filepath.Clean(path)
+	cleanedPath := filepath.Clean(rootRel)
 
 	// 检查路径遍历攻击 / Check for path traversal attacks
 	if strings.Contains(cleanedPath, "..") {
@@ -111,12 +173,12 @@ func (s *Service) validatePath(path string) (string, error) {
 	}
 
 	// 获取绝对路径 / Get absolute path
-	absPath := filepath.Join(s.sandboxDir, cleanedPath)
+	absPath := filepath.Join(root.dir, cleanedPath)
 	finalPath := filepath.Clean(absPath)
 
-	// 确保路径以沙箱目录开头 / Ensure path starts with sandbox directory
-	// 使用filepath.Rel来确保路径在沙箱内 / Use filepath.Rel to ensure path is within sandbox
-	relPath, err := filepath.Rel(s.sandboxDir, finalPath)
+	// 确保路径以挂载目录开头 / Ensure path starts with mount directory
+	// 使用filepath.Rel来确保路径在挂载内 / Use filepath.Rel to ensure path is within the mount
+	relPath, err := filepath.Rel(root.dir, finalPath)
 	if err != nil {
 		s.logger.Warn("failed to get relative path",
 			zap.String("requested_path", path),
@@ -129,10 +191,15 @@ func (s *Service) validatePath(path string) (string, error) {
 		s.logger.Warn("sandbox violation attempt",
 			zap.String("requested_path", path),
 			zap.String("relative_path", relPath),
-			zap.String("sandbox_dir", s.sandboxDir))
+			zap.String("mount_dir", root.dir))
 		return "", errors.New(types.ErrSandboxViolation)
 	}
 
+	// 解析符号链接,防止通过链接逃逸沙箱 / Resolve symlinks to prevent escaping the sandbox through links
+	if err = s.checkSymlinks(path, finalPath, root); err != nil {
+		return "", err
+	}
+
 	return finalPath, nil
 }
 
@@ -143,19 +210,24 @@ func (s *Service) CreateFile(req *types.CreateFileRequest) (*types.OperationResp
 		return nil, err
 	}
 
-	validPath, err := s.validatePath(req.Path)
+	validPath, err := s.validateWritePath(req.Path)
 	if err != nil {
 		return nil, err
 	}
+	defer s.locks.lock(writeLock(validPath))()
+
+	if err = s.checkWriteQuota(validPath, int64(len(req.Content))); err != nil {
+		return nil, err
+	}
 
 	// 确保父目录存在 / Ensure parent directory exists
 	dir := filepath.Dir(validPath)
-	if err = os.MkdirAll(dir, DefaultDirPerm); err != nil {
+	if err = s.fsys.MkdirAll(dir, DefaultDirPerm); err != nil {
 		return nil, fmt.Errorf("failed to create parent directory: %w", err)
 	}
 
 	// 创建文件 / Create file
-	file, err := os.Create(validPath)
+	file, err := s.fsys.OpenFile(validPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0666)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create file: %w", err)
 	}
@@ -182,12 +254,13 @@ func (s *Service) CreateDir(req *types.CreateDirRequest) (*types.OperationRespon
 		return nil, err
 	}
 
-	validPath, err := s.validatePath(req.Path)
+	validPath, err := s.validateWritePath(req.Path)
 	if err != nil {
 		return nil, err
 	}
+	defer s.locks.lock(writeLock(validPath))()
 
-	if err = os.MkdirAll(validPath, DefaultDirPerm); err != nil {
+	if err = s.fsys.MkdirAll(validPath, DefaultDirPerm); err != nil {
 		return nil, fmt.Errorf("failed to create directory: %w", err)
 	}
 
@@ -205,50 +278,108 @@ func (s *Service) ReadFile(req *types.ReadFileRequest) (*types.ReadFileResponse,
 		return nil, err
 	}
 
-	validPath, err := s.validatePath(req.Path)
+	validPath, err := s.validateAccessPath(req.Path, types.AccessRead)
 	if err != nil {
 		return nil, err
 	}
+	defer s.locks.lock(readLock(validPath))()
 
-	content, err := os.ReadFile(validPath)
+	file, err := s.fsys.Open(validPath)
 	if err != nil {
 		if errors.Is(err, fs.ErrNotExist) {
 			return nil, errors.New(types.ErrFileNotFound)
 		}
 		return nil, fmt.Errorf("failed to read file: %w", err)
 	}
+	defer func() { _ = file.Close() }()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat file: %w", err)
+	}
+	if info.IsDir() {
+		return nil, errors.New(types.ErrIsDirectory)
+	}
 
-	return &types.ReadFileResponse{
-		Content: string(content),
-	}, nil
+	resp, err := readFileContent(file, info.Size(), req)
+	if err != nil {
+		return nil, err
+	}
+
+	// 返回版本标识供条件写入使用,SHA-256需要读取整个文件,只在请求时计算
+	// Return the version token used by conditional writes; the SHA-256 needs a read of the whole file and is only computed on request
+	resp.Version = fileVersion(info)
+	resp.ModTime = info.ModTime()
+	if req.IncludeSHA256 && info.Size() <= MaxFileSize {
+		if resp.SHA256, err = fileSHA256(file); err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
 }
 
 // WriteFile 写入文件 / Write file
-func (s *Service) WriteFile(req *types.WriteFileRequest) (*types.OperationResponse, error) {
+// 内容按请求的字符集和换行符编码后先写入临时文件再重命名,前置条件的检查与替换在该路径的写锁内完成
+// Content is encoded with the requested charset and line endings, written to a temporary file and renamed; the precondition check and the replacement happen under the path's write lock
+func (s *Service) WriteFile(req *types.WriteFileRequest) (*types.WriteFileResponse, error) {
 	// 参数验证 / Parameter validation
 	if err := validateWriteFileRequest(req); err != nil {
 		return nil, err
 	}
 
-	validPath, err := s.validatePath(req.Path)
+	validPath, err := s.validateWritePath(req.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer s.locks.lock(writeLock(validPath))()
+
+	if err = checkWritePrecondition(s.fsys, validPath, req); err != nil {
+		return nil, err
+	}
+
+	data, err := encodeText(req.Content, req.Charset, req.BOM, req.LineEnding)
 	if err != nil {
 		return nil, err
 	}
+	if int64(len(data)) > MaxFileSize {
+		return nil, fmt.Errorf("content size exceeds maximum allowed size of %d bytes", MaxFileSize)
+	}
+	if err = s.checkWriteQuota(validPath, int64(len(data))); err != nil {
+		return nil, err
+	}
 
 	// 确保父目录存在 / Ensure parent directory exists
 	dir := filepath.Dir(validPath)
-	if err = os.MkdirAll(dir, DefaultDirPerm); err != nil {
+	if err = s.fsys.MkdirAll(dir, DefaultDirPerm); err != nil {
 		return nil, fmt.Errorf("failed to create parent directory: %w", err)
 	}
 
-	if err = os.WriteFile(validPath, []byte(req.Content), DefaultFilePerm); err != nil {
-		return nil, fmt.Errorf("failed to write file: %w", err)
+	// 保留已有文件的权限 / Keep the permissions of an existing file
+	perm := os.FileMode(DefaultFilePerm)
+	if info, statErr := s.fsys.Stat(validPath); statErr == nil {
+		if info.IsDir() {
+			return nil, errors.New(types.ErrIsDirectory)
+		}
+		perm = info.Mode().Perm()
+	}
+
+	if err = writeFileAtomic(s.fsys, validPath, data, perm); err != nil {
+		return nil, err
+	}
+
+	info, err := s.fsys.Stat(validPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat file: %w", err)
 	}
 
 	s.logger.Info("file written", zap.String("path", validPath))
-	return &types.OperationResponse{
+	return &types.WriteFileResponse{
 		Success: true,
 		Message: types.MsgFileWritten,
+		Version: fileVersion(info),
+		SHA256:  contentSHA256(data),
+		ModTime: info.ModTime(),
 	}, nil
 }
 
@@ -259,19 +390,21 @@ func (s *Service) Delete(req *types.DeleteRequest) (*types.OperationResponse, er
 		return nil, err
 	}
 
-	validPath, err := s.validatePath(req.Path)
+	validPath, err := s.validateAccessPath(req.Path, types.AccessDelete)
 	if err != nil {
 		return nil, err
 	}
+	defer s.locks.lock(writeLock(validPath))()
 
-	if err = os.RemoveAll(validPath); err != nil {
+	trashID, err := s.removePath(validPath, true)
+	if err != nil {
 		return nil, fmt.Errorf("failed to delete: %w", err)
 	}
 
 	s.logger.Info("deleted", zap.String("path", validPath))
 	return &types.OperationResponse{
 		Success: true,
-		Message: types.MsgFileDeleted,
+		Message: deleteMessage(types.MsgFileDeleted, trashID),
 	}, nil
 }
 
@@ -282,13 +415,14 @@ func (s *Service) DeleteFile(req *types.DeleteFileRequest) (*types.OperationResp
 		return nil, errors.New(types.ErrPathRequired)
 	}
 
-	validPath, err := s.validatePath(req.Path)
+	validPath, err := s.validateAccessPath(req.Path, types.AccessDelete)
 	if err != nil {
 		return nil, err
 	}
+	defer s.locks.lock(writeLock(validPath))()
 
 	// 检查是否为文件 / Check if it's a file
-	info, err := os.Stat(validPath)
+	info, err := s.fsys.Stat(validPath)
 	if err != nil {
 		if errors.Is(err, fs.ErrNotExist) {
 			return nil, errors.New(types.ErrFileNotFound)
@@ -300,14 +434,15 @@ func (s *Service) DeleteFile(req *types.DeleteFileRequest) (*types.OperationResp
 		return nil, errors.New("path is a directory, use delete_directory instead")
 	}
 
-	if err = os.Remove(validPath); err != nil {
+	trashID, err := s.removePath(validPath, false)
+	if err != nil {
 		return nil, fmt.Errorf("failed to delete file: %w", err)
 	}
 
 	s.logger.Info("file deleted", zap.String("path", validPath))
 	return &types.OperationResponse{
 		Success: true,
-		Message: types.MsgFileDeleted,
+		Message: deleteMessage(types.MsgFileDeleted, trashID),
 	}, nil
 }
 
@@ -318,13 +453,14 @@ func (s *Service) DeleteDirectory(req *types.DeleteDirectoryRequest) (*types.Ope
 		return nil, errors.New(types.ErrPathRequired)
 	}
 
-	validPath, err := s.validatePath(req.Path)
+	validPath, err := s.validateAccessPath(req.Path, types.AccessDelete)
 	if err != nil {
 		return nil, err
 	}
+	defer s.locks.lock(writeLock(validPath))()
 
 	// 检查是否为目录 / Check if it's a directory
-	info, err := os.Stat(validPath)
+	info, err := s.fsys.Stat(validPath)
 	if err != nil {
 		if errors.Is(err, fs.ErrNotExist) {
 			return nil, errors.New(types.ErrFileNotFound)
@@ -337,21 +473,19 @@ func (s *Service) DeleteDirectory(req *types.DeleteDirectoryRequest) (*types.Ope
 	}
 
 	// 默认递归删除 / Default to recursive delete
-	if req.Recursive {
-		if err = os.RemoveAll(validPath); err != nil {
+	trashID, err := s.removePath(validPath, req.Recursive)
+	if err != nil {
+		if req.Recursive {
 			return nil, fmt.Errorf("failed to delete directory: %w", err)
 		}
-	} else {
 		// 非递归删除，只能删除空目录 / Non-recursive delete, can only delete empty directory
-		if err = os.Remove(validPath); err != nil {
-			return nil, fmt.Errorf("failed to delete directory (directory may not be empty, use recursive=true): %w", err)
-		}
+		return nil, fmt.Errorf("failed to delete directory (directory may not be empty, use recursive=true): %w", err)
 	}
 
 	s.logger.Info("directory deleted", zap.String("path", validPath), zap.Bool("recursive", req.Recursive))
 	return &types.OperationResponse{
 		Success: true,
-		Message: "directory deleted successfully",
+		Message: deleteMessage("directory deleted successfully", trashID),
 	}, nil
 }
 
@@ -362,18 +496,22 @@ func (s *Service) Copy(req *types.CopyRequest) (*types.OperationResponse, error)
 		return nil, err
 	}
 
-	srcPath, err := s.validatePath(req.Source)
+	srcPath, err := s.validateAccessPath(req.Source, types.AccessRead)
 	if err != nil {
 		return nil, err
 	}
 
-	dstPath, err := s.validatePath(req.Destination)
+	dstPath, err := s.validateWritePath(req.Destination)
 	if err != nil {
 		return nil, err
 	}
+	if err = s.checkTransferAccess(srcPath, dstPath, types.AccessRead); err != nil {
+		return nil, err
+	}
+	defer s.locks.lock(readLock(srcPath), writeLock(dstPath))()
 
 	// 获取源文件信息 / Get source file info
-	srcInfo, err := os.Stat(srcPath)
+	srcInfo, err := s.fsys.Stat(srcPath)
 	if err != nil {
 		if errors.Is(err, fs.ErrNotExist) {
 			return nil, errors.New(types.ErrFileNotFound)
@@ -388,6 +526,9 @@ func (s *Service) Copy(req *types.CopyRequest) (*types.OperationResponse, error)
 		}
 	} else {
 		// 复制文件 / Copy file
+		if err = s.checkWriteQuota(dstPath, srcInfo.Size()); err != nil {
+			return nil, err
+		}
 		if err = s.copyFile(srcPath, dstPath); err != nil {
 			return nil, err
 		}
@@ -407,18 +548,22 @@ func (s *Service) CopyFile(req *types.CopyFileRequest) (*types.OperationResponse
 		return nil, errors.New("source and destination are required")
 	}
 
-	srcPath, err := s.validatePath(req.Source)
+	srcPath, err := s.validateAccessPath(req.Source, types.AccessRead)
 	if err != nil {
 		return nil, err
 	}
 
-	dstPath, err := s.validatePath(req.Destination)
+	dstPath, err := s.validateWritePath(req.Destination)
 	if err != nil {
 		return nil, err
 	}
+	if err = s.checkTransferAccess(srcPath, dstPath, types.AccessRead); err != nil {
+		return nil, err
+	}
+	defer s.locks.lock(readLock(srcPath), writeLock(dstPath))()
 
 	// 检查源是否为文件 / Check if source is a file
-	srcInfo, err := os.Stat(srcPath)
+	srcInfo, err := s.fsys.Stat(srcPath)
 	if err != nil {
 		if errors.Is(err, fs.ErrNotExist) {
 			return nil, errors.New(types.ErrFileNotFound)
@@ -430,6 +575,9 @@ func (s *Service) CopyFile(req *types.CopyFileRequest) (*types.OperationResponse
 		return nil, errors.New("source is a directory, use copy_directory instead")
 	}
 
+	if err = s.checkWriteQuota(dstPath, srcInfo.Size()); err != nil {
+		return nil, err
+	}
 	if err = s.copyFile(srcPath, dstPath); err != nil {
 		return nil, err
 	}
@@ -448,18 +596,22 @@ func (s *Service) CopyDirectory(req *types.CopyDirectoryRequest) (*types.Operati
 		return nil, errors.New("source and destination are required")
 	}
 
-	srcPath, err := s.validatePath(req.Source)
+	srcPath, err := s.validateAccessPath(req.Source, types.AccessRead)
 	if err != nil {
 		return nil, err
 	}
 
-	dstPath, err := s.validatePath(req.Destination)
+	dstPath, err := s.validateWritePath(req.Destination)
 	if err != nil {
 		return nil, err
 	}
+	if err = s.checkTransferAccess(srcPath, dstPath, types.AccessRead); err != nil {
+		return nil, err
+	}
+	defer s.locks.lock(readLock(srcPath), writeLock(dstPath))()
 
 	// 检查源是否为目录 / Check if source is a directory
-	srcInfo, err := os.Stat(srcPath)
+	srcInfo, err := s.fsys.Stat(srcPath)
 	if err != nil {
 		if errors.Is(err, fs.ErrNotExist) {
 			return nil, errors.New(types.ErrFileNotFound)
@@ -486,17 +638,17 @@ func (s *Service) CopyDirectory(req *types.CopyDirectoryRequest) (*types.Operati
 func (s *Service) copyFile(src, dst string) error {
 	// 确保目标目录存在 / Ensure destination directory exists
 	dstDir := filepath.Dir(dst)
-	if err := os.MkdirAll(dstDir, DefaultDirPerm); err != nil {
+	if err := s.fsys.MkdirAll(dstDir, DefaultDirPerm); err != nil {
 		return fmt.Errorf("failed to create destination directory: %w", err)
 	}
 
-	srcFile, err := os.Open(src)
+	srcFile, err := s.fsys.Open(src)
 	if err != nil {
 		return fmt.Errorf("failed to open source file: %w", err)
 	}
 	defer func() { _ = srcFile.Close() }()
 
-	dstFile, err := os.Create(dst)
+	dstFile, err := s.fsys.OpenFile(dst, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0666)
 	if err != nil {
 		return fmt.Errorf("failed to create destination file: %w", err)
 	}
@@ -507,26 +659,41 @@ func (s *Service) copyFile(src, dst string) error {
 	}
 
 	// 复制文件权限 / Copy file permissions
-	srcInfo, err := os.Stat(src)
+	srcInfo, err := s.fsys.Stat(src)
 	if err != nil {
 		return fmt.Errorf("failed to stat source file: %w", err)
 	}
 
-	if err = os.Chmod(dst, srcInfo.Mode()); err != nil {
+	if err = s.fsys.Chmod(dst, srcInfo.Mode()); err != nil {
 		return fmt.Errorf("failed to set file permissions: %w", err)
 	}
 
 	return nil
 }
 
-// copyDir 递归复制目录 / Recursively copy directory
+// copyDir 检查配额后递归复制目录 / Recursively copy directory after checking the quota
 func (s *Service) copyDir(src, dst string) error {
+	// 按源目录树的总量保守估计,不扣除目标中已有的内容 / Conservatively estimate with the whole source tree, without crediting existing content at the destination
+	if s.quotaEnabled() && s.inDefaultMount(dst) {
+		addBytes, addFiles := treeUsage(s.fsys, src)
+		if _, err := s.fsys.Lstat(dst); err != nil {
+			addFiles++
+		}
+		if err := s.checkQuota(addBytes, addFiles); err != nil {
+			return err
+		}
+	}
+	return s.copyDirTree(src, dst)
+}
+
+// copyDirTree 递归复制目录 / Recursively copy directory
+func (s *Service) copyDirTree(src, dst string) error {
 	// 创建目标目录 / Create destination directory
-	if err := os.MkdirAll(dst, DefaultDirPerm); err != nil {
+	if err := s.fsys.MkdirAll(dst, DefaultDirPerm); err != nil {
 		return fmt.Errorf("failed to create destination directory: %w", err)
 	}
 
-	entries, err := os.ReadDir(src)
+	entries, err := s.fsys.ReadDir(src)
 	if err != nil {
 		return fmt.Errorf("failed to read source directory: %w", err)
 	}
@@ -535,8 +702,26 @@ func (s *Service) copyDir(src, dst string) error {
 		srcPath := filepath.Join(src, entry.Name())
 		dstPath := filepath.Join(dst, entry.Name())
 
-		if entry.IsDir() {
-			if err = s.copyDir(srcPath, dstPath); err != nil {
+		isDir := entry.IsDir()
+		if entry.Type()&fs.ModeSymlink != 0 {
+			// 符号链接按策略处理,允许时复制其目标内容 / Symlinks follow the policy and are copied as their target's content when allowed
+			if !s.symlinkEntryAllowed(srcPath) {
+				s.logger.Warn("skipping symlink rejected by policy", zap.String("path", srcPath))
+				continue
+			}
+			targetInfo, err := s.fsys.Stat(srcPath)
+			if err != nil {
+				s.logger.Warn("skipping dangling symlink", zap.String("path", srcPath), zap.Error(err))
+				continue
+			}
+			if isDir = targetInfo.IsDir(); isDir && s.isSymlinkLoop(srcPath) {
+				s.logger.Warn("skipping symlink loop", zap.String("path", srcPath))
+				continue
+			}
+		}
+
+		if isDir {
+			if err = s.copyDirTree(srcPath, dstPath); err != nil {
 				return err
 			}
 		} else {
@@ -556,23 +741,31 @@ func (s *Service) Move(req *types.MoveRequest) (*types.OperationResponse, error)
 		return nil, err
 	}
 
-	srcPath, err := s.validatePath(req.Source)
+	srcPath, err := s.validateAccessPath(req.Source, types.AccessDelete)
 	if err != nil {
 		return nil, err
 	}
 
-	dstPath, err := s.validatePath(req.Destination)
+	dstPath, err := s.validateWritePath(req.Destination)
 	if err != nil {
 		return nil, err
 	}
+	// 移动后内容在目标处可读,因此源还需要读取权限 / The content becomes readable at the destination, so the source needs read access too
+	if err = s.checkTransferAccess(srcPath, dstPath, types.AccessRead, types.AccessDelete); err != nil {
+		return nil, err
+	}
+	defer s.locks.lock(writeLock(srcPath), writeLock(dstPath))()
 
 	// 确保目标目录存在 / Ensure destination directory exists
 	dstDir := filepath.Dir(dstPath)
-	if err = os.MkdirAll(dstDir, DefaultDirPerm); err != nil {
+	if err = s.fsys.MkdirAll(dstDir, DefaultDirPerm); err != nil {
 		return nil, fmt.Errorf("failed to create destination directory: %w", err)
 	}
 
-	if err = os.Rename(srcPath, dstPath); err != nil {
+	if err = s.checkMoveQuota(srcPath, dstPath); err != nil {
+		return nil, err
+	}
+	if err = s.movePath(srcPath, dstPath); err != nil {
 		return nil, fmt.Errorf("failed to move: %w", err)
 	}
 
@@ -590,18 +783,22 @@ func (s *Service) MoveFile(req *types.MoveFileRequest) (*types.OperationResponse
 		return nil, errors.New("source and destination are required")
 	}
 
-	srcPath, err := s.validatePath(req.Source)
+	srcPath, err := s.validateAccessPath(req.Source, types.AccessDelete)
 	if err != nil {
 		return nil, err
 	}
 
-	dstPath, err := s.validatePath(req.Destination)
+	dstPath, err := s.validateWritePath(req.Destination)
 	if err != nil {
 		return nil, err
 	}
+	if err = s.checkTransferAccess(srcPath, dstPath, types.AccessRead, types.AccessDelete); err != nil {
+		return nil, err
+	}
+	defer s.locks.lock(writeLock(srcPath), writeLock(dstPath))()
 
 	// 检查源是否为文件 / Check if source is a file
-	srcInfo, err := os.Stat(srcPath)
+	srcInfo, err := s.fsys.Stat(srcPath)
 	if err != nil {
 		if errors.Is(err, fs.ErrNotExist) {
 			return nil, errors.New(types.ErrFileNotFound)
@@ -615,11 +812,14 @@ func (s *Service) MoveFile(req *types.MoveFileRequest) (*types.OperationResponse
 
 	// 确保目标目录存在 / Ensure destination directory exists
 	dstDir := filepath.Dir(dstPath)
-	if err = os.MkdirAll(dstDir, DefaultDirPerm); err != nil {
+	if err = s.fsys.MkdirAll(dstDir, DefaultDirPerm); err != nil {
 		return nil, fmt.Errorf("failed to create destination directory: %w", err)
 	}
 
-	if err = os.Rename(srcPath, dstPath); err != nil {
+	if err = s.checkMoveQuota(srcPath, dstPath); err != nil {
+		return nil, err
+	}
+	if err = s.movePath(srcPath, dstPath); err != nil {
 		return nil, fmt.Errorf("failed to move file: %w", err)
 	}
 
@@ -637,18 +837,22 @@ func (s *Service) MoveDirectory(req *types.MoveDirectoryRequest) (*types.Operati
 		return nil, errors.New("source and destination are required")
 	}
 
-	srcPath, err := s.validatePath(req.Source)
+	srcPath, err := s.validateAccessPath(req.Source, types.AccessDelete)
 	if err != nil {
 		return nil, err
 	}
 
-	dstPath, err := s.validatePath(req.Destination)
+	dstPath, err := s.validateWritePath(req.Destination)
 	if err != nil {
 		return nil, err
 	}
+	if err = s.checkTransferAccess(srcPath, dstPath, types.AccessRead, types.AccessDelete); err != nil {
+		return nil, err
+	}
+	defer s.locks.lock(writeLock(srcPath), writeLock(dstPath))()
 
 	// 检查源是否为目录 / Check if source is a directory
-	srcInfo, err := os.Stat(srcPath)
+	srcInfo, err := s.fsys.Stat(srcPath)
 	if err != nil {
 		if errors.Is(err, fs.ErrNotExist) {
 			return nil, errors.New(types.ErrFileNotFound)
@@ -662,11 +866,14 @@ func (s *Service) MoveDirectory(req *types.MoveDirectoryRequest) (*types.Operati
 
 	// 确保目标父目录存在 / Ensure destination parent directory exists
 	dstDir := filepath.Dir(dstPath)
-	if err = os.MkdirAll(dstDir, DefaultDirPerm); err != nil {
+	if err = s.fsys.MkdirAll(dstDir, DefaultDirPerm); err != nil {
 		return nil, fmt.Errorf("failed to create destination directory: %w", err)
 	}
 
-	if err = os.Rename(srcPath, dstPath); err != nil {
+	if err = s.checkMoveQuota(srcPath, dstPath); err != nil {
+		return nil, err
+	}
+	if err = s.movePath(srcPath, dstPath); err != nil {
 		return nil, fmt.Errorf("failed to move directory: %w", err)
 	}
 
@@ -684,12 +891,12 @@ func (s *Service) ListDir(req *types.ListDirRequest) (*types.ListDirResponse, er
 		return nil, err
 	}
 
-	validPath, err := s.validatePath(req.Path)
+	validPath, err := s.validateAccessPath(req.Path, types.AccessList)
 	if err != nil {
 		return nil, err
 	}
 
-	entries, err := os.ReadDir(validPath)
+	entries, err := s.fsys.ReadDir(validPath)
 	if err != nil {
 		if errors.Is(err, fs.ErrNotExist) {
 			return nil, errors.New(types.ErrFileNotFound)
@@ -697,8 +904,55 @@ func (s *Service) ListDir(req *types.ListDirRequest) (*types.ListDirResponse, er
 		return nil, fmt.Errorf("failed to read directory: %w", err)
 	}
 
-	fileInfos := make([]types.FileInfo, 0, len(entries))
+	offset, err := decodeCursor(req.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = DefaultListLimit
+	}
+
+	// 过滤只依赖目录项,无需读取文件信息;隐藏条目默认列出,与分页之前的行为一致
+	// Filtering only needs directory entries, not file info; hidden entries are listed by default, as they were before pagination
+	excludeHidden := req.IncludeHidden != nil && !*req.IncludeHidden
+	filtered := entries[:0]
 	for _, entry := range entries {
+		if excludeHidden && strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		if !s.accessAllowed(filepath.Join(validPath, entry.Name()), types.AccessList) {
+			continue
+		}
+		if (req.Type == types.EntryTypeFile && entry.IsDir()) || (req.Type == types.EntryTypeDirectory && !entry.IsDir()) {
+			continue
+		}
+		if req.Pattern != "" {
+			if matched, _ := filepath.Match(req.Pattern, entry.Name()); !matched {
+				continue
+			}
+		}
+		filtered = append(filtered, entry)
+	}
+
+	resp := &types.ListDirResponse{Total: len(filtered)}
+
+	// 按名称排序时ReadDir的结果已有序,只需读取当前页的文件信息
+	// ReadDir output is already sorted by name, so only the current page needs file info
+	byName := req.SortBy == "" || req.SortBy == types.SortByName
+	if byName && req.SortOrder == types.SortOrderDesc {
+		slices.Reverse(filtered)
+	}
+	if byName {
+		if offset < len(filtered) {
+			filtered = filtered[offset:min(len(filtered), offset+limit)]
+		} else {
+			filtered = nil
+		}
+	}
+
+	fileInfos := make([]types.FileInfo, 0, len(filtered))
+	for _, entry := range filtered {
 		info, err := entry.Info()
 		if err != nil {
 			s.logger.Warn("failed to get file info", zap.String("name", entry.Name()), zap.Error(err))
@@ -707,7 +961,7 @@ func (s *Service) ListDir(req *types.ListDirRequest) (*types.ListDirResponse, er
 
 		fileInfos = append(fileInfos, types.FileInfo{
 			Name:    entry.Name(),
-			Path:    filepath.Join(req.Path, entry.Name()),
+			Path:    joinDisplayPath(req.Path, entry.Name()),
 			Size:    info.Size(),
 			IsDir:   entry.IsDir(),
 			Mode:    info.Mode().String(),
@@ -715,9 +969,20 @@ func (s *Service) ListDir(req *types.ListDirRequest) (*types.ListDirResponse, er
 		})
 	}
 
-	return &types.ListDirResponse{
-		Files: fileInfos,
-	}, nil
+	if !byName {
+		sortFileInfos(fileInfos, req.SortBy, req.SortOrder)
+		if offset < len(fileInfos) {
+			fileInfos = fileInfos[offset:min(len(fileInfos), offset+limit)]
+		} else {
+			fileInfos = []types.FileInfo{}
+		}
+	}
+
+	if offset+limit < resp.Total {
+		resp.NextCursor = encodeCursor(offset + limit)
+	}
+	resp.Files = fileInfos
+	return resp, nil
 }
 
 // Search 搜索文件 / Search files
@@ -727,41 +992,75 @@ func (s *Service) Search(req *types.SearchRequest) (*types.SearchResponse, error
 		return nil, err
 	}
 
-	validPath, err := s.validatePath(req.Path)
+	validPath, err := s.validateAccessPath(req.Path, types.AccessList)
 	if err != nil {
 		return nil, err
 	}
 
-	var matchedFiles []types.FileInfo
+	filter, err := newFileFilter(req)
+	if err != nil {
+		return nil, err
+	}
 
-	err = filepath.Walk(validPath, func(path string, info fs.FileInfo, err error) error {
-		if err != nil {
-			return nil // 跳过错误 / Skip errors
+	patterns := req.Include
+	if req.Pattern != "" {
+		patterns = append([]string{req.Pattern}, patterns...)
+	}
+
+	matchedFiles := []types.FileInfo{}
+
+	err = walkDir(s.fsys, validPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || path == validPath {
+			return nil // 跳过错误和搜索根目录 / Skip errors and the search root
 		}
 
-		// 检查文件名是否匹配模式 / Check if filename matches pattern
-		matched, err := filepath.Match(req.Pattern, info.Name())
+		// 相对搜索路径的路径用于模式匹配和深度计算 / Path relative to the search root for matching and depth
+		searchRel, err := filepath.Rel(validPath, path)
 		if err != nil {
-			return nil // 跳过无效模式 / Skip invalid patterns
+			return nil
 		}
+		searchRel = filepath.ToSlash(searchRel)
+		depth := strings.Count(searchRel, "/") + 1
 
-		if matched {
-			// 计算相对路径 / Calculate relative path
-			relPath, err := filepath.Rel(s.sandboxDir, path)
-			if err != nil {
-				relPath = path
+		if matchAnyGlob(req.Exclude, searchRel) {
+			if d.IsDir() {
+				return filepath.SkipDir
 			}
+			return nil
+		}
 
-			matchedFiles = append(matchedFiles, types.FileInfo{
-				Name:    info.Name(),
-				Path:    relPath,
-				Size:    info.Size(),
-				IsDir:   info.IsDir(),
-				Mode:    info.Mode().String(),
-				ModTime: info.ModTime(),
-			})
+		if d.Type()&fs.ModeSymlink != 0 && !s.symlinkEntryAllowed(path) {
+			return nil
 		}
 
+		// 规则禁止列出的条目不出现在结果中,目录整体跳过 / Entries the rules hide from listing are left out, directories as a whole
+		if !s.accessAllowed(path, types.AccessList) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if matchAnyGlob(patterns, searchRel) {
+			info, err := d.Info()
+			if err == nil && filter.match(info) {
+				// 计算相对路径 / Calculate relative path
+				relPath := s.displayPath(path)
+
+				matchedFiles = append(matchedFiles, types.FileInfo{
+					Name:    info.Name(),
+					Path:    relPath,
+					Size:    info.Size(),
+					IsDir:   info.IsDir(),
+					Mode:    info.Mode().String(),
+					ModTime: info.ModTime(),
+				})
+			}
+		}
+
+		if d.IsDir() && req.MaxDepth > 0 && depth >= req.MaxDepth {
+			return filepath.SkipDir
+		}
 		return nil
 	})
 
@@ -769,9 +1068,19 @@ func (s *Service) Search(req *types.SearchRequest) (*types.SearchResponse, error
 		return nil, fmt.Errorf("failed to search: %w", err)
 	}
 
-	return &types.SearchResponse{
-		Files: matchedFiles,
-	}, nil
+	maxResults := req.MaxResults
+	if maxResults <= 0 {
+		maxResults = DefaultFileSearchResults
+	}
+
+	resp := &types.SearchResponse{TotalMatches: len(matchedFiles)}
+	sortFileInfos(matchedFiles, req.SortBy, req.SortOrder)
+	if len(matchedFiles) > maxResults {
+		matchedFiles = matchedFiles[:maxResults]
+		resp.Truncated = true
+	}
+	resp.Files = matchedFiles
+	return resp, nil
 }
 
 // BatchDelete 批量删除 / Batch delete
@@ -784,14 +1093,17 @@ func (s *Service) BatchDelete(req *types.BatchDeleteRequest) (*types.OperationRe
 	var failedPaths []string
 
 	for _, path := range req.Paths {
-		validPath, err := s.validatePath(path)
+		validPath, err := s.validateAccessPath(path, types.AccessDelete)
 		if err != nil {
 			failedPaths = append(failedPaths, path)
 			s.logger.Warn("failed to validate path", zap.String("path", path), zap.Error(err))
 			continue
 		}
 
-		if err = os.RemoveAll(validPath); err != nil {
+		unlock := s.locks.lock(writeLock(validPath))
+		_, err = s.removePath(validPath, true)
+		unlock()
+		if err != nil {
 			failedPaths = append(failedPaths, path)
 			s.logger.Warn("failed to delete", zap.String("path", validPath), zap.Error(err))
 		}
@@ -818,12 +1130,12 @@ func (s *Service) FileStat(req *types.FileStatRequest) (*types.FileInfo, error)
 		return nil, err
 	}
 
-	validPath, err := s.validatePath(req.Path)
+	validPath, err := s.validateAccessPath(req.Path, types.AccessList)
 	if err != nil {
 		return nil, err
 	}
 
-	info, err := os.Stat(validPath)
+	info, err := s.fsys.Stat(validPath)
 	if err != nil {
 		if errors.Is(err, fs.ErrNotExist) {
 			return nil, errors.New(types.ErrFileNotFound)
@@ -832,19 +1144,33 @@ func (s *Service) FileStat(req *types.FileStatRequest) (*types.FileInfo, error)
 	}
 
 	// 计算相对路径 / Calculate relative path
-	relPath, err := filepath.Rel(s.sandboxDir, validPath)
-	if err != nil {
-		relPath = req.Path
-	}
+	relPath := s.displayPath(validPath)
 
-	return &types.FileInfo{
+	fileInfo := &types.FileInfo{
 		Name:    info.Name(),
 		Path:    relPath,
 		Size:    info.Size(),
 		IsDir:   info.IsDir(),
 		Mode:    info.Mode().String(),
 		ModTime: info.ModTime(),
-	}, nil
+	}
+
+	// 文件返回版本标识,内容SHA-256只在请求时计算 / Files carry a version token; their content SHA-256 is only computed on request
+	if !info.Mode().IsRegular() {
+		return fileInfo, nil
+	}
+	fileInfo.Version = fileVersion(info)
+	if req.IncludeSHA256 && info.Size() <= MaxFileSize {
+		file, err := s.fsys.Open(validPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open file: %w", err)
+		}
+		defer func() { _ = file.Close() }()
+		if fileInfo.SHA256, err = fileSHA256(file); err != nil {
+			return nil, err
+		}
+	}
+	return fileInfo, nil
 }
 
 // FileExists 检查文件是否存在 / Check if file exists
@@ -854,12 +1180,12 @@ func (s *Service) FileExists(req *types.FileExistsRequest) (*types.FileExistsRes
 		return nil, err
 	}
 
-	validPath, err := s.validatePath(req.Path)
+	validPath, err := s.validateAccessPath(req.Path, types.AccessList)
 	if err != nil {
 		return nil, err
 	}
 
-	_, err = os.Stat(validPath)
+	_, err = s.fsys.Stat(validPath)
 	exists := !errors.Is(err, fs.ErrNotExist)
 
 	return &types.FileExistsResponse{
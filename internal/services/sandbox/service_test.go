@@ -726,9 +726,9 @@ func TestSearchWithInvalidPattern(t *testing.T) {
 
 	resp, err := service.Search(req)
 
-	// 无效模式应该被跳过,返回空结果 / Invalid pattern should be skipped, return empty result
-	assert.NoError(t, err)
-	assert.NotNil(t, resp)
+	// 无效模式应该返回错误 / Invalid pattern should return an error
+	assert.Error(t, err)
+	assert.Nil(t, resp)
 }
 
 func TestMoveWithNestedDestination(t *testing.T) {
@@ -0,0 +1,706 @@
+// Copyright 2024 MCP Toolkit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sandbox
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"mcp-toolkit/pkg/types"
+	"mcp-toolkit/pkg/utils/json"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// SnapshotDirName 沙箱内快照存储目录名 / Name of the snapshot store directory inside the sandbox
+	SnapshotDirName = ".snapshots"
+
+	// snapshotObjectsDir 内容对象目录名,对象按SHA-256存储 / Directory of content objects, stored by SHA-256
+	snapshotObjectsDir = "objects"
+
+	// snapshotManifestsDir 快照清单目录名 / Directory of snapshot manifests
+	snapshotManifestsDir = "manifests"
+)
+
+// scannedFile 扫描得到的文件 / File found by a scan
+type scannedFile struct {
+	rel  string      // 相对于沙箱的斜杠路径 / Slash path relative to the sandbox
+	path string      // 绝对路径 / Absolute path
+	info fs.FileInfo // 文件信息 / File info
+}
+
+// snapshotState 快照范围内的当前状态 / Current state inside a snapshot scope
+type snapshotState struct {
+	dirs  []string
+	files []scannedFile
+}
+
+// snapshotDir 快照存储目录的绝对路径 / Absolute path of the snapshot store
+func (s *Service) snapshotDir() string {
+	return filepath.Join(s.sandboxDir, SnapshotDirName)
+}
+
+// snapshotObjectPath 内容对象的路径 / Path of a content object
+func (s *Service) snapshotObjectPath(hash string) string {
+	return filepath.Join(s.snapshotDir(), snapshotObjectsDir, hash[:2], hash)
+}
+
+// snapshotManifestPath 快照清单的路径 / Path of a snapshot manifest
+func (s *Service) snapshotManifestPath(id string) string {
+	return filepath.Join(s.snapshotDir(), snapshotManifestsDir, id+".json")
+}
+
+// scanSnapshotScope 扫描范围内的目录和常规文件,跳过回收站、快照存储、符号链接、排除的条目以及规则禁止读取的条目
+// Scan directories and regular files of a scope, skipping the trash, the snapshot store, symlinks, excluded entries and entries the rules deny reading
+func (s *Service) scanSnapshotScope(scope string, exclude []string) (*snapshotState, error) {
+	state := &snapshotState{}
+	err := walkDir(s.fsys, scope, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == scope {
+			return nil
+		}
+		if p == s.trashDir() || p == s.snapshotDir() {
+			return filepath.SkipDir
+		}
+
+		rel, err := filepath.Rel(scope, p)
+		if err != nil {
+			return err
+		}
+		// 快照会复制内容,禁止读取的条目不能进入对象库 / Snapshots copy content, so entries denied for reading must stay out of the object store
+		if matchAnyGlob(exclude, filepath.ToSlash(rel)) || !s.accessAllowed(p, types.AccessRead) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		switch {
+		case d.IsDir():
+			state.dirs = append(state.dirs, s.sandboxRelPath(p))
+		case d.Type().IsRegular():
+			if len(state.files) >= MaxSnapshotFiles {
+				return fmt.Errorf("snapshot file count exceeds maximum allowed count of %d", MaxSnapshotFiles)
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			state.files = append(state.files, scannedFile{rel: s.sandboxRelPath(p), path: p, info: info})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", scope, err)
+	}
+	return state, nil
+}
+
+// storeSnapshotObject 将文件内容存入对象库,已存在的对象不会重复写入,返回哈希、大小以及是否新建了对象
+// Store file content in the object store, skipping objects that already exist; returns the hash, the size and whether a new object was created
+func (s *Service) storeSnapshotObject(p string, buf []byte) (string, int64, bool, error) {
+	hash, size, err := hashFile(s.fsys, p, types.HashSHA256, buf)
+	if err != nil {
+		return "", 0, false, err
+	}
+	if _, err = s.fsys.Stat(s.snapshotObjectPath(hash)); err == nil {
+		return hash, size, false, nil
+	}
+
+	src, err := s.fsys.Open(p)
+	if err != nil {
+		return "", 0, false, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer src.Close()
+
+	objectsDir := filepath.Join(s.snapshotDir(), snapshotObjectsDir)
+	if err = s.fsys.MkdirAll(objectsDir, DefaultDirPerm); err != nil {
+		return "", 0, false, fmt.Errorf("failed to create object store: %w", err)
+	}
+	tmp, err := s.fsys.CreateTemp(objectsDir, ".object-*.tmp")
+	if err != nil {
+		return "", 0, false, fmt.Errorf("failed to create object: %w", err)
+	}
+	defer s.fsys.Remove(tmp.Name())
+
+	// 文件可能在两次读取之间被修改,按实际写入的内容命名 / The file may change between reads, so name the object by what was actually written
+	h := sha256.New()
+	n, err := io.CopyBuffer(io.MultiWriter(tmp, h), src, buf)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", 0, false, fmt.Errorf("failed to write object: %w", err)
+	}
+
+	hash = hex.EncodeToString(h.Sum(nil))
+	objectPath := s.snapshotObjectPath(hash)
+	if err = s.fsys.MkdirAll(filepath.Dir(objectPath), DefaultDirPerm); err != nil {
+		return "", 0, false, fmt.Errorf("failed to create object store: %w", err)
+	}
+	if err = s.fsys.Rename(tmp.Name(), objectPath); err != nil {
+		return "", 0, false, fmt.Errorf("failed to write object: %w", err)
+	}
+	return hash, n, true, nil
+}
+
+// CreateSnapshot 创建沙箱快照 / Create a sandbox snapshot
+func (s *Service) CreateSnapshot(req *types.CreateSnapshotRequest) (*types.CreateSnapshotResponse, error) {
+	// 参数验证 / Parameter validation
+	if err := validateCreateSnapshotRequest(req); err != nil {
+		return nil, err
+	}
+
+	scope, err := s.snapshotScope(req.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	s.snapshotMu.Lock()
+	defer s.snapshotMu.Unlock()
+	defer s.locks.lock(readLock(scope))()
+
+	state, err := s.scanSnapshotScope(scope, req.Exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	manifest := &types.SnapshotManifest{
+		SnapshotInfo: types.SnapshotInfo{
+			ID:        newTimestampID(now),
+			Name:      req.Name,
+			Path:      s.sandboxRelPath(scope),
+			CreatedAt: now,
+			FileCount: len(state.files),
+			DirCount:  len(state.dirs),
+		},
+		Exclude: req.Exclude,
+		Dirs:    state.dirs,
+		Files:   make([]types.SnapshotFile, 0, len(state.files)),
+	}
+	resp := &types.CreateSnapshotResponse{}
+
+	buf := make([]byte, readBufferSize)
+	for _, file := range state.files {
+		hash, size, created, err := s.storeSnapshotObject(file.path, buf)
+		if err != nil {
+			return nil, fmt.Errorf("failed to snapshot %s: %w", file.rel, err)
+		}
+		if created {
+			resp.NewObjects++
+			resp.NewBytes += size
+		}
+		manifest.TotalSize += size
+		manifest.Files = append(manifest.Files, types.SnapshotFile{
+			Path:    file.rel,
+			Hash:    hash,
+			Size:    size,
+			Mode:    uint32(file.info.Mode().Perm()),
+			ModTime: file.info.ModTime(),
+		})
+	}
+
+	if err = s.writeSnapshotManifest(manifest); err != nil {
+		return nil, err
+	}
+	resp.Snapshot = manifest.SnapshotInfo
+
+	s.logger.Info("snapshot created",
+		zap.String("id", manifest.ID),
+		zap.String("path", scope),
+		zap.Int("files", manifest.FileCount),
+		zap.Int("new_objects", resp.NewObjects))
+	return resp, nil
+}
+
+// snapshotScope 验证快照范围,范围必须是已存在的目录 / Validate a snapshot scope, which must be an existing directory
+func (s *Service) snapshotScope(scopePath string) (string, error) {
+	if scopePath == "" {
+		scopePath = "."
+	}
+	scope, err := s.validateAccessPath(scopePath, types.AccessRead)
+	if err != nil {
+		return "", err
+	}
+	// 快照对象存放在沙箱内,只覆盖沙箱根目录 / Snapshot objects live in the sandbox, so snapshots only cover the sandbox root
+	if !s.inDefaultMount(scope) {
+		return "", fmt.Errorf("cannot snapshot %s: snapshots are only supported inside the sandbox, not in mounts", scopePath)
+	}
+	if s.isInTrash(scope) || scope == s.snapshotDir() || strings.HasPrefix(scope, s.snapshotDir()+string(filepath.Separator)) {
+		return "", fmt.Errorf("cannot snapshot %s", scopePath)
+	}
+
+	info, err := s.fsys.Stat(scope)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", errors.New(types.ErrPathNotFound)
+		}
+		return "", fmt.Errorf("failed to stat path: %w", err)
+	}
+	if !info.IsDir() {
+		return "", errors.New(types.ErrNotDirectory)
+	}
+	return scope, nil
+}
+
+// writeSnapshotManifest 原子地写入快照清单 / Write a snapshot manifest atomically
+func (s *Service) writeSnapshotManifest(manifest *types.SnapshotManifest) error {
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot manifest: %w", err)
+	}
+
+	dir := filepath.Join(s.snapshotDir(), snapshotManifestsDir)
+	if err = s.fsys.MkdirAll(dir, DefaultDirPerm); err != nil {
+		return fmt.Errorf("failed to create snapshot store: %w", err)
+	}
+	tmp, err := s.fsys.CreateTemp(dir, ".manifest-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write snapshot manifest: %w", err)
+	}
+	defer s.fsys.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = s.fsys.Rename(tmp.Name(), s.snapshotManifestPath(manifest.ID))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write snapshot manifest: %w", err)
+	}
+	return nil
+}
+
+// loadSnapshotManifest 读取快照清单 / Load a snapshot manifest
+func (s *Service) loadSnapshotManifest(id string) (*types.SnapshotManifest, error) {
+	data, err := s.fsys.ReadFile(s.snapshotManifestPath(id))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("snapshot not found: %s", id)
+		}
+		return nil, fmt.Errorf("failed to read snapshot manifest: %w", err)
+	}
+	var manifest types.SnapshotManifest
+	if err = json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot manifest: %w", err)
+	}
+	manifest.ID = id
+	return &manifest, nil
+}
+
+// ListSnapshots 列出所有快照 / List all snapshots
+func (s *Service) ListSnapshots(req *types.ListSnapshotsRequest) (*types.ListSnapshotsResponse, error) {
+	if req == nil {
+		return nil, errors.New("request cannot be nil")
+	}
+
+	s.snapshotMu.Lock()
+	defer s.snapshotMu.Unlock()
+
+	resp := &types.ListSnapshotsResponse{Snapshots: []types.SnapshotInfo{}}
+	entries, err := s.fsys.ReadDir(filepath.Join(s.snapshotDir(), snapshotManifestsDir))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return resp, nil
+		}
+		return nil, fmt.Errorf("failed to read snapshot store: %w", err)
+	}
+
+	for _, entry := range entries {
+		id, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok || entry.IsDir() {
+			continue
+		}
+		manifest, err := s.loadSnapshotManifest(id)
+		if err != nil {
+			s.logger.Warn("skipping invalid snapshot", zap.String("id", id), zap.Error(err))
+			continue
+		}
+		resp.Snapshots = append(resp.Snapshots, manifest.SnapshotInfo)
+	}
+
+	sort.SliceStable(resp.Snapshots, func(i, j int) bool {
+		return resp.Snapshots[i].CreatedAt.After(resp.Snapshots[j].CreatedAt)
+	})
+	return resp, nil
+}
+
+// inSubtree 检查斜杠路径是否为prefix本身或位于其下,prefix为空表示全部
+// Check whether a slash path is prefix itself or lies under it; an empty prefix matches everything
+func inSubtree(p, prefix string) bool {
+	return prefix == "" || p == prefix || strings.HasPrefix(p, prefix+"/")
+}
+
+// snapshotSubtree 解析快照内的子路径,返回相对于沙箱的斜杠路径 / Resolve a sub path of a snapshot, returning a slash path relative to the sandbox
+func (s *Service) snapshotSubtree(manifest *types.SnapshotManifest, subPath string) (string, error) {
+	if subPath == "" {
+		return manifest.Path, nil
+	}
+	validPath, err := s.validatePath(subPath)
+	if err != nil {
+		return "", err
+	}
+	rel := s.sandboxRelPath(validPath)
+	if !inSubtree(rel, manifest.Path) {
+		return "", fmt.Errorf("path %s is outside of the snapshot scope %q", subPath, manifest.Path)
+	}
+	return rel, nil
+}
+
+// compareSnapshot 比较快照与当前状态,只包含subtree下的条目
+// Compare a snapshot with the current state, only including entries under subtree
+func (s *Service) compareSnapshot(manifest *types.SnapshotManifest, subtree string) (*types.SnapshotChanges, map[string]scannedFile, error) {
+	scope, err := s.validatePath(cmp.Or(manifest.Path, "."))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	current := &snapshotState{}
+	if info, statErr := s.fsys.Stat(scope); statErr == nil && info.IsDir() {
+		if current, err = s.scanSnapshotScope(scope, manifest.Exclude); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	changes := &types.SnapshotChanges{Added: []string{}, Modified: []string{}, Deleted: []string{}}
+
+	snapshotFiles := make(map[string]types.SnapshotFile, len(manifest.Files))
+	for _, file := range manifest.Files {
+		snapshotFiles[file.Path] = file
+	}
+	snapshotDirs := make(map[string]bool, len(manifest.Dirs))
+	for _, dir := range manifest.Dirs {
+		snapshotDirs[dir] = true
+	}
+
+	currentFiles := make(map[string]scannedFile, len(current.files))
+	buf := make([]byte, readBufferSize)
+	for _, file := range current.files {
+		if !inSubtree(file.rel, subtree) {
+			continue
+		}
+		currentFiles[file.rel] = file
+		recorded, ok := snapshotFiles[file.rel]
+		if !ok {
+			changes.Added = append(changes.Added, file.rel)
+			continue
+		}
+		changed, err := snapshotFileChanged(s.fsys, recorded, file, buf)
+		if err != nil {
+			return nil, nil, err
+		}
+		if changed {
+			changes.Modified = append(changes.Modified, file.rel)
+		}
+	}
+
+	currentDirs := make(map[string]bool, len(current.dirs))
+	for _, dir := range current.dirs {
+		if !inSubtree(dir, subtree) {
+			continue
+		}
+		currentDirs[dir] = true
+		if !snapshotDirs[dir] {
+			changes.Added = append(changes.Added, dir)
+		}
+	}
+
+	for _, file := range manifest.Files {
+		if _, ok := currentFiles[file.Path]; !ok && inSubtree(file.Path, subtree) {
+			changes.Deleted = append(changes.Deleted, file.Path)
+		}
+	}
+	for _, dir := range manifest.Dirs {
+		if !currentDirs[dir] && inSubtree(dir, subtree) {
+			changes.Deleted = append(changes.Deleted, dir)
+		}
+	}
+
+	slices.Sort(changes.Added)
+	slices.Sort(changes.Modified)
+	slices.Sort(changes.Deleted)
+	return changes, currentFiles, nil
+}
+
+// snapshotFileChanged 检查文件是否与快照记录不同,大小和修改时间都相同时视为未改变
+// Check whether a file differs from its snapshot record; equal size and modification time count as unchanged
+func snapshotFileChanged(fsys FileSystem, recorded types.SnapshotFile, file scannedFile, buf []byte) (bool, error) {
+	if file.info.Size() != recorded.Size || uint32(file.info.Mode().Perm()) != recorded.Mode {
+		return true, nil
+	}
+	if file.info.ModTime().Equal(recorded.ModTime) {
+		return false, nil
+	}
+	hash, _, err := hashFile(fsys, file.path, types.HashSHA256, buf)
+	if err != nil {
+		return false, err
+	}
+	return hash != recorded.Hash, nil
+}
+
+// DiffSnapshot 比较快照与当前状态 / Compare a snapshot with the current state
+func (s *Service) DiffSnapshot(req *types.DiffSnapshotRequest) (*types.DiffSnapshotResponse, error) {
+	// 参数验证 / Parameter validation
+	if err := validateDiffSnapshotRequest(req); err != nil {
+		return nil, err
+	}
+
+	s.snapshotMu.Lock()
+	defer s.snapshotMu.Unlock()
+
+	manifest, err := s.loadSnapshotManifest(req.ID)
+	if err != nil {
+		return nil, err
+	}
+	subtree, err := s.snapshotSubtree(manifest, req.Path)
+	if err != nil {
+		return nil, err
+	}
+	changes, currentFiles, err := s.compareSnapshot(manifest, subtree)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &types.DiffSnapshotResponse{SnapshotChanges: *changes}
+	if !req.Content || len(changes.Modified) == 0 {
+		return resp, nil
+	}
+
+	resp.Diffs = make(map[string]string, len(changes.Modified))
+	for _, file := range manifest.Files {
+		if _, ok := slices.BinarySearch(changes.Modified, file.Path); !ok {
+			continue
+		}
+		current := currentFiles[file.Path]
+		if file.Size > MaxReadSize || current.info.Size() > MaxReadSize {
+			continue
+		}
+		oldData, err := s.fsys.ReadFile(s.snapshotObjectPath(file.Hash))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read snapshot object: %w", err)
+		}
+		newData, err := s.fsys.ReadFile(current.path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file: %w", err)
+		}
+		// 二进制文件不生成差异 / No diffs for binary files
+		if isBinaryContent(oldData[:min(len(oldData), BinaryDetectSize)]) || isBinaryContent(newData[:min(len(newData), BinaryDetectSize)]) {
+			continue
+		}
+		if diff, _ := unifiedDiff("a/"+file.Path, "b/"+file.Path, string(oldData), string(newData), DefaultDiffContext); diff != "" {
+			resp.Diffs[file.Path] = diff
+		}
+	}
+	return resp, nil
+}
+
+// RestoreSnapshot 将沙箱恢复到快照状态 / Restore the sandbox to a snapshot
+func (s *Service) RestoreSnapshot(req *types.RestoreSnapshotRequest) (*types.RestoreSnapshotResponse, error) {
+	// 参数验证 / Parameter validation
+	if err := validateRestoreSnapshotRequest(req); err != nil {
+		return nil, err
+	}
+
+	s.snapshotMu.Lock()
+	defer s.snapshotMu.Unlock()
+
+	manifest, err := s.loadSnapshotManifest(req.ID)
+	if err != nil {
+		return nil, err
+	}
+	subtree, err := s.snapshotSubtree(manifest, req.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	// 比较和恢复期间锁定整个子树,预览只需共享锁 / Lock the whole subtree while comparing and restoring; a dry run only needs a shared lock
+	root := subtree
+	if root == "" {
+		root = "."
+	}
+	rootPath, err := s.validatePath(root)
+	if err != nil {
+		return nil, err
+	}
+	lock := writeLock(rootPath)
+	if req.DryRun {
+		lock = readLock(rootPath)
+	}
+	defer s.locks.lock(lock)()
+
+	changes, _, err := s.compareSnapshot(manifest, subtree)
+	if err != nil {
+		return nil, err
+	}
+	if req.KeepNewFiles {
+		changes.Added = []string{}
+	}
+
+	resp := &types.RestoreSnapshotResponse{Changes: *changes, DryRun: req.DryRun}
+	if req.DryRun {
+		resp.Success = true
+		resp.Message = "dry run completed, nothing was restored"
+		return resp, nil
+	}
+
+	// 先检查所有访问规则,避免只恢复一部分 / Check every access rule first so the restore is not left half done
+	if err = s.checkRestoreAccess(changes); err != nil {
+		return nil, err
+	}
+
+	// 先删除新增的条目,已删除目录下的条目一并移除 / Remove added entries first; entries under a removed directory go with it
+	removed := []string{}
+	removedSet := make(map[string]bool)
+	for _, rel := range changes.Added {
+		if underRemoved(rel, removedSet) {
+			continue
+		}
+		target, err := s.validateAccessPath(rel, types.AccessDelete)
+		if err != nil {
+			return nil, err
+		}
+		if _, err = s.removePath(target, true); err != nil {
+			return nil, fmt.Errorf("failed to remove %s: %w", rel, err)
+		}
+		removed = append(removed, rel)
+		removedSet[rel] = true
+	}
+	resp.Changes.Added = removed
+
+	files := make(map[string]types.SnapshotFile, len(manifest.Files))
+	for _, file := range manifest.Files {
+		files[file.Path] = file
+	}
+
+	// 删除的目录先于文件恢复,Deleted已排序,父目录在前 / Deleted is sorted, so parent directories are restored before their files
+	for _, rel := range changes.Deleted {
+		if _, isFile := files[rel]; isFile {
+			continue
+		}
+		target, err := s.validateAccessPath(rel, types.AccessWrite)
+		if err != nil {
+			return nil, err
+		}
+		if err = s.fsys.MkdirAll(target, DefaultDirPerm); err != nil {
+			return nil, fmt.Errorf("failed to create directory %s: %w", rel, err)
+		}
+	}
+
+	for _, rel := range slices.Concat(changes.Modified, changes.Deleted) {
+		file, ok := files[rel]
+		if !ok {
+			continue
+		}
+		if err = s.restoreSnapshotFile(file); err != nil {
+			return nil, err
+		}
+	}
+
+	resp.Success = true
+	resp.Message = fmt.Sprintf("restored snapshot %s: %d removed, %d modified, %d recreated",
+		manifest.ID, len(resp.Changes.Added), len(resp.Changes.Modified), len(resp.Changes.Deleted))
+	s.logger.Info("snapshot restored",
+		zap.String("id", manifest.ID),
+		zap.Int("removed", len(resp.Changes.Added)),
+		zap.Int("modified", len(resp.Changes.Modified)),
+		zap.Int("recreated", len(resp.Changes.Deleted)))
+	return resp, nil
+}
+
+// checkRestoreAccess 检查恢复涉及的删除和写入是否被访问规则允许 / Check whether the deletions and writes of a restore are allowed by the access rules
+func (s *Service) checkRestoreAccess(changes *types.SnapshotChanges) error {
+	for _, rel := range changes.Added {
+		target, err := s.validatePath(cmp.Or(rel, "."))
+		if err != nil {
+			return err
+		}
+		if err = s.checkTreeAccess(target, types.AccessDelete); err != nil {
+			return err
+		}
+	}
+	for _, rel := range slices.Concat(changes.Modified, changes.Deleted) {
+		if _, err := s.validateAccessPath(rel, types.AccessWrite); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// underRemoved 检查路径的某个祖先是否已被移除 / Check whether an ancestor of a path has already been removed
+func underRemoved(rel string, removed map[string]bool) bool {
+	for dir := path.Dir(rel); dir != "." && dir != "/"; dir = path.Dir(dir) {
+		if removed[dir] {
+			return true
+		}
+	}
+	return false
+}
+
+// restoreSnapshotFile 通过临时文件和重命名原子地恢复单个文件 / Restore a single file atomically via a temporary file and a rename
+func (s *Service) restoreSnapshotFile(file types.SnapshotFile) error {
+	target, err := s.validateAccessPath(file.Path, types.AccessWrite)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(target)
+	if err = s.fsys.MkdirAll(dir, DefaultDirPerm); err != nil {
+		return fmt.Errorf("failed to create parent directory: %w", err)
+	}
+
+	src, err := s.fsys.Open(s.snapshotObjectPath(file.Hash))
+	if err != nil {
+		return fmt.Errorf("failed to read snapshot object for %s: %w", file.Path, err)
+	}
+	defer src.Close()
+
+	tmp, err := s.fsys.CreateTemp(dir, "."+path.Base(file.Path)+".restore-*")
+	if err != nil {
+		return fmt.Errorf("failed to restore %s: %w", file.Path, err)
+	}
+	defer s.fsys.Remove(tmp.Name())
+
+	_, err = io.Copy(tmp, src)
+	if err == nil {
+		err = tmp.Chmod(fs.FileMode(file.Mode))
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		_ = s.fsys.Chtimes(tmp.Name(), file.ModTime, file.ModTime)
+		err = s.fsys.Rename(tmp.Name(), target)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to restore %s: %w", file.Path, err)
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+package sandbox
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"mcp-toolkit/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateSnapshotDeduplicates(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{
+		"a.txt":     "same",
+		"b.txt":     "same",
+		"src/c.go":  "package src\n",
+		"build.log": "noise",
+	})
+
+	first, err := service.CreateSnapshot(&types.CreateSnapshotRequest{Name: "first", Exclude: []string{"*.log"}})
+	require.NoError(t, err)
+	assert.Equal(t, 3, first.Snapshot.FileCount)
+	assert.Equal(t, 1, first.Snapshot.DirCount)
+	assert.Equal(t, int64(20), first.Snapshot.TotalSize)
+	// 相同内容只存一份 / Identical content is stored once
+	assert.Equal(t, 2, first.NewObjects)
+
+	second, err := service.CreateSnapshot(&types.CreateSnapshotRequest{Name: "second"})
+	require.NoError(t, err)
+	assert.Equal(t, 4, second.Snapshot.FileCount)
+	assert.Equal(t, 1, second.NewObjects)
+	assert.Equal(t, int64(5), second.NewBytes)
+
+	list, err := service.ListSnapshots(&types.ListSnapshotsRequest{})
+	require.NoError(t, err)
+	require.Len(t, list.Snapshots, 2)
+	assert.Equal(t, "second", list.Snapshots[0].Name)
+	assert.Equal(t, first.Snapshot.ID, list.Snapshots[1].ID)
+}
+
+func TestDiffSnapshot(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{
+		"keep.txt":        "keep",
+		"edit.txt":        "line1\nline2\n",
+		"remove.txt":      "bye",
+		"dir/nested.txt":  "nested",
+		"olddir/file.txt": "old",
+	})
+
+	created, err := service.CreateSnapshot(&types.CreateSnapshotRequest{})
+	require.NoError(t, err)
+
+	// 修改时间推后以避免同一时间戳下的误判 / Move the mtime forward so the change is not hidden by an equal timestamp
+	writeTestFiles(t, tempDir, map[string]string{"edit.txt": "line1\nchanged\n", "new/file.txt": "new"})
+	future := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(filepath.Join(tempDir, "edit.txt"), future, future))
+	require.NoError(t, os.Remove(filepath.Join(tempDir, "remove.txt")))
+	require.NoError(t, os.RemoveAll(filepath.Join(tempDir, "olddir")))
+
+	diff, err := service.DiffSnapshot(&types.DiffSnapshotRequest{ID: created.Snapshot.ID, Content: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"new", "new/file.txt"}, diff.Added)
+	assert.Equal(t, []string{"edit.txt"}, diff.Modified)
+	assert.Equal(t, []string{"olddir", "olddir/file.txt", "remove.txt"}, diff.Deleted)
+	assert.Contains(t, diff.Diffs["edit.txt"], "-line2\n+changed\n")
+
+	diff, err = service.DiffSnapshot(&types.DiffSnapshotRequest{ID: created.Snapshot.ID, Path: "new"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"new", "new/file.txt"}, diff.Added)
+	assert.Empty(t, diff.Modified)
+	assert.Empty(t, diff.Deleted)
+
+	_, err = service.DiffSnapshot(&types.DiffSnapshotRequest{ID: "missing"})
+	assert.Error(t, err)
+}
+
+func TestDiffSnapshotSameMtimeDifferentContent(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{"a.txt": "aaaa"})
+	created, err := service.CreateSnapshot(&types.CreateSnapshotRequest{})
+	require.NoError(t, err)
+
+	// 内容相同但修改时间改变时不算修改 / A touched file with the same content is not modified
+	later := time.Now().Add(time.Hour)
+	require.NoError(t, os.Chtimes(filepath.Join(tempDir, "a.txt"), later, later))
+
+	diff, err := service.DiffSnapshot(&types.DiffSnapshotRequest{ID: created.Snapshot.ID})
+	require.NoError(t, err)
+	assert.Empty(t, diff.Modified)
+}
+
+func TestRestoreSnapshot(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{
+		"edit.txt":       "original",
+		"remove.txt":     "bye",
+		"dir/nested.txt": "nested",
+	})
+	require.NoError(t, os.Chmod(filepath.Join(tempDir, "edit.txt"), 0600))
+
+	created, err := service.CreateSnapshot(&types.CreateSnapshotRequest{})
+	require.NoError(t, err)
+
+	writeTestFiles(t, tempDir, map[string]string{"edit.txt": "broken", "new/deep/file.txt": "new", "extra.txt": "x"})
+	require.NoError(t, os.RemoveAll(filepath.Join(tempDir, "dir")))
+	require.NoError(t, os.Remove(filepath.Join(tempDir, "remove.txt")))
+
+	dry, err := service.RestoreSnapshot(&types.RestoreSnapshotRequest{ID: created.Snapshot.ID, DryRun: true})
+	require.NoError(t, err)
+	assert.True(t, dry.DryRun)
+	assert.FileExists(t, filepath.Join(tempDir, "extra.txt"))
+
+	resp, err := service.RestoreSnapshot(&types.RestoreSnapshotRequest{ID: created.Snapshot.ID})
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	assert.Equal(t, []string{"extra.txt", "new"}, resp.Changes.Added)
+	assert.Equal(t, []string{"edit.txt"}, resp.Changes.Modified)
+	assert.Equal(t, []string{"dir", "dir/nested.txt", "remove.txt"}, resp.Changes.Deleted)
+
+	data, err := os.ReadFile(filepath.Join(tempDir, "edit.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "original", string(data))
+	info, err := os.Stat(filepath.Join(tempDir, "edit.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	assert.FileExists(t, filepath.Join(tempDir, "dir", "nested.txt"))
+	assert.FileExists(t, filepath.Join(tempDir, "remove.txt"))
+	assert.NoFileExists(t, filepath.Join(tempDir, "extra.txt"))
+	assert.NoDirExists(t, filepath.Join(tempDir, "new"))
+
+	// 恢复后与快照一致 / The sandbox matches the snapshot after restoring
+	diff, err := service.DiffSnapshot(&types.DiffSnapshotRequest{ID: created.Snapshot.ID})
+	require.NoError(t, err)
+	assert.Empty(t, diff.Added)
+	assert.Empty(t, diff.Modified)
+	assert.Empty(t, diff.Deleted)
+}
+
+func TestRestoreSnapshotSubtreeKeepNewFiles(t *testing.T) {
+	service, tempDir := setupTrashService(t, types.TrashConfig{})
+	defer cleanupTestService(t, tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{"src/a.go": "a", "docs/b.md": "b"})
+	created, err := service.CreateSnapshot(&types.CreateSnapshotRequest{})
+	require.NoError(t, err)
+
+	writeTestFiles(t, tempDir, map[string]string{"src/a.go": "changed", "src/new.go": "new", "docs/b.md": "changed"})
+
+	resp, err := service.RestoreSnapshot(&types.RestoreSnapshotRequest{ID: created.Snapshot.ID, Path: "src", KeepNewFiles: true})
+	require.NoError(t, err)
+	assert.Empty(t, resp.Changes.Added)
+	assert.Equal(t, []string{"src/a.go"}, resp.Changes.Modified)
+	assert.FileExists(t, filepath.Join(tempDir, "src", "new.go"))
+
+	data, err := os.ReadFile(filepath.Join(tempDir, "docs", "b.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "changed", string(data))
+
+	// 新增文件在回收站模式下移入回收站 / Added files go to the trash in trash mode
+	_, err = service.RestoreSnapshot(&types.RestoreSnapshotRequest{ID: created.Snapshot.ID, Path: "src"})
+	require.NoError(t, err)
+	trash, err := service.ListTrash(&types.ListTrashRequest{})
+	require.NoError(t, err)
+	require.Len(t, trash.Entries, 1)
+	assert.Equal(t, "src/new.go", trash.Entries[0].OriginalPath)
+}
+
+func TestCreateSnapshotInvalidScope(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{"a.txt": "a"})
+
+	_, err := service.CreateSnapshot(&types.CreateSnapshotRequest{Path: "a.txt"})
+	assert.Error(t, err)
+	_, err = service.CreateSnapshot(&types.CreateSnapshotRequest{Path: "missing"})
+	assert.Error(t, err)
+	_, err = service.CreateSnapshot(&types.CreateSnapshotRequest{Path: SnapshotDirName})
+	assert.Error(t, err)
+	_, err = service.CreateSnapshot(&types.CreateSnapshotRequest{Path: "../outside"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,168 @@
+// Copyright 2024 MCP Toolkit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sandbox
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"mcp-toolkit/pkg/types"
+
+	"go.uber.org/zap"
+)
+
+// maxSymlinkHops 解析悬空链接时允许的最大跳数 / Maximum hops when resolving dangling links
+const maxSymlinkHops = 40
+
+// SetSymlinkPolicy 设置符号链接策略,应在开始提供服务前调用 / Set the symlink policy, to be called before serving requests
+func (s *Service) SetSymlinkPolicy(policy types.SymlinkPolicy) error {
+	switch policy {
+	case types.SymlinkPolicyFollow, types.SymlinkPolicyDeny, types.SymlinkPolicyAllow:
+	default:
+		return fmt.Errorf("invalid symlink policy %q, must be follow, deny or allow", policy)
+	}
+	s.symlinkPolicy = policy
+	s.logger.Info("symlink policy set", zap.String("policy", string(policy)))
+	return nil
+}
+
+// SymlinkPolicy 获取符号链接策略 / Get the symlink policy
+func (s *Service) SymlinkPolicy() types.SymlinkPolicy {
+	return s.symlinkPolicy
+}
+
+// checkSymlinks 按策略检查路径中的符号链接,解析后的目标必须留在同一挂载内
+// Check the symlinks of a path against the policy; the resolved target must stay inside the same mount
+func (s *Service) checkSymlinks(requested, absPath string, root *mount) error {
+	if s.symlinkPolicy == types.SymlinkPolicyAllow {
+		return nil
+	}
+
+	resolved, err := resolveSymlinks(s.fsys, absPath)
+	if err != nil {
+		s.logger.Warn("failed to resolve symlinks",
+			zap.String("requested_path", requested),
+			zap.Error(err))
+		return errors.New(types.ErrSandboxViolation)
+	}
+
+	realRel, err := filepath.Rel(root.realDir, resolved)
+	if err != nil || !isLocalRel(realRel) {
+		s.logger.Warn("symlink escapes sandbox",
+			zap.String("requested_path", requested),
+			zap.String("resolved_path", resolved))
+		return errors.New(types.ErrSandboxViolation)
+	}
+
+	if s.symlinkPolicy == types.SymlinkPolicyDeny {
+		// 解析前后相对路径不同说明经过了符号链接 / A different relative path after resolution means a symlink was traversed
+		lexicalRel, err := filepath.Rel(root.dir, absPath)
+		if err != nil || lexicalRel != realRel {
+			s.logger.Warn("symlink rejected by policy",
+				zap.String("requested_path", requested),
+				zap.String("resolved_path", resolved))
+			return errors.New(types.ErrSandboxViolation)
+		}
+	}
+	return nil
+}
+
+// symlinkEntryAllowed 检查遍历中遇到的符号链接条目是否符合策略 / Check whether a symlink entry met while walking passes the policy
+func (s *Service) symlinkEntryAllowed(path string) bool {
+	switch s.symlinkPolicy {
+	case types.SymlinkPolicyAllow:
+		return true
+	case types.SymlinkPolicyDeny:
+		return false
+	}
+
+	resolved, err := resolveSymlinks(s.fsys, path)
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(s.mountFor(path).realDir, resolved)
+	return err == nil && isLocalRel(rel)
+}
+
+// isSymlinkLoop 检查目录链接是否指向其自身的祖先 / Check whether a directory link points at one of its own ancestors
+func (s *Service) isSymlinkLoop(path string) bool {
+	target, err := s.fsys.EvalSymlinks(path)
+	if err != nil {
+		return true
+	}
+	parent, err := s.fsys.EvalSymlinks(filepath.Dir(path))
+	if err != nil {
+		return true
+	}
+	rel, err := filepath.Rel(target, parent)
+	return err == nil && isLocalRel(rel)
+}
+
+// resolveSymlinks 解析路径中已存在部分的符号链接,不存在的部分按字面拼接
+// Resolve symlinks in the existing prefix of a path, appending the missing remainder as is
+func resolveSymlinks(fsys FileSystem, path string) (string, error) {
+	return resolveSymlinksHops(fsys, filepath.Clean(path), 0)
+}
+
+// resolveSymlinksHops 带跳数限制的解析 / Resolution with a hop limit
+func resolveSymlinksHops(fsys FileSystem, path string, hops int) (string, error) {
+	if hops > maxSymlinkHops {
+		return "", errors.New("too many levels of symbolic links")
+	}
+
+	current := path
+	var rest []string
+	for {
+		real, err := fsys.EvalSymlinks(current)
+		if err == nil {
+			return filepath.Join(append([]string{real}, rest...)...), nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+
+		// 悬空链接需要手动解析,否则写入时会在沙箱外创建目标
+		// Dangling links must be resolved by hand, otherwise a write would create the target outside the sandbox
+		if info, lerr := fsys.Lstat(current); lerr == nil && info.Mode()&fs.ModeSymlink != 0 {
+			target, err := fsys.Readlink(current)
+			if err != nil {
+				return "", err
+			}
+			if !filepath.IsAbs(target) {
+				target = filepath.Join(filepath.Dir(current), target)
+			}
+			resolved, err := resolveSymlinksHops(fsys, target, hops+1)
+			if err != nil {
+				return "", err
+			}
+			return filepath.Join(append([]string{resolved}, rest...)...), nil
+		}
+
+		parent := filepath.Dir(current)
+		if parent == current {
+			return filepath.Join(append([]string{current}, rest...)...), nil
+		}
+		rest = append([]string{filepath.Base(current)}, rest...)
+		current = parent
+	}
+}
+
+// isLocalRel 检查相对路径是否停留在基准目录内 / Check whether a relative path stays inside its base directory
+func isLocalRel(rel string) bool {
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && !filepath.IsAbs(rel)
+}
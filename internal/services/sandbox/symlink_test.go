@@ -0,0 +1,135 @@
+package sandbox
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"mcp-toolkit/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupSymlinkTest 创建沙箱外的目录以及指向它的链接 / Create a directory outside the sandbox and links pointing at it
+func setupSymlinkTest(t *testing.T) (*Service, string, string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks require elevated privileges on Windows")
+	}
+
+	service, tempDir := setupTestService(t)
+	t.Cleanup(func() { cleanupTestService(t, tempDir) })
+
+	outside := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(outside, "secret.txt"), []byte("secret"), 0644))
+	require.NoError(t, os.Symlink(outside, filepath.Join(tempDir, "escape")))
+	require.NoError(t, os.Symlink(filepath.Join(outside, "missing.txt"), filepath.Join(tempDir, "dangling")))
+
+	writeTestFiles(t, tempDir, map[string]string{"inside/data.txt": "data"})
+	require.NoError(t, os.Symlink("inside", filepath.Join(tempDir, "internal")))
+	return service, tempDir, outside
+}
+
+func TestValidatePathSymlinkFollow(t *testing.T) {
+	service, _, outside := setupSymlinkTest(t)
+
+	_, err := service.ReadFile(&types.ReadFileRequest{Path: "escape/secret.txt"})
+	require.Error(t, err)
+	assert.Equal(t, types.ErrSandboxViolation, err.Error())
+
+	// 指向沙箱外不存在文件的悬空链接也不能用于写入 / A dangling link to a missing file outside must not be writable either
+	_, err = service.WriteFile(&types.WriteFileRequest{Path: "dangling", Content: "x"})
+	require.Error(t, err)
+	_, statErr := os.Stat(filepath.Join(outside, "missing.txt"))
+	assert.True(t, os.IsNotExist(statErr))
+
+	_, err = service.CreateFile(&types.CreateFileRequest{Path: "escape/new/file.txt", Content: "x"})
+	assert.Error(t, err)
+
+	resp, err := service.ReadFile(&types.ReadFileRequest{Path: "internal/data.txt"})
+	require.NoError(t, err)
+	assert.Equal(t, "data", resp.Content)
+}
+
+func TestValidatePathSymlinkDeny(t *testing.T) {
+	service, _, _ := setupSymlinkTest(t)
+	require.NoError(t, service.SetSymlinkPolicy(types.SymlinkPolicyDeny))
+
+	_, err := service.ReadFile(&types.ReadFileRequest{Path: "internal/data.txt"})
+	require.Error(t, err)
+	assert.Equal(t, types.ErrSandboxViolation, err.Error())
+
+	resp, err := service.ReadFile(&types.ReadFileRequest{Path: "inside/data.txt"})
+	require.NoError(t, err)
+	assert.Equal(t, "data", resp.Content)
+}
+
+func TestValidatePathSymlinkAllow(t *testing.T) {
+	service, _, _ := setupSymlinkTest(t)
+	require.NoError(t, service.SetSymlinkPolicy(types.SymlinkPolicyAllow))
+
+	resp, err := service.ReadFile(&types.ReadFileRequest{Path: "escape/secret.txt"})
+	require.NoError(t, err)
+	assert.Equal(t, "secret", resp.Content)
+
+	assert.Error(t, service.SetSymlinkPolicy("sometimes"))
+	assert.Equal(t, types.SymlinkPolicyAllow, service.SymlinkPolicy())
+}
+
+func TestCopyDirSymlinkPolicy(t *testing.T) {
+	service, tempDir, _ := setupSymlinkTest(t)
+
+	_, err := service.CopyDirectory(&types.CopyDirectoryRequest{Source: ".", Destination: "../copy-target"})
+	require.Error(t, err)
+
+	writeTestFiles(t, tempDir, map[string]string{"src/file.txt": "content"})
+	require.NoError(t, os.Symlink(filepath.Join(tempDir, "escape", "secret.txt"), filepath.Join(tempDir, "src", "leak.txt")))
+	require.NoError(t, os.Symlink(filepath.Join(tempDir, "inside", "data.txt"), filepath.Join(tempDir, "src", "ok.txt")))
+	require.NoError(t, os.Symlink("..", filepath.Join(tempDir, "src", "parent")))
+
+	_, err = service.CopyDirectory(&types.CopyDirectoryRequest{Source: "src", Destination: "dst"})
+	require.NoError(t, err)
+
+	assert.FileExists(t, filepath.Join(tempDir, "dst", "file.txt"))
+	assert.NoFileExists(t, filepath.Join(tempDir, "dst", "leak.txt"))
+	assert.NoDirExists(t, filepath.Join(tempDir, "dst", "parent"))
+	data, err := os.ReadFile(filepath.Join(tempDir, "dst", "ok.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "data", string(data))
+}
+
+func TestSearchSymlinkPolicy(t *testing.T) {
+	service, _, _ := setupSymlinkTest(t)
+
+	resp, err := service.Search(&types.SearchRequest{Path: ".", Pattern: "*"})
+	require.NoError(t, err)
+	paths := searchPaths(resp)
+	assert.Contains(t, paths, "internal")
+	assert.NotContains(t, paths, "escape")
+	assert.NotContains(t, paths, "dangling")
+
+	require.NoError(t, service.SetSymlinkPolicy(types.SymlinkPolicyDeny))
+	resp, err = service.Search(&types.SearchRequest{Path: ".", Pattern: "*"})
+	require.NoError(t, err)
+	assert.NotContains(t, searchPaths(resp), "internal")
+}
+
+func TestResolveSymlinks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks require elevated privileges on Windows")
+	}
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "real"), 0755))
+	require.NoError(t, os.Symlink("real", filepath.Join(dir, "link")))
+	require.NoError(t, os.Symlink("loop", filepath.Join(dir, "loop")))
+
+	resolved, err := resolveSymlinks(NewOSFileSystem(), filepath.Join(dir, "link", "missing", "file.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "real", "missing", "file.txt"), resolved)
+
+	_, err = resolveSymlinks(NewOSFileSystem(), filepath.Join(dir, "loop", "file.txt"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,457 @@
+// Copyright 2024 MCP Toolkit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sandbox
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"mcp-toolkit/pkg/types"
+	"mcp-toolkit/pkg/utils/json"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// TrashDirName 沙箱内回收站目录名 / Name of the trash directory inside the sandbox
+	TrashDirName = ".trash"
+
+	// trashMetaFile 条目元数据文件名 / File name of the entry metadata
+	trashMetaFile = "meta.json"
+
+	// trashDataName 条目内容在条目目录中的名称 / Name of the entry content inside the entry directory
+	trashDataName = "data"
+)
+
+// SetTrashConfig 设置回收站配置,应在开始提供服务前调用 / Set the trash configuration, to be called before serving requests
+func (s *Service) SetTrashConfig(config types.TrashConfig) error {
+	if config.MaxAge < 0 {
+		return fmt.Errorf("invalid trash max age %d, must not be negative", config.MaxAge)
+	}
+	if config.MaxSize < 0 {
+		return fmt.Errorf("invalid trash max size %d, must not be negative", config.MaxSize)
+	}
+
+	s.trashMu.Lock()
+	s.trashConfig = config
+	s.trashMu.Unlock()
+
+	s.logger.Info("trash configured",
+		zap.Bool("enabled", config.Enabled),
+		zap.Int("max_age", config.MaxAge),
+		zap.Int64("max_size", config.MaxSize))
+	return nil
+}
+
+// TrashConfig 获取回收站配置 / Get the trash configuration
+func (s *Service) TrashConfig() types.TrashConfig {
+	s.trashMu.Lock()
+	defer s.trashMu.Unlock()
+	return s.trashConfig
+}
+
+// trashDir 回收站目录的绝对路径 / Absolute path of the trash directory
+func (s *Service) trashDir() string {
+	return filepath.Join(s.sandboxDir, TrashDirName)
+}
+
+// isInTrash 检查路径是否为回收站或位于其中 / Check whether a path is the trash or inside it
+func (s *Service) isInTrash(path string) bool {
+	rel, err := filepath.Rel(s.trashDir(), path)
+	return err == nil && isLocalRel(rel)
+}
+
+// removePath 删除路径,启用回收站时移入回收站并返回条目ID
+// Remove a path, moving it into the trash and returning the entry ID when trash mode is enabled
+func (s *Service) removePath(path string, recursive bool) (string, error) {
+	// 挂载根目录属于宿主机,不能删除 / Mount roots belong to the host and cannot be deleted
+	if m := s.mountFor(path); m.name != types.DefaultMountName && path == m.dir {
+		return "", fmt.Errorf("cannot delete the root of mount %s", m.name)
+	}
+
+	// 递归删除时目录下的每个条目都必须允许删除 / Every entry below a recursively deleted directory must allow deletion
+	if err := s.checkTreeAccess(path, types.AccessDelete); err != nil {
+		return "", err
+	}
+
+	if !s.TrashConfig().Enabled {
+		if recursive {
+			return "", s.fsys.RemoveAll(path)
+		}
+		return "", s.fsys.Remove(path)
+	}
+
+	if path == s.sandboxDir {
+		return "", errors.New("cannot move the sandbox root into the trash")
+	}
+	if s.isInTrash(path) {
+		return "", errors.New("path is inside the trash, use empty_trash instead")
+	}
+
+	info, err := s.fsys.Lstat(path)
+	if err != nil {
+		// 与RemoveAll一致,不存在的路径视为已删除 / Like RemoveAll, a missing path counts as removed
+		if recursive && errors.Is(err, fs.ErrNotExist) {
+			return "", nil
+		}
+		return "", err
+	}
+	if info.IsDir() && !recursive {
+		entries, err := s.fsys.ReadDir(path)
+		if err != nil {
+			return "", err
+		}
+		if len(entries) > 0 {
+			return "", errors.New(types.ErrDirectoryNotEmpty)
+		}
+	}
+
+	s.trashMu.Lock()
+	defer s.trashMu.Unlock()
+
+	entry, err := s.moveToTrashLocked(path, info)
+	if err != nil {
+		return "", err
+	}
+	s.applyTrashRetentionLocked()
+	return entry.ID, nil
+}
+
+// deleteMessage 删除结果消息,移入回收站时附带条目ID / Message of a deletion, carrying the entry ID when moved to the trash
+func deleteMessage(message, trashID string) string {
+	if trashID == "" {
+		return message
+	}
+	return fmt.Sprintf("%s (id: %s)", types.MsgMovedToTrash, trashID)
+}
+
+// moveToTrashLocked 将路径移入回收站,调用方需持有trashMu / Move a path into the trash; the caller must hold trashMu
+func (s *Service) moveToTrashLocked(path string, info fs.FileInfo) (*types.TrashEntry, error) {
+	now := time.Now()
+	entry := &types.TrashEntry{
+		ID:           newTimestampID(now),
+		OriginalPath: s.sandboxRelPath(path),
+		Type:         types.EntryTypeFile,
+		Size:         pathSize(s.fsys, path, info),
+		DeletedAt:    now,
+		SessionID:    s.sessionID,
+	}
+	if info.IsDir() {
+		entry.Type = types.EntryTypeDirectory
+	}
+
+	entryDir := filepath.Join(s.trashDir(), entry.ID)
+	if err := s.fsys.MkdirAll(entryDir, DefaultDirPerm); err != nil {
+		return nil, fmt.Errorf("failed to create trash entry: %w", err)
+	}
+
+	dataPath := filepath.Join(entryDir, trashDataName)
+	// 挂载中的条目可能位于其他设备,需要复制 / Entries from a mount may live on another device and need to be copied
+	if err := s.movePath(path, dataPath); err != nil {
+		_ = s.fsys.RemoveAll(entryDir)
+		return nil, fmt.Errorf("failed to move to trash: %w", err)
+	}
+
+	data, err := json.Marshal(entry)
+	if err == nil {
+		err = s.fsys.WriteFile(filepath.Join(entryDir, trashMetaFile), data, DefaultFilePerm)
+	}
+	if err != nil {
+		// 元数据写入失败时放回原处 / Put the entry back when its metadata cannot be written
+		_ = s.movePath(dataPath, path)
+		_ = s.fsys.RemoveAll(entryDir)
+		return nil, fmt.Errorf("failed to write trash metadata: %w", err)
+	}
+
+	s.logger.Info("moved to trash",
+		zap.String("path", path),
+		zap.String("id", entry.ID))
+	return entry, nil
+}
+
+// newTimestampID 生成以时间开头、可按字典序排序的ID / Generate an ID that starts with the time and sorts lexically
+func newTimestampID(now time.Time) string {
+	return now.UTC().Format("20060102T150405") + "-" + uuid.NewString()[:8]
+}
+
+// pathSize 计算文件或目录的总大小 / Compute the total size of a file or directory
+func pathSize(fsys FileSystem, path string, info fs.FileInfo) int64 {
+	if !info.IsDir() {
+		return info.Size()
+	}
+	var size int64
+	_ = walkDir(fsys, path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if fi, infoErr := d.Info(); infoErr == nil {
+			size += fi.Size()
+		}
+		return nil
+	})
+	return size
+}
+
+// loadTrashEntries 读取回收站中的所有条目,按删除时间从新到旧排序
+// Load every entry of the trash, sorted from the most to the least recently deleted
+func (s *Service) loadTrashEntries() ([]types.TrashEntry, error) {
+	dirEntries, err := s.fsys.ReadDir(s.trashDir())
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read trash: %w", err)
+	}
+
+	entries := make([]types.TrashEntry, 0, len(dirEntries))
+	for _, d := range dirEntries {
+		if !d.IsDir() {
+			continue
+		}
+		entry, err := s.loadTrashEntry(d.Name())
+		if err != nil {
+			s.logger.Warn("skipping invalid trash entry", zap.String("id", d.Name()), zap.Error(err))
+			continue
+		}
+		entries = append(entries, *entry)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].DeletedAt.After(entries[j].DeletedAt)
+	})
+	return entries, nil
+}
+
+// loadTrashEntry 读取单个条目的元数据 / Load the metadata of a single entry
+func (s *Service) loadTrashEntry(id string) (*types.TrashEntry, error) {
+	data, err := s.fsys.ReadFile(filepath.Join(s.trashDir(), id, trashMetaFile))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("trash entry not found: %s", id)
+		}
+		return nil, fmt.Errorf("failed to read trash metadata: %w", err)
+	}
+	var entry types.TrashEntry
+	if err = json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("failed to parse trash metadata: %w", err)
+	}
+	entry.ID = id
+	return &entry, nil
+}
+
+// applyTrashRetentionLocked 按保留时间和总大小清理条目,调用方需持有trashMu
+// Purge entries by age and total size; the caller must hold trashMu
+func (s *Service) applyTrashRetentionLocked() {
+	config := s.trashConfig
+	if config.MaxAge <= 0 && config.MaxSize <= 0 {
+		return
+	}
+
+	entries, err := s.loadTrashEntries()
+	if err != nil {
+		s.logger.Warn("failed to apply trash retention", zap.Error(err))
+		return
+	}
+
+	var total int64
+	for _, entry := range entries {
+		total += entry.Size
+	}
+
+	maxAge := time.Duration(config.MaxAge) * time.Second
+	// 从最早的条目开始清理 / Purge starting from the oldest entry
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		expired := config.MaxAge > 0 && time.Since(entry.DeletedAt) > maxAge
+		oversized := config.MaxSize > 0 && total > config.MaxSize
+		if !expired && !oversized {
+			continue
+		}
+		if err = s.fsys.RemoveAll(filepath.Join(s.trashDir(), entry.ID)); err != nil {
+			s.logger.Warn("failed to purge trash entry", zap.String("id", entry.ID), zap.Error(err))
+			continue
+		}
+		total -= entry.Size
+		s.logger.Info("trash entry purged by retention policy",
+			zap.String("id", entry.ID),
+			zap.Bool("expired", expired))
+	}
+}
+
+// ListTrash 列出回收站中的条目 / List entries in the trash
+func (s *Service) ListTrash(req *types.ListTrashRequest) (*types.ListTrashResponse, error) {
+	// 参数验证 / Parameter validation
+	if err := validateListTrashRequest(req); err != nil {
+		return nil, err
+	}
+
+	offset, err := decodeCursor(req.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	limit := req.Limit
+	if limit == 0 {
+		limit = DefaultListLimit
+	}
+
+	s.trashMu.Lock()
+	s.applyTrashRetentionLocked()
+	entries, err := s.loadTrashEntries()
+	enabled := s.trashConfig.Enabled
+	s.trashMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := strings.Trim(path.Clean(filepath.ToSlash(req.Path)), "/")
+	if prefix == "." {
+		prefix = ""
+	}
+
+	resp := &types.ListTrashResponse{Entries: []types.TrashEntry{}, Enabled: enabled}
+	var filtered []types.TrashEntry
+	for _, entry := range entries {
+		resp.TotalSize += entry.Size
+		if prefix == "" || entry.OriginalPath == prefix || strings.HasPrefix(entry.OriginalPath, prefix+"/") {
+			filtered = append(filtered, entry)
+		}
+	}
+
+	resp.Total = len(filtered)
+	if offset < len(filtered) {
+		resp.Entries = filtered[offset:min(len(filtered), offset+limit)]
+	}
+	if offset+limit < resp.Total {
+		resp.NextCursor = encodeCursor(offset + limit)
+	}
+	return resp, nil
+}
+
+// RestoreFromTrash 从回收站恢复条目 / Restore an entry from the trash
+func (s *Service) RestoreFromTrash(req *types.RestoreFromTrashRequest) (*types.RestoreFromTrashResponse, error) {
+	// 参数验证 / Parameter validation
+	if err := validateRestoreFromTrashRequest(req); err != nil {
+		return nil, err
+	}
+
+	destination := req.Destination
+	if destination == "" {
+		s.trashMu.Lock()
+		entry, err := s.loadTrashEntry(req.ID)
+		s.trashMu.Unlock()
+		if err != nil {
+			return nil, err
+		}
+		destination = entry.OriginalPath
+	}
+	validPath, err := s.validateWritePath(destination)
+	if err != nil {
+		return nil, err
+	}
+	if validPath == s.mountFor(validPath).dir || s.isInTrash(validPath) {
+		return nil, fmt.Errorf("cannot restore to %s", destination)
+	}
+
+	// 路径锁先于回收站锁获取,与删除操作的顺序一致 / The path lock is taken before the trash mutex, in the same order as deletions
+	defer s.locks.lock(writeLock(validPath))()
+	s.trashMu.Lock()
+	defer s.trashMu.Unlock()
+
+	// 条目可能在等待期间被清理 / The entry may have been purged while waiting
+	if _, err = s.loadTrashEntry(req.ID); err != nil {
+		return nil, err
+	}
+
+	if info, statErr := s.fsys.Lstat(validPath); statErr == nil {
+		if !req.Overwrite {
+			return nil, fmt.Errorf("path already exists: %s, set overwrite to replace it", destination)
+		}
+		// 被覆盖的内容同样进入回收站 / The replaced content goes to the trash as well
+		if s.trashConfig.Enabled {
+			_, err = s.moveToTrashLocked(validPath, info)
+		} else {
+			err = s.fsys.RemoveAll(validPath)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to replace existing path: %w", err)
+		}
+	}
+
+	if err = s.fsys.MkdirAll(filepath.Dir(validPath), DefaultDirPerm); err != nil {
+		return nil, fmt.Errorf("failed to create parent directory: %w", err)
+	}
+	entryDir := filepath.Join(s.trashDir(), req.ID)
+	if err = s.movePath(filepath.Join(entryDir, trashDataName), validPath); err != nil {
+		return nil, fmt.Errorf("failed to restore from trash: %w", err)
+	}
+	if err = s.fsys.RemoveAll(entryDir); err != nil {
+		s.logger.Warn("failed to remove trash entry", zap.String("id", req.ID), zap.Error(err))
+	}
+
+	s.logger.Info("restored from trash", zap.String("id", req.ID), zap.String("path", validPath))
+	return &types.RestoreFromTrashResponse{
+		Success: true,
+		Message: types.MsgRestoredFromTrash,
+		Path:    destination,
+	}, nil
+}
+
+// EmptyTrash 永久删除回收站中的条目 / Permanently delete entries in the trash
+func (s *Service) EmptyTrash(req *types.EmptyTrashRequest) (*types.EmptyTrashResponse, error) {
+	// 参数验证 / Parameter validation
+	if err := validateEmptyTrashRequest(req); err != nil {
+		return nil, err
+	}
+
+	s.trashMu.Lock()
+	defer s.trashMu.Unlock()
+
+	var entries []types.TrashEntry
+	if len(req.IDs) == 0 {
+		all, err := s.loadTrashEntries()
+		if err != nil {
+			return nil, err
+		}
+		entries = all
+	} else {
+		// 先检查所有ID,避免部分删除 / Check every ID first to avoid a partial removal
+		for _, id := range req.IDs {
+			entry, err := s.loadTrashEntry(id)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, *entry)
+		}
+	}
+
+	resp := &types.EmptyTrashResponse{Success: true}
+	for _, entry := range entries {
+		if err := s.fsys.RemoveAll(filepath.Join(s.trashDir(), entry.ID)); err != nil {
+			return nil, fmt.Errorf("failed to remove trash entry %s: %w", entry.ID, err)
+		}
+		resp.Removed++
+		resp.FreedBytes += entry.Size
+	}
+
+	s.logger.Info("trash emptied", zap.Int("removed", resp.Removed), zap.Int64("freed_bytes", resp.FreedBytes))
+	return resp, nil
+}
@@ -0,0 +1,236 @@
+package sandbox
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"mcp-toolkit/pkg/types"
+	"mcp-toolkit/pkg/utils/json"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupTrashService(t *testing.T, config types.TrashConfig) (*Service, string) {
+	service, tempDir := setupTestService(t)
+	config.Enabled = true
+	require.NoError(t, service.SetTrashConfig(config))
+	return service, tempDir
+}
+
+func TestDeleteMovesToTrash(t *testing.T) {
+	service, tempDir := setupTrashService(t, types.TrashConfig{})
+	defer cleanupTestService(t, tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{
+		"notes.txt":       "important",
+		"src/main.go":     "package main\n",
+		"src/lib/util.go": "package lib\n",
+		"a.txt":           "a",
+		"b.txt":           "b",
+	})
+	require.NoError(t, os.MkdirAll(filepath.Join(tempDir, "empty"), 0755))
+
+	resp, err := service.DeleteFile(&types.DeleteFileRequest{Path: "notes.txt"})
+	require.NoError(t, err)
+	assert.Contains(t, resp.Message, types.MsgMovedToTrash)
+	_, err = service.DeleteDirectory(&types.DeleteDirectoryRequest{Path: "src", Recursive: true})
+	require.NoError(t, err)
+	_, err = service.DeleteDirectory(&types.DeleteDirectoryRequest{Path: "empty"})
+	require.NoError(t, err)
+	batch, err := service.BatchDelete(&types.BatchDeleteRequest{Paths: []string{"a.txt", "b.txt"}})
+	require.NoError(t, err)
+	assert.True(t, batch.Success)
+
+	assert.NoFileExists(t, filepath.Join(tempDir, "notes.txt"))
+	assert.NoDirExists(t, filepath.Join(tempDir, "src"))
+
+	list, err := service.ListTrash(&types.ListTrashRequest{})
+	require.NoError(t, err)
+	assert.True(t, list.Enabled)
+	assert.Equal(t, 5, list.Total)
+	assert.Equal(t, int64(9+13+12+1+1), list.TotalSize)
+
+	byPath := make(map[string]types.TrashEntry)
+	for _, entry := range list.Entries {
+		byPath[entry.OriginalPath] = entry
+		assert.Equal(t, service.sessionID, entry.SessionID)
+	}
+	assert.Equal(t, types.EntryTypeDirectory, byPath["src"].Type)
+	assert.Equal(t, int64(25), byPath["src"].Size)
+	assert.Equal(t, types.EntryTypeFile, byPath["notes.txt"].Type)
+
+	filtered, err := service.ListTrash(&types.ListTrashRequest{Path: "src/"})
+	require.NoError(t, err)
+	require.Equal(t, 1, filtered.Total)
+	assert.Equal(t, "src", filtered.Entries[0].OriginalPath)
+
+	// 回收站内容不能再次移入回收站 / Trash contents cannot be trashed again
+	_, err = service.Delete(&types.DeleteRequest{Path: TrashDirName})
+	assert.Error(t, err)
+}
+
+func TestDeleteWithoutTrash(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{"a.txt": "a"})
+
+	resp, err := service.Delete(&types.DeleteRequest{Path: "a.txt"})
+	require.NoError(t, err)
+	assert.Equal(t, types.MsgFileDeleted, resp.Message)
+	assert.NoDirExists(t, filepath.Join(tempDir, TrashDirName))
+}
+
+func TestDeleteDirectoryNotEmptyWithTrash(t *testing.T) {
+	service, tempDir := setupTrashService(t, types.TrashConfig{})
+	defer cleanupTestService(t, tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{"dir/a.txt": "a"})
+
+	_, err := service.DeleteDirectory(&types.DeleteDirectoryRequest{Path: "dir"})
+	assert.Error(t, err)
+	assert.FileExists(t, filepath.Join(tempDir, "dir", "a.txt"))
+}
+
+func TestRestoreFromTrash(t *testing.T) {
+	service, tempDir := setupTrashService(t, types.TrashConfig{})
+	defer cleanupTestService(t, tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{"docs/readme.md": "v1"})
+
+	_, err := service.Delete(&types.DeleteRequest{Path: "docs/readme.md"})
+	require.NoError(t, err)
+	list, err := service.ListTrash(&types.ListTrashRequest{})
+	require.NoError(t, err)
+	require.Len(t, list.Entries, 1)
+	id := list.Entries[0].ID
+
+	// 原路径已被重新占用 / The original path is taken again
+	writeTestFiles(t, tempDir, map[string]string{"docs/readme.md": "v2"})
+	_, err = service.RestoreFromTrash(&types.RestoreFromTrashRequest{ID: id})
+	assert.Error(t, err)
+
+	resp, err := service.RestoreFromTrash(&types.RestoreFromTrashRequest{ID: id, Overwrite: true})
+	require.NoError(t, err)
+	assert.Equal(t, "docs/readme.md", resp.Path)
+	data, err := os.ReadFile(filepath.Join(tempDir, "docs", "readme.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "v1", string(data))
+
+	// 被覆盖的版本进入回收站 / The replaced version went to the trash
+	list, err = service.ListTrash(&types.ListTrashRequest{})
+	require.NoError(t, err)
+	require.Len(t, list.Entries, 1)
+	assert.NotEqual(t, id, list.Entries[0].ID)
+
+	resp, err = service.RestoreFromTrash(&types.RestoreFromTrashRequest{ID: list.Entries[0].ID, Destination: "old/readme.md"})
+	require.NoError(t, err)
+	data, err = os.ReadFile(filepath.Join(tempDir, "old", "readme.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "v2", string(data))
+
+	_, err = service.RestoreFromTrash(&types.RestoreFromTrashRequest{ID: id})
+	assert.Error(t, err)
+}
+
+func TestEmptyTrash(t *testing.T) {
+	service, tempDir := setupTrashService(t, types.TrashConfig{})
+	defer cleanupTestService(t, tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{"a.txt": "aaa", "b.txt": "bb", "c.txt": "c"})
+	_, err := service.BatchDelete(&types.BatchDeleteRequest{Paths: []string{"a.txt", "b.txt", "c.txt"}})
+	require.NoError(t, err)
+
+	list, err := service.ListTrash(&types.ListTrashRequest{})
+	require.NoError(t, err)
+	require.Len(t, list.Entries, 3)
+
+	_, err = service.EmptyTrash(&types.EmptyTrashRequest{IDs: []string{list.Entries[0].ID, "missing"}})
+	assert.Error(t, err)
+
+	resp, err := service.EmptyTrash(&types.EmptyTrashRequest{IDs: []string{list.Entries[0].ID}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, resp.Removed)
+
+	resp, err = service.EmptyTrash(&types.EmptyTrashRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, resp.Removed)
+
+	list, err = service.ListTrash(&types.ListTrashRequest{})
+	require.NoError(t, err)
+	assert.Empty(t, list.Entries)
+}
+
+func TestTrashRetention(t *testing.T) {
+	service, tempDir := setupTrashService(t, types.TrashConfig{MaxAge: 3600, MaxSize: 10})
+	defer cleanupTestService(t, tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{"old.txt": "old", "a.txt": "aaaa", "b.txt": "bbbb", "c.txt": "cccc"})
+
+	_, err := service.Delete(&types.DeleteRequest{Path: "old.txt"})
+	require.NoError(t, err)
+
+	// 将第一个条目的删除时间改为两小时前 / Backdate the first entry by two hours
+	list, err := service.ListTrash(&types.ListTrashRequest{})
+	require.NoError(t, err)
+	require.Len(t, list.Entries, 1)
+	entry := list.Entries[0]
+	entry.DeletedAt = time.Now().Add(-2 * time.Hour)
+	data, err := json.Marshal(entry)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, TrashDirName, entry.ID, trashMetaFile), data, 0644))
+
+	for _, name := range []string{"a.txt", "b.txt", "c.txt"} {
+		_, err = service.Delete(&types.DeleteRequest{Path: name})
+		require.NoError(t, err)
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// 过期条目被清理,总大小超限时最早的条目被清理 / The expired entry is purged, then the oldest while over the size limit
+	list, err = service.ListTrash(&types.ListTrashRequest{})
+	require.NoError(t, err)
+	paths := make([]string, 0, len(list.Entries))
+	for _, e := range list.Entries {
+		paths = append(paths, e.OriginalPath)
+	}
+	assert.Equal(t, []string{"c.txt", "b.txt"}, paths)
+	assert.Equal(t, int64(8), list.TotalSize)
+}
+
+func TestSetTrashConfig(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	assert.False(t, service.TrashConfig().Enabled)
+	assert.Error(t, service.SetTrashConfig(types.TrashConfig{MaxAge: -1}))
+	assert.Error(t, service.SetTrashConfig(types.TrashConfig{MaxSize: -1}))
+	require.NoError(t, service.SetTrashConfig(types.TrashConfig{Enabled: true, MaxSize: 100}))
+	assert.Equal(t, int64(100), service.TrashConfig().MaxSize)
+}
+
+func TestValidateStoreID(t *testing.T) {
+	tests := []struct {
+		id      string
+		wantErr bool
+	}{
+		{id: "20240101T000000-abcd1234", wantErr: false},
+		{id: "", wantErr: true},
+		{id: "..", wantErr: true},
+		{id: "../x", wantErr: true},
+		{id: `a\b`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			err := validateStoreID("trash entry", tt.id)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+// Copyright 2024 MCP Toolkit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sandbox
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"mcp-toolkit/pkg/types"
+)
+
+// treeBuilder 目录树构建器 / Directory tree builder
+type treeBuilder struct {
+	fsys     FileSystem
+	excludes []string
+	maxDepth int
+	visible  func(absPath string) bool // 访问规则是否允许列出 / Whether the access rules allow listing
+}
+
+// DirectoryTree 获取递归目录树 / Get a recursive directory tree
+func (s *Service) DirectoryTree(req *types.DirectoryTreeRequest) (*types.DirectoryTreeResponse, error) {
+	// 参数验证 / Parameter validation
+	if err := validateDirectoryTreeRequest(req); err != nil {
+		return nil, err
+	}
+
+	root := req.Path
+	if root == "" {
+		root = "."
+	}
+	validPath, err := s.validateAccessPath(root, types.AccessList)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := s.fsys.Stat(validPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, errors.New(types.ErrFileNotFound)
+		}
+		return nil, fmt.Errorf("failed to stat path: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, errors.New(types.ErrNotDirectory)
+	}
+
+	builder := &treeBuilder{
+		fsys:     s.fsys,
+		maxDepth: req.MaxDepth,
+		visible:  func(absPath string) bool { return s.accessAllowed(absPath, types.AccessList) },
+	}
+	if builder.maxDepth <= 0 {
+		builder.maxDepth = DefaultTreeDepth
+	}
+	if !req.NoDefaultExcludes {
+		builder.excludes = append(builder.excludes, DefaultTreeExcludes...)
+	}
+	builder.excludes = append(builder.excludes, req.Exclude...)
+
+	relPath := s.sandboxRelPath(validPath)
+	if relPath == "" {
+		relPath = "."
+	}
+	tree := builder.build(validPath, relPath, "", 0)
+	tree.Name = filepath.Base(validPath)
+
+	maxEntries := req.MaxEntries
+	if maxEntries <= 0 {
+		maxEntries = DefaultTreeEntries
+	}
+	entries, truncated := pruneTree(tree, maxEntries)
+
+	resp := &types.DirectoryTreeResponse{
+		Entries:   entries,
+		Truncated: truncated,
+	}
+	if req.Format != types.TreeFormatText {
+		resp.Root = tree
+	}
+	if req.Format != types.TreeFormatJSON {
+		resp.Text = renderTree(tree)
+	}
+	return resp, nil
+}
+
+// build 构建目录节点,深度范围内的子节点全部保留,更深的部分只参与汇总
+// Build a directory node keeping all children within the depth limit; deeper levels only contribute to the rollups
+func (b *treeBuilder) build(absPath, relPath, treeRel string, depth int) *types.TreeNode {
+	node := &types.TreeNode{
+		Name: filepath.Base(absPath),
+		Path: relPath,
+		Type: types.TreeNodeDirectory,
+	}
+
+	entries, err := b.fsys.ReadDir(absPath)
+	if err != nil {
+		return node // 无法读取的目录作为空目录展示 / Unreadable directories are shown as empty
+	}
+
+	expand := depth < b.maxDepth
+	for _, entry := range entries {
+		childTreeRel := path.Join(treeRel, entry.Name())
+		if matchAnyGlob(b.excludes, childTreeRel) || !b.visible(filepath.Join(absPath, entry.Name())) {
+			continue
+		}
+
+		childRel := joinDisplayPath(relPath, entry.Name())
+		var child *types.TreeNode
+		switch {
+		case entry.IsDir():
+			child = b.build(filepath.Join(absPath, entry.Name()), childRel, childTreeRel, depth+1)
+			node.DirCount += child.DirCount + 1
+			node.FileCount += child.FileCount
+		default:
+			info, err := entry.Info()
+			if err != nil {
+				continue
+			}
+			child = &types.TreeNode{Name: entry.Name(), Path: childRel, Type: types.TreeNodeFile, Size: info.Size()}
+			if entry.Type()&fs.ModeSymlink != 0 {
+				child.Type = types.TreeNodeSymlink
+			}
+			node.FileCount++
+		}
+		node.Size += child.Size
+
+		if expand {
+			node.Children = append(node.Children, child)
+		} else {
+			node.Omitted++
+		}
+	}
+
+	// 目录在前,同类按名称排序 / Directories first, then by name
+	sort.SliceStable(node.Children, func(i, j int) bool {
+		a, c := node.Children[i], node.Children[j]
+		if (a.Type == types.TreeNodeDirectory) != (c.Type == types.TreeNodeDirectory) {
+			return a.Type == types.TreeNodeDirectory
+		}
+		return a.Name < c.Name
+	})
+	return node
+}
+
+// pruneTree 按广度优先保留条目直到用完预算,返回保留的条目数和是否有条目被省略
+// Keep entries breadth-first until the budget is spent, returning the kept count and whether anything was left out
+func pruneTree(root *types.TreeNode, budget int) (int, bool) {
+	kept := 0
+	truncated := root.Omitted > 0
+	queue := []*types.TreeNode{root}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+
+		children := node.Children[:0]
+		for _, child := range node.Children {
+			if kept >= budget {
+				node.Omitted++
+				continue
+			}
+			kept++
+			children = append(children, child)
+			queue = append(queue, child)
+		}
+		node.Children = children
+		if node.Omitted > 0 {
+			truncated = true
+		}
+	}
+	return kept, truncated
+}
+
+// renderTree 将目录树渲染为缩进文本 / Render the tree as indented text
+func renderTree(root *types.TreeNode) string {
+	var sb strings.Builder
+	var render func(node *types.TreeNode, indent string)
+	render = func(node *types.TreeNode, indent string) {
+		sb.WriteString(indent)
+		if node.Type == types.TreeNodeDirectory {
+			fmt.Fprintf(&sb, "%s/ (%d files, %s)\n", node.Name, node.FileCount, formatSize(node.Size))
+		} else {
+			fmt.Fprintf(&sb, "%s (%s)\n", node.Name, formatSize(node.Size))
+		}
+		for _, child := range node.Children {
+			render(child, indent+"  ")
+		}
+		if node.Omitted > 0 {
+			fmt.Fprintf(&sb, "%s  ... %d more\n", indent, node.Omitted)
+		}
+	}
+	render(root, "")
+	return sb.String()
+}
+
+// formatSize 格式化字节数为可读形式 / Format a byte count in human readable form
+func formatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(size)/float64(div), "KMGTPE"[exp])
+}
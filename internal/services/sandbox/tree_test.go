@@ -0,0 +1,131 @@
+package sandbox
+
+import (
+	"strings"
+	"testing"
+
+	"mcp-toolkit/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDirectoryTree(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{
+		"README.md":               strings.Repeat("a", 100),
+		"src/main.go":             strings.Repeat("a", 200),
+		"src/pkg/util.go":         strings.Repeat("a", 300),
+		"src/pkg/deep/inner.go":   strings.Repeat("a", 400),
+		".git/HEAD":               "ref",
+		"node_modules/x/index.js": "module",
+	})
+
+	resp, err := service.DirectoryTree(&types.DirectoryTreeRequest{MaxDepth: 2})
+	require.NoError(t, err)
+	require.NotNil(t, resp.Root)
+
+	root := resp.Root
+	assert.Equal(t, int64(1000), root.Size)
+	assert.Equal(t, 4, root.FileCount)
+	assert.Equal(t, 3, root.DirCount)
+	require.Len(t, root.Children, 2)
+
+	// 目录在前 / Directories come first
+	src := root.Children[0]
+	assert.Equal(t, "src", src.Name)
+	assert.Equal(t, "src", src.Path)
+	assert.Equal(t, int64(900), src.Size)
+	assert.Equal(t, 3, src.FileCount)
+	assert.Equal(t, "README.md", root.Children[1].Name)
+
+	// 第二层的目录不再展开,但汇总包含更深的文件 / Level-two directories are not expanded but their rollups include deeper files
+	require.Len(t, src.Children, 2)
+	pkg := src.Children[0]
+	assert.Equal(t, "pkg", pkg.Name)
+	assert.Empty(t, pkg.Children)
+	assert.Equal(t, 2, pkg.Omitted)
+	assert.Equal(t, int64(700), pkg.Size)
+	assert.True(t, resp.Truncated)
+	assert.Equal(t, 4, resp.Entries)
+
+	assert.Contains(t, resp.Text, "src/ (3 files, 900 B)\n")
+	assert.Contains(t, resp.Text, "    pkg/ (2 files, 700 B)\n      ... 2 more\n")
+	assert.NotContains(t, resp.Text, ".git")
+	assert.NotContains(t, resp.Text, "node_modules")
+}
+
+func TestDirectoryTreeEntryBudget(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{
+		"a/1.txt": "1",
+		"a/2.txt": "2",
+		"b/3.txt": "3",
+		"c.txt":   "4",
+	})
+
+	resp, err := service.DirectoryTree(&types.DirectoryTreeRequest{MaxEntries: 4, Format: types.TreeFormatJSON})
+	require.NoError(t, err)
+	assert.Empty(t, resp.Text)
+	assert.Equal(t, 4, resp.Entries)
+	assert.True(t, resp.Truncated)
+
+	// 广度优先:先展示第一层的全部条目 / Breadth-first: all top-level entries are shown first
+	require.Len(t, resp.Root.Children, 3)
+	assert.Len(t, resp.Root.Children[0].Children, 1)
+	assert.Equal(t, 1, resp.Root.Children[0].Omitted)
+	assert.Empty(t, resp.Root.Children[1].Children)
+	assert.Equal(t, 1, resp.Root.Children[1].Omitted)
+}
+
+func TestDirectoryTreeExcludes(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{
+		"dist/bundle.js":          "x",
+		"app.log":                 "x",
+		"main.go":                 "x",
+		"node_modules/x/index.js": "x",
+	})
+
+	resp, err := service.DirectoryTree(&types.DirectoryTreeRequest{Exclude: []string{"dist", "*.log"}, NoDefaultExcludes: true, Format: types.TreeFormatText})
+	require.NoError(t, err)
+	assert.Nil(t, resp.Root)
+	assert.Contains(t, resp.Text, "node_modules/")
+	assert.Contains(t, resp.Text, "main.go (1 B)")
+	assert.NotContains(t, resp.Text, "dist")
+	assert.NotContains(t, resp.Text, "app.log")
+}
+
+func TestDirectoryTreeErrors(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	writeTestFiles(t, tempDir, map[string]string{"file.txt": "x"})
+
+	_, err := service.DirectoryTree(&types.DirectoryTreeRequest{Path: "file.txt"})
+	require.Error(t, err)
+	assert.Equal(t, types.ErrNotDirectory, err.Error())
+
+	_, err = service.DirectoryTree(&types.DirectoryTreeRequest{Path: "missing"})
+	require.Error(t, err)
+	assert.Equal(t, types.ErrFileNotFound, err.Error())
+
+	_, err = service.DirectoryTree(&types.DirectoryTreeRequest{Format: "xml"})
+	assert.Error(t, err)
+
+	_, err = service.DirectoryTree(&types.DirectoryTreeRequest{MaxDepth: MaxTreeDepth + 1})
+	assert.Error(t, err)
+}
+
+func TestFormatSize(t *testing.T) {
+	assert.Equal(t, "0 B", formatSize(0))
+	assert.Equal(t, "1023 B", formatSize(1023))
+	assert.Equal(t, "1.0 KB", formatSize(1024))
+	assert.Equal(t, "1.5 MB", formatSize(1536*1024))
+}
@@ -0,0 +1,328 @@
+// Copyright 2024 MCP Toolkit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sandbox
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"mcp-toolkit/pkg/types"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// MaxUploadChunkSize 单个分块解码后的最大字节数(8MB) / Maximum decoded bytes per chunk (8MB)
+	MaxUploadChunkSize int64 = 8 * 1024 * 1024
+
+	// UploadSessionTTL 上传会话无活动后的过期时间 / How long an upload session lives without activity
+	UploadSessionTTL = 15 * time.Minute
+
+	// MaxUploadSessions 同时进行的上传会话上限 / Maximum number of concurrent upload sessions
+	MaxUploadSessions = 64
+)
+
+// uploadSession 分块上传会话,内容暂存在目标目录的隐藏临时文件中,提交时重命名到位
+// Chunked upload session; the content is staged in a hidden temporary file in the target directory and renamed into place on commit
+type uploadSession struct {
+	id        string      // 会话ID / Session ID
+	path      string      // 目标文件的绝对路径 / Absolute path of the target file
+	tempPath  string      // 暂存内容的临时文件 / Temporary file staging the content
+	size      int64       // 预期总大小,0表示未知 / Expected total size, 0 when unknown
+	overwrite bool        // 是否覆盖已存在的文件 / Whether to overwrite an existing file
+	received  int64       // 已连续接收的字节数 / Contiguous bytes received so far
+	expiresAt time.Time   // 过期时间 / Expiry time
+	timer     *time.Timer // 过期定时器 / Expiry timer
+	mu        sync.Mutex  // 串行化同一会话的分块和提交 / Serialises the chunks and the commit of one session
+	done      bool        // 会话已提交或丢弃 / Whether the session was committed or discarded
+}
+
+// BeginUpload 开始分块上传,创建暂存文件并返回会话ID / Begin a chunked upload, creating the staging file and returning the session ID
+func (s *Service) BeginUpload(req *types.BeginUploadRequest) (*types.BeginUploadResponse, error) {
+	if err := validateBeginUploadRequest(req); err != nil {
+		return nil, err
+	}
+
+	validPath, err := s.validateWritePath(req.Path)
+	if err != nil {
+		return nil, err
+	}
+	// 与writeFileAtomic一样写入符号链接指向的文件 / Write the destination of a symlink, like writeFileAtomic
+	target := validPath
+	if info, lstatErr := s.fsys.Lstat(validPath); lstatErr == nil && info.Mode()&fs.ModeSymlink != 0 {
+		if resolved, evalErr := s.fsys.EvalSymlinks(validPath); evalErr == nil {
+			target = resolved
+		}
+	}
+	if err = s.checkUploadTarget(target, req.Overwrite); err != nil {
+		return nil, err
+	}
+	if err = s.checkWriteQuota(target, req.Size); err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Dir(target)
+	if err = s.fsys.MkdirAll(dir, DefaultDirPerm); err != nil {
+		return nil, fmt.Errorf("failed to create parent directory: %w", err)
+	}
+
+	s.uploadMu.Lock()
+	defer s.uploadMu.Unlock()
+	if len(s.uploads) >= MaxUploadSessions {
+		return nil, fmt.Errorf("too many upload sessions in progress, the limit is %d", MaxUploadSessions)
+	}
+
+	tmp, err := s.fsys.CreateTemp(dir, "."+filepath.Base(target)+".upload-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	if err = tmp.Close(); err != nil {
+		_ = s.fsys.Remove(tmp.Name())
+		return nil, fmt.Errorf("failed to close temporary file: %w", err)
+	}
+
+	session := &uploadSession{
+		id:        uuid.NewString(),
+		path:      target,
+		tempPath:  tmp.Name(),
+		size:      req.Size,
+		overwrite: req.Overwrite,
+		expiresAt: time.Now().Add(UploadSessionTTL),
+	}
+	session.timer = time.AfterFunc(UploadSessionTTL, func() { s.expireUpload(session) })
+	if s.uploads == nil {
+		s.uploads = make(map[string]*uploadSession)
+	}
+	s.uploads[session.id] = session
+
+	s.logger.Info("upload started", zap.String("id", session.id), zap.String("path", target), zap.Int64("size", req.Size))
+	return &types.BeginUploadResponse{
+		Success:      true,
+		Message:      types.MsgUploadStarted,
+		UploadID:     session.id,
+		Path:         s.displayPath(validPath),
+		MaxChunkSize: MaxUploadChunkSize,
+		ExpiresAt:    session.expiresAt,
+	}, nil
+}
+
+// UploadChunk 在指定偏移写入一个分块,偏移不能超过已接收的字节数,重发较早的分块会覆盖原内容
+// Write a chunk at the given offset; the offset may not exceed the bytes received so far, and resending an earlier chunk overwrites it
+func (s *Service) UploadChunk(req *types.UploadChunkRequest) (*types.UploadChunkResponse, error) {
+	if err := validateUploadChunkRequest(req); err != nil {
+		return nil, err
+	}
+	data, err := base64.StdEncoding.DecodeString(req.Data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64 data: %w", err)
+	}
+	if int64(len(data)) > MaxUploadChunkSize {
+		return nil, fmt.Errorf("chunk of %d bytes exceeds maximum chunk size of %d bytes", len(data), MaxUploadChunkSize)
+	}
+
+	session, err := s.lockUpload(req.UploadID)
+	if err != nil {
+		return nil, err
+	}
+	defer session.mu.Unlock()
+
+	if req.Offset > session.received {
+		return nil, fmt.Errorf("chunk offset %d would leave a gap, the next offset is %d", req.Offset, session.received)
+	}
+	newSize := max(session.received, req.Offset+int64(len(data)))
+	if newSize > MaxFileSize {
+		return nil, fmt.Errorf("upload exceeds maximum allowed file size of %d bytes", MaxFileSize)
+	}
+	if session.size > 0 && newSize > session.size {
+		return nil, fmt.Errorf("upload exceeds its declared size of %d bytes", session.size)
+	}
+	// 暂存文件已计入用量,只检查增长的部分 / The staging file already counts towards usage, so only the growth is checked
+	if s.inDefaultMount(session.path) {
+		if err = s.checkQuota(newSize-session.received, 0); err != nil {
+			return nil, err
+		}
+	}
+
+	file, err := s.fsys.OpenFile(session.tempPath, os.O_WRONLY, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open temporary file: %w", err)
+	}
+	if _, err = file.Seek(req.Offset, io.SeekStart); err == nil {
+		_, err = file.Write(data)
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to write chunk: %w", err)
+	}
+
+	session.received = newSize
+	session.expiresAt = time.Now().Add(UploadSessionTTL)
+	session.timer.Reset(UploadSessionTTL)
+
+	return &types.UploadChunkResponse{
+		Success:   true,
+		Message:   types.MsgChunkReceived,
+		UploadID:  session.id,
+		Received:  session.received,
+		ExpiresAt: session.expiresAt,
+	}, nil
+}
+
+// CommitUpload 校验上传内容并以原子方式重命名到目标路径;校验和不匹配时丢弃上传
+// Verify the uploaded content and atomically rename it onto the target path; the upload is discarded on a checksum mismatch
+func (s *Service) CommitUpload(req *types.CommitUploadRequest) (*types.CommitUploadResponse, error) {
+	if err := validateCommitUploadRequest(req); err != nil {
+		return nil, err
+	}
+
+	session, err := s.lockUpload(req.UploadID)
+	if err != nil {
+		return nil, err
+	}
+	defer session.mu.Unlock()
+
+	if session.size > 0 && session.received != session.size {
+		return nil, fmt.Errorf("upload incomplete: received %d of %d bytes", session.received, session.size)
+	}
+
+	defer s.locks.lock(writeLock(session.path))()
+
+	if err = s.checkUploadTarget(session.path, session.overwrite); err != nil {
+		return nil, err
+	}
+	perm := os.FileMode(DefaultFilePerm)
+	if info, statErr := s.fsys.Stat(session.path); statErr == nil {
+		perm = info.Mode().Perm()
+	}
+
+	file, err := s.fsys.OpenFile(session.tempPath, os.O_RDWR, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open temporary file: %w", err)
+	}
+	sum, err := fileSHA256(file)
+	if err == nil && req.SHA256 != "" && !strings.EqualFold(sum, req.SHA256) {
+		_ = file.Close()
+		s.discardUpload(session)
+		return nil, fmt.Errorf("%s: expected %s, found %s; the upload was discarded", types.ErrUploadChecksumMismatch, req.SHA256, sum)
+	}
+	if err == nil {
+		err = file.Sync()
+	}
+	if err == nil {
+		err = file.Chmod(perm)
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to finalize upload: %w", err)
+	}
+	if err = s.fsys.Rename(session.tempPath, session.path); err != nil {
+		return nil, fmt.Errorf("failed to replace file: %w", err)
+	}
+	s.finishUpload(session)
+
+	info, err := s.fsys.Stat(session.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat file: %w", err)
+	}
+
+	s.logger.Info("upload committed", zap.String("id", session.id), zap.String("path", session.path), zap.Int64("size", info.Size()))
+	return &types.CommitUploadResponse{
+		Success: true,
+		Message: types.MsgUploadCommitted,
+		Path:    s.displayPath(session.path),
+		Size:    info.Size(),
+		Version: fileVersion(info),
+		SHA256:  sum,
+		ModTime: info.ModTime(),
+	}, nil
+}
+
+// lockUpload 查找上传会话并锁定它,会话不存在或已结束时返回错误
+// Look up an upload session and lock it, returning an error when it does not exist or has ended
+func (s *Service) lockUpload(id string) (*uploadSession, error) {
+	s.uploadMu.Lock()
+	session := s.uploads[id]
+	s.uploadMu.Unlock()
+	if session == nil {
+		return nil, fmt.Errorf("%s: %s", types.ErrUploadNotFound, id)
+	}
+
+	session.mu.Lock()
+	if session.done {
+		session.mu.Unlock()
+		return nil, fmt.Errorf("%s: %s", types.ErrUploadNotFound, id)
+	}
+	return session, nil
+}
+
+// expireUpload 定时器到期时丢弃无活动的会话 / Discard an idle session when its timer fires
+func (s *Service) expireUpload(session *uploadSession) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	// 等待锁期间收到的分块会推迟过期 / A chunk received while waiting for the lock postpones the expiry
+	if session.done || time.Now().Before(session.expiresAt) {
+		return
+	}
+	s.discardUpload(session)
+	s.logger.Info("upload expired", zap.String("id", session.id), zap.String("path", session.path))
+}
+
+// discardUpload 结束会话并删除暂存文件,调用方需持有会话锁 / End a session and remove its staging file; the caller must hold the session lock
+func (s *Service) discardUpload(session *uploadSession) {
+	s.finishUpload(session)
+	if err := s.fsys.Remove(session.tempPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		s.logger.Warn("failed to remove upload staging file", zap.String("path", session.tempPath), zap.Error(err))
+	}
+}
+
+// finishUpload 将会话标记为结束并移出会话表,调用方需持有会话锁 / Mark a session as ended and remove it from the table; the caller must hold the session lock
+func (s *Service) finishUpload(session *uploadSession) {
+	session.done = true
+	session.timer.Stop()
+	s.uploadMu.Lock()
+	delete(s.uploads, session.id)
+	s.uploadMu.Unlock()
+}
+
+// checkUploadTarget 检查目标路径不是目录,且在未要求覆盖时不存在 / Check that the target is not a directory and, unless overwriting, does not exist
+func (s *Service) checkUploadTarget(path string, overwrite bool) error {
+	info, err := s.fsys.Stat(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("failed to stat file: %w", err)
+	}
+	if info.IsDir() {
+		return errors.New(types.ErrIsDirectory)
+	}
+	if !overwrite {
+		return fmt.Errorf("file already exists: %s, set overwrite to replace it", s.displayPath(path))
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package sandbox
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"mcp-toolkit/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// uploadChunk 以Base64上传一个分块 / Upload one chunk as base64
+func uploadChunk(t *testing.T, service *Service, id string, offset int64, data string) (*types.UploadChunkResponse, error) {
+	t.Helper()
+	return service.UploadChunk(&types.UploadChunkRequest{
+		UploadID: id,
+		Offset:   offset,
+		Data:     base64.StdEncoding.EncodeToString([]byte(data)),
+	})
+}
+
+func TestChunkedUpload(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+
+	content := "hello, chunked world"
+	begin, err := service.BeginUpload(&types.BeginUploadRequest{Path: "uploads/big.bin", Size: int64(len(content))})
+	require.NoError(t, err)
+	assert.Equal(t, "uploads/big.bin", begin.Path)
+	assert.Equal(t, MaxUploadChunkSize, begin.MaxChunkSize)
+	assert.True(t, begin.ExpiresAt.After(time.Now()))
+
+	resp, err := uploadChunk(t, service, begin.UploadID, 0, "hello, ")
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), resp.Received)
+
+	// 不允许留下空洞 / Gaps are not allowed
+	_, err = uploadChunk(t, service, begin.UploadID, 10, "world")
+	assert.ErrorContains(t, err, "gap")
+
+	// 重发较早的分块覆盖原内容 / Resending an earlier chunk overwrites it
+	_, err = uploadChunk(t, service, begin.UploadID, 0, "HELLO, ")
+	require.NoError(t, err)
+	_, err = uploadChunk(t, service, begin.UploadID, 0, "hello, ")
+	require.NoError(t, err)
+
+	// 提交前目标文件不存在 / The target does not exist before the commit
+	_, err = os.Stat(filepath.Join(tempDir, "uploads", "big.bin"))
+	assert.True(t, os.IsNotExist(err))
+
+	_, err = service.CommitUpload(&types.CommitUploadRequest{UploadID: begin.UploadID})
+	assert.ErrorContains(t, err, "received 7 of 20 bytes")
+
+	resp, err = uploadChunk(t, service, begin.UploadID, 7, "chunked world")
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(content)), resp.Received)
+
+	sum := sha256.Sum256([]byte(content))
+	commit, err := service.CommitUpload(&types.CommitUploadRequest{UploadID: begin.UploadID, SHA256: strings.ToUpper(hex.EncodeToString(sum[:]))})
+	require.NoError(t, err)
+	assert.Equal(t, "uploads/big.bin", commit.Path)
+	assert.Equal(t, int64(len(content)), commit.Size)
+	assert.Equal(t, hex.EncodeToString(sum[:]), commit.SHA256)
+	assert.Equal(t, content, readTestFile(t, filepath.Join(tempDir, "uploads", "big.bin")))
+
+	// 暂存文件已被重命名,会话已结束 / The staging file was renamed and the session has ended
+	entries, err := os.ReadDir(filepath.Join(tempDir, "uploads"))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+	_, err = uploadChunk(t, service, begin.UploadID, 0, "x")
+	assert.ErrorContains(t, err, types.ErrUploadNotFound)
+	assert.Empty(t, service.uploads)
+}
+
+func TestChunkedUploadErrors(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+	writeTestFiles(t, tempDir, map[string]string{"existing.txt": "old", "dir/keep.txt": "x"})
+
+	_, err := service.BeginUpload(&types.BeginUploadRequest{Path: "existing.txt"})
+	assert.ErrorContains(t, err, "already exists")
+	_, err = service.BeginUpload(&types.BeginUploadRequest{Path: "dir", Overwrite: true})
+	assert.ErrorContains(t, err, types.ErrIsDirectory)
+	_, err = service.BeginUpload(&types.BeginUploadRequest{Path: "../escape.bin"})
+	assert.ErrorContains(t, err, types.ErrSandboxViolation)
+	_, err = service.BeginUpload(&types.BeginUploadRequest{Path: "huge.bin", Size: MaxFileSize + 1})
+	assert.Error(t, err)
+
+	begin, err := service.BeginUpload(&types.BeginUploadRequest{Path: "existing.txt", Size: 3, Overwrite: true})
+	require.NoError(t, err)
+	_, err = uploadChunk(t, service, begin.UploadID, 0, "toolong")
+	assert.ErrorContains(t, err, "declared size")
+	_, err = service.UploadChunk(&types.UploadChunkRequest{UploadID: begin.UploadID, Data: "not base64!"})
+	assert.ErrorContains(t, err, "invalid base64")
+	_, err = uploadChunk(t, service, "missing", 0, "x")
+	assert.ErrorContains(t, err, types.ErrUploadNotFound)
+
+	// 校验和不匹配时丢弃上传,原文件保持不变 / A checksum mismatch discards the upload and leaves the original file untouched
+	_, err = uploadChunk(t, service, begin.UploadID, 0, "new")
+	require.NoError(t, err)
+	_, err = service.CommitUpload(&types.CommitUploadRequest{UploadID: begin.UploadID, SHA256: strings.Repeat("0", 64)})
+	assert.ErrorContains(t, err, types.ErrUploadChecksumMismatch)
+	assert.Equal(t, "old", readTestFile(t, filepath.Join(tempDir, "existing.txt")))
+	matches, err := filepath.Glob(filepath.Join(tempDir, ".existing.txt.upload-*"))
+	require.NoError(t, err)
+	assert.Empty(t, matches)
+	_, err = service.CommitUpload(&types.CommitUploadRequest{UploadID: begin.UploadID})
+	assert.ErrorContains(t, err, types.ErrUploadNotFound)
+
+	// 覆盖时保留原文件的权限 / Overwriting keeps the permissions of the original file
+	require.NoError(t, os.Chmod(filepath.Join(tempDir, "existing.txt"), 0600))
+	begin, err = service.BeginUpload(&types.BeginUploadRequest{Path: "existing.txt", Overwrite: true})
+	require.NoError(t, err)
+	_, err = uploadChunk(t, service, begin.UploadID, 0, "new")
+	require.NoError(t, err)
+	_, err = service.CommitUpload(&types.CommitUploadRequest{UploadID: begin.UploadID})
+	require.NoError(t, err)
+	assert.Equal(t, "new", readTestFile(t, filepath.Join(tempDir, "existing.txt")))
+	info, err := os.Stat(filepath.Join(tempDir, "existing.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, fs.FileMode(0600), info.Mode().Perm())
+}
+
+func TestChunkedUploadQuota(t *testing.T) {
+	service, tempDir := setupTestService(t)
+	defer cleanupTestService(t, tempDir)
+	require.NoError(t, service.SetQuotaConfig(types.QuotaConfig{MaxBytes: 10}))
+
+	_, err := service.BeginUpload(&types.BeginUploadRequest{Path: "a.bin", Size: 11})
+	assert.ErrorContains(t, err, types.ErrQuotaExceeded)
+
+	// 大小未声明时在分块到达时检查配额 / Without a declared size the quota is checked as chunks arrive
+	begin, err := service.BeginUpload(&types.BeginUploadRequest{Path: "a.bin"})
+	require.NoError(t, err)
+	_, err = uploadChunk(t, service, begin.UploadID, 0, "123456")
+	require.NoError(t, err)
+	_, err = uploadChunk(t, service, begin.UploadID, 6, "7890X")
+	assert.ErrorContains(t, err, types.ErrQuotaExceeded)
+	// 覆盖已接收的内容不增加用量 / Overwriting received content does not add usage
+	_, err = uploadChunk(t, service, begin.UploadID, 0, "abcdef")
+	require.NoError(t, err)
+}
+
+func TestChunkedUploadExpiry(t *testing.T) {
+	service, memFS := setupMemService(t)
+
+	begin, err := service.BeginUpload(&types.BeginUploadRequest{Path: "late.bin"})
+	require.NoError(t, err)
+	_, err = uploadChunk(t, service, begin.UploadID, 0, "partial")
+	require.NoError(t, err)
+
+	session := service.uploads[begin.UploadID]
+	require.NotNil(t, session)
+	_, err = memFS.Stat(session.tempPath)
+	require.NoError(t, err)
+
+	// 仍在有效期内的会话不会被丢弃 / A session that is still valid is not discarded
+	service.expireUpload(session)
+	_, err = uploadChunk(t, service, begin.UploadID, 7, " more")
+	require.NoError(t, err)
+
+	session.mu.Lock()
+	session.expiresAt = time.Now().Add(-time.Second)
+	session.mu.Unlock()
+	service.expireUpload(session)
+
+	_, err = memFS.Stat(session.tempPath)
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	_, err = service.CommitUpload(&types.CommitUploadRequest{UploadID: begin.UploadID})
+	assert.ErrorContains(t, err, types.ErrUploadNotFound)
+	_, err = memFS.Stat(filepath.Join(memSandboxDir, "late.bin"))
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
@@ -1,8 +1,13 @@
 package sandbox
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"path"
+	"strings"
+	"time"
 
 	"mcp-toolkit/pkg/types"
 )
@@ -43,6 +48,26 @@ func validateReadFileRequest(req *types.ReadFileRequest) error {
 	if req.Path == "" {
 		return errors.New(types.ErrInvalidPath)
 	}
+	if req.StartLine < 0 || req.EndLine < 0 || req.TailLines < 0 {
+		return errors.New("line numbers cannot be negative")
+	}
+	if req.Offset < 0 || req.Length < 0 {
+		return errors.New("offset and length cannot be negative")
+	}
+	if req.Length > MaxReadSize {
+		return fmt.Errorf("length exceeds maximum allowed read size of %d bytes", MaxReadSize)
+	}
+	if req.EndLine > 0 && req.StartLine > req.EndLine {
+		return errors.New("start_line cannot be greater than end_line")
+	}
+
+	// 三种读取模式互斥 / The three read modes are mutually exclusive
+	lineMode := req.StartLine > 0 || req.EndLine > 0
+	tailMode := req.TailLines > 0
+	byteMode := req.Offset > 0
+	if (lineMode && tailMode) || (lineMode && byteMode) || (tailMode && byteMode) {
+		return errors.New("start_line/end_line, tail_lines and offset cannot be combined")
+	}
 	return nil
 }
 
@@ -60,6 +85,85 @@ func validateWriteFileRequest(req *types.WriteFileRequest) error {
 	if len(req.Content) > int(MaxFileSize) {
 		return fmt.Errorf("content size exceeds maximum allowed size of %d bytes", MaxFileSize)
 	}
+	if req.ExpectedSHA256 != "" {
+		if _, err := hex.DecodeString(req.ExpectedSHA256); err != nil || len(req.ExpectedSHA256) != 2*sha256.Size {
+			return errors.New("expected_sha256 must be a 64 character hex string")
+		}
+	}
+	return validateTextEncoding(req.Charset, req.BOM, req.LineEnding)
+}
+
+// validateTextEncoding 验证写入的字符集、BOM和换行符风格 / Validate the charset, BOM and line ending style to write
+func validateTextEncoding(charset string, bom bool, lineEnding string) error {
+	if charset == "" {
+		charset = types.CharsetUTF8
+	}
+	charset, err := normalizeCharset(charset)
+	if err != nil {
+		return err
+	}
+	if bom && charsetBOM(charset) == nil {
+		return fmt.Errorf("byte order marks are only supported for UTF charsets, not %s", charset)
+	}
+	if lineEnding != "" && lineEnding != types.LineEndingLF && lineEnding != types.LineEndingCRLF {
+		return fmt.Errorf("invalid line ending: %s, must be %s or %s", lineEnding, types.LineEndingLF, types.LineEndingCRLF)
+	}
+	return nil
+}
+
+// validateConvertEncodingRequest 验证转换文件编码请求 / Validate convert file encoding request
+func validateConvertEncodingRequest(req *types.ConvertEncodingRequest) error {
+	if req == nil {
+		return errors.New("request cannot be nil")
+	}
+	if req.Path == "" {
+		return errors.New(types.ErrInvalidPath)
+	}
+	if len(req.Path) > MaxPathLength {
+		return fmt.Errorf("path length exceeds maximum allowed length of %d", MaxPathLength)
+	}
+	if req.From != "" {
+		if _, err := normalizeCharset(req.From); err != nil {
+			return err
+		}
+	}
+	if req.To == "" {
+		return errors.New("target charset is required")
+	}
+	return validateTextEncoding(req.To, req.BOM, req.LineEnding)
+}
+
+// validateEditFileRequest 验证编辑文件请求 / Validate edit file request
+func validateEditFileRequest(req *types.EditFileRequest) error {
+	if req == nil {
+		return errors.New("request cannot be nil")
+	}
+	if req.Path == "" {
+		return errors.New(types.ErrInvalidPath)
+	}
+	if len(req.Path) > MaxPathLength {
+		return fmt.Errorf("path length exceeds maximum allowed length of %d", MaxPathLength)
+	}
+	if len(req.Edits) == 0 && req.Patch == "" {
+		return errors.New("either edits or patch must be provided")
+	}
+	if len(req.Edits) > 0 && req.Patch != "" {
+		return errors.New("edits and patch cannot be combined")
+	}
+	if len(req.Edits) > MaxEditCount {
+		return fmt.Errorf("edit count exceeds maximum allowed count of %d", MaxEditCount)
+	}
+	for i, edit := range req.Edits {
+		if edit.OldText == "" {
+			return fmt.Errorf("edit %d: old_text cannot be empty", i+1)
+		}
+		if edit.ExpectedOccurrences < 0 {
+			return fmt.Errorf("edit %d: expected_occurrences cannot be negative", i+1)
+		}
+	}
+	if len(req.Patch) > int(MaxFileSize) {
+		return fmt.Errorf("patch size exceeds maximum allowed size of %d bytes", MaxFileSize)
+	}
 	return nil
 }
 
@@ -116,6 +220,23 @@ func validateListDirRequest(req *types.ListDirRequest) error {
 	if req.Path == "" {
 		return errors.New(types.ErrInvalidPath)
 	}
+	if req.Limit < 0 || req.Limit > MaxListLimit {
+		return fmt.Errorf("limit must be between 0 and %d", MaxListLimit)
+	}
+	if req.SortBy == types.SortByPath {
+		return fmt.Errorf("invalid sort_by %q, must be one of name, size, mtime", req.SortBy)
+	}
+	if err := validateSortOptions(req.SortBy, req.SortOrder); err != nil {
+		return err
+	}
+	if req.Type != "" && req.Type != types.EntryTypeFile && req.Type != types.EntryTypeDirectory {
+		return fmt.Errorf("invalid type %q, must be %q or %q", req.Type, types.EntryTypeFile, types.EntryTypeDirectory)
+	}
+	if req.Pattern != "" {
+		if _, err := path.Match(req.Pattern, ""); err != nil {
+			return fmt.Errorf("invalid glob pattern %q: %w", req.Pattern, err)
+		}
+	}
 	return nil
 }
 
@@ -127,9 +248,103 @@ func validateSearchRequest(req *types.SearchRequest) error {
 	if req.Path == "" {
 		return errors.New(types.ErrInvalidPath)
 	}
+	if req.Pattern == "" && len(req.Include) == 0 {
+		return errors.New("search pattern cannot be empty")
+	}
+	patterns := append([]string{}, req.Include...)
+	if req.Pattern != "" {
+		patterns = append(patterns, req.Pattern)
+	}
+	for _, pattern := range append(patterns, req.Exclude...) {
+		if err := validateGlobPattern(pattern); err != nil {
+			return err
+		}
+	}
+	if req.Type != "" && req.Type != types.EntryTypeFile && req.Type != types.EntryTypeDirectory {
+		return fmt.Errorf("invalid type %q, must be %q or %q", req.Type, types.EntryTypeFile, types.EntryTypeDirectory)
+	}
+	if req.MinSize < 0 || req.MaxSize < 0 {
+		return errors.New("size filters cannot be negative")
+	}
+	if req.MaxSize > 0 && req.MinSize > req.MaxSize {
+		return errors.New("min_size cannot be greater than max_size")
+	}
+	if req.MaxDepth < 0 {
+		return errors.New("max_depth cannot be negative")
+	}
+	if req.MaxResults < 0 || req.MaxResults > MaxFileSearchResults {
+		return fmt.Errorf("max_results must be between 0 and %d", MaxFileSearchResults)
+	}
+	return validateSortOptions(req.SortBy, req.SortOrder)
+}
+
+// validateSortOptions 验证排序选项 / Validate sort options
+func validateSortOptions(sortBy, sortOrder string) error {
+	switch sortBy {
+	case "", types.SortByName, types.SortByPath, types.SortBySize, types.SortByModTime:
+	default:
+		return fmt.Errorf("invalid sort_by %q, must be one of name, path, size, mtime", sortBy)
+	}
+	switch sortOrder {
+	case "", types.SortOrderAsc, types.SortOrderDesc:
+	default:
+		return fmt.Errorf("invalid sort_order %q, must be asc or desc", sortOrder)
+	}
+	return nil
+}
+
+// validateSearchContentRequest 验证内容搜索请求 / Validate content search request
+func validateSearchContentRequest(req *types.SearchContentRequest) error {
+	if req == nil {
+		return errors.New("request cannot be nil")
+	}
+	if len(req.Path) > MaxPathLength {
+		return fmt.Errorf("path length exceeds maximum allowed length of %d", MaxPathLength)
+	}
 	if req.Pattern == "" {
 		return errors.New("search pattern cannot be empty")
 	}
+	if req.ContextLines < 0 || req.ContextLines > MaxContextLines {
+		return fmt.Errorf("context_lines must be between 0 and %d", MaxContextLines)
+	}
+	if req.MaxResults < 0 || req.MaxResults > MaxSearchResults {
+		return fmt.Errorf("max_results must be between 0 and %d", MaxSearchResults)
+	}
+	if req.MaxMatchesPerFile < 0 {
+		return errors.New("max_matches_per_file cannot be negative")
+	}
+	for _, pattern := range append(append([]string{}, req.Include...), req.Exclude...) {
+		if err := validateGlobPattern(pattern); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateDirectoryTreeRequest 验证目录树请求 / Validate directory tree request
+func validateDirectoryTreeRequest(req *types.DirectoryTreeRequest) error {
+	if req == nil {
+		return errors.New("request cannot be nil")
+	}
+	if len(req.Path) > MaxPathLength {
+		return fmt.Errorf("path length exceeds maximum allowed length of %d", MaxPathLength)
+	}
+	if req.MaxDepth < 0 || req.MaxDepth > MaxTreeDepth {
+		return fmt.Errorf("max_depth must be between 0 and %d", MaxTreeDepth)
+	}
+	if req.MaxEntries < 0 || req.MaxEntries > MaxTreeEntries {
+		return fmt.Errorf("max_entries must be between 0 and %d", MaxTreeEntries)
+	}
+	switch req.Format {
+	case "", types.TreeFormatJSON, types.TreeFormatText, types.TreeFormatBoth:
+	default:
+		return fmt.Errorf("invalid format %q, must be json, text or both", req.Format)
+	}
+	for _, pattern := range req.Exclude {
+		if err := validateGlobPattern(pattern); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -155,6 +370,190 @@ func validateBatchDeleteRequest(req *types.BatchDeleteRequest) error {
 	return nil
 }
 
+// validateFileHashRequest 验证文件哈希请求 / Validate file hash request
+func validateFileHashRequest(req *types.FileHashRequest) error {
+	if req == nil {
+		return errors.New("request cannot be nil")
+	}
+	if len(req.Paths) == 0 {
+		return errors.New("paths list cannot be empty")
+	}
+	if len(req.Paths) > MaxHashFiles {
+		return fmt.Errorf("path count exceeds maximum allowed count of %d", MaxHashFiles)
+	}
+	for i, path := range req.Paths {
+		if path == "" {
+			return fmt.Errorf("path at index %d cannot be empty", i)
+		}
+		if len(path) > MaxPathLength {
+			return fmt.Errorf("path at index %d exceeds maximum allowed length of %d", i, MaxPathLength)
+		}
+	}
+	if req.Algorithm != "" {
+		if _, err := newHasher(strings.ToLower(req.Algorithm)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateCompareFilesRequest 验证文件比较请求 / Validate compare files request
+func validateCompareFilesRequest(req *types.CompareFilesRequest) error {
+	if req == nil {
+		return errors.New("request cannot be nil")
+	}
+	if len(req.Pairs) == 0 {
+		return errors.New("pairs list cannot be empty")
+	}
+	if len(req.Pairs) > MaxComparePairs {
+		return fmt.Errorf("pair count exceeds maximum allowed count of %d", MaxComparePairs)
+	}
+	for i, pair := range req.Pairs {
+		if pair.Source == "" || pair.Target == "" {
+			return fmt.Errorf("pair at index %d requires both source and target", i)
+		}
+		if len(pair.Source) > MaxPathLength || len(pair.Target) > MaxPathLength {
+			return fmt.Errorf("pair at index %d exceeds maximum allowed path length of %d", i, MaxPathLength)
+		}
+	}
+	return nil
+}
+
+// validateCreateArchiveRequest 验证创建归档请求 / Validate create archive request
+func validateCreateArchiveRequest(req *types.CreateArchiveRequest) error {
+	if req == nil {
+		return errors.New("request cannot be nil")
+	}
+	if req.Path == "" {
+		return errors.New(types.ErrPathRequired)
+	}
+	if len(req.Path) > MaxPathLength {
+		return fmt.Errorf("path length exceeds maximum allowed length of %d", MaxPathLength)
+	}
+	if len(req.Sources) == 0 {
+		return errors.New("sources list cannot be empty")
+	}
+	for i, source := range req.Sources {
+		if source == "" {
+			return fmt.Errorf("source at index %d cannot be empty", i)
+		}
+		if len(source) > MaxPathLength {
+			return fmt.Errorf("source at index %d exceeds maximum allowed length of %d", i, MaxPathLength)
+		}
+	}
+	for _, pattern := range req.Exclude {
+		if err := validateGlobPattern(pattern); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateExtractArchiveRequest 验证解压归档请求 / Validate extract archive request
+func validateExtractArchiveRequest(req *types.ExtractArchiveRequest) error {
+	if req == nil {
+		return errors.New("request cannot be nil")
+	}
+	if req.Path == "" {
+		return errors.New(types.ErrPathRequired)
+	}
+	if len(req.Path) > MaxPathLength || len(req.Destination) > MaxPathLength {
+		return fmt.Errorf("path length exceeds maximum allowed length of %d", MaxPathLength)
+	}
+	return nil
+}
+
+// validateListTrashRequest 验证列出回收站请求 / Validate list trash request
+func validateListTrashRequest(req *types.ListTrashRequest) error {
+	if req == nil {
+		return errors.New("request cannot be nil")
+	}
+	if len(req.Path) > MaxPathLength {
+		return fmt.Errorf("path length exceeds maximum allowed length of %d", MaxPathLength)
+	}
+	if req.Limit < 0 || req.Limit > MaxListLimit {
+		return fmt.Errorf("limit must be between 0 and %d", MaxListLimit)
+	}
+	return nil
+}
+
+// validateRestoreFromTrashRequest 验证从回收站恢复请求 / Validate restore from trash request
+func validateRestoreFromTrashRequest(req *types.RestoreFromTrashRequest) error {
+	if req == nil {
+		return errors.New("request cannot be nil")
+	}
+	if err := validateStoreID("trash entry", req.ID); err != nil {
+		return err
+	}
+	if len(req.Destination) > MaxPathLength {
+		return fmt.Errorf("destination length exceeds maximum allowed length of %d", MaxPathLength)
+	}
+	return nil
+}
+
+// validateEmptyTrashRequest 验证清空回收站请求 / Validate empty trash request
+func validateEmptyTrashRequest(req *types.EmptyTrashRequest) error {
+	if req == nil {
+		return errors.New("request cannot be nil")
+	}
+	for _, id := range req.IDs {
+		if err := validateStoreID("trash entry", id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateCreateSnapshotRequest 验证创建快照请求 / Validate create snapshot request
+func validateCreateSnapshotRequest(req *types.CreateSnapshotRequest) error {
+	if req == nil {
+		return errors.New("request cannot be nil")
+	}
+	if len(req.Path) > MaxPathLength {
+		return fmt.Errorf("path length exceeds maximum allowed length of %d", MaxPathLength)
+	}
+	for _, pattern := range req.Exclude {
+		if err := validateGlobPattern(pattern); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateDiffSnapshotRequest 验证快照差异请求 / Validate diff snapshot request
+func validateDiffSnapshotRequest(req *types.DiffSnapshotRequest) error {
+	if req == nil {
+		return errors.New("request cannot be nil")
+	}
+	if len(req.Path) > MaxPathLength {
+		return fmt.Errorf("path length exceeds maximum allowed length of %d", MaxPathLength)
+	}
+	return validateStoreID("snapshot", req.ID)
+}
+
+// validateRestoreSnapshotRequest 验证恢复快照请求 / Validate restore snapshot request
+func validateRestoreSnapshotRequest(req *types.RestoreSnapshotRequest) error {
+	if req == nil {
+		return errors.New("request cannot be nil")
+	}
+	if len(req.Path) > MaxPathLength {
+		return fmt.Errorf("path length exceeds maximum allowed length of %d", MaxPathLength)
+	}
+	return validateStoreID("snapshot", req.ID)
+}
+
+// validateStoreID 验证回收站条目、快照或上传会话的ID,ID必须是单个路径段
+// Validate the ID of a trash entry, snapshot or upload session, which must be a single path segment
+func validateStoreID(kind, id string) error {
+	if id == "" {
+		return fmt.Errorf("%s id is required", kind)
+	}
+	if id == "." || id == ".." || strings.ContainsAny(id, `/\`) {
+		return fmt.Errorf("invalid %s id: %s", kind, id)
+	}
+	return nil
+}
+
 // validateFileStatRequest 验证文件状态请求 / Validate file stat request
 func validateFileStatRequest(req *types.FileStatRequest) error {
 	if req == nil {
@@ -247,6 +646,51 @@ func validateDownloadFileRequest(req *types.DownloadFileRequest) error {
 	return nil
 }
 
+// validateBeginUploadRequest 验证开始分块上传请求 / Validate begin chunked upload request
+func validateBeginUploadRequest(req *types.BeginUploadRequest) error {
+	if req == nil {
+		return errors.New("request cannot be nil")
+	}
+	if req.Path == "" {
+		return errors.New(types.ErrInvalidPath)
+	}
+	if len(req.Path) > MaxPathLength {
+		return fmt.Errorf("path length exceeds maximum allowed length of %d", MaxPathLength)
+	}
+	if req.Size < 0 {
+		return errors.New("size cannot be negative")
+	}
+	if req.Size > MaxFileSize {
+		return fmt.Errorf("size exceeds maximum allowed file size of %d bytes", MaxFileSize)
+	}
+	return nil
+}
+
+// validateUploadChunkRequest 验证上传分块请求 / Validate upload chunk request
+func validateUploadChunkRequest(req *types.UploadChunkRequest) error {
+	if req == nil {
+		return errors.New("request cannot be nil")
+	}
+	if err := validateStoreID("upload", req.UploadID); err != nil {
+		return err
+	}
+	if req.Offset < 0 {
+		return errors.New("offset cannot be negative")
+	}
+	if req.Data == "" {
+		return errors.New("data cannot be empty")
+	}
+	return nil
+}
+
+// validateCommitUploadRequest 验证提交分块上传请求 / Validate commit chunked upload request
+func validateCommitUploadRequest(req *types.CommitUploadRequest) error {
+	if req == nil {
+		return errors.New("request cannot be nil")
+	}
+	return validateStoreID("upload", req.UploadID)
+}
+
 // isValidURL 检查URL是否有效 / Check if URL is valid
 func isValidURL(url string) bool {
 	if len(url) == 0 {
@@ -261,3 +705,236 @@ func isValidURL(url string) bool {
 	}
 	return false
 }
+
+// validateLockPathRequest 验证锁定路径请求 / Validate lock path request
+func validateLockPathRequest(req *types.LockPathRequest) error {
+	if req == nil {
+		return errors.New("request cannot be nil")
+	}
+	if req.Path == "" {
+		return errors.New(types.ErrInvalidPath)
+	}
+	if len(req.Path) > MaxPathLength {
+		return fmt.Errorf("path length exceeds maximum allowed length of %d", MaxPathLength)
+	}
+	if req.TTL < 0 || time.Duration(req.TTL)*time.Second > MaxLockTTL {
+		return fmt.Errorf("ttl must be between 0 and %d seconds", int(MaxLockTTL.Seconds()))
+	}
+	if req.Wait < 0 || time.Duration(req.Wait)*time.Second > MaxLockWait {
+		return fmt.Errorf("wait must be between 0 and %d seconds", int(MaxLockWait.Seconds()))
+	}
+	if len(req.Owner) > MaxLockOwnerLength {
+		return fmt.Errorf("owner length exceeds maximum allowed length of %d", MaxLockOwnerLength)
+	}
+	return nil
+}
+
+// validateUnlockPathRequest 验证释放路径锁请求 / Validate unlock path request
+func validateUnlockPathRequest(req *types.UnlockPathRequest) error {
+	if req == nil {
+		return errors.New("request cannot be nil")
+	}
+	if req.LockID == "" {
+		return errors.New("lock_id is required")
+	}
+	return nil
+}
+
+// validateAppendFileRequest 验证追加文件请求 / Validate append file request
+func validateAppendFileRequest(req *types.AppendFileRequest) error {
+	if req == nil {
+		return errors.New("request cannot be nil")
+	}
+	if req.Path == "" {
+		return errors.New(types.ErrInvalidPath)
+	}
+	if len(req.Path) > MaxPathLength {
+		return fmt.Errorf("path length exceeds maximum allowed length of %d", MaxPathLength)
+	}
+	if len(req.Content) > int(MaxFileSize) {
+		return fmt.Errorf("content size exceeds maximum allowed size of %d bytes", MaxFileSize)
+	}
+	return nil
+}
+
+// validateInsertLinesRequest 验证插入行请求 / Validate insert lines request
+func validateInsertLinesRequest(req *types.InsertLinesRequest) error {
+	if req == nil {
+		return errors.New("request cannot be nil")
+	}
+	if req.Path == "" {
+		return errors.New(types.ErrInvalidPath)
+	}
+	if len(req.Path) > MaxPathLength {
+		return fmt.Errorf("path length exceeds maximum allowed length of %d", MaxPathLength)
+	}
+	if req.Line < 1 {
+		return errors.New("line must be at least 1")
+	}
+	if req.Content == "" {
+		return errors.New("content is required")
+	}
+	if len(req.Content) > int(MaxFileSize) {
+		return fmt.Errorf("content size exceeds maximum allowed size of %d bytes", MaxFileSize)
+	}
+	return nil
+}
+
+// validateDeleteLinesRequest 验证删除行请求 / Validate delete lines request
+func validateDeleteLinesRequest(req *types.DeleteLinesRequest) error {
+	if req == nil {
+		return errors.New("request cannot be nil")
+	}
+	if req.Path == "" {
+		return errors.New(types.ErrInvalidPath)
+	}
+	if len(req.Path) > MaxPathLength {
+		return fmt.Errorf("path length exceeds maximum allowed length of %d", MaxPathLength)
+	}
+	if req.StartLine < 1 {
+		return errors.New("start_line must be at least 1")
+	}
+	if req.EndLine != 0 && req.EndLine < req.StartLine {
+		return fmt.Errorf("end_line %d is before start_line %d", req.EndLine, req.StartLine)
+	}
+	return nil
+}
+
+// validateTruncateFileRequest 验证截断文件请求 / Validate truncate file request
+func validateTruncateFileRequest(req *types.TruncateFileRequest) error {
+	if req == nil {
+		return errors.New("request cannot be nil")
+	}
+	if req.Path == "" {
+		return errors.New(types.ErrInvalidPath)
+	}
+	if len(req.Path) > MaxPathLength {
+		return fmt.Errorf("path length exceeds maximum allowed length of %d", MaxPathLength)
+	}
+	if req.Size < 0 {
+		return errors.New("size cannot be negative")
+	}
+	if req.Size > MaxFileSize {
+		return fmt.Errorf("size exceeds maximum allowed size of %d bytes", MaxFileSize)
+	}
+	return nil
+}
+
+// validateDiffFilesRequest 验证比较文件差异请求 / Validate diff files request
+func validateDiffFilesRequest(req *types.DiffFilesRequest) error {
+	if req == nil {
+		return errors.New("request cannot be nil")
+	}
+	sides := []struct {
+		name    string
+		path    string
+		content *string
+	}{
+		{name: "old", path: req.OldPath, content: req.OldContent},
+		{name: "new", path: req.NewPath, content: req.NewContent},
+	}
+	for _, side := range sides {
+		if side.path == "" && side.content == nil {
+			return fmt.Errorf("either %s_path or %s_content must be provided", side.name, side.name)
+		}
+		if len(side.path) > MaxPathLength {
+			return fmt.Errorf("%s_path length exceeds maximum allowed length of %d", side.name, MaxPathLength)
+		}
+		if side.content != nil && len(*side.content) > int(MaxFileSize) {
+			return fmt.Errorf("%s_content size exceeds maximum allowed size of %d bytes", side.name, MaxFileSize)
+		}
+	}
+	if req.Context != nil && (*req.Context < 0 || *req.Context > MaxDiffContext) {
+		return fmt.Errorf("context must be between 0 and %d", MaxDiffContext)
+	}
+	return nil
+}
+
+// validateDataFormat 验证数据格式和CSV选项 / Validate the data format and CSV options
+func validateDataFormat(format, delimiter string) error {
+	switch format {
+	case "", types.DataFormatJSON, types.DataFormatYAML, types.DataFormatTOML, types.DataFormatCSV:
+	default:
+		return fmt.Errorf("%s: %q, must be one of json, yaml, toml or csv", types.ErrUnsupportedDataFormat, format)
+	}
+	if delimiter != "" {
+		if _, err := parseDataDelimiter(delimiter); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateQueryDataRequest 验证数据查询请求 / Validate query data request
+func validateQueryDataRequest(req *types.QueryDataRequest) error {
+	if req == nil {
+		return errors.New("request cannot be nil")
+	}
+	if req.Path == "" {
+		return errors.New(types.ErrInvalidPath)
+	}
+	if len(req.Path) > MaxPathLength {
+		return fmt.Errorf("path length exceeds maximum allowed length of %d", MaxPathLength)
+	}
+	if req.Query == "" {
+		return errors.New("query is required")
+	}
+	if req.Limit < 0 || req.Limit > MaxDataQueryLimit {
+		return fmt.Errorf("limit must be between 0 and %d", MaxDataQueryLimit)
+	}
+	return validateDataFormat(req.Format, req.Delimiter)
+}
+
+// validateUpdateDataRequest 验证数据更新请求 / Validate update data request
+func validateUpdateDataRequest(req *types.UpdateDataRequest) error {
+	if req == nil {
+		return errors.New("request cannot be nil")
+	}
+	if req.Path == "" {
+		return errors.New(types.ErrInvalidPath)
+	}
+	if len(req.Path) > MaxPathLength {
+		return fmt.Errorf("path length exceeds maximum allowed length of %d", MaxPathLength)
+	}
+	if req.Query == "" {
+		return errors.New("query is required")
+	}
+	switch req.Op {
+	case types.DataOpSet:
+		if len(req.Value) == 0 {
+			return errors.New("value is required for set")
+		}
+	case types.DataOpDelete:
+		if len(req.Value) > 0 {
+			return errors.New("value must not be set for delete")
+		}
+	default:
+		return fmt.Errorf("invalid op %q: must be set or delete", req.Op)
+	}
+	return validateDataFormat(req.Format, req.Delimiter)
+}
+
+// validateDiskUsageRequest 验证磁盘用量分析请求 / Validate disk usage request
+func validateDiskUsageRequest(req *types.DiskUsageRequest) error {
+	if req == nil {
+		return errors.New("request cannot be nil")
+	}
+	if len(req.Path) > MaxPathLength {
+		return fmt.Errorf("path length exceeds maximum allowed length of %d", MaxPathLength)
+	}
+	if req.Top < 0 || req.Top > MaxDiskUsageTop {
+		return fmt.Errorf("top must be between 0 and %d", MaxDiskUsageTop)
+	}
+	if req.MaxDepth < 0 {
+		return errors.New("max_depth must not be negative")
+	}
+	if req.Timeout < 0 || time.Duration(req.Timeout)*time.Second > MaxDiskUsageTimeout {
+		return fmt.Errorf("timeout must be between 0 and %d seconds", int(MaxDiskUsageTimeout.Seconds()))
+	}
+	for _, pattern := range req.Exclude {
+		if err := validateGlobPattern(pattern); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -60,6 +60,24 @@ func getVersion() string {
 	return version
 }
 
+// mountFlags 可重复的-mount参数 / Repeatable -mount flag
+type mountFlags []types.MountConfig
+
+// String 实现flag.Value / Implement flag.Value
+func (m *mountFlags) String() string {
+	return fmt.Sprint(*m)
+}
+
+// Set 解析一个挂载说明 / Parse one mount spec
+func (m *mountFlags) Set(value string) error {
+	config, err := types.ParseMountConfig(value)
+	if err != nil {
+		return err
+	}
+	*m = append(*m, config)
+	return nil
+}
+
 // initLogger 初始化日志记录器 / Initialize logger
 func initLogger() (*zap.Logger, error) {
 	config := zap.NewProductionConfig()
@@ -80,6 +98,23 @@ func main() {
 	showVersion := flag.Bool("version", false, "显示版本信息 / Show version information")
 	sandboxDir := flag.String("sandbox", types.GetDefaultSandboxDir(), "沙箱目录路径 / Sandbox directory path")
 	transportType := flag.String("transport", "stdio", "传输类型: stdio, http, sse / Transport type: stdio, http, sse")
+	symlinkPolicy := flag.String("symlink-policy", string(types.SymlinkPolicyFollow), "符号链接策略: follow, deny, allow / Symlink policy: follow, deny, allow")
+
+	// 回收站参数 / Trash parameters
+	trashEnabled := flag.Bool("trash", false, "删除时移入沙箱回收站 / Move deleted entries into the sandbox trash")
+	trashMaxAge := flag.Int("trash-max-age", 7*24*3600, "回收站条目保留时间(秒,0为不限制) / Retention time of trash entries (seconds, 0 for no limit)")
+	trashMaxSize := flag.Int64("trash-max-size", 1<<30, "回收站最大总大小(字节,0为不限制) / Maximum total size of the trash (bytes, 0 for no limit)")
+
+	// 磁盘配额参数 / Disk quota parameters
+	quotaMaxBytes := flag.Int64("quota-max-bytes", 0, "沙箱文件总大小上限(字节,0为不限制) / Maximum total size of files in the sandbox (bytes, 0 for no limit)")
+	quotaMaxFiles := flag.Int64("quota-max-files", 0, "沙箱文件和目录总数上限(0为不限制) / Maximum number of files and directories in the sandbox (0 for no limit)")
+
+	// 挂载参数 / Mount parameters
+	var mounts mountFlags
+	flag.Var(&mounts, "mount", "命名挂载,格式为 名称=宿主路径[:ro|rw],可重复,通过\"名称:相对路径\"访问 / Named mount as name=/host/path[:ro|rw], repeatable, addressed as \"name:relative/path\"")
+
+	// 访问控制参数 / Access control parameters
+	aclConfig := flag.String("acl-config", "", "路径访问控制规则的JSON文件 / JSON file with path access control rules")
 
 	// HTTP 基础参数 / HTTP basic parameters
 	httpHost := flag.String("http-host", "127.0.0.1", "HTTP监听地址 / HTTP listen address")
@@ -147,6 +182,43 @@ func main() {
 		logger.Fatal("failed to create sandbox service", zap.Error(err))
 	}
 
+	if err = sandboxService.SetSymlinkPolicy(types.SymlinkPolicy(*symlinkPolicy)); err != nil {
+		logger.Fatal("invalid symlink policy", zap.Error(err))
+	}
+
+	err = sandboxService.SetTrashConfig(types.TrashConfig{
+		Enabled: *trashEnabled,
+		MaxAge:  *trashMaxAge,
+		MaxSize: *trashMaxSize,
+	})
+	if err != nil {
+		logger.Fatal("invalid trash configuration", zap.Error(err))
+	}
+
+	err = sandboxService.SetQuotaConfig(types.QuotaConfig{
+		MaxBytes: *quotaMaxBytes,
+		MaxFiles: *quotaMaxFiles,
+	})
+	if err != nil {
+		logger.Fatal("invalid quota configuration", zap.Error(err))
+	}
+
+	for _, mount := range mounts {
+		if err = sandboxService.AddMount(mount); err != nil {
+			logger.Fatal("invalid mount", zap.Error(err))
+		}
+	}
+
+	if *aclConfig != "" {
+		accessConfig, err := sandbox.LoadAccessConfig(*aclConfig)
+		if err != nil {
+			logger.Fatal("failed to load access rules", zap.Error(err))
+		}
+		if err = sandboxService.SetAccessConfig(accessConfig); err != nil {
+			logger.Fatal("invalid access rules", zap.Error(err))
+		}
+	}
+
 	logger.Info("sandbox service initialized", zap.String("sandbox_dir", absSandboxDir))
 
 	// 创建MCP服务器 / Create MCP server
@@ -157,15 +229,19 @@ func main() {
 		},
 		&mcp.ServerOptions{
 			Capabilities: &mcp.ServerCapabilities{
-				Tools: &mcp.ToolCapabilities{},
+				Tools:     &mcp.ToolCapabilities{},
+				Resources: &mcp.ResourceCapabilities{Subscribe: true},
 			},
+			SubscribeHandler:   sandboxService.HandleSubscribe,
+			UnsubscribeHandler: sandboxService.HandleUnsubscribe,
 		},
 	)
 
-	// 注册沙箱工具 / Register sandbox tools
+	// 注册沙箱工具和文件资源 / Register sandbox tools and file resources
 	sandboxService.RegisterTools(mcpServer)
+	sandboxService.RegisterResources(mcpServer)
 
-	logger.Info("MCP tools registered successfully")
+	logger.Info("MCP tools and resources registered successfully")
 
 	// 创建上下文和信号处理 / Create context and signal handling
 	ctx, cancel := context.WithCancel(context.Background())
@@ -211,8 +287,9 @@ func main() {
 				errChan <- fmt.Errorf("failed to create HTTP transport server: %w", err)
 				return
 			}
-			// 注册工具到工具注册表 / Register tools to tool registry
+			// 注册工具和资源到注册表 / Register tools and resources to the registries
 			sandboxService.RegisterToolsToRegistry(httpServer.GetToolRegistry())
+			sandboxService.RegisterResourcesToRegistry(httpServer.GetResourceRegistry())
 			errChan <- httpServer.Start(ctx, mcpServer)
 
 		case types.TransportSSE:
@@ -231,8 +308,9 @@ func main() {
 				errChan <- fmt.Errorf("failed to create SSE transport server: %w", err)
 				return
 			}
-			// 注册工具到工具注册表 / Register tools to tool registry
+			// 注册工具和资源到注册表 / Register tools and resources to the registries
 			sandboxService.RegisterToolsToRegistry(sseServer.GetToolRegistry())
+			sandboxService.RegisterResourcesToRegistry(sseServer.GetResourceRegistry())
 			errChan <- sseServer.Start(ctx, mcpServer)
 
 		case types.TransportStdio:
@@ -18,6 +18,7 @@ var pretouchTypes = []any{
 	Tool{},
 	CallToolResponse{},
 	Content{},
+	ResourceContents{},
 }
 
 // PretouchAll 预热所有客户端类型 / Preheat all client types
@@ -92,6 +92,27 @@ type Content struct {
 	// Text 文本内容(当type为text时) / Text content (when type is text)
 	Text string `json:"text,omitempty"`
 
-	// Data 数据内容(当type为其他类型时) / Data content (when type is other)
+	// Data 数据内容(当type为其他类型时,图片为base64字符串) / Data content (when type is other, a base64 string for images)
 	Data interface{} `json:"data,omitempty"`
+
+	// MimeType MIME类型(当type为image时) / MIME type (when type is image)
+	MimeType string `json:"mimeType,omitempty"`
+
+	// Resource 嵌入资源(当type为resource时) / Embedded resource (when type is resource)
+	Resource *ResourceContents `json:"resource,omitempty"`
+}
+
+// ResourceContents 资源内容 / Resource contents
+type ResourceContents struct {
+	// URI 资源URI / Resource URI
+	URI string `json:"uri"`
+
+	// MimeType MIME类型 / MIME type
+	MimeType string `json:"mimeType,omitempty"`
+
+	// Text 文本内容 / Text content
+	Text string `json:"text,omitempty"`
+
+	// Blob base64编码的二进制内容 / Base64-encoded binary content
+	Blob string `json:"blob,omitempty"`
 }
@@ -41,6 +41,11 @@
 //   - 自动 panic recovery 保护
 //   - 支持动态工具注册
 //
+// ResourceRegistry：
+//   - 资源注册表，将 resources/* 请求转发给 ResourceProvider
+//   - 按订阅者跟踪资源订阅：HTTP 传输按会话，SSE 传输按连接
+//   - 资源变化时通过会话或连接的 SSE 流发送 notifications/resources/updated
+//
 // # 使用示例
 //
 // 创建 HTTP 传输服务器：
@@ -58,8 +63,9 @@
 //	    log.Fatal(err)
 //	}
 //
-//	// 注册工具
+//	// 注册工具和资源
 //	fsService.RegisterToolsToRegistry(server.GetToolRegistry())
+//	fsService.RegisterResourcesToRegistry(server.GetResourceRegistry())
 //
 //	// 启动服务器
 //	err = server.Start(ctx, mcpServer)
@@ -80,8 +86,9 @@
 //	    log.Fatal(err)
 //	}
 //
-//	// 注册工具
+//	// 注册工具和资源
 //	fsService.RegisterToolsToRegistry(server.GetToolRegistry())
+//	fsService.RegisterResourcesToRegistry(server.GetResourceRegistry())
 //
 //	// 启动服务器
 //	err = server.Start(ctx, mcpServer)
@@ -114,6 +121,9 @@
 //   - initialize：初始化连接
 //   - tools/list：列出所有可用工具
 //   - tools/call：调用指定工具
+//   - resources/list、resources/templates/list、resources/read：列出、读取资源
+//   - resources/subscribe、resources/unsubscribe：订阅资源变化，HTTP 传输需要启用会话和 SSE，
+//     SSE 传输需要在消息端点上用 connectionId 查询参数指定连接
 //
 // # 错误处理
 //
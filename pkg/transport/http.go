@@ -36,10 +36,11 @@ import (
 // HTTPSession HTTP会话 / HTTP session
 // 使用互斥锁保护并发访问 / Use mutex to protect concurrent access
 type HTTPSession struct {
-	ID        string
-	CreatedAt time.Time
-	lastUsed  time.Time
-	mu        sync.RWMutex // 保护 lastUsed 字段 / Protect lastUsed field
+	ID            string
+	CreatedAt     time.Time
+	lastUsed      time.Time
+	mu            sync.RWMutex // 保护 lastUsed 字段 / Protect lastUsed field
+	notifications chan []byte  // 待通过SSE流发送的服务器通知 / Server notifications waiting to be sent over the SSE stream
 }
 
 // GetLastUsed 获取最后使用时间 / Get last used time
@@ -58,21 +59,30 @@ func (s *HTTPSession) UpdateLastUsed() {
 
 // HTTPTransportServer HTTP传输服务器 / HTTP transport server
 type HTTPTransportServer struct {
-	config       *types.HTTPConfig
-	logger       *zap.Logger
-	server       *http.Server
-	mcpServer    *mcp.Server
-	toolRegistry *ToolRegistry
-	sessions     sync.Map // map[string]*HTTPSession
-	sessionMu    sync.RWMutex
-	rateLimiter  *RateLimiter // 请求频率限制器 / Request rate limiter
+	config           *types.HTTPConfig
+	logger           *zap.Logger
+	server           *http.Server
+	mcpServer        *mcp.Server
+	toolRegistry     *ToolRegistry
+	resourceRegistry *ResourceRegistry
+	sessions         sync.Map // map[string]*HTTPSession
+	sessionMu        sync.RWMutex
+	rateLimiter      *RateLimiter // 请求频率限制器 / Request rate limiter
 }
 
+// sessionNotificationBuffer 每个会话缓冲的通知数量 / Number of notifications buffered per session
+const sessionNotificationBuffer = 100
+
 // GetToolRegistry 获取工具注册表 / Get tool registry
 func (s *HTTPTransportServer) GetToolRegistry() *ToolRegistry {
 	return s.toolRegistry
 }
 
+// GetResourceRegistry 获取资源注册表 / Get resource registry
+func (s *HTTPTransportServer) GetResourceRegistry() *ResourceRegistry {
+	return s.resourceRegistry
+}
+
 // generateSessionID 生成会话ID / Generate session ID
 func (s *HTTPTransportServer) generateSessionID() (string, error) {
 	bytes := make([]byte, 16)
@@ -91,9 +101,10 @@ func (s *HTTPTransportServer) createSession() (*HTTPSession, error) {
 
 	now := time.Now()
 	session := &HTTPSession{
-		ID:        sessionID,
-		CreatedAt: now,
-		lastUsed:  now,
+		ID:            sessionID,
+		CreatedAt:     now,
+		lastUsed:      now,
+		notifications: make(chan []byte, sessionNotificationBuffer),
 	}
 
 	s.sessions.Store(sessionID, session)
@@ -120,6 +131,7 @@ func (s *HTTPTransportServer) getSession(sessionID string) (*HTTPSession, error)
 // deleteSession 删除会话 / Delete session
 func (s *HTTPTransportServer) deleteSession(sessionID string) {
 	s.sessions.Delete(sessionID)
+	s.resourceRegistry.RemoveSubscriber(sessionID)
 	s.logger.Info("deleted session", zap.String("session_id", sessionID))
 }
 
@@ -219,11 +231,13 @@ func NewHTTPTransportServer(config *types.HTTPConfig, logger *zap.Logger) (*HTTP
 	registry.SetLogger(logger)
 
 	server := &HTTPTransportServer{
-		config:       config,
-		logger:       logger,
-		mcpServer:    nil, // 将在Start方法中设置 / Will be set in Start method
-		toolRegistry: registry,
+		config:           config,
+		logger:           logger,
+		mcpServer:        nil, // 将在Start方法中设置 / Will be set in Start method
+		toolRegistry:     registry,
+		resourceRegistry: NewResourceRegistry(),
 	}
+	server.resourceRegistry.SetNotifier(server.notifySession)
 
 	// 如果启用频率限制，创建限制器 / If rate limiting enabled, create limiter
 	if config.EnableRateLimit {
@@ -443,6 +457,14 @@ func (s *HTTPTransportServer) handleSSEStream(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	// 带会话的流同时接收该会话的服务器通知 / A stream with a session also receives the server notifications of that session
+	var notifications chan []byte
+	if s.config.EnableSessionManagement {
+		if session, err := s.getSession(r.Header.Get("Mcp-Session-Id")); err == nil {
+			notifications = session.notifications
+		}
+	}
+
 	s.logger.Info("new SSE stream connection",
 		zap.String("remote_addr", r.RemoteAddr))
 
@@ -456,6 +478,14 @@ func (s *HTTPTransportServer) handleSSEStream(w http.ResponseWriter, r *http.Req
 			s.logger.Info("SSE stream connection closed",
 				zap.String("remote_addr", r.RemoteAddr))
 			return
+		case msg := <-notifications:
+			// 发送通知事件 / Send notification event
+			if _, err := fmt.Fprintf(w, "data: %s\n\n", msg); err != nil {
+				s.logger.Error("failed to send notification",
+					zap.Error(err))
+				return
+			}
+			flusher.Flush()
 		case <-ticker.C:
 			// 发送心跳事件 / Send heartbeat event
 			_, err := fmt.Fprintf(w, ": heartbeat\n\n")
@@ -594,6 +624,13 @@ func (s *HTTPTransportServer) handleMCPRequestInternal(w http.ResponseWriter, r
 			s.sendErrorResponse(w, mcpReq.ID, types.MCPErrorCodeInternalError, err.Error(), nil)
 			return nil
 		}
+	case "resources/list", "resources/templates/list", "resources/read", "resources/subscribe", "resources/unsubscribe":
+		result, err = s.handleResourceRequest(mcpReq, session)
+		if err != nil {
+			code, message, data := rpcError(err)
+			s.sendErrorResponse(w, mcpReq.ID, code, message, data)
+			return nil
+		}
 	default:
 		s.sendErrorResponse(w, mcpReq.ID, types.MCPErrorCodeMethodNotFound, types.MCPErrorMsgMethodNotFound, mcpReq.Method)
 		return nil
@@ -617,9 +654,7 @@ func (s *HTTPTransportServer) handleMCPRequestInternal(w http.ResponseWriter, r
 func (s *HTTPTransportServer) handleInitialize(req types.MCPRequest, session *HTTPSession) interface{} {
 	return map[string]interface{}{
 		"protocolVersion": types.ProtocolVersion,
-		"capabilities": map[string]interface{}{
-			"tools": map[string]interface{}{},
-		},
+		"capabilities":    s.capabilities(),
 		"serverInfo": map[string]interface{}{
 			"name":    types.ServerName,
 			"version": types.ServerVersion,
@@ -627,6 +662,81 @@ func (s *HTTPTransportServer) handleInitialize(req types.MCPRequest, session *HT
 	}
 }
 
+// capabilities 返回服务器能力,订阅需要会话和SSE流来投递通知
+// Return the server capabilities; subscriptions need sessions and an SSE stream to deliver notifications
+func (s *HTTPTransportServer) capabilities() map[string]interface{} {
+	capabilities := map[string]interface{}{
+		"tools": map[string]interface{}{},
+	}
+	subscribe := s.config.EnableSessionManagement && s.config.EnableSSE
+	if resources := s.resourceRegistry.resourceCapabilities(subscribe); resources != nil {
+		capabilities["resources"] = resources
+	}
+	return capabilities
+}
+
+// handleResourceRequest 处理resources/*请求 / Handle resources/* requests
+func (s *HTTPTransportServer) handleResourceRequest(req types.MCPRequest, session *HTTPSession) (interface{}, error) {
+	switch req.Method {
+	case "resources/list":
+		var params mcp.ListResourcesParams
+		if err := decodeParams(req.Params, &params); err != nil {
+			return nil, err
+		}
+		return s.resourceRegistry.ListResources(&params)
+	case "resources/templates/list":
+		var params mcp.ListResourceTemplatesParams
+		if err := decodeParams(req.Params, &params); err != nil {
+			return nil, err
+		}
+		return s.resourceRegistry.ListResourceTemplates(&params)
+	case "resources/read":
+		var params mcp.ReadResourceParams
+		if err := decodeParams(req.Params, &params); err != nil {
+			return nil, err
+		}
+		return s.resourceRegistry.ReadResource(&params)
+	}
+
+	// 订阅按会话跟踪 / Subscriptions are tracked per session
+	if session == nil || !s.config.EnableSSE {
+		return nil, errors.New("resource subscriptions require session management and SSE")
+	}
+	var params mcp.SubscribeParams
+	if err := decodeParams(req.Params, &params); err != nil {
+		return nil, err
+	}
+	var err error
+	if req.Method == "resources/subscribe" {
+		err = s.resourceRegistry.Subscribe(session.ID, params.URI)
+	} else {
+		err = s.resourceRegistry.Unsubscribe(session.ID, params.URI)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{}, nil
+}
+
+// notifySession 将资源更新通知放入会话的SSE流队列 / Queue a resource update notification on the SSE stream of a session
+func (s *HTTPTransportServer) notifySession(sessionID string, params *mcp.ResourceUpdatedNotificationParams) {
+	value, ok := s.sessions.Load(sessionID)
+	if !ok {
+		return
+	}
+	msg, err := resourceUpdatedMessage(params)
+	if err != nil {
+		s.logger.Error("failed to marshal notification", zap.Error(err))
+		return
+	}
+	select {
+	case value.(*HTTPSession).notifications <- msg:
+	default:
+		s.logger.Warn("notification queue full for session",
+			zap.String("session_id", sessionID))
+	}
+}
+
 // handleToolsList 处理工具列表请求 / Handle tools list request
 func (s *HTTPTransportServer) handleToolsList(req types.MCPRequest) interface{} {
 	// 解析请求参数 / Parse request params
@@ -699,19 +809,7 @@ func (s *HTTPTransportServer) handleToolsCall(req types.MCPRequest) (interface{}
 	}
 
 	// 转换结果为响应格式 / Convert result to response format
-	content := make([]map[string]interface{}, len(result.Content))
-	for i, c := range result.Content {
-		if textContent, ok := c.(*mcp.TextContent); ok {
-			content[i] = map[string]interface{}{
-				"type": "text",
-				"text": textContent.Text,
-			}
-		}
-	}
-
-	return map[string]interface{}{
-		"content": content,
-	}, nil
+	return toolCallResultPayload(result)
 }
 
 // sendJSONResponse 发送JSON响应 / Send JSON response
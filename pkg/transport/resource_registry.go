@@ -0,0 +1,254 @@
+// Copyright 2024 MCP Toolkit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"mcp-toolkit/pkg/types"
+	"mcp-toolkit/pkg/utils/json"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// errNoResourceProvider 未注册资源提供者 / No resource provider has been registered
+var errNoResourceProvider = errors.New("resources are not supported")
+
+// ResourceProvider 资源提供者,负责列出、读取和监视资源 / Resource provider that lists, reads and watches resources
+type ResourceProvider interface {
+	// ListResources 分页列出资源 / List resources page by page
+	ListResources(params *mcp.ListResourcesParams) (*mcp.ListResourcesResult, error)
+	// ListResourceTemplates 列出资源URI模板 / List resource URI templates
+	ListResourceTemplates(params *mcp.ListResourceTemplatesParams) (*mcp.ListResourceTemplatesResult, error)
+	// ReadResource 读取资源内容 / Read the content of a resource
+	ReadResource(params *mcp.ReadResourceParams) (*mcp.ReadResourceResult, error)
+	// SubscribeResource 开始监视资源,每个订阅调用一次 / Start watching a resource, called once per subscription
+	SubscribeResource(uri string) error
+	// UnsubscribeResource 结束一个订阅 / End one subscription
+	UnsubscribeResource(uri string) error
+}
+
+// ResourceNotifier 向单个订阅者发送资源更新通知 / Deliver a resource update notification to a single subscriber
+type ResourceNotifier func(subscriber string, params *mcp.ResourceUpdatedNotificationParams)
+
+// ResourceRegistry 资源注册表,转发资源请求并按订阅者(会话或连接)跟踪订阅
+// Resource registry forwarding resource requests and tracking subscriptions per subscriber (session or connection)
+type ResourceRegistry struct {
+	mu            sync.RWMutex
+	provider      ResourceProvider
+	notifier      ResourceNotifier
+	subscriptions map[string]map[string]bool // uri -> subscriber -> true
+}
+
+// NewResourceRegistry 创建资源注册表 / Create resource registry
+func NewResourceRegistry() *ResourceRegistry {
+	return &ResourceRegistry{
+		subscriptions: make(map[string]map[string]bool),
+	}
+}
+
+// SetProvider 设置资源提供者 / Set the resource provider
+func (r *ResourceRegistry) SetProvider(provider ResourceProvider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.provider = provider
+}
+
+// HasProvider 是否已注册资源提供者 / Whether a resource provider is registered
+func (r *ResourceRegistry) HasProvider() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.provider != nil
+}
+
+// SetNotifier 设置订阅通知的投递方式,由传输服务器调用 / Set how subscription notifications are delivered, called by the transport server
+func (r *ResourceRegistry) SetNotifier(notifier ResourceNotifier) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.notifier = notifier
+}
+
+// getProvider 获取资源提供者 / Get the resource provider
+func (r *ResourceRegistry) getProvider() (ResourceProvider, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.provider == nil {
+		return nil, errNoResourceProvider
+	}
+	return r.provider, nil
+}
+
+// ListResources 列出资源 / List resources
+func (r *ResourceRegistry) ListResources(params *mcp.ListResourcesParams) (*mcp.ListResourcesResult, error) {
+	provider, err := r.getProvider()
+	if err != nil {
+		return nil, err
+	}
+	return provider.ListResources(params)
+}
+
+// ListResourceTemplates 列出资源模板 / List resource templates
+func (r *ResourceRegistry) ListResourceTemplates(params *mcp.ListResourceTemplatesParams) (*mcp.ListResourceTemplatesResult, error) {
+	provider, err := r.getProvider()
+	if err != nil {
+		return nil, err
+	}
+	return provider.ListResourceTemplates(params)
+}
+
+// ReadResource 读取资源 / Read a resource
+func (r *ResourceRegistry) ReadResource(params *mcp.ReadResourceParams) (*mcp.ReadResourceResult, error) {
+	provider, err := r.getProvider()
+	if err != nil {
+		return nil, err
+	}
+	return provider.ReadResource(params)
+}
+
+// Subscribe 为订阅者订阅资源,重复订阅不会再次通知提供者 / Subscribe a subscriber to a resource; repeated subscriptions do not reach the provider again
+func (r *ResourceRegistry) Subscribe(subscriber, uri string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.provider == nil {
+		return errNoResourceProvider
+	}
+	if r.subscriptions[uri][subscriber] {
+		return nil
+	}
+	if err := r.provider.SubscribeResource(uri); err != nil {
+		return err
+	}
+	if r.subscriptions[uri] == nil {
+		r.subscriptions[uri] = make(map[string]bool)
+	}
+	r.subscriptions[uri][subscriber] = true
+	return nil
+}
+
+// Unsubscribe 取消订阅者对资源的订阅 / Cancel a subscriber's subscription to a resource
+func (r *ResourceRegistry) Unsubscribe(subscriber, uri string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.provider == nil {
+		return errNoResourceProvider
+	}
+	if !r.subscriptions[uri][subscriber] {
+		return nil
+	}
+	r.removeLocked(subscriber, uri)
+	return nil
+}
+
+// RemoveSubscriber 取消订阅者的全部订阅,在会话或连接结束时调用 / Cancel every subscription of a subscriber, called when its session or connection ends
+func (r *ResourceRegistry) RemoveSubscriber(subscriber string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for uri, subscribers := range r.subscriptions {
+		if subscribers[subscriber] {
+			r.removeLocked(subscriber, uri)
+		}
+	}
+}
+
+// removeLocked 删除单个订阅,调用方需持有锁 / Remove a single subscription; the caller must hold the lock
+func (r *ResourceRegistry) removeLocked(subscriber, uri string) {
+	delete(r.subscriptions[uri], subscriber)
+	if len(r.subscriptions[uri]) == 0 {
+		delete(r.subscriptions, uri)
+	}
+	_ = r.provider.UnsubscribeResource(uri)
+}
+
+// Subscriptions 返回订阅者订阅的资源数量 / Return how many resources a subscriber is subscribed to
+func (r *ResourceRegistry) Subscriptions(subscriber string) int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	count := 0
+	for _, subscribers := range r.subscriptions {
+		if subscribers[subscriber] {
+			count++
+		}
+	}
+	return count
+}
+
+// NotifyUpdated 通知资源的所有订阅者资源已变化 / Notify every subscriber of a resource that it has changed
+func (r *ResourceRegistry) NotifyUpdated(uri string) {
+	r.mu.RLock()
+	notifier := r.notifier
+	subscribers := make([]string, 0, len(r.subscriptions[uri]))
+	for subscriber := range r.subscriptions[uri] {
+		subscribers = append(subscribers, subscriber)
+	}
+	r.mu.RUnlock()
+
+	if notifier == nil {
+		return
+	}
+	for _, subscriber := range subscribers {
+		notifier(subscriber, &mcp.ResourceUpdatedNotificationParams{URI: uri})
+	}
+}
+
+// resourceUpdatedMessage 构建资源更新通知的JSON-RPC消息 / Build the JSON-RPC message of a resource update notification
+func resourceUpdatedMessage(params *mcp.ResourceUpdatedNotificationParams) ([]byte, error) {
+	return json.Marshal(types.NewMCPNotification(types.MCPNotificationResourceUpdated, params))
+}
+
+// resourceCapabilities 返回initialize结果中的资源能力,没有提供者时返回nil / Return the resources capability of an initialize result, nil without a provider
+func (r *ResourceRegistry) resourceCapabilities(subscribe bool) map[string]interface{} {
+	if !r.HasProvider() {
+		return nil
+	}
+	return map[string]interface{}{
+		"subscribe":   subscribe,
+		"listChanged": false,
+	}
+}
+
+// decodeParams 将请求参数解码为指定结构体 / Decode request params into the given struct
+func decodeParams(params interface{}, v interface{}) error {
+	if params == nil {
+		return nil
+	}
+	paramsJSON, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("failed to marshal params: %w", err)
+	}
+	if err = json.Unmarshal(paramsJSON, v); err != nil {
+		return fmt.Errorf("failed to parse params: %w", err)
+	}
+	return nil
+}
+
+// rpcError 将处理错误转换为JSON-RPC错误码、消息和数据,保留提供者返回的协议错误(如资源未找到)
+// Convert a handler error into a JSON-RPC code, message and data, keeping protocol errors returned by the provider (such as resource not found)
+func rpcError(err error) (int, string, interface{}) {
+	var wireErr *jsonrpc.Error
+	if errors.As(err, &wireErr) {
+		var data interface{}
+		if len(wireErr.Data) > 0 {
+			data = json.RawMessage(wireErr.Data)
+		}
+		return int(wireErr.Code), wireErr.Message, data
+	}
+	if errors.Is(err, errNoResourceProvider) {
+		return types.MCPErrorCodeMethodNotFound, types.MCPErrorMsgMethodNotFound, err.Error()
+	}
+	return types.MCPErrorCodeInternalError, err.Error(), nil
+}
@@ -0,0 +1,225 @@
+package transport
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"mcp-toolkit/pkg/types"
+	"mcp-toolkit/pkg/utils/json"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeResourceProvider 测试用的资源提供者 / Resource provider used by tests
+type fakeResourceProvider struct {
+	files         map[string]string
+	subscriptions map[string]int
+}
+
+func newFakeResourceProvider() *fakeResourceProvider {
+	return &fakeResourceProvider{
+		files:         map[string]string{"file:///a.txt": "alpha", "file:///b.txt": "beta"},
+		subscriptions: make(map[string]int),
+	}
+}
+
+func (p *fakeResourceProvider) ListResources(params *mcp.ListResourcesParams) (*mcp.ListResourcesResult, error) {
+	return &mcp.ListResourcesResult{Resources: []*mcp.Resource{{URI: "file:///a.txt", Name: "a.txt"}}, NextCursor: "next"}, nil
+}
+
+func (p *fakeResourceProvider) ListResourceTemplates(params *mcp.ListResourceTemplatesParams) (*mcp.ListResourceTemplatesResult, error) {
+	return &mcp.ListResourceTemplatesResult{ResourceTemplates: []*mcp.ResourceTemplate{{URITemplate: "file:///{+path}", Name: "file"}}}, nil
+}
+
+func (p *fakeResourceProvider) ReadResource(params *mcp.ReadResourceParams) (*mcp.ReadResourceResult, error) {
+	text, ok := p.files[params.URI]
+	if !ok {
+		return nil, mcp.ResourceNotFoundError(params.URI)
+	}
+	return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{{URI: params.URI, Text: text}}}, nil
+}
+
+func (p *fakeResourceProvider) SubscribeResource(uri string) error {
+	p.subscriptions[uri]++
+	return nil
+}
+
+func (p *fakeResourceProvider) UnsubscribeResource(uri string) error {
+	p.subscriptions[uri]--
+	return nil
+}
+
+// postHTTPResource 向HTTP服务器发送请求并解析响应 / Post a request to the HTTP server and parse the response
+func postHTTPResource(t *testing.T, server *HTTPTransportServer, sessionID, method string, params interface{}) types.MCPResponse {
+	reqJSON, err := json.Marshal(types.MCPRequest{JSONRPC: "2.0", ID: 1, Method: method, Params: params})
+	require.NoError(t, err)
+	httpReq := httptest.NewRequest(http.MethodPost, "/mcp", bytes.NewReader(reqJSON))
+	httpReq.Header.Set("Accept", "application/json")
+	if sessionID != "" {
+		httpReq.Header.Set("Mcp-Session-Id", sessionID)
+	}
+	w := httptest.NewRecorder()
+	server.handleMCPRequest(w, httpReq)
+
+	var resp types.MCPResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return resp
+}
+
+func TestResourceRegistry_Subscriptions(t *testing.T) {
+	registry := NewResourceRegistry()
+	_, err := registry.ListResources(nil)
+	assert.ErrorIs(t, err, errNoResourceProvider)
+	assert.ErrorIs(t, registry.Subscribe("s1", "file:///a.txt"), errNoResourceProvider)
+
+	provider := newFakeResourceProvider()
+	registry.SetProvider(provider)
+
+	var notified []string
+	registry.SetNotifier(func(subscriber string, params *mcp.ResourceUpdatedNotificationParams) {
+		notified = append(notified, subscriber+" "+params.URI)
+	})
+
+	// 同一订阅者的重复订阅只传给提供者一次 / Repeated subscriptions of one subscriber reach the provider once
+	require.NoError(t, registry.Subscribe("s1", "file:///a.txt"))
+	require.NoError(t, registry.Subscribe("s1", "file:///a.txt"))
+	require.NoError(t, registry.Subscribe("s2", "file:///a.txt"))
+	require.NoError(t, registry.Subscribe("s2", "file:///b.txt"))
+	assert.Equal(t, 2, provider.subscriptions["file:///a.txt"])
+	assert.Equal(t, 2, registry.Subscriptions("s2"))
+
+	registry.NotifyUpdated("file:///a.txt")
+	assert.ElementsMatch(t, []string{"s1 file:///a.txt", "s2 file:///a.txt"}, notified)
+
+	require.NoError(t, registry.Unsubscribe("s1", "file:///a.txt"))
+	registry.RemoveSubscriber("s2")
+	assert.Equal(t, 0, provider.subscriptions["file:///a.txt"])
+	assert.Equal(t, 0, provider.subscriptions["file:///b.txt"])
+
+	notified = nil
+	registry.NotifyUpdated("file:///a.txt")
+	assert.Empty(t, notified)
+}
+
+func TestHTTPTransportServer_Resources(t *testing.T) {
+	config := types.DefaultHTTPConfig()
+	config.Port = 18090
+	config.EnableSessionManagement = true
+	config.EnableSSE = true
+	server, err := NewHTTPTransportServer(config, zap.NewNop())
+	require.NoError(t, err)
+
+	// 未注册提供者时不声明资源能力 / No resources capability without a provider
+	result := server.handleInitialize(types.MCPRequest{Method: "initialize"}, nil).(map[string]interface{})
+	assert.NotContains(t, result["capabilities"], "resources")
+
+	provider := newFakeResourceProvider()
+	server.GetResourceRegistry().SetProvider(provider)
+	result = server.handleInitialize(types.MCPRequest{Method: "initialize"}, nil).(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"subscribe": true, "listChanged": false}, result["capabilities"].(map[string]interface{})["resources"])
+
+	session, err := server.createSession()
+	require.NoError(t, err)
+
+	resp := postHTTPResource(t, server, session.ID, "resources/list", nil)
+	require.Nil(t, resp.Error)
+	assert.Equal(t, "next", resp.Result.(map[string]interface{})["nextCursor"])
+
+	resp = postHTTPResource(t, server, session.ID, "resources/templates/list", nil)
+	require.Nil(t, resp.Error)
+	assert.Len(t, resp.Result.(map[string]interface{})["resourceTemplates"], 1)
+
+	resp = postHTTPResource(t, server, session.ID, "resources/read", map[string]interface{}{"uri": "file:///a.txt"})
+	require.Nil(t, resp.Error)
+	contents := resp.Result.(map[string]interface{})["contents"].([]interface{})
+	assert.Equal(t, "alpha", contents[0].(map[string]interface{})["text"])
+
+	// 资源未找到保留协议错误码 / A missing resource keeps its protocol error code
+	resp = postHTTPResource(t, server, session.ID, "resources/read", map[string]interface{}{"uri": "file:///missing.txt"})
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, int(mcp.CodeResourceNotFound), resp.Error.Code)
+
+	// 订阅的通知进入会话的SSE流队列 / Subscribed notifications are queued on the session's SSE stream
+	resp = postHTTPResource(t, server, session.ID, "resources/subscribe", map[string]interface{}{"uri": "file:///a.txt"})
+	require.Nil(t, resp.Error)
+	server.GetResourceRegistry().NotifyUpdated("file:///a.txt")
+	select {
+	case msg := <-session.notifications:
+		var notification types.MCPRequest
+		require.NoError(t, json.Unmarshal(msg, &notification))
+		assert.Equal(t, types.MCPNotificationResourceUpdated, notification.Method)
+		assert.Nil(t, notification.ID)
+		assert.Equal(t, "file:///a.txt", notification.Params.(map[string]interface{})["uri"])
+	case <-time.After(time.Second):
+		t.Fatal("notification not queued")
+	}
+
+	// 删除会话取消其订阅 / Deleting the session cancels its subscriptions
+	server.deleteSession(session.ID)
+	assert.Equal(t, 0, provider.subscriptions["file:///a.txt"])
+}
+
+func TestHTTPTransportServer_ResourceSubscribeRequiresSession(t *testing.T) {
+	server, _ := setupHTTPServer(t)
+	server.GetResourceRegistry().SetProvider(newFakeResourceProvider())
+
+	resp := postHTTPResource(t, server, "", "resources/read", map[string]interface{}{"uri": "file:///b.txt"})
+	require.Nil(t, resp.Error)
+
+	resp = postHTTPResource(t, server, "", "resources/subscribe", map[string]interface{}{"uri": "file:///b.txt"})
+	require.NotNil(t, resp.Error)
+	assert.Contains(t, resp.Error.Message, "session management")
+}
+
+func TestSSETransportServer_Resources(t *testing.T) {
+	server, _ := setupSSEServer(t)
+	provider := newFakeResourceProvider()
+	server.GetResourceRegistry().SetProvider(provider)
+
+	conn := &SSEConnection{
+		id:        "resource-conn",
+		messages:  make(chan *types.SSEMessage, 10),
+		done:      make(chan struct{}),
+		createdAt: time.Now(),
+		lastPing:  time.Now(),
+	}
+	require.NoError(t, server.addConnection(conn))
+
+	post := func(target, method string, params interface{}) types.MCPResponse {
+		reqJSON, err := json.Marshal(types.MCPRequest{JSONRPC: "2.0", ID: 1, Method: method, Params: params})
+		require.NoError(t, err)
+		w := httptest.NewRecorder()
+		server.handleMCPMessage(w, httptest.NewRequest(http.MethodPost, target, bytes.NewReader(reqJSON)))
+		var resp types.MCPResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp
+	}
+
+	resp := post("/sse/message", "resources/read", map[string]interface{}{"uri": "file:///b.txt"})
+	require.Nil(t, resp.Error)
+
+	// 订阅需要指定已打开的连接 / Subscribing requires naming an open connection
+	resp = post("/sse/message", "resources/subscribe", map[string]interface{}{"uri": "file:///b.txt"})
+	require.NotNil(t, resp.Error)
+
+	resp = post("/sse/message?connectionId=resource-conn", "resources/subscribe", map[string]interface{}{"uri": "file:///b.txt"})
+	require.Nil(t, resp.Error)
+	server.GetResourceRegistry().NotifyUpdated("file:///b.txt")
+	select {
+	case msg := <-conn.messages:
+		assert.Equal(t, "message", msg.Event)
+		assert.Contains(t, msg.Data, types.MCPNotificationResourceUpdated)
+		assert.Contains(t, msg.Data, "file:///b.txt")
+	case <-time.After(time.Second):
+		t.Fatal("notification not sent")
+	}
+
+	server.removeConnection("resource-conn")
+	assert.Equal(t, 0, provider.subscriptions["file:///b.txt"])
+}
@@ -45,14 +45,15 @@ type SSEConnection struct {
 
 // SSETransportServer SSE传输服务器 / SSE transport server
 type SSETransportServer struct {
-	config       *types.SSEConfig
-	logger       *zap.Logger
-	server       *http.Server
-	mcpServer    *mcp.Server
-	toolRegistry *ToolRegistry
-	connections  sync.Map     // 存储活跃连接 / Store active connections
-	connCount    int32        // 连接计数 / Connection count
-	rateLimiter  *RateLimiter // 请求频率限制器 / Request rate limiter
+	config           *types.SSEConfig
+	logger           *zap.Logger
+	server           *http.Server
+	mcpServer        *mcp.Server
+	toolRegistry     *ToolRegistry
+	resourceRegistry *ResourceRegistry
+	connections      sync.Map     // 存储活跃连接 / Store active connections
+	connCount        int32        // 连接计数 / Connection count
+	rateLimiter      *RateLimiter // 请求频率限制器 / Request rate limiter
 }
 
 // NewSSETransportServer 创建SSE传输服务器 / Create SSE transport server
@@ -99,11 +100,13 @@ func NewSSETransportServer(config *types.SSEConfig, logger *zap.Logger) (*SSETra
 	toolRegistry.SetLogger(logger)
 
 	server := &SSETransportServer{
-		config:       config,
-		logger:       logger,
-		mcpServer:    nil, // 将在Start方法中设置 / Will be set in Start method
-		toolRegistry: toolRegistry,
+		config:           config,
+		logger:           logger,
+		mcpServer:        nil, // 将在Start方法中设置 / Will be set in Start method
+		toolRegistry:     toolRegistry,
+		resourceRegistry: NewResourceRegistry(),
 	}
+	server.resourceRegistry.SetNotifier(server.notifyConnection)
 
 	// 如果启用频率限制，创建限制器 / If rate limiting enabled, create limiter
 	if config.EnableRateLimit {
@@ -125,6 +128,11 @@ func (s *SSETransportServer) GetToolRegistry() *ToolRegistry {
 	return s.toolRegistry
 }
 
+// GetResourceRegistry 获取资源注册表 / Get resource registry
+func (s *SSETransportServer) GetResourceRegistry() *ResourceRegistry {
+	return s.resourceRegistry
+}
+
 // Start 启动SSE服务器 / Start SSE server
 func (s *SSETransportServer) Start(ctx context.Context, mcpServer *mcp.Server) error {
 	s.mcpServer = mcpServer
@@ -379,6 +387,14 @@ func (s *SSETransportServer) handleMCPMessageInternal(w http.ResponseWriter, r *
 			s.sendJSONError(w, mcpReq.ID, types.MCPErrorCodeInternalError, err.Error(), nil)
 			return nil
 		}
+	case "resources/list", "resources/templates/list", "resources/read", "resources/subscribe", "resources/unsubscribe":
+		// 订阅通知发送到connectionId查询参数指定的流 / Subscription notifications go to the stream named by the connectionId query parameter
+		result, err = s.handleResourceRequest(mcpReq, r.URL.Query().Get("connectionId"))
+		if err != nil {
+			code, message, data := rpcError(err)
+			s.sendJSONError(w, mcpReq.ID, code, message, data)
+			return nil
+		}
 	default:
 		s.sendJSONError(w, mcpReq.ID, types.MCPErrorCodeMethodNotFound, types.MCPErrorMsgMethodNotFound, mcpReq.Method)
 		return nil
@@ -403,9 +419,7 @@ func (s *SSETransportServer) handleMCPMessageInternal(w http.ResponseWriter, r *
 func (s *SSETransportServer) handleInitialize(req types.MCPRequest) interface{} {
 	return map[string]interface{}{
 		"protocolVersion": types.ProtocolVersion,
-		"capabilities": map[string]interface{}{
-			"tools": map[string]interface{}{},
-		},
+		"capabilities":    s.capabilities(),
 		"serverInfo": map[string]interface{}{
 			"name":    types.ServerName,
 			"version": types.ServerVersion,
@@ -413,6 +427,74 @@ func (s *SSETransportServer) handleInitialize(req types.MCPRequest) interface{}
 	}
 }
 
+// capabilities 返回服务器能力 / Return the server capabilities
+func (s *SSETransportServer) capabilities() map[string]interface{} {
+	capabilities := map[string]interface{}{
+		"tools": map[string]interface{}{},
+	}
+	if resources := s.resourceRegistry.resourceCapabilities(true); resources != nil {
+		capabilities["resources"] = resources
+	}
+	return capabilities
+}
+
+// handleResourceRequest 处理resources/*请求 / Handle resources/* requests
+func (s *SSETransportServer) handleResourceRequest(req types.MCPRequest, connID string) (interface{}, error) {
+	switch req.Method {
+	case "resources/list":
+		var params mcp.ListResourcesParams
+		if err := decodeParams(req.Params, &params); err != nil {
+			return nil, err
+		}
+		return s.resourceRegistry.ListResources(&params)
+	case "resources/templates/list":
+		var params mcp.ListResourceTemplatesParams
+		if err := decodeParams(req.Params, &params); err != nil {
+			return nil, err
+		}
+		return s.resourceRegistry.ListResourceTemplates(&params)
+	case "resources/read":
+		var params mcp.ReadResourceParams
+		if err := decodeParams(req.Params, &params); err != nil {
+			return nil, err
+		}
+		return s.resourceRegistry.ReadResource(&params)
+	}
+
+	// 订阅按SSE连接跟踪 / Subscriptions are tracked per SSE connection
+	if _, ok := s.getConnection(connID); !ok {
+		return nil, fmt.Errorf("resource subscriptions require an open SSE connection: %q", connID)
+	}
+	var params mcp.SubscribeParams
+	if err := decodeParams(req.Params, &params); err != nil {
+		return nil, err
+	}
+	var err error
+	if req.Method == "resources/subscribe" {
+		err = s.resourceRegistry.Subscribe(connID, params.URI)
+	} else {
+		err = s.resourceRegistry.Unsubscribe(connID, params.URI)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{}, nil
+}
+
+// notifyConnection 向SSE连接发送资源更新通知 / Send a resource update notification to an SSE connection
+func (s *SSETransportServer) notifyConnection(connID string, params *mcp.ResourceUpdatedNotificationParams) {
+	msg, err := resourceUpdatedMessage(params)
+	if err != nil {
+		s.logger.Error("failed to marshal notification", zap.Error(err))
+		return
+	}
+	if err = s.sendToConnection(connID, &types.SSEMessage{Event: "message", Data: string(msg)}); err != nil {
+		s.logger.Warn("failed to send notification",
+			zap.String("conn_id", connID),
+			zap.Error(err))
+	}
+}
+
 // handleToolsList 处理工具列表请求 / Handle tools list request
 func (s *SSETransportServer) handleToolsList(req types.MCPRequest) interface{} {
 	// 解析请求参数 / Parse request params
@@ -485,19 +567,7 @@ func (s *SSETransportServer) handleToolsCall(req types.MCPRequest) (interface{},
 	}
 
 	// 转换结果为响应格式 / Convert result to response format
-	content := make([]map[string]interface{}, len(result.Content))
-	for i, c := range result.Content {
-		if textContent, ok := c.(*mcp.TextContent); ok {
-			content[i] = map[string]interface{}{
-				"type": "text",
-				"text": textContent.Text,
-			}
-		}
-	}
-
-	return map[string]interface{}{
-		"content": content,
-	}, nil
+	return toolCallResultPayload(result)
 }
 
 // sendJSONError 发送JSON错误响应 / Send JSON error response
@@ -665,6 +735,7 @@ func (s *SSETransportServer) removeConnection(connID string) {
 			close(sseConn.done)
 			close(sseConn.messages)
 		}
+		s.resourceRegistry.RemoveSubscriber(connID)
 		atomic.AddInt32(&s.connCount, -1)
 		s.logger.Info("SSE connection removed",
 			zap.String("conn_id", connID),
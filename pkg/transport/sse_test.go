@@ -627,6 +627,58 @@ func TestSSETransportServer_HandleToolsCall(t *testing.T) {
 	})
 }
 
+func TestSSETransportServer_HandleToolsCallContentTypes(t *testing.T) {
+	server, _ := setupSSEServer(t)
+
+	// 注册返回图片和嵌入资源的工具 / Register a tool returning an image and an embedded resource
+	server.toolRegistry.RegisterTool(&mcp.Tool{
+		Name:        "read_file",
+		Description: "Read a file",
+	}, func(ctx context.Context, arguments interface{}) (*mcp.CallToolResult, error) {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.ImageContent{Data: []byte{0x89, 'P', 'N', 'G'}, MIMEType: "image/png"},
+				&mcp.EmbeddedResource{Resource: &mcp.ResourceContents{URI: "file:///data.bin", MIMEType: "application/octet-stream", Blob: []byte{0x00, 0x01}}},
+				&mcp.TextContent{Text: "plain"},
+			},
+		}, nil
+	})
+
+	result, err := server.handleToolsCall(types.MCPRequest{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  "tools/call",
+		Params:  map[string]interface{}{"name": "read_file"},
+	})
+	require.NoError(t, err)
+
+	data, err := json.Marshal(result)
+	require.NoError(t, err)
+	var decoded struct {
+		Content []struct {
+			Type     string `json:"type"`
+			Text     string `json:"text"`
+			Data     string `json:"data"`
+			MimeType string `json:"mimeType"`
+			Resource struct {
+				URI  string `json:"uri"`
+				Blob string `json:"blob"`
+			} `json:"resource"`
+		} `json:"content"`
+	}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.Len(t, decoded.Content, 3)
+
+	assert.Equal(t, "image", decoded.Content[0].Type)
+	assert.Equal(t, "image/png", decoded.Content[0].MimeType)
+	assert.Equal(t, "iVBORw==", decoded.Content[0].Data)
+	assert.Equal(t, "resource", decoded.Content[1].Type)
+	assert.Equal(t, "file:///data.bin", decoded.Content[1].Resource.URI)
+	assert.Equal(t, "AAE=", decoded.Content[1].Resource.Blob)
+	assert.Equal(t, "text", decoded.Content[2].Type)
+	assert.Equal(t, "plain", decoded.Content[2].Text)
+}
+
 func TestSSETransportServer_MethodNotAllowed(t *testing.T) {
 	t.Run("GET request to message endpoint", func(t *testing.T) {
 		httpReq := httptest.NewRequest(http.MethodGet, "/sse/message", nil)
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"sync"
 
+	"mcp-toolkit/pkg/utils/json"
 	"mcp-toolkit/pkg/utils/recovery"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -121,3 +122,28 @@ func (r *ToolRegistry) GetTool(name string) (*mcp.Tool, bool) {
 	tool, exists := r.tools[name]
 	return tool, exists
 }
+
+// toolCallResultPayload 将工具结果转换为tools/call的JSON-RPC结果,保留文本、图片和嵌入资源等各类内容
+// Convert a tool result into the tools/call JSON-RPC result, keeping every content type such as text, images and embedded resources
+func toolCallResultPayload(result *mcp.CallToolResult) (map[string]interface{}, error) {
+	content := make([]json.RawMessage, 0)
+	isError := false
+	if result != nil {
+		for _, c := range result.Content {
+			data, err := c.MarshalJSON()
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal tool result content: %w", err)
+			}
+			content = append(content, data)
+		}
+		isError = result.IsError
+	}
+
+	payload := map[string]interface{}{
+		"content": content,
+	}
+	if isError {
+		payload["isError"] = true
+	}
+	return payload, nil
+}
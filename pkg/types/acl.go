@@ -0,0 +1,82 @@
+// Copyright 2024 MCP Toolkit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package types 访问控制相关类型定义 / Access control related type definitions
+package types
+
+// AccessOperation 访问控制的操作类别 / Operation class of access control
+type AccessOperation string
+
+const (
+	// AccessRead 读取文件内容 / Read file contents
+	AccessRead AccessOperation = "read"
+
+	// AccessWrite 创建或修改文件和目录 / Create or modify files and directories
+	AccessWrite AccessOperation = "write"
+
+	// AccessDelete 删除或移走文件和目录 / Delete or move away files and directories
+	AccessDelete AccessOperation = "delete"
+
+	// AccessList 列出目录、查看元数据或在搜索结果中出现 / List directories, view metadata or appear in search results
+	AccessList AccessOperation = "list"
+)
+
+// AccessOperations 所有操作类别 / All operation classes
+var AccessOperations = []AccessOperation{AccessRead, AccessWrite, AccessDelete, AccessList}
+
+// AccessEffect 规则效果 / Rule effect
+type AccessEffect string
+
+const (
+	// AccessDeny 拒绝(默认) / Deny (default)
+	AccessDeny AccessEffect = "deny"
+
+	// AccessAllow 允许,用于在更宽泛的拒绝规则之前设置例外 / Allow, used to carve out exceptions ahead of broader deny rules
+	AccessAllow AccessEffect = "allow"
+)
+
+// AccessRule 路径访问控制规则 / Path access control rule
+type AccessRule struct {
+	// Pattern 支持**的glob模式,相对于挂载根目录;"名称:"前缀限定挂载,不含"/"时只匹配文件名
+	// Glob pattern supporting **, relative to the mount root; a "name:" prefix restricts it to one mount and patterns without "/" match the base name only
+	Pattern string `json:"pattern"`
+
+	// Operations 规则适用的操作类别,为空时适用于全部 / Operation classes the rule applies to, all when empty
+	Operations []AccessOperation `json:"operations,omitempty"`
+
+	// Effect 规则效果,为空时为拒绝 / Rule effect, deny when empty
+	Effect AccessEffect `json:"effect,omitempty"`
+
+	// Description 规则说明 / Rule description
+	Description string `json:"description,omitempty"`
+}
+
+// GetAccessRulesRequest 获取访问控制规则请求 / Get access rules request
+type GetAccessRulesRequest struct {
+	Path string `json:"path,omitempty"` // 可选,评估该路径在各操作类别下的结果 / Optional path to evaluate for every operation class
+}
+
+// AccessDecision 单个操作类别的评估结果 / Evaluation result of one operation class
+type AccessDecision struct {
+	Operation AccessOperation `json:"operation"`      // 操作类别 / Operation class
+	Allowed   bool            `json:"allowed"`        // 是否允许 / Whether allowed
+	Rule      int             `json:"rule,omitempty"` // 生效规则的序号(从1开始),0表示没有匹配的规则 / 1-based number of the deciding rule, 0 when no rule matched
+}
+
+// GetAccessRulesResponse 获取访问控制规则响应 / Get access rules response
+type GetAccessRulesResponse struct {
+	Rules     []AccessRule     `json:"rules"`               // 按顺序排列的规则 / Rules in evaluation order
+	Path      string           `json:"path,omitempty"`      // 评估的路径 / Evaluated path
+	Decisions []AccessDecision `json:"decisions,omitempty"` // 各操作类别的评估结果 / Decisions per operation class
+}
@@ -0,0 +1,84 @@
+// Copyright 2024 MCP Toolkit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package types 归档相关类型定义 / Archive related type definitions
+package types
+
+import "time"
+
+// 支持的归档格式 / Supported archive formats
+const (
+	ArchiveFormatZip    = "zip"     // ZIP
+	ArchiveFormatTar    = "tar"     // 未压缩的tar / Uncompressed tar
+	ArchiveFormatTarGz  = "tar.gz"  // gzip压缩的tar / gzip compressed tar
+	ArchiveFormatTarZst = "tar.zst" // zstd压缩的tar / zstd compressed tar
+)
+
+// 归档条目类型 / Archive entry types
+const (
+	ArchiveEntryFile      = "file"      // 常规文件 / Regular file
+	ArchiveEntryDirectory = "directory" // 目录 / Directory
+	ArchiveEntrySymlink   = "symlink"   // 符号链接 / Symbolic link
+	ArchiveEntryOther     = "other"     // 其他(硬链接、设备等) / Others (hard links, devices, ...)
+)
+
+// CreateArchiveRequest 创建归档请求 / Create archive request
+type CreateArchiveRequest struct {
+	Sources   []string `json:"sources"`             // 要打包的文件或目录 / Files or directories to pack
+	Path      string   `json:"path"`                // 归档文件路径 / Archive file path
+	Format    string   `json:"format,omitempty"`    // 归档格式(默认按扩展名推断) / Archive format (inferred from the extension by default)
+	Exclude   []string `json:"exclude,omitempty"`   // 排除的glob模式 / Glob patterns to exclude
+	Overwrite bool     `json:"overwrite,omitempty"` // 是否覆盖已存在的归档 / Whether to overwrite an existing archive
+}
+
+// CreateArchiveResponse 创建归档响应 / Create archive response
+type CreateArchiveResponse struct {
+	Success     bool   `json:"success"`      // 是否成功 / Whether successful
+	Message     string `json:"message"`      // 消息 / Message
+	Path        string `json:"path"`         // 归档文件路径 / Archive file path
+	Format      string `json:"format"`       // 归档格式 / Archive format
+	Entries     int    `json:"entries"`      // 条目数量 / Number of entries
+	TotalSize   int64  `json:"total_size"`   // 未压缩总大小 / Total uncompressed size
+	ArchiveSize int64  `json:"archive_size"` // 归档文件大小 / Archive file size
+}
+
+// ExtractArchiveRequest 解压归档请求 / Extract archive request
+type ExtractArchiveRequest struct {
+	Path        string `json:"path"`                  // 归档文件路径 / Archive file path
+	Destination string `json:"destination,omitempty"` // 解压目标目录(默认为归档所在目录) / Target directory (the archive's directory by default)
+	Format      string `json:"format,omitempty"`      // 归档格式(默认按扩展名推断) / Archive format (inferred from the extension by default)
+	Overwrite   bool   `json:"overwrite,omitempty"`   // 是否覆盖已存在的文件 / Whether to overwrite existing files
+	ListOnly    bool   `json:"list_only,omitempty"`   // 仅列出内容而不解压 / Only list the contents without extracting
+}
+
+// ArchiveEntry 归档中的条目 / Entry of an archive
+type ArchiveEntry struct {
+	Name    string    `json:"name"`     // 条目名称 / Entry name
+	Type    string    `json:"type"`     // 条目类型 / Entry type
+	Size    int64     `json:"size"`     // 未压缩大小 / Uncompressed size
+	Mode    string    `json:"mode"`     // 权限 / Permissions
+	ModTime time.Time `json:"mod_time"` // 修改时间 / Modification time
+}
+
+// ExtractArchiveResponse 解压归档响应 / Extract archive response
+type ExtractArchiveResponse struct {
+	Success     bool           `json:"success"`               // 是否成功 / Whether successful
+	Message     string         `json:"message"`               // 消息 / Message
+	Format      string         `json:"format"`                // 归档格式 / Archive format
+	Destination string         `json:"destination,omitempty"` // 解压目标目录 / Target directory
+	EntryCount  int            `json:"entry_count"`           // 条目数量 / Number of entries
+	TotalSize   int64          `json:"total_size"`            // 未压缩总大小 / Total uncompressed size
+	Entries     []ArchiveEntry `json:"entries,omitempty"`     // 条目列表(仅list_only) / Entries (list_only only)
+	Skipped     []string       `json:"skipped,omitempty"`     // 跳过的链接和特殊条目 / Skipped links and special entries
+}
@@ -0,0 +1,68 @@
+// Copyright 2024 MCP Toolkit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package types 文本编码检测与转换相关类型定义 / Text encoding detection and conversion related type definitions
+package types
+
+import "time"
+
+const (
+	// CharsetUTF8 UTF-8字符集 / UTF-8 charset
+	CharsetUTF8 = "utf-8"
+
+	// CharsetUTF16LE 小端UTF-16字符集 / Little-endian UTF-16 charset
+	CharsetUTF16LE = "utf-16le"
+
+	// CharsetUTF16BE 大端UTF-16字符集 / Big-endian UTF-16 charset
+	CharsetUTF16BE = "utf-16be"
+
+	// CharsetGBK GBK字符集 / GBK charset
+	CharsetGBK = "gbk"
+
+	// CharsetGB18030 GB18030字符集 / GB18030 charset
+	CharsetGB18030 = "gb18030"
+
+	// CharsetShiftJIS Shift-JIS字符集 / Shift-JIS charset
+	CharsetShiftJIS = "shift_jis"
+)
+
+const (
+	// LineEndingLF Unix换行符 / Unix line ending
+	LineEndingLF = "lf"
+
+	// LineEndingCRLF Windows换行符 / Windows line ending
+	LineEndingCRLF = "crlf"
+)
+
+// ConvertEncodingRequest 转换文件编码请求 / Convert file encoding request
+type ConvertEncodingRequest struct {
+	Path       string `json:"path"`                  // 文件路径 / File path
+	From       string `json:"from,omitempty"`        // 源字符集,为空时自动检测 / Source charset, detected when empty
+	To         string `json:"to"`                    // 目标字符集 / Target charset
+	BOM        bool   `json:"bom,omitempty"`         // 是否写入字节顺序标记(仅UTF编码) / Whether to write a byte order mark (UTF charsets only)
+	LineEnding string `json:"line_ending,omitempty"` // 换行符风格: lf 或 crlf,为空时保持不变 / Line ending style: lf or crlf, unchanged when empty
+}
+
+// ConvertEncodingResponse 转换文件编码响应 / Convert file encoding response
+type ConvertEncodingResponse struct {
+	Success bool      `json:"success"`  // 是否成功 / Whether successful
+	Message string    `json:"message"`  // 消息 / Message
+	From    string    `json:"from"`     // 源字符集 / Source charset
+	To      string    `json:"to"`       // 目标字符集 / Target charset
+	BOM     bool      `json:"bom"`      // 是否写入了字节顺序标记 / Whether a byte order mark was written
+	Size    int64     `json:"size"`     // 转换后的文件大小 / File size after the conversion
+	Version string    `json:"version"`  // 转换后的版本标识 / Version token after the conversion
+	SHA256  string    `json:"sha256"`   // 转换后内容的SHA-256 / SHA-256 of the content after the conversion
+	ModTime time.Time `json:"mod_time"` // 转换后的修改时间 / Modification time after the conversion
+}
@@ -57,13 +57,15 @@ type ExecuteCommandRequest struct {
 
 // ExecuteCommandResponse 执行命令响应 / Execute command response
 type ExecuteCommandResponse struct {
-	Success        bool   `json:"success"`          // 是否成功 / Whether successful
-	ExitCode       int    `json:"exit_code"`        // 退出码 / Exit code
-	Stdout         string `json:"stdout"`           // 标准输出 / Standard output
-	Stderr         string `json:"stderr"`           // 标准错误 / Standard error
-	Message        string `json:"message"`          // 消息 / Message
-	CommandLine    string `json:"command_line"`     // 完整的命令行 / Full command line
-	CurrentWorkDir string `json:"current_work_dir"` // 当前工作目录(相对于沙箱根目录) / Current working directory (relative to sandbox root)
+	Success        bool        `json:"success"`                  // 是否成功 / Whether successful
+	ExitCode       int         `json:"exit_code"`                // 退出码 / Exit code
+	Stdout         string      `json:"stdout"`                   // 标准输出 / Standard output
+	Stderr         string      `json:"stderr"`                   // 标准错误 / Standard error
+	Message        string      `json:"message"`                  // 消息 / Message
+	CommandLine    string      `json:"command_line"`             // 完整的命令行 / Full command line
+	CurrentWorkDir string      `json:"current_work_dir"`         // 当前工作目录(相对于沙箱根目录) / Current working directory (relative to sandbox root)
+	QuotaExceeded  bool        `json:"quota_exceeded,omitempty"` // 命令执行后沙箱是否超出配额 / Whether the sandbox exceeds its quota after the command
+	QuotaUsage     *QuotaUsage `json:"quota_usage,omitempty"`    // 启用配额时命令执行后的用量 / Usage after the command when a quota is configured
 }
 
 // GetCommandBlacklistRequest 获取命令黑名单请求 / Get command blacklist request
@@ -90,7 +92,9 @@ type GetWorkingDirectoryRequest struct{}
 
 // GetWorkingDirectoryResponse 获取当前工作目录响应 / Get working directory response
 type GetWorkingDirectoryResponse struct {
-	WorkDir string `json:"work_dir"` // 当前工作目录(相对于沙箱根目录) / Current working directory (relative to sandbox root)
+	WorkDir  string `json:"work_dir"`  // 当前工作目录(相对于所在挂载点,非默认挂载带"名称:"前缀) / Current working directory (relative to its mount, prefixed with "name:" outside the default mount)
+	Mount    string `json:"mount"`     // 工作目录所在的挂载名称 / Name of the mount holding the working directory
+	ReadOnly bool   `json:"read_only"` // 该挂载是否只读 / Whether that mount is read-only
 }
 
 // ChangeDirectoryRequest 切换工作目录请求 / Change directory request
@@ -136,20 +140,22 @@ type ExecuteCommandAsyncResponse struct {
 
 // CommandTask 命令执行任务 / Command execution task
 type CommandTask struct {
-	ID              string                 `json:"id"`               // 任务ID / Task ID
-	Command         string                 `json:"command"`          // 命令 / Command
-	Args            []string               `json:"args"`             // 参数 / Arguments
-	WorkDir         string                 `json:"work_dir"`         // 工作目录 / Working directory
-	Status          CommandTaskStatus      `json:"status"`           // 状态 / Status
-	StartTime       time.Time              `json:"start_time"`       // 开始时间 / Start time
-	EndTime         time.Time              `json:"end_time"`         // 结束时间 / End time
-	ExitCode        int                    `json:"exit_code"`        // 退出码 / Exit code
-	Stdout          string                 `json:"stdout"`           // 标准输出 / Standard output
-	Stderr          string                 `json:"stderr"`           // 标准错误 / Standard error
-	Error           string                 `json:"error,omitempty"`  // 错误信息 / Error message
-	User            string                 `json:"user,omitempty"`   // 执行用户 / Executing user
-	PermissionLevel CommandPermissionLevel `json:"permission_level"` // 权限级别 / Permission level
-	Environment     map[string]string      `json:"environment"`      // 环境变量 / Environment variables
+	ID              string                 `json:"id"`                       // 任务ID / Task ID
+	Command         string                 `json:"command"`                  // 命令 / Command
+	Args            []string               `json:"args"`                     // 参数 / Arguments
+	WorkDir         string                 `json:"work_dir"`                 // 工作目录 / Working directory
+	Status          CommandTaskStatus      `json:"status"`                   // 状态 / Status
+	StartTime       time.Time              `json:"start_time"`               // 开始时间 / Start time
+	EndTime         time.Time              `json:"end_time"`                 // 结束时间 / End time
+	ExitCode        int                    `json:"exit_code"`                // 退出码 / Exit code
+	Stdout          string                 `json:"stdout"`                   // 标准输出 / Standard output
+	Stderr          string                 `json:"stderr"`                   // 标准错误 / Standard error
+	Error           string                 `json:"error,omitempty"`          // 错误信息 / Error message
+	User            string                 `json:"user,omitempty"`           // 执行用户 / Executing user
+	PermissionLevel CommandPermissionLevel `json:"permission_level"`         // 权限级别 / Permission level
+	Environment     map[string]string      `json:"environment"`              // 环境变量 / Environment variables
+	QuotaExceeded   bool                   `json:"quota_exceeded,omitempty"` // 命令结束后沙箱是否超出配额 / Whether the sandbox exceeds its quota after the command finished
+	QuotaUsage      *QuotaUsage            `json:"quota_usage,omitempty"`    // 启用配额时命令结束后的用量 / Usage after the command finished when a quota is configured
 }
 
 // GetCommandTaskRequest 获取命令任务请求 / Get command task request
@@ -25,12 +25,14 @@ import "time"
 
 // FileInfo 文件信息结构体 / File information structure
 type FileInfo struct {
-	Name    string    `json:"name"`     // 文件名 / File name
-	Path    string    `json:"path"`     // 文件路径 / File path
-	Size    int64     `json:"size"`     // 文件大小(字节) / File size in bytes
-	IsDir   bool      `json:"is_dir"`   // 是否为目录 / Whether it is a directory
-	Mode    string    `json:"mode"`     // 文件权限 / File permissions
-	ModTime time.Time `json:"mod_time"` // 修改时间 / Modification time
+	Name    string    `json:"name"`              // 文件名 / File name
+	Path    string    `json:"path"`              // 文件路径 / File path
+	Size    int64     `json:"size"`              // 文件大小(字节) / File size in bytes
+	IsDir   bool      `json:"is_dir"`            // 是否为目录 / Whether it is a directory
+	Mode    string    `json:"mode"`              // 文件权限 / File permissions
+	ModTime time.Time `json:"mod_time"`          // 修改时间 / Modification time
+	Version string    `json:"version,omitempty"` // 版本标识(仅file_stat对文件返回) / Version token (only returned by get_file_info for files)
+	SHA256  string    `json:"sha256,omitempty"`  // 内容SHA-256(仅file_stat在请求时对文件返回) / Content SHA-256 (only returned by get_file_info for files on request)
 }
 
 // OperationResponse 操作响应 / Operation response
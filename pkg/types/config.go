@@ -14,6 +14,11 @@
 
 package types
 
+import (
+	"fmt"
+	"strings"
+)
+
 // TransportType 传输类型 / Transport type
 type TransportType string
 
@@ -28,6 +33,93 @@ const (
 	TransportSSE TransportType = "sse"
 )
 
+// SymlinkPolicy 符号链接处理策略 / Symbolic link handling policy
+type SymlinkPolicy string
+
+const (
+	// SymlinkPolicyFollow 跟随符号链接,但解析后的目标必须位于沙箱内(默认) / Follow symlinks whose resolved target stays inside the sandbox (default)
+	SymlinkPolicyFollow SymlinkPolicy = "follow"
+
+	// SymlinkPolicyDeny 拒绝任何经过符号链接的路径 / Reject any path that goes through a symlink
+	SymlinkPolicyDeny SymlinkPolicy = "deny"
+
+	// SymlinkPolicyAllow 信任所有符号链接,包括指向沙箱外的链接 / Trust all symlinks, including those pointing outside the sandbox
+	SymlinkPolicyAllow SymlinkPolicy = "allow"
+)
+
+// TrashConfig 回收站配置 / Trash configuration
+type TrashConfig struct {
+	// Enabled 删除时移入回收站而不是永久删除 / Move deleted entries into the trash instead of removing them
+	Enabled bool `json:"enabled"`
+
+	// MaxAge 条目保留时间(秒),0表示不限制 / Retention time of entries in seconds, 0 for no limit
+	MaxAge int `json:"max_age"`
+
+	// MaxSize 回收站最大总大小(字节),超出时先清理最早的条目,0表示不限制
+	// Maximum total size in bytes; the oldest entries are purged first when exceeded, 0 for no limit
+	MaxSize int64 `json:"max_size"`
+}
+
+// QuotaConfig 沙箱磁盘配额配置 / Sandbox disk quota configuration
+type QuotaConfig struct {
+	// MaxBytes 沙箱内文件总大小上限(字节),0表示不限制 / Maximum total size of files in the sandbox in bytes, 0 for no limit
+	MaxBytes int64 `json:"max_bytes"`
+
+	// MaxFiles 沙箱内文件和目录总数上限,0表示不限制 / Maximum number of files and directories in the sandbox, 0 for no limit
+	MaxFiles int64 `json:"max_files"`
+}
+
+// AccessConfig 路径访问控制配置 / Path access control configuration
+type AccessConfig struct {
+	// Rules 按顺序匹配的规则,第一条匹配的规则生效,没有匹配时允许
+	// Rules matched in order; the first matching rule decides and operations are allowed when none matches
+	Rules []AccessRule `json:"rules"`
+}
+
+// MountMode 挂载模式 / Mount mode
+type MountMode string
+
+const (
+	// MountModeReadWrite 读写挂载(默认) / Read-write mount (default)
+	MountModeReadWrite MountMode = "rw"
+
+	// MountModeReadOnly 只读挂载,拒绝所有写入和删除 / Read-only mount rejecting all writes and deletions
+	MountModeReadOnly MountMode = "ro"
+)
+
+// DefaultMountName 沙箱根目录的挂载名称 / Mount name of the sandbox root
+const DefaultMountName = "sandbox"
+
+// MountConfig 命名挂载配置,通过"名称:相对路径"访问 / Named mount configuration, addressed as "name:relative/path"
+type MountConfig struct {
+	// Name 挂载名称 / Mount name
+	Name string `json:"name"`
+
+	// Path 宿主机目录 / Host directory
+	Path string `json:"path"`
+
+	// Mode 挂载模式,为空时为读写 / Mount mode, read-write when empty
+	Mode MountMode `json:"mode,omitempty"`
+}
+
+// ParseMountConfig 解析"名称=宿主路径[:ro|rw]"格式的挂载说明 / Parse a mount spec of the form "name=/host/path[:ro|rw]"
+func ParseMountConfig(spec string) (MountConfig, error) {
+	name, path, ok := strings.Cut(spec, "=")
+	if !ok || name == "" || path == "" {
+		return MountConfig{}, fmt.Errorf("invalid mount %q, expected name=/host/path[:ro|rw]", spec)
+	}
+
+	config := MountConfig{Name: name, Path: path, Mode: MountModeReadWrite}
+	// 只有ro/rw后缀被视为模式,以免误解析Windows盘符 / Only ro/rw suffixes are taken as the mode so Windows drive letters are left alone
+	if idx := strings.LastIndex(path, ":"); idx > 0 {
+		switch mode := MountMode(path[idx+1:]); mode {
+		case MountModeReadOnly, MountModeReadWrite:
+			config.Path, config.Mode = path[:idx], mode
+		}
+	}
+	return config, nil
+}
+
 // ServerConfig MCP服务器配置 / MCP server configuration
 type ServerConfig struct {
 	// Transport 传输类型 / Transport type
@@ -104,6 +104,39 @@ const (
 	// ErrPathRequired 路径必填错误 / Path required error
 	ErrPathRequired = "path is required"
 
+	// ErrWriteConflict 写入冲突错误(文件在读取后被修改) / Write conflict error (the file changed since it was read)
+	ErrWriteConflict = "write conflict: file has been modified since it was read"
+
+	// ErrQuotaExceeded 超出沙箱配额错误 / Sandbox quota exceeded error
+	ErrQuotaExceeded = "sandbox quota exceeded"
+
+	// ErrReadOnlyMount 挂载点为只读 / Mount is read-only
+	ErrReadOnlyMount = "mount is read-only"
+
+	// ErrAccessDenied 访问控制规则拒绝了操作 / Operation denied by an access control rule
+	ErrAccessDenied = "access denied by rule"
+
+	// ErrInvalidResourceURI 资源URI无效或不是沙箱文件URI / Resource URI is invalid or not a sandbox file URI
+	ErrInvalidResourceURI = "invalid resource URI"
+
+	// ErrUploadNotFound 上传会话不存在或已过期 / Upload session does not exist or has expired
+	ErrUploadNotFound = "upload session not found or expired"
+
+	// ErrUploadChecksumMismatch 上传内容的校验和不匹配 / Checksum of the uploaded content does not match
+	ErrUploadChecksumMismatch = "upload checksum mismatch"
+
+	// ErrPathLocked 路径被其他租约锁定 / Path is held by another lease
+	ErrPathLocked = "path is locked"
+
+	// ErrLockNotFound 路径锁不存在或已过期 / Path lock does not exist or has expired
+	ErrLockNotFound = "lock not found or expired"
+
+	// ErrUnsupportedDataFormat 不支持的数据格式 / Unsupported data format
+	ErrUnsupportedDataFormat = "unsupported data format"
+
+	// ErrDataNoMatch 查询没有匹配的值 / The query matches no values
+	ErrDataNoMatch = "no values match the query"
+
 	// MsgSuccess 成功消息 / Success message
 	MsgSuccess = "operation completed successfully"
 
@@ -125,6 +158,45 @@ const (
 	// MsgFileWritten 文件写入成功消息 / File written successfully message
 	MsgFileWritten = "file written successfully"
 
+	// MsgUploadStarted 分块上传开始消息 / Chunked upload started message
+	MsgUploadStarted = "upload started"
+
+	// MsgChunkReceived 分块接收成功消息 / Chunk received successfully message
+	MsgChunkReceived = "chunk received"
+
+	// MsgUploadCommitted 分块上传提交成功消息 / Chunked upload committed successfully message
+	MsgUploadCommitted = "upload committed successfully"
+
+	// MsgPathLocked 路径锁定成功消息 / Path locked successfully message
+	MsgPathLocked = "path locked"
+
+	// MsgLockRenewed 路径锁续约成功消息 / Path lock renewed successfully message
+	MsgLockRenewed = "lock renewed"
+
+	// MsgPathUnlocked 路径锁释放成功消息 / Path unlocked successfully message
+	MsgPathUnlocked = "path unlocked"
+
+	// MsgDataQueried 数据查询成功消息 / Data queried successfully message
+	MsgDataQueried = "data queried successfully"
+
+	// MsgDataUpdated 数据更新成功消息 / Data updated successfully message
+	MsgDataUpdated = "data updated successfully"
+
+	// MsgFileEdited 文件编辑成功消息 / File edited successfully message
+	MsgFileEdited = "file edited successfully"
+
+	// MsgArchiveCreated 归档创建成功消息 / Archive created successfully message
+	MsgArchiveCreated = "archive created successfully"
+
+	// MsgArchiveExtracted 归档解压成功消息 / Archive extracted successfully message
+	MsgArchiveExtracted = "archive extracted successfully"
+
+	// MsgMovedToTrash 移入回收站消息 / Moved to trash message
+	MsgMovedToTrash = "moved to trash"
+
+	// MsgRestoredFromTrash 从回收站恢复成功消息 / Restored from trash successfully message
+	MsgRestoredFromTrash = "restored from trash successfully"
+
 	// MsgCommandExecuted 命令执行成功消息 / Command executed successfully message
 	MsgCommandExecuted = "command executed successfully"
 
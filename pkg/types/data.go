@@ -0,0 +1,95 @@
+// Copyright 2024 MCP Toolkit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package types 结构化数据查询相关类型定义 / Structured data query related type definitions
+package types
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	// DataFormatJSON JSON格式 / JSON format
+	DataFormatJSON = "json"
+
+	// DataFormatYAML YAML格式 / YAML format
+	DataFormatYAML = "yaml"
+
+	// DataFormatTOML TOML格式 / TOML format
+	DataFormatTOML = "toml"
+
+	// DataFormatCSV CSV格式,包括制表符分隔的文件 / CSV format, including tab separated files
+	DataFormatCSV = "csv"
+
+	// DataOpSet 设置值 / Set a value
+	DataOpSet = "set"
+
+	// DataOpDelete 删除值 / Delete a value
+	DataOpDelete = "delete"
+)
+
+// QueryDataRequest 查询结构化数据请求 / Query structured data request
+type QueryDataRequest struct {
+	Path      string `json:"path"`                // 文件路径 / File path
+	Query     string `json:"query"`               // JSONPath风格的表达式 / JSONPath-style expression
+	Format    string `json:"format,omitempty"`    // 文件格式,省略时按扩展名判断 / File format, taken from the extension when omitted
+	Delimiter string `json:"delimiter,omitempty"` // CSV分隔符,省略时自动检测 / CSV delimiter, detected when omitted
+	NoHeader  bool   `json:"no_header,omitempty"` // CSV没有表头行,每行作为数组 / The CSV has no header row and every row is an array
+	Limit     int    `json:"limit,omitempty"`     // 最多返回的匹配数 / Maximum number of matches to return
+}
+
+// DataMatch 表达式匹配到的一个值 / A value matched by an expression
+type DataMatch struct {
+	Path  string          `json:"path"`  // 值的规范路径 / Normalized path of the value
+	Value json.RawMessage `json:"value"` // 匹配的值 / Matched value
+}
+
+// QueryDataResponse 查询结构化数据响应 / Query structured data response
+type QueryDataResponse struct {
+	Success   bool        `json:"success"`             // 是否成功 / Whether successful
+	Message   string      `json:"message"`             // 消息 / Message
+	Format    string      `json:"format"`              // 解析使用的格式 / Format the file was parsed as
+	Count     int         `json:"count"`               // 匹配总数 / Total number of matches
+	Matches   []DataMatch `json:"matches"`             // 匹配的值 / Matched values
+	Truncated bool        `json:"truncated,omitempty"` // 匹配数超过限制时为true / True when there were more matches than the limit
+}
+
+// UpdateDataRequest 修改结构化数据请求 / Update structured data request
+type UpdateDataRequest struct {
+	Path      string `json:"path"`                // 文件路径 / File path
+	Query     string `json:"query"`               // 选择要修改的值的表达式 / Expression selecting the values to change
+	Op        string `json:"op"`                  // 操作: set或delete / Operation: set or delete
+	Value     string `json:"value,omitempty"`     // set的值,以JSON文本表示 / Value to set, as JSON text
+	Format    string `json:"format,omitempty"`    // 文件格式,省略时按扩展名判断 / File format, taken from the extension when omitted
+	Delimiter string `json:"delimiter,omitempty"` // CSV分隔符,省略时自动检测 / CSV delimiter, detected when omitted
+	NoHeader  bool   `json:"no_header,omitempty"` // CSV没有表头行 / The CSV has no header row
+	DryRun    bool   `json:"dry_run,omitempty"`   // 仅预览差异而不写入 / Preview the diff without writing
+}
+
+// UpdateDataResponse 修改结构化数据响应 / Update structured data response
+type UpdateDataResponse struct {
+	Success     bool      `json:"success"`               // 是否成功 / Whether successful
+	Message     string    `json:"message"`               // 消息 / Message
+	Format      string    `json:"format"`                // 解析使用的格式 / Format the file was parsed as
+	Updated     int       `json:"updated"`               // 修改的值的数量 / Number of values changed
+	Paths       []string  `json:"paths"`                 // 修改的值的规范路径 / Normalized paths of the changed values
+	Reformatted bool      `json:"reformatted,omitempty"` // 无法保留原格式而重新序列化了整个文件 / The whole file was re-serialised because the original layout could not be kept
+	Diff        string    `json:"diff"`                  // 修改产生的统一差异 / Unified diff of the change
+	DryRun      bool      `json:"dry_run,omitempty"`     // 是否为预览 / Whether this was a dry run
+	Size        int64     `json:"size,omitempty"`        // 修改后的文件大小 / File size after the change
+	Version     string    `json:"version,omitempty"`     // 修改后的版本标识 / Version token after the change
+	SHA256      string    `json:"sha256,omitempty"`      // 修改后内容的SHA-256 / SHA-256 of the content after the change
+	ModTime     time.Time `json:"mod_time,omitempty"`    // 修改后的修改时间 / Modification time after the change
+}
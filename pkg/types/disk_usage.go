@@ -0,0 +1,58 @@
+// Copyright 2024 MCP Toolkit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package types 磁盘用量分析相关类型定义 / Disk usage analysis related type definitions
+package types
+
+// DiskUsageRequest 磁盘用量分析请求 / Disk usage analysis request
+type DiskUsageRequest struct {
+	Path     string   `json:"path,omitempty"`      // 根目录(默认为沙箱根目录) / Root directory (defaults to the sandbox root)
+	Top      int      `json:"top,omitempty"`       // 返回的最大文件、目录和扩展名数 / Number of largest files, directories and extensions to return
+	MaxDepth int      `json:"max_depth,omitempty"` // 遍历的最大深度,0表示不限制 / Maximum depth to walk, 0 for no limit
+	Timeout  int      `json:"timeout,omitempty"`   // 遍历的最长时间(秒) / Maximum walk time in seconds
+	Exclude  []string `json:"exclude,omitempty"`   // 排除的glob模式 / Glob patterns to exclude
+}
+
+// DiskUsageEntry 文件或目录的用量 / Usage of a file or directory
+type DiskUsageEntry struct {
+	Path      string `json:"path"`                 // 相对沙箱的路径 / Path relative to the sandbox
+	Size      int64  `json:"size"`                 // 大小(目录为汇总大小) / Size (aggregate for directories)
+	FileCount int    `json:"file_count,omitempty"` // 目录子树中的文件数 / Number of files in a directory's subtree
+}
+
+// ExtensionUsage 按扩展名汇总的用量 / Usage aggregated by extension
+type ExtensionUsage struct {
+	Extension string `json:"extension"`  // 小写扩展名,没有扩展名时为空 / Lower-case extension, empty for files without one
+	Size      int64  `json:"size"`       // 总大小 / Total size
+	FileCount int    `json:"file_count"` // 文件数 / Number of files
+}
+
+// DiskUsageResponse 磁盘用量分析响应 / Disk usage analysis response
+// 遍历因深度或时间限制未完成时,各项统计只包含已遍历的部分
+// When the walk is cut short by the depth or time limit, every figure only covers what was walked
+type DiskUsageResponse struct {
+	Path               string           `json:"path"`                    // 分析的目录 / Directory analysed
+	TotalSize          int64            `json:"total_size"`              // 文件总大小 / Total size of the files
+	FileCount          int              `json:"file_count"`              // 文件数 / Number of files
+	DirCount           int              `json:"dir_count"`               // 目录数(不含根目录) / Number of directories, excluding the root
+	SymlinkCount       int              `json:"symlink_count,omitempty"` // 未跟随的符号链接数 / Number of symbolic links, which are not followed
+	LargestFiles       []DiskUsageEntry `json:"largest_files"`           // 最大的文件 / Largest files
+	LargestDirectories []DiskUsageEntry `json:"largest_directories"`     // 最大的目录 / Largest directories
+	Extensions         []ExtensionUsage `json:"extensions"`              // 占用最多的扩展名 / Extensions using the most space
+	ExtensionCount     int              `json:"extension_count"`         // 不同扩展名的总数 / Total number of distinct extensions
+	SkippedDirs        int              `json:"skipped_dirs,omitempty"`  // 因深度限制或无法读取而未进入的目录数 / Directories not entered due to the depth limit or read errors
+	DepthLimited       bool             `json:"depth_limited,omitempty"` // 是否有目录超出深度限制 / Whether any directory was beyond the depth limit
+	TimedOut           bool             `json:"timed_out,omitempty"`     // 遍历是否因超时而停止 / Whether the walk stopped because it ran out of time
+	Duration           int64            `json:"duration"`                // 遍历时长(毫秒) / Walk duration in milliseconds
+}
@@ -0,0 +1,73 @@
+// Copyright 2024 MCP Toolkit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package types 文件编辑与差异相关类型定义 / File edit and diff related type definitions
+package types
+
+// TextEdit 单个查找替换编辑 / Single search/replace edit
+type TextEdit struct {
+	OldText             string `json:"old_text"`                       // 要查找的原文本 / Text to search for
+	NewText             string `json:"new_text"`                       // 替换后的文本 / Replacement text
+	ReplaceAll          bool   `json:"replace_all,omitempty"`          // 是否替换所有出现位置 / Whether to replace all occurrences
+	ExpectedOccurrences int    `json:"expected_occurrences,omitempty"` // 期望的出现次数(0表示要求唯一) / Expected occurrence count (0 requires a unique match)
+}
+
+// EditFileRequest 编辑文件请求 / Edit file request
+// Edits 与 Patch 二选一 / Exactly one of Edits and Patch must be provided
+type EditFileRequest struct {
+	Path   string     `json:"path"`              // 文件路径 / File path
+	Edits  []TextEdit `json:"edits,omitempty"`   // 查找替换编辑列表(按顺序应用) / Search/replace edits (applied in order)
+	Patch  string     `json:"patch,omitempty"`   // 统一差异格式补丁 / Unified diff patch
+	DryRun bool       `json:"dry_run,omitempty"` // 仅预览差异而不写入 / Preview the diff without writing
+}
+
+// EditFileResponse 编辑文件响应 / Edit file response
+type EditFileResponse struct {
+	Success      bool   `json:"success"`                 // 是否成功 / Whether successful
+	Message      string `json:"message"`                 // 消息 / Message
+	Diff         string `json:"diff"`                    // 编辑产生的统一差异 / Unified diff of the change
+	Replacements []int  `json:"replacements,omitempty"`  // 每个编辑替换的次数 / Number of replacements per edit
+	HunksApplied int    `json:"hunks_applied,omitempty"` // 应用的补丁块数量 / Number of patch hunks applied
+	DryRun       bool   `json:"dry_run,omitempty"`       // 是否为预览 / Whether this was a dry run
+}
+
+// DiffHunk 统一差异中的一个块 / A single hunk of a unified diff
+type DiffHunk struct {
+	OldStart int      `json:"old_start"` // 原文件起始行 / Start line in the old file
+	OldLines int      `json:"old_lines"` // 原文件行数 / Line count in the old file
+	NewStart int      `json:"new_start"` // 新文件起始行 / Start line in the new file
+	NewLines int      `json:"new_lines"` // 新文件行数 / Line count in the new file
+	Lines    []string `json:"lines"`     // 带前缀(' ', '-', '+')的行 / Lines prefixed with ' ', '-' or '+'
+}
+
+// DiffFilesRequest 比较文件差异请求 / Diff files request
+// 每一侧提供路径或内联内容之一 / Each side takes either a path or inline content
+type DiffFilesRequest struct {
+	OldPath          string  `json:"old_path,omitempty"`          // 原文件路径 / Old file path
+	OldContent       *string `json:"old_content,omitempty"`       // 原内容(内联) / Old content (inline)
+	NewPath          string  `json:"new_path,omitempty"`          // 新文件路径 / New file path
+	NewContent       *string `json:"new_content,omitempty"`       // 新内容(内联) / New content (inline)
+	Context          *int    `json:"context,omitempty"`           // 上下文行数(默认3) / Context lines (3 by default)
+	IgnoreWhitespace bool    `json:"ignore_whitespace,omitempty"` // 比较时忽略空白差异 / Ignore whitespace differences when comparing
+}
+
+// DiffFilesResponse 比较文件差异响应 / Diff files response
+type DiffFilesResponse struct {
+	Identical bool       `json:"identical"`        // 是否没有差异 / Whether there are no differences
+	Binary    bool       `json:"binary,omitempty"` // 任一侧为二进制内容时只比较是否相同 / When either side is binary only equality is reported
+	Diff      string     `json:"diff"`             // 统一差异文本 / Unified diff text
+	Hunks     []DiffHunk `json:"hunks"`            // 结构化差异块 / Structured hunks
+	Added     int        `json:"added"`            // 新增行数 / Added line count
+	Removed   int        `json:"removed"`          // 删除行数 / Removed line count
+}
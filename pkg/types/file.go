@@ -15,6 +15,8 @@
 // Package types 文件操作相关类型定义 / File operation related type definitions
 package types
 
+import "time"
+
 // CreateFileRequest 创建文件请求 / Create file request
 type CreateFileRequest struct {
 	Path    string `json:"path"`    // 文件路径 / File path
@@ -27,14 +29,34 @@ type CreateDirRequest struct {
 }
 
 // ReadFileRequest 读取文件请求 / Read file request
+// 行范围(start_line/end_line)、末尾行数(tail_lines)和字节范围(offset/length)三种模式互斥
+// Line range (start_line/end_line), tail lines (tail_lines) and byte range (offset/length) modes are mutually exclusive
 type ReadFileRequest struct {
-	Path string `json:"path"` // 文件路径 / File path
+	Path      string `json:"path"`                 // 文件路径 / File path
+	StartLine int    `json:"start_line,omitempty"` // 起始行号(从1开始) / Start line number (1-based)
+	EndLine   int    `json:"end_line,omitempty"`   // 结束行号(包含,0表示到文件末尾) / End line number (inclusive, 0 means end of file)
+	TailLines int    `json:"tail_lines,omitempty"` // 读取文件末尾的行数 / Number of lines to read from the end of file
+	Offset    int64  `json:"offset,omitempty"`     // 字节偏移量 / Byte offset
+	Length    int64  `json:"length,omitempty"`     // 读取的最大字节数(0表示默认值) / Maximum bytes to read (0 means default)
+	// 总行数需要扫描整个文件,只在读取已到达文件末尾或设置了CountLines时返回
+	// The total line count needs a scan of the whole file and is only returned when the read reached the end of the file or CountLines is set
+	CountLines bool `json:"count_lines,omitempty"` // 总是统计文件总行数 / Always count the total lines of the file
+	// SHA-256需要读取整个文件,只在设置了IncludeSHA256时计算 / The SHA-256 needs a read of the whole file and is only computed when IncludeSHA256 is set
+	IncludeSHA256 bool `json:"include_sha256,omitempty"` // 返回整个文件的SHA-256 / Return the SHA-256 of the whole file
 }
 
 // WriteFileRequest 写入文件请求 / Write file request
+// ExpectedVersion、ExpectedSHA256 和 ExpectedMtime 为可选的前置条件,文件在读取后被修改时写入以冲突错误失败
+// ExpectedVersion, ExpectedSHA256 and ExpectedMtime are optional preconditions; the write fails with a conflict error if the file changed since it was read
 type WriteFileRequest struct {
-	Path    string `json:"path"`    // 文件路径 / File path
-	Content string `json:"content"` // 文件内容 / File content
+	Path            string     `json:"path"`                       // 文件路径 / File path
+	Content         string     `json:"content"`                    // 文件内容 / File content
+	ExpectedVersion string     `json:"expected_version,omitempty"` // 期望的当前版本标识(只需stat) / Expected current version token (needs only a stat)
+	ExpectedSHA256  string     `json:"expected_sha256,omitempty"`  // 期望的当前内容SHA-256 / Expected SHA-256 of the current content
+	ExpectedMtime   *time.Time `json:"expected_mtime,omitempty"`   // 期望的当前修改时间 / Expected current modification time
+	Charset         string     `json:"charset,omitempty"`          // 写入的字符集,默认UTF-8 / Charset to write, UTF-8 by default
+	BOM             bool       `json:"bom,omitempty"`              // 是否写入字节顺序标记(仅UTF编码) / Whether to write a byte order mark (UTF charsets only)
+	LineEnding      string     `json:"line_ending,omitempty"`      // 换行符风格: lf 或 crlf,为空时保持不变 / Line ending style: lf or crlf, unchanged when empty
 }
 
 // DeleteRequest 删除请求（自动判断文件或目录）/ Delete request (auto-detect file or directory)
@@ -89,15 +111,51 @@ type MoveDirectoryRequest struct {
 	Destination string `json:"destination"` // 目标目录路径 / Destination directory path
 }
 
+// 条目类型过滤 / Entry type filters
+const (
+	EntryTypeFile      = "file"      // 文件 / File
+	EntryTypeDirectory = "directory" // 目录 / Directory
+)
+
+// 排序字段与方向 / Sort fields and orders
+const (
+	SortByName    = "name"  // 按名称排序 / Sort by name
+	SortByPath    = "path"  // 按路径排序 / Sort by path
+	SortBySize    = "size"  // 按大小排序 / Sort by size
+	SortByModTime = "mtime" // 按修改时间排序 / Sort by modification time
+	SortOrderAsc  = "asc"   // 升序 / Ascending
+	SortOrderDesc = "desc"  // 降序 / Descending
+)
+
 // ListDirRequest 列出目录请求 / List directory request
 type ListDirRequest struct {
-	Path string `json:"path"` // 目录路径 / Directory path
+	Path          string `json:"path"`                     // 目录路径 / Directory path
+	Cursor        string `json:"cursor,omitempty"`         // 上一页返回的分页游标 / Pagination cursor returned by the previous page
+	Limit         int    `json:"limit,omitempty"`          // 每页最大条目数 / Maximum entries per page
+	SortBy        string `json:"sort_by,omitempty"`        // 排序字段(name/size/mtime) / Sort field (name/size/mtime)
+	SortOrder     string `json:"sort_order,omitempty"`     // 排序方向(asc/desc) / Sort order (asc/desc)
+	Type          string `json:"type,omitempty"`           // 条目类型(file/directory) / Entry type (file/directory)
+	Pattern       string `json:"pattern,omitempty"`        // 名称glob过滤 / Name glob filter
+	IncludeHidden *bool  `json:"include_hidden,omitempty"` // 是否包含以"."开头的隐藏条目,未设置时包含 / Whether to include entries starting with ".", included when unset
 }
 
 // SearchRequest 搜索请求 / Search request
+// 不含"/"的模式匹配文件名,含"/"的模式匹配相对搜索路径的路径,均支持**
+// Patterns without "/" match the file name, patterns with "/" match the path relative to the search path; both support **
 type SearchRequest struct {
-	Path    string `json:"path"`    // 搜索路径 / Search path
-	Pattern string `json:"pattern"` // 搜索模式 / Search pattern
+	Path           string   `json:"path"`                      // 搜索路径 / Search path
+	Pattern        string   `json:"pattern,omitempty"`         // 搜索模式 / Search pattern
+	Include        []string `json:"include,omitempty"`         // 额外的包含模式,匹配任一即可 / Additional include patterns, any may match
+	Exclude        []string `json:"exclude,omitempty"`         // 排除模式,匹配的目录不再深入 / Exclude patterns, matching directories are not descended into
+	Type           string   `json:"type,omitempty"`            // 条目类型(file/directory) / Entry type (file/directory)
+	MinSize        int64    `json:"min_size,omitempty"`        // 最小文件大小(字节) / Minimum file size in bytes
+	MaxSize        int64    `json:"max_size,omitempty"`        // 最大文件大小(字节) / Maximum file size in bytes
+	ModifiedAfter  string   `json:"modified_after,omitempty"`  // 修改时间下限(RFC3339) / Modified at or after (RFC3339)
+	ModifiedBefore string   `json:"modified_before,omitempty"` // 修改时间上限(RFC3339) / Modified before (RFC3339)
+	MaxDepth       int      `json:"max_depth,omitempty"`       // 最大搜索深度(0表示不限) / Maximum search depth (0 means unlimited)
+	MaxResults     int      `json:"max_results,omitempty"`     // 最大返回数量 / Maximum number of results
+	SortBy         string   `json:"sort_by,omitempty"`         // 排序字段(path/name/size/mtime) / Sort field (path/name/size/mtime)
+	SortOrder      string   `json:"sort_order,omitempty"`      // 排序方向(asc/desc) / Sort order (asc/desc)
 }
 
 // BatchDeleteRequest 批量删除请求 / Batch delete request
@@ -107,7 +165,8 @@ type BatchDeleteRequest struct {
 
 // FileStatRequest 获取文件状态请求 / Get file status request
 type FileStatRequest struct {
-	Path string `json:"path"` // 文件路径 / File path
+	Path          string `json:"path"`                     // 文件路径 / File path
+	IncludeSHA256 bool   `json:"include_sha256,omitempty"` // 对文件返回内容SHA-256 / Return the content SHA-256 of a file
 }
 
 // FileExistsRequest 检查文件是否存在请求 / Check file exists request
@@ -122,7 +181,13 @@ type CreateFileResponse = OperationResponse
 type CreateDirResponse = OperationResponse
 
 // WriteFileResponse 写入文件响应 / Write file response
-type WriteFileResponse = OperationResponse
+type WriteFileResponse struct {
+	Success bool      `json:"success"`  // 是否成功 / Whether successful
+	Message string    `json:"message"`  // 消息 / Message
+	Version string    `json:"version"`  // 写入后的版本标识,可用作下次写入的前置条件 / Version token after the write, usable as the next write's precondition
+	SHA256  string    `json:"sha256"`   // 写入后内容的SHA-256 / SHA-256 of the written content
+	ModTime time.Time `json:"mod_time"` // 写入后的修改时间 / Modification time after the write
+}
 
 // DeleteResponse 删除响应 / Delete response
 type DeleteResponse = OperationResponse
@@ -164,17 +229,44 @@ type FileExistsResponse struct {
 
 // ReadFileResponse 读取文件响应 / Read file response
 type ReadFileResponse struct {
-	Content string `json:"content"` // 文件内容 / File content
+	Content    string `json:"content,omitempty"`     // 文件内容(文本或base64) / File content (text or base64)
+	Encoding   string `json:"encoding"`              // 内容编码: utf-8 或 base64 / Content encoding: utf-8 or base64
+	Charset    string `json:"charset,omitempty"`     // 检测到的文件字符集(仅文本文件),内容已转换为UTF-8 / Detected charset of the file (text files only); the content is converted to UTF-8
+	BOM        bool   `json:"bom,omitempty"`         // 文件是否以字节顺序标记开头 / Whether the file starts with a byte order mark
+	MimeType   string `json:"mime_type,omitempty"`   // 检测到的MIME类型 / Detected MIME type
+	Size       int64  `json:"size"`                  // 文件总大小(字节) / Total file size in bytes
+	TotalLines int    `json:"total_lines,omitempty"` // 文件总行数(仅文本文件,见ReadFileRequest.CountLines) / Total line count (text files only, see ReadFileRequest.CountLines)
+	StartLine  int    `json:"start_line,omitempty"`  // 返回内容的起始行号 / Start line of returned content
+	EndLine    int    `json:"end_line,omitempty"`    // 返回内容的结束行号 / End line of returned content
+	Offset     int64  `json:"offset"`                // 返回内容的起始字节偏移 / Byte offset of returned content
+	Length     int64  `json:"length"`                // 返回内容的字节数 / Byte length of returned content
+	Truncated  bool   `json:"truncated"`             // 是否未返回全部请求内容 / Whether the requested content was truncated
+	// 可用作write_file前置条件的版本信息 / Version information usable as write_file preconditions
+	Version string    `json:"version"`          // 由大小和纳秒修改时间组成的版本标识 / Version token made of the size and the nanosecond modification time
+	SHA256  string    `json:"sha256,omitempty"` // 整个文件的SHA-256(仅IncludeSHA256) / SHA-256 of the whole file (IncludeSHA256 only)
+	ModTime time.Time `json:"mod_time"`         // 修改时间 / Modification time
 }
 
+const (
+	// ContentEncodingUTF8 UTF-8文本内容编码 / UTF-8 text content encoding
+	ContentEncodingUTF8 = "utf-8"
+
+	// ContentEncodingBase64 Base64内容编码(用于二进制内容) / Base64 content encoding (for binary content)
+	ContentEncodingBase64 = "base64"
+)
+
 // ListDirResponse 列出目录响应 / List directory response
 type ListDirResponse struct {
-	Files []FileInfo `json:"files"` // 文件列表 / File list
+	Files      []FileInfo `json:"files"`                 // 文件列表 / File list
+	Total      int        `json:"total"`                 // 过滤后的条目总数 / Total entries after filtering
+	NextCursor string     `json:"next_cursor,omitempty"` // 下一页游标,为空表示没有更多 / Cursor of the next page, empty when there are no more
 }
 
 // SearchResponse 搜索响应 / Search response
 type SearchResponse struct {
-	Files []FileInfo `json:"files"` // 匹配的文件列表 / Matched file list
+	Files        []FileInfo `json:"files"`         // 匹配的文件列表 / Matched file list
+	TotalMatches int        `json:"total_matches"` // 匹配总数(截断前) / Total number of matches (before truncation)
+	Truncated    bool       `json:"truncated"`     // 是否因达到上限而截断 / Whether results were cut off by the limit
 }
 
 // DownloadFileRequest 下载文件请求 / Download file request
@@ -0,0 +1,69 @@
+// Copyright 2024 MCP Toolkit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package types 追加、插入、删除行和截断文件相关类型定义 / Append, insert lines, delete lines and truncate file related type definitions
+package types
+
+import "time"
+
+// AppendFileRequest 追加文件请求 / Append file request
+type AppendFileRequest struct {
+	Path          string `json:"path"`                     // 文件路径,不存在时创建 / File path, created when missing
+	Content       string `json:"content"`                  // 追加的内容 / Content to append
+	EnsureNewline bool   `json:"ensure_newline,omitempty"` // 文件不以换行结尾时先补一个换行 / Add a newline first when the file does not end with one
+}
+
+// InsertLinesRequest 插入行请求 / Insert lines request
+type InsertLinesRequest struct {
+	Path    string `json:"path"`    // 文件路径 / File path
+	Line    int    `json:"line"`    // 在该行之前插入(从1开始,总行数+1表示追加到末尾) / Insert before this line (1-based, total lines + 1 appends at the end)
+	Content string `json:"content"` // 插入的内容,缺少结尾换行时自动补上 / Content to insert; a missing trailing newline is added
+}
+
+// DeleteLinesRequest 删除行请求 / Delete lines request
+type DeleteLinesRequest struct {
+	Path      string `json:"path"`               // 文件路径 / File path
+	StartLine int    `json:"start_line"`         // 起始行号(从1开始) / Start line number (1-based)
+	EndLine   int    `json:"end_line,omitempty"` // 结束行号(包含,0表示只删除起始行) / End line number (inclusive, 0 deletes only the start line)
+}
+
+// TruncateFileRequest 截断文件请求 / Truncate file request
+type TruncateFileRequest struct {
+	Path string `json:"path"`           // 文件路径 / File path
+	Size int64  `json:"size,omitempty"` // 截断后的大小(字节),大于当前大小时以零字节扩展 / Size after truncation in bytes; a larger size extends the file with zero bytes
+}
+
+// FileChangeResponse 增量修改文件响应 / Incremental file change response
+type FileChangeResponse struct {
+	Success    bool      `json:"success"`               // 是否成功 / Whether successful
+	Message    string    `json:"message"`               // 消息 / Message
+	Size       int64     `json:"size"`                  // 修改后的文件大小 / File size after the change
+	TotalLines int       `json:"total_lines,omitempty"` // 修改后的总行数(仅按行操作) / Total lines after the change (line operations only)
+	Lines      int       `json:"lines,omitempty"`       // 插入或删除的行数 / Number of lines inserted or deleted
+	Version    string    `json:"version"`               // 修改后的版本标识 / Version token after the change
+	SHA256     string    `json:"sha256,omitempty"`      // 修改后内容的SHA-256(仅按行操作) / SHA-256 of the content after the change (line operations only)
+	ModTime    time.Time `json:"mod_time"`              // 修改后的修改时间 / Modification time after the change
+}
+
+// AppendFileResponse 追加文件响应 / Append file response
+type AppendFileResponse = FileChangeResponse
+
+// InsertLinesResponse 插入行响应 / Insert lines response
+type InsertLinesResponse = FileChangeResponse
+
+// DeleteLinesResponse 删除行响应 / Delete lines response
+type DeleteLinesResponse = FileChangeResponse
+
+// TruncateFileResponse 截断文件响应 / Truncate file response
+type TruncateFileResponse = FileChangeResponse
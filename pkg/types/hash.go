@@ -0,0 +1,75 @@
+// Copyright 2024 MCP Toolkit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package types 文件校验和比较相关类型定义 / File checksum and comparison related type definitions
+package types
+
+// 支持的哈希算法 / Supported hash algorithms
+const (
+	HashMD5    = "md5"    // MD5
+	HashSHA1   = "sha1"   // SHA-1
+	HashSHA256 = "sha256" // SHA-256
+	HashSHA512 = "sha512" // SHA-512
+	HashCRC32  = "crc32"  // CRC-32 (IEEE)
+)
+
+// FileHashRequest 文件哈希请求 / File hash request
+type FileHashRequest struct {
+	Paths     []string `json:"paths"`               // 文件或目录路径列表 / File or directory paths
+	Algorithm string   `json:"algorithm,omitempty"` // 哈希算法(默认sha256) / Hash algorithm (sha256 by default)
+	Recursive bool     `json:"recursive,omitempty"` // 是否递归计算目录中的所有文件 / Whether to hash every file under directories
+}
+
+// FileHashResult 单个文件的哈希结果 / Hash result of a single file
+type FileHashResult struct {
+	Path  string `json:"path"`            // 文件路径 / File path
+	Hash  string `json:"hash,omitempty"`  // 十六进制哈希值 / Hex encoded hash
+	Size  int64  `json:"size"`            // 文件大小 / File size
+	Error string `json:"error,omitempty"` // 失败原因 / Failure reason
+}
+
+// FileHashResponse 文件哈希响应 / File hash response
+type FileHashResponse struct {
+	Algorithm string           `json:"algorithm"` // 使用的算法 / Algorithm used
+	Results   []FileHashResult `json:"results"`   // 哈希结果 / Hash results
+	Truncated bool             `json:"truncated"` // 是否因文件数上限而截断 / Whether results were cut off by the file limit
+}
+
+// FilePair 待比较的文件对 / Pair of files to compare
+type FilePair struct {
+	Source string `json:"source"` // 源文件 / Source file
+	Target string `json:"target"` // 目标文件 / Target file
+}
+
+// CompareFilesRequest 文件比较请求 / Compare files request
+type CompareFilesRequest struct {
+	Pairs []FilePair `json:"pairs"` // 待比较的文件对 / Pairs of files to compare
+}
+
+// CompareFilesResult 单个文件对的比较结果 / Comparison result of a single pair
+type CompareFilesResult struct {
+	Source          string `json:"source"`                     // 源文件 / Source file
+	Target          string `json:"target"`                     // 目标文件 / Target file
+	Identical       bool   `json:"identical"`                  // 内容是否相同 / Whether the contents are identical
+	SourceSize      int64  `json:"source_size"`                // 源文件大小 / Source file size
+	TargetSize      int64  `json:"target_size"`                // 目标文件大小 / Target file size
+	FirstDifference int64  `json:"first_difference,omitempty"` // 第一个不同字节的偏移量(从0开始) / Offset of the first differing byte (0-based)
+	Error           string `json:"error,omitempty"`            // 失败原因 / Failure reason
+}
+
+// CompareFilesResponse 文件比较响应 / Compare files response
+type CompareFilesResponse struct {
+	Results      []CompareFilesResult `json:"results"`       // 比较结果 / Comparison results
+	AllIdentical bool                 `json:"all_identical"` // 是否所有文件对都相同 / Whether every pair is identical
+}
@@ -0,0 +1,51 @@
+// Copyright 2024 MCP Toolkit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package types 路径锁相关类型定义 / Path lock related type definitions
+package types
+
+import "time"
+
+// LockPathRequest 锁定路径请求,锁是咨询性的,只与其他lock_path调用互斥
+// Lock path request; the lock is advisory and only conflicts with other lock_path calls
+type LockPathRequest struct {
+	Path   string `json:"path"`              // 要锁定的文件或目录,锁定目录覆盖其下所有路径 / File or directory to lock; locking a directory covers everything below it
+	Shared bool   `json:"shared,omitempty"`  // 是否为共享锁,共享锁之间兼容 / Whether the lock is shared; shared locks are compatible with each other
+	TTL    int    `json:"ttl,omitempty"`     // 租约时长(秒),默认300 / Lease duration in seconds, defaults to 300
+	Wait   int    `json:"wait,omitempty"`    // 路径被占用时最多等待的秒数,默认不等待 / Seconds to wait while the path is held, no waiting by default
+	Owner  string `json:"owner,omitempty"`   // 持有者标签,出现在冲突信息中 / Holder label shown in conflict messages
+	LockID string `json:"lock_id,omitempty"` // 续约已有的锁 / Renew an existing lock
+}
+
+// LockPathResponse 锁定路径响应 / Lock path response
+type LockPathResponse struct {
+	Success   bool      `json:"success"`    // 是否成功 / Whether successful
+	Message   string    `json:"message"`    // 消息 / Message
+	LockID    string    `json:"lock_id"`    // 锁ID,用于续约和释放 / Lock ID used to renew and release the lock
+	Path      string    `json:"path"`       // 锁定的路径 / Locked path
+	Shared    bool      `json:"shared"`     // 是否为共享锁 / Whether the lock is shared
+	ExpiresAt time.Time `json:"expires_at"` // 租约到期时间 / When the lease expires
+}
+
+// UnlockPathRequest 释放路径锁请求 / Unlock path request
+type UnlockPathRequest struct {
+	LockID string `json:"lock_id"` // 锁ID / Lock ID
+}
+
+// UnlockPathResponse 释放路径锁响应 / Unlock path response
+type UnlockPathResponse struct {
+	Success bool   `json:"success"` // 是否成功 / Whether successful
+	Message string `json:"message"` // 消息 / Message
+	Path    string `json:"path"`    // 释放的路径 / Released path
+}
@@ -78,6 +78,12 @@ const (
 	MCPErrorMsgInternalError = "Internal error"
 )
 
+// MCP通知方法常量 / MCP notification method constants
+const (
+	// MCPNotificationResourceUpdated 已订阅资源发生变化 / A subscribed resource has changed
+	MCPNotificationResourceUpdated = "notifications/resources/updated"
+)
+
 // NewMCPError 创建MCP错误 / Create MCP error
 func NewMCPError(code int, message string, data interface{}) *MCPError {
 	return &MCPError{
@@ -87,6 +93,15 @@ func NewMCPError(code int, message string, data interface{}) *MCPError {
 	}
 }
 
+// NewMCPNotification 创建没有ID的MCP通知 / Create an MCP notification, which carries no ID
+func NewMCPNotification(method string, params interface{}) *MCPRequest {
+	return &MCPRequest{
+		JSONRPC: "2.0",
+		Method:  method,
+		Params:  params,
+	}
+}
+
 // NewMCPResponse 创建MCP响应 / Create MCP response
 func NewMCPResponse(id interface{}, result interface{}) *MCPResponse {
 	return &MCPResponse{
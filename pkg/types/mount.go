@@ -0,0 +1,33 @@
+// Copyright 2024 MCP Toolkit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package types 挂载相关类型定义 / Mount related type definitions
+package types
+
+// MountInfo 挂载信息 / Mount information
+type MountInfo struct {
+	Name     string    `json:"name"`      // 挂载名称 / Mount name
+	Path     string    `json:"path"`      // 宿主机目录 / Host directory
+	Mode     MountMode `json:"mode"`      // 挂载模式 / Mount mode
+	ReadOnly bool      `json:"read_only"` // 是否只读 / Whether read-only
+	Default  bool      `json:"default"`   // 是否为沙箱根目录(无前缀路径所在的挂载) / Whether this is the sandbox root that unprefixed paths resolve to
+}
+
+// ListMountsRequest 列出挂载请求 / List mounts request
+type ListMountsRequest struct{}
+
+// ListMountsResponse 列出挂载响应 / List mounts response
+type ListMountsResponse struct {
+	Mounts []MountInfo `json:"mounts"` // 挂载列表,默认挂载在前 / Mounts, the default mount first
+}
@@ -0,0 +1,36 @@
+// Copyright 2024 MCP Toolkit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package types 磁盘配额相关类型定义 / Disk quota related type definitions
+package types
+
+// QuotaUsage 沙箱配额用量 / Sandbox quota usage
+type QuotaUsage struct {
+	UsedBytes int64 `json:"used_bytes"` // 已用字节数 / Bytes in use
+	UsedFiles int64 `json:"used_files"` // 已用文件和目录数 / Files and directories in use
+	MaxBytes  int64 `json:"max_bytes"`  // 字节上限,0表示不限制 / Byte limit, 0 for no limit
+	MaxFiles  int64 `json:"max_files"`  // 文件数上限,0表示不限制 / File count limit, 0 for no limit
+	Exceeded  bool  `json:"exceeded"`   // 是否超出任一上限 / Whether either limit is exceeded
+}
+
+// GetQuotaUsageRequest 获取配额用量请求 / Get quota usage request
+type GetQuotaUsageRequest struct{}
+
+// GetQuotaUsageResponse 获取配额用量响应 / Get quota usage response
+type GetQuotaUsageResponse struct {
+	QuotaUsage
+	Enabled        bool  `json:"enabled"`         // 是否配置了任一上限 / Whether either limit is configured
+	RemainingBytes int64 `json:"remaining_bytes"` // 剩余字节数,不限制时为-1 / Remaining bytes, -1 when unlimited
+	RemainingFiles int64 `json:"remaining_files"` // 剩余文件数,不限制时为-1 / Remaining files, -1 when unlimited
+}
@@ -88,6 +88,12 @@ type Items struct {
 
 	// Enum 数组元素枚举值 / Array element enumeration values
 	Enum []string `json:"enum,omitempty"`
+
+	// Properties 对象元素的属性定义（仅当 Type 为 "object" 时使用）/ Object element properties (only when Type is "object")
+	Properties map[string]Property `json:"properties,omitempty"`
+
+	// Required 对象元素的必需属性（仅当 Type 为 "object" 时使用）/ Required object element properties (only when Type is "object")
+	Required []string `json:"required,omitempty"`
 }
 
 // ToolSchemas 工具输入模式定义 / Tool input schema definitions
@@ -130,7 +136,7 @@ var ToolSchemas = map[string]JSONSchema{
 
 	"read_file": {
 		Type:        "object",
-		Description: "READ AND RETRIEVE the complete content of a file. Use this tool when you need to: 1) View or inspect file contents, 2) Read source code before modification, 3) Check configuration files, 4) Analyze existing data, 5) Understand file structure before editing. This is the primary tool for reading files - use it whenever you need to see what's inside a file. Keywords: read, view, show, display, get content, inspect file, check file, open file.",
+		Description: "READ AND RETRIEVE the content of a file. Use this tool when you need to: 1) View or inspect file contents, 2) Read source code before modification, 3) Check configuration files, 4) Analyze existing data, 5) Understand file structure before editing. Large files can be read by line range, by the last N lines, or by byte offset and length; the response reports total size, total lines and whether content was truncated. Text is returned as plain text content, decoded to UTF-8 when the file uses another charset (UTF-16, GBK, GB18030 or Shift-JIS; the detected charset and BOM are reported), images as image content and other binary files as embedded resources with a base64 blob, followed by the metadata as JSON. This is the primary tool for reading files - use it whenever you need to see what's inside a file. Keywords: read, view, show, display, get content, inspect file, check file, open file.",
 		Properties: map[string]Property{
 			"path": {
 				Type:        "string",
@@ -138,13 +144,54 @@ var ToolSchemas = map[string]JSONSchema{
 				MinLength:   intPtr(1),
 				Examples:    []any{"src/main.go", "package.json", "README.md", "config.yaml", ".env", "data/users.json"},
 			},
+			"start_line": {
+				Type:        "integer",
+				Description: "First line to read (1-based). Use together with 'end_line' to read a slice of a large text file. Cannot be combined with 'tail_lines' or 'offset'.",
+				Minimum:     float64Ptr(1),
+				Examples:    []any{1, 100, 2500},
+			},
+			"end_line": {
+				Type:        "integer",
+				Description: "Last line to read (inclusive). Omit or use 0 to read until the end of the file or until the byte budget is used up.",
+				Minimum:     float64Ptr(0),
+				Examples:    []any{50, 200, 3000},
+			},
+			"tail_lines": {
+				Type:        "integer",
+				Description: "Read only the last N lines of the file, like 'tail -n'. Ideal for large build or application logs: the file is read backwards from the end, and when 'length' is too small the newest lines are kept. Cannot be combined with 'start_line'/'end_line' or 'offset'.",
+				Minimum:     float64Ptr(1),
+				Examples:    []any{50, 200, 1000},
+			},
+			"offset": {
+				Type:        "integer",
+				Description: "Byte offset to start reading from. Use with 'length' to page through large or binary files; continue from 'offset' + 'length' of the previous response.",
+				Minimum:     float64Ptr(0),
+				Examples:    []any{0, 1048576},
+			},
+			"length": {
+				Type:        "integer",
+				Description: "Maximum number of bytes to return (default 1MB, maximum 10MB). Applies to every read mode; 'truncated' in the response tells whether more content is available.",
+				Minimum:     float64Ptr(0),
+				Maximum:     float64Ptr(10485760),
+				Examples:    []any{65536, 1048576},
+			},
+			"count_lines": {
+				Type:        "boolean",
+				Description: "Always return 'total_lines', scanning the whole file if needed. Without it, 'total_lines' (and the line numbers of a tail on files over 64KB) is only returned when the read reached the start or end of the file anyway. Default is false.",
+				Default:     false,
+			},
+			"include_sha256": {
+				Type:        "boolean",
+				Description: "Also return the sha256 of the whole file, which reads the entire file. The 'version' token in every response is enough for conditional writes. Default is false.",
+				Default:     false,
+			},
 		},
 		Required: []string{"path"},
 	},
 
 	"write_file": {
 		Type:        "object",
-		Description: "WRITE OR UPDATE content to an existing file, completely replacing its current content. Use this tool when you need to: 1) Update an existing file with new content, 2) Modify and save changes to a file, 3) Replace file content after editing. WORKFLOW: First use 'read_file' to get current content, modify it, then use 'write_file' to save. For new files, use 'create_file' instead. Keywords: write, update, modify, save, edit file, change file, replace content.",
+		Description: "WRITE OR UPDATE content to an existing file, completely replacing its current content. Use this tool when you need to: 1) Update an existing file with new content, 2) Modify and save changes to a file, 3) Replace file content after editing. WORKFLOW: First use 'read_file' to get current content, modify it, then use 'write_file' to save. Pass the version from read_file as expected_version to avoid overwriting concurrent changes. The write is atomic (temporary file plus rename). For new files, use 'create_file' instead. Keywords: write, update, modify, save, edit file, change file, replace content.",
 		Properties: map[string]Property{
 			"path": {
 				Type:        "string",
@@ -157,10 +204,182 @@ var ToolSchemas = map[string]JSONSchema{
 				Description: "The new content to write to the file. This will completely replace the existing content. Make sure to include all content you want to keep, as the old content will be lost.",
 				Examples:    []any{"Updated content", "package main\n\nfunc main() {\n\tfmt.Println(\"Updated\")\n}", "{\"version\": \"2.0\", \"updated\": true}"},
 			},
+			"expected_version": {
+				Type:        "string",
+				Description: "Optional precondition: the version returned by read_file, file_stat or a previous write. The write fails with a conflict error if the file changed since then. Only needs a stat, so it is cheap for large files.",
+			},
+			"expected_sha256": {
+				Type:        "string",
+				Description: "Optional precondition: the sha256 returned by read_file or file_stat with include_sha256. The write fails with a conflict error if the file content changed since it was read. Reads the whole file to check.",
+				Pattern:     "^[0-9a-fA-F]{64}$",
+			},
+			"expected_mtime": {
+				Type:        "string",
+				Format:      "date-time",
+				Description: "Optional precondition: the mod_time returned by read_file or file_stat (RFC 3339). The write fails with a conflict error if the file was modified since then.",
+			},
+			"charset": {
+				Type:        "string",
+				Description: "Charset to write the content in. Defaults to utf-8; pass the charset reported by read_file to keep a file in its original encoding. Characters the charset cannot represent fail the write.",
+				Enum:        []string{"utf-8", "utf-16le", "utf-16be", "gbk", "gb18030", "shift_jis"},
+			},
+			"bom": {
+				Type:        "boolean",
+				Description: "Write a byte order mark at the start of the file. Only valid for utf-8, utf-16le and utf-16be.",
+				Default:     false,
+			},
+			"line_ending": {
+				Type:        "string",
+				Description: "Convert every line ending to this style before writing. Omit to keep the line endings of the content as they are.",
+				Enum:        []string{"lf", "crlf"},
+			},
+		},
+		Required: []string{"path", "content"},
+	},
+
+	"edit_file": {
+		Type:        "object",
+		Description: "EDIT PART OF A FILE without resending its whole content. Provide either 'edits' (exact search/replace pairs applied in order) or 'patch' (a unified diff). Use this tool when you need to: 1) Change a few lines in a large file, 2) Rename an identifier in one file, 3) Apply a diff you produced, 4) Preview a change with dry_run. Each old_text must match exactly once unless replace_all or expected_occurrences is set. The edit is atomic - if any edit or hunk fails to apply, nothing is written. The response contains the resulting unified diff. Keywords: edit, modify, replace, patch, diff, change lines, refactor, search and replace.",
+		Properties: map[string]Property{
+			"path": {
+				Type:        "string",
+				Description: "The file path to edit. The file must already exist. Examples: 'src/main.go', 'config/app.yaml'.",
+				MinLength:   intPtr(1),
+				Examples:    []any{"src/main.go", "config/app.yaml", "README.md"},
+			},
+			"edits": {
+				Type:        "array",
+				Description: "Search/replace edits applied in order, each to the result of the previous one. Include enough surrounding text in old_text to make it unique. Cannot be combined with 'patch'.",
+				Items: &Items{
+					Type:        "object",
+					Description: "A single exact search/replace edit",
+					Properties: map[string]Property{
+						"old_text": {
+							Type:        "string",
+							Description: "Exact text to find, including whitespace and indentation.",
+							MinLength:   intPtr(1),
+						},
+						"new_text": {
+							Type:        "string",
+							Description: "Replacement text. Use an empty string to delete old_text.",
+						},
+						"replace_all": {
+							Type:        "boolean",
+							Description: "Replace every occurrence instead of requiring a unique match. Default is false.",
+							Default:     false,
+						},
+						"expected_occurrences": {
+							Type:        "integer",
+							Description: "Number of occurrences old_text must have; all of them are replaced. The edit fails if the count differs.",
+							Minimum:     float64Ptr(1),
+						},
+					},
+					Required: []string{"old_text", "new_text"},
+				},
+				Examples: []any{[]map[string]any{{"old_text": "func oldName(", "new_text": "func newName("}}},
+			},
+			"patch": {
+				Type:        "string",
+				Description: "A unified diff ('@@ -start,count +start,count @@' hunks with ' ', '-' and '+' lines) to apply to the file. File headers ('---'/'+++') are optional. Hunks are located by their context, so slightly wrong line numbers are tolerated. Cannot be combined with 'edits'.",
+				Examples:    []any{"@@ -1,3 +1,3 @@\n package main\n-import \"fmt\"\n+import \"log\"\n \n"},
+			},
+			"dry_run": {
+				Type:        "boolean",
+				Description: "Only compute and return the diff without modifying the file. Default is false.",
+				Default:     false,
+			},
+		},
+		Required: []string{"path"},
+	},
+
+	"append_file": {
+		Type:        "object",
+		Description: "Append content to the end of a file, creating it if missing.",
+		Properties: map[string]Property{
+			"path": {
+				Type:        "string",
+				Description: "The file to append to. Created together with its parent directories if it does not exist.",
+				MinLength:   intPtr(1),
+				Examples:    []any{"logs/build.log", "output.txt"},
+			},
+			"content": {
+				Type:        "string",
+				Description: "The content to append.",
+			},
+			"ensure_newline": {
+				Type:        "boolean",
+				Description: "Insert a newline before the content when the file does not already end with one.",
+				Default:     false,
+			},
 		},
 		Required: []string{"path", "content"},
 	},
 
+	"insert_lines": {
+		Type:        "object",
+		Description: "Insert content before a line of a text file.",
+		Properties: map[string]Property{
+			"path": {
+				Type:        "string",
+				Description: "The text file to modify.",
+				MinLength:   intPtr(1),
+			},
+			"line": {
+				Type:        "integer",
+				Description: "The 1-based line to insert before. Use the total line count + 1 to append at the end.",
+				Minimum:     float64Ptr(1),
+			},
+			"content": {
+				Type:        "string",
+				Description: "The lines to insert. A trailing newline is added if missing.",
+				MinLength:   intPtr(1),
+			},
+		},
+		Required: []string{"path", "line", "content"},
+	},
+
+	"delete_lines": {
+		Type:        "object",
+		Description: "Delete an inclusive range of lines from a text file.",
+		Properties: map[string]Property{
+			"path": {
+				Type:        "string",
+				Description: "The text file to modify.",
+				MinLength:   intPtr(1),
+			},
+			"start_line": {
+				Type:        "integer",
+				Description: "The first line to delete (1-based).",
+				Minimum:     float64Ptr(1),
+			},
+			"end_line": {
+				Type:        "integer",
+				Description: "The last line to delete (inclusive). Defaults to start_line.",
+				Minimum:     float64Ptr(1),
+			},
+		},
+		Required: []string{"path", "start_line"},
+	},
+
+	"truncate_file": {
+		Type:        "object",
+		Description: "Truncate a file to a size in bytes.",
+		Properties: map[string]Property{
+			"path": {
+				Type:        "string",
+				Description: "The file to truncate.",
+				MinLength:   intPtr(1),
+			},
+			"size": {
+				Type:        "integer",
+				Description: "The size in bytes after truncation. 0 (default) empties the file.",
+				Minimum:     float64Ptr(0),
+				Default:     0,
+			},
+		},
+		Required: []string{"path"},
+	},
+
 	"delete": {
 		Type:        "object",
 		Description: "[RECOMMENDED - PRIMARY DELETION TOOL] DELETE any file or directory automatically. This is the MAIN deletion tool - use it for ALL deletion operations. The tool intelligently detects whether the path is a file or directory and handles it appropriately. For directories, it performs recursive deletion (removes all contents). Use this tool when you need to: 1) Remove any file, 2) Remove any directory and its contents, 3) Clean up temporary files, 4) Delete build artifacts, 5) Remove old backups. Keywords: delete, remove, erase, clean, rm, unlink, destroy, eliminate.",
@@ -298,96 +517,603 @@ var ToolSchemas = map[string]JSONSchema{
 				MinLength:   intPtr(1),
 				Examples:    []any{"old_config.json", "temp.txt", "draft.md"},
 			},
-			"destination": {
+			"destination": {
+				Type:        "string",
+				Description: "The destination file path where the file will be moved to.",
+				MinLength:   intPtr(1),
+				Examples:    []any{"config.json", "archive/temp.txt", "published.md"},
+			},
+		},
+		Required: []string{"source", "destination"},
+	},
+
+	"move_directory": {
+		Type:        "object",
+		Description: "Move or rename a directory. Only works on directories, not files. Use 'move' tool if you're unsure whether the path is a file or directory.",
+		Properties: map[string]Property{
+			"source": {
+				Type:        "string",
+				Description: "The source directory path to move. Must be a directory, not a file.",
+				MinLength:   intPtr(1),
+				Examples:    []any{"old_src/", "temp_build/", "draft_docs/"},
+			},
+			"destination": {
+				Type:        "string",
+				Description: "The destination directory path where the directory will be moved to.",
+				MinLength:   intPtr(1),
+				Examples:    []any{"src/", "build/", "docs/"},
+			},
+		},
+		Required: []string{"source", "destination"},
+	},
+
+	"list_directory": {
+		Type:        "object",
+		Description: "LIST AND EXPLORE directory contents. Shows files and subdirectories with detailed information including names, types (file/directory), sizes, and modification times. Results are paginated with a cursor and can be sorted by name, size or mtime and filtered by type and name pattern; hidden entries are listed unless include_hidden is false. Use this tool when you need to: 1) See what files are in a directory, 2) Explore project structure, 3) Find specific files, 4) Check directory contents before operations, 5) Understand folder organization. This is the primary tool for directory exploration. Keywords: list, ls, dir, show files, directory contents, browse, explore folder, view directory.",
+		Properties: map[string]Property{
+			"path": {
+				Type:        "string",
+				Description: "The directory path to list. Use '.' for current directory, '..' for parent directory, or specify any directory path. Examples: '.' (current directory), 'src/' (source directory), '/home/user/projects' (absolute path), '..' (parent directory).",
+				MinLength:   intPtr(1),
+				Examples:    []any{".", "src/", "/home/user/projects", "..", "docs/", "build/"},
+			},
+			"cursor": {
+				Type:        "string",
+				Description: "Pagination cursor. Pass the 'next_cursor' value from the previous response to get the next page; omit for the first page.",
+			},
+			"limit": {
+				Type:        "integer",
+				Description: "Maximum number of entries per page (1-10000). Default is 1000. The response contains 'total' and, when more entries exist, 'next_cursor'.",
+				Minimum:     float64Ptr(0),
+				Maximum:     float64Ptr(10000),
+				Default:     1000,
+			},
+			"sort_by": {
+				Type:        "string",
+				Description: "Sort field: 'name' (default), 'size' or 'mtime'.",
+				Enum:        []string{"name", "size", "mtime"},
+				Default:     "name",
+			},
+			"sort_order": {
+				Type:        "string",
+				Description: "Sort order: 'asc' (default) or 'desc'. Use sort_by 'mtime' with 'desc' to see the most recently modified entries first.",
+				Enum:        []string{"asc", "desc"},
+				Default:     "asc",
+			},
+			"type": {
+				Type:        "string",
+				Description: "Only list entries of this type: 'file' or 'directory'. Default lists both.",
+				Enum:        []string{"file", "directory"},
+			},
+			"pattern": {
+				Type:        "string",
+				Description: "Only list entries whose name matches this glob pattern. Examples: '*.go', 'test_*'.",
+				Examples:    []any{"*.go", "test_*", "*.json"},
+			},
+			"include_hidden": {
+				Type:        "boolean",
+				Description: "Include hidden entries whose name starts with '.'. Set to false to skip them. Default is true.",
+				Default:     true,
+			},
+		},
+		Required: []string{"path"},
+	},
+
+	"directory_tree": {
+		Type:        "object",
+		Description: "SHOW A RECURSIVE DIRECTORY TREE in one call. Returns the tree as structured JSON and/or compact indented text, with aggregate sizes and file counts for every directory. Noise such as .git and node_modules is excluded by default. Use this tool when you need to: 1) Orient yourself in an unfamiliar project, 2) Understand a project's layout, 3) Find which directories are large, 4) Replace many list_directory calls. Keywords: tree, directory structure, project layout, overview, explore, folder hierarchy.",
+		Properties: map[string]Property{
+			"path": {
+				Type:        "string",
+				Description: "The root directory of the tree. Defaults to the sandbox root. Examples: '.', 'src/'.",
+				Examples:    []any{".", "src/", "internal/"},
+			},
+			"max_depth": {
+				Type:        "integer",
+				Description: "Maximum depth to show (1-20); 1 shows only the direct children. Default is 3. Sizes and file counts still include deeper levels.",
+				Minimum:     float64Ptr(0),
+				Maximum:     float64Ptr(20),
+				Default:     3,
+			},
+			"max_entries": {
+				Type:        "integer",
+				Description: "Maximum number of entries to return (1-5000), filled breadth-first so shallow levels are shown first. Default is 500.",
+				Minimum:     float64Ptr(0),
+				Maximum:     float64Ptr(5000),
+				Default:     500,
+			},
+			"exclude": {
+				Type:        "array",
+				Description: "Additional glob patterns to exclude; patterns without '/' match names at any depth. Examples: ['dist', '*.log'].",
+				Items:       &Items{Type: "string", Description: "A glob pattern"},
+				Examples:    []any{[]string{"dist", "*.log"}},
+			},
+			"no_default_excludes": {
+				Type:        "boolean",
+				Description: "Include entries that are excluded by default (.git, .svn, .hg, node_modules, __pycache__, .venv, .DS_Store). Default is false.",
+				Default:     false,
+			},
+			"format": {
+				Type:        "string",
+				Description: "Output format: 'json' (structured tree), 'text' (compact indented text) or 'both'. Default is 'both'.",
+				Enum:        []string{"json", "text", "both"},
+				Default:     "both",
+			},
+		},
+	},
+
+	"search_files": {
+		Type:        "object",
+		Description: "SEARCH AND FIND files and directories by name or path pattern. Recursively searches through all subdirectories using glob patterns with ** support, and can filter by type, size and modification time. Use this tool when you need to: 1) Find files by extension (*.go, *.js), 2) Locate specific files by name pattern, 3) Find files under a path pattern (src/**/test/*.ts), 4) Find large or recently modified files, 5) Discover files across project. Patterns without '/' match the file name; patterns with '/' match the path relative to 'path'. Wildcards: * (any characters except /), ? (single character), [abc] (character class), ** (any number of directories). Invalid patterns are reported as errors. Keywords: search, find, locate, glob, filter, pattern match, find large files, recently modified.",
+		Properties: map[string]Property{
+			"path": {
+				Type:        "string",
+				Description: "The directory path to start searching from. The search will recursively include all subdirectories. Use '.' for current directory, or specify any directory path. Examples: '.' (search from current), 'src/' (search in source), '/home/user/projects' (absolute path).",
+				MinLength:   intPtr(1),
+				Examples:    []any{".", "src/", "/home/user/projects", "docs/", "tests/"},
+			},
+			"pattern": {
+				Type:        "string",
+				Description: "The search pattern using glob syntax. Patterns without '/' match the file name at any depth; patterns with '/' match the path relative to 'path'. Wildcards: * matches any characters except '/', ? matches a single character, ** matches any number of directories. Examples: '*.go' (all Go files), 'test_*.py' (test files starting with test_), '**/*.md' (all Markdown files), 'src/**/*.test.js' (test files under src). Optional when 'include' is given.",
+				Examples:    []any{"*.go", "*.js", "test_*.py", "**/*.md", "config.*", "*.json", "src/**/*.test.js"},
+			},
+			"include": {
+				Type:        "array",
+				Description: "Additional glob patterns; an entry matches if it matches 'pattern' or any of these. Examples: ['*.yaml', '*.yml'].",
+				Items:       &Items{Type: "string", Description: "A glob pattern"},
+				Examples:    []any{[]string{"*.yaml", "*.yml"}},
+			},
+			"exclude": {
+				Type:        "array",
+				Description: "Glob patterns to exclude. Matching directories are not descended into. Examples: ['node_modules', 'vendor', '**/*.min.js'].",
+				Items:       &Items{Type: "string", Description: "A glob pattern"},
+				Examples:    []any{[]string{"node_modules", "vendor", ".git"}},
+			},
+			"type": {
+				Type:        "string",
+				Description: "Only return entries of this type: 'file' or 'directory'. Default returns both.",
+				Enum:        []string{"file", "directory"},
+			},
+			"min_size": {
+				Type:        "integer",
+				Description: "Only return files of at least this many bytes. Directories are excluded when a size filter is set.",
+				Minimum:     float64Ptr(0),
+			},
+			"max_size": {
+				Type:        "integer",
+				Description: "Only return files of at most this many bytes. Directories are excluded when a size filter is set.",
+				Minimum:     float64Ptr(0),
+			},
+			"modified_after": {
+				Type:        "string",
+				Description: "Only return entries modified at or after this time (RFC3339). Example: '2024-01-01T00:00:00Z'.",
+				Format:      "date-time",
+			},
+			"modified_before": {
+				Type:        "string",
+				Description: "Only return entries modified before this time (RFC3339). Example: '2024-12-31T23:59:59Z'.",
+				Format:      "date-time",
+			},
+			"max_depth": {
+				Type:        "integer",
+				Description: "Maximum directory depth to search below 'path'; 1 means only direct children. Default is unlimited (0).",
+				Minimum:     float64Ptr(0),
+			},
+			"max_results": {
+				Type:        "integer",
+				Description: "Maximum number of entries to return (1-10000). Default is 1000. The response reports 'total_matches' and sets 'truncated' when more entries matched.",
+				Minimum:     float64Ptr(0),
+				Maximum:     float64Ptr(10000),
+				Default:     1000,
+			},
+			"sort_by": {
+				Type:        "string",
+				Description: "Sort field: 'path' (default), 'name', 'size' or 'mtime'. Sorting is applied before truncation.",
+				Enum:        []string{"path", "name", "size", "mtime"},
+				Default:     "path",
+			},
+			"sort_order": {
+				Type:        "string",
+				Description: "Sort order: 'asc' (default) or 'desc'. Use sort_by 'size' with 'desc' to find the largest files.",
+				Enum:        []string{"asc", "desc"},
+				Default:     "asc",
+			},
+		},
+		Required: []string{"path"},
+	},
+
+	"search_content": {
+		Type:        "object",
+		Description: "SEARCH INSIDE FILES for text or a regular expression (like grep/ripgrep). Recursively walks the directory, skips binary files, .git and anything ignored by .gitignore, and returns the file path, line number, column and optional context lines of every matching line. Use this tool when you need to: 1) Find where a function, variable or string is used, 2) Locate TODOs or error messages, 3) Find configuration keys, 4) Explore an unfamiliar codebase. Keywords: grep, search text, find in files, regex, ripgrep, find usages, code search.",
+		Properties: map[string]Property{
+			"path": {
+				Type:        "string",
+				Description: "The directory (or single file) to search. Defaults to the sandbox root. Examples: '.', 'src/', 'internal/service.go'.",
+				Examples:    []any{".", "src/", "internal/"},
+			},
+			"pattern": {
+				Type:        "string",
+				Description: "The regular expression (RE2 syntax) to search for, or plain text when 'literal' is true. Examples: 'func \\w+Handler', 'TODO', 'import .*fmt'.",
+				MinLength:   intPtr(1),
+				Examples:    []any{"TODO", "func \\w+Handler", "errors\\.New\\("},
+			},
+			"literal": {
+				Type:        "boolean",
+				Description: "Treat the pattern as literal text instead of a regular expression. Default is false.",
+				Default:     false,
+			},
+			"ignore_case": {
+				Type:        "boolean",
+				Description: "Match case-insensitively. Default is false.",
+				Default:     false,
+			},
+			"context_lines": {
+				Type:        "integer",
+				Description: "Number of lines of context to return before and after each matching line (0-20). Default is 0.",
+				Minimum:     float64Ptr(0),
+				Maximum:     float64Ptr(20),
+				Default:     0,
+			},
+			"include": {
+				Type:        "array",
+				Description: "Only search files matching at least one of these glob patterns. Patterns without '/' match the file name, others match the path relative to 'path' and may use '**'. Examples: ['*.go'], ['src/**/*.ts', '*.tsx'].",
+				Items:       &Items{Type: "string", Description: "A glob pattern"},
+				Examples:    []any{[]string{"*.go"}, []string{"src/**/*.ts", "*.tsx"}},
+			},
+			"exclude": {
+				Type:        "array",
+				Description: "Skip files and directories matching any of these glob patterns, using the same rules as 'include'. Examples: ['vendor', '*_test.go'].",
+				Items:       &Items{Type: "string", Description: "A glob pattern"},
+				Examples:    []any{[]string{"vendor", "*_test.go"}},
+			},
+			"max_results": {
+				Type:        "integer",
+				Description: "Maximum number of matching lines to return (1-1000). Default is 100. The response sets 'truncated' when more matches exist.",
+				Minimum:     float64Ptr(0),
+				Maximum:     float64Ptr(1000),
+				Default:     100,
+			},
+			"max_matches_per_file": {
+				Type:        "integer",
+				Description: "Maximum number of matching lines to return per file. Default is unlimited (0).",
+				Minimum:     float64Ptr(0),
+			},
+			"no_ignore": {
+				Type:        "boolean",
+				Description: "Also search files ignored by .gitignore. Default is false.",
+				Default:     false,
+			},
+		},
+		Required: []string{"pattern"},
+	},
+
+	"batch_delete": {
+		Type:        "object",
+		Description: "Delete multiple files or directories in a single operation. Each path is processed independently, and the tool will report success/failure for each item.",
+		Properties: map[string]Property{
+			"paths": {
+				Type:        "array",
+				Description: "List of file or directory paths to delete. Each path will be processed independently.",
+				Items:       &Items{Type: "string", Description: "A file or directory path to delete"},
+				Examples:    []any{[]string{"temp.txt", "old_backup/", "unused.log"}},
+			},
+		},
+		Required: []string{"paths"},
+	},
+
+	"file_stat": {
+		Type:        "object",
+		Description: "Get detailed information about a file or directory, including size, permissions, modification time, and type (file/directory/symlink).",
+		Properties: map[string]Property{
+			"path": {
+				Type:        "string",
+				Description: "The path of the file or directory to get information about.",
+				MinLength:   intPtr(1),
+				Examples:    []any{"main.go", "src/", "config.json"},
+			},
+			"include_sha256": {
+				Type:        "boolean",
+				Description: "Also return the sha256 of a file's content, which reads the entire file. Files always carry a cheap 'version' token usable as write_file's expected_version. Default is false.",
+				Default:     false,
+			},
+		},
+		Required: []string{"path"},
+	},
+
+	"file_hash": {
+		Type:        "object",
+		Description: "Compute checksums of one or more files. Files are streamed so large files are fine; failures are reported per path.",
+		Properties: map[string]Property{
+			"paths": {
+				Type:        "array",
+				Description: "List of file paths to hash. Directories are hashed file by file when recursive is set.",
+				Items:       &Items{Type: "string", Description: "A file or directory path to hash"},
+				Examples:    []any{[]string{"release.tar.gz"}, []string{"dist/"}},
+			},
+			"algorithm": {
+				Type:        "string",
+				Description: "Hash algorithm to use.",
+				Enum:        []string{HashMD5, HashSHA1, HashSHA256, HashSHA512, HashCRC32},
+				Default:     HashSHA256,
+			},
+			"recursive": {
+				Type:        "boolean",
+				Description: "Hash every regular file under directories in paths. Without it, directories are reported as errors.",
+				Default:     false,
+			},
+		},
+		Required: []string{"paths"},
+	},
+
+	"compare_files": {
+		Type:        "object",
+		Description: "Check whether pairs of files have identical content, reporting sizes and the offset of the first difference.",
+		Properties: map[string]Property{
+			"pairs": {
+				Type:        "array",
+				Description: "List of file pairs to compare. Each pair is compared independently.",
+				Items: &Items{
+					Type:        "object",
+					Description: "A pair of files to compare",
+					Properties: map[string]Property{
+						"source": {Type: "string", Description: "The first file of the pair."},
+						"target": {Type: "string", Description: "The second file of the pair."},
+					},
+					Required: []string{"source", "target"},
+				},
+				Examples: []any{[]map[string]string{{"source": "config.json", "target": "backup/config.json"}}},
+			},
+		},
+		Required: []string{"pairs"},
+	},
+
+	"diff_files": {
+		Type:        "object",
+		Description: "Unified diff between two files or inline contents.",
+		Properties: map[string]Property{
+			"old_path": {
+				Type:        "string",
+				Description: "The original file. When old_content is also given, the path is only used as the label in the diff header.",
+			},
+			"old_content": {
+				Type:        "string",
+				Description: "Inline original content, used instead of reading old_path.",
+			},
+			"new_path": {
+				Type:        "string",
+				Description: "The changed file. When new_content is also given, the path is only used as the label in the diff header.",
+			},
+			"new_content": {
+				Type:        "string",
+				Description: "Inline changed content, e.g. the content you are about to pass to write_file.",
+			},
+			"context": {
+				Type:        "integer",
+				Description: "Number of unchanged context lines around each change. Default is 3.",
+				Minimum:     float64Ptr(0),
+				Maximum:     float64Ptr(1000),
+				Default:     3,
+			},
+			"ignore_whitespace": {
+				Type:        "boolean",
+				Description: "Treat lines that differ only in whitespace as equal.",
+				Default:     false,
+			},
+		},
+	},
+
+	"create_archive": {
+		Type:        "object",
+		Description: "Pack files and directories into a zip, tar, tar.gz or tar.zst archive. Entries are named relative to each source's parent directory.",
+		Properties: map[string]Property{
+			"sources": {
+				Type:        "array",
+				Description: "Files or directories to pack. Directories are packed recursively.",
+				Items:       &Items{Type: "string", Description: "A file or directory path to pack"},
+				Examples:    []any{[]string{"dist/"}, []string{"README.md", "src/"}},
+			},
+			"path": {
+				Type:        "string",
+				Description: "The path of the archive to create.",
+				MinLength:   intPtr(1),
+				Examples:    []any{"release.zip", "backup/src.tar.gz"},
+			},
+			"format": {
+				Type:        "string",
+				Description: "Archive format. Inferred from the path extension (.zip, .tar, .tar.gz/.tgz, .tar.zst/.tzst) when omitted.",
+				Enum:        []string{ArchiveFormatZip, ArchiveFormatTar, ArchiveFormatTarGz, ArchiveFormatTarZst},
+			},
+			"exclude": {
+				Type:        "array",
+				Description: "Glob patterns of entries to leave out, matched against entry names. Patterns without '/' match base names.",
+				Items:       &Items{Type: "string", Description: "A glob pattern to exclude"},
+				Examples:    []any{[]string{"*.log", "node_modules"}},
+			},
+			"overwrite": {
+				Type:        "boolean",
+				Description: "Replace the archive if it already exists.",
+				Default:     false,
+			},
+		},
+		Required: []string{"sources", "path"},
+	},
+
+	"extract_archive": {
+		Type:        "object",
+		Description: "Extract a zip, tar, tar.gz or tar.zst archive, or list its contents without extracting. Entries escaping the destination are rejected and links are skipped.",
+		Properties: map[string]Property{
+			"path": {
+				Type:        "string",
+				Description: "The path of the archive to extract.",
+				MinLength:   intPtr(1),
+				Examples:    []any{"release.zip", "downloads/src.tar.gz"},
+			},
+			"destination": {
+				Type:        "string",
+				Description: "Directory to extract into. Defaults to the directory containing the archive.",
+				Examples:    []any{"release/", "."},
+			},
+			"format": {
+				Type:        "string",
+				Description: "Archive format. Inferred from the path extension when omitted.",
+				Enum:        []string{ArchiveFormatZip, ArchiveFormatTar, ArchiveFormatTarGz, ArchiveFormatTarZst},
+			},
+			"overwrite": {
+				Type:        "boolean",
+				Description: "Replace existing files. Without it, extraction fails before writing anything if a file already exists.",
+				Default:     false,
+			},
+			"list_only": {
+				Type:        "boolean",
+				Description: "Only list the archive entries without extracting them.",
+				Default:     false,
+			},
+		},
+		Required: []string{"path"},
+	},
+
+	"list_trash": {
+		Type:        "object",
+		Description: "List entries in the sandbox trash, most recently deleted first.",
+		Properties: map[string]Property{
+			"path": {
+				Type:        "string",
+				Description: "Only list entries whose original path is this path or lies under it.",
+				Examples:    []any{"src/", "notes.txt"},
+			},
+			"cursor": {
 				Type:        "string",
-				Description: "The destination file path where the file will be moved to.",
-				MinLength:   intPtr(1),
-				Examples:    []any{"config.json", "archive/temp.txt", "published.md"},
+				Description: "Opaque cursor returned as next_cursor by a previous call, used to fetch the next page.",
+			},
+			"limit": {
+				Type:        "integer",
+				Description: "Maximum number of entries to return per page.",
+				Minimum:     float64Ptr(0),
+				Maximum:     float64Ptr(10000),
+				Default:     1000,
 			},
 		},
-		Required: []string{"source", "destination"},
 	},
 
-	"move_directory": {
+	"restore_from_trash": {
 		Type:        "object",
-		Description: "Move or rename a directory. Only works on directories, not files. Use 'move' tool if you're unsure whether the path is a file or directory.",
+		Description: "Restore an entry from the trash to its original path or to a new destination.",
 		Properties: map[string]Property{
-			"source": {
+			"id": {
 				Type:        "string",
-				Description: "The source directory path to move. Must be a directory, not a file.",
+				Description: "The id of the trash entry, as returned by list_trash or a delete operation.",
 				MinLength:   intPtr(1),
-				Examples:    []any{"old_src/", "temp_build/", "draft_docs/"},
 			},
 			"destination": {
 				Type:        "string",
-				Description: "The destination directory path where the directory will be moved to.",
-				MinLength:   intPtr(1),
-				Examples:    []any{"src/", "build/", "docs/"},
+				Description: "Where to restore the entry. Defaults to its original path.",
+				Examples:    []any{"restored/notes.txt"},
+			},
+			"overwrite": {
+				Type:        "boolean",
+				Description: "Replace an existing path at the destination.",
+				Default:     false,
 			},
 		},
-		Required: []string{"source", "destination"},
+		Required: []string{"id"},
 	},
 
-	"list_directory": {
+	"empty_trash": {
 		Type:        "object",
-		Description: "LIST AND EXPLORE directory contents. Shows all files and subdirectories with detailed information including names, types (file/directory), sizes, and modification times. Use this tool when you need to: 1) See what files are in a directory, 2) Explore project structure, 3) Find specific files, 4) Check directory contents before operations, 5) Understand folder organization. This is the primary tool for directory exploration. Keywords: list, ls, dir, show files, directory contents, browse, explore folder, view directory.",
+		Description: "Permanently delete entries from the trash. Without ids the whole trash is emptied.",
 		Properties: map[string]Property{
-			"path": {
-				Type:        "string",
-				Description: "The directory path to list. Use '.' for current directory, '..' for parent directory, or specify any directory path. Examples: '.' (current directory), 'src/' (source directory), '/home/user/projects' (absolute path), '..' (parent directory).",
-				MinLength:   intPtr(1),
-				Examples:    []any{".", "src/", "/home/user/projects", "..", "docs/", "build/"},
+			"ids": {
+				Type:        "array",
+				Description: "Ids of the entries to delete permanently. Leave empty to empty the whole trash.",
+				Items:       &Items{Type: "string", Description: "A trash entry id"},
 			},
 		},
-		Required: []string{"path"},
 	},
 
-	"search_files": {
+	"create_snapshot": {
 		Type:        "object",
-		Description: "SEARCH AND FIND files matching patterns within directories. Recursively searches through all subdirectories using powerful glob patterns. Use this tool when you need to: 1) Find files by extension (*.go, *.js), 2) Locate specific files by name pattern, 3) Search for test files, 4) Find configuration files, 5) Discover files across project. Supports wildcards: * (any characters), ? (single character), ** (any directories). Keywords: search, find, locate, grep files, filter, pattern match, glob.",
+		Description: "Capture the current state of the sandbox or a directory in it as a snapshot. Unchanged files are deduplicated between snapshots.",
 		Properties: map[string]Property{
-			"path": {
+			"name": {
 				Type:        "string",
-				Description: "The directory path to start searching from. The search will recursively include all subdirectories. Use '.' for current directory, or specify any directory path. Examples: '.' (search from current), 'src/' (search in source), '/home/user/projects' (absolute path).",
-				MinLength:   intPtr(1),
-				Examples:    []any{".", "src/", "/home/user/projects", "docs/", "tests/"},
+				Description: "Optional human readable name of the snapshot.",
+				Examples:    []any{"before refactor"},
 			},
-			"pattern": {
+			"path": {
 				Type:        "string",
-				Description: "The search pattern using glob syntax. Wildcards: * matches any characters, ? matches single character, ** matches any directories. Examples: '*.go' (all Go files), 'test_*.py' (test files starting with test_), '**/*.md' (all Markdown files in any subdirectory), 'config.*' (config files with any extension).",
-				MinLength:   intPtr(1),
-				Examples:    []any{"*.go", "*.js", "test_*.py", "**/*.md", "config.*", "*.json", "**/*.test.js"},
+				Description: "Directory to snapshot. Defaults to the sandbox root.",
+				Examples:    []any{".", "src/"},
+			},
+			"exclude": {
+				Type:        "array",
+				Description: "Glob patterns of paths to leave out, relative to the snapshot directory. The same patterns apply when diffing and restoring.",
+				Items:       &Items{Type: "string", Description: "A glob pattern to exclude"},
+				Examples:    []any{[]string{"node_modules", "*.log"}},
 			},
 		},
-		Required: []string{"path", "pattern"},
 	},
 
-	"batch_delete": {
+	"list_snapshots": {
 		Type:        "object",
-		Description: "Delete multiple files or directories in a single operation. Each path is processed independently, and the tool will report success/failure for each item.",
+		Description: "List all snapshots, newest first.",
+		Properties:  map[string]Property{},
+	},
+
+	"diff_snapshot": {
+		Type:        "object",
+		Description: "Show which paths were added, modified or deleted since a snapshot.",
 		Properties: map[string]Property{
-			"paths": {
-				Type:        "array",
-				Description: "List of file or directory paths to delete. Each path will be processed independently.",
-				Items:       &Items{Type: "string", Description: "A file or directory path to delete"},
-				Examples:    []any{[]string{"temp.txt", "old_backup/", "unused.log"}},
+			"id": {
+				Type:        "string",
+				Description: "The id of the snapshot, as returned by create_snapshot or list_snapshots.",
+				MinLength:   intPtr(1),
+			},
+			"path": {
+				Type:        "string",
+				Description: "Only compare entries under this path.",
+				Examples:    []any{"src/"},
+			},
+			"content": {
+				Type:        "boolean",
+				Description: "Include unified diffs of modified text files, from the snapshot to the current content.",
+				Default:     false,
 			},
 		},
-		Required: []string{"paths"},
+		Required: []string{"id"},
 	},
 
-	"file_stat": {
+	"restore_snapshot": {
 		Type:        "object",
-		Description: "Get detailed information about a file or directory, including size, permissions, modification time, and type (file/directory/symlink).",
+		Description: "Roll the sandbox or a path in it back to a snapshot. Each file is replaced atomically and every change is reported.",
 		Properties: map[string]Property{
-			"path": {
+			"id": {
 				Type:        "string",
-				Description: "The path of the file or directory to get information about.",
+				Description: "The id of the snapshot to restore.",
 				MinLength:   intPtr(1),
-				Examples:    []any{"main.go", "src/", "config.json"},
+			},
+			"path": {
+				Type:        "string",
+				Description: "Only restore entries under this path.",
+				Examples:    []any{"src/main.go", "docs/"},
+			},
+			"keep_new_files": {
+				Type:        "boolean",
+				Description: "Keep paths that were added after the snapshot instead of removing them.",
+				Default:     false,
+			},
+			"dry_run": {
+				Type:        "boolean",
+				Description: "Only report the changes that would be made.",
+				Default:     false,
 			},
 		},
-		Required: []string{"path"},
+		Required: []string{"id"},
+	},
+
+	"get_quota_usage": {
+		Type:        "object",
+		Description: "Get sandbox disk usage against the configured quota.",
+		Properties:  map[string]Property{},
 	},
 
 	"file_exists": {
@@ -483,11 +1209,304 @@ var ToolSchemas = map[string]JSONSchema{
 
 	"get_working_directory": {
 		Type:        "object",
-		Description: "Get the current working directory path. Returns the absolute path of the current working directory.",
+		Description: "Get the current working directory path, the mount it is on and whether that mount is read-only.",
 		Properties:  map[string]Property{},
 		Required:    []string{},
 	},
 
+	"list_mounts": {
+		Type:        "object",
+		Description: "List the sandbox root and the named mounts with their host paths and read-only/read-write modes. Paths in other tools address a mount as 'name:relative/path'; unprefixed paths resolve to the sandbox root.",
+		Properties:  map[string]Property{},
+		Required:    []string{},
+	},
+
+	"get_access_rules": {
+		Type:        "object",
+		Description: "List the path access control rules in evaluation order. Rules are glob patterns denying or allowing the read, write, delete and list operation classes; the first matching rule decides. Pass a path to see which operations are allowed on it and which rule decided.",
+		Properties: map[string]Property{
+			"path": {
+				Type:        "string",
+				Description: "Optional path to evaluate against the rules for every operation class",
+			},
+		},
+		Required: []string{},
+	},
+
+	"begin_upload": {
+		Type:        "object",
+		Description: "Begin a chunked upload for files too large to send in one create_file or write_file call. Returns an upload_id and the maximum decoded chunk size. Send the content with upload_chunk and finish with commit_upload; sessions expire after 15 minutes without activity.",
+		Properties: map[string]Property{
+			"path": {
+				Type:        "string",
+				Description: "Path of the file to create. Parent directories are created automatically.",
+				MinLength:   intPtr(1),
+				Examples:    []any{"data/large.bin", "backups/db.sql"},
+			},
+			"size": {
+				Type:        "integer",
+				Description: "Expected total size in bytes. When given, the file size limit and quota are checked up front, chunks may not go past it and commit fails until it is fully received.",
+				Minimum:     float64Ptr(0),
+			},
+			"overwrite": {
+				Type:        "boolean",
+				Description: "Replace the file if it already exists. Without it, the upload fails if the file exists.",
+				Default:     false,
+			},
+		},
+		Required: []string{"path"},
+	},
+
+	"upload_chunk": {
+		Type:        "object",
+		Description: "Upload one chunk of a chunked upload. The chunk is base64 encoded and written at a byte offset that must not exceed the bytes received so far; resending an earlier chunk overwrites it. Returns the bytes received, which is the offset of the next chunk.",
+		Properties: map[string]Property{
+			"upload_id": {
+				Type:        "string",
+				Description: "Upload ID returned by begin_upload",
+				MinLength:   intPtr(1),
+			},
+			"offset": {
+				Type:        "integer",
+				Description: "Byte offset of the chunk in the file, normally the received value of the previous response",
+				Minimum:     float64Ptr(0),
+			},
+			"data": {
+				Type:        "string",
+				Description: "Base64 encoded chunk content, at most max_chunk_size bytes once decoded",
+				MinLength:   intPtr(1),
+			},
+		},
+		Required: []string{"upload_id", "offset", "data"},
+	},
+
+	"commit_upload": {
+		Type:        "object",
+		Description: "Finish a chunked upload and atomically move the file into place. Pass the SHA-256 of the whole content to verify it; on a mismatch the upload is discarded and must be started again.",
+		Properties: map[string]Property{
+			"upload_id": {
+				Type:        "string",
+				Description: "Upload ID returned by begin_upload",
+				MinLength:   intPtr(1),
+			},
+			"sha256": {
+				Type:        "string",
+				Description: "Optional hex SHA-256 of the whole content to verify before the file is moved into place",
+				Pattern:     "^[0-9a-fA-F]{64}$",
+			},
+		},
+		Required: []string{"upload_id"},
+	},
+
+	"convert_encoding": {
+		Type:        "object",
+		Description: "Re-encode a text file in place, for example from GBK or UTF-16 to UTF-8, optionally adding a byte order mark and normalising line endings. The source charset is detected when omitted. The file is rewritten atomically and keeps its permissions. Keywords: encoding, charset, convert, transcode, gbk, shift-jis, utf-16, bom, crlf, line endings.",
+		Properties: map[string]Property{
+			"path": {
+				Type:        "string",
+				Description: "The file to convert",
+				MinLength:   intPtr(1),
+			},
+			"from": {
+				Type:        "string",
+				Description: "Current charset of the file. Detected from its content and byte order mark when omitted.",
+				Enum:        []string{"utf-8", "utf-16le", "utf-16be", "gbk", "gb18030", "shift_jis"},
+			},
+			"to": {
+				Type:        "string",
+				Description: "Charset to convert the file to",
+				Enum:        []string{"utf-8", "utf-16le", "utf-16be", "gbk", "gb18030", "shift_jis"},
+			},
+			"bom": {
+				Type:        "boolean",
+				Description: "Write a byte order mark. Only valid for utf-8, utf-16le and utf-16be; an existing mark is always dropped otherwise.",
+				Default:     false,
+			},
+			"line_ending": {
+				Type:        "string",
+				Description: "Convert every line ending to this style. Omit to keep line endings unchanged.",
+				Enum:        []string{"lf", "crlf"},
+			},
+		},
+		Required: []string{"path", "to"},
+	},
+
+	"lock_path": {
+		Type:        "object",
+		Description: "Reserve a file or directory with an advisory lease before a multi-step edit, so other agents calling lock_path on an overlapping path are told it is taken. Locking a directory covers everything below it. Leases are advisory: they never block other tools, only other lock_path calls. Pass lock_id to renew a lease, and release it with unlock_path when done. Keywords: lock, lease, reserve, mutex, coordinate, concurrent, claim.",
+		Properties: map[string]Property{
+			"path": {
+				Type:        "string",
+				Description: "The file or directory to lock",
+				MinLength:   intPtr(1),
+			},
+			"shared": {
+				Type:        "boolean",
+				Description: "Take a shared lock that is compatible with other shared locks, for example while reading files that must not change. Exclusive locks require write access.",
+				Default:     false,
+			},
+			"ttl": {
+				Type:        "integer",
+				Description: "Lease duration in seconds. The lock is released automatically when it expires unless it is renewed. Defaults to 300.",
+				Minimum:     float64Ptr(0),
+				Maximum:     float64Ptr(3600),
+			},
+			"wait": {
+				Type:        "integer",
+				Description: "Seconds to wait for a conflicting lease to be released before failing. Defaults to 0, which fails immediately.",
+				Minimum:     float64Ptr(0),
+				Maximum:     float64Ptr(60),
+			},
+			"owner": {
+				Type:        "string",
+				Description: "Optional label naming the holder, shown to others whose lock_path call conflicts with this lease",
+			},
+			"lock_id": {
+				Type:        "string",
+				Description: "ID of a lease to renew. The path and shared flag must match the original request.",
+			},
+		},
+		Required: []string{"path"},
+	},
+
+	"unlock_path": {
+		Type:        "object",
+		Description: "Release an advisory lease taken with lock_path. Keywords: unlock, release, lease, lock.",
+		Properties: map[string]Property{
+			"lock_id": {
+				Type:        "string",
+				Description: "The lock_id returned by lock_path",
+				MinLength:   intPtr(1),
+			},
+		},
+		Required: []string{"lock_id"},
+	},
+
+	"query_data": {
+		Type:        "object",
+		Description: "Read values out of a JSON, YAML, TOML or CSV file with a JSONPath-style expression instead of reading the whole file. Supports $.a.b, ['key'], [0], [-1], [1:3], [*], .. (recursive descent) and filters such as [?(@.port > 8000)]. Each match is returned with its normalized path. CSV rows become objects keyed by the header row. Keywords: query, jsonpath, config, json, yaml, toml, csv, extract, lookup, value.",
+		Properties: map[string]Property{
+			"path": {
+				Type:        "string",
+				Description: "The data file to query",
+				MinLength:   intPtr(1),
+				Examples:    []any{"package.json", "config/app.yaml", "Cargo.toml", "data/users.csv"},
+			},
+			"query": {
+				Type:        "string",
+				Description: "JSONPath-style expression. The leading $ is optional.",
+				MinLength:   intPtr(1),
+				Examples:    []any{"$.version", "$.services.*.image", "$..name", "$.items[?(@.enabled == true)].id", "$[0:5]"},
+			},
+			"format": {
+				Type:        "string",
+				Description: "File format. Detected from the extension (.json, .yaml, .yml, .toml, .csv, .tsv) when omitted.",
+				Enum:        []string{"json", "yaml", "toml", "csv"},
+			},
+			"delimiter": {
+				Type:        "string",
+				Description: "CSV field delimiter, a single character or 'tab'. Detected from the first line when omitted.",
+			},
+			"no_header": {
+				Type:        "boolean",
+				Description: "The CSV file has no header row; every row is an array of fields",
+				Default:     false,
+			},
+			"limit": {
+				Type:        "integer",
+				Description: "Maximum number of matches to return. Defaults to 100.",
+				Minimum:     float64Ptr(0),
+				Maximum:     float64Ptr(10000),
+			},
+		},
+		Required: []string{"path", "query"},
+	},
+
+	"update_data": {
+		Type:        "object",
+		Description: "Set or delete values in a JSON, YAML, TOML or CSV file at a JSONPath-style expression and write it back. Only the changed values are rewritten, so comments, key order and indentation are kept; when that is not possible the file is re-serialised and reformatted is true. Setting a missing key or the index just past the end of an array adds it. Returns the diff. Keywords: update, set, change, delete, remove, config, json, yaml, toml, csv, jsonpath.",
+		Properties: map[string]Property{
+			"path": {
+				Type:        "string",
+				Description: "The data file to update",
+				MinLength:   intPtr(1),
+			},
+			"query": {
+				Type:        "string",
+				Description: "JSONPath-style expression selecting the values to change. Every match is changed.",
+				MinLength:   intPtr(1),
+				Examples:    []any{"$.version", "$.dependencies.lodash", "$.spec.containers[*].image", "$.servers[?(@.name == 'db')].port"},
+			},
+			"op": {
+				Type:        "string",
+				Description: "set replaces or adds the value, delete removes it",
+				Enum:        []string{"set", "delete"},
+			},
+			"value": {
+				Type:        "string",
+				Description: "The value to set, written as JSON text: strings need quotes, for example '\"1.2.0\"', '8080', 'true' or '{\"enabled\": false}'. Required for set.",
+			},
+			"format": {
+				Type:        "string",
+				Description: "File format. Detected from the extension (.json, .yaml, .yml, .toml, .csv, .tsv) when omitted.",
+				Enum:        []string{"json", "yaml", "toml", "csv"},
+			},
+			"delimiter": {
+				Type:        "string",
+				Description: "CSV field delimiter, a single character or 'tab'. Detected from the first line when omitted.",
+			},
+			"no_header": {
+				Type:        "boolean",
+				Description: "The CSV file has no header row; every row is an array of fields",
+				Default:     false,
+			},
+			"dry_run": {
+				Type:        "boolean",
+				Description: "Return the diff without modifying the file",
+				Default:     false,
+			},
+		},
+		Required: []string{"path", "query", "op"},
+	},
+
+	"disk_usage": {
+		Type:        "object",
+		Description: "ANALYSE DISK USAGE of a directory, like 'du' but with only list permission. Walks the directory and returns the largest files and directories, usage broken down by file extension, and file, directory and symlink counts. Symlinks are not followed. Depth and walk time limits keep it safe on huge trees; when a limit is hit the figures cover only what was walked. Keywords: disk usage, du, space, size, largest files, big directories, cleanup, storage.",
+		Properties: map[string]Property{
+			"path": {
+				Type:        "string",
+				Description: "The directory to analyse. Defaults to the sandbox root.",
+				Examples:    []any{".", "build/", "data/"},
+			},
+			"top": {
+				Type:        "integer",
+				Description: "Number of largest files, directories and extensions to return (1-100). Default is 10.",
+				Minimum:     float64Ptr(0),
+				Maximum:     float64Ptr(100),
+				Default:     10,
+			},
+			"max_depth": {
+				Type:        "integer",
+				Description: "Maximum depth to walk; 1 counts only the files directly in the directory. Deeper directories are counted but not entered. Default is 0, which means no limit.",
+				Minimum:     float64Ptr(0),
+				Default:     0,
+			},
+			"timeout": {
+				Type:        "integer",
+				Description: "Maximum walk time in seconds (1-60). Default is 10. When it runs out, partial results are returned with timed_out set.",
+				Minimum:     float64Ptr(0),
+				Maximum:     float64Ptr(60),
+				Default:     10,
+			},
+			"exclude": {
+				Type:        "array",
+				Description: "Glob patterns to exclude; patterns without '/' match names at any depth. Examples: ['.git', '*.log'].",
+				Items:       &Items{Type: "string", Description: "A glob pattern"},
+				Examples:    []any{[]string{".git", "node_modules"}},
+			},
+		},
+	},
+
 	"change_directory": {
 		Type:        "object",
 		Description: "Change the current working directory. Similar to 'cd' command. The new directory must exist and be within the sandbox.",
@@ -821,10 +1840,19 @@ func simplifyItems(items *Items) *Items {
 	if items == nil {
 		return nil
 	}
+	var simplifiedProps map[string]Property
+	if len(items.Properties) > 0 {
+		simplifiedProps = make(map[string]Property, len(items.Properties))
+		for name, prop := range items.Properties {
+			simplifiedProps[name] = SimplifyProperty(prop)
+		}
+	}
 	return &Items{
 		Type:        items.Type,
 		Description: SimplifyDescription(items.Description),
 		Enum:        items.Enum,
+		Properties:  simplifiedProps,
+		Required:    items.Required,
 	}
 }
 
@@ -103,6 +103,36 @@ func TestSimplifyProperty(t *testing.T) {
 	assert.Empty(t, simplified.Format)
 }
 
+// TestSimplifyPropertyObjectItems 测试精简对象数组元素 / Test simplifying object array items
+func TestSimplifyPropertyObjectItems(t *testing.T) {
+	prop := Property{
+		Type:        "array",
+		Description: "List of edits. Keywords: edit. / 编辑列表",
+		Items: &Items{
+			Type:        "object",
+			Description: "A single edit. / 单个编辑",
+			Properties: map[string]Property{
+				"old_text": {
+					Type:        "string",
+					Description: "Text to replace. Examples: foo. / 要替换的文本",
+					MinLength:   intPtr(1),
+				},
+			},
+			Required: []string{"old_text"},
+		},
+	}
+
+	simplified := SimplifyProperty(prop)
+
+	assert.Equal(t, "List of edits.", simplified.Description)
+	assert.NotNil(t, simplified.Items)
+	assert.Equal(t, "object", simplified.Items.Type)
+	assert.Equal(t, "A single edit.", simplified.Items.Description)
+	assert.Equal(t, []string{"old_text"}, simplified.Items.Required)
+	assert.Equal(t, "Text to replace.", simplified.Items.Properties["old_text"].Description)
+	assert.Nil(t, simplified.Items.Properties["old_text"].MinLength)
+}
+
 // TestSimplifySchema 测试精简Schema功能 / Test simplify schema function
 func TestSimplifySchema(t *testing.T) {
 	schema := JSONSchema{
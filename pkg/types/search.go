@@ -0,0 +1,50 @@
+// Copyright 2024 MCP Toolkit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package types 内容搜索相关类型定义 / Content search related type definitions
+package types
+
+// SearchContentRequest 文件内容搜索请求 / File content search request
+type SearchContentRequest struct {
+	Path              string   `json:"path,omitempty"`                 // 搜索起始路径(默认为沙箱根目录) / Search root (defaults to the sandbox root)
+	Pattern           string   `json:"pattern"`                        // 正则表达式或字面文本 / Regular expression or literal text
+	Literal           bool     `json:"literal,omitempty"`              // 按字面文本匹配 / Match the pattern as literal text
+	IgnoreCase        bool     `json:"ignore_case,omitempty"`          // 忽略大小写 / Case-insensitive matching
+	ContextLines      int      `json:"context_lines,omitempty"`        // 匹配行前后的上下文行数 / Context lines before and after each match
+	Include           []string `json:"include,omitempty"`              // 只搜索匹配这些glob的文件 / Only search files matching these globs
+	Exclude           []string `json:"exclude,omitempty"`              // 跳过匹配这些glob的文件和目录 / Skip files and directories matching these globs
+	MaxResults        int      `json:"max_results,omitempty"`          // 最大匹配行数 / Maximum number of matching lines
+	MaxMatchesPerFile int      `json:"max_matches_per_file,omitempty"` // 每个文件的最大匹配行数 / Maximum matching lines per file
+	NoIgnore          bool     `json:"no_ignore,omitempty"`            // 不使用.gitignore规则 / Do not apply .gitignore rules
+}
+
+// ContentMatch 单个内容匹配结果 / Single content match
+type ContentMatch struct {
+	Path   string   `json:"path"`             // 文件相对路径 / Relative file path
+	Line   int      `json:"line"`             // 行号(从1开始) / Line number (1-based)
+	Column int      `json:"column"`           // 首个匹配的字符列号(从1开始) / Character column of the first match (1-based)
+	Text   string   `json:"text"`             // 匹配行内容 / Matching line content
+	Before []string `json:"before,omitempty"` // 匹配行之前的上下文 / Context lines before the match
+	After  []string `json:"after,omitempty"`  // 匹配行之后的上下文 / Context lines after the match
+}
+
+// SearchContentResponse 文件内容搜索响应 / File content search response
+type SearchContentResponse struct {
+	Matches       []ContentMatch `json:"matches"`        // 匹配结果 / Matches
+	TotalMatches  int            `json:"total_matches"`  // 返回的匹配行数 / Number of matching lines returned
+	FilesSearched int            `json:"files_searched"` // 搜索的文件数 / Number of files searched
+	FilesMatched  int            `json:"files_matched"`  // 有匹配的文件数 / Number of files with matches
+	SkippedFiles  int            `json:"skipped_files"`  // 因二进制或过大而跳过的文件数 / Files skipped as binary or too large
+	Truncated     bool           `json:"truncated"`      // 是否因达到结果上限而截断 / Whether results were cut off by the result limit
+}
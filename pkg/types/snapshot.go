@@ -0,0 +1,104 @@
+// Copyright 2024 MCP Toolkit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package types 快照相关类型定义 / Snapshot related type definitions
+package types
+
+import "time"
+
+// SnapshotFile 快照中的文件 / File recorded in a snapshot
+type SnapshotFile struct {
+	Path    string    `json:"path"`     // 相对于沙箱的路径 / Path relative to the sandbox
+	Hash    string    `json:"hash"`     // 内容的SHA-256 / SHA-256 of the content
+	Size    int64     `json:"size"`     // 文件大小 / File size
+	Mode    uint32    `json:"mode"`     // 权限位 / Permission bits
+	ModTime time.Time `json:"mod_time"` // 修改时间 / Modification time
+}
+
+// SnapshotManifest 快照清单 / Snapshot manifest
+type SnapshotManifest struct {
+	SnapshotInfo
+	Exclude []string       `json:"exclude,omitempty"` // 创建时使用的排除模式 / Exclude patterns used at creation
+	Dirs    []string       `json:"dirs"`              // 目录列表 / Directories
+	Files   []SnapshotFile `json:"files"`             // 文件列表 / Files
+}
+
+// SnapshotInfo 快照摘要 / Snapshot summary
+type SnapshotInfo struct {
+	ID        string    `json:"id"`             // 快照ID / Snapshot ID
+	Name      string    `json:"name,omitempty"` // 快照名称 / Snapshot name
+	Path      string    `json:"path"`           // 快照范围(相对于沙箱) / Snapshot scope (relative to the sandbox)
+	CreatedAt time.Time `json:"created_at"`     // 创建时间 / Creation time
+	FileCount int       `json:"file_count"`     // 文件数量 / Number of files
+	DirCount  int       `json:"dir_count"`      // 目录数量 / Number of directories
+	TotalSize int64     `json:"total_size"`     // 文件总大小 / Total size of files
+}
+
+// CreateSnapshotRequest 创建快照请求 / Create snapshot request
+type CreateSnapshotRequest struct {
+	Name    string   `json:"name,omitempty"`    // 快照名称 / Snapshot name
+	Path    string   `json:"path,omitempty"`    // 快照范围(默认为沙箱根目录) / Snapshot scope (the sandbox root by default)
+	Exclude []string `json:"exclude,omitempty"` // 排除的glob模式 / Glob patterns to exclude
+}
+
+// CreateSnapshotResponse 创建快照响应 / Create snapshot response
+type CreateSnapshotResponse struct {
+	Snapshot   SnapshotInfo `json:"snapshot"`    // 快照摘要 / Snapshot summary
+	NewObjects int          `json:"new_objects"` // 新存储的内容对象数 / Number of newly stored content objects
+	NewBytes   int64        `json:"new_bytes"`   // 新存储的字节数 / Bytes newly stored
+}
+
+// ListSnapshotsRequest 列出快照请求 / List snapshots request
+type ListSnapshotsRequest struct{}
+
+// ListSnapshotsResponse 列出快照响应 / List snapshots response
+type ListSnapshotsResponse struct {
+	Snapshots []SnapshotInfo `json:"snapshots"` // 快照列表(最新的在前) / Snapshots (newest first)
+}
+
+// SnapshotChanges 快照与当前状态之间的变化 / Changes between a snapshot and the current state
+type SnapshotChanges struct {
+	Added    []string `json:"added"`    // 快照之后新增的路径 / Paths added since the snapshot
+	Modified []string `json:"modified"` // 内容或权限改变的文件 / Files whose content or permissions changed
+	Deleted  []string `json:"deleted"`  // 快照之后删除的路径 / Paths deleted since the snapshot
+}
+
+// DiffSnapshotRequest 快照差异请求 / Diff snapshot request
+type DiffSnapshotRequest struct {
+	ID      string `json:"id"`                // 快照ID / Snapshot ID
+	Path    string `json:"path,omitempty"`    // 只比较该路径下的条目 / Only compare entries under this path
+	Content bool   `json:"content,omitempty"` // 为修改的文本文件附带统一差异 / Include unified diffs of modified text files
+}
+
+// DiffSnapshotResponse 快照差异响应 / Diff snapshot response
+type DiffSnapshotResponse struct {
+	SnapshotChanges
+	Diffs map[string]string `json:"diffs,omitempty"` // 修改文件的统一差异(快照→当前) / Unified diffs of modified files (snapshot to current)
+}
+
+// RestoreSnapshotRequest 恢复快照请求 / Restore snapshot request
+type RestoreSnapshotRequest struct {
+	ID           string `json:"id"`                       // 快照ID / Snapshot ID
+	Path         string `json:"path,omitempty"`           // 只恢复该路径下的条目 / Only restore entries under this path
+	KeepNewFiles bool   `json:"keep_new_files,omitempty"` // 保留快照之后新增的路径 / Keep paths added since the snapshot
+	DryRun       bool   `json:"dry_run,omitempty"`        // 仅报告将要进行的变更 / Only report the changes that would be made
+}
+
+// RestoreSnapshotResponse 恢复快照响应 / Restore snapshot response
+type RestoreSnapshotResponse struct {
+	Success bool            `json:"success"`           // 是否成功 / Whether successful
+	Message string          `json:"message"`           // 消息 / Message
+	Changes SnapshotChanges `json:"changes"`           // 已撤销的变化 / Changes that were reverted
+	DryRun  bool            `json:"dry_run,omitempty"` // 是否为预览 / Whether this was a dry run
+}
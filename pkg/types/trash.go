@@ -0,0 +1,70 @@
+// Copyright 2024 MCP Toolkit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package types 回收站相关类型定义 / Trash related type definitions
+package types
+
+import "time"
+
+// TrashEntry 回收站中的条目 / Entry in the trash
+type TrashEntry struct {
+	ID           string    `json:"id"`            // 条目ID / Entry ID
+	OriginalPath string    `json:"original_path"` // 删除前的路径(相对于沙箱) / Path before deletion (relative to the sandbox)
+	Type         string    `json:"type"`          // 条目类型(file/directory) / Entry type (file/directory)
+	Size         int64     `json:"size"`          // 总大小 / Total size
+	DeletedAt    time.Time `json:"deleted_at"`    // 删除时间 / Deletion time
+	SessionID    string    `json:"session_id"`    // 执行删除的服务会话 / Server session that performed the deletion
+}
+
+// ListTrashRequest 列出回收站请求 / List trash request
+type ListTrashRequest struct {
+	Path   string `json:"path,omitempty"`   // 按原路径前缀过滤 / Filter by original path prefix
+	Cursor string `json:"cursor,omitempty"` // 分页游标 / Pagination cursor
+	Limit  int    `json:"limit,omitempty"`  // 每页条目数 / Page size
+}
+
+// ListTrashResponse 列出回收站响应 / List trash response
+type ListTrashResponse struct {
+	Entries    []TrashEntry `json:"entries"`               // 条目列表(最近删除的在前) / Entries (most recently deleted first)
+	Total      int          `json:"total"`                 // 匹配的条目总数 / Total matching entries
+	TotalSize  int64        `json:"total_size"`            // 回收站总大小 / Total size of the trash
+	NextCursor string       `json:"next_cursor,omitempty"` // 下一页游标 / Cursor of the next page
+	Enabled    bool         `json:"enabled"`               // 是否启用回收站模式 / Whether trash mode is enabled
+}
+
+// RestoreFromTrashRequest 从回收站恢复请求 / Restore from trash request
+type RestoreFromTrashRequest struct {
+	ID          string `json:"id"`                    // 条目ID / Entry ID
+	Destination string `json:"destination,omitempty"` // 恢复位置(默认为原路径) / Restore location (the original path by default)
+	Overwrite   bool   `json:"overwrite,omitempty"`   // 是否覆盖已存在的路径 / Whether to replace an existing path
+}
+
+// RestoreFromTrashResponse 从回收站恢复响应 / Restore from trash response
+type RestoreFromTrashResponse struct {
+	Success bool   `json:"success"` // 是否成功 / Whether successful
+	Message string `json:"message"` // 消息 / Message
+	Path    string `json:"path"`    // 恢复后的路径 / Restored path
+}
+
+// EmptyTrashRequest 清空回收站请求 / Empty trash request
+type EmptyTrashRequest struct {
+	IDs []string `json:"ids,omitempty"` // 要永久删除的条目(为空时清空全部) / Entries to delete permanently (everything when empty)
+}
+
+// EmptyTrashResponse 清空回收站响应 / Empty trash response
+type EmptyTrashResponse struct {
+	Success    bool  `json:"success"`     // 是否成功 / Whether successful
+	Removed    int   `json:"removed"`     // 删除的条目数 / Number of entries removed
+	FreedBytes int64 `json:"freed_bytes"` // 释放的字节数 / Bytes freed
+}
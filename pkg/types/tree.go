@@ -0,0 +1,62 @@
+// Copyright 2024 MCP Toolkit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package types 目录树相关类型定义 / Directory tree related type definitions
+package types
+
+// 目录树输出格式 / Directory tree output formats
+const (
+	TreeFormatJSON = "json" // 仅结构化JSON / Structured JSON only
+	TreeFormatText = "text" // 仅缩进文本 / Indented text only
+	TreeFormatBoth = "both" // 同时返回两者 / Both
+)
+
+// 目录树节点类型 / Directory tree node types
+const (
+	TreeNodeFile      = "file"      // 文件 / File
+	TreeNodeDirectory = "directory" // 目录 / Directory
+	TreeNodeSymlink   = "symlink"   // 符号链接 / Symbolic link
+)
+
+// DirectoryTreeRequest 目录树请求 / Directory tree request
+type DirectoryTreeRequest struct {
+	Path              string   `json:"path,omitempty"`                // 根目录(默认为沙箱根目录) / Root directory (defaults to the sandbox root)
+	MaxDepth          int      `json:"max_depth,omitempty"`           // 展示的最大深度 / Maximum depth to render
+	MaxEntries        int      `json:"max_entries,omitempty"`         // 返回的最大条目数 / Maximum number of entries to return
+	Exclude           []string `json:"exclude,omitempty"`             // 额外排除的glob模式 / Additional glob patterns to exclude
+	NoDefaultExcludes bool     `json:"no_default_excludes,omitempty"` // 不使用默认排除列表 / Do not apply the default exclusion list
+	Format            string   `json:"format,omitempty"`              // 输出格式(json/text/both) / Output format (json/text/both)
+}
+
+// TreeNode 目录树节点 / Directory tree node
+// 目录的 Size 和 FileCount 为整个子树的汇总,不受深度和条目数限制
+// Size and FileCount of a directory aggregate its whole subtree regardless of depth and entry limits
+type TreeNode struct {
+	Name      string      `json:"name"`                 // 名称 / Name
+	Path      string      `json:"path"`                 // 相对沙箱的路径 / Path relative to the sandbox
+	Type      string      `json:"type"`                 // 节点类型 / Node type
+	Size      int64       `json:"size"`                 // 大小(目录为汇总大小) / Size (aggregate for directories)
+	FileCount int         `json:"file_count,omitempty"` // 子树中的文件数 / Number of files in the subtree
+	DirCount  int         `json:"dir_count,omitempty"`  // 子树中的目录数 / Number of directories in the subtree
+	Children  []*TreeNode `json:"children,omitempty"`   // 子节点 / Children
+	Omitted   int         `json:"omitted,omitempty"`    // 因深度或条目数限制未展示的子节点数 / Children not shown due to depth or entry limits
+}
+
+// DirectoryTreeResponse 目录树响应 / Directory tree response
+type DirectoryTreeResponse struct {
+	Root      *TreeNode `json:"root,omitempty"` // 结构化目录树 / Structured tree
+	Text      string    `json:"text,omitempty"` // 缩进文本形式的目录树 / Tree rendered as indented text
+	Entries   int       `json:"entries"`        // 返回的条目数 / Number of entries returned
+	Truncated bool      `json:"truncated"`      // 是否有条目因限制未展示 / Whether any entries were left out by the limits
+}
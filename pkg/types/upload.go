@@ -0,0 +1,68 @@
+// Copyright 2024 MCP Toolkit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package types 分块上传相关类型定义 / Chunked upload related type definitions
+package types
+
+import "time"
+
+// BeginUploadRequest 开始分块上传请求 / Begin chunked upload request
+type BeginUploadRequest struct {
+	Path      string `json:"path"`                // 目标文件路径 / Target file path
+	Size      int64  `json:"size,omitempty"`      // 预期的总大小,提供时提前检查并在提交时校验 / Expected total size, checked up front and verified on commit when given
+	Overwrite bool   `json:"overwrite,omitempty"` // 是否覆盖已存在的文件 / Whether to overwrite an existing file
+}
+
+// BeginUploadResponse 开始分块上传响应 / Begin chunked upload response
+type BeginUploadResponse struct {
+	Success      bool      `json:"success"`        // 是否成功 / Whether successful
+	Message      string    `json:"message"`        // 消息 / Message
+	UploadID     string    `json:"upload_id"`      // 上传会话ID / Upload session ID
+	Path         string    `json:"path"`           // 目标文件路径 / Target file path
+	MaxChunkSize int64     `json:"max_chunk_size"` // 单个分块解码后的最大字节数 / Maximum decoded bytes per chunk
+	ExpiresAt    time.Time `json:"expires_at"`     // 会话在无活动时的过期时间 / When the session expires without activity
+}
+
+// UploadChunkRequest 上传分块请求 / Upload chunk request
+type UploadChunkRequest struct {
+	UploadID string `json:"upload_id"` // 上传会话ID / Upload session ID
+	Offset   int64  `json:"offset"`    // 分块在文件中的字节偏移 / Byte offset of the chunk in the file
+	Data     string `json:"data"`      // Base64编码的分块内容 / Base64 encoded chunk content
+}
+
+// UploadChunkResponse 上传分块响应 / Upload chunk response
+type UploadChunkResponse struct {
+	Success   bool      `json:"success"`    // 是否成功 / Whether successful
+	Message   string    `json:"message"`    // 消息 / Message
+	UploadID  string    `json:"upload_id"`  // 上传会话ID / Upload session ID
+	Received  int64     `json:"received"`   // 已连续接收的字节数,即下一个分块的偏移 / Contiguous bytes received so far, i.e. the offset of the next chunk
+	ExpiresAt time.Time `json:"expires_at"` // 会话在无活动时的过期时间 / When the session expires without activity
+}
+
+// CommitUploadRequest 提交分块上传请求 / Commit chunked upload request
+type CommitUploadRequest struct {
+	UploadID string `json:"upload_id"`        // 上传会话ID / Upload session ID
+	SHA256   string `json:"sha256,omitempty"` // 预期的完整内容SHA-256,不匹配时放弃上传 / Expected SHA-256 of the whole content; the upload is discarded on a mismatch
+}
+
+// CommitUploadResponse 提交分块上传响应 / Commit chunked upload response
+type CommitUploadResponse struct {
+	Success bool      `json:"success"`  // 是否成功 / Whether successful
+	Message string    `json:"message"`  // 消息 / Message
+	Path    string    `json:"path"`     // 写入的文件路径 / Path of the written file
+	Size    int64     `json:"size"`     // 文件大小 / File size
+	Version string    `json:"version"`  // 版本标识 / Version token
+	SHA256  string    `json:"sha256"`   // 文件内容的SHA-256 / SHA-256 of the file content
+	ModTime time.Time `json:"mod_time"` // 修改时间 / Modification time
+}
@@ -49,6 +49,38 @@ var pretouchTypes = []any{
 	types.GetPermissionLevelRequest{},
 	types.GetSystemInfoRequest{},
 	types.DownloadFileRequest{},
+	types.EditFileRequest{},
+	types.TextEdit{},
+	types.SearchContentRequest{},
+	types.DirectoryTreeRequest{},
+	types.FileHashRequest{},
+	types.CompareFilesRequest{},
+	types.CreateArchiveRequest{},
+	types.ExtractArchiveRequest{},
+	types.ListTrashRequest{},
+	types.RestoreFromTrashRequest{},
+	types.EmptyTrashRequest{},
+	types.CreateSnapshotRequest{},
+	types.ListSnapshotsRequest{},
+	types.DiffSnapshotRequest{},
+	types.RestoreSnapshotRequest{},
+	types.GetQuotaUsageRequest{},
+	types.AppendFileRequest{},
+	types.InsertLinesRequest{},
+	types.DeleteLinesRequest{},
+	types.TruncateFileRequest{},
+	types.DiffFilesRequest{},
+	types.ListMountsRequest{},
+	types.GetAccessRulesRequest{},
+	types.BeginUploadRequest{},
+	types.UploadChunkRequest{},
+	types.CommitUploadRequest{},
+	types.ConvertEncodingRequest{},
+	types.LockPathRequest{},
+	types.UnlockPathRequest{},
+	types.QueryDataRequest{},
+	types.UpdateDataRequest{},
+	types.DiskUsageRequest{},
 
 	// 响应类型 / Response types
 	types.FileExistsResponse{},
@@ -68,6 +100,37 @@ var pretouchTypes = []any{
 	types.GetSystemInfoResponse{},
 	types.CommandHistoryEntry{},
 	types.CommandTask{},
+	types.EditFileResponse{},
+	types.DiffHunk{},
+	types.SearchContentResponse{},
+	types.ContentMatch{},
+	types.DirectoryTreeResponse{},
+	types.TreeNode{},
+	types.FileHashResponse{},
+	types.CompareFilesResponse{},
+	types.CreateArchiveResponse{},
+	types.ExtractArchiveResponse{},
+	types.ListTrashResponse{},
+	types.RestoreFromTrashResponse{},
+	types.EmptyTrashResponse{},
+	types.CreateSnapshotResponse{},
+	types.ListSnapshotsResponse{},
+	types.DiffSnapshotResponse{},
+	types.RestoreSnapshotResponse{},
+	types.GetQuotaUsageResponse{},
+	types.FileChangeResponse{},
+	types.DiffFilesResponse{},
+	types.ListMountsResponse{},
+	types.GetAccessRulesResponse{},
+	types.BeginUploadResponse{},
+	types.UploadChunkResponse{},
+	types.CommitUploadResponse{},
+	types.ConvertEncodingResponse{},
+	types.LockPathResponse{},
+	types.UnlockPathResponse{},
+	types.QueryDataResponse{},
+	types.UpdateDataResponse{},
+	types.DiskUsageResponse{},
 
 	// 系统信息相关结构体 / System info related structures
 	types.OSInfo{},